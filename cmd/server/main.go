@@ -1,18 +1,30 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	libmiddleware "github.com/Ecom-micro-template/lib-common-go/middleware"
+	services "github.com/Ecom-micro-template/service-agent/internal/application"
 	"github.com/Ecom-micro-template/service-agent/internal/config"
 	"github.com/Ecom-micro-template/service-agent/internal/database"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/agent"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/commission"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
 	"github.com/Ecom-micro-template/service-agent/internal/handlers"
+	"github.com/Ecom-micro-template/service-agent/internal/infrastructure/bankfile"
+	"github.com/Ecom-micro-template/service-agent/internal/infrastructure/disbursement"
+	"github.com/Ecom-micro-template/service-agent/internal/infrastructure/memory"
+	"github.com/Ecom-micro-template/service-agent/internal/infrastructure/messaging"
+	"github.com/Ecom-micro-template/service-agent/internal/infrastructure/persistence"
 	"github.com/Ecom-micro-template/service-agent/internal/middleware"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"go.uber.org/zap"
 )
 
 func main() {
@@ -44,19 +56,253 @@ func main() {
 
 	log.Info().
 		Str("environment", cfg.Environment).
+		Str("storage_driver", cfg.StorageDriver).
+		Str("message_bus", cfg.MessageBus).
 		Int("port", cfg.ServerPort).
 		Msg("Configuration loaded")
 
-	// Initialize database
-	if err := database.InitDatabase(cfg); err != nil {
-		log.Fatal().Err(err).Msg("Failed to initialize database")
+	// Initialize repositories
+	var (
+		agentRepo              repository.AgentRepository
+		commissionRepo         repository.CommissionRepository
+		clawbackRepo           repository.ClawbackRepository
+		autoApprovalRepo       repository.AutoApprovalRepository
+		disputeRepo            repository.DisputeRepository
+		tierEvaluationRepo     repository.TierEvaluationRepository
+		categoryCommissionRepo repository.CategoryCommissionRepository
+		rateChangeRepo         repository.RateChangeRepository
+		fxRateRepo             repository.FXRateRepository
+		payoutRepo             repository.PayoutRepository
+		payoutRunRepo          repository.PayoutRunRepository
+		payoutExportRepo       repository.PayoutExportRepository
+		payoutStatementRepo    repository.PayoutStatementRepository
+		payoutMethodRepo       repository.PayoutMethodRepository
+		payoutHoldRepo         repository.PayoutHoldRepository
+		reservePolicyRepo      repository.ReservePolicyRepository
+		payoutReserveRepo      repository.PayoutReserveRepository
+		withholdingRuleRepo    repository.WithholdingRuleRepository
+		taxProfileRepo         repository.TaxProfileRepository
+		ledgerRepo             repository.LedgerReader
+		teamRepo               repository.TeamRepository
+		customerRepo           repository.CustomerRepository
+		orderRepo              repository.OrderReader
+		userDirectory          repository.UserDirectory
+		transactor             repository.Transactor
+	)
+
+	switch cfg.StorageDriver {
+	case "memory":
+		log.Warn().Msg("Using in-memory storage with seeded fixtures - data is lost on restart")
+
+		store := memory.NewStore()
+		memory.Seed(store)
+
+		agentRepo = memory.NewAgentRepository(store)
+		commissionRepo = memory.NewCommissionRepository(store)
+		clawbackRepo = memory.NewClawbackRepository(store)
+		autoApprovalRepo = memory.NewAutoApprovalRepository(store)
+		disputeRepo = memory.NewDisputeRepository(store)
+		tierEvaluationRepo = memory.NewTierEvaluationRepository(store)
+		categoryCommissionRepo = memory.NewCategoryCommissionRepository(store)
+		rateChangeRepo = memory.NewRateChangeRepository(store)
+		fxRateRepo = memory.NewFXRateRepository(store)
+		payoutRepo = memory.NewPayoutRepository(store)
+		payoutRunRepo = memory.NewPayoutRunRepository(store)
+		payoutExportRepo = memory.NewPayoutExportRepository(store)
+		payoutStatementRepo = memory.NewPayoutStatementRepository(store)
+		payoutMethodRepo = memory.NewPayoutMethodRepository(store)
+		payoutHoldRepo = memory.NewPayoutHoldRepository(store)
+		reservePolicyRepo = memory.NewReservePolicyRepository(store)
+		payoutReserveRepo = memory.NewPayoutReserveRepository(store)
+		withholdingRuleRepo = memory.NewWithholdingRuleRepository(store)
+		taxProfileRepo = memory.NewTaxProfileRepository(store)
+		ledgerRepo = memory.NewLedgerRepository(store)
+		teamRepo = memory.NewTeamRepository(store)
+		customerRepo = memory.NewCustomerRepository(store)
+		orderRepo = memory.NewOrderRepository(store)
+		userDirectory = memory.NewUserDirectory(store)
+		transactor = memory.NewTransactor(store)
+	case "postgres":
+		// Initialize database
+		if err := database.InitDatabase(cfg); err != nil {
+			log.Fatal().Err(err).Msg("Failed to initialize database")
+		}
+
+		// Get database instance for repositories
+		db := database.GetDB()
+
+		agentRepo = persistence.NewAgentRepository(db)
+		commissionRepo = persistence.NewCommissionRepository(db)
+		clawbackRepo = persistence.NewClawbackRepository(db)
+		autoApprovalRepo = persistence.NewAutoApprovalRepository(db)
+		disputeRepo = persistence.NewDisputeRepository(db)
+		tierEvaluationRepo = persistence.NewTierEvaluationRepository(db)
+		categoryCommissionRepo = persistence.NewCategoryCommissionRepository(db)
+		rateChangeRepo = persistence.NewRateChangeRepository(db)
+		fxRateRepo = persistence.NewFXRateRepository(db)
+		payoutRepo = persistence.NewPayoutRepository(db)
+		payoutRunRepo = persistence.NewPayoutRunRepository(db)
+		payoutExportRepo = persistence.NewPayoutExportRepository(db)
+		payoutStatementRepo = persistence.NewPayoutStatementRepository(db)
+		fieldCipher, err := persistence.NewFieldCipher(cfg.PayoutMethodEncryptionKey)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Invalid PAYOUT_METHOD_ENCRYPTION_KEY")
+		}
+		payoutMethodRepo = persistence.NewPayoutMethodRepository(db, fieldCipher)
+		payoutHoldRepo = persistence.NewPayoutHoldRepository(db)
+		reservePolicyRepo = persistence.NewReservePolicyRepository(db)
+		payoutReserveRepo = persistence.NewPayoutReserveRepository(db)
+		withholdingRuleRepo = persistence.NewWithholdingRuleRepository(db)
+		taxProfileRepo = persistence.NewTaxProfileRepository(db, fieldCipher)
+		ledgerRepo = persistence.NewLedgerRepository(db)
+		teamRepo = persistence.NewTeamRepository(db)
+		customerRepo = persistence.NewCustomerRepository(db)
+		orderRepo = persistence.NewOrderRepository(db)
+		userDirectory = persistence.NewUserDirectory(db)
+		transactor = persistence.NewTransactor(db)
+	default:
+		log.Fatal().Str("storage_driver", cfg.StorageDriver).Msg("Unknown storage driver")
+	}
+
+	// Application services use zap
+	var appLogger *zap.Logger
+	if cfg.Environment == "development" || cfg.Environment == "dev" {
+		appLogger, err = zap.NewDevelopment()
+	} else {
+		appLogger, err = zap.NewProduction()
+	}
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize application logger")
+	}
+	defer appLogger.Sync()
+
+	// Initialize message bus
+	var bus messaging.Bus
+	switch cfg.MessageBus {
+	case "nats":
+		bus, err = messaging.NewNATSBus(cfg.NATSURL, "service-agent")
+		if err != nil {
+			log.Fatal().Err(err).Str("url", cfg.NATSURL).Msg("Failed to connect to message bus")
+		}
+	case "inprocess":
+		bus = messaging.NewInProcessBus()
+	default:
+		log.Fatal().Str("message_bus", cfg.MessageBus).Msg("Unknown message bus")
+	}
+	defer bus.Close()
+
+	// Subscribe to order lifecycle events
+	commissionCalculator := services.NewCommissionCalculatorService(agentRepo, commissionRepo, appLogger)
+	hierarchyService := services.NewHierarchyService(agentRepo, cfg.HierarchyMaxDepth, appLogger)
+	fxService := services.NewFXService(fxRateRepo, appLogger)
+	commissionService := services.NewCommissionService(commissionRepo, clawbackRepo, agentRepo, teamRepo, rateChangeRepo, transactor, hierarchyService, fxService, cfg.DownlineRates, appLogger)
+	orderEvents := services.NewOrderEventSubscriber(commissionCalculator, commissionService, commissionRepo, agentRepo, transactor, appLogger)
+	if err := orderEvents.Subscribe(bus); err != nil {
+		log.Fatal().Err(err).Msg("Failed to subscribe to order events")
+	}
+
+	// Re-evaluate agent tiers periodically
+	tierPolicy := agent.TierPolicy{}
+	for tier, threshold := range cfg.TierThresholds {
+		tierPolicy[shared.AgentTier(tier)] = agent.TierCriteria{
+			MinSales:           threshold.MinSales,
+			MinOrders:          int64(threshold.MinOrders),
+			MinActiveCustomers: int64(threshold.MinActiveCustomers),
+		}
 	}
+	tierEvaluator := services.NewTierEvaluator(agentRepo, commissionRepo, customerRepo, tierEvaluationRepo, bus, tierPolicy, cfg.TierEvaluationMonths, appLogger)
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	go tierEvaluator.Run(workerCtx, cfg.TierEvaluationInterval)
 
-	// Get database instance for handlers
-	db := database.GetDB()
+	// Apply scheduled rate changes as they fall due
+	rateService := services.NewRateService(rateChangeRepo, categoryCommissionRepo, agentRepo, teamRepo, appLogger)
+	go rateService.Run(workerCtx, cfg.RateScheduleInterval)
 
-	// Initialize admin handler - removed duplicate
-	// adminAgentHandler := handlers.NewAdminAgentHandler(db)
+	// Approve pending commissions once their holding period has passed
+	autoApprovalPolicy := make(commission.AutoApprovalPolicy, len(cfg.AutoApprovalRules))
+	for i, rule := range cfg.AutoApprovalRules {
+		autoApprovalPolicy[i] = commission.AutoApprovalRule{
+			Name:          rule.Name,
+			HoldingPeriod: rule.HoldingPeriod,
+			MaxAmount:     shared.MoneyFromFloat(rule.MaxAmount, shared.DefaultCurrency),
+		}
+	}
+	autoApprover := services.NewAutoApprover(commissionRepo, clawbackRepo, agentRepo, disputeRepo, autoApprovalRepo, transactor, commissionService, fxService, autoApprovalPolicy, appLogger)
+	if len(autoApprovalPolicy) > 0 {
+		go autoApprover.Run(workerCtx, cfg.AutoApprovalInterval)
+	} else {
+		log.Info().Msg("Commission auto-approval is off")
+	}
+
+	ledgerService := services.NewLedgerService(ledgerRepo, agentRepo, commissionRepo, clawbackRepo, payoutRepo, appLogger)
+
+	// Initialize handlers
+	agentHandler := handlers.NewAgentHandler(agentRepo, commissionRepo, payoutRepo, userDirectory, hierarchyService, rateService)
+	categoryCommissionHandler := handlers.NewCategoryCommissionHandler(categoryCommissionRepo, rateService)
+	rateHandler := handlers.NewRateHandler(rateChangeRepo, rateService)
+	fxRateHandler := handlers.NewFXRateHandler(fxRateRepo, fxService)
+	autoApprovalHandler := handlers.NewAutoApprovalHandler(autoApprovalRepo, autoApprover)
+	disputeService := services.NewDisputeService(disputeRepo, commissionRepo, agentRepo, commissionService, appLogger)
+	disputeHandler := handlers.NewDisputeHandler(disputeRepo, disputeService)
+	commissionHandler := handlers.NewCommissionHandler(commissionRepo, agentRepo, commissionService)
+	payoutMethodService := services.NewPayoutMethodService(payoutMethodRepo, agentRepo, bus, services.PayoutMethodPolicy{
+		Cooldown: cfg.PayoutMethodCooldown,
+		CodeTTL:  cfg.PayoutMethodCodeTTL,
+	}, appLogger)
+	payoutMethodHandler := handlers.NewPayoutMethodHandler(payoutMethodRepo, payoutMethodService, cfg.PayoutMethodCooldown)
+	payoutHoldService := services.NewPayoutHoldService(payoutHoldRepo, reservePolicyRepo, payoutReserveRepo, agentRepo, payoutMethodService, appLogger)
+	taxService := services.NewTaxService(withholdingRuleRepo, taxProfileRepo, payoutRepo, agentRepo, appLogger)
+	taxHandler := handlers.NewTaxHandler(withholdingRuleRepo, taxService)
+	payoutService := services.NewPayoutService(payoutRepo, payoutRunRepo, commissionRepo, clawbackRepo, payoutReserveRepo, agentRepo, transactor, fxService, payoutHoldService, taxService, payoutHoldService, appLogger)
+	payoutHandler := handlers.NewPayoutHandler(payoutRepo, agentRepo, payoutService)
+	payoutHoldHandler := handlers.NewPayoutHoldHandler(payoutHoldRepo, payoutReserveRepo, payoutHoldService, payoutService)
+
+	// Pay out reserves as they fall due
+	go payoutService.Run(workerCtx, cfg.PayoutReserveReleaseInterval)
+
+	remittanceService := services.NewRemittanceService(payoutRepo, commissionRepo, clawbackRepo, agentRepo, appLogger)
+	remittanceHandler := handlers.NewRemittanceHandler(payoutRepo, payoutRunRepo, remittanceService)
+	bankExportService := services.NewBankExportService(payoutRepo, payoutExportRepo, payoutHoldService, transactor, payoutService, bankfile.Debtor{
+		Name:    cfg.BankDebtorName,
+		Account: cfg.BankDebtorAccount,
+		BIC:     cfg.BankDebtorBIC,
+	}, appLogger)
+	payoutRunHandler := handlers.NewPayoutRunHandler(payoutRunRepo, payoutExportRepo, payoutService, bankExportService, cfg.PayoutMinimumAmount)
+
+	// Pay payouts through the payout provider, if one is configured
+	var payoutProvider disbursement.Provider
+	switch cfg.PayoutProvider {
+	case "simulated":
+		simulated, err := disbursement.NewSimulatedProvider(disbursement.SimulatedConfig{
+			Outcome:           cfg.PayoutProviderOutcome,
+			Delay:             cfg.PayoutProviderDelay,
+			TransientFailures: cfg.PayoutProviderTransientFailures,
+		})
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to initialize payout provider")
+		}
+		payoutProvider = simulated
+	case "none":
+		log.Info().Msg("No payout provider; payouts are paid by bank file")
+	default:
+		log.Fatal().Str("payout_provider", cfg.PayoutProvider).Msg("Unknown payout provider")
+	}
+	disbursementService := services.NewDisbursementService(payoutRepo, payoutHoldService, payoutService, payoutProvider, services.RetryPolicy{
+		Attempts: cfg.PayoutProviderAttempts,
+		Timeout:  cfg.PayoutProviderTimeout,
+		Backoff:  cfg.PayoutProviderBackoff,
+	}, appLogger)
+	if simulated, ok := payoutProvider.(*disbursement.SimulatedProvider); ok {
+		simulated.OnUpdate(disbursementService.HandleUpdate)
+	}
+	disbursementHandler := handlers.NewDisbursementHandler(payoutRepo, payoutRunRepo, disbursementService, cfg.PayoutProviderSecret)
+	statementService := services.NewStatementService(payoutStatementRepo, payoutRepo, transactor, payoutService, appLogger)
+	payoutStatementHandler := handlers.NewPayoutStatementHandler(payoutStatementRepo, statementService)
+	ledgerHandler := handlers.NewLedgerHandler(ledgerRepo, agentRepo, ledgerService)
+	hierarchyHandler := handlers.NewHierarchyHandler(hierarchyService)
+	tierHandler := handlers.NewTierHandler(tierEvaluationRepo, tierEvaluator)
+	portalHandler := handlers.NewAgentPortalHandler(agentRepo, commissionRepo, teamRepo, customerRepo, orderRepo, userDirectory, payoutHoldService)
 
 	// Setup Gin
 	if cfg.GinMode == "release" {
@@ -96,48 +342,68 @@ func main() {
 	v1 := router.Group("/api/v1")
 	{
 		// Admin Agent routes (CRUD) - under /agents for backwards compatibility
-		v1.POST("/agents", handlers.CreateAgent)
-		v1.GET("/agents", handlers.GetAgents)
-		v1.GET("/agents/:id", handlers.GetAgent)
-		v1.PUT("/agents/:id", handlers.UpdateAgent)
-		v1.DELETE("/agents/:id", handlers.DeleteAgent)
-		v1.GET("/agents/:id/stats", handlers.GetAgentStats)
+		v1.POST("/agents", agentHandler.CreateAgent)
+		v1.GET("/agents", agentHandler.GetAgents)
+		v1.GET("/agents/:id", agentHandler.GetAgent)
+		v1.PUT("/agents/:id", agentHandler.UpdateAgent)
+		v1.DELETE("/agents/:id", agentHandler.DeleteAgent)
+		v1.GET("/agents/:id/stats", agentHandler.GetAgentStats)
 
 		// Agent Category Commission routes
-		v1.GET("/agents/:id/category-commissions", handlers.GetAgentCategoryCommissionsLegacy(db))
-		v1.PUT("/agents/:id/category-commissions", handlers.UpdateAgentCategoryCommissionsLegacy(db))
+		v1.GET("/agents/:id/category-commissions", categoryCommissionHandler.GetAgentCategoryCommissions)
+		v1.PUT("/agents/:id/category-commissions", categoryCommissionHandler.UpdateAgentCategoryCommissions)
+		v1.GET("/agents/:id/category-commissions/history", categoryCommissionHandler.GetAgentCategoryCommissionHistory)
 
 		// Password reset route
-		v1.PUT("/agents/:id/reset-password", handlers.ResetAgentPassword)
+		v1.PUT("/agents/:id/reset-password", agentHandler.ResetAgentPassword)
 
 		// Commission routes
-		v1.POST("/commissions", handlers.CreateCommission)
-		v1.GET("/agents/:id/commissions", handlers.GetAgentCommissions)
-		v1.GET("/commissions/pending", handlers.GetPendingCommissions)
-		v1.PUT("/commissions/:id/approve", handlers.ApproveCommission)
+		v1.POST("/commissions", commissionHandler.CreateCommission)
+		v1.GET("/agents/:id/commissions", commissionHandler.GetAgentCommissionsByID)
+		v1.GET("/commissions/pending", commissionHandler.GetPendingCommissions)
+		v1.PUT("/commissions/:id/approve", commissionHandler.ApproveCommission)
 
 		// Payout routes
-		v1.POST("/payouts", handlers.CreatePayout)
-		v1.GET("/agents/:id/payouts", handlers.GetAgentPayouts)
-		v1.GET("/payouts/:id", handlers.GetPayout)
-		v1.PUT("/payouts/:id/mark-paid", handlers.MarkPayoutPaid)
+		v1.POST("/payouts", payoutHandler.CreatePayout)
+		v1.GET("/agents/:id/payouts", payoutHandler.GetAgentPayouts)
+		v1.GET("/payouts/:id", payoutHandler.GetPayout)
+		v1.PUT("/payouts/:id/mark-paid", payoutHandler.CompletePayout)
+
+		// Payout provider callbacks, authenticated by signature
+		v1.POST("/webhooks/payout-provider", disbursementHandler.ProviderCallback)
 
 		// Agent Portal routes (for frontend - require agent auth)
 		agent := v1.Group("/agent")
-		agent.Use(middleware.AgentAuthMiddleware())
+		agent.Use(middleware.AgentAuthMiddleware(agentRepo))
 		{
-			agent.GET("/profile", handlers.GetAgentProfile)
-			agent.GET("/dashboard", handlers.GetAgentDashboard)
-			agent.GET("/orders", handlers.GetAgentOrders)
-			agent.POST("/orders", handlers.CreateAgentOrder)
-			agent.GET("/orders/:id", handlers.GetAgentOrder)
-			agent.GET("/customers", handlers.GetAgentCustomers)
-			agent.POST("/customers", handlers.CreateAgentCustomer)
-			agent.GET("/customers/:id", handlers.GetAgentCustomer)
-			agent.PUT("/customers/:id", handlers.UpdateAgentCustomer)
-			agent.GET("/commissions", handlers.GetAgentCommissions)
-			agent.GET("/performance", handlers.GetAgentPerformance)
-			agent.GET("/team", handlers.GetAgentTeam)
+			agent.GET("/profile", portalHandler.GetAgentProfile)
+			agent.GET("/dashboard", portalHandler.GetAgentDashboard)
+			agent.GET("/orders", portalHandler.GetAgentOrders)
+			agent.POST("/orders", portalHandler.CreateAgentOrder)
+			agent.GET("/orders/:id", portalHandler.GetAgentOrder)
+			agent.GET("/customers", portalHandler.GetAgentCustomers)
+			agent.POST("/customers", portalHandler.CreateAgentCustomer)
+			agent.GET("/customers/:id", portalHandler.GetAgentCustomer)
+			agent.PUT("/customers/:id", portalHandler.UpdateAgentCustomer)
+			agent.GET("/commissions", portalHandler.GetAgentCommissions)
+			agent.GET("/ledger", ledgerHandler.GetMyLedger)
+			agent.GET("/performance", portalHandler.GetAgentPerformance)
+			agent.GET("/team", portalHandler.GetAgentTeam)
+			agent.GET("/tier-evaluations", tierHandler.GetMyTierEvaluations)
+			agent.GET("/payout-methods", payoutMethodHandler.GetMyPayoutMethods)
+			agent.POST("/payout-methods", payoutMethodHandler.AddMyPayoutMethod)
+			agent.PUT("/payout-methods/:id", payoutMethodHandler.UpdateMyPayoutMethod)
+			agent.DELETE("/payout-methods/:id", payoutMethodHandler.DeleteMyPayoutMethod)
+			agent.POST("/payout-methods/:id/verify", payoutMethodHandler.VerifyMyPayoutMethod)
+			agent.POST("/payout-methods/:id/resend-code", payoutMethodHandler.ResendMyPayoutMethodCode)
+			agent.PUT("/payout-methods/:id/default", payoutMethodHandler.SetMyDefaultPayoutMethod)
+			agent.GET("/payouts", payoutHandler.GetMyPayouts)
+			agent.GET("/payouts/:id/statement", remittanceHandler.GetMyPayoutStatement)
+			agent.GET("/tax-statements/:year", taxHandler.GetMyTaxStatement)
+			agent.GET("/disputes", disputeHandler.GetMyDisputes)
+			agent.POST("/disputes", disputeHandler.OpenDispute)
+			agent.GET("/disputes/:id", disputeHandler.GetMyDispute)
+			agent.POST("/disputes/:id/messages", disputeHandler.AddMyDisputeMessage)
 		}
 
 		// Admin routes (require admin middleware)
@@ -146,27 +412,115 @@ func main() {
 		admin.Use(libmiddleware.RequireAdmin())     // Verify admin role
 		{
 			// Agent management
-			admin.GET("/agents", handlers.GetAgents)
-			admin.POST("/agents", handlers.CreateAgent)
-			admin.GET("/agents/:id", handlers.GetAgent)
-			admin.PUT("/agents/:id", handlers.UpdateAgent)
-			admin.DELETE("/agents/:id", handlers.DeleteAgent)
-			admin.GET("/agents/:id/stats", handlers.GetAgentStats)
-			admin.GET("/agents/:id/commissions", handlers.GetAgentCommissions)
-			admin.GET("/agents/:id/payouts", handlers.GetAgentPayouts)
-			admin.GET("/agents/:id/category-commissions", handlers.GetAgentCategoryCommissionsLegacy(db))
-			admin.PUT("/agents/:id/category-commissions", handlers.UpdateAgentCategoryCommissionsLegacy(db))
-			admin.PUT("/agents/:id/reset-password", handlers.ResetAgentPassword)
+			admin.GET("/agents", agentHandler.GetAgents)
+			admin.POST("/agents", agentHandler.CreateAgent)
+			admin.GET("/agents/:id", agentHandler.GetAgent)
+			admin.PUT("/agents/:id", agentHandler.UpdateAgent)
+			admin.DELETE("/agents/:id", agentHandler.DeleteAgent)
+			admin.GET("/agents/:id/stats", agentHandler.GetAgentStats)
+			admin.GET("/agents/:id/commissions", commissionHandler.GetAgentCommissionsByID)
+			admin.POST("/agents/:id/adjustments", commissionHandler.CreateAdjustment)
+			admin.GET("/agents/:id/payouts", payoutHandler.GetAgentPayouts)
+			admin.GET("/agents/:id/payout-methods", payoutMethodHandler.GetAgentPayoutMethods)
+			admin.GET("/agents/:id/payout-holds", payoutHoldHandler.GetAgentHolds)
+			admin.POST("/agents/:id/payout-holds", payoutHoldHandler.PlaceHold)
+			admin.GET("/agents/:id/reserve-policy", payoutHoldHandler.GetReservePolicy)
+			admin.PUT("/agents/:id/reserve-policy", payoutHoldHandler.UpdateReservePolicy)
+			admin.DELETE("/agents/:id/reserve-policy", payoutHoldHandler.DeleteReservePolicy)
+			admin.GET("/agents/:id/reserves", payoutHoldHandler.GetAgentReserves)
+			admin.GET("/agents/:id/tax-profile", taxHandler.GetTaxProfile)
+			admin.PUT("/agents/:id/tax-profile", taxHandler.UpdateTaxProfile)
+			admin.GET("/agents/:id/tax-statements/:year", taxHandler.GetAgentTaxStatement)
+			admin.GET("/agents/:id/ledger", ledgerHandler.GetAgentLedger)
+			admin.GET("/agents/:id/category-commissions", categoryCommissionHandler.GetAgentCategoryCommissions)
+			admin.PUT("/agents/:id/category-commissions", categoryCommissionHandler.UpdateAgentCategoryCommissions)
+			admin.GET("/agents/:id/category-commissions/history", categoryCommissionHandler.GetAgentCategoryCommissionHistory)
+			admin.DELETE("/agents/:id/category-commissions/:commissionId", categoryCommissionHandler.CancelAgentCategoryCommission)
+			admin.GET("/agents/:id/commission-rates", rateHandler.GetAgentRates)
+			admin.POST("/agents/:id/commission-rates", rateHandler.ScheduleAgentRate)
+			admin.PUT("/agents/:id/reset-password", agentHandler.ResetAgentPassword)
+			admin.GET("/agents/:id/upline", hierarchyHandler.GetUpline)
+			admin.GET("/agents/:id/downline", hierarchyHandler.GetDownline)
+			admin.PUT("/agents/:id/parent", hierarchyHandler.MoveAgent)
+			admin.GET("/agents/:id/tier-evaluations", tierHandler.GetAgentTierEvaluations)
+			admin.POST("/tier-evaluations/run", tierHandler.RunTierEvaluation)
+
+			// Rate management
+			admin.GET("/teams/:id/commission-rates", rateHandler.GetTeamRates)
+			admin.POST("/teams/:id/commission-rates", rateHandler.ScheduleTeamRate)
+			admin.DELETE("/commission-rates/:id", rateHandler.CancelRateChange)
+
+			// Exchange rates
+			admin.GET("/fx-rates", fxRateHandler.GetFXRates)
+			admin.POST("/fx-rates", fxRateHandler.SetFXRate)
+			admin.DELETE("/fx-rates/:id", fxRateHandler.DeleteFXRate)
 
 			// Commission management
-			admin.GET("/commissions", handlers.GetPendingCommissions)
-			admin.POST("/commissions", handlers.CreateCommission)
-			admin.PUT("/commissions/:id/approve", handlers.ApproveCommission)
+			admin.GET("/commissions", commissionHandler.GetPendingCommissions)
+			admin.POST("/commissions", commissionHandler.CreateCommission)
+			admin.PUT("/commissions/:id/approve", commissionHandler.ApproveCommission)
+			admin.PUT("/commissions/:id/reject", commissionHandler.RejectCommission)
+			admin.PUT("/commissions/:id/cancel", commissionHandler.CancelCommission)
+			admin.GET("/commissions/:id/auto-approval", autoApprovalHandler.GetCommissionAutoApproval)
+			admin.GET("/auto-approvals", autoApprovalHandler.GetAutoApprovals)
+			admin.POST("/auto-approvals/run", autoApprovalHandler.RunAutoApproval)
+
+			// Commission disputes
+			admin.GET("/disputes", disputeHandler.GetDisputes)
+			admin.GET("/disputes/:id", disputeHandler.GetDispute)
+			admin.POST("/disputes/:id/messages", disputeHandler.AddDisputeMessage)
+			admin.PUT("/disputes/:id/review", disputeHandler.ReviewDispute)
+			admin.PUT("/disputes/:id/resolve", disputeHandler.ResolveDispute)
+			admin.PUT("/disputes/:id/reject", disputeHandler.RejectDispute)
 
 			// Payout management
-			admin.POST("/payouts", handlers.CreatePayout)
-			admin.GET("/payouts/:id", handlers.GetPayout)
-			admin.PUT("/payouts/:id/mark-paid", handlers.MarkPayoutPaid)
+			admin.POST("/payouts", payoutHandler.CreatePayout)
+			admin.GET("/payouts/:id", payoutHandler.GetPayout)
+			admin.GET("/payouts/:id/statement", remittanceHandler.GetPayoutStatement)
+			admin.PUT("/payouts/:id/process", payoutHandler.ProcessPayout)
+			admin.PUT("/payouts/:id/complete", payoutHandler.CompletePayout)
+			admin.PUT("/payouts/:id/fail", payoutHandler.FailPayout)
+			admin.PUT("/payouts/:id/retry", payoutHandler.RetryPayout)
+			admin.PUT("/payouts/:id/cancel", payoutHandler.CancelPayout)
+			admin.PUT("/payouts/:id/mark-paid", payoutHandler.CompletePayout)
+			admin.POST("/payouts/:id/submit", disbursementHandler.SubmitPayout)
+			admin.POST("/payouts/:id/sync", disbursementHandler.SyncPayout)
+			admin.POST("/payouts/:id/cancel-transfer", disbursementHandler.CancelTransfer)
+
+			// Payout runs
+			admin.POST("/payout-runs/preview", payoutRunHandler.PreviewRun)
+			admin.POST("/payout-runs", payoutRunHandler.CommitRun)
+			admin.GET("/payout-runs", payoutRunHandler.ListRuns)
+			admin.GET("/payout-runs/:id", payoutRunHandler.GetRun)
+			admin.PUT("/payout-runs/:id/cancel", payoutRunHandler.CancelRun)
+			admin.POST("/payout-runs/:id/export", payoutRunHandler.ExportRun)
+			admin.GET("/payout-runs/:id/exports", payoutRunHandler.ListExports)
+			admin.GET("/payout-exports/:id", payoutRunHandler.DownloadExport)
+			admin.POST("/payout-runs/:id/submit", disbursementHandler.SubmitRun)
+			admin.GET("/payout-runs/:id/statements", remittanceHandler.GetRunStatements)
+
+			// Payout holds and reserves
+			admin.GET("/payout-holds", payoutHoldHandler.ListActiveHolds)
+			admin.PUT("/payout-holds/:id/release", payoutHoldHandler.ReleaseHold)
+			admin.POST("/payout-reserves/release", payoutHoldHandler.RunReserveRelease)
+
+			// Bank statement reconciliation
+			admin.POST("/payout-statements", payoutStatementHandler.ImportStatement)
+			admin.GET("/payout-statements", payoutStatementHandler.ListStatements)
+			admin.GET("/payout-statements/:id", payoutStatementHandler.GetStatement)
+			admin.GET("/payout-statement-lines", payoutStatementHandler.ListReviewQueue)
+			admin.PUT("/payout-statement-lines/:id/resolve", payoutStatementHandler.ResolveLine)
+			admin.PUT("/payout-statement-lines/:id/dismiss", payoutStatementHandler.DismissLine)
+
+			// Earnings ledger
+			admin.GET("/ledger/reconcile", ledgerHandler.Reconcile)
+
+			// Withholding tax
+			admin.GET("/withholding-rules", taxHandler.ListRules)
+			admin.POST("/withholding-rules", taxHandler.CreateRule)
+			admin.PUT("/withholding-rules/:id", taxHandler.UpdateRule)
+			admin.DELETE("/withholding-rules/:id", taxHandler.DeleteRule)
+			admin.GET("/tax-statements/:year", taxHandler.GetTaxStatements)
 		}
 	}
 
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/commission"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"go.uber.org/zap"
+)
+
+// AutoApprover periodically approves pending commissions once the return
+// window after the sale has passed, following the auto-approval policy.
+// Commissions of agents who are not in good standing, or on orders that
+// were refunded or that the agent disputes, are left for review. Every auto-approval is
+// recorded with the rule that approved it.
+type AutoApprover struct {
+	commissions repository.CommissionReader
+	clawbacks   repository.ClawbackReader
+	agents      repository.AgentReader
+	disputes    repository.DisputeReader
+	approvals   repository.AutoApprovalWriter
+	tx          repository.Transactor
+	service     *CommissionService
+	fx          *FXService
+	policy      commission.AutoApprovalPolicy
+	logger      *zap.Logger
+}
+
+// NewAutoApprover creates a new auto-approver
+func NewAutoApprover(
+	commissions repository.CommissionReader,
+	clawbacks repository.ClawbackReader,
+	agents repository.AgentReader,
+	disputes repository.DisputeReader,
+	approvals repository.AutoApprovalWriter,
+	tx repository.Transactor,
+	service *CommissionService,
+	fx *FXService,
+	policy commission.AutoApprovalPolicy,
+	logger *zap.Logger,
+) *AutoApprover {
+	return &AutoApprover{
+		commissions: commissions,
+		clawbacks:   clawbacks,
+		agents:      agents,
+		disputes:    disputes,
+		approvals:   approvals,
+		tx:          tx,
+		service:     service,
+		fx:          fx,
+		policy:      policy,
+		logger:      logger,
+	}
+}
+
+// Run approves due commissions every interval until the context is done
+func (a *AutoApprover) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if _, err := a.ApproveDue(ctx, now); err != nil {
+				a.logger.Error("Auto-approval failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// ApproveDue approves every pending commission the policy allows at the
+// given time and returns the auto-approvals
+func (a *AutoApprover) ApproveDue(ctx context.Context, now time.Time) ([]*commission.AutoApproval, error) {
+	pending, _, err := a.commissions.GetPending(ctx, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load pending commissions: %w", err)
+	}
+
+	approvals := make([]*commission.AutoApproval, 0)
+	held := 0
+	for _, c := range pending {
+		approval, err := a.approve(ctx, c, now)
+		if err != nil {
+			a.logger.Error("Failed to auto-approve commission", zap.Uint("commission_id", c.ID()), zap.Error(err))
+			continue
+		}
+		if approval == nil {
+			held++
+			continue
+		}
+		approvals = append(approvals, approval)
+	}
+
+	a.logger.Info("Auto-approval completed",
+		zap.Int("pending", len(pending)),
+		zap.Int("approved", len(approvals)),
+		zap.Int("held", held),
+	)
+	return approvals, nil
+}
+
+// approve approves a pending commission if a rule allows it, returning nil
+// when it is held for review. The approval and its record are saved
+// together, and fail with repository.ErrConflict if the commission was
+// approved, rejected or changed since it was loaded.
+func (a *AutoApprover) approve(ctx context.Context, c *commission.Commission, now time.Time) (*commission.AutoApproval, error) {
+	facts, err := a.facts(ctx, c, now)
+	if err != nil {
+		return nil, err
+	}
+
+	rule, reasons := a.policy.Match(facts)
+	if rule == nil {
+		a.logger.Debug("Commission held for review",
+			zap.Uint("commission_id", c.ID()),
+			zap.Strings("reasons", reasons),
+		)
+		return nil, nil
+	}
+
+	approval := commission.NewAutoApproval(c, rule.Name, reasons, now)
+	err = a.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := a.service.Approve(ctx, c); err != nil {
+			return err
+		}
+		if err := a.approvals.Create(ctx, approval); err != nil {
+			return fmt.Errorf("failed to record auto-approval of commission %d: %w", c.ID(), err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	a.logger.Info("Commission auto-approved",
+		zap.Uint("commission_id", c.ID()),
+		zap.Uint("agent_id", c.AgentID()),
+		zap.String("rule", rule.Name),
+		zap.Float64("amount", c.NetAmount().Float64()),
+	)
+	return approval, nil
+}
+
+// facts gathers what the policy looks at for a commission
+func (a *AutoApprover) facts(ctx context.Context, c *commission.Commission, now time.Time) (commission.AutoApprovalFacts, error) {
+	facts := commission.AutoApprovalFacts{
+		Age:      now.Sub(c.CreatedAt()),
+		Disputed: c.ReversedAmount().IsPositive(),
+	}
+
+	amount, err := a.fx.Convert(ctx, c.NetAmount(), shared.DefaultCurrency, c.CreatedAt())
+	if err != nil {
+		return facts, err
+	}
+	facts.Amount = amount
+
+	seller, err := a.agents.GetByID(ctx, c.AgentID())
+	if err != nil {
+		return facts, fmt.Errorf("failed to load agent %d: %w", c.AgentID(), err)
+	}
+	facts.AgentActive = seller.CanEarnCommission()
+
+	outstanding, err := a.clawbacks.GetOutstanding(ctx, c.AgentID())
+	if err != nil {
+		return facts, fmt.Errorf("failed to load outstanding clawbacks for agent %d: %w", c.AgentID(), err)
+	}
+	facts.OutstandingClawbacks = len(outstanding)
+
+	if !facts.Disputed {
+		disputed, err := a.disputes.HasActive(ctx, c.AgentID(), c.OrderID())
+		if err != nil {
+			return facts, fmt.Errorf("failed to check disputes on order %s: %w", c.OrderID(), err)
+		}
+		facts.Disputed = disputed
+	}
+
+	return facts, nil
+}
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/payout"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/infrastructure/bankfile"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"go.uber.org/zap"
+)
+
+// PayeeResolver looks up who an agent's payouts are paid to. Payee
+// returns an error wrapping payout.ErrPayoutOnHold while the agent's
+// payouts are held.
+type PayeeResolver interface {
+	Payee(ctx context.Context, agentID uint) (bankfile.Payee, error)
+}
+
+// BankExportService writes bank payment files for payout runs. Every
+// payout in an exported file starts processing, and every file is kept so
+// it can be downloaded again.
+type BankExportService struct {
+	payouts repository.PayoutReader
+	exports repository.PayoutExportWriter
+	payees  PayeeResolver
+	tx      repository.Transactor
+	service *PayoutService
+	debtor  bankfile.Debtor
+	logger  *zap.Logger
+}
+
+// NewBankExportService creates a new bank export service. debtor is the
+// account payouts are paid from.
+func NewBankExportService(
+	payouts repository.PayoutReader,
+	exports repository.PayoutExportWriter,
+	payees PayeeResolver,
+	tx repository.Transactor,
+	service *PayoutService,
+	debtor bankfile.Debtor,
+	logger *zap.Logger,
+) *BankExportService {
+	return &BankExportService{
+		payouts: payouts,
+		exports: exports,
+		payees:  payees,
+		tx:      tx,
+		service: service,
+		debtor:  debtor,
+		logger:  logger,
+	}
+}
+
+// ExportRun writes a payment file in the given format for the run's
+// pending payouts and starts processing them. Payouts that are already
+// processing are left out, so a run whose failed payouts are retried can
+// be exported again for just those payouts. Payouts on hold are left out
+// too and stay pending; their IDs are returned with the file.
+//
+// The payouts start processing, and the file is built and saved as an
+// export of the run, in one transaction: if any payout cannot start
+// processing, or the file's count or totals do not match the payouts, no
+// file is returned and every payout stays pending.
+func (s *BankExportService) ExportRun(ctx context.Context, run *payout.Run, format, actor string) (*payout.Export, *bankfile.File, []uint, error) {
+	exporter, err := bankfile.NewExporter(format)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if run.Status() != shared.PayoutRunCommitted {
+		return nil, nil, nil, payout.ErrRunNotCommitted
+	}
+
+	var pending []*payout.Payout
+	for _, id := range run.PayoutIDs() {
+		p, err := s.payouts.GetByID(ctx, id)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to load payout %d: %w", id, err)
+		}
+		if p.IsPending() {
+			pending = append(pending, p)
+		}
+	}
+
+	now := time.Now()
+	batch := bankfile.Batch{
+		ID:        fmt.Sprintf("RUN%d-%s", run.ID(), now.UTC().Format("20060102150405")),
+		CreatedAt: now,
+		Debtor:    s.debtor,
+	}
+	var sending []*payout.Payout
+	var held []uint
+	for _, p := range pending {
+		payee, err := s.payees.Payee(ctx, p.AgentID())
+		if errors.Is(err, payout.ErrPayoutOnHold) {
+			s.logger.Info("Payout held from payment file",
+				zap.Uint("run_id", run.ID()),
+				zap.Uint("payout_id", p.ID()),
+				zap.Error(err),
+			)
+			held = append(held, p.ID())
+			continue
+		}
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to resolve payee for agent %d: %w", p.AgentID(), err)
+		}
+		sending = append(sending, p)
+		batch.Payments = append(batch.Payments, bankfile.Payment{
+			PayoutID:   p.ID(),
+			Reference:  p.Reference(),
+			Amount:     p.Amount(),
+			Payee:      payee,
+			Remittance: fmt.Sprintf("Commission payout %s", p.Period()),
+		})
+	}
+	if len(sending) == 0 {
+		if len(held) > 0 {
+			return nil, nil, held, fmt.Errorf("%w: %d payouts are on hold", payout.ErrNothingToSend, len(held))
+		}
+		return nil, nil, nil, payout.ErrNothingToSend
+	}
+
+	var file *bankfile.File
+	var export *payout.Export
+	err = s.tx.Transaction(ctx, func(ctx context.Context) error {
+		for _, p := range sending {
+			if err := s.service.Process(ctx, p); err != nil {
+				return fmt.Errorf("failed to start processing payout %d: %w", p.ID(), err)
+			}
+		}
+		var err error
+		file, err = bankfile.Generate(exporter, batch)
+		if err != nil {
+			return err
+		}
+		export, err = payout.NewExport(payout.ExportParams{
+			RunID:       run.ID(),
+			Format:      exporter.Format(),
+			FileName:    file.Name,
+			ContentType: file.ContentType,
+			Content:     file.Content,
+			Checksum:    file.Checksum,
+			Count:       file.Count,
+			ExportedBy:  actor,
+		})
+		if err != nil {
+			return err
+		}
+		if err := s.exports.Create(ctx, export); err != nil {
+			return fmt.Errorf("failed to save payment file: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	s.logger.Info("Payment file exported",
+		zap.Uint("run_id", run.ID()),
+		zap.Uint("export_id", export.ID()),
+		zap.String("file", file.Name),
+		zap.String("format", exporter.Format()),
+		zap.Int("payments", file.Count),
+		zap.Int("held", len(held)),
+		zap.String("checksum", file.Checksum),
+		zap.String("actor", actor),
+	)
+	return export, file, held, nil
+}
+
+// File rebuilds a saved export's payment file for download. The content
+// is checked against the checksum recorded when it was exported, and its
+// payment count and totals are read back from it.
+func (s *BankExportService) File(export *payout.Export) (*bankfile.File, error) {
+	sum := sha256.Sum256(export.Content())
+	if checksum := hex.EncodeToString(sum[:]); checksum != export.Checksum() {
+		return nil, fmt.Errorf("payment file export %d does not match its checksum %s", export.ID(), export.Checksum())
+	}
+
+	exporter, err := bankfile.NewExporter(export.Format())
+	if err != nil {
+		return nil, err
+	}
+	summary, err := exporter.Summarize(export.Content())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read payment file export %d: %w", export.ID(), err)
+	}
+	return &bankfile.File{
+		Name:        export.FileName(),
+		ContentType: export.ContentType(),
+		Content:     export.Content(),
+		Checksum:    export.Checksum(),
+		Summary:     summary,
+	}, nil
+}
@@ -0,0 +1,398 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/commission"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/team"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"go.uber.org/zap"
+)
+
+// CommissionService applies commission state changes together with their
+// side effects: the team leader override and upline downline commissions
+// that follow each sale commission. Each change is recorded in the agent's
+// earnings ledger when the commission is saved.
+//
+// Commissions are earned in the order currency and converted to the
+// agent's payout currency when they are created. The ledger is kept in the
+// payout currency.
+type CommissionService struct {
+	commissions repository.CommissionRepository
+	clawbacks   repository.ClawbackRepository
+	agents      repository.AgentRepository
+	teams       repository.TeamReader
+	rates       repository.RateChangeReader
+	tx          repository.Transactor
+	hierarchy   *HierarchyService
+	fx          *FXService
+	logger      *zap.Logger
+
+	// downlineRates are the rates paid to upline sponsors by level,
+	// starting with the seller's direct sponsor
+	downlineRates []float64
+}
+
+// NewCommissionService creates a new commission service
+func NewCommissionService(
+	commissions repository.CommissionRepository,
+	clawbacks repository.ClawbackRepository,
+	agents repository.AgentRepository,
+	teams repository.TeamReader,
+	rates repository.RateChangeReader,
+	tx repository.Transactor,
+	hierarchy *HierarchyService,
+	fx *FXService,
+	downlineRates []float64,
+	logger *zap.Logger,
+) *CommissionService {
+	return &CommissionService{
+		commissions:   commissions,
+		clawbacks:     clawbacks,
+		agents:        agents,
+		teams:         teams,
+		rates:         rates,
+		tx:            tx,
+		hierarchy:     hierarchy,
+		fx:            fx,
+		logger:        logger,
+		downlineRates: downlineRates,
+	}
+}
+
+// ConvertForPayout converts a pending commission to its agent's payout
+// currency at the rate in effect at the given time
+func (s *CommissionService) ConvertForPayout(ctx context.Context, c *commission.Commission, at time.Time) error {
+	a, err := s.agents.GetByID(ctx, c.AgentID())
+	if err != nil {
+		return err
+	}
+	rate, err := s.fx.RateAt(ctx, c.Currency(), a.PayoutCurrency(), at)
+	if err != nil {
+		return err
+	}
+	return c.ConvertForPayout(rate)
+}
+
+// Approve approves a pending commission, making it available to the agent
+// in the ledger. Approving a sale commission generates the team leader's
+// override and the upline sponsors' downline commissions, in the same
+// transaction: if either fails, the commission stays pending.
+func (s *CommissionService) Approve(ctx context.Context, c *commission.Commission) error {
+	if err := c.Approve(); err != nil {
+		return err
+	}
+	return s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.commissions.Update(ctx, c); err != nil {
+			return fmt.Errorf("failed to approve commission %d: %w", c.ID(), err)
+		}
+		if c.Type() != shared.CommissionTypeSale {
+			return nil
+		}
+		if err := s.createOverride(ctx, c); err != nil {
+			return fmt.Errorf("failed to create team override on commission %d: %w", c.ID(), err)
+		}
+		if err := s.createDownline(ctx, c); err != nil {
+			return fmt.Errorf("failed to create downline commissions on commission %d: %w", c.ID(), err)
+		}
+		return nil
+	})
+}
+
+// Adjust makes a manual adjustment to an agent's earnings. The admin making
+// it approves it, so it is recorded in the earnings at once and paid, or
+// netted, with the agent's next payout. It is created and approved in one
+// transaction, so a failed approval leaves no pending adjustment behind.
+func (s *CommissionService) Adjust(ctx context.Context, params commission.AdjustmentParams) (*commission.Commission, error) {
+	c, err := commission.NewAdjustmentCommission(params)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ConvertForPayout(ctx, c, c.CreatedAt()); err != nil {
+		return nil, err
+	}
+	err = s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.commissions.Create(ctx, c); err != nil {
+			return fmt.Errorf("failed to create adjustment for agent %d: %w", params.AgentID, err)
+		}
+		return s.Approve(ctx, c)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Commission adjustment made",
+		zap.Uint("commission_id", c.ID()),
+		zap.Uint("agent_id", c.AgentID()),
+		zap.String("category", c.Category().String()),
+		zap.Float64("amount", c.Amount().Float64()),
+		zap.String("currency", c.Currency()),
+		zap.String("actor", c.CreatedBy()),
+	)
+	return c, nil
+}
+
+// Reject rejects a pending commission on review. Nothing was earned on it
+// yet, so there is nothing to reverse.
+func (s *CommissionService) Reject(ctx context.Context, c *commission.Commission, reason, actor string) error {
+	if err := c.Reject(reason, actor); err != nil {
+		return err
+	}
+	if err := s.commissions.Update(ctx, c); err != nil {
+		return fmt.Errorf("failed to reject commission %d: %w", c.ID(), err)
+	}
+
+	s.logger.Info("Commission rejected",
+		zap.Uint("commission_id", c.ID()),
+		zap.String("order_id", c.OrderID()),
+		zap.String("reason", reason),
+		zap.String("actor", actor),
+	)
+	return nil
+}
+
+// Cancel cancels an unpaid commission, taking it back out of the agent's
+// pending or available balance. Commissions derived from it are reversed
+// in full. The actor is who cancelled it; empty means the system.
+func (s *CommissionService) Cancel(ctx context.Context, c *commission.Commission, reason, actor string) error {
+	if err := c.Cancel(reason, actor); err != nil {
+		return err
+	}
+	err := s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.commissions.Update(ctx, c); err != nil {
+			return fmt.Errorf("failed to cancel commission %d: %w", c.ID(), err)
+		}
+		return s.followDerived(ctx, c, func(derived *commission.Commission) commission.Refund {
+			return commission.Refund{Amount: derived.OrderTotal(), OrderTotal: derived.OrderTotal(), Reason: reason}
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("Commission cancelled",
+		zap.Uint("commission_id", c.ID()),
+		zap.String("order_id", c.OrderID()),
+		zap.String("reason", reason),
+		zap.String("actor", actor),
+	)
+	return nil
+}
+
+// Reverse reverses a commission for an order refund. Unpaid commissions
+// are cancelled when the whole order is refunded; anything else is clawed
+// back. Commissions derived from it follow with the same refund.
+//
+// Refunds are applied once per commission, by their key, so replayed
+// refund events are ignored. The clawback, the commission and those
+// derived from it are saved in one transaction.
+func (s *CommissionService) Reverse(ctx context.Context, c *commission.Commission, refund commission.Refund) error {
+	if c.Status().IsTerminal() {
+		return nil
+	}
+	if refund.IsFull() && !c.Status().WasPaid() {
+		return s.Cancel(ctx, c, refund.Reason, "")
+	}
+
+	keyed := refund
+	keyed.ID = refund.Key(c)
+	done, err := s.hasClawback(ctx, c.ID(), keyed.ID)
+	if err != nil || done {
+		return err
+	}
+
+	clawback, err := c.ClawBack(keyed)
+	if err != nil {
+		return fmt.Errorf("failed to claw back commission %d: %w", c.ID(), err)
+	}
+	err = s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.clawbacks.Create(ctx, clawback); err != nil {
+			return fmt.Errorf("failed to record clawback for commission %d: %w", c.ID(), err)
+		}
+		if err := s.commissions.Update(ctx, c); err != nil {
+			return fmt.Errorf("failed to update commission %d: %w", c.ID(), err)
+		}
+		return s.followDerived(ctx, c, func(*commission.Commission) commission.Refund {
+			return refund
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("Commission clawed back",
+		zap.Uint("clawback_id", clawback.ID()),
+		zap.Uint("commission_id", c.ID()),
+		zap.String("order_id", c.OrderID()),
+		zap.Float64("amount", clawback.Amount().Float64()),
+		zap.String("status", c.Status().String()),
+	)
+	return nil
+}
+
+// followDerived reverses the commissions derived from a sale commission
+func (s *CommissionService) followDerived(ctx context.Context, source *commission.Commission, refundFor func(*commission.Commission) commission.Refund) error {
+	if source.IsDerived() {
+		return nil
+	}
+
+	derived, err := s.commissions.GetBySourceID(ctx, source.ID())
+	if err != nil {
+		return fmt.Errorf("failed to load commissions derived from %d: %w", source.ID(), err)
+	}
+	for _, d := range derived {
+		if err := s.Reverse(ctx, d, refundFor(d)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasClawback reports whether a commission was already clawed back for a refund
+func (s *CommissionService) hasClawback(ctx context.Context, commissionID uint, refundID string) (bool, error) {
+	existing, err := s.clawbacks.GetByCommissionID(ctx, commissionID)
+	if err != nil {
+		return false, fmt.Errorf("failed to load clawbacks for commission %d: %w", commissionID, err)
+	}
+	for _, cb := range existing {
+		if cb.RefundID() == refundID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// createOverride generates and approves the team leader's override on an
+// approved sale commission, at the team's rate when the sale was made.
+// Agents without an active team, teams without a leader or rate, and
+// leaders selling on their own account earn none.
+func (s *CommissionService) createOverride(ctx context.Context, source *commission.Commission) error {
+	member, err := s.agents.GetByID(ctx, source.AgentID())
+	if err != nil {
+		return err
+	}
+	if member.TeamID() == nil {
+		return nil
+	}
+
+	t, err := s.teams.GetByID(ctx, *member.TeamID())
+	if errors.Is(err, team.ErrTeamNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !t.IsActive() || !t.HasLeader() || *t.LeaderID() == member.ID() {
+		return nil
+	}
+
+	rate := t.CommissionRate()
+	change, err := s.rates.GetRateAt(ctx, commission.RateScopeTeam, t.ID(), source.CreatedAt())
+	if err == nil {
+		rate = change.Rate()
+	} else if !errors.Is(err, commission.ErrRateChangeNotFound) {
+		return err
+	}
+	if rate <= 0 {
+		return nil
+	}
+
+	leader, err := s.agents.GetByID(ctx, *t.LeaderID())
+	if err != nil {
+		return err
+	}
+	if !leader.CanEarnCommission() {
+		s.logger.Info("Team leader cannot earn commission, skipping override",
+			zap.Uint("leader_id", leader.ID()),
+			zap.Uint("commission_id", source.ID()),
+		)
+		return nil
+	}
+
+	override, err := commission.NewOverrideCommission(source, leader.ID(), rate)
+	if err != nil {
+		return err
+	}
+	if err := s.ConvertForPayout(ctx, override, source.CreatedAt()); err != nil {
+		return err
+	}
+	if err := s.commissions.Create(ctx, override); err != nil {
+		// Already generated for this sale
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil
+		}
+		return err
+	}
+	if err := s.Approve(ctx, override); err != nil {
+		return err
+	}
+
+	s.logger.Info("Team override created",
+		zap.Uint("commission_id", override.ID()),
+		zap.Uint("source_commission_id", source.ID()),
+		zap.Uint("leader_id", leader.ID()),
+		zap.Float64("amount", override.Amount().Float64()),
+	)
+	return nil
+}
+
+// createDownline generates and approves the downline commissions of the
+// seller's upline on an approved sale commission, one level per
+// configured rate. Sponsors who cannot earn commission are skipped, and
+// their level's commission is not passed further up.
+func (s *CommissionService) createDownline(ctx context.Context, source *commission.Commission) error {
+	if len(s.downlineRates) == 0 {
+		return nil
+	}
+
+	upline, err := s.hierarchy.Upline(ctx, source.AgentID(), len(s.downlineRates))
+	if err != nil {
+		return err
+	}
+
+	for i, sponsor := range upline {
+		level, rate := i+1, s.downlineRates[i]
+		if rate <= 0 {
+			continue
+		}
+		if !sponsor.CanEarnCommission() {
+			s.logger.Info("Sponsor cannot earn commission, skipping downline level",
+				zap.Uint("sponsor_id", sponsor.ID()),
+				zap.Uint("commission_id", source.ID()),
+				zap.Int("level", level),
+			)
+			continue
+		}
+
+		downline, err := commission.NewDownlineCommission(source, sponsor.ID(), level, rate)
+		if err != nil {
+			return err
+		}
+		if err := s.ConvertForPayout(ctx, downline, source.CreatedAt()); err != nil {
+			return err
+		}
+		if err := s.commissions.Create(ctx, downline); err != nil {
+			// Already generated for this sale
+			if errors.Is(err, repository.ErrDuplicate) {
+				continue
+			}
+			return err
+		}
+		if err := s.Approve(ctx, downline); err != nil {
+			return err
+		}
+
+		s.logger.Info("Downline commission created",
+			zap.Uint("commission_id", downline.ID()),
+			zap.Uint("source_commission_id", source.ID()),
+			zap.Uint("sponsor_id", sponsor.ID()),
+			zap.Int("level", level),
+			zap.Float64("amount", downline.Amount().Float64()),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/payout"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/infrastructure/disbursement"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"go.uber.org/zap"
+)
+
+// ErrNoPayoutProvider is returned when no payout provider is configured
+var ErrNoPayoutProvider = errors.New("no payout provider is configured")
+
+// RetryPolicy controls how provider requests that fail transiently are
+// retried: up to Attempts tries, each given Timeout, waiting Backoff
+// before the second and doubling it after that
+type RetryPolicy struct {
+	Attempts int
+	Timeout  time.Duration
+	Backoff  time.Duration
+}
+
+// DisbursementService pays payouts through a payout provider. Submitting
+// a pending payout sends it to the provider, and the provider's updates,
+// returned to requests or pushed by callback, move the payout through
+// processing to completed or failed.
+type DisbursementService struct {
+	payouts  repository.PayoutReader
+	payees   PayeeResolver
+	service  *PayoutService
+	provider disbursement.Provider
+	retry    RetryPolicy
+	logger   *zap.Logger
+
+	// mu serialises status changes, so a callback racing the response to
+	// the request that caused it does not overwrite it
+	mu sync.Mutex
+}
+
+// NewDisbursementService creates a new disbursement service. provider may
+// be nil, in which case every request returns ErrNoPayoutProvider.
+func NewDisbursementService(
+	payouts repository.PayoutReader,
+	payees PayeeResolver,
+	service *PayoutService,
+	provider disbursement.Provider,
+	retry RetryPolicy,
+	logger *zap.Logger,
+) *DisbursementService {
+	if retry.Attempts < 1 {
+		retry.Attempts = 1
+	}
+	return &DisbursementService{
+		payouts:  payouts,
+		payees:   payees,
+		service:  service,
+		provider: provider,
+		retry:    retry,
+		logger:   logger,
+	}
+}
+
+// Submit sends a pending payout to the provider
+func (s *DisbursementService) Submit(ctx context.Context, p *payout.Payout) error {
+	if s.provider == nil {
+		return ErrNoPayoutProvider
+	}
+	if !p.IsPending() {
+		return fmt.Errorf("%w: payout is %s", shared.ErrInvalidPayoutTransition, p.Status())
+	}
+
+	payee, err := s.payees.Payee(ctx, p.AgentID())
+	if err != nil {
+		return fmt.Errorf("failed to resolve payee for agent %d: %w", p.AgentID(), err)
+	}
+	transfer := disbursement.Transfer{
+		Reference:   p.TransferReference(),
+		Amount:      p.Amount(),
+		Payee:       payee,
+		Description: fmt.Sprintf("Commission payout %s", p.Period()),
+	}
+
+	var update disbursement.Update
+	err = s.call(ctx, "submit", func(ctx context.Context) (err error) {
+		update, err = s.provider.Submit(ctx, transfer)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("Payout submitted to provider",
+		zap.Uint("payout_id", p.ID()),
+		zap.String("provider", s.provider.Name()),
+		zap.String("status", update.Status),
+	)
+	return s.apply(ctx, p.ID(), update)
+}
+
+// SubmitRun sends every pending payout in a run to the provider, and
+// returns the IDs of those submitted and why any others failed
+func (s *DisbursementService) SubmitRun(ctx context.Context, run *payout.Run) ([]uint, map[uint]error, error) {
+	if s.provider == nil {
+		return nil, nil, ErrNoPayoutProvider
+	}
+	if run.Status() != shared.PayoutRunCommitted {
+		return nil, nil, payout.ErrRunNotCommitted
+	}
+
+	var submitted []uint
+	failed := make(map[uint]error)
+	for _, id := range run.PayoutIDs() {
+		p, err := s.payouts.GetByID(ctx, id)
+		if err != nil {
+			failed[id] = err
+			continue
+		}
+		if !p.IsPending() {
+			continue
+		}
+		if err := s.Submit(ctx, p); err != nil {
+			failed[id] = err
+			continue
+		}
+		submitted = append(submitted, id)
+	}
+	if len(submitted) == 0 && len(failed) == 0 {
+		return nil, nil, payout.ErrNothingToSend
+	}
+	return submitted, failed, nil
+}
+
+// Sync queries a submitted payout's transfer at the provider and applies
+// its status, for when a callback was missed
+func (s *DisbursementService) Sync(ctx context.Context, p *payout.Payout) error {
+	if s.provider == nil {
+		return ErrNoPayoutProvider
+	}
+
+	var update disbursement.Update
+	err := s.call(ctx, "status", func(ctx context.Context) (err error) {
+		update, err = s.provider.Status(ctx, p.TransferReference())
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return s.apply(ctx, p.ID(), update)
+}
+
+// CancelTransfer cancels a processing payout's transfer at the provider.
+// The payout fails, and can then be retried or cancelled.
+func (s *DisbursementService) CancelTransfer(ctx context.Context, p *payout.Payout) error {
+	if s.provider == nil {
+		return ErrNoPayoutProvider
+	}
+	if !p.Status().IsProcessing() {
+		return fmt.Errorf("%w: payout is %s", shared.ErrInvalidPayoutTransition, p.Status())
+	}
+
+	var update disbursement.Update
+	err := s.call(ctx, "cancel", func(ctx context.Context) (err error) {
+		update, err = s.provider.Cancel(ctx, p.TransferReference())
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return s.apply(ctx, p.ID(), update)
+}
+
+// HandleUpdate applies a transfer update pushed by the provider. Updates
+// that the payout already reflects are ignored, so a provider may deliver
+// the same update more than once.
+func (s *DisbursementService) HandleUpdate(ctx context.Context, update disbursement.Update) error {
+	id, ok := payout.ParseReference(update.Reference)
+	if !ok {
+		return payout.ErrPayoutNotFound
+	}
+	return s.apply(ctx, id, update)
+}
+
+// apply moves a payout to the status a transfer update reports. The
+// payout is loaded afresh, as a callback may have changed it since the
+// caller loaded it.
+func (s *DisbursementService) apply(ctx context.Context, payoutID uint, update disbursement.Update) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, err := s.payouts.GetByID(ctx, payoutID)
+	if err != nil {
+		return err
+	}
+	// Updates for the transfers of earlier attempts no longer apply
+	if update.Reference != p.TransferReference() {
+		s.logger.Info("Ignoring update for an earlier transfer",
+			zap.Uint("payout_id", payoutID),
+			zap.String("reference", update.Reference),
+			zap.String("current_reference", p.TransferReference()),
+		)
+		return nil
+	}
+
+	// Every transfer the provider knows of has at least started
+	if p.IsPending() {
+		if err := s.service.Process(ctx, p); err != nil {
+			return err
+		}
+	}
+
+	switch update.Status {
+	case disbursement.StatusProcessing:
+		return nil
+	case disbursement.StatusSucceeded:
+		if p.IsCompleted() && p.TransactionRef() == update.TransactionRef {
+			return nil
+		}
+		return s.service.Complete(ctx, p, update.TransactionRef)
+	case disbursement.StatusFailed, disbursement.StatusCancelled:
+		if p.IsFailed() {
+			return nil
+		}
+		reason := update.Reason
+		if reason == "" {
+			reason = fmt.Sprintf("transfer %s at payout provider", update.Status)
+		}
+		return s.service.Fail(ctx, p, reason)
+	}
+	return fmt.Errorf("unknown transfer status %q", update.Status)
+}
+
+// call makes a provider request, retrying transient failures with
+// exponential backoff
+func (s *DisbursementService) call(ctx context.Context, operation string, request func(ctx context.Context) error) error {
+	backoff := s.retry.Backoff
+	var err error
+	for attempt := 1; attempt <= s.retry.Attempts; attempt++ {
+		err = s.attempt(ctx, request)
+		if err == nil || !disbursement.IsTransient(err) {
+			return err
+		}
+		if attempt == s.retry.Attempts {
+			break
+		}
+
+		s.logger.Warn("Payout provider request failed, retrying",
+			zap.String("operation", operation),
+			zap.Int("attempt", attempt),
+			zap.Duration("backoff", backoff),
+			zap.Error(err),
+		)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	return fmt.Errorf("payout provider %s failed after %d attempts: %w", operation, s.retry.Attempts, err)
+}
+
+// attempt makes one provider request, limited to the retry timeout
+func (s *DisbursementService) attempt(ctx context.Context, request func(ctx context.Context) error) error {
+	if s.retry.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.retry.Timeout)
+		defer cancel()
+	}
+	return request(ctx)
+}
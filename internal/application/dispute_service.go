@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/commission"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/dispute"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"go.uber.org/zap"
+)
+
+// DisputeService handles agents' disputes over missing or wrong
+// commissions, from opening through triage to a decision. Resolving a
+// dispute can grant the agent an adjustment commission on the order.
+type DisputeService struct {
+	disputes    repository.DisputeRepository
+	commissions repository.CommissionRepository
+	agents      repository.AgentReader
+	service     *CommissionService
+	logger      *zap.Logger
+}
+
+// NewDisputeService creates a new dispute service
+func NewDisputeService(
+	disputes repository.DisputeRepository,
+	commissions repository.CommissionRepository,
+	agents repository.AgentReader,
+	service *CommissionService,
+	logger *zap.Logger,
+) *DisputeService {
+	return &DisputeService{
+		disputes:    disputes,
+		commissions: commissions,
+		agents:      agents,
+		service:     service,
+		logger:      logger,
+	}
+}
+
+// Open opens an agent's dispute against an order, or against one of their
+// commissions. A dispute against a commission is on the commission's order.
+func (s *DisputeService) Open(ctx context.Context, agentID uint, orderID string, commissionID *uint, note string) (*dispute.Dispute, error) {
+	if commissionID != nil {
+		c, err := s.commissions.GetByID(ctx, *commissionID)
+		if err != nil {
+			return nil, err
+		}
+		// Agents can only dispute their own commissions
+		if c.AgentID() != agentID {
+			return nil, commission.ErrCommissionNotFound
+		}
+		if orderID == "" {
+			orderID = c.OrderID()
+		}
+		if orderID != c.OrderID() {
+			return nil, fmt.Errorf("%w: commission %d is not on order %s", dispute.ErrInvalidDispute, c.ID(), orderID)
+		}
+	}
+
+	d, err := dispute.Open(dispute.DisputeParams{
+		AgentID:      agentID,
+		OrderID:      orderID,
+		CommissionID: commissionID,
+	}, note)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.disputes.Create(ctx, d); err != nil {
+		return nil, fmt.Errorf("failed to open dispute: %w", err)
+	}
+
+	s.logger.Info("Dispute opened",
+		zap.Uint("dispute_id", d.ID()),
+		zap.Uint("agent_id", agentID),
+		zap.String("order_id", orderID),
+	)
+	return d, nil
+}
+
+// AddMessage adds a message from the agent or an admin to the thread
+func (s *DisputeService) AddMessage(ctx context.Context, d *dispute.Dispute, author, authorID, body string) error {
+	if err := d.AddMessage(author, authorID, body); err != nil {
+		return err
+	}
+	if err := s.disputes.Update(ctx, d); err != nil {
+		return fmt.Errorf("failed to add message to dispute %d: %w", d.ID(), err)
+	}
+	return nil
+}
+
+// StartReview marks a dispute as under review by an admin
+func (s *DisputeService) StartReview(ctx context.Context, d *dispute.Dispute, actor string) error {
+	if err := d.StartReview(); err != nil {
+		return err
+	}
+	if err := s.disputes.Update(ctx, d); err != nil {
+		return fmt.Errorf("failed to update dispute %d: %w", d.ID(), err)
+	}
+
+	s.logger.Info("Dispute under review", zap.Uint("dispute_id", d.ID()), zap.String("actor", actor))
+	return nil
+}
+
+// Resolve resolves a dispute in the agent's favour. A positive adjustment
+// is granted as an approved adjustment commission on the disputed order,
+// in the disputed commission's currency or else the agent's payout
+// currency.
+func (s *DisputeService) Resolve(ctx context.Context, d *dispute.Dispute, resolution, actor string, adjustment float64) error {
+	if resolution == "" {
+		return dispute.ErrEmptyMessage
+	}
+	if _, err := d.Status().TransitionTo(shared.DisputeResolved); err != nil {
+		return err
+	}
+
+	var adjustmentID *uint
+	if adjustment > 0 {
+		c, err := s.grantAdjustment(ctx, d, adjustment, resolution, actor)
+		if err != nil {
+			return err
+		}
+		id := c.ID()
+		adjustmentID = &id
+	}
+
+	if err := d.Resolve(resolution, actor, adjustmentID); err != nil {
+		return err
+	}
+	if err := s.disputes.Update(ctx, d); err != nil {
+		return fmt.Errorf("failed to resolve dispute %d: %w", d.ID(), err)
+	}
+
+	s.logger.Info("Dispute resolved",
+		zap.Uint("dispute_id", d.ID()),
+		zap.String("actor", actor),
+		zap.Float64("adjustment", adjustment),
+	)
+	return nil
+}
+
+// Reject rejects a dispute, leaving the agent's commissions as they are
+func (s *DisputeService) Reject(ctx context.Context, d *dispute.Dispute, reason, actor string) error {
+	if err := d.Reject(reason, actor); err != nil {
+		return err
+	}
+	if err := s.disputes.Update(ctx, d); err != nil {
+		return fmt.Errorf("failed to reject dispute %d: %w", d.ID(), err)
+	}
+
+	s.logger.Info("Dispute rejected", zap.Uint("dispute_id", d.ID()), zap.String("actor", actor))
+	return nil
+}
+
+// grantAdjustment grants the agent a correction on the disputed order,
+// noting the dispute and its resolution
+func (s *DisputeService) grantAdjustment(ctx context.Context, d *dispute.Dispute, amount float64, resolution, actor string) (*commission.Commission, error) {
+	currency, err := s.adjustmentCurrency(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := s.service.Adjust(ctx, commission.AdjustmentParams{
+		AgentID:   d.AgentID(),
+		OrderID:   d.OrderID(),
+		Amount:    shared.MoneyFromFloat(amount, currency),
+		Category:  shared.AdjustmentCorrection,
+		Note:      fmt.Sprintf("Dispute #%d: %s", d.ID(), resolution),
+		CreatedBy: actor,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to grant adjustment for dispute %d: %w", d.ID(), err)
+	}
+	return c, nil
+}
+
+// adjustmentCurrency returns the currency an adjustment for the dispute is
+// granted in
+func (s *DisputeService) adjustmentCurrency(ctx context.Context, d *dispute.Dispute) (string, error) {
+	if d.CommissionID() != nil {
+		c, err := s.commissions.GetByID(ctx, *d.CommissionID())
+		if err != nil {
+			return "", err
+		}
+		return c.Currency(), nil
+	}
+	a, err := s.agents.GetByID(ctx, d.AgentID())
+	if err != nil {
+		return "", err
+	}
+	return a.PayoutCurrency(), nil
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/fx"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"go.uber.org/zap"
+)
+
+// FXService maintains the local exchange rate table and converts amounts
+// at the rate in effect at a given time. A pair with no rate of its own is
+// converted at the inverse of the opposite pair's rate.
+type FXService struct {
+	rates  repository.FXRateRepository
+	logger *zap.Logger
+}
+
+// NewFXService creates a new FX service
+func NewFXService(rates repository.FXRateRepository, logger *zap.Logger) *FXService {
+	return &FXService{
+		rates:  rates,
+		logger: logger,
+	}
+}
+
+// RateAt returns the exchange rate from base to quote in effect at the
+// given time
+func (s *FXService) RateAt(ctx context.Context, base, quote string, at time.Time) (shared.ExchangeRate, error) {
+	if base == quote {
+		return shared.IdentityRate(base), nil
+	}
+
+	rate, err := s.rates.GetRateAt(ctx, base, quote, at)
+	if err == nil {
+		return rate.ExchangeRate(), nil
+	}
+	if !errors.Is(err, fx.ErrRateNotFound) {
+		return shared.ExchangeRate{}, fmt.Errorf("failed to load %s/%s rate: %w", base, quote, err)
+	}
+
+	inverse, err := s.rates.GetRateAt(ctx, quote, base, at)
+	if errors.Is(err, fx.ErrRateNotFound) {
+		return shared.ExchangeRate{}, fmt.Errorf("%w: %s/%s", fx.ErrRateNotFound, base, quote)
+	}
+	if err != nil {
+		return shared.ExchangeRate{}, fmt.Errorf("failed to load %s/%s rate: %w", quote, base, err)
+	}
+	return inverse.ExchangeRate().Inverse(), nil
+}
+
+// Convert converts an amount to another currency at the rate in effect at
+// the given time
+func (s *FXService) Convert(ctx context.Context, amount shared.Money, currency string, at time.Time) (shared.Money, error) {
+	rate, err := s.RateAt(ctx, amount.Currency(), currency, at)
+	if err != nil {
+		return shared.Money{}, err
+	}
+	return rate.Convert(amount)
+}
+
+// SetRate records the rate for a currency pair from effectiveFrom on. A
+// zero effectiveFrom takes effect immediately. Rates may be backdated to
+// record what applied in the past; amounts already converted keep the rate
+// they were converted at.
+func (s *FXService) SetRate(ctx context.Context, base, quote, rate string, effectiveFrom time.Time) (*fx.Rate, error) {
+	r, err := fx.NewRate(base, quote, rate, effectiveFrom)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.rates.Create(ctx, r); err != nil {
+		return nil, fmt.Errorf("failed to record exchange rate: %w", err)
+	}
+
+	s.logger.Info("Exchange rate recorded",
+		zap.Uint("fx_rate_id", r.ID()),
+		zap.String("base", r.BaseCurrency()),
+		zap.String("quote", r.QuoteCurrency()),
+		zap.String("rate", r.ExchangeRate().String()),
+		zap.Time("effective_from", r.EffectiveFrom()),
+	)
+	return r, nil
+}
+
+// DeleteRate removes a rate from the table
+func (s *FXService) DeleteRate(ctx context.Context, id uint) error {
+	r, err := s.rates.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.rates.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete exchange rate %d: %w", id, err)
+	}
+
+	s.logger.Info("Exchange rate deleted",
+		zap.Uint("fx_rate_id", id),
+		zap.String("base", r.BaseCurrency()),
+		zap.String("quote", r.QuoteCurrency()),
+	)
+	return nil
+}
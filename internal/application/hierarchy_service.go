@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/agent"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"go.uber.org/zap"
+)
+
+// HierarchyNode is an agent and its downline
+type HierarchyNode struct {
+	Agent    *agent.Agent
+	Level    int // Levels below the root of the walk
+	Children []*HierarchyNode
+}
+
+// Depth returns the number of levels below the node
+func (n *HierarchyNode) Depth() int {
+	depth := 0
+	for _, child := range n.Children {
+		if d := child.Depth() + 1; d > depth {
+			depth = d
+		}
+	}
+	return depth
+}
+
+// Size returns the number of agents below the node
+func (n *HierarchyNode) Size() int {
+	size := len(n.Children)
+	for _, child := range n.Children {
+		size += child.Size()
+	}
+	return size
+}
+
+// HierarchyService walks and restructures the agent upline/downline tree.
+// Moves are checked so that the tree never gains a cycle or grows deeper
+// than the configured maximum.
+type HierarchyService struct {
+	agents   repository.AgentRepository
+	maxDepth int
+	logger   *zap.Logger
+
+	// mu serialises moves so two concurrent moves cannot together form a
+	// cycle that neither would on its own.
+	mu sync.Mutex
+}
+
+// NewHierarchyService creates a new hierarchy service
+func NewHierarchyService(agents repository.AgentRepository, maxDepth int, logger *zap.Logger) *HierarchyService {
+	if maxDepth <= 0 {
+		maxDepth = agent.DefaultMaxDepth
+	}
+	return &HierarchyService{
+		agents:   agents,
+		maxDepth: maxDepth,
+		logger:   logger,
+	}
+}
+
+// MaxDepth returns the maximum number of levels below a top-level agent
+func (s *HierarchyService) MaxDepth() int {
+	return s.maxDepth
+}
+
+// Upline returns an agent's upline sponsors, nearest first, up to the
+// given number of levels. Zero levels means the whole upline.
+func (s *HierarchyService) Upline(ctx context.Context, agentID uint, levels int) ([]*agent.Agent, error) {
+	a, err := s.agents.GetByID(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+	return s.uplineOf(ctx, a, levels)
+}
+
+func (s *HierarchyService) uplineOf(ctx context.Context, a *agent.Agent, levels int) ([]*agent.Agent, error) {
+	if levels <= 0 || levels > s.maxDepth {
+		levels = s.maxDepth
+	}
+
+	var upline []*agent.Agent
+	seen := map[uint]bool{a.ID(): true}
+	for current := a; current.HasParent() && len(upline) < levels; {
+		parentID := *current.ParentID()
+		if seen[parentID] {
+			return nil, agent.ErrHierarchyCycle
+		}
+		seen[parentID] = true
+
+		parent, err := s.agents.GetByID(ctx, parentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load sponsor %d: %w", parentID, err)
+		}
+		upline = append(upline, parent)
+		current = parent
+	}
+	return upline, nil
+}
+
+// Downline returns the tree of agents below an agent, down to the given
+// number of levels. Zero levels means the whole downline.
+func (s *HierarchyService) Downline(ctx context.Context, agentID uint, levels int) (*HierarchyNode, error) {
+	a, err := s.agents.GetByID(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+	if levels <= 0 || levels > s.maxDepth {
+		levels = s.maxDepth
+	}
+
+	root := &HierarchyNode{Agent: a}
+	seen := map[uint]bool{a.ID(): true}
+	queue := []*HierarchyNode{root}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		if node.Level >= levels {
+			continue
+		}
+
+		parentID := node.Agent.ID()
+		children, _, err := s.agents.List(ctx, repository.AgentFilter{ParentID: &parentID, IncludeInactive: true})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load downline of agent %d: %w", parentID, err)
+		}
+		for _, child := range children {
+			if seen[child.ID()] {
+				return nil, agent.ErrHierarchyCycle
+			}
+			seen[child.ID()] = true
+
+			childNode := &HierarchyNode{Agent: child, Level: node.Level + 1}
+			node.Children = append(node.Children, childNode)
+			queue = append(queue, childNode)
+		}
+	}
+	return root, nil
+}
+
+// CheckSponsor checks that a new agent can join under a sponsor
+func (s *HierarchyService) CheckSponsor(ctx context.Context, parentID uint) error {
+	parent, err := s.agents.GetByID(ctx, parentID)
+	if err != nil {
+		return err
+	}
+	upline, err := s.uplineOf(ctx, parent, 0)
+	if err != nil {
+		return err
+	}
+	return agent.CheckMove(0, uplineIDs(parent, upline), 0, s.maxDepth)
+}
+
+// Move moves an agent, with its whole downline, under a new sponsor. A
+// nil parent makes the agent a top-level agent.
+func (s *HierarchyService) Move(ctx context.Context, agentID uint, parentID *uint) (*agent.Agent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, err := s.agents.GetByID(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if parentID == nil {
+		a.MoveToTop()
+	} else {
+		parent, err := s.agents.GetByID(ctx, *parentID)
+		if err != nil {
+			return nil, err
+		}
+		upline, err := s.uplineOf(ctx, parent, 0)
+		if err != nil {
+			return nil, err
+		}
+		downline, err := s.Downline(ctx, a.ID(), 0)
+		if err != nil {
+			return nil, err
+		}
+		if err := agent.CheckMove(a.ID(), uplineIDs(parent, upline), downline.Depth(), s.maxDepth); err != nil {
+			return nil, err
+		}
+		if err := a.MoveUnder(parent.ID()); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.agents.Update(ctx, a); err != nil {
+		return nil, fmt.Errorf("failed to move agent %d: %w", a.ID(), err)
+	}
+
+	s.logger.Info("Agent moved",
+		zap.Uint("agent_id", a.ID()),
+		zap.Uintp("parent_id", a.ParentID()),
+	)
+	return a, nil
+}
+
+// uplineIDs lists a sponsor's ID followed by its upline's IDs
+func uplineIDs(parent *agent.Agent, upline []*agent.Agent) []uint {
+	ids := make([]uint, 0, len(upline)+1)
+	ids = append(ids, parent.ID())
+	for _, a := range upline {
+		ids = append(ids, a.ID())
+	}
+	return ids
+}
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/agent"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"go.uber.org/zap"
+)
+
+// LedgerService reconciles agents' earnings ledgers against the
+// commissions, clawbacks and payouts they are meant to record.
+type LedgerService struct {
+	ledger      repository.LedgerReader
+	agents      repository.AgentReader
+	commissions repository.CommissionReader
+	clawbacks   repository.ClawbackReader
+	payouts     repository.PayoutReader
+	logger      *zap.Logger
+}
+
+// NewLedgerService creates a new ledger service
+func NewLedgerService(
+	ledger repository.LedgerReader,
+	agents repository.AgentReader,
+	commissions repository.CommissionReader,
+	clawbacks repository.ClawbackReader,
+	payouts repository.PayoutReader,
+	logger *zap.Logger,
+) *LedgerService {
+	return &LedgerService{
+		ledger:      ledger,
+		agents:      agents,
+		commissions: commissions,
+		clawbacks:   clawbacks,
+		payouts:     payouts,
+		logger:      logger,
+	}
+}
+
+// LedgerDiscrepancy is an account whose ledger balance differs from the
+// balance expected from the agent's commissions and payouts
+type LedgerDiscrepancy struct {
+	AgentID  uint
+	Account  shared.LedgerAccount
+	Balance  shared.Money
+	Expected shared.Money
+}
+
+// Difference returns the ledger balance less the expected balance
+func (d LedgerDiscrepancy) Difference() shared.Money {
+	return d.Balance.Sub(d.Expected)
+}
+
+// Reconciliation is the result of reconciling agents' ledgers
+type Reconciliation struct {
+	AgentsChecked int
+	Discrepancies []LedgerDiscrepancy
+	// UnbalancedEntries are the IDs of entries whose lines do not net to
+	// zero, by agent
+	UnbalancedEntries map[uint][]uint
+}
+
+// IsClean returns true if every ledger checked adds up
+func (r *Reconciliation) IsClean() bool {
+	return len(r.Discrepancies) == 0 && len(r.UnbalancedEntries) == 0
+}
+
+// Reconcile checks one agent's ledger, or every agent's when agentID is
+// zero. Each account balance must match its source records:
+//   - pending: the net payout amounts of pending commissions
+//   - available: the net payout amounts of approved commissions
+//   - paid_out: the amounts of payouts that were not cancelled
+//   - tax_withheld: the tax withheld from payouts that were not cancelled
+//   - reserve: the reserves held back by payouts that were not cancelled,
+//     less those released by them
+//   - clawback_receivable: the outstanding clawbacks on paid commissions
+func (s *LedgerService) Reconcile(ctx context.Context, agentID uint) (*Reconciliation, error) {
+	var agents []*agent.Agent
+	if agentID != 0 {
+		a, err := s.agents.GetByID(ctx, agentID)
+		if err != nil {
+			return nil, err
+		}
+		agents = []*agent.Agent{a}
+	} else {
+		all, _, err := s.agents.List(ctx, repository.AgentFilter{IncludeInactive: true})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load agents: %w", err)
+		}
+		agents = all
+	}
+
+	result := &Reconciliation{UnbalancedEntries: make(map[uint][]uint)}
+	for _, a := range agents {
+		discrepancies, err := s.reconcileAgent(ctx, a.ID())
+		if err != nil {
+			return nil, fmt.Errorf("failed to reconcile agent %d: %w", a.ID(), err)
+		}
+		result.Discrepancies = append(result.Discrepancies, discrepancies...)
+
+		unbalanced, err := s.ledger.GetUnbalanced(ctx, a.ID())
+		if err != nil {
+			return nil, fmt.Errorf("failed to check agent %d entries: %w", a.ID(), err)
+		}
+		if len(unbalanced) > 0 {
+			result.UnbalancedEntries[a.ID()] = unbalanced
+		}
+		result.AgentsChecked++
+	}
+
+	if !result.IsClean() {
+		s.logger.Warn("Ledger reconciliation found discrepancies",
+			zap.Uint("agent_id", agentID),
+			zap.Int("discrepancies", len(result.Discrepancies)),
+			zap.Int("agents_with_unbalanced_entries", len(result.UnbalancedEntries)),
+		)
+	}
+	return result, nil
+}
+
+// balanceKey identifies an account balance in one currency
+type balanceKey struct {
+	account  shared.LedgerAccount
+	currency string
+}
+
+// reconcileAgent compares an agent's ledger balances with the balances
+// expected from their records
+func (s *LedgerService) reconcileAgent(ctx context.Context, agentID uint) ([]LedgerDiscrepancy, error) {
+	expected, err := s.expectedBalances(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+	balances, err := s.ledger.GetBalances(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	actual := make(map[balanceKey]shared.Money)
+	for _, balance := range balances {
+		if balance.Account == shared.LedgerCommissionExpense {
+			continue
+		}
+		actual[balanceKey{balance.Account, balance.Amount.Currency()}] = balance.Amount
+	}
+
+	keys := make([]balanceKey, 0, len(actual)+len(expected))
+	for key := range actual {
+		keys = append(keys, key)
+	}
+	for key := range expected {
+		if _, ok := actual[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].account != keys[j].account {
+			return keys[i].account < keys[j].account
+		}
+		return keys[i].currency < keys[j].currency
+	})
+
+	var discrepancies []LedgerDiscrepancy
+	for _, key := range keys {
+		balance, ok := actual[key]
+		if !ok {
+			balance = shared.ZeroMoney(key.currency)
+		}
+		want, ok := expected[key]
+		if !ok {
+			want = shared.ZeroMoney(key.currency)
+		}
+		if balance.Cmp(want) != 0 {
+			discrepancies = append(discrepancies, LedgerDiscrepancy{
+				AgentID:  agentID,
+				Account:  key.account,
+				Balance:  balance,
+				Expected: want,
+			})
+		}
+	}
+	return discrepancies, nil
+}
+
+// expectedBalances sums an agent's commissions, clawbacks and payouts into
+// the account balances the ledger should hold
+func (s *LedgerService) expectedBalances(ctx context.Context, agentID uint) (map[balanceKey]shared.Money, error) {
+	expected := make(map[balanceKey]shared.Money)
+	add := func(account shared.LedgerAccount, amount shared.Money) {
+		key := balanceKey{account, amount.Currency()}
+		total, ok := expected[key]
+		if !ok {
+			total = shared.ZeroMoney(key.currency)
+		}
+		expected[key] = total.Add(amount)
+	}
+
+	for status, account := range map[shared.CommissionStatus]shared.LedgerAccount{
+		shared.CommissionPending:  shared.LedgerPending,
+		shared.CommissionApproved: shared.LedgerAvailable,
+	} {
+		commissions, _, err := s.commissions.GetByAgentID(ctx, agentID, repository.CommissionFilter{Status: status.String()})
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range commissions {
+			add(account, c.NetPayoutAmount())
+		}
+	}
+
+	clawbacks, err := s.clawbacks.GetOutstanding(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+	for _, cb := range clawbacks {
+		add(shared.LedgerClawbackReceivable, cb.PayoutAmount().Neg())
+	}
+
+	payouts, _, err := s.payouts.GetByAgentID(ctx, agentID, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range payouts {
+		switch p.Status() {
+		case shared.PayoutCancelled:
+			continue
+		case shared.PayoutFailed:
+			// A failed payout's entry is reversed, but its commissions stay
+			// in it and the clawbacks it netted stay recovered
+			add(shared.LedgerAvailable, p.GrossAmount())
+			add(shared.LedgerClawbackReceivable, p.Deductions())
+			continue
+		case shared.PayoutCompleted:
+			add(shared.LedgerPaidOut, p.Amount())
+		default:
+			add(shared.LedgerInPayout, p.Amount())
+		}
+		add(shared.LedgerTaxWithheld, p.Withheld())
+		add(shared.LedgerReserve, p.Reserved().Sub(p.Released()))
+	}
+	return expected, nil
+}
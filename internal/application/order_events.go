@@ -0,0 +1,347 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/commission"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/infrastructure/messaging"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Order lifecycle subjects published by service-order
+const (
+	SubjectOrderPaid      = "order.paid"
+	SubjectOrderCompleted = "order.completed"
+	SubjectOrderCancelled = "order.cancelled"
+	SubjectOrderRefunded  = "order.refunded"
+)
+
+// OrderEvent is the payload of an order lifecycle event
+type OrderEvent struct {
+	OrderID      string            `json:"order_id"`
+	OrderNumber  string            `json:"order_number"`
+	AgentCode    string            `json:"agent_code"`       // Referral code used at checkout
+	Splits       []OrderEventSplit `json:"splits,omitempty"` // Co-selling agents, instead of agent_code
+	Subtotal     float64           `json:"subtotal"`
+	ShippingCost float64           `json:"shipping_cost"`
+	Discount     float64           `json:"discount"`
+	Total        float64           `json:"total"`
+	Currency     string            `json:"currency,omitempty"`      // Order currency; omitted means the default currency
+	RefundID     string            `json:"refund_id,omitempty"`     // order.refunded only
+	RefundAmount float64           `json:"refund_amount,omitempty"` // order.refunded only
+	Items        []OrderEventItem  `json:"items"`
+	OccurredAt   time.Time         `json:"occurred_at"`
+}
+
+// money converts an amount in the event to Money in the order currency
+func (e *OrderEvent) money(amount float64) shared.Money {
+	currency := e.Currency
+	if currency == "" {
+		currency = shared.DefaultCurrency
+	}
+	return shared.MoneyFromFloat(amount, currency)
+}
+
+// OrderEventSplit is one co-selling agent's percentage share of an order.
+// The shares of an order must sum to 100.
+type OrderEventSplit struct {
+	AgentCode string  `json:"agent_code"`
+	Share     float64 `json:"share"`
+}
+
+// OrderEventItem is an order line in an order event
+type OrderEventItem struct {
+	ProductID  string  `json:"product_id"`
+	CategoryID string  `json:"category_id"`
+	Subtotal   float64 `json:"subtotal"`
+}
+
+// OrderEventSubscriber creates, approves and reverses commissions from
+// order lifecycle events:
+//
+//   - order.paid creates a pending commission
+//   - order.completed approves it
+//   - order.cancelled and full order.refunded cancel it, or claw it back
+//     in full if it was already paid
+//   - partial order.refunded claws back a pro-rated share of it
+//
+// Co-sold orders list their agents' shares in splits, and get one linked
+// commission per agent.
+//
+// Commission creation is idempotent per order ID and clawbacks per refund,
+// so replayed or out-of-order events never count twice.
+type OrderEventSubscriber struct {
+	calculator  *CommissionCalculatorService
+	service     *CommissionService
+	commissions repository.CommissionRepository
+	agents      repository.AgentReader
+	tx          repository.Transactor
+	logger      *zap.Logger
+
+	// mu serialises event handling so the check-then-create on an order
+	// cannot race within this process. The unique index on
+	// (order_id, agent_id) covers other replicas.
+	mu sync.Mutex
+}
+
+// NewOrderEventSubscriber creates a new order event subscriber
+func NewOrderEventSubscriber(
+	calculator *CommissionCalculatorService,
+	service *CommissionService,
+	commissions repository.CommissionRepository,
+	agents repository.AgentReader,
+	tx repository.Transactor,
+	logger *zap.Logger,
+) *OrderEventSubscriber {
+	return &OrderEventSubscriber{
+		calculator:  calculator,
+		service:     service,
+		commissions: commissions,
+		agents:      agents,
+		tx:          tx,
+		logger:      logger,
+	}
+}
+
+// Subscribe registers the subscriber for all order lifecycle subjects
+func (s *OrderEventSubscriber) Subscribe(bus messaging.Bus) error {
+	for _, subject := range []string{SubjectOrderPaid, SubjectOrderCompleted, SubjectOrderCancelled, SubjectOrderRefunded} {
+		if err := bus.Subscribe(subject, s.Handle); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Handle processes a single order event message
+func (s *OrderEventSubscriber) Handle(ctx context.Context, msg messaging.Message) error {
+	var event OrderEvent
+	if err := json.Unmarshal(msg.Data, &event); err != nil {
+		return fmt.Errorf("invalid %s payload: %w", msg.Subject, err)
+	}
+	if event.OrderID == "" {
+		return fmt.Errorf("invalid %s payload: order_id is required", msg.Subject)
+	}
+	currency, err := shared.ParseCurrency(event.Currency)
+	if err != nil {
+		return fmt.Errorf("invalid %s payload: %w", msg.Subject, err)
+	}
+	event.Currency = currency
+
+	// Orders placed without an agent referral earn no commission
+	if event.AgentCode == "" && len(event.Splits) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch msg.Subject {
+	case SubjectOrderPaid:
+		_, err := s.ensureCommissions(ctx, &event)
+		return err
+	case SubjectOrderCompleted:
+		return s.approveCommissions(ctx, &event)
+	case SubjectOrderCancelled:
+		return s.reverseCommissions(ctx, &event, commission.Refund{
+			Amount:     event.money(event.Total),
+			OrderTotal: event.money(event.Total),
+			Reason:     "order cancelled",
+		})
+	case SubjectOrderRefunded:
+		refund := commission.Refund{
+			ID:         event.RefundID,
+			Amount:     event.money(event.RefundAmount),
+			OrderTotal: event.money(event.Total),
+			Reason:     "order refunded",
+		}
+		// A refund with no amount refunds the whole order
+		if !refund.Amount.IsPositive() {
+			refund.Amount = refund.OrderTotal
+		}
+		if !refund.IsFull() {
+			refund.Reason = "order partially refunded"
+		}
+		return s.reverseCommissions(ctx, &event, refund)
+	default:
+		s.logger.Debug("Ignoring order event", zap.String("subject", msg.Subject))
+		return nil
+	}
+}
+
+// ensureCommissions returns the order's commissions, creating one for
+// each referring agent if none exist yet. The commissions of a co-sold
+// order are created in one transaction, so the order is never left with
+// only some of its splits. Corrections granted on the order before its
+// commissions were created do not count.
+func (s *OrderEventSubscriber) ensureCommissions(ctx context.Context, event *OrderEvent) ([]*commission.Commission, error) {
+	existing, err := s.commissions.GetByOrderID(ctx, event.OrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load commissions for order %s: %w", event.OrderID, err)
+	}
+	for _, c := range existing {
+		if !c.IsAdjustment() {
+			return existing, nil
+		}
+	}
+
+	splits, err := s.resolveSplits(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &CommissionCalculationRequest{
+		OrderID:        event.OrderID,
+		OrderTotal:     event.money(event.Total),
+		OrderSubtotal:  event.money(event.Subtotal),
+		ShippingCost:   event.money(event.ShippingCost),
+		DiscountAmount: event.money(event.Discount),
+		OrderedAt:      event.OccurredAt,
+	}
+	for _, item := range event.Items {
+		req.Items = append(req.Items, CommissionLineItem{
+			ProductID:  item.ProductID,
+			CategoryID: item.CategoryID,
+			Amount:     event.money(item.Subtotal),
+		})
+	}
+
+	results, err := s.calculator.CalculateSplitCommission(ctx, req, splits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate commission for order %s: %w", event.OrderID, err)
+	}
+
+	var groupID string
+	if len(splits) > 1 {
+		groupID = uuid.NewString()
+	}
+
+	created := make([]*commission.Commission, 0, len(results))
+	for _, result := range results {
+		if !result.CommissionAmount.IsPositive() {
+			continue
+		}
+
+		c, err := commission.NewCommission(commission.CommissionParams{
+			AgentID:      result.AgentID,
+			OrderID:      event.OrderID,
+			OrderTotal:   result.OrderBaseAmount,
+			Rate:         result.CommissionRate,
+			Amount:       result.CommissionAmount,
+			SplitGroupID: groupID,
+			SplitShare:   result.Share,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to build commission for order %s: %w", event.OrderID, err)
+		}
+		// Converted at the rate in effect when the order was placed
+		convertAt := event.OccurredAt
+		if convertAt.IsZero() {
+			convertAt = c.CreatedAt()
+		}
+		if err := s.service.ConvertForPayout(ctx, c, convertAt); err != nil {
+			return nil, fmt.Errorf("failed to convert commission for order %s: %w", event.OrderID, err)
+		}
+
+		created = append(created, c)
+	}
+
+	err = s.tx.Transaction(ctx, func(ctx context.Context) error {
+		for _, c := range created {
+			if err := s.commissions.Create(ctx, c); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	// Another replica created them first
+	if errors.Is(err, repository.ErrDuplicate) {
+		return s.commissions.GetByOrderID(ctx, event.OrderID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create commissions for order %s: %w", event.OrderID, err)
+	}
+
+	for _, c := range created {
+		s.logger.Info("Commission created from order event",
+			zap.Uint("commission_id", c.ID()),
+			zap.String("order_id", event.OrderID),
+			zap.Uint("agent_id", c.AgentID()),
+			zap.Float64("amount", c.Amount().Float64()),
+			zap.Float64("share", c.SplitShare()),
+		)
+	}
+	return created, nil
+}
+
+// resolveSplits maps the event's agent codes to agent IDs. An order with
+// a single referring agent is a 100% split.
+func (s *OrderEventSubscriber) resolveSplits(ctx context.Context, event *OrderEvent) ([]commission.Split, error) {
+	eventSplits := event.Splits
+	if len(eventSplits) == 0 {
+		eventSplits = []OrderEventSplit{{AgentCode: event.AgentCode, Share: commission.FullShare}}
+	}
+
+	splits := make([]commission.Split, len(eventSplits))
+	for i, split := range eventSplits {
+		a, err := s.agents.GetByCode(ctx, split.AgentCode)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve agent %s: %w", split.AgentCode, err)
+		}
+		splits[i] = commission.Split{AgentID: a.ID(), Share: split.Share}
+	}
+	return splits, nil
+}
+
+// approveCommissions approves the order's pending sale commissions and
+// those derived from them. Adjustments made on the order, by an admin or
+// on resolving a dispute, are left to their own review.
+func (s *OrderEventSubscriber) approveCommissions(ctx context.Context, event *OrderEvent) error {
+	commissions, err := s.ensureCommissions(ctx, event)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range commissions {
+		if !c.IsPending() || c.IsAdjustment() {
+			continue
+		}
+		if err := s.service.Approve(ctx, c); err != nil {
+			return err
+		}
+
+		s.logger.Info("Commission approved from order event",
+			zap.Uint("commission_id", c.ID()),
+			zap.String("order_id", event.OrderID),
+		)
+	}
+	return nil
+}
+
+// reverseCommissions reverses the order's sale commissions, and any
+// corrections granted on it, for a refund. Derived commissions follow their
+// sale commission.
+func (s *OrderEventSubscriber) reverseCommissions(ctx context.Context, event *OrderEvent, refund commission.Refund) error {
+	commissions, err := s.commissions.GetByOrderID(ctx, event.OrderID)
+	if err != nil {
+		return fmt.Errorf("failed to load commissions for order %s: %w", event.OrderID, err)
+	}
+
+	for _, c := range commissions {
+		if c.IsDerived() || c.Amount().IsNegative() {
+			continue
+		}
+		if err := s.service.Reverse(ctx, c, refund); err != nil {
+			return err
+		}
+	}
+	return nil
+}
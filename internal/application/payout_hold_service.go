@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/payout"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/infrastructure/bankfile"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"go.uber.org/zap"
+)
+
+// PayoutHoldService manages the holds admins place on risky agents'
+// payouts and the reserve policies that keep back a share of them. On top
+// of the checks on the agent's payout method, it decides whether payouts
+// may be created for an agent and sent to them, for the payout service,
+// bank files and the payout provider.
+type PayoutHoldService struct {
+	holds    repository.PayoutHoldRepository
+	policies repository.ReservePolicyRepository
+	reserves repository.PayoutReserveReader
+	agents   repository.AgentReader
+	methods  *PayoutMethodService
+	logger   *zap.Logger
+}
+
+// NewPayoutHoldService creates a new payout hold service
+func NewPayoutHoldService(
+	holds repository.PayoutHoldRepository,
+	policies repository.ReservePolicyRepository,
+	reserves repository.PayoutReserveReader,
+	agents repository.AgentReader,
+	methods *PayoutMethodService,
+	logger *zap.Logger,
+) *PayoutHoldService {
+	return &PayoutHoldService{
+		holds:    holds,
+		policies: policies,
+		reserves: reserves,
+		agents:   agents,
+		methods:  methods,
+		logger:   logger,
+	}
+}
+
+// Place puts a hold on an agent's payouts until it expires
+func (s *PayoutHoldService) Place(ctx context.Context, agentID uint, reason string, expiresAt time.Time, actor string) (*payout.Hold, error) {
+	if _, err := s.agents.GetByID(ctx, agentID); err != nil {
+		return nil, err
+	}
+	h, err := payout.NewHold(payout.HoldParams{
+		AgentID:   agentID,
+		Reason:    reason,
+		ExpiresAt: expiresAt,
+		CreatedBy: actor,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.holds.Create(ctx, h); err != nil {
+		return nil, fmt.Errorf("failed to create payout hold: %w", err)
+	}
+
+	s.logger.Info("Payout hold placed",
+		zap.Uint("hold_id", h.ID()),
+		zap.Uint("agent_id", agentID),
+		zap.String("reason", h.Reason()),
+		zap.Time("expires_at", h.ExpiresAt()),
+		zap.String("actor", actor),
+	)
+	return h, nil
+}
+
+// Release ends a hold before it expires
+func (s *PayoutHoldService) Release(ctx context.Context, h *payout.Hold, actor string) error {
+	if err := h.Release(actor); err != nil {
+		return err
+	}
+	if err := s.holds.Update(ctx, h); err != nil {
+		return fmt.Errorf("failed to release payout hold %d: %w", h.ID(), err)
+	}
+
+	s.logger.Info("Payout hold released",
+		zap.Uint("hold_id", h.ID()),
+		zap.Uint("agent_id", h.AgentID()),
+		zap.String("actor", actor),
+	)
+	return nil
+}
+
+// Active returns the agent's active hold that lasts longest, or nil if
+// none is active
+func (s *PayoutHoldService) Active(ctx context.Context, agentID uint) (*payout.Hold, error) {
+	holds, err := s.holds.ListByAgent(ctx, agentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load payout holds: %w", err)
+	}
+	now := time.Now()
+	var active *payout.Hold
+	for _, h := range holds {
+		if h.IsActive(now) && (active == nil || h.ExpiresAt().After(active.ExpiresAt())) {
+			active = h
+		}
+	}
+	return active, nil
+}
+
+// Holding returns an error wrapping payout.ErrPayoutOnHold, saying why, if
+// an admin hold stops payouts being created for the agent
+func (s *PayoutHoldService) Holding(ctx context.Context, agentID uint) error {
+	h, err := s.Active(ctx, agentID)
+	if err != nil {
+		return err
+	}
+	if h != nil {
+		return h.Err()
+	}
+	return nil
+}
+
+// Check returns an error wrapping payout.ErrPayoutOnHold if the agent's
+// payouts may not be sent: an admin hold is active, or their payout
+// method is not ready
+func (s *PayoutHoldService) Check(ctx context.Context, agentID uint) error {
+	if err := s.Holding(ctx, agentID); err != nil {
+		return err
+	}
+	return s.methods.Check(ctx, agentID)
+}
+
+// Payee returns who the agent's payouts are paid to, or an error wrapping
+// payout.ErrPayoutOnHold if they may not be sent
+func (s *PayoutHoldService) Payee(ctx context.Context, agentID uint) (bankfile.Payee, error) {
+	if err := s.Holding(ctx, agentID); err != nil {
+		return bankfile.Payee{}, err
+	}
+	return s.methods.Payee(ctx, agentID)
+}
+
+// ReservePolicy returns an agent's reserve policy
+func (s *PayoutHoldService) ReservePolicy(ctx context.Context, agentID uint) (*payout.ReservePolicy, error) {
+	return s.policies.GetByAgentID(ctx, agentID)
+}
+
+// SetReservePolicy creates or changes an agent's reserve policy. Payouts
+// created from now on keep back reserves by it.
+func (s *PayoutHoldService) SetReservePolicy(ctx context.Context, agentID uint, percent float64, days int, reason, actor string) (*payout.ReservePolicy, error) {
+	if _, err := s.agents.GetByID(ctx, agentID); err != nil {
+		return nil, err
+	}
+
+	policy, err := s.policies.GetByAgentID(ctx, agentID)
+	switch {
+	case errors.Is(err, payout.ErrReservePolicyNotFound):
+		policy, err = payout.NewReservePolicy(payout.ReservePolicyParams{
+			AgentID:   agentID,
+			Percent:   percent,
+			Days:      days,
+			Reason:    reason,
+			UpdatedBy: actor,
+		})
+	case err == nil:
+		err = policy.Update(percent, days, reason, actor)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.policies.Save(ctx, policy); err != nil {
+		return nil, fmt.Errorf("failed to save reserve policy: %w", err)
+	}
+
+	s.logger.Info("Reserve policy saved",
+		zap.Uint("agent_id", agentID),
+		zap.Float64("percent", percent),
+		zap.Int("days", days),
+		zap.String("actor", actor),
+	)
+	return policy, nil
+}
+
+// RemoveReservePolicy stops keeping back reserves from an agent's payouts.
+// Reserves already held are released when they are due.
+func (s *PayoutHoldService) RemoveReservePolicy(ctx context.Context, agentID uint, actor string) error {
+	if err := s.policies.Delete(ctx, agentID); err != nil {
+		return err
+	}
+	s.logger.Info("Reserve policy removed", zap.Uint("agent_id", agentID), zap.String("actor", actor))
+	return nil
+}
+
+// ReserveTerms returns the percentage of an agent's payouts kept back in
+// reserve and for how many days, or zeros if they have no policy
+func (s *PayoutHoldService) ReserveTerms(ctx context.Context, agentID uint) (float64, int, error) {
+	policy, err := s.policies.GetByAgentID(ctx, agentID)
+	if errors.Is(err, payout.ErrReservePolicyNotFound) {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	return policy.Percent(), policy.Days(), nil
+}
+
+// HoldBalances are what is kept from an agent: the hold on their payouts,
+// if one is active, and their reserves still held
+type HoldBalances struct {
+	Hold        *payout.Hold
+	Reserved    shared.Money // In the agent's payout currency
+	NextRelease *time.Time   // When the next reserve is due
+}
+
+// Balances returns the hold on an agent's payouts and the total of their
+// reserves still held
+func (s *PayoutHoldService) Balances(ctx context.Context, agentID uint) (*HoldBalances, error) {
+	a, err := s.agents.GetByID(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+	hold, err := s.Active(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+	reserves, err := s.reserves.ListByAgent(ctx, agentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load reserves: %w", err)
+	}
+
+	balances := &HoldBalances{Hold: hold, Reserved: shared.ZeroMoney(a.PayoutCurrency())}
+	for _, r := range reserves {
+		if !r.IsHeld() || !balances.Reserved.SameCurrency(r.Amount()) {
+			continue
+		}
+		balances.Reserved = balances.Reserved.Add(r.Amount())
+		if releaseAt := r.ReleaseAt(); balances.NextRelease == nil || releaseAt.Before(*balances.NextRelease) {
+			balances.NextRelease = &releaseAt
+		}
+	}
+	return balances, nil
+}
@@ -0,0 +1,325 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/payout"
+	"github.com/Ecom-micro-template/service-agent/internal/infrastructure/bankfile"
+	"github.com/Ecom-micro-template/service-agent/internal/infrastructure/messaging"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"go.uber.org/zap"
+)
+
+// Subjects of the payout method events published to the message bus
+const (
+	SubjectPayoutMethodVerification = "agent.payout_method.verification_requested"
+	SubjectPayoutMethodChanged      = "agent.payout_method.changed"
+)
+
+// Payout method changes reported in PayoutMethodChangedEvent
+const (
+	PayoutMethodAdded   = "added"
+	PayoutMethodUpdated = "updated"
+	PayoutMethodDefault = "default"
+	PayoutMethodRemoved = "removed"
+)
+
+// PayoutMethodVerificationEvent is the payload of
+// agent.payout_method.verification_requested. The notification service
+// sends the code to the agent, who enters it in the portal.
+type PayoutMethodVerificationEvent struct {
+	AgentID       uint      `json:"agent_id"`
+	AgentCode     string    `json:"agent_code"`
+	Email         string    `json:"email"`
+	MethodID      uint      `json:"method_id"`
+	MethodType    string    `json:"method_type"`
+	Provider      string    `json:"provider"`
+	AccountNumber string    `json:"account_number"` // Masked
+	Code          string    `json:"code"`
+	ExpiresAt     time.Time `json:"expires_at"`
+}
+
+// PayoutMethodChangedEvent is the payload of agent.payout_method.changed,
+// so the agent is told whenever where they are paid changes
+type PayoutMethodChangedEvent struct {
+	AgentID       uint      `json:"agent_id"`
+	AgentCode     string    `json:"agent_code"`
+	Email         string    `json:"email"`
+	MethodID      uint      `json:"method_id"`
+	Change        string    `json:"change"`
+	Provider      string    `json:"provider"`
+	AccountNumber string    `json:"account_number"` // Masked
+	HeldUntil     time.Time `json:"held_until"`
+	OccurredAt    time.Time `json:"occurred_at"`
+}
+
+// PayoutMethodPolicy controls payout method verification and the hold
+// after changes
+type PayoutMethodPolicy struct {
+	// Cooldown is how long payouts to a method are held after it is
+	// added, its details change or it becomes the default
+	Cooldown time.Duration
+
+	// CodeTTL is how long a verification code can be used
+	CodeTTL time.Duration
+}
+
+// PayoutMethodService manages the accounts agents are paid into. New and
+// changed methods are verified with a code sent to the agent, and payouts
+// are held while the agent's default method is unverified or in its
+// cooldown. It resolves payees for bank files and the payout provider,
+// and decides holds for the payout service.
+type PayoutMethodService struct {
+	methods repository.PayoutMethodRepository
+	agents  repository.AgentReader
+	bus     messaging.Bus
+	policy  PayoutMethodPolicy
+	logger  *zap.Logger
+}
+
+// NewPayoutMethodService creates a new payout method service
+func NewPayoutMethodService(
+	methods repository.PayoutMethodRepository,
+	agents repository.AgentReader,
+	bus messaging.Bus,
+	policy PayoutMethodPolicy,
+	logger *zap.Logger,
+) *PayoutMethodService {
+	return &PayoutMethodService{
+		methods: methods,
+		agents:  agents,
+		bus:     bus,
+		policy:  policy,
+		logger:  logger,
+	}
+}
+
+// Add adds a payout method for an agent and sends its verification code.
+// An agent's first method is their default.
+func (s *PayoutMethodService) Add(ctx context.Context, params payout.MethodParams) (*payout.Method, error) {
+	existing, err := s.methods.ListByAgent(ctx, params.AgentID)
+	if err != nil {
+		return nil, err
+	}
+	params.IsDefault = len(existing) == 0
+
+	m, err := payout.NewMethod(params)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.methods.Create(ctx, m); err != nil {
+		return nil, fmt.Errorf("failed to create payout method: %w", err)
+	}
+
+	s.logger.Info("Payout method added",
+		zap.Uint("method_id", m.ID()),
+		zap.Uint("agent_id", m.AgentID()),
+		zap.String("type", m.Type().String()),
+	)
+	s.publishChanged(ctx, m, PayoutMethodAdded)
+	return m, s.SendCode(ctx, m)
+}
+
+// Update changes where a method pays. It must be verified again, with the
+// new code sent to the agent, and payouts to it are held again.
+func (s *PayoutMethodService) Update(ctx context.Context, m *payout.Method, provider, providerCode, accountName, accountNumber string) error {
+	if err := m.UpdateDetails(provider, providerCode, accountName, accountNumber); err != nil {
+		return err
+	}
+	if err := s.methods.Update(ctx, m); err != nil {
+		return fmt.Errorf("failed to update payout method %d: %w", m.ID(), err)
+	}
+
+	s.logger.Info("Payout method updated", zap.Uint("method_id", m.ID()), zap.Uint("agent_id", m.AgentID()))
+	s.publishChanged(ctx, m, PayoutMethodUpdated)
+	return s.SendCode(ctx, m)
+}
+
+// SendCode sends a new verification code for an unverified method,
+// replacing any outstanding code
+func (s *PayoutMethodService) SendCode(ctx context.Context, m *payout.Method) error {
+	a, err := s.agents.GetByID(ctx, m.AgentID())
+	if err != nil {
+		return err
+	}
+
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return fmt.Errorf("failed to generate verification code: %w", err)
+	}
+	code := fmt.Sprintf("%06d", n.Int64())
+	expiresAt := time.Now().Add(s.policy.CodeTTL)
+
+	if err := m.StartVerification(hashVerificationCode(m, code), expiresAt); err != nil {
+		return err
+	}
+	if err := s.methods.Update(ctx, m); err != nil {
+		return fmt.Errorf("failed to update payout method %d: %w", m.ID(), err)
+	}
+
+	data, _ := json.Marshal(PayoutMethodVerificationEvent{
+		AgentID:       a.ID(),
+		AgentCode:     a.Code(),
+		Email:         a.Email(),
+		MethodID:      m.ID(),
+		MethodType:    m.Type().String(),
+		Provider:      m.Provider(),
+		AccountNumber: m.MaskedAccountNumber(),
+		Code:          code,
+		ExpiresAt:     expiresAt,
+	})
+	if err := s.bus.Publish(ctx, SubjectPayoutMethodVerification, data); err != nil {
+		return fmt.Errorf("failed to send verification code: %w", err)
+	}
+	return nil
+}
+
+// Verify verifies a method with the code sent to the agent. Wrong codes
+// are counted, so the method is saved either way.
+func (s *PayoutMethodService) Verify(ctx context.Context, m *payout.Method, code string) error {
+	verifyErr := m.Verify(hashVerificationCode(m, code))
+	if verifyErr != nil && !errors.Is(verifyErr, payout.ErrVerificationCode) {
+		return verifyErr
+	}
+	if err := s.methods.Update(ctx, m); err != nil {
+		return fmt.Errorf("failed to update payout method %d: %w", m.ID(), err)
+	}
+	if verifyErr != nil {
+		s.logger.Warn("Wrong payout method verification code",
+			zap.Uint("method_id", m.ID()),
+			zap.Uint("agent_id", m.AgentID()),
+			zap.Int("attempts", m.VerificationAttempts()),
+		)
+		return verifyErr
+	}
+
+	s.logger.Info("Payout method verified", zap.Uint("method_id", m.ID()), zap.Uint("agent_id", m.AgentID()))
+	return nil
+}
+
+// SetDefault makes a verified method the one the agent's payouts go to.
+// Payouts are held for the cooldown.
+func (s *PayoutMethodService) SetDefault(ctx context.Context, m *payout.Method) error {
+	if m.IsDefault() {
+		return nil
+	}
+	if err := m.MakeDefault(); err != nil {
+		return err
+	}
+	if err := s.methods.SetDefault(ctx, m); err != nil {
+		return fmt.Errorf("failed to update payout method %d: %w", m.ID(), err)
+	}
+
+	s.logger.Info("Default payout method changed", zap.Uint("method_id", m.ID()), zap.Uint("agent_id", m.AgentID()))
+	s.publishChanged(ctx, m, PayoutMethodDefault)
+	return nil
+}
+
+// Remove removes a method. The default method can only be removed when it
+// is the agent's last.
+func (s *PayoutMethodService) Remove(ctx context.Context, m *payout.Method) error {
+	if m.IsDefault() {
+		methods, err := s.methods.ListByAgent(ctx, m.AgentID())
+		if err != nil {
+			return err
+		}
+		if len(methods) > 1 {
+			return payout.ErrDefaultMethod
+		}
+	}
+	if err := s.methods.Delete(ctx, m.ID()); err != nil {
+		return err
+	}
+
+	s.logger.Info("Payout method removed", zap.Uint("method_id", m.ID()), zap.Uint("agent_id", m.AgentID()))
+	s.publishChanged(ctx, m, PayoutMethodRemoved)
+	return nil
+}
+
+// Check returns an error wrapping payout.ErrPayoutOnHold if the agent has
+// no verified default method or it is in its cooldown
+func (s *PayoutMethodService) Check(ctx context.Context, agentID uint) error {
+	_, err := s.payable(ctx, agentID)
+	return err
+}
+
+// Payee returns the agent's default method as a payee, or an error
+// wrapping payout.ErrPayoutOnHold if payouts to it are held
+func (s *PayoutMethodService) Payee(ctx context.Context, agentID uint) (bankfile.Payee, error) {
+	m, err := s.payable(ctx, agentID)
+	if err != nil {
+		return bankfile.Payee{}, err
+	}
+	a, err := s.agents.GetByID(ctx, agentID)
+	if err != nil {
+		return bankfile.Payee{}, err
+	}
+	return bankfile.Payee{
+		AgentCode:     a.Code(),
+		Name:          a.Name(),
+		Email:         a.Email(),
+		AccountName:   m.AccountName(),
+		AccountNumber: m.AccountNumber(),
+		BankCode:      m.ProviderCode(),
+	}, nil
+}
+
+// payable returns the agent's default method if payouts to it can be sent
+func (s *PayoutMethodService) payable(ctx context.Context, agentID uint) (*payout.Method, error) {
+	methods, err := s.methods.ListByAgent(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range methods {
+		if m.IsDefault() {
+			if err := m.CheckPayable(s.policy.Cooldown, time.Now()); err != nil {
+				return nil, err
+			}
+			return m, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %w", payout.ErrPayoutOnHold, payout.ErrNoPayoutMethod)
+}
+
+// publishChanged tells the agent where they are paid has changed
+func (s *PayoutMethodService) publishChanged(ctx context.Context, m *payout.Method, change string) {
+	a, err := s.agents.GetByID(ctx, m.AgentID())
+	if err != nil {
+		s.logger.Error("Failed to publish payout method change", zap.Uint("method_id", m.ID()), zap.Error(err))
+		return
+	}
+
+	data, _ := json.Marshal(PayoutMethodChangedEvent{
+		AgentID:       a.ID(),
+		AgentCode:     a.Code(),
+		Email:         a.Email(),
+		MethodID:      m.ID(),
+		Change:        change,
+		Provider:      m.Provider(),
+		AccountNumber: m.MaskedAccountNumber(),
+		HeldUntil:     m.CooldownUntil(s.policy.Cooldown),
+		OccurredAt:    time.Now(),
+	})
+	if err := s.bus.Publish(ctx, SubjectPayoutMethodChanged, data); err != nil {
+		s.logger.Error("Failed to publish payout method change",
+			zap.Uint("method_id", m.ID()),
+			zap.String("change", change),
+			zap.Error(err),
+		)
+	}
+}
+
+// hashVerificationCode hashes a code for a method, so the stored hash
+// only verifies that method
+func hashVerificationCode(m *payout.Method, code string) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d:%s", m.ID(), code)))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,719 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/agent"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/commission"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/payout"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"go.uber.org/zap"
+)
+
+// HoldChecker decides whether an agent's payouts may be created and sent.
+// Holding returns an error wrapping payout.ErrPayoutOnHold, saying why,
+// while no payout may be created for them; Check does while their payouts
+// may not be sent.
+type HoldChecker interface {
+	Holding(ctx context.Context, agentID uint) error
+	Check(ctx context.Context, agentID uint) error
+}
+
+// WithholdingPolicy decides the tax withheld from an agent's payouts.
+// WithholdingRate returns the percentage withheld.
+type WithholdingPolicy interface {
+	WithholdingRate(ctx context.Context, agentID uint) (float64, error)
+}
+
+// ReservePolicy decides the reserve kept back from an agent's payouts.
+// ReserveTerms returns the percentage kept back and for how many days,
+// zeros if none is.
+type ReservePolicy interface {
+	ReserveTerms(ctx context.Context, agentID uint) (float64, int, error)
+}
+
+// PayoutService pays agents their approved commissions, one agent at a
+// time or in a payout run over every agent for a period. Outstanding
+// clawbacks are netted against each payout, tax is withheld from it and,
+// for agents with a reserve policy, a reserve is kept back from it until
+// it is released in a later payout. No payout is created for an agent
+// while they are on hold.
+type PayoutService struct {
+	payouts     repository.PayoutRepository
+	runs        repository.PayoutRunRepository
+	commissions repository.CommissionRepository
+	clawbacks   repository.ClawbackRepository
+	reserves    repository.PayoutReserveRepository
+	agents      repository.AgentReader
+	tx          repository.Transactor
+	fx          *FXService
+	holds       HoldChecker
+	tax         WithholdingPolicy
+	reserve     ReservePolicy
+	logger      *zap.Logger
+}
+
+// NewPayoutService creates a new payout service
+func NewPayoutService(
+	payouts repository.PayoutRepository,
+	runs repository.PayoutRunRepository,
+	commissions repository.CommissionRepository,
+	clawbacks repository.ClawbackRepository,
+	reserves repository.PayoutReserveRepository,
+	agents repository.AgentReader,
+	tx repository.Transactor,
+	fx *FXService,
+	holds HoldChecker,
+	tax WithholdingPolicy,
+	reserve ReservePolicy,
+	logger *zap.Logger,
+) *PayoutService {
+	return &PayoutService{
+		payouts:     payouts,
+		runs:        runs,
+		commissions: commissions,
+		clawbacks:   clawbacks,
+		reserves:    reserves,
+		agents:      agents,
+		tx:          tx,
+		fx:          fx,
+		holds:       holds,
+		tax:         tax,
+		reserve:     reserve,
+		logger:      logger,
+	}
+}
+
+// draft is a payout built but not yet saved, with the changes settling it
+// makes once it is
+type draft struct {
+	payout      *payout.Payout
+	netted      []*commission.Clawback // Clawbacks the payout nets
+	reserveDays int                    // How long its reserve is held
+	released    []*payout.Reserve      // Reserves the payout releases
+}
+
+// Create pays an agent all their approved commissions for a period, in
+// the payout currency the ledger holds them in, which must be the same for
+// all of them. Without convert, it returns payout.ErrConversionRequired if
+// any was converted from its order currency. It returns an error wrapping
+// payout.ErrPayoutOnHold while the agent is on hold.
+func (s *PayoutService) Create(ctx context.Context, agentID uint, period string, convert bool) (*payout.Payout, error) {
+	if err := s.holds.Holding(ctx, agentID); err != nil {
+		return nil, err
+	}
+	commissions, err := s.approved(ctx, agentID, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	d, err := s.build(ctx, agentID, period, commissions, convert)
+	if err != nil {
+		return nil, err
+	}
+
+	p := d.payout
+	err = s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.payouts.Create(ctx, p); err != nil {
+			return fmt.Errorf("failed to create payout: %w", err)
+		}
+		return s.settle(ctx, d)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Payout created",
+		zap.Uint("payout_id", p.ID()),
+		zap.Uint("agent_id", agentID),
+		zap.Float64("amount", p.Amount().Float64()),
+		zap.Float64("deductions", p.Deductions().Float64()),
+		zap.Float64("withheld", p.Withheld().Float64()),
+		zap.Float64("reserved", p.Reserved().Float64()),
+		zap.String("currency", p.Currency()),
+	)
+	return p, nil
+}
+
+// Process starts processing a pending payout, once it has been sent to the
+// bank. Payouts on hold stay pending.
+func (s *PayoutService) Process(ctx context.Context, p *payout.Payout) error {
+	if p.IsPending() {
+		if err := s.holds.Check(ctx, p.AgentID()); err != nil {
+			return err
+		}
+	}
+	if err := p.Process(); err != nil {
+		return err
+	}
+	return s.save(ctx, p, "Payout processing")
+}
+
+// Complete marks a processing payout as paid with the bank transaction
+// reference. Its commissions are paid.
+func (s *PayoutService) Complete(ctx context.Context, p *payout.Payout, transactionRef string) error {
+	if err := p.Complete(transactionRef); err != nil {
+		return err
+	}
+	return s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.save(ctx, p, "Payout completed"); err != nil {
+			return err
+		}
+		if _, err := s.commissions.UpdateStatus(ctx, p.CommissionIDs(), shared.CommissionInPayout, shared.CommissionPaid); err != nil {
+			return fmt.Errorf("failed to mark commissions of payout %d as paid: %w", p.ID(), err)
+		}
+		return nil
+	})
+}
+
+// Fail marks a processing payout as failed with the reason. Its
+// commissions stay in the payout until it is retried or cancelled.
+func (s *PayoutService) Fail(ctx context.Context, p *payout.Payout, reason string) error {
+	if err := p.Fail(reason); err != nil {
+		return err
+	}
+	return s.save(ctx, p, "Payout failed")
+}
+
+// Retry returns a failed payout to pending so it can be sent again.
+func (s *PayoutService) Retry(ctx context.Context, p *payout.Payout) error {
+	if err := p.Retry(); err != nil {
+		return err
+	}
+	return s.save(ctx, p, "Payout retried")
+}
+
+// save saves a payout after a status change
+func (s *PayoutService) save(ctx context.Context, p *payout.Payout, message string) error {
+	if err := s.payouts.Update(ctx, p); err != nil {
+		return fmt.Errorf("failed to update payout %d: %w", p.ID(), err)
+	}
+	s.logger.Info(message,
+		zap.Uint("payout_id", p.ID()),
+		zap.Uint("agent_id", p.AgentID()),
+		zap.String("status", p.Status().String()),
+	)
+	return nil
+}
+
+// Cancel cancels a pending or failed payout. Its commissions are approved
+// again and the clawbacks it netted are outstanding again, for the agent's
+// next payout. The reserve it kept back is cancelled, and the reserves it
+// released are held again, to be released once more.
+func (s *PayoutService) Cancel(ctx context.Context, p *payout.Payout) error {
+	if err := p.Cancel(); err != nil {
+		return err
+	}
+	err := s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.payouts.Update(ctx, p); err != nil {
+			return fmt.Errorf("failed to cancel payout %d: %w", p.ID(), err)
+		}
+		// Commissions reversed while in the payout stay reversed
+		if _, err := s.commissions.UpdateStatus(ctx, p.CommissionIDs(), shared.CommissionInPayout, shared.CommissionApproved); err != nil {
+			return fmt.Errorf("failed to return commissions of payout %d to approved: %w", p.ID(), err)
+		}
+
+		clawbacks, err := s.clawbacks.GetByPayoutID(ctx, p.ID())
+		if err != nil {
+			return fmt.Errorf("failed to load clawbacks recovered by payout %d: %w", p.ID(), err)
+		}
+		for _, cb := range clawbacks {
+			if err := cb.Reinstate(); err != nil {
+				return fmt.Errorf("failed to reinstate clawback %d: %w", cb.ID(), err)
+			}
+			if err := s.clawbacks.Update(ctx, cb); err != nil {
+				return fmt.Errorf("failed to save reinstated clawback %d: %w", cb.ID(), err)
+			}
+		}
+		return s.unwindReserves(ctx, p)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("Payout cancelled", zap.Uint("payout_id", p.ID()), zap.Uint("agent_id", p.AgentID()))
+	return nil
+}
+
+// PreviewRun works out a payout run for a period without saving it: who
+// would be paid and how much, and whose balances would be carried forward.
+func (s *PayoutService) PreviewRun(ctx context.Context, period string, minimum shared.Money, actor string) (*payout.Run, error) {
+	run, _, err := s.planRun(ctx, period, minimum, actor)
+	return run, err
+}
+
+// CommitRun runs payouts for a period: every active agent whose approved
+// commissions for sales up to the end of the period reach the minimum is
+// paid in their payout currency. A period can only have one committed run.
+func (s *PayoutService) CommitRun(ctx context.Context, period string, minimum shared.Money, actor string) (*payout.Run, error) {
+	_, committed, err := s.runs.List(ctx, repository.PayoutRunFilter{
+		Period: period,
+		Status: shared.PayoutRunCommitted.String(),
+		Limit:  1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check payout runs: %w", err)
+	}
+	if committed > 0 {
+		return nil, repository.ErrDuplicate
+	}
+
+	run, drafts, err := s.planRun(ctx, period, minimum, actor)
+	if err != nil {
+		return nil, err
+	}
+	if err := run.Commit(); err != nil {
+		return nil, err
+	}
+	err = s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.runs.Create(ctx, run); err != nil {
+			if errors.Is(err, repository.ErrDuplicate) {
+				return err
+			}
+			return fmt.Errorf("failed to save payout run: %w", err)
+		}
+		for _, p := range run.Payouts() {
+			if err := s.settle(ctx, drafts[p.AgentID()]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Payout run committed",
+		zap.Uint("run_id", run.ID()),
+		zap.String("period", period),
+		zap.Int("paid", run.PayoutCount()),
+		zap.Int("carried_forward", len(run.Lines())-run.PayoutCount()),
+		zap.String("actor", actor),
+	)
+	return run, nil
+}
+
+// CancelRun cancels a committed run and all its payouts, so the period can
+// be run again. None of its payouts may have started processing.
+func (s *PayoutService) CancelRun(ctx context.Context, run *payout.Run, actor string) error {
+	payouts := make([]*payout.Payout, 0, run.PayoutCount())
+	for _, id := range run.PayoutIDs() {
+		p, err := s.payouts.GetByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to load payout %d: %w", id, err)
+		}
+		if !p.IsPending() && p.Status() != shared.PayoutCancelled {
+			return payout.ErrRunInProgress
+		}
+		payouts = append(payouts, p)
+	}
+	if err := run.Cancel(actor); err != nil {
+		return err
+	}
+
+	err := s.tx.Transaction(ctx, func(ctx context.Context) error {
+		for _, p := range payouts {
+			if p.Status() == shared.PayoutCancelled {
+				continue
+			}
+			if err := s.Cancel(ctx, p); err != nil {
+				return err
+			}
+		}
+		if err := s.runs.Update(ctx, run); err != nil {
+			return fmt.Errorf("failed to cancel payout run: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("Payout run cancelled",
+		zap.Uint("run_id", run.ID()),
+		zap.String("period", run.Period()),
+		zap.String("actor", actor),
+	)
+	return nil
+}
+
+// planRun builds a draft run for a period, with the draft of each payout
+// by agent
+func (s *PayoutService) planRun(ctx context.Context, period string, minimum shared.Money, actor string) (*payout.Run, map[uint]*draft, error) {
+	run, err := payout.NewRun(payout.RunParams{
+		Period:    period,
+		Minimum:   minimum,
+		CreatedBy: actor,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	agents, _, err := s.agents.List(ctx, repository.AgentFilter{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load agents: %w", err)
+	}
+
+	drafts := make(map[uint]*draft)
+	for _, a := range agents {
+		d, err := s.planAgent(ctx, run, a)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to plan payout for agent %d: %w", a.ID(), err)
+		}
+		if d != nil {
+			drafts[a.ID()] = d
+		}
+	}
+	return run, drafts, nil
+}
+
+// planAgent adds an agent's payout to the run, or carries their balance
+// forward with the reason it is not paid. Agents with no approved
+// commissions for the period are left out.
+func (s *PayoutService) planAgent(ctx context.Context, run *payout.Run, a *agent.Agent) (*draft, error) {
+	commissions, err := s.approved(ctx, a.ID(), run.PeriodEnd())
+	if err != nil {
+		return nil, err
+	}
+	if len(commissions) == 0 {
+		return nil, nil
+	}
+
+	if !a.CanReceivePayout() {
+		return nil, run.CarryForward(a.ID(), balanceOf(a, commissions), len(commissions),
+			fmt.Sprintf("agent is %s", a.Status()))
+	}
+	if err := s.holds.Holding(ctx, a.ID()); err != nil {
+		if !errors.Is(err, payout.ErrPayoutOnHold) {
+			return nil, err
+		}
+		return nil, run.CarryForward(a.ID(), balanceOf(a, commissions), len(commissions), err.Error())
+	}
+
+	d, err := s.build(ctx, a.ID(), run.Period(), commissions, true)
+	switch {
+	case errors.Is(err, shared.ErrCurrencyMismatch):
+		return nil, run.CarryForward(a.ID(), balanceOf(a, commissions), len(commissions),
+			"commissions were converted to more than one payout currency")
+	case errors.Is(err, payout.ErrNegativePayout):
+		return nil, run.CarryForward(a.ID(), balanceOf(a, commissions), len(commissions),
+			"debit adjustments exceed the commissions")
+	case err != nil:
+		return nil, err
+	}
+
+	p := d.payout
+	amount, err := s.fx.Convert(ctx, p.Amount(), shared.DefaultCurrency, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if amount.Cmp(run.Minimum()) < 0 {
+		return nil, run.CarryForward(a.ID(), p.Amount(), len(commissions),
+			fmt.Sprintf("below the minimum payout of %s %.2f", run.Minimum().Currency(), run.Minimum().Float64()))
+	}
+	return d, run.AddPayout(p)
+}
+
+// approved returns an agent's approved commissions, for sales before the
+// given time unless it is zero
+func (s *PayoutService) approved(ctx context.Context, agentID uint, before time.Time) ([]*commission.Commission, error) {
+	commissions, _, err := s.commissions.GetByAgentID(ctx, agentID, repository.CommissionFilter{
+		Status: shared.CommissionApproved.String(),
+		Period: repository.Period{To: before},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch commissions: %w", err)
+	}
+	return commissions, nil
+}
+
+// build builds an agent's payout of the given commissions, netting their
+// outstanding clawbacks on paid commissions oldest first. Clawbacks that do
+// not fit in the payout, or are in another currency, are carried over to
+// the next one. Tax is withheld at the agent's current rate and the
+// reserve kept back by their reserve policy.
+func (s *PayoutService) build(ctx context.Context, agentID uint, period string, commissions []*commission.Commission, convert bool) (*draft, error) {
+	if len(commissions) == 0 {
+		return nil, payout.ErrNoCommissions
+	}
+
+	// Collect payout items, all in one currency. Payouts are paid in the
+	// payout currency the ledger holds the commissions in; without convert,
+	// none may have been converted to it.
+	items := make([]payout.PayoutItem, len(commissions))
+	var gross shared.Money
+	for i, c := range commissions {
+		amount := c.NetPayoutAmount()
+		if !convert && amount.Currency() != c.NetAmount().Currency() {
+			return nil, fmt.Errorf("%w: commission %d is in %s, paid in %s",
+				payout.ErrConversionRequired, c.ID(), c.NetAmount().Currency(), amount.Currency())
+		}
+		if !gross.SameCurrency(amount) {
+			return nil, shared.ErrCurrencyMismatch
+		}
+		items[i] = payout.NewPayoutItem(c.ID(), c.OrderID(), amount)
+		gross = gross.Add(amount)
+	}
+
+	deductions, netted, err := s.net(ctx, agentID, gross)
+	if err != nil {
+		return nil, err
+	}
+
+	rate, err := s.tax.WithholdingRate(ctx, agentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decide withholding: %w", err)
+	}
+	percent, days, err := s.reserve.ReserveTerms(ctx, agentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decide reserve: %w", err)
+	}
+
+	p, err := payout.NewPayout(payout.PayoutParams{
+		AgentID:         agentID,
+		Period:          period,
+		Items:           items,
+		Deductions:      deductions,
+		WithholdingRate: rate,
+		ReservePercent:  percent,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &draft{payout: p, netted: netted, reserveDays: days}, nil
+}
+
+// net picks the agent's outstanding clawbacks to net against a payout of
+// the given amount, oldest first, until the next one does not fit. It
+// returns their total with the clawbacks.
+func (s *PayoutService) net(ctx context.Context, agentID uint, gross shared.Money) (shared.Money, []*commission.Clawback, error) {
+	outstanding, err := s.clawbacks.GetOutstanding(ctx, agentID)
+	if err != nil {
+		return shared.Money{}, nil, fmt.Errorf("failed to fetch outstanding clawbacks: %w", err)
+	}
+	var deductions shared.Money
+	var netted []*commission.Clawback
+	for _, cb := range outstanding {
+		amount := cb.PayoutAmount()
+		if !gross.SameCurrency(amount) {
+			continue
+		}
+		next := deductions.Sub(amount)
+		if next.Cmp(gross) > 0 {
+			break
+		}
+		deductions = next
+		netted = append(netted, cb)
+	}
+	return deductions, netted, nil
+}
+
+// settle moves a saved payout's commissions into the payout, marks the
+// clawbacks it netted as recovered by it, holds the reserve it kept back
+// and marks the reserves it paid as released. It must run in the
+// transaction that saved the payout, and fails with repository.ErrConflict
+// if any of the commissions is no longer approved, or another payout
+// recovered one of the clawbacks or released one of the reserves first.
+func (s *PayoutService) settle(ctx context.Context, d *draft) error {
+	p := d.payout
+	ids := p.CommissionIDs()
+	moved, err := s.commissions.UpdateStatus(ctx, ids, shared.CommissionApproved, shared.CommissionInPayout)
+	if err != nil {
+		return fmt.Errorf("failed to move commissions into payout: %w", err)
+	}
+	if moved < len(ids) {
+		return fmt.Errorf("%w: %d of the payout's %d commissions are no longer approved",
+			repository.ErrConflict, len(ids)-moved, len(ids))
+	}
+
+	for _, cb := range d.netted {
+		if err := cb.Recover(p.ID()); err != nil {
+			return fmt.Errorf("failed to recover clawback %d: %w", cb.ID(), err)
+		}
+		if err := s.clawbacks.Update(ctx, cb); err != nil {
+			return fmt.Errorf("failed to mark clawback %d as recovered: %w", cb.ID(), err)
+		}
+	}
+
+	if p.Reserved().IsPositive() {
+		r, err := payout.NewReserve(p, d.reserveDays)
+		if err != nil {
+			return err
+		}
+		if err := s.reserves.Create(ctx, r); err != nil {
+			return fmt.Errorf("failed to hold payout reserve: %w", err)
+		}
+	}
+
+	for _, r := range d.released {
+		if err := r.Release(p.ID()); err != nil {
+			return fmt.Errorf("failed to release reserve %d: %w", r.ID(), err)
+		}
+		if err := s.reserves.Update(ctx, r); err != nil {
+			return fmt.Errorf("failed to mark reserve %d as released: %w", r.ID(), err)
+		}
+	}
+	return nil
+}
+
+// unwindReserves cancels the reserve a cancelled payout kept back and
+// holds the reserves it released again
+func (s *PayoutService) unwindReserves(ctx context.Context, p *payout.Payout) error {
+	if p.Reserved().IsPositive() {
+		r, err := s.reserves.GetByPayoutID(ctx, p.ID())
+		if err != nil {
+			return fmt.Errorf("failed to load payout reserve: %w", err)
+		}
+		if err := r.Cancel(); err != nil {
+			return err
+		}
+		if err := s.reserves.Update(ctx, r); err != nil {
+			return fmt.Errorf("failed to cancel payout reserve: %w", err)
+		}
+	}
+
+	if !p.IsRelease() {
+		return nil
+	}
+	released, err := s.reserves.GetByReleasePayoutID(ctx, p.ID())
+	if err != nil {
+		return fmt.Errorf("failed to load released reserves: %w", err)
+	}
+	for _, r := range released {
+		if err := r.Reinstate(); err != nil {
+			return fmt.Errorf("failed to reinstate reserve %d: %w", r.ID(), err)
+		}
+		if err := s.reserves.Update(ctx, r); err != nil {
+			return fmt.Errorf("failed to save reinstated reserve %d: %w", r.ID(), err)
+		}
+	}
+	return nil
+}
+
+// Run releases due reserves every interval until the context is done
+func (s *PayoutService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if _, err := s.ReleaseReserves(ctx, now); err != nil {
+				s.logger.Error("Reserve release failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// ReleaseReserves pays agents the reserves due at the given time whose
+// payouts have been paid, in one release payout per agent and currency.
+// The agent's outstanding clawbacks are netted against it first. Reserves
+// of agents on hold stay held until the hold ends. It returns the release
+// payouts created.
+func (s *PayoutService) ReleaseReserves(ctx context.Context, now time.Time) ([]*payout.Payout, error) {
+	due, err := s.reserves.GetDue(ctx, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load due reserves: %w", err)
+	}
+
+	type release struct {
+		agentID  uint
+		currency string
+	}
+	var order []release
+	groups := make(map[release][]*payout.Reserve)
+	for _, r := range due {
+		source, err := s.payouts.GetByID(ctx, r.PayoutID())
+		if err != nil {
+			s.logger.Error("Failed to load reserve payout", zap.Uint("reserve_id", r.ID()), zap.Error(err))
+			continue
+		}
+		if !source.IsCompleted() {
+			continue
+		}
+		key := release{r.AgentID(), r.Amount().Currency()}
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], r)
+	}
+
+	payouts := make([]*payout.Payout, 0, len(order))
+	for _, key := range order {
+		if err := s.holds.Holding(ctx, key.agentID); err != nil {
+			if !errors.Is(err, payout.ErrPayoutOnHold) {
+				s.logger.Error("Failed to check payout hold", zap.Uint("agent_id", key.agentID), zap.Error(err))
+			}
+			continue
+		}
+
+		d, err := s.buildRelease(ctx, key.agentID, groups[key], now)
+		if err != nil {
+			s.logger.Error("Failed to build reserve release",
+				zap.Uint("agent_id", key.agentID), zap.String("currency", key.currency), zap.Error(err))
+			continue
+		}
+		p := d.payout
+		err = s.tx.Transaction(ctx, func(ctx context.Context) error {
+			if err := s.payouts.Create(ctx, p); err != nil {
+				return err
+			}
+			return s.settle(ctx, d)
+		})
+		if err != nil {
+			return payouts, fmt.Errorf("failed to create release payout for agent %d: %w", key.agentID, err)
+		}
+		payouts = append(payouts, p)
+
+		s.logger.Info("Reserves released",
+			zap.Uint("payout_id", p.ID()),
+			zap.Uint("agent_id", key.agentID),
+			zap.Int("reserves", len(d.released)),
+			zap.Float64("released", p.Released().Float64()),
+			zap.Float64("deductions", p.Deductions().Float64()),
+			zap.String("currency", p.Currency()),
+		)
+	}
+	return payouts, nil
+}
+
+// buildRelease builds a payout releasing an agent's reserves, all in one
+// currency, with their outstanding clawbacks netted against it
+func (s *PayoutService) buildRelease(ctx context.Context, agentID uint, reserves []*payout.Reserve, now time.Time) (*draft, error) {
+	var total shared.Money
+	for _, r := range reserves {
+		total = total.Add(r.Amount())
+	}
+
+	deductions, netted, err := s.net(ctx, agentID, total)
+	if err != nil {
+		return nil, err
+	}
+	p, err := payout.NewPayout(payout.PayoutParams{
+		AgentID:    agentID,
+		Period:     payout.FormatPeriod(now),
+		Released:   total,
+		Deductions: deductions,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &draft{payout: p, netted: netted, released: reserves}, nil
+}
+
+// balanceOf totals the commissions carried forward for an agent in their
+// payout currency. Commissions converted to another currency are left out.
+func balanceOf(a *agent.Agent, commissions []*commission.Commission) shared.Money {
+	balance := shared.ZeroMoney(a.PayoutCurrency())
+	for _, c := range commissions {
+		if amount := c.NetPayoutAmount(); balance.SameCurrency(amount) {
+			balance = balance.Add(amount)
+		}
+	}
+	return balance
+}
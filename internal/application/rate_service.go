@@ -0,0 +1,279 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/commission"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"go.uber.org/zap"
+)
+
+// RateService schedules effective-dated commission rate changes: agent
+// base rates, team override rates and agent category rates. Changes can
+// take effect immediately or on a future date, but never in the past, so
+// the rates a commission was calculated at stay auditable.
+//
+// Agents and teams keep their current rate on the aggregate. It is updated
+// when a change takes effect, either straight away or by ApplyDue.
+type RateService struct {
+	rates      repository.RateChangeRepository
+	categories repository.CategoryCommissionRepository
+	agents     repository.AgentRepository
+	teams      repository.TeamRepository
+	logger     *zap.Logger
+}
+
+// NewRateService creates a new rate service
+func NewRateService(
+	rates repository.RateChangeRepository,
+	categories repository.CategoryCommissionRepository,
+	agents repository.AgentRepository,
+	teams repository.TeamRepository,
+	logger *zap.Logger,
+) *RateService {
+	return &RateService{
+		rates:      rates,
+		categories: categories,
+		agents:     agents,
+		teams:      teams,
+		logger:     logger,
+	}
+}
+
+// Schedule changes an agent's base rate or a team's override rate from
+// effectiveFrom on. A zero effectiveFrom takes effect immediately.
+func (s *RateService) Schedule(ctx context.Context, scope commission.RateScope, subjectID uint, rate float64, effectiveFrom time.Time) (*commission.RateChange, error) {
+	now := time.Now()
+	if !effectiveFrom.IsZero() && effectiveFrom.Before(now) {
+		return nil, commission.ErrRateChangeInPast
+	}
+
+	change, err := commission.NewRateChange(scope, subjectID, rate, effectiveFrom)
+	if err != nil {
+		return nil, err
+	}
+
+	current, since, err := s.currentRate(ctx, scope, subjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.rates.GetBySubject(ctx, scope, subjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load rate history: %w", err)
+	}
+	// Rates set before history was kept have been in effect since the
+	// agent or team was created
+	if len(existing) == 0 {
+		initial, err := commission.NewRateChange(scope, subjectID, current, since)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.rates.Create(ctx, initial); err != nil {
+			return nil, fmt.Errorf("failed to record initial rate: %w", err)
+		}
+	}
+
+	if err := s.rates.Create(ctx, change); err != nil {
+		return nil, fmt.Errorf("failed to schedule rate change: %w", err)
+	}
+
+	s.logger.Info("Commission rate change scheduled",
+		zap.Uint("rate_change_id", change.ID()),
+		zap.String("scope", string(scope)),
+		zap.Uint("subject_id", subjectID),
+		zap.Float64("rate", change.Rate()),
+		zap.Time("effective_from", change.EffectiveFrom()),
+	)
+
+	if !change.IsScheduled(time.Now()) {
+		if err := s.apply(ctx, change); err != nil {
+			return nil, err
+		}
+	}
+	return change, nil
+}
+
+// Cancel removes a rate change that has not taken effect yet
+func (s *RateService) Cancel(ctx context.Context, id uint) error {
+	change, err := s.rates.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !change.IsScheduled(time.Now()) {
+		return commission.ErrRateChangeInEffect
+	}
+	if err := s.rates.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to cancel rate change %d: %w", id, err)
+	}
+
+	s.logger.Info("Commission rate change cancelled",
+		zap.Uint("rate_change_id", id),
+		zap.String("scope", string(change.Scope())),
+		zap.Uint("subject_id", change.SubjectID()),
+	)
+	return nil
+}
+
+// ScheduleCategoryRates replaces an agent's category rates from
+// effectiveFrom on. A zero effectiveFrom takes effect immediately.
+func (s *RateService) ScheduleCategoryRates(ctx context.Context, agentID uint, commissions []domain.AgentCategoryCommission, effectiveFrom time.Time) error {
+	now := time.Now()
+	if effectiveFrom.IsZero() {
+		effectiveFrom = now
+	} else if effectiveFrom.Before(now) {
+		return commission.ErrRateChangeInPast
+	}
+	if _, err := s.agents.GetByID(ctx, agentID); err != nil {
+		return err
+	}
+
+	if err := s.categories.Schedule(ctx, agentID, commissions, effectiveFrom); err != nil {
+		return fmt.Errorf("failed to schedule category rates: %w", err)
+	}
+
+	s.logger.Info("Category commission rates scheduled",
+		zap.Uint("agent_id", agentID),
+		zap.Int("count", len(commissions)),
+		zap.Time("effective_from", effectiveFrom),
+	)
+	return nil
+}
+
+// CancelCategoryRate removes one of an agent's category rates that has
+// not taken effect yet
+func (s *RateService) CancelCategoryRate(ctx context.Context, agentID, id uint) error {
+	cc, err := s.categories.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if cc.AgentID != agentID {
+		return repository.ErrNotFound
+	}
+	if !cc.EffectiveFrom.After(time.Now()) {
+		return commission.ErrRateChangeInEffect
+	}
+	if err := s.categories.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to cancel category rate %d: %w", id, err)
+	}
+
+	s.logger.Info("Category commission rate cancelled",
+		zap.Uint("agent_id", agentID),
+		zap.Uint("category_commission_id", id),
+		zap.String("category_id", cc.CategoryID),
+	)
+	return nil
+}
+
+// Run applies rate changes as they fall due, every interval until the
+// context is done
+func (s *RateService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if _, err := s.ApplyDue(ctx, now); err != nil {
+				s.logger.Error("Applying rate changes failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// ApplyDue brings every agent's and team's current rate in line with the
+// change in effect at the given time, and returns how many were updated
+func (s *RateService) ApplyDue(ctx context.Context, now time.Time) (int, error) {
+	applied := 0
+	for _, scope := range []commission.RateScope{commission.RateScopeAgent, commission.RateScopeTeam} {
+		changes, err := s.rates.GetEffective(ctx, scope, now)
+		if err != nil {
+			return applied, fmt.Errorf("failed to load %s rates: %w", scope, err)
+		}
+		for _, change := range changes {
+			current, _, err := s.currentRate(ctx, scope, change.SubjectID())
+			if err != nil {
+				s.logger.Error("Failed to load current rate",
+					zap.String("scope", string(scope)),
+					zap.Uint("subject_id", change.SubjectID()),
+					zap.Error(err),
+				)
+				continue
+			}
+			if current == change.Rate() {
+				continue
+			}
+			if err := s.apply(ctx, change); err != nil {
+				s.logger.Error("Failed to apply rate change",
+					zap.Uint("rate_change_id", change.ID()),
+					zap.Error(err),
+				)
+				continue
+			}
+			applied++
+		}
+	}
+	return applied, nil
+}
+
+// currentRate returns an agent's or team's current rate and when it was
+// created
+func (s *RateService) currentRate(ctx context.Context, scope commission.RateScope, subjectID uint) (float64, time.Time, error) {
+	switch scope {
+	case commission.RateScopeAgent:
+		a, err := s.agents.GetByID(ctx, subjectID)
+		if err != nil {
+			return 0, time.Time{}, err
+		}
+		return a.CommissionRate().Value(), a.CreatedAt(), nil
+	case commission.RateScopeTeam:
+		t, err := s.teams.GetByID(ctx, subjectID)
+		if err != nil {
+			return 0, time.Time{}, err
+		}
+		return t.CommissionRate(), t.CreatedAt(), nil
+	default:
+		return 0, time.Time{}, commission.ErrInvalidRateChange
+	}
+}
+
+// apply sets an agent's or team's current rate to a change's rate
+func (s *RateService) apply(ctx context.Context, change *commission.RateChange) error {
+	switch change.Scope() {
+	case commission.RateScopeAgent:
+		a, err := s.agents.GetByID(ctx, change.SubjectID())
+		if err != nil {
+			return err
+		}
+		if err := a.SetCommissionRate(change.Rate()); err != nil {
+			return err
+		}
+		if err := s.agents.Update(ctx, a); err != nil {
+			return fmt.Errorf("failed to update agent %d rate: %w", a.ID(), err)
+		}
+	case commission.RateScopeTeam:
+		t, err := s.teams.GetByID(ctx, change.SubjectID())
+		if err != nil {
+			return err
+		}
+		t.SetCommissionRate(change.Rate())
+		if err := s.teams.Update(ctx, t); err != nil {
+			return fmt.Errorf("failed to update team %d rate: %w", t.ID(), err)
+		}
+	default:
+		return commission.ErrInvalidRateChange
+	}
+
+	s.logger.Info("Commission rate change applied",
+		zap.Uint("rate_change_id", change.ID()),
+		zap.String("scope", string(change.Scope())),
+		zap.Uint("subject_id", change.SubjectID()),
+		zap.Float64("rate", change.Rate()),
+	)
+	return nil
+}
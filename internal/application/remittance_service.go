@@ -0,0 +1,270 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/agent"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/commission"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/payout"
+	"github.com/Ecom-micro-template/service-agent/internal/infrastructure/pdf"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"go.uber.org/zap"
+)
+
+// Remittance advice formats
+const (
+	RemittanceFormatPDF = "pdf"
+	RemittanceFormatCSV = "csv"
+)
+
+// ErrUnknownRemittanceFormat is returned for formats remittance advices
+// are not written in
+var ErrUnknownRemittanceFormat = errors.New("format must be pdf or csv")
+
+// RemittanceAdvice is a payout's remittance advice, with the agent it is
+// for
+type RemittanceAdvice struct {
+	*payout.Remittance
+	Agent *agent.Agent
+}
+
+// RemittanceService builds the remittance advice agents are given as
+// proof of each payout, as a PDF or CSV document
+type RemittanceService struct {
+	payouts     repository.PayoutReader
+	commissions repository.CommissionReader
+	clawbacks   repository.ClawbackReader
+	agents      repository.AgentReader
+	logger      *zap.Logger
+}
+
+// NewRemittanceService creates a new remittance service
+func NewRemittanceService(
+	payouts repository.PayoutReader,
+	commissions repository.CommissionReader,
+	clawbacks repository.ClawbackReader,
+	agents repository.AgentReader,
+	logger *zap.Logger,
+) *RemittanceService {
+	return &RemittanceService{
+		payouts:     payouts,
+		commissions: commissions,
+		clawbacks:   clawbacks,
+		agents:      agents,
+		logger:      logger,
+	}
+}
+
+// Advice builds a payout's remittance advice
+func (s *RemittanceService) Advice(ctx context.Context, p *payout.Payout) (*RemittanceAdvice, error) {
+	a, err := s.agents.GetByID(ctx, p.AgentID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load agent %d: %w", p.AgentID(), err)
+	}
+
+	commissions := make([]*commission.Commission, 0, p.ItemCount())
+	for _, id := range p.CommissionIDs() {
+		c, err := s.commissions.GetByID(ctx, id)
+		if errors.Is(err, commission.ErrCommissionNotFound) {
+			// The item still shows on the advice, without its description
+			s.logger.Warn("Payout item commission not found",
+				zap.Uint("payout_id", p.ID()), zap.Uint("commission_id", id))
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load commission %d: %w", id, err)
+		}
+		commissions = append(commissions, c)
+	}
+
+	clawbacks, err := s.clawbacks.GetByPayoutID(ctx, p.ID())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load clawbacks: %w", err)
+	}
+	return &RemittanceAdvice{Remittance: payout.NewRemittance(p, commissions, clawbacks), Agent: a}, nil
+}
+
+// Document renders a remittance advice in a format, returning the file
+// name and content
+func (s *RemittanceService) Document(advice *RemittanceAdvice, format string) (string, []byte, error) {
+	name := fmt.Sprintf("%s-%s", advice.Payout.Reference(), advice.Agent.Code())
+	switch format {
+	case RemittanceFormatPDF:
+		return name + ".pdf", s.PDF(advice), nil
+	case RemittanceFormatCSV:
+		content, err := s.CSV(advice)
+		return name + ".csv", content, err
+	default:
+		return "", nil, ErrUnknownRemittanceFormat
+	}
+}
+
+// RunArchive writes the remittance advice of every payout in a run to a
+// zip archive, one document per payout in the format given
+func (s *RemittanceService) RunArchive(ctx context.Context, run *payout.Run, format string) ([]byte, error) {
+	if format != RemittanceFormatPDF && format != RemittanceFormatCSV {
+		return nil, ErrUnknownRemittanceFormat
+	}
+	ids := run.PayoutIDs()
+	if len(ids) == 0 {
+		return nil, payout.ErrRunNotCommitted
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, id := range ids {
+		p, err := s.payouts.GetByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load payout %d: %w", id, err)
+		}
+		advice, err := s.Advice(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		name, content, err := s.Document(advice, format)
+		if err != nil {
+			return nil, err
+		}
+		w, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: time.Now()})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(content); err != nil {
+			return nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Remittance advices archived",
+		zap.Uint("run_id", run.ID()),
+		zap.Int("payouts", len(ids)),
+		zap.String("format", format),
+	)
+	return buf.Bytes(), nil
+}
+
+// CSV writes a remittance advice as CSV: a row per commission,
+// adjustment and clawback, then the payout's totals. Every row carries the
+// payout reference and agent, so the advices of a run can be combined.
+//
+//	reference,agent_code,period,line,id,order_id,description,amount,currency
+func (s *RemittanceService) CSV(advice *RemittanceAdvice) ([]byte, error) {
+	p := advice.Payout
+	currency := p.Currency()
+	row := func(line, id, orderID, description, amount string) []string {
+		return []string{p.Reference(), advice.Agent.Code(), p.Period(), line, id, orderID, description, amount, currency}
+	}
+
+	records := [][]string{{"reference", "agent_code", "period", "line", "id", "order_id", "description", "amount", "currency"}}
+	for _, l := range advice.Lines() {
+		records = append(records, row(l.Kind, fmt.Sprintf("%d", l.ID), l.OrderID, l.Description, l.Amount.String()))
+	}
+	records = append(records,
+		row("total", "", "", "Commissions", advice.CommissionTotal.String()),
+		row("total", "", "", "Adjustments", advice.AdjustmentTotal.String()),
+		row("total", "", "", "Clawbacks", p.Deductions().Neg().String()),
+		row("total", "", "", "Taxable income", p.TaxableAmount().String()),
+		row("total", "", "", fmt.Sprintf("Tax withheld at %.2f%%", p.WithholdingRate()), p.Withheld().Neg().String()),
+	)
+	if p.Reserved().IsPositive() {
+		records = append(records, row("total", "", "", fmt.Sprintf("Reserve kept at %.2f%%", p.ReservePercent()), p.Reserved().Neg().String()))
+	}
+	if p.IsRelease() {
+		records = append(records, row("total", "", "", "Reserves released", p.Released().String()))
+	}
+	records = append(records, row("total", "", "", "Net paid", p.Amount().String()))
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll(records); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// PDF renders a remittance advice as a printable PDF
+func (s *RemittanceService) PDF(advice *RemittanceAdvice) []byte {
+	p := advice.Payout
+	title := "Payout Remittance Advice"
+	doc := pdf.New(title, fmt.Sprintf("%s  |  %s", advice.Agent.Code(), p.Reference()))
+
+	doc.Heading(title, 16)
+	doc.Text(pdf.Regular, 9, "The commissions paid to the agent in this payout, with the adjustments and clawbacks "+
+		"netted against them and the tax withheld. Amounts are in the payout currency.")
+	doc.Space(8)
+
+	paid := "Not yet paid"
+	if p.PaidAt() != nil {
+		paid = p.PaidAt().UTC().Format("2006-01-02")
+	}
+	details := [][2]string{
+		{"Agent", fmt.Sprintf("%s (%s)", advice.Agent.Name(), advice.Agent.Code())},
+		{"Email", advice.Agent.Email()},
+		{"Reference", p.Reference()},
+		{"Period", p.Period()},
+		{"Status", p.Status().Label()},
+		{"Paid", paid},
+	}
+	if p.TransactionRef() != "" {
+		details = append(details, [2]string{"Transaction", p.TransactionRef()})
+	}
+	doc.KeyValues(10, details)
+	doc.Space(10)
+
+	columns := []pdf.Column{
+		{Header: "ID", Width: 0.08},
+		{Header: "Order", Width: 0.38},
+		{Header: "Description", Width: 0.38},
+		{Header: "Amount", Width: 0.16, Align: pdf.Right},
+	}
+	section := func(heading string, lines []payout.RemittanceLine, total string) {
+		if len(lines) == 0 {
+			return
+		}
+		doc.Heading(heading, 12)
+		rows := make([][]string, 0, len(lines)+1)
+		for _, l := range lines {
+			orderID := l.OrderID
+			if orderID == "" {
+				orderID = "-"
+			}
+			rows = append(rows, []string{fmt.Sprintf("%d", l.ID), orderID, l.Description, formatAmount(l.Amount)})
+		}
+		rows = append(rows, []string{"=Total", "", "", total})
+		doc.Table(8, columns, rows)
+		doc.Space(10)
+	}
+	section("Commissions", advice.Commissions, formatAmount(advice.CommissionTotal))
+	section("Adjustments", advice.Adjustments, formatAmount(advice.AdjustmentTotal))
+	section("Clawbacks", advice.Clawbacks, formatAmount(p.Deductions().Neg()))
+
+	summary := [][]string{
+		{"Commissions", formatAmount(advice.CommissionTotal)},
+		{"Adjustments", formatAmount(advice.AdjustmentTotal)},
+		{"Clawbacks", formatAmount(p.Deductions().Neg())},
+		{"Taxable income", formatAmount(p.TaxableAmount())},
+		{fmt.Sprintf("Tax withheld at %.2f%%", p.WithholdingRate()), formatAmount(p.Withheld().Neg())},
+	}
+	if p.Reserved().IsPositive() {
+		summary = append(summary, []string{fmt.Sprintf("Reserve kept at %.2f%%", p.ReservePercent()), formatAmount(p.Reserved().Neg())})
+	}
+	if p.IsRelease() {
+		summary = append(summary, []string{"Reserves released", formatAmount(p.Released())})
+	}
+	summary = append(summary, []string{"=Net paid", formatAmount(p.Amount())})
+
+	doc.Heading("Summary", 12)
+	doc.Table(10, []pdf.Column{
+		{Header: "", Width: 0.70},
+		{Header: p.Currency(), Width: 0.30, Align: pdf.Right},
+	}, summary)
+	return doc.Bytes()
+}
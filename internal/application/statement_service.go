@@ -0,0 +1,218 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/payout"
+	"github.com/Ecom-micro-template/service-agent/internal/infrastructure/bankfile"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"go.uber.org/zap"
+)
+
+// StatementService reconciles payouts against the bank statements that
+// come back after the bank pays: each statement line completes or fails
+// the payout it pays, or waits for review when it matches none.
+type StatementService struct {
+	statements repository.PayoutStatementRepository
+	payouts    repository.PayoutReader
+	tx         repository.Transactor
+	service    *PayoutService
+	logger     *zap.Logger
+}
+
+// NewStatementService creates a new statement service
+func NewStatementService(
+	statements repository.PayoutStatementRepository,
+	payouts repository.PayoutReader,
+	tx repository.Transactor,
+	service *PayoutService,
+	logger *zap.Logger,
+) *StatementService {
+	return &StatementService{
+		statements: statements,
+		payouts:    payouts,
+		tx:         tx,
+		service:    service,
+		logger:     logger,
+	}
+}
+
+// Import reads a bank statement file and matches each line to a payout by
+// its reference. A line paying a processing payout its exact amount
+// completes it with the bank's transaction reference; a line paying the
+// wrong amount or currency fails it. Lines that match no processing payout
+// go to the review queue. A file can only be imported once; a payout
+// completed by the same transaction on another statement is recorded as a
+// duplicate.
+//
+// The statement, its lines and the payouts they complete or fail are saved
+// in one transaction, so a failed import changes no payout.
+func (s *StatementService) Import(ctx context.Context, format, fileName string, content []byte, actor string) (*payout.Statement, error) {
+	parser, err := bankfile.NewStatementParser(format)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(content)
+	checksum := hex.EncodeToString(sum[:])
+
+	entries, err := parser.Parse(content)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, payout.ErrEmptyStatement
+	}
+
+	statement, err := payout.NewStatement(payout.StatementParams{
+		Format:     parser.Format(),
+		FileName:   fileName,
+		Checksum:   checksum,
+		ImportedBy: actor,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if _, err := s.statements.GetByChecksum(ctx, checksum); err == nil {
+			return repository.ErrDuplicate
+		} else if !errors.Is(err, payout.ErrStatementNotFound) {
+			return err
+		}
+
+		for _, entry := range entries {
+			line := statement.AddLine(payout.StatementLineParams{
+				Reference:      entry.Reference,
+				TransactionRef: entry.TransactionRef,
+				Amount:         entry.Amount,
+				BookedAt:       entry.BookedAt,
+			})
+			if err := s.match(ctx, line, entry.Credit); err != nil {
+				return err
+			}
+		}
+
+		if err := s.statements.Create(ctx, statement); err != nil {
+			return fmt.Errorf("failed to save statement: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Bank statement imported",
+		zap.Uint("statement_id", statement.ID()),
+		zap.String("format", statement.Format()),
+		zap.Int("completed", statement.Count(payout.StatementLineCompleted)),
+		zap.Int("failed", statement.Count(payout.StatementLineFailed)),
+		zap.Int("unmatched", statement.Count(payout.StatementLineUnmatched)),
+		zap.String("actor", actor),
+	)
+	return statement, nil
+}
+
+// match completes or fails the payout a statement line pays, or refers
+// the line for review
+func (s *StatementService) match(ctx context.Context, line *payout.StatementLine, credit bool) error {
+	id, ok := payout.ParseReference(line.Reference())
+	if !ok {
+		line.Refer("no payout reference", nil)
+		return nil
+	}
+	if credit {
+		line.Refer("money received, such as a returned payment", &id)
+		return nil
+	}
+
+	p, err := s.payouts.GetByID(ctx, id)
+	if errors.Is(err, payout.ErrPayoutNotFound) {
+		line.Refer(fmt.Sprintf("payout %d not found", id), nil)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load payout %d: %w", id, err)
+	}
+
+	switch {
+	case p.IsCompleted() && p.TransactionRef() == line.TransactionRef():
+		line.Duplicate(id)
+		return nil
+	case !p.Status().IsProcessing():
+		line.Refer(fmt.Sprintf("payout is %s", p.Status()), &id)
+		return nil
+	}
+
+	if err := p.CheckPaid(line.Amount()); err != nil {
+		reason := err.Error()
+		if err := s.service.Fail(ctx, p, reason); err != nil {
+			return err
+		}
+		line.Fail(id, reason)
+		return nil
+	}
+
+	if err := s.service.Complete(ctx, p, line.TransactionRef()); err != nil {
+		return err
+	}
+	line.Complete(id)
+	return nil
+}
+
+// Resolve matches a line in the review queue to the processing payout it
+// paid, completing the payout. The line must pay the payout's exact
+// amount.
+func (s *StatementService) Resolve(ctx context.Context, line *payout.StatementLine, payoutID uint, actor string) error {
+	if !line.NeedsReview() {
+		return payout.ErrLineNotInReview
+	}
+
+	p, err := s.payouts.GetByID(ctx, payoutID)
+	if err != nil {
+		return err
+	}
+	if err := p.CheckPaid(line.Amount()); err != nil {
+		return err
+	}
+	if err := line.Resolve(payoutID, actor); err != nil {
+		return err
+	}
+
+	err = s.tx.Transaction(ctx, func(ctx context.Context) error {
+		if err := s.service.Complete(ctx, p, line.TransactionRef()); err != nil {
+			return err
+		}
+		if err := s.statements.UpdateLine(ctx, line); err != nil {
+			return fmt.Errorf("failed to update statement line %d: %w", line.ID(), err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("Statement line resolved",
+		zap.Uint("line_id", line.ID()),
+		zap.Uint("payout_id", payoutID),
+		zap.String("actor", actor),
+	)
+	return nil
+}
+
+// Dismiss removes a line from the review queue without matching it
+func (s *StatementService) Dismiss(ctx context.Context, line *payout.StatementLine, note, actor string) error {
+	if err := line.Dismiss(note, actor); err != nil {
+		return err
+	}
+	if err := s.statements.UpdateLine(ctx, line); err != nil {
+		return fmt.Errorf("failed to update statement line %d: %w", line.ID(), err)
+	}
+
+	s.logger.Info("Statement line dismissed", zap.Uint("line_id", line.ID()), zap.String("actor", actor))
+	return nil
+}
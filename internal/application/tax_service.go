@@ -0,0 +1,390 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/agent"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/tax"
+	"github.com/Ecom-micro-template/service-agent/internal/infrastructure/pdf"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"go.uber.org/zap"
+)
+
+// AnnualTaxStatement is an agent's statement of commission income for a
+// year, with the agent it is for
+type AnnualTaxStatement struct {
+	*tax.Statement
+	Agent *agent.Agent
+}
+
+// TaxService manages the tax withheld from agents' payouts: the
+// withholding rules, agents' tax profiles and the annual statements of
+// commission income they are given. It decides the withholding rate for
+// the payout service.
+type TaxService struct {
+	rules    repository.WithholdingRuleRepository
+	profiles repository.TaxProfileRepository
+	payouts  repository.PayoutReader
+	agents   repository.AgentReader
+	logger   *zap.Logger
+}
+
+// NewTaxService creates a new tax service
+func NewTaxService(
+	rules repository.WithholdingRuleRepository,
+	profiles repository.TaxProfileRepository,
+	payouts repository.PayoutReader,
+	agents repository.AgentReader,
+	logger *zap.Logger,
+) *TaxService {
+	return &TaxService{
+		rules:    rules,
+		profiles: profiles,
+		payouts:  payouts,
+		agents:   agents,
+		logger:   logger,
+	}
+}
+
+// CreateRule adds a withholding rule. It applies to payouts created from
+// now on.
+func (s *TaxService) CreateRule(ctx context.Context, params tax.RuleParams) (*tax.Rule, error) {
+	rule, err := tax.NewRule(params)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.rules.Create(ctx, rule); err != nil {
+		return nil, fmt.Errorf("failed to create withholding rule: %w", err)
+	}
+
+	s.logger.Info("Withholding rule created",
+		zap.Uint("rule_id", rule.ID()),
+		zap.String("applies_to", rule.Describe()),
+		zap.Float64("rate", rule.Rate()),
+	)
+	return rule, nil
+}
+
+// UpdateRule changes a withholding rule. Payouts already created keep the
+// rate they were created with.
+func (s *TaxService) UpdateRule(ctx context.Context, rule *tax.Rule, params tax.RuleParams) error {
+	if err := rule.Update(params.Name, params.Residency, params.TaxIDPresent, params.Rate, params.Active); err != nil {
+		return err
+	}
+	if err := s.rules.Update(ctx, rule); err != nil {
+		return fmt.Errorf("failed to update withholding rule %d: %w", rule.ID(), err)
+	}
+
+	s.logger.Info("Withholding rule updated",
+		zap.Uint("rule_id", rule.ID()),
+		zap.String("applies_to", rule.Describe()),
+		zap.Float64("rate", rule.Rate()),
+		zap.Bool("active", rule.IsActive()),
+	)
+	return nil
+}
+
+// DeleteRule removes a withholding rule
+func (s *TaxService) DeleteRule(ctx context.Context, id uint) error {
+	if err := s.rules.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.logger.Info("Withholding rule deleted", zap.Uint("rule_id", id))
+	return nil
+}
+
+// Profile returns an agent's tax profile, or the default profile if none
+// has been recorded
+func (s *TaxService) Profile(ctx context.Context, agentID uint) (*tax.Profile, error) {
+	if _, err := s.agents.GetByID(ctx, agentID); err != nil {
+		return nil, err
+	}
+	return s.profile(ctx, agentID)
+}
+
+// SaveProfile records an agent's tax details. Their payouts created from
+// now on are withheld by them.
+func (s *TaxService) SaveProfile(ctx context.Context, agentID uint, taxID, residency string, rateOverride *float64, actor string) (*tax.Profile, error) {
+	if _, err := s.agents.GetByID(ctx, agentID); err != nil {
+		return nil, err
+	}
+
+	p, err := s.profiles.GetByAgentID(ctx, agentID)
+	switch {
+	case errors.Is(err, tax.ErrProfileNotFound):
+		p, err = tax.NewProfile(tax.ProfileParams{
+			AgentID:      agentID,
+			TaxID:        taxID,
+			Residency:    residency,
+			RateOverride: rateOverride,
+			UpdatedBy:    actor,
+		})
+	case err == nil:
+		err = p.Update(taxID, residency, rateOverride, actor)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.profiles.Save(ctx, p); err != nil {
+		return nil, fmt.Errorf("failed to save tax profile: %w", err)
+	}
+
+	s.logger.Info("Tax profile saved",
+		zap.Uint("agent_id", agentID),
+		zap.String("residency", p.Residency().String()),
+		zap.Bool("tax_id", p.HasTaxID()),
+		zap.Bool("rate_override", p.RateOverride() != nil),
+		zap.String("actor", actor),
+	)
+	return p, nil
+}
+
+// Withholding returns the rate withheld from an agent's payouts, and why
+func (s *TaxService) Withholding(ctx context.Context, agentID uint) (tax.Withholding, error) {
+	p, err := s.profile(ctx, agentID)
+	if err != nil {
+		return tax.Withholding{}, err
+	}
+	rules, err := s.rules.List(ctx)
+	if err != nil {
+		return tax.Withholding{}, fmt.Errorf("failed to load withholding rules: %w", err)
+	}
+	return p.Withholding(rules), nil
+}
+
+// WithholdingRate returns the percentage withheld from an agent's payouts
+func (s *TaxService) WithholdingRate(ctx context.Context, agentID uint) (float64, error) {
+	w, err := s.Withholding(ctx, agentID)
+	if err != nil {
+		return 0, err
+	}
+	return w.Rate, nil
+}
+
+// Statement builds an agent's statement of commission income for a year
+func (s *TaxService) Statement(ctx context.Context, agentID uint, year int) (*AnnualTaxStatement, error) {
+	a, err := s.agents.GetByID(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+	p, err := s.profile(ctx, agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Agents are paid at most a few times a month, so their payouts are
+	// loaded whole and filtered to the year
+	payouts, _, err := s.payouts.GetByAgentID(ctx, agentID, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch payouts: %w", err)
+	}
+	return &AnnualTaxStatement{Statement: tax.NewStatement(year, p, payouts), Agent: a}, nil
+}
+
+// Statements builds the statements of every agent paid in a year, by
+// agent ID
+func (s *TaxService) Statements(ctx context.Context, year int) ([]*AnnualTaxStatement, error) {
+	from, to := tax.YearRange(year)
+	payouts, err := s.payouts.GetPaid(ctx, repository.Period{From: from, To: to})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch payouts: %w", err)
+	}
+
+	byAgent := make(map[uint][]int)
+	var agentIDs []uint
+	for i, p := range payouts {
+		if _, ok := byAgent[p.AgentID()]; !ok {
+			agentIDs = append(agentIDs, p.AgentID())
+		}
+		byAgent[p.AgentID()] = append(byAgent[p.AgentID()], i)
+	}
+	sort.Slice(agentIDs, func(i, j int) bool { return agentIDs[i] < agentIDs[j] })
+
+	statements := make([]*AnnualTaxStatement, 0, len(agentIDs))
+	for _, agentID := range agentIDs {
+		a, err := s.agents.GetByID(ctx, agentID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load agent %d: %w", agentID, err)
+		}
+		p, err := s.profile(ctx, agentID)
+		if err != nil {
+			return nil, err
+		}
+		paid := payouts[:0:0]
+		for _, i := range byAgent[agentID] {
+			paid = append(paid, payouts[i])
+		}
+		statements = append(statements, &AnnualTaxStatement{Statement: tax.NewStatement(year, p, paid), Agent: a})
+	}
+	return statements, nil
+}
+
+// profile returns an agent's tax profile, or the default profile
+func (s *TaxService) profile(ctx context.Context, agentID uint) (*tax.Profile, error) {
+	p, err := s.profiles.GetByAgentID(ctx, agentID)
+	if errors.Is(err, tax.ErrProfileNotFound) {
+		return tax.DefaultProfile(agentID), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load tax profile: %w", err)
+	}
+	return p, nil
+}
+
+// StatementPDF renders an agent's annual statement as a printable PDF
+func (s *TaxService) StatementPDF(st *AnnualTaxStatement) []byte {
+	title := fmt.Sprintf("Statement of Commission Income %d", st.Year)
+	doc := pdf.New(title, fmt.Sprintf("%s  |  %s", st.Agent.Code(), title))
+
+	doc.Heading(title, 16)
+	doc.Text(pdf.Regular, 9, "Commission payouts made to the agent in the calendar year, with the tax withheld from them. "+
+		"Payouts count in the year they were paid. Gross income is after clawbacks netted against the payouts.")
+	doc.Space(8)
+
+	taxID := st.TaxID
+	if taxID == "" {
+		taxID = "Not provided"
+	}
+	doc.KeyValues(10, [][2]string{
+		{"Agent", fmt.Sprintf("%s (%s)", st.Agent.Name(), st.Agent.Code())},
+		{"Email", st.Agent.Email()},
+		{"Tax ID", taxID},
+		{"Residency", st.Residency.Label()},
+		{"Year", fmt.Sprintf("%d", st.Year)},
+	})
+	doc.Space(10)
+
+	if st.IsEmpty() {
+		doc.Text(pdf.Regular, 10, fmt.Sprintf("No commission was paid to the agent in %d.", st.Year))
+		return doc.Bytes()
+	}
+
+	amountColumns := []pdf.Column{
+		{Header: "Currency", Width: 0.16},
+		{Header: "Payouts", Width: 0.12, Align: pdf.Right},
+		{Header: "Gross", Width: 0.24, Align: pdf.Right},
+		{Header: "Withheld", Width: 0.24, Align: pdf.Right},
+		{Header: "Net Paid", Width: 0.24, Align: pdf.Right},
+	}
+	doc.Heading("Summary", 12)
+	rows := make([][]string, len(st.Totals))
+	for i, t := range st.Totals {
+		rows[i] = []string{t.Currency, fmt.Sprintf("%d", t.Payouts), formatAmount(t.Gross), formatAmount(t.Withheld), formatAmount(t.Net)}
+	}
+	doc.Table(10, amountColumns, rows)
+	doc.Space(10)
+
+	doc.Heading("By Month", 12)
+	monthColumns := append([]pdf.Column{{Header: "Month", Width: 0.16}}, amountColumns...)
+	for i := range monthColumns[1:] {
+		monthColumns[i+1].Width *= 0.84
+	}
+	rows = make([][]string, len(st.Months))
+	for i, m := range st.Months {
+		rows[i] = []string{m.Month, m.Currency, fmt.Sprintf("%d", m.Payouts),
+			formatAmount(m.Gross), formatAmount(m.Withheld), formatAmount(m.Net)}
+	}
+	doc.Table(9, monthColumns, rows)
+	doc.Space(10)
+
+	doc.Heading("Payouts", 12)
+	rows = make([][]string, len(st.Payouts))
+	for i, p := range st.Payouts {
+		rows[i] = []string{p.Reference, p.PaidAt.UTC().Format("2006-01-02"), p.Period, p.Net.Currency(),
+			formatAmount(p.Gross), fmt.Sprintf("%.2f%%", p.WithholdingRate), formatAmount(p.Withheld), formatAmount(p.Net)}
+	}
+	doc.Table(8, []pdf.Column{
+		{Header: "Reference", Width: 0.19},
+		{Header: "Paid", Width: 0.13},
+		{Header: "Period", Width: 0.10},
+		{Header: "Currency", Width: 0.10},
+		{Header: "Gross", Width: 0.13, Align: pdf.Right},
+		{Header: "Rate", Width: 0.09, Align: pdf.Right},
+		{Header: "Withheld", Width: 0.13, Align: pdf.Right},
+		{Header: "Net Paid", Width: 0.13, Align: pdf.Right},
+	}, rows)
+	return doc.Bytes()
+}
+
+// StatementsPDF renders a year's statements as a printable summary with
+// one row per agent and currency
+func (s *TaxService) StatementsPDF(year int, statements []*AnnualTaxStatement) []byte {
+	title := fmt.Sprintf("Commission Income and Tax Withheld %d", year)
+	doc := pdf.New(title, title)
+
+	doc.Heading(title, 16)
+	doc.Text(pdf.Regular, 9, fmt.Sprintf("Every agent paid commission in %d, with the tax withheld from their payouts.", year))
+	doc.Space(8)
+
+	var rows [][]string
+	totals := make(map[string]*tax.Amounts)
+	var currencies []string
+	for _, st := range statements {
+		taxID := st.TaxID
+		if taxID == "" {
+			taxID = "-"
+		}
+		for _, t := range st.Totals {
+			rows = append(rows, []string{st.Agent.Code(), st.Agent.Name(), taxID, t.Currency,
+				formatAmount(t.Gross), formatAmount(t.Withheld), formatAmount(t.Net)})
+
+			total, ok := totals[t.Currency]
+			if !ok {
+				zero := shared.ZeroMoney(t.Currency)
+				total = &tax.Amounts{Currency: t.Currency, Gross: zero, Withheld: zero, Net: zero}
+				totals[t.Currency] = total
+				currencies = append(currencies, t.Currency)
+			}
+			total.Gross = total.Gross.Add(t.Gross)
+			total.Withheld = total.Withheld.Add(t.Withheld)
+			total.Net = total.Net.Add(t.Net)
+		}
+	}
+	sort.Strings(currencies)
+	for _, currency := range currencies {
+		t := totals[currency]
+		rows = append(rows, []string{"=Total", "", "", currency, formatAmount(t.Gross), formatAmount(t.Withheld), formatAmount(t.Net)})
+	}
+	if len(rows) == 0 {
+		doc.Text(pdf.Regular, 10, fmt.Sprintf("No commission was paid in %d.", year))
+		return doc.Bytes()
+	}
+
+	doc.Table(8, []pdf.Column{
+		{Header: "Agent", Width: 0.12},
+		{Header: "Name", Width: 0.22},
+		{Header: "Tax ID", Width: 0.16},
+		{Header: "Currency", Width: 0.10},
+		{Header: "Gross", Width: 0.14, Align: pdf.Right},
+		{Header: "Withheld", Width: 0.12, Align: pdf.Right},
+		{Header: "Net Paid", Width: 0.14, Align: pdf.Right},
+	}, rows)
+	return doc.Bytes()
+}
+
+// formatAmount formats an amount for print with thousands separators,
+// e.g. "12,345.60"
+func formatAmount(m shared.Money) string {
+	s := m.String()
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	whole, fraction, hasFraction := strings.Cut(s, ".")
+	var b strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(digit)
+	}
+	if hasFraction {
+		return sign + b.String() + "." + fraction
+	}
+	return sign + b.String()
+}
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/agent"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/infrastructure/messaging"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"go.uber.org/zap"
+)
+
+// Agent tier subjects published by the tier evaluator
+const (
+	SubjectAgentPromoted = "agent.promoted"
+	SubjectAgentDemoted  = "agent.demoted"
+)
+
+// TierChangedEvent is the payload of agent.promoted and agent.demoted
+type TierChangedEvent struct {
+	AgentID      uint      `json:"agent_id"`
+	AgentCode    string    `json:"agent_code"`
+	PreviousTier string    `json:"previous_tier"`
+	NewTier      string    `json:"new_tier"`
+	EvaluationID uint      `json:"evaluation_id"`
+	OccurredAt   time.Time `json:"occurred_at"`
+}
+
+// TierEvaluator periodically re-evaluates every active agent's tier from
+// their rolling sales, order count and active customers, promoting or
+// demoting them to the highest tier they qualify for. Each evaluation is
+// recorded with its reasons, whether or not the tier changed.
+type TierEvaluator struct {
+	agents      repository.AgentRepository
+	commissions repository.CommissionReader
+	customers   repository.CustomerReader
+	evaluations repository.TierEvaluationWriter
+	bus         messaging.Bus
+	policy      agent.TierPolicy
+	months      int
+	logger      *zap.Logger
+}
+
+// NewTierEvaluator creates a new tier evaluator. Agents are evaluated on
+// their results over the last given number of months.
+func NewTierEvaluator(
+	agents repository.AgentRepository,
+	commissions repository.CommissionReader,
+	customers repository.CustomerReader,
+	evaluations repository.TierEvaluationWriter,
+	bus messaging.Bus,
+	policy agent.TierPolicy,
+	months int,
+	logger *zap.Logger,
+) *TierEvaluator {
+	if months <= 0 {
+		months = 3
+	}
+	return &TierEvaluator{
+		agents:      agents,
+		commissions: commissions,
+		customers:   customers,
+		evaluations: evaluations,
+		bus:         bus,
+		policy:      policy,
+		months:      months,
+		logger:      logger,
+	}
+}
+
+// Run evaluates all agents every interval until the context is done
+func (e *TierEvaluator) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if _, err := e.EvaluateAll(ctx, now); err != nil {
+				e.logger.Error("Tier evaluation failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+// EvaluateAll evaluates every active agent and returns the evaluations
+func (e *TierEvaluator) EvaluateAll(ctx context.Context, now time.Time) ([]*agent.TierEvaluation, error) {
+	agents, _, err := e.agents.List(ctx, repository.AgentFilter{Status: shared.AgentStatusActive.String()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agents: %w", err)
+	}
+
+	evaluations := make([]*agent.TierEvaluation, 0, len(agents))
+	for _, a := range agents {
+		evaluation, err := e.Evaluate(ctx, a, now)
+		if err != nil {
+			e.logger.Error("Failed to evaluate agent tier", zap.Uint("agent_id", a.ID()), zap.Error(err))
+			continue
+		}
+		evaluations = append(evaluations, evaluation)
+	}
+
+	e.logger.Info("Tier evaluation completed",
+		zap.Int("agents", len(agents)),
+		zap.Int("evaluated", len(evaluations)),
+	)
+	return evaluations, nil
+}
+
+// Evaluate moves an agent to the tier their results over the window
+// qualify for, and records the evaluation
+func (e *TierEvaluator) Evaluate(ctx context.Context, a *agent.Agent, now time.Time) (*agent.TierEvaluation, error) {
+	period := repository.Period{From: now.AddDate(0, -e.months, 0), To: now}
+
+	sales, err := e.commissions.GetSalesSummary(ctx, a.ID(), period)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load sales: %w", err)
+	}
+	activeCustomers, err := e.customers.CountActive(ctx, a.ID(), period.From)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count active customers: %w", err)
+	}
+
+	metrics := agent.TierMetrics{
+		Sales:           sales.Sales,
+		Orders:          sales.Orders,
+		ActiveCustomers: activeCustomers,
+	}
+	tier, reasons := e.policy.Qualify(metrics)
+
+	previousTier := a.Tier()
+	if err := a.ChangeTier(tier); err != nil {
+		return nil, err
+	}
+	if tier != previousTier {
+		if err := e.agents.Update(ctx, a); err != nil {
+			return nil, fmt.Errorf("failed to update agent tier: %w", err)
+		}
+	}
+
+	evaluation := agent.NewTierEvaluation(a.ID(), previousTier, tier, metrics, period.From, period.To, reasons)
+	if err := e.evaluations.Create(ctx, evaluation); err != nil {
+		return nil, fmt.Errorf("failed to record tier evaluation: %w", err)
+	}
+
+	if tier != previousTier {
+		e.logger.Info("Agent tier changed",
+			zap.Uint("agent_id", a.ID()),
+			zap.String("previous_tier", previousTier.String()),
+			zap.String("new_tier", tier.String()),
+			zap.Strings("reasons", reasons),
+		)
+	}
+	e.publish(ctx, a, evaluation)
+	return evaluation, nil
+}
+
+// publish sends the agent's promoted and demoted events, one per tier crossed
+func (e *TierEvaluator) publish(ctx context.Context, a *agent.Agent, evaluation *agent.TierEvaluation) {
+	previousTier := evaluation.PreviousTier().String()
+	for _, event := range a.Events() {
+		var subject, newTier string
+		switch ev := event.(type) {
+		case agent.AgentPromotedEvent:
+			subject, newTier = SubjectAgentPromoted, ev.NewTier
+		case agent.AgentDemotedEvent:
+			subject, newTier = SubjectAgentDemoted, ev.NewTier
+		default:
+			continue
+		}
+
+		data, _ := json.Marshal(TierChangedEvent{
+			AgentID:      a.ID(),
+			AgentCode:    a.Code(),
+			PreviousTier: previousTier,
+			NewTier:      newTier,
+			EvaluationID: evaluation.ID(),
+			OccurredAt:   event.OccurredAt(),
+		})
+		if err := e.bus.Publish(ctx, subject, data); err != nil {
+			e.logger.Error("Failed to publish tier event",
+				zap.String("subject", subject),
+				zap.Uint("agent_id", a.ID()),
+				zap.Error(err),
+			)
+		}
+		previousTier = newTier
+	}
+}
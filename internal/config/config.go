@@ -4,11 +4,17 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
+	// Storage selects the repository backend: "postgres" (default) or
+	// "memory" for running without a database against seeded fixtures.
+	StorageDriver string
+
 	// Database
 	DatabaseHost     string
 	DatabasePort     int
@@ -27,6 +33,75 @@ type Config struct {
 
 	// Commission
 	DefaultCommissionRate float64
+
+	// Hierarchy: DownlineRates are the commission rates paid to upline
+	// sponsors by level, starting with the seller's direct sponsor. None
+	// are paid unless DOWNLINE_RATES is set.
+	DownlineRates     []float64
+	HierarchyMaxDepth int
+
+	// Tiers: agents are re-evaluated every TierEvaluationInterval against
+	// their results over the last TierEvaluationMonths months. Thresholds
+	// are keyed by tier; bronze needs none.
+	TierEvaluationInterval time.Duration
+	TierEvaluationMonths   int
+	TierThresholds         map[string]TierThreshold
+
+	// Rates: scheduled rate changes are applied to agents' and teams'
+	// current rates every RateScheduleInterval once they fall due.
+	RateScheduleInterval time.Duration
+
+	// Auto-approval: pending commissions are checked every
+	// AutoApprovalInterval and approved by the first rule they meet. No
+	// rules turns auto-approval off.
+	AutoApprovalInterval time.Duration
+	AutoApprovalRules    []AutoApprovalRule
+
+	// Payouts: a payout run only pays agents whose approved balance is at
+	// least PayoutMinimumAmount, in the default currency. Smaller balances
+	// are carried forward to the next run.
+	PayoutMinimumAmount float64
+
+	// Reserves: the share of risky agents' payouts kept back by their
+	// reserve policies is checked every PayoutReserveReleaseInterval and
+	// paid out once due.
+	PayoutReserveReleaseInterval time.Duration
+
+	// Bank: the account payouts are paid from, written to bank payment
+	// files. The account is an IBAN or a local account number.
+	BankDebtorName    string
+	BankDebtorAccount string
+	BankDebtorBIC     string
+
+	// Payout methods: account numbers, and agents' tax IDs, are encrypted
+	// at rest with PayoutMethodEncryptionKey (32 bytes, base64; required
+	// with postgres).
+	// Verification codes expire after PayoutMethodCodeTTL, and payouts are
+	// held for PayoutMethodCooldown after a method changes.
+	PayoutMethodEncryptionKey string
+	PayoutMethodCodeTTL       time.Duration
+	PayoutMethodCooldown      time.Duration
+
+	// Payout provider: "none" (default) or "simulated", which gives every
+	// transfer PayoutProviderOutcome ("succeed", "fail" or "timeout")
+	// after PayoutProviderDelay, failing each submission transiently
+	// PayoutProviderTransientFailures times first. Requests that fail transiently are tried
+	// PayoutProviderAttempts times, each limited to PayoutProviderTimeout,
+	// backing off from PayoutProviderBackoff. Callbacks must be signed
+	// with PayoutProviderSecret.
+	PayoutProvider                  string
+	PayoutProviderOutcome           string
+	PayoutProviderDelay             time.Duration
+	PayoutProviderTransientFailures int
+	PayoutProviderAttempts          int
+	PayoutProviderTimeout           time.Duration
+	PayoutProviderBackoff           time.Duration
+	PayoutProviderSecret            string
+
+	// Messaging selects the order event bus: "inprocess" (default) or
+	// "nats" to consume order events published by service-order.
+	MessageBus string
+	NATSURL    string
 }
 
 func Load() (*Config, error) {
@@ -43,6 +118,7 @@ func Load() (*Config, error) {
 	}
 
 	cfg := &Config{
+		StorageDriver:         getEnv("STORAGE_DRIVER", "postgres"),
 		DatabaseHost:          getEnv("DB_HOST", "localhost"),
 		DatabasePort:          getEnvAsInt("DB_PORT", 5432),
 		DatabaseUser:          getEnv("DB_USER", "postgres"),
@@ -54,11 +130,69 @@ func Load() (*Config, error) {
 		LogLevel:              getEnv("LOG_LEVEL", "info"),
 		Environment:           environment,
 		DefaultCommissionRate: getEnvAsFloat("DEFAULT_COMMISSION_RATE", 10.0),
+		HierarchyMaxDepth:     getEnvAsInt("HIERARCHY_MAX_DEPTH", 10),
+		MessageBus:            getEnv("MESSAGE_BUS", "inprocess"),
+		NATSURL:               getEnv("NATS_URL", "nats://localhost:4222"),
 	}
 
+	downlineRates, err := getEnvAsFloatList("DOWNLINE_RATES")
+	if err != nil {
+		return nil, err
+	}
+	for level, rate := range downlineRates {
+		if rate < 0 || rate > 100 {
+			return nil, fmt.Errorf("DOWNLINE_RATES: level %d rate %v is not between 0 and 100", level+1, rate)
+		}
+	}
+	cfg.DownlineRates = downlineRates
+
+	cfg.TierEvaluationInterval = getEnvAsDuration("TIER_EVALUATION_INTERVAL", 24*time.Hour)
+	cfg.TierEvaluationMonths = getEnvAsInt("TIER_EVALUATION_MONTHS", 3)
+	cfg.TierThresholds = map[string]TierThreshold{
+		"silver":   getTierThreshold("SILVER", TierThreshold{MinSales: 5000, MinOrders: 10, MinActiveCustomers: 5}),
+		"gold":     getTierThreshold("GOLD", TierThreshold{MinSales: 15000, MinOrders: 30, MinActiveCustomers: 15}),
+		"platinum": getTierThreshold("PLATINUM", TierThreshold{MinSales: 40000, MinOrders: 80, MinActiveCustomers: 40}),
+	}
+	cfg.RateScheduleInterval = getEnvAsDuration("RATE_SCHEDULE_INTERVAL", time.Minute)
+	cfg.AutoApprovalInterval = getEnvAsDuration("AUTO_APPROVAL_INTERVAL", time.Hour)
+	cfg.AutoApprovalRules = getAutoApprovalRules("AUTO_APPROVAL_RULES", nil)
+	cfg.PayoutMinimumAmount = getEnvAsFloat("PAYOUT_MINIMUM_AMOUNT", 50)
+	cfg.PayoutReserveReleaseInterval = getEnvAsDuration("PAYOUT_RESERVE_RELEASE_INTERVAL", time.Hour)
+	cfg.BankDebtorName = getEnv("BANK_DEBTOR_NAME", "")
+	cfg.BankDebtorAccount = getEnv("BANK_DEBTOR_ACCOUNT", "")
+	cfg.BankDebtorBIC = getEnv("BANK_DEBTOR_BIC", "")
+	cfg.PayoutMethodEncryptionKey = getEnv("PAYOUT_METHOD_ENCRYPTION_KEY", "")
+	cfg.PayoutMethodCodeTTL = getEnvAsDuration("PAYOUT_METHOD_CODE_TTL", 15*time.Minute)
+	cfg.PayoutMethodCooldown = getEnvAsDuration("PAYOUT_METHOD_COOLDOWN", 72*time.Hour)
+	cfg.PayoutProvider = getEnv("PAYOUT_PROVIDER", "none")
+	cfg.PayoutProviderOutcome = getEnv("PAYOUT_PROVIDER_OUTCOME", "succeed")
+	cfg.PayoutProviderDelay = getEnvAsDuration("PAYOUT_PROVIDER_DELAY", 5*time.Second)
+	cfg.PayoutProviderTransientFailures = getEnvAsInt("PAYOUT_PROVIDER_TRANSIENT_FAILURES", 0)
+	cfg.PayoutProviderAttempts = getEnvAsInt("PAYOUT_PROVIDER_ATTEMPTS", 3)
+	cfg.PayoutProviderTimeout = getEnvAsDuration("PAYOUT_PROVIDER_TIMEOUT", 10*time.Second)
+	cfg.PayoutProviderBackoff = getEnvAsDuration("PAYOUT_PROVIDER_BACKOFF", time.Second)
+	cfg.PayoutProviderSecret = getEnv("PAYOUT_PROVIDER_SECRET", "")
+
 	return cfg, nil
 }
 
+// TierThreshold is the minimum rolling sales, order count and active
+// customer count for a tier
+type TierThreshold struct {
+	MinSales           float64
+	MinOrders          int
+	MinActiveCustomers int
+}
+
+// AutoApprovalRule approves pending commissions of up to MaxAmount in the
+// default currency once HoldingPeriod has passed since the sale. A zero
+// MaxAmount means no limit.
+type AutoApprovalRule struct {
+	Name          string
+	HoldingPeriod time.Duration
+	MaxAmount     float64
+}
+
 func (c *Config) GetDatabaseURL() string {
 	return fmt.Sprintf(
 		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
@@ -95,3 +229,75 @@ func getEnvAsFloat(key string, defaultValue float64) float64 {
 	}
 	return defaultValue
 }
+
+// getEnvAsFloatList parses a comma-separated list of numbers, such as
+// "3,1". An empty value or "none" is an empty list; any invalid entry is
+// an error, so a typo fails startup instead of being replaced.
+func getEnvAsFloatList(key string) ([]float64, error) {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" || value == "none" {
+		return nil, nil
+	}
+	parts := strings.Split(value, ",")
+	values := make([]float64, len(parts))
+	for i, part := range parts {
+		floatValue, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: entry %d %q is not a number", key, i+1, strings.TrimSpace(part))
+		}
+		values[i] = floatValue
+	}
+	return values, nil
+}
+
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if duration, err := time.ParseDuration(value); err == nil {
+			return duration
+		}
+	}
+	return defaultValue
+}
+
+// getTierThreshold reads TIER_<TIER>_MIN_SALES, TIER_<TIER>_MIN_ORDERS and
+// TIER_<TIER>_MIN_CUSTOMERS, falling back to the defaults
+func getTierThreshold(tier string, defaults TierThreshold) TierThreshold {
+	prefix := "TIER_" + tier + "_MIN_"
+	return TierThreshold{
+		MinSales:           getEnvAsFloat(prefix+"SALES", defaults.MinSales),
+		MinOrders:          getEnvAsInt(prefix+"ORDERS", defaults.MinOrders),
+		MinActiveCustomers: getEnvAsInt(prefix+"CUSTOMERS", defaults.MinActiveCustomers),
+	}
+}
+
+// getAutoApprovalRules parses a comma-separated list of
+// name:holding_period:max_amount rules, such as
+// "small:72h:100,standard:336h:10000". "none" turns auto-approval off; an
+// empty value or any invalid entry yields the default.
+func getAutoApprovalRules(key string, defaultValue []AutoApprovalRule) []AutoApprovalRule {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	if value == "none" {
+		return nil
+	}
+	parts := strings.Split(value, ",")
+	rules := make([]AutoApprovalRule, len(parts))
+	for i, part := range parts {
+		fields := strings.Split(strings.TrimSpace(part), ":")
+		if len(fields) != 3 || fields[0] == "" {
+			return defaultValue
+		}
+		holdingPeriod, err := time.ParseDuration(fields[1])
+		if err != nil {
+			return defaultValue
+		}
+		maxAmount, err := strconv.ParseFloat(fields[2], 64)
+		if err != nil {
+			return defaultValue
+		}
+		rules[i] = AutoApprovalRule{Name: fields[0], HoldingPeriod: holdingPeriod, MaxAmount: maxAmount}
+	}
+	return rules
+}
@@ -28,6 +28,7 @@ func InitDatabase(cfg *config.Config) error {
 	DB, err = gorm.Open(postgres.Open(cfg.GetDatabaseURL()), &gorm.Config{
 		Logger:                                   gormLogger,
 		DisableForeignKeyConstraintWhenMigrating: true,
+		TranslateError:                           true,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to connect to database: %w", err)
@@ -22,9 +22,11 @@ type Agent struct {
 	Email          string    `gorm:"uniqueIndex;size:255;not null" json:"email"`
 	Phone          string    `gorm:"size:50" json:"phone"`
 	CommissionRate float64   `gorm:"type:decimal(5,2);default:10.0" json:"commission_rate"`
+	Tier           string    `gorm:"size:20;default:'bronze'" json:"tier"`
 	Status         string    `gorm:"size:20;default:'active'" json:"status"`
-	TotalEarned    float64   `gorm:"type:decimal(10,2);default:0" json:"total_earned"`
+	TotalEarned    float64   `gorm:"-" json:"total_earned"`
 	TeamID         *uint     `gorm:"index" json:"team_id,omitempty"`
+	ParentID       *uint     `gorm:"index" json:"parent_id,omitempty"`
 	CreatedAt      time.Time `json:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at"`
 
@@ -44,6 +46,9 @@ func (a *Agent) BeforeCreate(tx *gorm.DB) error {
 	if a.Status == "" {
 		a.Status = "active"
 	}
+	if a.Tier == "" {
+		a.Tier = "bronze"
+	}
 	if a.CommissionRate == 0 {
 		a.CommissionRate = 10.0
 	}
@@ -55,19 +60,38 @@ func (Agent) TableName() string {
 }
 
 // Dashboard represents agent dashboard statistics
+//
+// Commission amounts are in Currency, the agent's payout currency.
+// Commissions paid in a former payout currency are only counted in
+// CommissionByCurrency, which has the totals in every currency.
 type Dashboard struct {
-	TotalOrders         int64               `json:"total_orders"`
-	TotalSales          float64             `json:"total_sales"`
-	TotalCommission     float64             `json:"total_commission"`
-	PendingCommission   float64             `json:"pending_commission"`
-	ApprovedCommission  float64             `json:"approved_commission"`
-	PaidCommission      float64             `json:"paid_commission"`
-	TotalCustomers      int64               `json:"total_customers"`
-	MonthlyOrders       int64               `json:"monthly_orders"`
-	MonthlySales        float64             `json:"monthly_sales"`
-	MonthlyCommission   float64             `json:"monthly_commission"`
-	AverageOrderValue   float64             `json:"average_order_value"`
-	CommissionBreakdown CommissionBreakdown `json:"commission_breakdown"`
+	Currency             string              `json:"currency"`
+	TotalOrders          int64               `json:"total_orders"`
+	TotalSales           float64             `json:"total_sales"`
+	TotalCommission      float64             `json:"total_commission"`
+	PendingCommission    float64             `json:"pending_commission"`
+	ApprovedCommission   float64             `json:"approved_commission"`
+	PaidCommission       float64             `json:"paid_commission"`
+	TotalCustomers       int64               `json:"total_customers"`
+	MonthlyOrders        int64               `json:"monthly_orders"`
+	MonthlySales         float64             `json:"monthly_sales"`
+	MonthlyCommission    float64             `json:"monthly_commission"`
+	AverageOrderValue    float64             `json:"average_order_value"`
+	CommissionBreakdown  CommissionBreakdown `json:"commission_breakdown"`
+	CommissionByCurrency []CommissionTotals  `json:"commission_by_currency"`
+	// HeldBalance is the approved commission an admin hold stops being
+	// paid out. ReservedBalance is kept back from past payouts in reserve,
+	// the first of it due for release at NextReserveRelease.
+	HeldBalance        float64     `json:"held_balance"`
+	ReservedBalance    float64     `json:"reserved_balance"`
+	NextReserveRelease *time.Time  `json:"next_reserve_release,omitempty"`
+	PayoutHold         *PayoutHold `json:"payout_hold,omitempty"`
+}
+
+// PayoutHold shows an agent why their payouts are held, and until when
+type PayoutHold struct {
+	Reason    string    `json:"reason"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
 
 // CommissionBreakdown shows commission by status
@@ -77,6 +101,16 @@ type CommissionBreakdown struct {
 	Paid     float64 `json:"paid"`
 }
 
+// CommissionTotals are an agent's commission amounts by status in one
+// payout currency
+type CommissionTotals struct {
+	Currency string  `json:"currency"`
+	Total    float64 `json:"total"`
+	Pending  float64 `json:"pending"`
+	Approved float64 `json:"approved"`
+	Paid     float64 `json:"paid"`
+}
+
 // Customer represents an agent's customer
 type Customer struct {
 	ID          uint       `gorm:"primaryKey" json:"id"`
@@ -149,6 +183,7 @@ func (Team) TableName() string {
 // Performance represents monthly performance metrics
 type Performance struct {
 	Month              time.Time `json:"month"`
+	Currency           string    `json:"currency"` // The agent's payout currency, which commissions are in
 	TotalSales         float64   `json:"total_sales"`
 	TotalOrders        int64     `json:"total_orders"`
 	TotalCommission    float64   `json:"total_commission"`
@@ -27,11 +27,18 @@ type Agent struct {
 	commissionRate shared.CommissionRate
 	tier           shared.AgentTier
 	status         shared.AgentStatus
-	totalEarned    float64
+	payoutCurrency string
 	teamID         *uint
 	createdAt      time.Time
 	updatedAt      time.Time
 
+	// parentID is the agent's upline sponsor; top-level agents have none
+	parentID *uint
+
+	// totalEarned is derived from the agent's earnings ledger in their
+	// payout currency when the agent is loaded; it is never changed here
+	totalEarned shared.Money
+
 	// Domain events
 	events []Event
 }
@@ -47,6 +54,13 @@ type AgentParams struct {
 	Tier           string
 	Status         string
 	TeamID         *uint
+	ParentID       *uint
+	PayoutCurrency string // Defaults to the default currency
+
+	// Stored state, only read by Reconstitute.
+	TotalEarned shared.Money
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
 }
 
 // NewAgent creates a new Agent aggregate.
@@ -58,8 +72,9 @@ func NewAgent(params AgentParams) (*Agent, error) {
 		return nil, errors.New("email is required")
 	}
 
+	// Unsaved agents get their code from the store on first save.
 	code := params.Code
-	if code == "" {
+	if code == "" && params.ID != 0 {
 		code = fmt.Sprintf("AGT%04d", params.ID)
 	}
 
@@ -79,6 +94,11 @@ func NewAgent(params AgentParams) (*Agent, error) {
 		}
 	}
 
+	payoutCurrency, err := shared.ParseCurrency(params.PayoutCurrency)
+	if err != nil {
+		return nil, err
+	}
+
 	status := shared.AgentStatusActive
 	if params.Status != "" {
 		s, err := shared.ParseAgentStatus(params.Status)
@@ -97,11 +117,14 @@ func NewAgent(params AgentParams) (*Agent, error) {
 		commissionRate: rate,
 		tier:           tier,
 		status:         status,
-		totalEarned:    0,
+		payoutCurrency: payoutCurrency,
 		teamID:         params.TeamID,
 		createdAt:      now,
 		updatedAt:      now,
 		events:         make([]Event, 0),
+
+		parentID:    params.ParentID,
+		totalEarned: shared.ZeroMoney(payoutCurrency),
 	}
 
 	agent.addEvent(NewAgentCreatedEvent(params.ID, code, params.Name))
@@ -109,6 +132,44 @@ func NewAgent(params AgentParams) (*Agent, error) {
 	return agent, nil
 }
 
+// Reconstitute rebuilds an Agent from stored state.
+// No validation is performed and no events are raised.
+func Reconstitute(params AgentParams) *Agent {
+	rate, err := shared.NewCommissionRate(params.CommissionRate)
+	if err != nil {
+		rate = shared.DefaultCommissionRate()
+	}
+
+	tier := shared.AgentTier(params.Tier)
+	if !tier.IsValid() {
+		tier = shared.TierBronze
+	}
+
+	payoutCurrency, err := shared.ParseCurrency(params.PayoutCurrency)
+	if err != nil {
+		payoutCurrency = shared.DefaultCurrency
+	}
+
+	return &Agent{
+		id:             params.ID,
+		code:           params.Code,
+		name:           params.Name,
+		email:          params.Email,
+		phone:          params.Phone,
+		commissionRate: rate,
+		tier:           tier,
+		status:         shared.AgentStatus(params.Status),
+		payoutCurrency: payoutCurrency,
+		teamID:         params.TeamID,
+		createdAt:      params.CreatedAt,
+		updatedAt:      params.UpdatedAt,
+		events:         make([]Event, 0),
+
+		parentID:    params.ParentID,
+		totalEarned: params.TotalEarned,
+	}
+}
+
 // Getters
 func (a *Agent) ID() uint                              { return a.id }
 func (a *Agent) Code() string                          { return a.code }
@@ -118,8 +179,10 @@ func (a *Agent) Phone() string                         { return a.phone }
 func (a *Agent) CommissionRate() shared.CommissionRate { return a.commissionRate }
 func (a *Agent) Tier() shared.AgentTier                { return a.tier }
 func (a *Agent) Status() shared.AgentStatus            { return a.status }
-func (a *Agent) TotalEarned() float64                  { return a.totalEarned }
+func (a *Agent) TotalEarned() shared.Money             { return a.totalEarned }
+func (a *Agent) PayoutCurrency() string                { return a.payoutCurrency }
 func (a *Agent) TeamID() *uint                         { return a.teamID }
+func (a *Agent) ParentID() *uint                       { return a.parentID }
 func (a *Agent) CreatedAt() time.Time                  { return a.createdAt }
 func (a *Agent) UpdatedAt() time.Time                  { return a.updatedAt }
 
@@ -131,6 +194,12 @@ func (a *Agent) EffectiveCommissionRate() shared.CommissionRate {
 
 // --- Behavior Methods ---
 
+// AssignIdentity records the ID and code assigned by the store on first save.
+func (a *Agent) AssignIdentity(id uint, code string) {
+	a.id = id
+	a.code = code
+}
+
 // UpdateProfile updates the agent's profile.
 func (a *Agent) UpdateProfile(name, email, phone string) error {
 	if name != "" {
@@ -155,6 +224,18 @@ func (a *Agent) SetCommissionRate(rate float64) error {
 	return nil
 }
 
+// SetPayoutCurrency sets the currency the agent is paid in. Commissions
+// already approved keep the currency they were converted to.
+func (a *Agent) SetPayoutCurrency(currency string) error {
+	c, err := shared.ParseCurrency(currency)
+	if err != nil {
+		return err
+	}
+	a.payoutCurrency = c
+	a.updatedAt = time.Now()
+	return nil
+}
+
 // PromoteTier promotes the agent to the next tier.
 func (a *Agent) PromoteTier() error {
 	nextTier := a.tier.NextTier()
@@ -167,6 +248,34 @@ func (a *Agent) PromoteTier() error {
 	return nil
 }
 
+// DemoteTier demotes the agent to the previous tier.
+func (a *Agent) DemoteTier() error {
+	previousTier := a.tier.PreviousTier()
+	if previousTier == a.tier {
+		return errors.New("already at lowest tier")
+	}
+	a.tier = previousTier
+	a.updatedAt = time.Now()
+	a.addEvent(NewAgentDemotedEvent(a.id, string(previousTier)))
+	return nil
+}
+
+// ChangeTier moves the agent to the given tier one step at a time, so a
+// promoted or demoted event is raised for every tier crossed.
+func (a *Agent) ChangeTier(target shared.AgentTier) error {
+	for target.IsHigherThan(a.tier) {
+		if err := a.PromoteTier(); err != nil {
+			return err
+		}
+	}
+	for a.tier.IsHigherThan(target) {
+		if err := a.DemoteTier(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // SetTier sets the agent tier directly.
 func (a *Agent) SetTier(tierStr string) error {
 	tier, err := shared.ParseAgentTier(tierStr)
@@ -178,6 +287,21 @@ func (a *Agent) SetTier(tierStr string) error {
 	return nil
 }
 
+// SetStatus sets the agent status directly.
+func (a *Agent) SetStatus(statusStr string) error {
+	status, err := shared.ParseAgentStatus(statusStr)
+	if err != nil {
+		return err
+	}
+	if status == a.status {
+		return nil
+	}
+	a.status = status
+	a.updatedAt = time.Now()
+	a.addEvent(NewAgentStatusChangedEvent(a.id, string(a.status)))
+	return nil
+}
+
 // Activate activates the agent.
 func (a *Agent) Activate() error {
 	if !a.status.CanBeActivated() {
@@ -220,10 +344,38 @@ func (a *Agent) RemoveFromTeam() {
 	a.updatedAt = time.Now()
 }
 
-// RecordEarnings records earnings from a commission.
-func (a *Agent) RecordEarnings(amount float64) {
-	a.totalEarned += amount
+// MoveUnder makes another agent this agent's upline sponsor. The
+// agent's downline moves with it. Callers check the rest of the tree for
+// cycles and depth with CheckMove.
+func (a *Agent) MoveUnder(parentID uint) error {
+	if parentID == a.id {
+		return ErrHierarchyCycle
+	}
+	if a.parentID != nil && *a.parentID == parentID {
+		return nil
+	}
+	previous := a.parentID
+	a.parentID = &parentID
+	a.updatedAt = time.Now()
+	a.addEvent(NewAgentMovedEvent(a.id, previous, a.parentID))
+	return nil
+}
+
+// MoveToTop removes the agent's upline sponsor, making it the root of its
+// own downline.
+func (a *Agent) MoveToTop() {
+	if a.parentID == nil {
+		return
+	}
+	previous := a.parentID
+	a.parentID = nil
 	a.updatedAt = time.Now()
+	a.addEvent(NewAgentMovedEvent(a.id, previous, nil))
+}
+
+// HasParent returns true if the agent has an upline sponsor.
+func (a *Agent) HasParent() bool {
+	return a.parentID != nil
 }
 
 // CanEarnCommission returns true if agent can earn commissions.
@@ -69,3 +69,38 @@ func NewAgentPromotedEvent(agentID uint, newTier string) AgentPromotedEvent {
 		NewTier:   newTier,
 	}
 }
+
+// AgentDemotedEvent is raised when agent is demoted to a lower tier.
+type AgentDemotedEvent struct {
+	baseEvent
+	NewTier string
+}
+
+func (e AgentDemotedEvent) EventType() string { return "agent.demoted" }
+
+// NewAgentDemotedEvent creates a new AgentDemotedEvent.
+func NewAgentDemotedEvent(agentID uint, newTier string) AgentDemotedEvent {
+	return AgentDemotedEvent{
+		baseEvent: baseEvent{occurredAt: time.Now(), aggregateID: agentID},
+		NewTier:   newTier,
+	}
+}
+
+// AgentMovedEvent is raised when an agent, with its downline, is moved
+// under a different upline sponsor.
+type AgentMovedEvent struct {
+	baseEvent
+	PreviousParentID *uint
+	NewParentID      *uint
+}
+
+func (e AgentMovedEvent) EventType() string { return "agent.moved" }
+
+// NewAgentMovedEvent creates a new AgentMovedEvent.
+func NewAgentMovedEvent(agentID uint, previousParentID, newParentID *uint) AgentMovedEvent {
+	return AgentMovedEvent{
+		baseEvent:        baseEvent{occurredAt: time.Now(), aggregateID: agentID},
+		PreviousParentID: previousParentID,
+		NewParentID:      newParentID,
+	}
+}
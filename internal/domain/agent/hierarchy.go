@@ -0,0 +1,31 @@
+package agent
+
+import "errors"
+
+// DefaultMaxDepth is the default number of levels an agent hierarchy may
+// have below its top-level agent.
+const DefaultMaxDepth = 10
+
+// Domain errors for the agent hierarchy
+var (
+	ErrHierarchyCycle   = errors.New("agent cannot be moved under its own downline")
+	ErrHierarchyTooDeep = errors.New("agent hierarchy exceeds the maximum depth")
+)
+
+// CheckMove checks that an agent can be moved under a new upline sponsor.
+//
+// upline lists the new sponsor's ID followed by the IDs of its own
+// upline, nearest first. downlineDepth is the number of levels below the
+// agent being moved, which move with it. The deepest agent of the moved
+// subtree must stay within maxDepth levels of the top.
+func CheckMove(agentID uint, upline []uint, downlineDepth, maxDepth int) error {
+	for _, id := range upline {
+		if id == agentID {
+			return ErrHierarchyCycle
+		}
+	}
+	if len(upline)+downlineDepth > maxDepth {
+		return ErrHierarchyTooDeep
+	}
+	return nil
+}
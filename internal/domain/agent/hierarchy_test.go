@@ -0,0 +1,37 @@
+package agent
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckMove(t *testing.T) {
+	tests := []struct {
+		name          string
+		agentID       uint
+		upline        []uint
+		downlineDepth int
+		maxDepth      int
+		want          error
+	}{
+		{"to the top", 5, nil, 3, 5, nil},
+		{"under a top-level sponsor", 5, []uint{1}, 0, 5, nil},
+		{"new agent", 0, []uint{4, 3, 2, 1}, 0, 5, nil},
+		{"deepest agent at the limit", 5, []uint{3, 2, 1}, 2, 5, nil},
+		{"deepest agent past the limit", 5, []uint{3, 2, 1}, 3, 5, ErrHierarchyTooDeep},
+		{"new agent past the limit", 0, []uint{6, 5, 4, 3, 2, 1}, 0, 5, ErrHierarchyTooDeep},
+		{"no levels allowed", 5, []uint{1}, 0, 0, ErrHierarchyTooDeep},
+		{"under itself", 5, []uint{5, 1}, 0, 5, ErrHierarchyCycle},
+		{"under its direct downline", 5, []uint{6, 5, 1}, 1, 5, ErrHierarchyCycle},
+		{"under its deep downline", 5, []uint{8, 7, 6, 5, 1}, 0, 10, ErrHierarchyCycle},
+		{"cycle reported before depth", 5, []uint{9, 8, 7, 6, 5}, 4, 5, ErrHierarchyCycle},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckMove(tt.agentID, tt.upline, tt.downlineDepth, tt.maxDepth)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("CheckMove() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package agent
+
+import (
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+)
+
+// Tier evaluation outcomes
+const (
+	TierPromoted  = "promoted"
+	TierDemoted   = "demoted"
+	TierUnchanged = "unchanged"
+)
+
+// TierEvaluation records one run of the tier engine for an agent: the
+// metrics it looked at, the tier it settled on, and why.
+type TierEvaluation struct {
+	id           uint
+	agentID      uint
+	previousTier shared.AgentTier
+	newTier      shared.AgentTier
+	metrics      TierMetrics
+	periodFrom   time.Time
+	periodTo     time.Time
+	reasons      []string
+	evaluatedAt  time.Time
+}
+
+// TierEvaluationParams contains the stored state of a TierEvaluation.
+type TierEvaluationParams struct {
+	ID           uint
+	AgentID      uint
+	PreviousTier string
+	NewTier      string
+	Metrics      TierMetrics
+	PeriodFrom   time.Time
+	PeriodTo     time.Time
+	Reasons      []string
+	EvaluatedAt  time.Time
+}
+
+// NewTierEvaluation records an evaluation of the agent over a period.
+func NewTierEvaluation(agentID uint, previousTier, newTier shared.AgentTier, metrics TierMetrics, periodFrom, periodTo time.Time, reasons []string) *TierEvaluation {
+	return &TierEvaluation{
+		agentID:      agentID,
+		previousTier: previousTier,
+		newTier:      newTier,
+		metrics:      metrics,
+		periodFrom:   periodFrom,
+		periodTo:     periodTo,
+		reasons:      reasons,
+		evaluatedAt:  time.Now(),
+	}
+}
+
+// ReconstituteTierEvaluation rebuilds a TierEvaluation from stored state.
+func ReconstituteTierEvaluation(params TierEvaluationParams) *TierEvaluation {
+	return &TierEvaluation{
+		id:           params.ID,
+		agentID:      params.AgentID,
+		previousTier: shared.AgentTier(params.PreviousTier),
+		newTier:      shared.AgentTier(params.NewTier),
+		metrics:      params.Metrics,
+		periodFrom:   params.PeriodFrom,
+		periodTo:     params.PeriodTo,
+		reasons:      params.Reasons,
+		evaluatedAt:  params.EvaluatedAt,
+	}
+}
+
+// Getters
+func (e *TierEvaluation) ID() uint                       { return e.id }
+func (e *TierEvaluation) AgentID() uint                  { return e.agentID }
+func (e *TierEvaluation) PreviousTier() shared.AgentTier { return e.previousTier }
+func (e *TierEvaluation) NewTier() shared.AgentTier      { return e.newTier }
+func (e *TierEvaluation) Metrics() TierMetrics           { return e.metrics }
+func (e *TierEvaluation) PeriodFrom() time.Time          { return e.periodFrom }
+func (e *TierEvaluation) PeriodTo() time.Time            { return e.periodTo }
+func (e *TierEvaluation) Reasons() []string              { return e.reasons }
+func (e *TierEvaluation) EvaluatedAt() time.Time         { return e.evaluatedAt }
+
+// SetID records the ID assigned by the store on first save.
+func (e *TierEvaluation) SetID(id uint) {
+	e.id = id
+}
+
+// Outcome returns whether the agent was promoted, demoted or kept their tier.
+func (e *TierEvaluation) Outcome() string {
+	switch {
+	case e.newTier.IsHigherThan(e.previousTier):
+		return TierPromoted
+	case e.previousTier.IsHigherThan(e.newTier):
+		return TierDemoted
+	default:
+		return TierUnchanged
+	}
+}
@@ -0,0 +1,71 @@
+package agent
+
+import (
+	"fmt"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+)
+
+// TierCriteria are the minimum results an agent needs over the evaluation
+// window to qualify for a tier. All three must be met.
+type TierCriteria struct {
+	MinSales           float64
+	MinOrders          int64
+	MinActiveCustomers int64
+}
+
+// TierMetrics are an agent's results over the evaluation window.
+type TierMetrics struct {
+	Sales           float64
+	Orders          int64
+	ActiveCustomers int64
+}
+
+// TierPolicy holds the criteria for each tier above bronze. Bronze is the
+// base tier and needs none.
+type TierPolicy map[shared.AgentTier]TierCriteria
+
+// Qualify returns the highest tier the metrics qualify for, with the
+// reasons: why the next tier up was missed, and why this tier was met.
+func (p TierPolicy) Qualify(m TierMetrics) (shared.AgentTier, []string) {
+	tiers := shared.AllAgentTiers()
+
+	var missed []string
+	for i := len(tiers) - 1; i > 0; i-- {
+		tier := tiers[i]
+		criteria, ok := p[tier]
+		if !ok {
+			continue
+		}
+		shortfalls := criteria.shortfalls(m)
+		if len(shortfalls) == 0 {
+			return tier, append(missed, fmt.Sprintf("qualifies for %s: %s", tier, criteria.describe(m)))
+		}
+		missed = make([]string, len(shortfalls))
+		for j, shortfall := range shortfalls {
+			missed[j] = fmt.Sprintf("below %s: %s", tier, shortfall)
+		}
+	}
+	return shared.TierBronze, append(missed, "qualifies for bronze: base tier")
+}
+
+// shortfalls lists each criterion the metrics miss
+func (c TierCriteria) shortfalls(m TierMetrics) []string {
+	var shortfalls []string
+	if m.Sales < c.MinSales {
+		shortfalls = append(shortfalls, fmt.Sprintf("sales %.2f < %.2f", m.Sales, c.MinSales))
+	}
+	if m.Orders < c.MinOrders {
+		shortfalls = append(shortfalls, fmt.Sprintf("orders %d < %d", m.Orders, c.MinOrders))
+	}
+	if m.ActiveCustomers < c.MinActiveCustomers {
+		shortfalls = append(shortfalls, fmt.Sprintf("active customers %d < %d", m.ActiveCustomers, c.MinActiveCustomers))
+	}
+	return shortfalls
+}
+
+// describe summarises how the metrics meet the criteria
+func (c TierCriteria) describe(m TierMetrics) string {
+	return fmt.Sprintf("sales %.2f >= %.2f, orders %d >= %d, active customers %d >= %d",
+		m.Sales, c.MinSales, m.Orders, c.MinOrders, m.ActiveCustomers, c.MinActiveCustomers)
+}
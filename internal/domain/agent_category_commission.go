@@ -14,7 +14,10 @@ import (
 	"gorm.io/gorm"
 )
 
-// AgentCategoryCommission stores category-specific commission rates per agent
+// AgentCategoryCommission stores category-specific commission rates per
+// agent. Rows are effective-dated: a row applies from EffectiveFrom until
+// the next row for the same agent and category, and an inactive row ends
+// the category rate so the agent's base rate applies again.
 type AgentCategoryCommission struct {
 	ID             uint           `gorm:"primaryKey" json:"id"`
 	AgentID        uint           `gorm:"index;not null" json:"agent_id"`
@@ -22,6 +25,7 @@ type AgentCategoryCommission struct {
 	CategoryName   string         `gorm:"size:255" json:"category_name"`       // Cached for display
 	CommissionRate float64        `gorm:"type:decimal(5,2);not null" json:"commission_rate"`
 	IsActive       bool           `gorm:"default:true" json:"is_active"`
+	EffectiveFrom  time.Time      `gorm:"index;not null" json:"effective_from"`
 	CreatedAt      time.Time      `json:"created_at"`
 	UpdatedAt      time.Time      `json:"updated_at"`
 	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
@@ -10,6 +10,8 @@ package domain
 
 import (
 	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
 )
 
 type Commission struct {
@@ -31,7 +33,9 @@ func (Commission) TableName() string {
 	return "commissions"
 }
 
-// CalculateCommission calculates commission amount from order total and rate
+// CalculateCommission calculates commission amount from order total and rate,
+// rounded half up to the sen
 func CalculateCommission(orderTotal, rate float64) float64 {
-	return (orderTotal * rate) / 100.0
+	total := shared.MoneyFromFloat(orderTotal, shared.DefaultCurrency)
+	return total.MulPercent(rate, shared.RoundHalfUp).Float64()
 }
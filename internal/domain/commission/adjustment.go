@@ -0,0 +1,94 @@
+package commission
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+)
+
+// Domain errors for adjustments
+var (
+	ErrInvalidAdjustment = errors.New("invalid adjustment amount")
+	ErrNoteRequired      = errors.New("a note is required for an adjustment")
+)
+
+// AdjustmentParams contains parameters for a manual adjustment to an
+// agent's earnings.
+type AdjustmentParams struct {
+	AgentID uint
+
+	// Amount is signed: positive credits the agent, negative debits them.
+	// It must suit the category.
+	Amount   shared.Money
+	Category shared.AdjustmentCategory
+	Note     string
+
+	// CreatedBy is the admin making the adjustment
+	CreatedBy string
+
+	// OrderID links the order a correction is for, such as one found on a
+	// dispute. Bonuses and penalties are not tied to an order.
+	OrderID string
+}
+
+// NewAdjustmentCommission creates a manual adjustment an admin makes to an
+// agent's earnings. It is paid like any other commission, and a debit is
+// netted against the agent's other commissions in their next payout.
+func NewAdjustmentCommission(params AdjustmentParams) (*Commission, error) {
+	return NewCommission(CommissionParams{
+		AgentID:   params.AgentID,
+		OrderID:   params.OrderID,
+		Amount:    params.Amount,
+		Type:      shared.CommissionTypeAdjustment.String(),
+		Category:  params.Category.String(),
+		Note:      params.Note,
+		CreatedBy: params.CreatedBy,
+	})
+}
+
+// newAdjustment validates and creates an adjustment. Adjustments are not
+// earned on a sale, so they have no order total, rate or split.
+func newAdjustment(params CommissionParams) (*Commission, error) {
+	category, err := shared.ParseAdjustmentCategory(params.Category)
+	if err != nil {
+		return nil, err
+	}
+	if !category.AllowsAmount(params.Amount) {
+		return nil, ErrInvalidAdjustment
+	}
+	if params.Note == "" {
+		return nil, ErrNoteRequired
+	}
+	if params.SourceCommissionID != nil || params.SplitGroupID != "" {
+		return nil, ErrInvalidCommission
+	}
+
+	amount := params.Amount
+	now := time.Now()
+	c := &Commission{
+		id:         params.ID,
+		agentID:    params.AgentID,
+		orderID:    params.OrderID,
+		orderTotal: shared.ZeroMoney(amount.Currency()),
+		amount:     amount,
+		status:     shared.CommissionPending,
+		createdAt:  now,
+		updatedAt:  now,
+		events:     make([]Event, 0),
+
+		payoutAmount: amount,
+		fxRate:       shared.IdentityRate(amount.Currency()),
+		splitShare:   FullShare,
+
+		commissionType: shared.CommissionTypeAdjustment,
+		category:       category,
+		note:           params.Note,
+		createdBy:      params.CreatedBy,
+	}
+
+	c.addEvent(NewCommissionCreatedEvent(params.ID, params.AgentID, params.OrderID, amount.Float64()))
+	c.recordCreated()
+
+	return c, nil
+}
@@ -0,0 +1,173 @@
+package commission
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+)
+
+// ErrAutoApprovalNotFound is returned when a commission was not auto-approved
+var ErrAutoApprovalNotFound = errors.New("auto-approval not found")
+
+// AutoApprovalRule approves pending commissions without review once the
+// return window after the sale has passed, up to a maximum amount.
+type AutoApprovalRule struct {
+	Name          string
+	HoldingPeriod time.Duration
+	MaxAmount     shared.Money // In the default currency; zero means no limit
+}
+
+// AutoApprovalPolicy holds the auto-approval rules in the order they are
+// tried. A commission is approved by the first rule it meets.
+type AutoApprovalPolicy []AutoApprovalRule
+
+// AutoApprovalFacts are what the policy looks at for a pending commission.
+type AutoApprovalFacts struct {
+	Age                  time.Duration // Time since the sale
+	Amount               shared.Money  // Net amount in the default currency
+	AgentActive          bool
+	OutstandingClawbacks int
+	Disputed             bool // Part of the order was refunded, or the agent disputes it
+}
+
+// Match returns the rule that approves a commission with the given facts,
+// with the reasons it was met. When no rule does, it returns nil with the
+// reasons the commission is held for review.
+func (p AutoApprovalPolicy) Match(f AutoApprovalFacts) (*AutoApprovalRule, []string) {
+	if held := f.holds(); len(held) > 0 {
+		return nil, held
+	}
+
+	var missed []string
+	for i := range p {
+		rule := &p[i]
+		shortfalls := rule.shortfalls(f)
+		if len(shortfalls) == 0 {
+			return rule, []string{
+				fmt.Sprintf("holding period of %s elapsed", formatPeriod(rule.HoldingPeriod)),
+				rule.describeAmount(f),
+				"agent in good standing",
+				"order not refunded or disputed",
+			}
+		}
+		for _, shortfall := range shortfalls {
+			missed = append(missed, fmt.Sprintf("%s: %s", rule.Name, shortfall))
+		}
+	}
+	if len(p) == 0 {
+		missed = append(missed, "no auto-approval rules")
+	}
+	return nil, missed
+}
+
+// holds lists why the commission needs review whatever the rule
+func (f AutoApprovalFacts) holds() []string {
+	var held []string
+	if !f.AgentActive {
+		held = append(held, "agent is not active")
+	}
+	if f.OutstandingClawbacks > 0 {
+		held = append(held, fmt.Sprintf("agent has %d outstanding clawbacks", f.OutstandingClawbacks))
+	}
+	if f.Disputed {
+		held = append(held, "order is refunded or disputed")
+	}
+	return held
+}
+
+// shortfalls lists each condition of the rule the facts miss
+func (r *AutoApprovalRule) shortfalls(f AutoApprovalFacts) []string {
+	var shortfalls []string
+	if f.Age < r.HoldingPeriod {
+		shortfalls = append(shortfalls, fmt.Sprintf("holding period of %s not elapsed", formatPeriod(r.HoldingPeriod)))
+	}
+	if r.MaxAmount.IsPositive() && f.Amount.Cmp(r.MaxAmount) > 0 {
+		shortfalls = append(shortfalls, fmt.Sprintf("amount %s > %s", f.Amount, r.MaxAmount))
+	}
+	return shortfalls
+}
+
+// describeAmount summarises how the amount meets the rule
+func (r *AutoApprovalRule) describeAmount(f AutoApprovalFacts) string {
+	if !r.MaxAmount.IsPositive() {
+		return fmt.Sprintf("amount %s, no limit", f.Amount)
+	}
+	return fmt.Sprintf("amount %s <= %s", f.Amount, r.MaxAmount)
+}
+
+// formatPeriod formats a holding period in days when it is a whole number
+// of days
+func formatPeriod(d time.Duration) string {
+	const day = 24 * time.Hour
+	if d > 0 && d%day == 0 {
+		if d == day {
+			return "1 day"
+		}
+		return fmt.Sprintf("%d days", d/day)
+	}
+	return d.String()
+}
+
+// AutoApproval records a commission approved without review, with the
+// rule that approved it and why.
+type AutoApproval struct {
+	id           uint
+	commissionID uint
+	agentID      uint
+	rule         string
+	amount       shared.Money
+	reasons      []string
+	approvedAt   time.Time
+}
+
+// AutoApprovalParams contains the stored state of an AutoApproval.
+type AutoApprovalParams struct {
+	ID           uint
+	CommissionID uint
+	AgentID      uint
+	Rule         string
+	Amount       shared.Money // The commission's net amount when approved
+	Reasons      []string
+	ApprovedAt   time.Time
+}
+
+// NewAutoApproval records the approval of a commission by a rule.
+func NewAutoApproval(c *Commission, rule string, reasons []string, approvedAt time.Time) *AutoApproval {
+	return &AutoApproval{
+		commissionID: c.ID(),
+		agentID:      c.AgentID(),
+		rule:         rule,
+		amount:       c.NetAmount(),
+		reasons:      reasons,
+		approvedAt:   approvedAt,
+	}
+}
+
+// ReconstituteAutoApproval rebuilds an AutoApproval from stored state.
+func ReconstituteAutoApproval(params AutoApprovalParams) *AutoApproval {
+	return &AutoApproval{
+		id:           params.ID,
+		commissionID: params.CommissionID,
+		agentID:      params.AgentID,
+		rule:         params.Rule,
+		amount:       params.Amount,
+		reasons:      params.Reasons,
+		approvedAt:   params.ApprovedAt,
+	}
+}
+
+// Getters
+func (a *AutoApproval) ID() uint              { return a.id }
+func (a *AutoApproval) CommissionID() uint    { return a.commissionID }
+func (a *AutoApproval) AgentID() uint         { return a.agentID }
+func (a *AutoApproval) Rule() string          { return a.rule }
+func (a *AutoApproval) Amount() shared.Money  { return a.amount }
+func (a *AutoApproval) Reasons() []string     { return a.reasons }
+func (a *AutoApproval) ApprovedAt() time.Time { return a.approvedAt }
+
+// SetID records the ID assigned by the store on first save.
+func (a *AutoApproval) SetID(id uint) {
+	a.id = id
+}
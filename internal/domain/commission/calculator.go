@@ -14,18 +14,18 @@ func NewCalculator() *Calculator {
 
 // CalculationParams contains parameters for commission calculation.
 type CalculationParams struct {
-	OrderTotal     float64
+	OrderTotal     shared.Money
 	BaseRate       shared.CommissionRate
 	AgentTier      shared.AgentTier
 	CategoryRates  map[string]shared.CommissionRate // Optional category-specific rates
-	ProductAmounts map[string]float64               // productID -> amount
+	ProductAmounts map[string]shared.Money          // productID -> amount
 }
 
 // CalculationResult contains the result of commission calculation.
 type CalculationResult struct {
-	BaseAmount    float64
-	TierBonus     float64
-	TotalAmount   float64
+	BaseAmount    shared.Money
+	TierBonus     shared.Money
+	TotalAmount   shared.Money
 	EffectiveRate float64
 }
 
@@ -35,13 +35,12 @@ func (c *Calculator) Calculate(params CalculationParams) CalculationResult {
 	baseAmount := params.BaseRate.CalculateCommission(params.OrderTotal)
 
 	// Tier bonus
-	tierBonusRate := params.AgentTier.BonusPercentage()
-	tierBonus := params.OrderTotal * tierBonusRate
+	tierBonus := tierBonusOn(params.OrderTotal, params.AgentTier)
 
-	totalAmount := baseAmount + tierBonus
+	totalAmount := baseAmount.Add(tierBonus)
 	effectiveRate := 0.0
-	if params.OrderTotal > 0 {
-		effectiveRate = (totalAmount / params.OrderTotal) * 100
+	if params.OrderTotal.IsPositive() {
+		effectiveRate = float64(totalAmount.Minor()) / float64(params.OrderTotal.Minor()) * 100
 	}
 
 	return CalculationResult{
@@ -53,43 +52,47 @@ func (c *Calculator) Calculate(params CalculationParams) CalculationResult {
 }
 
 // CalculateSimple calculates commission without tier bonus.
-func (c *Calculator) CalculateSimple(orderTotal float64, rate shared.CommissionRate) float64 {
+func (c *Calculator) CalculateSimple(orderTotal shared.Money, rate shared.CommissionRate) shared.Money {
 	return rate.CalculateCommission(orderTotal)
 }
 
 // CalculateWithTier calculates commission with tier bonus applied.
-func (c *Calculator) CalculateWithTier(orderTotal float64, rate shared.CommissionRate, tier shared.AgentTier) float64 {
+func (c *Calculator) CalculateWithTier(orderTotal shared.Money, rate shared.CommissionRate, tier shared.AgentTier) shared.Money {
 	baseAmount := rate.CalculateCommission(orderTotal)
-	tierBonus := orderTotal * tier.BonusPercentage()
-	return baseAmount + tierBonus
+	return baseAmount.Add(tierBonusOn(orderTotal, tier))
 }
 
 // CalculateCategoryBased calculates commission with category-specific rates.
+// Each category is rounded on its own, so the total matches the sum of the
+// per-category amounts shown to the agent.
 func (c *Calculator) CalculateCategoryBased(
-	productAmounts map[string]float64, // categoryID -> amount
+	productAmounts map[string]shared.Money, // categoryID -> amount
 	categoryRates map[string]shared.CommissionRate,
 	defaultRate shared.CommissionRate,
 	tier shared.AgentTier,
-) float64 {
-	var totalCommission float64
-	var totalOrder float64
+) shared.Money {
+	var totalCommission shared.Money
+	var totalOrder shared.Money
 
 	for categoryID, amount := range productAmounts {
 		rate := defaultRate
 		if r, exists := categoryRates[categoryID]; exists {
 			rate = r
 		}
-		totalCommission += rate.CalculateCommission(amount)
-		totalOrder += amount
+		totalCommission = totalCommission.Add(rate.CalculateCommission(amount))
+		totalOrder = totalOrder.Add(amount)
 	}
 
 	// Add tier bonus on total
-	tierBonus := totalOrder * tier.BonusPercentage()
-
-	return totalCommission + tierBonus
+	return totalCommission.Add(tierBonusOn(totalOrder, tier))
 }
 
 // EffectiveRate calculates the effective rate including tier bonus.
 func (c *Calculator) EffectiveRate(baseRate shared.CommissionRate, tier shared.AgentTier) shared.CommissionRate {
 	return baseRate.AddPercentage(tier.BonusPercentage())
 }
+
+// tierBonusOn returns a tier's bonus on an amount.
+func tierBonusOn(amount shared.Money, tier shared.AgentTier) shared.Money {
+	return amount.MulPercent(tier.BonusPercentage()*100, shared.RoundHalfUp)
+}
@@ -0,0 +1,152 @@
+package commission
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+)
+
+// Domain errors for Clawback entity
+var (
+	ErrClawbackNotFound       = errors.New("clawback not found")
+	ErrInvalidClawback        = errors.New("invalid clawback data")
+	ErrClawbackNotOutstanding = errors.New("clawback is not outstanding")
+)
+
+// Clawback is a negative adjustment against a commission, raised when the
+// order behind it is refunded. Its amount is always negative.
+type Clawback struct {
+	id           uint
+	commissionID uint
+	agentID      uint
+	orderID      string
+	refundID     string
+	amount       shared.Money
+	refundAmount shared.Money
+	payoutAmount shared.Money // The amount in the commission's payout currency
+	reason       string
+	status       shared.ClawbackStatus
+	payoutID     *uint
+	createdAt    time.Time
+	updatedAt    time.Time
+
+	// version counts the clawback's saves, so two payouts cannot both
+	// recover it
+	version int
+}
+
+// ClawbackParams contains the stored state of a Clawback.
+type ClawbackParams struct {
+	ID           uint
+	CommissionID uint
+	AgentID      uint
+	OrderID      string
+	RefundID     string
+	Amount       shared.Money
+	RefundAmount shared.Money
+	PayoutAmount shared.Money
+	Reason       string
+	Status       string
+	PayoutID     *uint
+	Version      int
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// newClawback creates a clawback of the given positive amount against c,
+// which is payoutAmount in the commission's payout currency.
+func newClawback(c *Commission, amount, payoutAmount shared.Money, refund Refund, status shared.ClawbackStatus) *Clawback {
+	now := time.Now()
+	return &Clawback{
+		commissionID: c.id,
+		agentID:      c.agentID,
+		orderID:      c.orderID,
+		refundID:     refund.ID,
+		amount:       amount.Neg(),
+		refundAmount: refund.Amount,
+		payoutAmount: payoutAmount.Neg(),
+		reason:       refund.Reason,
+		status:       status,
+		createdAt:    now,
+		updatedAt:    now,
+	}
+}
+
+// ReconstituteClawback rebuilds a Clawback from stored state.
+func ReconstituteClawback(params ClawbackParams) *Clawback {
+	payoutAmount := params.PayoutAmount
+	if payoutAmount.IsZero() {
+		payoutAmount = params.Amount
+	}
+	return &Clawback{
+		id:           params.ID,
+		commissionID: params.CommissionID,
+		agentID:      params.AgentID,
+		orderID:      params.OrderID,
+		refundID:     params.RefundID,
+		amount:       params.Amount,
+		refundAmount: params.RefundAmount,
+		payoutAmount: payoutAmount,
+		reason:       params.Reason,
+		status:       shared.ClawbackStatus(params.Status),
+		payoutID:     params.PayoutID,
+		version:      params.Version,
+		createdAt:    params.CreatedAt,
+		updatedAt:    params.UpdatedAt,
+	}
+}
+
+// Getters
+func (c *Clawback) ID() uint                      { return c.id }
+func (c *Clawback) CommissionID() uint            { return c.commissionID }
+func (c *Clawback) AgentID() uint                 { return c.agentID }
+func (c *Clawback) OrderID() string               { return c.orderID }
+func (c *Clawback) RefundID() string              { return c.refundID }
+func (c *Clawback) Amount() shared.Money          { return c.amount }
+func (c *Clawback) RefundAmount() shared.Money    { return c.refundAmount }
+func (c *Clawback) PayoutAmount() shared.Money    { return c.payoutAmount }
+func (c *Clawback) Reason() string                { return c.reason }
+func (c *Clawback) Status() shared.ClawbackStatus { return c.status }
+func (c *Clawback) PayoutID() *uint               { return c.payoutID }
+func (c *Clawback) CreatedAt() time.Time          { return c.createdAt }
+func (c *Clawback) UpdatedAt() time.Time          { return c.updatedAt }
+func (c *Clawback) Version() int                  { return c.version }
+
+// SetID records the ID assigned by the store on first save.
+func (c *Clawback) SetID(id uint) {
+	c.id = id
+}
+
+// SetVersion records the version the store saved the clawback at.
+func (c *Clawback) SetVersion(version int) {
+	c.version = version
+}
+
+// Recover marks an outstanding clawback as netted against a payout.
+func (c *Clawback) Recover(payoutID uint) error {
+	if !c.status.CanTransitionTo(shared.ClawbackRecovered) {
+		return ErrClawbackNotOutstanding
+	}
+	c.status = shared.ClawbackRecovered
+	c.payoutID = &payoutID
+	c.updatedAt = time.Now()
+	return nil
+}
+
+// Reinstate makes a recovered clawback outstanding again when the payout
+// it was netted against is cancelled.
+func (c *Clawback) Reinstate() error {
+	if !c.status.CanTransitionTo(shared.ClawbackOutstanding) {
+		return ErrInvalidClawback
+	}
+	c.status = shared.ClawbackOutstanding
+	c.payoutID = nil
+	c.updatedAt = time.Now()
+	return nil
+}
+
+// IsOutstanding returns true if the clawback still has to be recovered.
+func (c *Clawback) IsOutstanding() bool {
+	return c.status.IsOutstanding()
+}
@@ -0,0 +1,148 @@
+package commission
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/ledger"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+)
+
+// balancesOf sums ledger entries into account balances
+func balancesOf(entries []*ledger.Entry) ledger.Balances {
+	sums := make(map[shared.LedgerAccount]shared.Money)
+	var accounts []shared.LedgerAccount
+	for _, entry := range entries {
+		for _, line := range entry.Lines() {
+			if _, ok := sums[line.Account()]; !ok {
+				accounts = append(accounts, line.Account())
+			}
+			sums[line.Account()] = sums[line.Account()].Add(line.Amount())
+		}
+	}
+	balances := make(ledger.Balances, 0, len(accounts))
+	for _, account := range accounts {
+		balances = append(balances, ledger.NewBalance(account, sums[account]))
+	}
+	return balances
+}
+
+func myr(minor int64) shared.Money { return shared.NewMoney(minor, "MYR") }
+
+func TestClawBackLowersEarnings(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  shared.CommissionStatus
+		refund  int64
+		clawed  int64
+		account shared.LedgerAccount
+		balance int64 // The account's balance after the clawback
+		earned  int64 // The change in what the agent has earned
+	}{
+		{"pending, full refund", shared.CommissionPending, 10000, 1000, shared.LedgerPending, -1000, 0},
+		{"approved, partial refund", shared.CommissionApproved, 2500, 250, shared.LedgerAvailable, -250, -250},
+		{"paid, full refund", shared.CommissionPaid, 10000, 1000, shared.LedgerClawbackReceivable, 1000, -1000},
+		{"paid, partial refund", shared.CommissionPaid, 3333, 333, shared.LedgerClawbackReceivable, 333, -333},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Reconstitute(CommissionParams{
+				ID:         1,
+				AgentID:    1,
+				OrderID:    "ORD-1",
+				OrderTotal: myr(10000),
+				Rate:       10,
+				Amount:     myr(1000),
+				Status:     string(tt.status),
+			})
+			clawback, err := c.ClawBack(Refund{Amount: myr(tt.refund), OrderTotal: myr(10000), Reason: "refund"})
+			if err != nil {
+				t.Fatalf("ClawBack: %v", err)
+			}
+
+			// Clawbacks are negative, whatever the commission's status
+			if clawback.Amount().Minor() != -tt.clawed || clawback.PayoutAmount().Minor() != -tt.clawed {
+				t.Errorf("clawback amount %s, payout amount %s, want -%s",
+					clawback.Amount(), clawback.PayoutAmount(), myr(tt.clawed))
+			}
+
+			entries := c.LedgerEntries()
+			for _, entry := range entries {
+				if !entry.IsBalanced() {
+					t.Errorf("ledger entry %s is not balanced", entry.Kind())
+				}
+			}
+			balances := balancesOf(entries)
+			if got := balances.Of(tt.account, "MYR"); got.Minor() != tt.balance {
+				t.Errorf("%s balance = %s, want %s", tt.account, got, myr(tt.balance))
+			}
+			if got := balances.Earned("MYR"); got.Minor() != tt.earned {
+				t.Errorf("earned = %s, want %s", got, myr(tt.earned))
+			}
+			if got := balances.Of(shared.LedgerCommissionExpense, "MYR"); got.Minor() != -tt.clawed {
+				t.Errorf("commission expense = %s, want -%s", got, myr(tt.clawed))
+			}
+		})
+	}
+}
+
+func TestClawBackProRatesRefunds(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  shared.CommissionStatus
+		amount  int64   // Commission on an order of 10000
+		refunds []int64 // Refunds against the order, in turn
+		clawed  []int64 // Clawed back by each refund
+		final   shared.CommissionStatus
+		err     error // Returned by one more refund of 1000
+	}{
+		{"pending, one third", shared.CommissionPending, 1000, []int64{3333}, []int64{333}, shared.CommissionPending, nil},
+		{"approved, rounds half up", shared.CommissionApproved, 1001, []int64{5000}, []int64{501}, shared.CommissionApproved, nil},
+		{"pending, halves", shared.CommissionPending, 1000, []int64{5000, 5000}, []int64{500, 500}, shared.CommissionCancelled, ErrNotReversible},
+		{"paid, quarters", shared.CommissionPaid, 1000, []int64{2500, 2500, 2500, 2500}, []int64{250, 250, 250, 250}, shared.CommissionReversed, ErrNotReversible},
+		{"paid, refunds over the order", shared.CommissionPaid, 1000, []int64{6000, 6000}, []int64{600, 400}, shared.CommissionReversed, ErrNotReversible},
+		{"paid, full after partial", shared.CommissionPaid, 1000, []int64{2500, 10000}, []int64{250, 750}, shared.CommissionReversed, ErrNotReversible},
+		{"paid, partial", shared.CommissionPaid, 999, []int64{1000, 1000}, []int64{100, 100}, shared.CommissionPartiallyReversed, nil},
+		{"refund too small to claw back", shared.CommissionApproved, 1, []int64{}, []int64{}, shared.CommissionApproved, ErrInvalidClawback},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Reconstitute(CommissionParams{
+				ID:         1,
+				AgentID:    1,
+				OrderID:    "ORD-1",
+				OrderTotal: myr(10000),
+				Rate:       10,
+				Amount:     myr(tt.amount),
+				Status:     string(tt.status),
+			})
+			var total int64
+			for i, refund := range tt.refunds {
+				clawback, err := c.ClawBack(Refund{Amount: myr(refund), OrderTotal: myr(10000), Reason: "refund"})
+				if err != nil {
+					t.Fatalf("ClawBack(%s): %v", myr(refund), err)
+				}
+				if clawback.Amount().Minor() != -tt.clawed[i] {
+					t.Errorf("refund %s clawed back %s, want -%s", myr(refund), clawback.Amount(), myr(tt.clawed[i]))
+				}
+				total += tt.clawed[i]
+			}
+
+			// The clawbacks never take back more than the commission
+			if c.NetAmount().Minor() != tt.amount-total || c.NetAmount().IsNegative() {
+				t.Errorf("net amount %s, want %s", c.NetAmount(), myr(tt.amount-total))
+			}
+			if c.Status() != tt.final {
+				t.Errorf("status %s, want %s", c.Status(), tt.final)
+			}
+			if got := balancesOf(c.LedgerEntries()).Of(shared.LedgerCommissionExpense, "MYR"); got.Minor() != -total {
+				t.Errorf("commission expense = %s, want -%s", got, myr(total))
+			}
+
+			_, err := c.ClawBack(Refund{Amount: myr(1000), OrderTotal: myr(10000), Reason: "refund"})
+			if !errors.Is(err, tt.err) {
+				t.Errorf("another refund: error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
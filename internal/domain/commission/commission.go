@@ -2,8 +2,10 @@ package commission
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
+	"github.com/Ecom-micro-template/service-agent/internal/domain/ledger"
 	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
 )
 
@@ -11,24 +13,68 @@ import (
 var (
 	ErrCommissionNotFound = errors.New("commission not found")
 	ErrInvalidCommission  = errors.New("invalid commission data")
-	ErrAlreadyPaid        = errors.New("commission already paid")
-	ErrNotApproved        = errors.New("commission must be approved before payment")
+	ErrReasonRequired     = errors.New("a reason is required to reject or cancel a commission")
+	ErrNotReversible      = errors.New("commission cannot be clawed back")
+	ErrAlreadyConverted   = errors.New("commission payout currency cannot change after approval")
 )
 
-// Commission represents an agent's commission for an order.
+// Commission represents an agent's commission for an order, or a manual
+// adjustment to their earnings.
 type Commission struct {
 	id         uint
 	agentID    uint
 	orderID    string
-	orderTotal float64
+	orderTotal shared.Money
 	rate       shared.CommissionRate
-	amount     float64
+	amount     shared.Money
 	status     shared.CommissionStatus
 	createdAt  time.Time
 	updatedAt  time.Time
 
+	// reversedAmount is the total clawed back, as a positive amount
+	reversedAmount shared.Money
+
+	// statusReason is why the commission was rejected or cancelled, and
+	// statusChangedBy who did it; empty means the system, such as on a
+	// full refund.
+	statusReason    string
+	statusChangedBy string
+
+	// The amount is in the order's currency. payoutAmount is the same
+	// amount in the agent's payout currency, converted at fxRate.
+	payoutAmount shared.Money
+	fxRate       shared.ExchangeRate
+
+	// Co-sold orders have one commission per agent, linked by a split
+	// group ID. splitShare is the agent's percentage of the order.
+	splitGroupID string
+	splitShare   float64
+
+	// Derived commissions, such as team leader overrides, link back to
+	// the sale commission they were generated from.
+	commissionType     shared.CommissionType
+	sourceCommissionID *uint
+
+	// level is a downline commission's distance from the selling agent,
+	// starting at 1 for the seller's direct sponsor
+	level int
+
+	// Adjustments are signed and carry no order total or rate; the
+	// category and note say what they are for, and createdBy who made it.
+	category  shared.AdjustmentCategory
+	note      string
+	createdBy string
+
+	// version counts the commission's saves, so a save made from a stale
+	// copy is refused instead of overwriting a change made since
+	version int
+
 	// Domain events
 	events []Event
+
+	// Ledger entries for the transitions since the commission was last
+	// saved, in its payout currency
+	entries []*ledger.Entry
 }
 
 // CommissionParams contains parameters for creating a Commission.
@@ -36,9 +82,39 @@ type CommissionParams struct {
 	ID         uint
 	AgentID    uint
 	OrderID    string
-	OrderTotal float64
+	OrderTotal shared.Money
 	Rate       float64
-	Amount     float64
+	Amount     shared.Money
+
+	// SplitGroupID links the commissions of a co-sold order, and
+	// SplitShare is this agent's percentage of it. A zero share means the
+	// agent sold the order alone.
+	SplitGroupID string
+	SplitShare   float64
+
+	// Type defaults to a sale commission. Derived types must link the
+	// sale commission they came from.
+	Type               string
+	SourceCommissionID *uint
+	Level              int
+
+	// Category and Note describe an adjustment, whose Amount is signed,
+	// and CreatedBy is the admin who made it. Adjustments need no order;
+	// an OrderID only links the order corrected.
+	Category  string
+	Note      string
+	CreatedBy string
+
+	// Stored state, only read by Reconstitute.
+	Status          string
+	StatusReason    string
+	StatusChangedBy string
+	ReversedAmount  shared.Money
+	PayoutAmount    shared.Money
+	FXRate          string
+	Version         int
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
 }
 
 // NewCommission creates a new Commission entity.
@@ -46,10 +122,23 @@ func NewCommission(params CommissionParams) (*Commission, error) {
 	if params.AgentID == 0 {
 		return nil, errors.New("agent ID is required")
 	}
+
+	commissionType := shared.CommissionTypeSale
+	if params.Type != "" {
+		var err error
+		commissionType, err = shared.ParseCommissionType(params.Type)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if commissionType == shared.CommissionTypeAdjustment {
+		return newAdjustment(params)
+	}
+
 	if params.OrderID == "" {
 		return nil, errors.New("order ID is required")
 	}
-	if params.OrderTotal <= 0 {
+	if !params.OrderTotal.IsPositive() {
 		return nil, errors.New("order total must be positive")
 	}
 
@@ -58,10 +147,28 @@ func NewCommission(params CommissionParams) (*Commission, error) {
 		return nil, err
 	}
 
+	share := params.SplitShare
+	if share == 0 {
+		share = FullShare
+	}
+	if share < 0 || share > FullShare {
+		return nil, ErrInvalidSplit
+	}
+	if share < FullShare && params.SplitGroupID == "" {
+		return nil, errors.New("split group ID is required for a split commission")
+	}
+
+	if commissionType.IsDerived() != (params.SourceCommissionID != nil) {
+		return nil, errors.New("source commission is required for derived commissions only")
+	}
+
 	// Calculate amount if not provided
 	amount := params.Amount
-	if amount <= 0 {
-		amount = rate.CalculateCommission(params.OrderTotal)
+	if !amount.IsPositive() {
+		amount = rate.CalculateCommission(params.OrderTotal.MulPercent(share, shared.RoundHalfUp))
+	}
+	if !amount.SameCurrency(params.OrderTotal) {
+		return nil, shared.ErrCurrencyMismatch
 	}
 
 	now := time.Now()
@@ -76,58 +183,338 @@ func NewCommission(params CommissionParams) (*Commission, error) {
 		createdAt:  now,
 		updatedAt:  now,
 		events:     make([]Event, 0),
+
+		payoutAmount: amount,
+		fxRate:       shared.IdentityRate(amount.Currency()),
+
+		splitGroupID: params.SplitGroupID,
+		splitShare:   share,
+
+		commissionType:     commissionType,
+		sourceCommissionID: params.SourceCommissionID,
+		level:              params.Level,
 	}
 
-	commission.addEvent(NewCommissionCreatedEvent(params.ID, params.AgentID, params.OrderID, amount))
+	commission.addEvent(NewCommissionCreatedEvent(params.ID, params.AgentID, params.OrderID, amount.Float64()))
+	commission.recordCreated()
 
 	return commission, nil
 }
 
+// Reconstitute rebuilds a Commission from stored state.
+// No validation is performed and no events are raised.
+func Reconstitute(params CommissionParams) *Commission {
+	rate, _ := shared.NewCommissionRate(params.Rate)
+	share := params.SplitShare
+	if share == 0 {
+		share = FullShare
+	}
+	commissionType := shared.CommissionType(params.Type)
+	if commissionType == "" {
+		commissionType = shared.CommissionTypeSale
+	}
+	// Commissions stored before conversion are paid in their own currency
+	payoutAmount := params.PayoutAmount
+	fxRate, err := shared.NewExchangeRate(params.Amount.Currency(), payoutAmount.Currency(), params.FXRate)
+	if err != nil {
+		payoutAmount = params.Amount
+		fxRate = shared.IdentityRate(params.Amount.Currency())
+	}
+	return &Commission{
+		id:         params.ID,
+		agentID:    params.AgentID,
+		orderID:    params.OrderID,
+		orderTotal: params.OrderTotal,
+		rate:       rate,
+		amount:     params.Amount,
+		status:     shared.CommissionStatus(params.Status),
+		createdAt:  params.CreatedAt,
+		updatedAt:  params.UpdatedAt,
+		events:     make([]Event, 0),
+
+		reversedAmount:  params.ReversedAmount,
+		statusReason:    params.StatusReason,
+		statusChangedBy: params.StatusChangedBy,
+		splitGroupID:    params.SplitGroupID,
+		splitShare:      share,
+		payoutAmount:    payoutAmount,
+		fxRate:          fxRate,
+
+		commissionType:     commissionType,
+		sourceCommissionID: params.SourceCommissionID,
+		level:              params.Level,
+
+		category:  shared.AdjustmentCategory(params.Category),
+		note:      params.Note,
+		createdBy: params.CreatedBy,
+
+		version: params.Version,
+	}
+}
+
 // Getters
-func (c *Commission) ID() uint                        { return c.id }
-func (c *Commission) AgentID() uint                   { return c.agentID }
-func (c *Commission) OrderID() string                 { return c.orderID }
-func (c *Commission) OrderTotal() float64             { return c.orderTotal }
-func (c *Commission) Rate() shared.CommissionRate     { return c.rate }
-func (c *Commission) Amount() float64                 { return c.amount }
-func (c *Commission) Status() shared.CommissionStatus { return c.status }
-func (c *Commission) CreatedAt() time.Time            { return c.createdAt }
-func (c *Commission) UpdatedAt() time.Time            { return c.updatedAt }
+func (c *Commission) ID() uint                            { return c.id }
+func (c *Commission) AgentID() uint                       { return c.agentID }
+func (c *Commission) OrderID() string                     { return c.orderID }
+func (c *Commission) OrderTotal() shared.Money            { return c.orderTotal }
+func (c *Commission) Rate() shared.CommissionRate         { return c.rate }
+func (c *Commission) Amount() shared.Money                { return c.amount }
+func (c *Commission) Status() shared.CommissionStatus     { return c.status }
+func (c *Commission) CreatedAt() time.Time                { return c.createdAt }
+func (c *Commission) UpdatedAt() time.Time                { return c.updatedAt }
+func (c *Commission) ReversedAmount() shared.Money        { return c.reversedAmount }
+func (c *Commission) SplitGroupID() string                { return c.splitGroupID }
+func (c *Commission) SplitShare() float64                 { return c.splitShare }
+func (c *Commission) Type() shared.CommissionType         { return c.commissionType }
+func (c *Commission) SourceCommissionID() *uint           { return c.sourceCommissionID }
+func (c *Commission) Level() int                          { return c.level }
+func (c *Commission) PayoutAmount() shared.Money          { return c.payoutAmount }
+func (c *Commission) FXRate() shared.ExchangeRate         { return c.fxRate }
+func (c *Commission) StatusReason() string                { return c.statusReason }
+func (c *Commission) StatusChangedBy() string             { return c.statusChangedBy }
+func (c *Commission) Category() shared.AdjustmentCategory { return c.category }
+func (c *Commission) Note() string                        { return c.note }
+func (c *Commission) CreatedBy() string                   { return c.createdBy }
+func (c *Commission) Version() int                        { return c.version }
+
+// Currency returns the currency of the order the commission was earned on.
+func (c *Commission) Currency() string {
+	return c.amount.Currency()
+}
+
+// PayoutCurrency returns the currency the agent is paid the commission in.
+func (c *Commission) PayoutCurrency() string {
+	return c.payoutAmount.Currency()
+}
+
+// IsSplit returns true if the order was co-sold with other agents.
+func (c *Commission) IsSplit() bool {
+	return c.splitGroupID != ""
+}
+
+// IsAdjustment returns true if the commission is a manual adjustment
+// rather than earned on a sale.
+func (c *Commission) IsAdjustment() bool {
+	return c.commissionType == shared.CommissionTypeAdjustment
+}
+
+// IsDerived returns true if the commission was generated from another
+// agent's sale commission.
+func (c *Commission) IsDerived() bool {
+	return c.commissionType.IsDerived()
+}
+
+// ShareOfOrder returns the agent's share of the order total.
+func (c *Commission) ShareOfOrder() shared.Money {
+	return c.orderTotal.MulPercent(c.splitShare, shared.RoundHalfUp)
+}
+
+// NetAmount returns the commission amount less everything clawed back.
+func (c *Commission) NetAmount() shared.Money {
+	return c.amount.Sub(c.reversedAmount)
+}
+
+// NetPayoutAmount returns the net amount in the payout currency, at the
+// rate the commission was converted at.
+func (c *Commission) NetPayoutAmount() shared.Money {
+	if c.reversedAmount.IsZero() {
+		return c.payoutAmount
+	}
+	net, err := c.fxRate.Convert(c.NetAmount())
+	if err != nil {
+		return c.payoutAmount.MulRatio(c.NetAmount().Minor(), c.amount.Minor(), shared.RoundHalfUp)
+	}
+	return net
+}
 
 // --- Behavior Methods ---
 
+// SetID records the ID assigned by the store on first save.
+func (c *Commission) SetID(id uint) {
+	c.id = id
+}
+
+// SetVersion records the version the store saved the commission at.
+func (c *Commission) SetVersion(version int) {
+	c.version = version
+}
+
+// ConvertForPayout records the amount in the agent's payout currency at
+// the given rate from the order currency. The currency is fixed once the
+// commission is approved.
+func (c *Commission) ConvertForPayout(rate shared.ExchangeRate) error {
+	if !c.status.IsPending() {
+		return ErrAlreadyConverted
+	}
+	payoutAmount, err := rate.Convert(c.amount)
+	if err != nil {
+		return err
+	}
+
+	// A stored commission moves its pending balance to the new currency;
+	// a new one is simply recorded in it
+	if c.id != 0 {
+		c.post(ledger.KindCommissionConverted, shared.LedgerPending, shared.LedgerCommissionExpense, c.NetPayoutAmount())
+	}
+	c.payoutAmount = payoutAmount
+	c.fxRate = rate
+	c.updatedAt = time.Now()
+	if c.id != 0 {
+		c.post(ledger.KindCommissionConverted, shared.LedgerCommissionExpense, shared.LedgerPending, c.NetPayoutAmount())
+	} else {
+		c.recordCreated()
+	}
+	return nil
+}
+
 // Approve approves the commission for payment.
 func (c *Commission) Approve() error {
-	if !c.status.CanTransitionTo(shared.CommissionApproved) {
-		return ErrInvalidCommission
+	if err := c.transitionTo(shared.CommissionApproved); err != nil {
+		return err
 	}
-	c.status = shared.CommissionApproved
-	c.updatedAt = time.Now()
-	c.addEvent(NewCommissionApprovedEvent(c.id, c.agentID, c.amount))
+	c.post(ledger.KindCommissionApproved, shared.LedgerPending, shared.LedgerAvailable, c.NetPayoutAmount())
+	c.addEvent(NewCommissionApprovedEvent(c.id, c.agentID, c.amount.Float64()))
 	return nil
 }
 
 // MarkAsPaid marks the commission as paid.
 func (c *Commission) MarkAsPaid() error {
-	if !c.status.CanTransitionTo(shared.CommissionPaid) {
-		return ErrNotApproved
+	if err := c.transitionTo(shared.CommissionPaid); err != nil {
+		return err
 	}
-	c.status = shared.CommissionPaid
-	c.updatedAt = time.Now()
-	c.addEvent(NewCommissionPaidEvent(c.id, c.agentID, c.amount))
+	c.addEvent(NewCommissionPaidEvent(c.id, c.agentID, c.amount.Float64()))
 	return nil
 }
 
-// Cancel cancels the commission.
-func (c *Commission) Cancel(reason string) error {
-	if c.status.IsTerminal() {
-		return ErrAlreadyPaid
+// Reject rejects a pending commission on review. The actor is who rejected
+// it.
+func (c *Commission) Reject(reason, actor string) error {
+	if reason == "" {
+		return ErrReasonRequired
 	}
-	c.status = shared.CommissionCancelled
+	if err := c.transitionTo(shared.CommissionRejected); err != nil {
+		return err
+	}
+	c.post(ledger.KindCommissionRejected, shared.LedgerPending, shared.LedgerCommissionExpense, c.NetPayoutAmount())
+	c.statusReason = reason
+	c.statusChangedBy = actor
+	c.addEvent(NewCommissionRejectedEvent(c.id, c.agentID, c.amount.Float64(), reason))
+	return nil
+}
+
+// Cancel cancels a pending or approved commission. The actor is who
+// cancelled it; empty means the system.
+func (c *Commission) Cancel(reason, actor string) error {
+	account := c.ledgerAccount()
+	if err := c.transitionTo(shared.CommissionCancelled); err != nil {
+		return err
+	}
+	c.post(ledger.KindCommissionCancelled, account, shared.LedgerCommissionExpense, c.NetPayoutAmount())
+	c.statusReason = reason
+	c.statusChangedBy = actor
+	c.addEvent(NewCommissionCancelledEvent(c.id, c.agentID, c.amount.Float64(), reason))
+	return nil
+}
+
+// transitionTo moves the commission to the target status if the current
+// status allows it.
+func (c *Commission) transitionTo(target shared.CommissionStatus) error {
+	status, err := c.status.TransitionTo(target)
+	if err != nil {
+		return err
+	}
+	c.status = status
 	c.updatedAt = time.Now()
 	return nil
 }
 
+// Refund describes an order refund that a commission is clawed back for.
+type Refund struct {
+	ID         string       // Refund reference from service-order, if any
+	Amount     shared.Money // Amount refunded to the customer
+	OrderTotal shared.Money // Order total the refund is pro-rated over
+	Reason     string
+}
+
+// IsFull returns true if the refund covers the whole order.
+func (r Refund) IsFull() bool {
+	return r.Amount.Cmp(r.OrderTotal) >= 0
+}
+
+// Key identifies the refund for a commission, so a replayed refund is
+// clawed back only once. It is the refund ID or, for refunds without one,
+// is made from the order, the commission and the amount refunded: two
+// refunds of the same amount without IDs count as one.
+func (r Refund) Key(c *Commission) string {
+	if r.ID != "" {
+		return r.ID
+	}
+	return fmt.Sprintf("%s:%d:%s %s", c.OrderID(), c.ID(), r.Amount, r.Amount.Currency())
+}
+
+// ClawBack reverses the part of the commission earned on a refund.
+//
+// The clawback is pro-rated from the refund over the order total; a refund
+// of the whole order reverses whatever is left of the commission. How the
+// clawback is settled depends on whether the commission was paid:
+//
+//   - pending or approved: the clawback is deducted from the commission
+//     before payout. A full clawback cancels the commission.
+//   - paid or partially reversed: the agent owes the clawback, which is
+//     netted against their next payout. The commission moves to
+//     partially_reversed or reversed.
+func (c *Commission) ClawBack(refund Refund) (*Clawback, error) {
+	if !refund.Amount.IsPositive() || !refund.OrderTotal.IsPositive() {
+		return nil, ErrInvalidClawback
+	}
+	if c.status.IsTerminal() || !c.amount.IsPositive() {
+		return nil, ErrNotReversible
+	}
+
+	remaining := c.NetAmount()
+	amount := remaining
+	full := refund.IsFull()
+	if !full {
+		amount = c.amount.MulRatio(refund.Amount.Minor(), refund.OrderTotal.Minor(), shared.RoundHalfUp)
+		if amount.Cmp(remaining) >= 0 {
+			amount = remaining
+			full = true
+		}
+	}
+	if !amount.IsPositive() {
+		return nil, ErrInvalidClawback
+	}
+
+	// The clawback in the payout currency is what it takes off the net
+	// payout amount, so the ledger always adds up to the commission
+	account := c.ledgerAccount()
+	before := c.NetPayoutAmount()
+	c.reversedAmount = c.reversedAmount.Add(amount)
+	c.updatedAt = time.Now()
+	payoutAmount := before.Sub(c.NetPayoutAmount())
+	c.post(ledger.KindCommissionClawedBack, account, shared.LedgerCommissionExpense, payoutAmount)
+
+	if !c.status.WasPaid() {
+		if full {
+			c.status = shared.CommissionCancelled
+			c.statusReason = refund.Reason
+			c.addEvent(NewCommissionCancelledEvent(c.id, c.agentID, c.amount.Float64(), refund.Reason))
+		} else {
+			c.addEvent(NewCommissionPartiallyReversedEvent(c.id, c.agentID, amount.Float64(), c.NetAmount().Float64(), refund.Reason))
+		}
+		return newClawback(c, amount, payoutAmount, refund, shared.ClawbackApplied), nil
+	}
+
+	if full {
+		c.status = shared.CommissionReversed
+		c.addEvent(NewCommissionReversedEvent(c.id, c.agentID, amount.Float64(), refund.Reason))
+	} else {
+		c.status = shared.CommissionPartiallyReversed
+		c.addEvent(NewCommissionPartiallyReversedEvent(c.id, c.agentID, amount.Float64(), c.NetAmount().Float64(), refund.Reason))
+	}
+	return newClawback(c, amount, payoutAmount, refund, shared.ClawbackOutstanding), nil
+}
+
 // IsPending returns true if commission is pending.
 func (c *Commission) IsPending() bool {
 	return c.status.IsPending()
@@ -158,3 +545,42 @@ func (c *Commission) Events() []Event {
 func (c *Commission) addEvent(event Event) {
 	c.events = append(c.events, event)
 }
+
+// LedgerEntries returns and clears the ledger entries for the transitions
+// since the commission was last saved, linked to the commission.
+func (c *Commission) LedgerEntries() []*ledger.Entry {
+	entries := c.entries
+	c.entries = nil
+	for _, entry := range entries {
+		entry.ForCommission(c.id)
+	}
+	return entries
+}
+
+// recordCreated records the new commission as pending in the ledger,
+// replacing the entry made before it was converted for payout.
+func (c *Commission) recordCreated() {
+	c.entries = nil
+	c.post(ledger.KindCommissionCreated, shared.LedgerCommissionExpense, shared.LedgerPending, c.payoutAmount)
+}
+
+// post records a ledger entry debiting one account and crediting another.
+func (c *Commission) post(kind string, debit, credit shared.LedgerAccount, amount shared.Money) {
+	if entry := ledger.Transfer(c.agentID, kind, debit, credit, amount); entry != nil {
+		c.entries = append(c.entries, entry)
+	}
+}
+
+// ledgerAccount returns the account the commission's net amount is held
+// in: pending until approved, then available until paid. Once paid, the
+// agent owes whatever is clawed back.
+func (c *Commission) ledgerAccount() shared.LedgerAccount {
+	switch {
+	case c.status.IsPending():
+		return shared.LedgerPending
+	case c.status.WasPaid():
+		return shared.LedgerClawbackReceivable
+	default:
+		return shared.LedgerAvailable
+	}
+}
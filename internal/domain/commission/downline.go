@@ -0,0 +1,31 @@
+package commission
+
+import (
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+)
+
+// NewDownlineCommission creates an upline sponsor's commission on a sale
+// made level steps below them in the hierarchy. Like an override, it is
+// earned on the seller's share of the order, less any part of the sale
+// already clawed back.
+func NewDownlineCommission(source *Commission, sponsorID uint, level int, rate float64) (*Commission, error) {
+	if source.id == 0 || source.commissionType != shared.CommissionTypeSale || sponsorID == source.agentID || level < 1 {
+		return nil, ErrInvalidCommission
+	}
+
+	base := source.ShareOfOrder()
+	if source.amount.IsPositive() {
+		base = base.MulRatio(source.NetAmount().Minor(), source.amount.Minor(), shared.RoundHalfUp)
+	}
+
+	sourceID := source.id
+	return NewCommission(CommissionParams{
+		AgentID:            sponsorID,
+		OrderID:            source.orderID,
+		OrderTotal:         base,
+		Rate:               rate,
+		Type:               shared.CommissionTypeDownline.String(),
+		SourceCommissionID: &sourceID,
+		Level:              level,
+	})
+}
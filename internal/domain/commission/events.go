@@ -78,3 +78,98 @@ func NewCommissionPaidEvent(commissionID, agentID uint, amount float64) Commissi
 		Amount:       amount,
 	}
 }
+
+// CommissionCancelledEvent is raised when a commission is cancelled.
+type CommissionCancelledEvent struct {
+	baseEvent
+	CommissionID uint
+	AgentID      uint
+	Amount       float64
+	Reason       string
+}
+
+func (e CommissionCancelledEvent) EventType() string { return "commission.cancelled" }
+
+// NewCommissionCancelledEvent creates a new CommissionCancelledEvent.
+func NewCommissionCancelledEvent(commissionID, agentID uint, amount float64, reason string) CommissionCancelledEvent {
+	return CommissionCancelledEvent{
+		baseEvent:    baseEvent{occurredAt: time.Now()},
+		CommissionID: commissionID,
+		AgentID:      agentID,
+		Amount:       amount,
+		Reason:       reason,
+	}
+}
+
+// CommissionRejectedEvent is raised when a pending commission is rejected
+// on review.
+type CommissionRejectedEvent struct {
+	baseEvent
+	CommissionID uint
+	AgentID      uint
+	Amount       float64
+	Reason       string
+}
+
+func (e CommissionRejectedEvent) EventType() string { return "commission.rejected" }
+
+// NewCommissionRejectedEvent creates a new CommissionRejectedEvent.
+func NewCommissionRejectedEvent(commissionID, agentID uint, amount float64, reason string) CommissionRejectedEvent {
+	return CommissionRejectedEvent{
+		baseEvent:    baseEvent{occurredAt: time.Now()},
+		CommissionID: commissionID,
+		AgentID:      agentID,
+		Amount:       amount,
+		Reason:       reason,
+	}
+}
+
+// CommissionPartiallyReversedEvent is raised when part of a commission is
+// clawed back.
+type CommissionPartiallyReversedEvent struct {
+	baseEvent
+	CommissionID    uint
+	AgentID         uint
+	ClawbackAmount  float64
+	RemainingAmount float64
+	Reason          string
+}
+
+func (e CommissionPartiallyReversedEvent) EventType() string {
+	return "commission.partially_reversed"
+}
+
+// NewCommissionPartiallyReversedEvent creates a new CommissionPartiallyReversedEvent.
+func NewCommissionPartiallyReversedEvent(commissionID, agentID uint, clawbackAmount, remainingAmount float64, reason string) CommissionPartiallyReversedEvent {
+	return CommissionPartiallyReversedEvent{
+		baseEvent:       baseEvent{occurredAt: time.Now()},
+		CommissionID:    commissionID,
+		AgentID:         agentID,
+		ClawbackAmount:  clawbackAmount,
+		RemainingAmount: remainingAmount,
+		Reason:          reason,
+	}
+}
+
+// CommissionReversedEvent is raised when the rest of a paid commission is
+// clawed back.
+type CommissionReversedEvent struct {
+	baseEvent
+	CommissionID   uint
+	AgentID        uint
+	ClawbackAmount float64
+	Reason         string
+}
+
+func (e CommissionReversedEvent) EventType() string { return "commission.reversed" }
+
+// NewCommissionReversedEvent creates a new CommissionReversedEvent.
+func NewCommissionReversedEvent(commissionID, agentID uint, clawbackAmount float64, reason string) CommissionReversedEvent {
+	return CommissionReversedEvent{
+		baseEvent:      baseEvent{occurredAt: time.Now()},
+		CommissionID:   commissionID,
+		AgentID:        agentID,
+		ClawbackAmount: clawbackAmount,
+		Reason:         reason,
+	}
+}
@@ -0,0 +1,30 @@
+package commission
+
+import (
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+)
+
+// NewOverrideCommission creates a team leader's override commission on a
+// member's sale commission. The override is earned at the team's rate on
+// the member's share of the order, less any part of the sale already
+// clawed back.
+func NewOverrideCommission(source *Commission, leaderID uint, teamRate float64) (*Commission, error) {
+	if source.id == 0 || source.commissionType != shared.CommissionTypeSale || leaderID == source.agentID {
+		return nil, ErrInvalidCommission
+	}
+
+	base := source.ShareOfOrder()
+	if source.amount.IsPositive() {
+		base = base.MulRatio(source.NetAmount().Minor(), source.amount.Minor(), shared.RoundHalfUp)
+	}
+
+	sourceID := source.id
+	return NewCommission(CommissionParams{
+		AgentID:            leaderID,
+		OrderID:            source.orderID,
+		OrderTotal:         base,
+		Rate:               teamRate,
+		Type:               shared.CommissionTypeOverride.String(),
+		SourceCommissionID: &sourceID,
+	})
+}
@@ -0,0 +1,108 @@
+package commission
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+)
+
+// Domain errors for RateChange entity
+var (
+	ErrRateChangeNotFound = errors.New("rate change not found")
+	ErrInvalidRateChange  = errors.New("invalid rate change data")
+	ErrRateChangeInPast   = errors.New("rate change cannot take effect in the past")
+	ErrRateChangeInEffect = errors.New("rate change is already in effect")
+)
+
+// RateScope is what a rate change applies to
+type RateScope string
+
+// Rate change scopes
+const (
+	RateScopeAgent RateScope = "agent" // An agent's base rate
+	RateScopeTeam  RateScope = "team"  // A team leader's override rate
+)
+
+// IsValid checks if the scope is known
+func (s RateScope) IsValid() bool {
+	return s == RateScopeAgent || s == RateScopeTeam
+}
+
+// RateChange is an agent's base rate or a team's override rate from a
+// point in time on. It stays in effect until the next change for the same
+// agent or team, so commissions can be calculated at the rate that was
+// valid when the order was placed.
+type RateChange struct {
+	id            uint
+	scope         RateScope
+	subjectID     uint
+	rate          shared.CommissionRate
+	effectiveFrom time.Time
+	createdAt     time.Time
+}
+
+// RateChangeParams contains the stored state of a RateChange.
+type RateChangeParams struct {
+	ID            uint
+	Scope         string
+	SubjectID     uint // Agent or team ID, depending on the scope
+	Rate          float64
+	EffectiveFrom time.Time
+	CreatedAt     time.Time
+}
+
+// NewRateChange creates a rate change for an agent or team. A zero
+// effectiveFrom takes effect immediately.
+func NewRateChange(scope RateScope, subjectID uint, rate float64, effectiveFrom time.Time) (*RateChange, error) {
+	if !scope.IsValid() || subjectID == 0 {
+		return nil, ErrInvalidRateChange
+	}
+	r, err := shared.NewCommissionRate(rate)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if effectiveFrom.IsZero() {
+		effectiveFrom = now
+	}
+	return &RateChange{
+		scope:         scope,
+		subjectID:     subjectID,
+		rate:          r,
+		effectiveFrom: effectiveFrom,
+		createdAt:     now,
+	}, nil
+}
+
+// ReconstituteRateChange rebuilds a RateChange from stored state.
+func ReconstituteRateChange(params RateChangeParams) *RateChange {
+	rate, _ := shared.NewCommissionRate(params.Rate)
+	return &RateChange{
+		id:            params.ID,
+		scope:         RateScope(params.Scope),
+		subjectID:     params.SubjectID,
+		rate:          rate,
+		effectiveFrom: params.EffectiveFrom,
+		createdAt:     params.CreatedAt,
+	}
+}
+
+// Getters
+func (c *RateChange) ID() uint                 { return c.id }
+func (c *RateChange) Scope() RateScope         { return c.scope }
+func (c *RateChange) SubjectID() uint          { return c.subjectID }
+func (c *RateChange) Rate() float64            { return c.rate.Value() }
+func (c *RateChange) EffectiveFrom() time.Time { return c.effectiveFrom }
+func (c *RateChange) CreatedAt() time.Time     { return c.createdAt }
+
+// SetID records the ID assigned by the store on first save.
+func (c *RateChange) SetID(id uint) {
+	c.id = id
+}
+
+// IsScheduled returns true if the change has not taken effect yet.
+func (c *RateChange) IsScheduled(now time.Time) bool {
+	return c.effectiveFrom.After(now)
+}
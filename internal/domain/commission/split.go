@@ -0,0 +1,53 @@
+package commission
+
+import (
+	"errors"
+	"math"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+)
+
+// ErrInvalidSplit is returned for split rules that do not add up.
+var ErrInvalidSplit = errors.New("invalid commission split")
+
+// FullShare is the share of an order held by an agent who sold it alone.
+const FullShare = 100.0
+
+// Split is one agent's percentage share of a co-sold order.
+type Split struct {
+	AgentID uint
+	Share   float64 // Percentage of the order, 0-100
+}
+
+// ValidateSplits checks that every agent appears once with a positive
+// share and that the shares sum to 100.
+func ValidateSplits(splits []Split) error {
+	if len(splits) == 0 {
+		return ErrInvalidSplit
+	}
+
+	seen := make(map[uint]bool, len(splits))
+	var total float64
+	for _, s := range splits {
+		if s.AgentID == 0 || s.Share <= 0 || s.Share > FullShare || seen[s.AgentID] {
+			return ErrInvalidSplit
+		}
+		seen[s.AgentID] = true
+		total += s.Share
+	}
+	if math.Abs(total-FullShare) > 0.001 {
+		return ErrInvalidSplit
+	}
+	return nil
+}
+
+// Allocate divides an amount between the splits by share. The parts are
+// rounded so that they always add up to the amount, with leftover minor
+// units going to the largest remainders.
+func Allocate(amount shared.Money, splits []Split) []shared.Money {
+	weights := make([]int64, len(splits))
+	for i, s := range splits {
+		weights[i] = int64(math.Round(s.Share * 1_000_000))
+	}
+	return amount.Allocate(weights)
+}
@@ -0,0 +1,63 @@
+package commission
+
+import (
+	"testing"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+)
+
+func TestAllocateSplitsSumToAmount(t *testing.T) {
+	tests := []struct {
+		name   string
+		splits []Split
+	}{
+		{"alone", []Split{{AgentID: 1, Share: 100}}},
+		{"halves", []Split{{AgentID: 1, Share: 50}, {AgentID: 2, Share: 50}}},
+		{"thirds", []Split{{AgentID: 1, Share: 33.33}, {AgentID: 2, Share: 33.33}, {AgentID: 3, Share: 33.34}}},
+		{"uneven", []Split{{AgentID: 1, Share: 62.5}, {AgentID: 2, Share: 25}, {AgentID: 3, Share: 12.5}}},
+		{"small share", []Split{{AgentID: 1, Share: 99.9}, {AgentID: 2, Share: 0.1}}},
+	}
+	amounts := []int64{0, 1, 2, 3, 99, 100, 101, 12345, 100_000_001}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateSplits(tt.splits); err != nil {
+				t.Fatalf("ValidateSplits: %v", err)
+			}
+			for _, minor := range amounts {
+				amount := shared.NewMoney(minor, "MYR")
+				parts := Allocate(amount, tt.splits)
+				if len(parts) != len(tt.splits) {
+					t.Fatalf("got %d parts, want %d", len(parts), len(tt.splits))
+				}
+				if sum := shared.SumMoney(parts...); sum.Cmp(amount) != 0 {
+					t.Errorf("Allocate(%s) sums to %s", amount, sum)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateSplits(t *testing.T) {
+	tests := []struct {
+		name   string
+		splits []Split
+		valid  bool
+	}{
+		{"full", []Split{{AgentID: 1, Share: 100}}, true},
+		{"thirds", []Split{{AgentID: 1, Share: 33.33}, {AgentID: 2, Share: 33.33}, {AgentID: 3, Share: 33.34}}, true},
+		{"none", nil, false},
+		{"short", []Split{{AgentID: 1, Share: 60}, {AgentID: 2, Share: 30}}, false},
+		{"over", []Split{{AgentID: 1, Share: 60}, {AgentID: 2, Share: 50}}, false},
+		{"repeated agent", []Split{{AgentID: 1, Share: 50}, {AgentID: 1, Share: 50}}, false},
+		{"no agent", []Split{{AgentID: 0, Share: 100}}, false},
+		{"zero share", []Split{{AgentID: 1, Share: 100}, {AgentID: 2, Share: 0}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSplits(tt.splits)
+			if (err == nil) != tt.valid {
+				t.Errorf("ValidateSplits() error = %v, want valid %v", err, tt.valid)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package dispute
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+)
+
+// Domain errors for Dispute aggregate
+var (
+	ErrDisputeNotFound = errors.New("dispute not found")
+	ErrInvalidDispute  = errors.New("invalid dispute data")
+	ErrDisputeClosed   = errors.New("dispute is closed")
+	ErrEmptyMessage    = errors.New("message is required")
+)
+
+// Authors of messages in a dispute thread
+const (
+	AuthorAgent = "agent"
+	AuthorAdmin = "admin"
+)
+
+// Dispute is an agent's claim that a commission is missing or wrong. It is
+// raised against an order, or against one of the agent's commissions, and
+// carries the thread of messages between the agent and the admins.
+type Dispute struct {
+	id           uint
+	agentID      uint
+	orderID      string
+	commissionID *uint
+	status       shared.DisputeStatus
+	resolution   string
+	resolvedBy   string
+	resolvedAt   *time.Time
+	createdAt    time.Time
+	updatedAt    time.Time
+
+	// adjustmentCommissionID is the commission granted on resolution, if any
+	adjustmentCommissionID *uint
+
+	messages []Message
+}
+
+// DisputeParams contains parameters for opening a Dispute.
+type DisputeParams struct {
+	ID           uint
+	AgentID      uint
+	OrderID      string
+	CommissionID *uint
+	Messages     []Message
+
+	// Stored state, only read by Reconstitute.
+	Status                 string
+	Resolution             string
+	ResolvedBy             string
+	ResolvedAt             *time.Time
+	AdjustmentCommissionID *uint
+	CreatedAt              time.Time
+	UpdatedAt              time.Time
+}
+
+// Open opens a dispute for an agent with the note explaining it. The
+// dispute must name the order, and the commission when there is one.
+func Open(params DisputeParams, note string) (*Dispute, error) {
+	if params.AgentID == 0 || params.OrderID == "" {
+		return nil, ErrInvalidDispute
+	}
+
+	now := time.Now()
+	d := &Dispute{
+		id:           params.ID,
+		agentID:      params.AgentID,
+		orderID:      params.OrderID,
+		commissionID: params.CommissionID,
+		status:       shared.DisputeOpen,
+		createdAt:    now,
+		updatedAt:    now,
+	}
+	if err := d.AddMessage(AuthorAgent, "", note); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Reconstitute rebuilds a Dispute from stored state.
+func Reconstitute(params DisputeParams) *Dispute {
+	return &Dispute{
+		id:                     params.ID,
+		agentID:                params.AgentID,
+		orderID:                params.OrderID,
+		commissionID:           params.CommissionID,
+		status:                 shared.DisputeStatus(params.Status),
+		resolution:             params.Resolution,
+		resolvedBy:             params.ResolvedBy,
+		resolvedAt:             params.ResolvedAt,
+		adjustmentCommissionID: params.AdjustmentCommissionID,
+		createdAt:              params.CreatedAt,
+		updatedAt:              params.UpdatedAt,
+		messages:               params.Messages,
+	}
+}
+
+// Getters
+func (d *Dispute) ID() uint                      { return d.id }
+func (d *Dispute) AgentID() uint                 { return d.agentID }
+func (d *Dispute) OrderID() string               { return d.orderID }
+func (d *Dispute) CommissionID() *uint           { return d.commissionID }
+func (d *Dispute) Status() shared.DisputeStatus  { return d.status }
+func (d *Dispute) Resolution() string            { return d.resolution }
+func (d *Dispute) ResolvedBy() string            { return d.resolvedBy }
+func (d *Dispute) ResolvedAt() *time.Time        { return d.resolvedAt }
+func (d *Dispute) AdjustmentCommissionID() *uint { return d.adjustmentCommissionID }
+func (d *Dispute) CreatedAt() time.Time          { return d.createdAt }
+func (d *Dispute) UpdatedAt() time.Time          { return d.updatedAt }
+func (d *Dispute) Messages() []Message           { return d.messages }
+
+// IsActive returns true if the dispute is waiting for a decision.
+func (d *Dispute) IsActive() bool {
+	return d.status.IsActive()
+}
+
+// --- Behavior Methods ---
+
+// SetID records the ID assigned by the store on first save.
+func (d *Dispute) SetID(id uint) {
+	d.id = id
+}
+
+// SetMessageID records the ID assigned by the store to the message at the
+// given position in the thread.
+func (d *Dispute) SetMessageID(i int, id uint) {
+	d.messages[i] = d.messages[i].WithID(id)
+}
+
+// AddMessage adds a message to the thread. The author ID identifies the
+// admin; agents are identified by the dispute. Closed disputes take no more
+// messages.
+func (d *Dispute) AddMessage(author, authorID, body string) error {
+	if !d.IsActive() {
+		return ErrDisputeClosed
+	}
+	if body == "" {
+		return ErrEmptyMessage
+	}
+	if author != AuthorAgent && author != AuthorAdmin {
+		return ErrInvalidDispute
+	}
+	now := time.Now()
+	d.messages = append(d.messages, NewMessage(author, authorID, body, now))
+	d.updatedAt = now
+	return nil
+}
+
+// StartReview marks the dispute as picked up by an admin.
+func (d *Dispute) StartReview() error {
+	return d.transitionTo(shared.DisputeUnderReview)
+}
+
+// Resolve closes the dispute in the agent's favour. The resolution is
+// added to the thread, with the adjustment commission granted, if any.
+func (d *Dispute) Resolve(resolution, actor string, adjustmentCommissionID *uint) error {
+	return d.close(shared.DisputeResolved, resolution, actor, adjustmentCommissionID)
+}
+
+// Reject closes the dispute without a change to the agent's commissions.
+// The reason is added to the thread.
+func (d *Dispute) Reject(reason, actor string) error {
+	return d.close(shared.DisputeRejected, reason, actor, nil)
+}
+
+// close adds the decision to the thread and closes the dispute.
+func (d *Dispute) close(target shared.DisputeStatus, resolution, actor string, adjustmentCommissionID *uint) error {
+	if resolution == "" {
+		return ErrEmptyMessage
+	}
+	status, err := d.status.TransitionTo(target)
+	if err != nil {
+		return err
+	}
+	if err := d.AddMessage(AuthorAdmin, actor, resolution); err != nil {
+		return err
+	}
+	now := d.updatedAt
+	d.status = status
+	d.resolution = resolution
+	d.resolvedBy = actor
+	d.resolvedAt = &now
+	d.adjustmentCommissionID = adjustmentCommissionID
+	return nil
+}
+
+// transitionTo moves the dispute to the target status if the current
+// status allows it.
+func (d *Dispute) transitionTo(target shared.DisputeStatus) error {
+	status, err := d.status.TransitionTo(target)
+	if err != nil {
+		return err
+	}
+	d.status = status
+	d.updatedAt = time.Now()
+	return nil
+}
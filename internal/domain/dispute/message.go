@@ -0,0 +1,52 @@
+package dispute
+
+import "time"
+
+// Message is a note in a dispute thread from the agent or an admin.
+// This is a value object - immutable once created.
+type Message struct {
+	id        uint
+	author    string
+	authorID  string
+	body      string
+	createdAt time.Time
+}
+
+// NewMessage creates a new Message.
+func NewMessage(author, authorID, body string, createdAt time.Time) Message {
+	return Message{
+		author:    author,
+		authorID:  authorID,
+		body:      body,
+		createdAt: createdAt,
+	}
+}
+
+// ReconstituteMessage rebuilds a stored Message.
+func ReconstituteMessage(id uint, author, authorID, body string, createdAt time.Time) Message {
+	return Message{
+		id:        id,
+		author:    author,
+		authorID:  authorID,
+		body:      body,
+		createdAt: createdAt,
+	}
+}
+
+// Getters
+func (m Message) ID() uint             { return m.id }
+func (m Message) Author() string       { return m.author }
+func (m Message) AuthorID() string     { return m.authorID }
+func (m Message) Body() string         { return m.body }
+func (m Message) CreatedAt() time.Time { return m.createdAt }
+
+// IsNew returns true if the message has not been stored yet.
+func (m Message) IsNew() bool {
+	return m.id == 0
+}
+
+// WithID returns the message with the ID assigned by the store.
+func (m Message) WithID(id uint) Message {
+	m.id = id
+	return m
+}
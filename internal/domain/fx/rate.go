@@ -0,0 +1,80 @@
+package fx
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+)
+
+// Domain errors for Rate entity
+var (
+	ErrRateNotFound = errors.New("no exchange rate for currency pair")
+	ErrInvalidRate  = errors.New("invalid exchange rate data")
+)
+
+// Rate is the exchange rate between two currencies from a point in time
+// on. It stays in effect until the next rate for the same pair, so
+// amounts can be converted at the rate that applied when they were earned.
+type Rate struct {
+	id            uint
+	rate          shared.ExchangeRate
+	effectiveFrom time.Time
+	createdAt     time.Time
+}
+
+// RateParams contains the stored state of a Rate.
+type RateParams struct {
+	ID            uint
+	BaseCurrency  string
+	QuoteCurrency string
+	Rate          string // Units of the quote currency per unit of the base currency
+	EffectiveFrom time.Time
+	CreatedAt     time.Time
+}
+
+// NewRate creates an exchange rate between two different currencies. A
+// zero effectiveFrom takes effect immediately.
+func NewRate(base, quote, rate string, effectiveFrom time.Time) (*Rate, error) {
+	r, err := shared.NewExchangeRate(base, quote, rate)
+	if err != nil {
+		return nil, err
+	}
+	if r.IsIdentity() {
+		return nil, ErrInvalidRate
+	}
+
+	now := time.Now()
+	if effectiveFrom.IsZero() {
+		effectiveFrom = now
+	}
+	return &Rate{
+		rate:          r,
+		effectiveFrom: effectiveFrom,
+		createdAt:     now,
+	}, nil
+}
+
+// ReconstituteRate rebuilds a Rate from stored state.
+func ReconstituteRate(params RateParams) *Rate {
+	rate, _ := shared.NewExchangeRate(params.BaseCurrency, params.QuoteCurrency, params.Rate)
+	return &Rate{
+		id:            params.ID,
+		rate:          rate,
+		effectiveFrom: params.EffectiveFrom,
+		createdAt:     params.CreatedAt,
+	}
+}
+
+// Getters
+func (r *Rate) ID() uint                          { return r.id }
+func (r *Rate) BaseCurrency() string              { return r.rate.Base() }
+func (r *Rate) QuoteCurrency() string             { return r.rate.Quote() }
+func (r *Rate) ExchangeRate() shared.ExchangeRate { return r.rate }
+func (r *Rate) EffectiveFrom() time.Time          { return r.effectiveFrom }
+func (r *Rate) CreatedAt() time.Time              { return r.createdAt }
+
+// SetID records the ID assigned by the store on first save.
+func (r *Rate) SetID(id uint) {
+	r.id = id
+}
@@ -0,0 +1,44 @@
+package ledger
+
+import "github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+
+// Balance is an account's balance in one currency, derived from the sum of
+// its lines and shown on the account's normal side: positive is money owed
+// to the agent for their own accounts, or owed by them for clawbacks.
+type Balance struct {
+	Account shared.LedgerAccount
+	Amount  shared.Money
+}
+
+// NewBalance creates a balance from the signed sum of an account's lines.
+func NewBalance(account shared.LedgerAccount, sum shared.Money) Balance {
+	if !account.IsDebitNormal() {
+		sum = sum.Neg()
+	}
+	return Balance{Account: account, Amount: sum}
+}
+
+// Balances are an agent's account balances.
+type Balances []Balance
+
+// Of returns an account's balance in a currency, zero if it has none.
+func (b Balances) Of(account shared.LedgerAccount, currency string) shared.Money {
+	for _, balance := range b {
+		if balance.Account == account && balance.Amount.Currency() == currency {
+			return balance.Amount
+		}
+	}
+	return shared.ZeroMoney(currency)
+}
+
+// Earned returns what the agent has earned in a currency: approved
+// commissions, whether in a payout, paid out, withheld as tax, held in
+// reserve or not, less clawbacks still owed.
+func (b Balances) Earned(currency string) shared.Money {
+	return b.Of(shared.LedgerAvailable, currency).
+		Add(b.Of(shared.LedgerInPayout, currency)).
+		Add(b.Of(shared.LedgerPaidOut, currency)).
+		Add(b.Of(shared.LedgerTaxWithheld, currency)).
+		Add(b.Of(shared.LedgerReserve, currency)).
+		Sub(b.Of(shared.LedgerClawbackReceivable, currency))
+}
@@ -0,0 +1,166 @@
+package ledger
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+)
+
+// Domain errors for ledger entries
+var (
+	ErrInvalidEntry    = errors.New("invalid ledger entry")
+	ErrUnbalancedEntry = errors.New("ledger entry debits and credits do not balance")
+)
+
+// Kinds of ledger entry, one per commission or payout transition
+const (
+	KindCommissionCreated    = "commission_created"
+	KindCommissionConverted  = "commission_converted"
+	KindCommissionApproved   = "commission_approved"
+	KindCommissionRejected   = "commission_rejected"
+	KindCommissionCancelled  = "commission_cancelled"
+	KindCommissionClawedBack = "commission_clawed_back"
+	KindPayoutCreated        = "payout_created"
+	KindPayoutCompleted      = "payout_completed"
+	KindPayoutFailed         = "payout_failed"
+	KindPayoutRetried        = "payout_retried"
+	KindPayoutCancelled      = "payout_cancelled"
+	KindOpeningBalance       = "opening_balance"
+)
+
+// Entry is a balanced journal entry in an agent's earnings ledger. Its
+// lines move an amount between the agent's accounts; debits and credits
+// always net to zero. Entries are append-only: a mistake is corrected by
+// a later entry, never by changing one.
+type Entry struct {
+	id           uint
+	agentID      uint
+	kind         string
+	commissionID *uint
+	payoutID     *uint
+	lines        []Line
+	createdAt    time.Time
+}
+
+// EntryParams contains the stored state of an Entry.
+type EntryParams struct {
+	ID           uint
+	AgentID      uint
+	Kind         string
+	CommissionID *uint
+	PayoutID     *uint
+	Lines        []Line
+	CreatedAt    time.Time
+}
+
+// NewEntry creates a balanced entry from its lines, all in one currency.
+// Zero lines are left out.
+func NewEntry(agentID uint, kind string, lines ...Line) (*Entry, error) {
+	if agentID == 0 || kind == "" {
+		return nil, ErrInvalidEntry
+	}
+
+	var total shared.Money
+	kept := make([]Line, 0, len(lines))
+	for _, line := range lines {
+		if !line.account.IsValid() || !total.SameCurrency(line.amount) {
+			return nil, ErrInvalidEntry
+		}
+		if line.amount.IsZero() {
+			continue
+		}
+		total = total.Add(line.amount)
+		kept = append(kept, line)
+	}
+	if len(kept) < 2 {
+		return nil, ErrInvalidEntry
+	}
+	if !total.IsZero() {
+		return nil, ErrUnbalancedEntry
+	}
+
+	return &Entry{
+		agentID:   agentID,
+		kind:      kind,
+		lines:     kept,
+		createdAt: time.Now(),
+	}, nil
+}
+
+// Transfer creates an entry that debits one account and credits another
+// with the same amount. A zero amount needs no entry, so it returns nil.
+func Transfer(agentID uint, kind string, debit, credit shared.LedgerAccount, amount shared.Money) *Entry {
+	if amount.IsZero() {
+		return nil
+	}
+	entry, err := NewEntry(agentID, kind, Debit(debit, amount), Credit(credit, amount))
+	if err != nil {
+		// Two lines of one amount always balance
+		panic(err)
+	}
+	return entry
+}
+
+// Reconstitute rebuilds an Entry from stored state.
+func Reconstitute(params EntryParams) *Entry {
+	return &Entry{
+		id:           params.ID,
+		agentID:      params.AgentID,
+		kind:         params.Kind,
+		commissionID: params.CommissionID,
+		payoutID:     params.PayoutID,
+		lines:        params.Lines,
+		createdAt:    params.CreatedAt,
+	}
+}
+
+// Getters
+func (e *Entry) ID() uint             { return e.id }
+func (e *Entry) AgentID() uint        { return e.agentID }
+func (e *Entry) Kind() string         { return e.kind }
+func (e *Entry) CommissionID() *uint  { return e.commissionID }
+func (e *Entry) PayoutID() *uint      { return e.payoutID }
+func (e *Entry) Lines() []Line        { return e.lines }
+func (e *Entry) CreatedAt() time.Time { return e.createdAt }
+
+// Currency returns the currency of the entry's lines.
+func (e *Entry) Currency() string {
+	if len(e.lines) == 0 {
+		return shared.DefaultCurrency
+	}
+	return e.lines[0].amount.Currency()
+}
+
+// IsBalanced returns true if the entry's debits and credits net to zero.
+func (e *Entry) IsBalanced() bool {
+	var total shared.Money
+	for _, line := range e.lines {
+		if !total.SameCurrency(line.amount) {
+			return false
+		}
+		total = total.Add(line.amount)
+	}
+	return total.IsZero()
+}
+
+// SetID records the ID assigned by the store on first save.
+func (e *Entry) SetID(id uint) {
+	e.id = id
+}
+
+// ForCommission links the entry to the commission it records, once the
+// commission has its ID.
+func (e *Entry) ForCommission(id uint) {
+	if e.commissionID == nil && id != 0 {
+		e.commissionID = &id
+	}
+}
+
+// ForPayout links the entry to the payout it records, once the payout has
+// its ID.
+func (e *Entry) ForPayout(id uint) {
+	if e.payoutID == nil && id != 0 {
+		e.payoutID = &id
+	}
+}
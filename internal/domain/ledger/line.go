@@ -0,0 +1,41 @@
+package ledger
+
+import "github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+
+// Line is one side of a ledger entry: an amount debited or credited to an
+// account. Debits are held as positive amounts and credits as negative.
+// This is a value object - immutable once created.
+type Line struct {
+	account shared.LedgerAccount
+	amount  shared.Money
+}
+
+// Debit creates a line debiting an account.
+func Debit(account shared.LedgerAccount, amount shared.Money) Line {
+	return Line{account: account, amount: amount}
+}
+
+// Credit creates a line crediting an account.
+func Credit(account shared.LedgerAccount, amount shared.Money) Line {
+	return Line{account: account, amount: amount.Neg()}
+}
+
+// ReconstituteLine rebuilds a stored line from its signed amount.
+func ReconstituteLine(account shared.LedgerAccount, amount shared.Money) Line {
+	return Line{account: account, amount: amount}
+}
+
+// Getters
+func (l Line) Account() shared.LedgerAccount { return l.account }
+func (l Line) Amount() shared.Money          { return l.amount }
+
+// Reversed returns the line moving the same amount the other way, to
+// reverse an earlier entry.
+func (l Line) Reversed() Line {
+	return Line{account: l.account, amount: l.amount.Neg()}
+}
+
+// IsDebit returns true if the line debits its account.
+func (l Line) IsDebit() bool {
+	return l.amount.IsPositive()
+}
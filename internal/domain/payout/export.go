@@ -0,0 +1,87 @@
+package payout
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrExportNotFound is returned when a payment file export does not exist
+var ErrExportNotFound = errors.New("payment file export not found")
+
+// Export is a bank payment file written for a payout run. The file is
+// kept as it was handed out, so it can be downloaded again and checked
+// against the checksum the bank was given.
+type Export struct {
+	id          uint
+	runID       uint
+	format      string
+	fileName    string
+	contentType string
+	content     []byte
+	checksum    string
+	count       int
+	exportedBy  string
+	createdAt   time.Time
+}
+
+// ExportParams contains parameters for creating an Export.
+type ExportParams struct {
+	ID          uint
+	RunID       uint
+	Format      string
+	FileName    string
+	ContentType string
+	Content     []byte
+	Checksum    string // Hex SHA-256 of the content
+	Count       int    // Payments in the file
+	ExportedBy  string
+
+	// Stored state, only read by ReconstituteExport.
+	CreatedAt time.Time
+}
+
+// NewExport records a payment file written for a run.
+func NewExport(params ExportParams) (*Export, error) {
+	if params.RunID == 0 {
+		return nil, errors.New("payout run ID is required")
+	}
+	if params.Format == "" || params.Checksum == "" || len(params.Content) == 0 {
+		return nil, errors.New("export format, checksum and content are required")
+	}
+	e := ReconstituteExport(params)
+	e.createdAt = time.Now()
+	return e, nil
+}
+
+// ReconstituteExport rebuilds an Export from stored state.
+func ReconstituteExport(params ExportParams) *Export {
+	return &Export{
+		id:          params.ID,
+		runID:       params.RunID,
+		format:      params.Format,
+		fileName:    params.FileName,
+		contentType: params.ContentType,
+		content:     params.Content,
+		checksum:    params.Checksum,
+		count:       params.Count,
+		exportedBy:  params.ExportedBy,
+		createdAt:   params.CreatedAt,
+	}
+}
+
+// Getters
+func (e *Export) ID() uint             { return e.id }
+func (e *Export) RunID() uint          { return e.runID }
+func (e *Export) Format() string       { return e.format }
+func (e *Export) FileName() string     { return e.fileName }
+func (e *Export) ContentType() string  { return e.contentType }
+func (e *Export) Content() []byte      { return e.content }
+func (e *Export) Checksum() string     { return e.checksum }
+func (e *Export) Count() int           { return e.count }
+func (e *Export) ExportedBy() string   { return e.exportedBy }
+func (e *Export) CreatedAt() time.Time { return e.createdAt }
+
+// SetID sets the ID (used by repository after insert).
+func (e *Export) SetID(id uint) {
+	e.id = id
+}
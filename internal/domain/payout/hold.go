@@ -0,0 +1,148 @@
+package payout
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Domain errors for the Hold aggregate
+var (
+	ErrHoldNotFound = errors.New("payout hold not found")
+	ErrInvalidHold  = errors.New("invalid payout hold")
+	ErrHoldInactive = errors.New("payout hold has already expired or been released")
+)
+
+// Hold status values. A hold's status is derived from its release and
+// expiry rather than stored.
+const (
+	HoldActive   = "active"
+	HoldReleased = "released"
+	HoldExpired  = "expired"
+)
+
+// Hold is the aggregate root for a payout hold an admin places on a risky
+// agent. While it is active no payout is created for the agent, their
+// balance is carried forward in payout runs and their pending payouts and
+// reserves stay where they are. A hold ends when it expires or an admin
+// releases it.
+type Hold struct {
+	id         uint
+	agentID    uint
+	reason     string
+	expiresAt  time.Time
+	createdBy  string
+	releasedBy string
+	releasedAt *time.Time
+	createdAt  time.Time
+	updatedAt  time.Time
+}
+
+// HoldParams contains parameters for creating a Hold.
+type HoldParams struct {
+	ID        uint
+	AgentID   uint
+	Reason    string
+	ExpiresAt time.Time
+	CreatedBy string
+
+	// Stored state, only read by ReconstituteHold.
+	ReleasedBy string
+	ReleasedAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// NewHold places a hold on an agent's payouts until it expires.
+func NewHold(params HoldParams) (*Hold, error) {
+	if params.AgentID == 0 {
+		return nil, fmt.Errorf("%w: agent ID is required", ErrInvalidHold)
+	}
+	reason := strings.TrimSpace(params.Reason)
+	if reason == "" {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidHold, ErrReasonRequired)
+	}
+	now := time.Now()
+	if !params.ExpiresAt.After(now) {
+		return nil, fmt.Errorf("%w: expiry must be in the future", ErrInvalidHold)
+	}
+
+	return &Hold{
+		id:        params.ID,
+		agentID:   params.AgentID,
+		reason:    reason,
+		expiresAt: params.ExpiresAt,
+		createdBy: params.CreatedBy,
+		createdAt: now,
+		updatedAt: now,
+	}, nil
+}
+
+// ReconstituteHold rebuilds a Hold from stored state.
+func ReconstituteHold(params HoldParams) *Hold {
+	return &Hold{
+		id:         params.ID,
+		agentID:    params.AgentID,
+		reason:     params.Reason,
+		expiresAt:  params.ExpiresAt,
+		createdBy:  params.CreatedBy,
+		releasedBy: params.ReleasedBy,
+		releasedAt: params.ReleasedAt,
+		createdAt:  params.CreatedAt,
+		updatedAt:  params.UpdatedAt,
+	}
+}
+
+// Getters
+func (h *Hold) ID() uint               { return h.id }
+func (h *Hold) AgentID() uint          { return h.agentID }
+func (h *Hold) Reason() string         { return h.reason }
+func (h *Hold) ExpiresAt() time.Time   { return h.expiresAt }
+func (h *Hold) CreatedBy() string      { return h.createdBy }
+func (h *Hold) ReleasedBy() string     { return h.releasedBy }
+func (h *Hold) ReleasedAt() *time.Time { return h.releasedAt }
+func (h *Hold) CreatedAt() time.Time   { return h.createdAt }
+func (h *Hold) UpdatedAt() time.Time   { return h.updatedAt }
+
+// IsActive returns true if the hold applies at the given time.
+func (h *Hold) IsActive(now time.Time) bool {
+	return h.releasedAt == nil && now.Before(h.expiresAt)
+}
+
+// Status returns whether the hold is active, released or expired at the
+// given time.
+func (h *Hold) Status(now time.Time) string {
+	switch {
+	case h.releasedAt != nil:
+		return HoldReleased
+	case !now.Before(h.expiresAt):
+		return HoldExpired
+	default:
+		return HoldActive
+	}
+}
+
+// Err returns an error wrapping ErrPayoutOnHold that says why and until
+// when the agent's payouts are held.
+func (h *Hold) Err() error {
+	return fmt.Errorf("%w: %s, held until %s",
+		ErrPayoutOnHold, h.reason, h.expiresAt.UTC().Format(time.RFC3339))
+}
+
+// SetID sets the ID (used by repository after insert).
+func (h *Hold) SetID(id uint) {
+	h.id = id
+}
+
+// Release ends the hold before it expires.
+func (h *Hold) Release(actor string) error {
+	now := time.Now()
+	if !h.IsActive(now) {
+		return ErrHoldInactive
+	}
+	h.releasedBy = actor
+	h.releasedAt = &now
+	h.updatedAt = now
+	return nil
+}
@@ -0,0 +1,310 @@
+package payout
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+)
+
+// Domain errors for the Method aggregate
+var (
+	ErrMethodNotFound     = errors.New("payout method not found")
+	ErrInvalidMethod      = errors.New("invalid payout method data")
+	ErrMethodNotVerified  = errors.New("payout method is not verified")
+	ErrMethodVerified     = errors.New("payout method is already verified")
+	ErrVerificationCode   = errors.New("verification code is wrong or has expired")
+	ErrVerificationLocked = errors.New("too many wrong verification codes; request a new code")
+	ErrDefaultMethod      = errors.New("make another payout method the default before removing this one")
+	ErrNoPayoutMethod     = errors.New("agent has no verified default payout method")
+	ErrPayoutOnHold       = errors.New("payout is on hold")
+)
+
+// MaxVerificationAttempts is how many wrong codes may be entered before a
+// new code has to be requested.
+const MaxVerificationAttempts = 5
+
+// accountNumberPattern accepts account numbers, IBANs, wallet IDs and
+// phone numbers once spaces and dashes are removed.
+var accountNumberPattern = regexp.MustCompile(`^\+?[A-Za-z0-9]{4,34}$`)
+
+// Method is the aggregate root for an account an agent is paid into: a
+// bank account or an e-wallet. An agent's payouts go to their default
+// method, once it is verified.
+//
+// Every change to where payouts go, adding a method, changing its details
+// or making it the default, restarts a cooldown on it, during which
+// payouts to it are held.
+type Method struct {
+	id            uint
+	agentID       uint
+	methodType    shared.PayoutMethodType
+	provider      string // Bank or e-wallet provider name
+	providerCode  string // Bank BIC or e-wallet provider code
+	accountName   string
+	accountNumber string
+	isDefault     bool
+	status        shared.PayoutMethodStatus
+
+	// verificationHash is the hex SHA-256 of the outstanding verification
+	// code; the code itself is never stored
+	verificationHash      string
+	verificationExpiresAt *time.Time
+	verificationAttempts  int
+
+	verifiedAt *time.Time
+	changedAt  time.Time
+	createdAt  time.Time
+	updatedAt  time.Time
+
+	// version counts the method's saves, so a save made from a stale copy,
+	// such as a second wrong code entered at the same time, is refused
+	version int
+}
+
+// MethodParams contains parameters for creating a Method.
+type MethodParams struct {
+	ID            uint
+	AgentID       uint
+	Type          string
+	Provider      string
+	ProviderCode  string
+	AccountName   string
+	AccountNumber string
+	IsDefault     bool
+
+	// Stored state, only read by ReconstituteMethod.
+	Status                string
+	VerificationHash      string
+	VerificationExpiresAt *time.Time
+	VerificationAttempts  int
+	VerifiedAt            *time.Time
+	ChangedAt             time.Time
+	Version               int
+	CreatedAt             time.Time
+	UpdatedAt             time.Time
+}
+
+// NewMethod creates an unverified payout method.
+func NewMethod(params MethodParams) (*Method, error) {
+	if params.AgentID == 0 {
+		return nil, errors.New("agent ID is required")
+	}
+	methodType, err := shared.ParsePayoutMethodType(params.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	m := &Method{
+		id:         params.ID,
+		agentID:    params.AgentID,
+		methodType: methodType,
+		isDefault:  params.IsDefault,
+		status:     shared.PayoutMethodUnverified,
+		changedAt:  now,
+		createdAt:  now,
+		updatedAt:  now,
+	}
+	if err := m.setDetails(params.Provider, params.ProviderCode, params.AccountName, params.AccountNumber); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ReconstituteMethod rebuilds a Method from stored state.
+func ReconstituteMethod(params MethodParams) *Method {
+	return &Method{
+		id:                    params.ID,
+		agentID:               params.AgentID,
+		methodType:            shared.PayoutMethodType(params.Type),
+		provider:              params.Provider,
+		providerCode:          params.ProviderCode,
+		accountName:           params.AccountName,
+		accountNumber:         params.AccountNumber,
+		isDefault:             params.IsDefault,
+		status:                shared.PayoutMethodStatus(params.Status),
+		verificationHash:      params.VerificationHash,
+		verificationExpiresAt: params.VerificationExpiresAt,
+		verificationAttempts:  params.VerificationAttempts,
+		verifiedAt:            params.VerifiedAt,
+		changedAt:             params.ChangedAt,
+		version:               params.Version,
+		createdAt:             params.CreatedAt,
+		updatedAt:             params.UpdatedAt,
+	}
+}
+
+// Getters
+func (m *Method) ID() uint                          { return m.id }
+func (m *Method) AgentID() uint                     { return m.agentID }
+func (m *Method) Type() shared.PayoutMethodType     { return m.methodType }
+func (m *Method) Provider() string                  { return m.provider }
+func (m *Method) ProviderCode() string              { return m.providerCode }
+func (m *Method) AccountName() string               { return m.accountName }
+func (m *Method) AccountNumber() string             { return m.accountNumber }
+func (m *Method) IsDefault() bool                   { return m.isDefault }
+func (m *Method) Status() shared.PayoutMethodStatus { return m.status }
+func (m *Method) VerificationHash() string          { return m.verificationHash }
+func (m *Method) VerificationExpiresAt() *time.Time { return m.verificationExpiresAt }
+func (m *Method) VerificationAttempts() int         { return m.verificationAttempts }
+func (m *Method) VerifiedAt() *time.Time            { return m.verifiedAt }
+func (m *Method) ChangedAt() time.Time              { return m.changedAt }
+func (m *Method) CreatedAt() time.Time              { return m.createdAt }
+func (m *Method) UpdatedAt() time.Time              { return m.updatedAt }
+func (m *Method) Version() int                      { return m.version }
+
+// IsVerified returns true if the agent has verified the method's details.
+func (m *Method) IsVerified() bool {
+	return m.status == shared.PayoutMethodVerified
+}
+
+// MaskedAccountNumber returns the account number with all but its last
+// four characters hidden.
+func (m *Method) MaskedAccountNumber() string {
+	if len(m.accountNumber) <= 4 {
+		return strings.Repeat("*", len(m.accountNumber))
+	}
+	return "****" + m.accountNumber[len(m.accountNumber)-4:]
+}
+
+// CooldownUntil returns when the cooldown after the method's last change
+// ends.
+func (m *Method) CooldownUntil(cooldown time.Duration) time.Time {
+	return m.changedAt.Add(cooldown)
+}
+
+// InCooldown returns true if the method changed less than cooldown ago.
+func (m *Method) InCooldown(cooldown time.Duration, now time.Time) bool {
+	return now.Before(m.CooldownUntil(cooldown))
+}
+
+// UpdateDetails changes where the method pays. The method must be
+// verified again and its cooldown restarts.
+func (m *Method) UpdateDetails(provider, providerCode, accountName, accountNumber string) error {
+	if err := m.setDetails(provider, providerCode, accountName, accountNumber); err != nil {
+		return err
+	}
+	m.status = shared.PayoutMethodUnverified
+	m.verifiedAt = nil
+	m.clearVerification()
+	m.changedAt = time.Now()
+	m.updatedAt = m.changedAt
+	return nil
+}
+
+// StartVerification records a new verification code, replacing any
+// outstanding one.
+func (m *Method) StartVerification(codeHash string, expiresAt time.Time) error {
+	if m.IsVerified() {
+		return ErrMethodVerified
+	}
+	if codeHash == "" {
+		return errors.New("verification code is required")
+	}
+	m.verificationHash = codeHash
+	m.verificationExpiresAt = &expiresAt
+	m.verificationAttempts = 0
+	m.updatedAt = time.Now()
+	return nil
+}
+
+// Verify checks a verification code and verifies the method if it is the
+// outstanding code. Wrong codes count towards MaxVerificationAttempts.
+func (m *Method) Verify(codeHash string) error {
+	if m.IsVerified() {
+		return ErrMethodVerified
+	}
+	if m.verificationAttempts >= MaxVerificationAttempts {
+		return ErrVerificationLocked
+	}
+
+	now := time.Now()
+	m.updatedAt = now
+	if m.verificationHash == "" || m.verificationExpiresAt == nil || now.After(*m.verificationExpiresAt) ||
+		subtle.ConstantTimeCompare([]byte(codeHash), []byte(m.verificationHash)) != 1 {
+		m.verificationAttempts++
+		return ErrVerificationCode
+	}
+
+	m.status = shared.PayoutMethodVerified
+	m.verifiedAt = &now
+	m.clearVerification()
+	return nil
+}
+
+// MakeDefault makes the method the one payouts go to. Only verified
+// methods can become the default, and the cooldown restarts.
+func (m *Method) MakeDefault() error {
+	if !m.IsVerified() {
+		return ErrMethodNotVerified
+	}
+	if m.isDefault {
+		return nil
+	}
+	m.isDefault = true
+	m.changedAt = time.Now()
+	m.updatedAt = m.changedAt
+	return nil
+}
+
+// ClearDefault records that another method became the default.
+func (m *Method) ClearDefault() {
+	if m.isDefault {
+		m.isDefault = false
+		m.updatedAt = time.Now()
+	}
+}
+
+// CheckPayable returns nil if payouts can be sent to the method now, or
+// an error wrapping ErrPayoutOnHold saying why not.
+func (m *Method) CheckPayable(cooldown time.Duration, now time.Time) error {
+	if !m.IsVerified() {
+		return fmt.Errorf("%w: %w", ErrPayoutOnHold, ErrMethodNotVerified)
+	}
+	if m.InCooldown(cooldown, now) {
+		return fmt.Errorf("%w: payout method changed, held until %s",
+			ErrPayoutOnHold, m.CooldownUntil(cooldown).UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// SetID sets the ID (used by repository after insert).
+func (m *Method) SetID(id uint) {
+	m.id = id
+}
+
+// SetVersion records the version the store saved the method at.
+func (m *Method) SetVersion(version int) {
+	m.version = version
+}
+
+// setDetails validates and sets the method's account details
+func (m *Method) setDetails(provider, providerCode, accountName, accountNumber string) error {
+	provider = strings.TrimSpace(provider)
+	accountName = strings.TrimSpace(accountName)
+	accountNumber = strings.NewReplacer(" ", "", "-", "").Replace(accountNumber)
+	if provider == "" || accountName == "" {
+		return fmt.Errorf("%w: provider and account name are required", ErrInvalidMethod)
+	}
+	if !accountNumberPattern.MatchString(accountNumber) {
+		return fmt.Errorf("%w: account number must be 4 to 34 letters or digits", ErrInvalidMethod)
+	}
+
+	m.provider = provider
+	m.providerCode = strings.ToUpper(strings.TrimSpace(providerCode))
+	m.accountName = accountName
+	m.accountNumber = strings.ToUpper(accountNumber)
+	return nil
+}
+
+// clearVerification discards the outstanding verification code
+func (m *Method) clearVerification() {
+	m.verificationHash = ""
+	m.verificationExpiresAt = nil
+	m.verificationAttempts = 0
+}
@@ -2,30 +2,72 @@ package payout
 
 import (
 	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
 	"time"
 
+	"github.com/Ecom-micro-template/service-agent/internal/domain/ledger"
 	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
 )
 
 // Domain errors for Payout aggregate
 var (
-	ErrPayoutNotFound  = errors.New("payout not found")
-	ErrInvalidPayout   = errors.New("invalid payout data")
-	ErrPayoutCompleted = errors.New("payout already completed")
-	ErrNoCommissions   = errors.New("no commissions to payout")
+	ErrPayoutNotFound     = errors.New("payout not found")
+	ErrInvalidPayout      = errors.New("invalid payout data")
+	ErrPayoutCompleted    = errors.New("payout already completed")
+	ErrNoCommissions      = errors.New("no commissions to payout")
+	ErrNegativePayout     = errors.New("adjustments exceed the commissions to payout")
+	ErrInvalidPeriod      = errors.New("period must be a month in YYYY-MM format")
+	ErrReasonRequired     = errors.New("a reason is required")
+	ErrConversionRequired = errors.New("commissions were converted to the agent's payout currency")
 )
 
+// periodLayout is the format of payout periods.
+const periodLayout = "2006-01"
+
+// ParsePeriod parses a YYYY-MM payout period into the first instant of the
+// month, in UTC.
+func ParsePeriod(period string) (time.Time, error) {
+	start, err := time.Parse(periodLayout, period)
+	if err != nil {
+		return time.Time{}, ErrInvalidPeriod
+	}
+	return start, nil
+}
+
+// FormatPeriod returns the payout period a time falls in.
+func FormatPeriod(t time.Time) string {
+	return t.Format(periodLayout)
+}
+
 // Payout is the aggregate root for agent payouts.
 type Payout struct {
-	id        uint
-	agentID   uint
-	amount    float64
-	period    string // Format: YYYY-MM
-	items     []PayoutItem
-	status    shared.PayoutStatus
-	paidAt    *time.Time
-	createdAt time.Time
-	updatedAt time.Time
+	id             uint
+	agentID        uint
+	amount         shared.Money
+	deductions     shared.Money // Clawbacks netted against this payout
+	withheld       shared.Money // Tax withheld from this payout
+	withholding    float64      // Percentage of the taxable amount withheld
+	reserved       shared.Money // Kept back in reserve from this payout
+	reservePercent float64      // Percentage of the amount after tax reserved
+	released       shared.Money // Earlier reserves this payout releases
+	period         string       // Format: YYYY-MM
+	items          []PayoutItem
+	status         shared.PayoutStatus
+	transactionRef string
+	failureReason  string
+	retries        int // Times the payout was retried after failing
+	paidAt         *time.Time
+	createdAt      time.Time
+	updatedAt      time.Time
+
+	// version counts the payout's saves, so a save made from a stale copy
+	// is refused instead of overwriting a change made since
+	version int
+
+	// Ledger entries for the transitions since the payout was last saved
+	entries []*ledger.Entry
 }
 
 // PayoutParams contains parameters for creating a Payout.
@@ -34,6 +76,37 @@ type PayoutParams struct {
 	AgentID uint
 	Period  string
 	Items   []PayoutItem
+
+	// Deductions is the total of outstanding clawbacks netted against the
+	// payout, as a positive amount. It may not exceed the commission total.
+	Deductions shared.Money
+
+	// WithholdingRate is the percentage of the commissions, less
+	// deductions, withheld as tax from the payout.
+	WithholdingRate float64
+
+	// ReservePercent is the percentage of the amount left after tax kept
+	// back in reserve under the agent's reserve policy.
+	ReservePercent float64
+
+	// Released is the total of earlier reserves paid to the agent by the
+	// payout. A release payout has no items: it pays released reserves
+	// alone, less the clawbacks netted against them, and is not taxed
+	// again.
+	Released shared.Money
+
+	// Stored state, only read by Reconstitute.
+	Withheld       shared.Money
+	Reserved       shared.Money
+	Amount         shared.Money
+	Status         string
+	TransactionRef string
+	FailureReason  string
+	Retries        int
+	PaidAt         *time.Time
+	Version        int
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
 }
 
 // NewPayout creates a new Payout aggregate.
@@ -41,42 +114,212 @@ func NewPayout(params PayoutParams) (*Payout, error) {
 	if params.AgentID == 0 {
 		return nil, errors.New("agent ID is required")
 	}
-	if params.Period == "" {
-		return nil, errors.New("period is required")
+	if _, err := ParsePeriod(params.Period); err != nil {
+		return nil, err
 	}
-	if len(params.Items) == 0 {
+	if len(params.Items) == 0 && !params.Released.IsPositive() {
 		return nil, ErrNoCommissions
 	}
+	if len(params.Items) > 0 && !params.Released.IsZero() {
+		return nil, fmt.Errorf("%w: a payout pays commissions or released reserves, not both", ErrInvalidPayout)
+	}
 
 	// Calculate total amount
-	var amount float64
+	var amount shared.Money
 	for _, item := range params.Items {
-		amount += item.Amount()
+		if !amount.SameCurrency(item.Amount()) {
+			return nil, shared.ErrCurrencyMismatch
+		}
+		amount = amount.Add(item.Amount())
+	}
+	if !amount.SameCurrency(params.Deductions) || !amount.SameCurrency(params.Released) {
+		return nil, shared.ErrCurrencyMismatch
+	}
+	// Debit adjustments are netted against the commissions, but cannot
+	// take the payout below zero
+	if amount.IsNegative() {
+		return nil, ErrNegativePayout
+	}
+	currency := amount.Add(params.Deductions).Add(params.Released).Currency()
+	released := params.Released
+	if released.IsZero() {
+		released = shared.ZeroMoney(currency)
+	}
+	if released.IsNegative() || params.Deductions.IsNegative() || params.Deductions.Cmp(amount.Add(released)) > 0 {
+		return nil, ErrInvalidPayout
+	}
+	if params.WithholdingRate < 0 || params.WithholdingRate > 100 ||
+		params.ReservePercent < 0 || params.ReservePercent > 100 {
+		return nil, ErrInvalidPayout
 	}
 
+	// Tax is withheld from what the agent is owed once the clawbacks are
+	// settled, and the reserve is kept back from what is left. Released
+	// reserves were taxed when they were kept back, so clawbacks netted
+	// against them are all a release payout takes off.
+	taxable := amount.Sub(params.Deductions)
+	withheld := shared.ZeroMoney(currency)
+	reserved := shared.ZeroMoney(currency)
+	if taxable.IsPositive() {
+		withheld = taxable.MulPercent(params.WithholdingRate, shared.RoundHalfUp)
+		reserved = taxable.Sub(withheld).MulPercent(params.ReservePercent, shared.RoundHalfUp)
+	}
+	net := taxable.Sub(withheld).Sub(reserved).Add(released)
+
+	reservePercent := params.ReservePercent
+	if reserved.IsZero() {
+		reservePercent = 0
+	}
 	now := time.Now()
+	p := &Payout{
+		id:             params.ID,
+		agentID:        params.AgentID,
+		amount:         net,
+		deductions:     params.Deductions,
+		withheld:       withheld,
+		withholding:    params.WithholdingRate,
+		reserved:       reserved,
+		reservePercent: reservePercent,
+		released:       released,
+		period:         params.Period,
+		items:          params.Items,
+		status:         shared.PayoutPending,
+		createdAt:      now,
+		updatedAt:      now,
+	}
+	if err := p.post(ledger.KindPayoutCreated, false); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Reconstitute rebuilds a Payout from stored state.
+// The stored amount is kept as-is rather than re-summed from the items.
+func Reconstitute(params PayoutParams) *Payout {
+	orZero := func(m shared.Money) shared.Money {
+		if m.IsZero() {
+			return shared.ZeroMoney(params.Amount.Currency())
+		}
+		return m
+	}
 	return &Payout{
-		id:        params.ID,
-		agentID:   params.AgentID,
-		amount:    amount,
-		period:    params.Period,
-		items:     params.Items,
-		status:    shared.PayoutPending,
-		createdAt: now,
-		updatedAt: now,
-	}, nil
+		id:             params.ID,
+		agentID:        params.AgentID,
+		amount:         params.Amount,
+		deductions:     params.Deductions,
+		withheld:       orZero(params.Withheld),
+		withholding:    params.WithholdingRate,
+		reserved:       orZero(params.Reserved),
+		reservePercent: params.ReservePercent,
+		released:       orZero(params.Released),
+		period:         params.Period,
+		items:          params.Items,
+		status:         shared.PayoutStatus(params.Status),
+		transactionRef: params.TransactionRef,
+		failureReason:  params.FailureReason,
+		retries:        params.Retries,
+		paidAt:         params.PaidAt,
+		version:        params.Version,
+		createdAt:      params.CreatedAt,
+		updatedAt:      params.UpdatedAt,
+	}
 }
 
 // Getters
 func (p *Payout) ID() uint                    { return p.id }
 func (p *Payout) AgentID() uint               { return p.agentID }
-func (p *Payout) Amount() float64             { return p.amount }
+func (p *Payout) Amount() shared.Money        { return p.amount }
+func (p *Payout) Deductions() shared.Money    { return p.deductions }
+func (p *Payout) Withheld() shared.Money      { return p.withheld }
+func (p *Payout) WithholdingRate() float64    { return p.withholding }
+func (p *Payout) Reserved() shared.Money      { return p.reserved }
+func (p *Payout) ReservePercent() float64     { return p.reservePercent }
+func (p *Payout) Released() shared.Money      { return p.released }
 func (p *Payout) Period() string              { return p.period }
 func (p *Payout) Items() []PayoutItem         { return p.items }
 func (p *Payout) Status() shared.PayoutStatus { return p.status }
+func (p *Payout) TransactionRef() string      { return p.transactionRef }
+func (p *Payout) FailureReason() string       { return p.failureReason }
+func (p *Payout) Retries() int                { return p.retries }
 func (p *Payout) PaidAt() *time.Time          { return p.paidAt }
 func (p *Payout) CreatedAt() time.Time        { return p.createdAt }
 func (p *Payout) UpdatedAt() time.Time        { return p.updatedAt }
+func (p *Payout) Version() int                { return p.version }
+
+// Currency returns the currency the payout is made in.
+func (p *Payout) Currency() string {
+	return p.amount.Currency()
+}
+
+// Reference returns the payout's payment reference, sent to the bank with
+// the payment and quoted back on the bank statement.
+func (p *Payout) Reference() string {
+	return fmt.Sprintf("PAYOUT-%08d", p.id)
+}
+
+// TransferReference returns the reference the payout is sent to a payout
+// provider under. Each retry after a failure is a new transfer, so it gets
+// its own reference: the payout reference with the attempt number added,
+// such as PAYOUT-00000042-2. ParseReference reads the payout ID from it.
+func (p *Payout) TransferReference() string {
+	if p.retries == 0 {
+		return p.Reference()
+	}
+	return fmt.Sprintf("%s-%d", p.Reference(), p.retries+1)
+}
+
+// referencePattern finds a payout reference in bank statement text.
+var referencePattern = regexp.MustCompile(`(?i)PAYOUT-(\d{1,10})`)
+
+// ParseReference returns the ID of the payout a bank reference names. The
+// reference may be surrounded by other text, as banks often add to it.
+func ParseReference(reference string) (uint, bool) {
+	match := referencePattern.FindStringSubmatch(reference)
+	if match == nil {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(match[1], 10, 32)
+	if err != nil || id == 0 {
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// CheckPaid returns ErrStatementMismatch, saying how, if the bank paid an
+// amount other than the payout amount.
+func (p *Payout) CheckPaid(paid shared.Money) error {
+	switch {
+	case paid.Currency() != p.Currency():
+		return fmt.Errorf("%w: bank paid %s %s, expected %s %s",
+			ErrStatementMismatch, paid, paid.Currency(), p.amount, p.Currency())
+	case paid.Cmp(p.amount) < 0:
+		return fmt.Errorf("%w: short paid %s of %s %s",
+			ErrStatementMismatch, paid, p.amount, p.Currency())
+	case paid.Cmp(p.amount) > 0:
+		return fmt.Errorf("%w: bank paid %s %s, expected %s",
+			ErrStatementMismatch, paid, p.Currency(), p.amount)
+	}
+	return nil
+}
+
+// GrossAmount returns the commission total before clawback deductions,
+// tax and reserves.
+func (p *Payout) GrossAmount() shared.Money {
+	return p.TaxableAmount().Add(p.deductions)
+}
+
+// TaxableAmount returns the commission total less clawback deductions:
+// the agent's income from the payout, before tax is withheld. Clawbacks
+// netted against released reserves make it negative.
+func (p *Payout) TaxableAmount() shared.Money {
+	return p.amount.Add(p.withheld).Add(p.reserved).Sub(p.released)
+}
+
+// IsRelease returns true if the payout pays released reserves rather
+// than commissions.
+func (p *Payout) IsRelease() bool {
+	return p.released.IsPositive()
+}
 
 // CommissionIDs returns all commission IDs in this payout.
 func (p *Payout) CommissionIDs() []uint {
@@ -92,56 +335,128 @@ func (p *Payout) ItemCount() int {
 	return len(p.items)
 }
 
+// LedgerEntries returns and clears the ledger entries for the transitions
+// since the payout was last saved, linked to the payout.
+func (p *Payout) LedgerEntries() []*ledger.Entry {
+	entries := p.entries
+	p.entries = nil
+	for _, entry := range entries {
+		entry.ForPayout(p.id)
+	}
+	return entries
+}
+
 // --- Behavior Methods ---
 
-// Process starts processing the payout.
+// SetID records the ID assigned by the store on first save.
+func (p *Payout) SetID(id uint) {
+	p.id = id
+}
+
+// SetVersion records the version the store saved the payout at.
+func (p *Payout) SetVersion(version int) {
+	p.version = version
+}
+
+// Process starts processing a pending payout, once it has been sent to
+// the bank.
 func (p *Payout) Process() error {
-	if !p.status.CanTransitionTo(shared.PayoutProcessing) {
-		return ErrPayoutCompleted
-	}
-	p.status = shared.PayoutProcessing
-	p.updatedAt = time.Now()
-	return nil
+	return p.transitionTo(shared.PayoutProcessing)
 }
 
-// Complete marks the payout as completed.
-func (p *Payout) Complete() error {
-	if !p.status.CanTransitionTo(shared.PayoutCompleted) {
-		return ErrPayoutCompleted
+// Complete marks a processing payout as paid, with the bank transaction
+// reference. Its net amount moves from in payout to paid out.
+func (p *Payout) Complete(transactionRef string) error {
+	if err := p.transitionTo(shared.PayoutCompleted); err != nil {
+		return err
+	}
+	p.transactionRef = transactionRef
+	p.paidAt = &p.updatedAt
+	if entry := ledger.Transfer(p.agentID, ledger.KindPayoutCompleted, shared.LedgerInPayout, shared.LedgerPaidOut, p.amount); entry != nil {
+		p.entries = append(p.entries, entry)
 	}
-	p.status = shared.PayoutCompleted
-	now := time.Now()
-	p.paidAt = &now
-	p.updatedAt = now
 	return nil
 }
 
-// Fail marks the payout as failed.
+// Fail marks a processing payout as failed, with the reason the bank gave.
+// Its ledger entry is reversed until it is retried.
 func (p *Payout) Fail(reason string) error {
-	if !p.status.CanTransitionTo(shared.PayoutFailed) {
-		return ErrPayoutCompleted
+	if reason == "" {
+		return ErrReasonRequired
 	}
-	p.status = shared.PayoutFailed
-	p.updatedAt = time.Now()
-	return nil
+	if err := p.transitionTo(shared.PayoutFailed); err != nil {
+		return err
+	}
+	p.failureReason = reason
+	return p.post(ledger.KindPayoutFailed, true)
 }
 
-// Retry retries a failed payout.
+// Retry returns a failed payout to pending so it can be sent again, as a
+// new transfer, posting its ledger entry again.
 func (p *Payout) Retry() error {
-	if !p.status.CanRetry() {
-		return ErrInvalidPayout
+	if err := p.transitionTo(shared.PayoutPending); err != nil {
+		return err
 	}
-	p.status = shared.PayoutPending
-	p.updatedAt = time.Now()
-	return nil
+	p.failureReason = ""
+	p.retries++
+	return p.post(ledger.KindPayoutRetried, false)
 }
 
-// Cancel cancels the payout.
+// Cancel cancels a pending or failed payout. Its commissions return to the
+// agent's available balance, the clawbacks it netted are owed again, the
+// tax it withheld and the reserve it kept back are reversed, and the
+// reserves it released are held again. A failed payout's ledger entry was
+// reversed when it failed.
 func (p *Payout) Cancel() error {
-	if p.status.IsTerminal() {
-		return ErrPayoutCompleted
+	failed := p.IsFailed()
+	if err := p.transitionTo(shared.PayoutCancelled); err != nil {
+		return err
+	}
+	if failed {
+		return nil
+	}
+	return p.post(ledger.KindPayoutCancelled, true)
+}
+
+// post records the ledger entry for the payout, or with reverse the entry
+// undoing it. The commissions leave the agent's available balance, and
+// released reserves their reserve: the net amount is in payout until it is
+// paid, the deductions settle the clawbacks they owed, the tax is withheld
+// and the reserve kept back. A payout of nothing posts no entry.
+func (p *Payout) post(kind string, reverse bool) error {
+	gross := p.GrossAmount()
+	if gross.IsZero() && p.released.IsZero() {
+		return nil
+	}
+	lines := []ledger.Line{
+		ledger.Debit(shared.LedgerAvailable, gross),
+		ledger.Debit(shared.LedgerReserve, p.released),
+		ledger.Credit(shared.LedgerInPayout, p.amount),
+		ledger.Credit(shared.LedgerClawbackReceivable, p.deductions),
+		ledger.Credit(shared.LedgerTaxWithheld, p.withheld),
+		ledger.Credit(shared.LedgerReserve, p.reserved),
+	}
+	if reverse {
+		for i, line := range lines {
+			lines[i] = line.Reversed()
+		}
+	}
+	entry, err := ledger.NewEntry(p.agentID, kind, lines...)
+	if err != nil {
+		return fmt.Errorf("payout %d ledger entry: %w", p.id, err)
+	}
+	p.entries = append(p.entries, entry)
+	return nil
+}
+
+// transitionTo moves the payout to the target status if the current
+// status allows it.
+func (p *Payout) transitionTo(target shared.PayoutStatus) error {
+	status, err := p.status.TransitionTo(target)
+	if err != nil {
+		return err
 	}
-	p.status = shared.PayoutCancelled
+	p.status = status
 	p.updatedAt = time.Now()
 	return nil
 }
@@ -1,15 +1,17 @@
 package payout
 
+import "github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+
 // PayoutItem represents a commission included in a payout.
 // This is a value object - immutable once created.
 type PayoutItem struct {
 	commissionID uint
 	orderID      string
-	amount       float64
+	amount       shared.Money
 }
 
 // NewPayoutItem creates a new PayoutItem.
-func NewPayoutItem(commissionID uint, orderID string, amount float64) PayoutItem {
+func NewPayoutItem(commissionID uint, orderID string, amount shared.Money) PayoutItem {
 	return PayoutItem{
 		commissionID: commissionID,
 		orderID:      orderID,
@@ -18,9 +20,9 @@ func NewPayoutItem(commissionID uint, orderID string, amount float64) PayoutItem
 }
 
 // Getters
-func (i PayoutItem) CommissionID() uint { return i.commissionID }
-func (i PayoutItem) OrderID() string    { return i.orderID }
-func (i PayoutItem) Amount() float64    { return i.amount }
+func (i PayoutItem) CommissionID() uint   { return i.commissionID }
+func (i PayoutItem) OrderID() string      { return i.orderID }
+func (i PayoutItem) Amount() shared.Money { return i.amount }
 
 // Equals compares two payout items.
 func (i PayoutItem) Equals(other PayoutItem) bool {
@@ -0,0 +1,268 @@
+package payout
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/ledger"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+)
+
+func TestNewPayoutTotals(t *testing.T) {
+	myr := func(minor int64) shared.Money { return shared.NewMoney(minor, "MYR") }
+	tests := []struct {
+		name        string
+		items       []int64
+		deductions  int64
+		released    int64
+		withholding float64
+		reserve     float64
+	}{
+		{"plain", []int64{10000, 2550}, 0, 0, 0, 0},
+		{"withholding", []int64{12345, 6789, 1}, 0, 0, 10, 0},
+		{"reserve", []int64{33333}, 0, 0, 0, 7.5},
+		{"deductions, tax and reserve", []int64{10001, 20002, 30003}, 4567, 0, 2.5, 12.345},
+		{"debit adjustment", []int64{50000, -1234}, 999, 0, 3, 10},
+		{"deductions take it all", []int64{1000}, 1000, 0, 10, 10},
+		{"release", nil, 0, 7777, 10, 10},
+		{"release less deductions", nil, 333, 7777, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var items []PayoutItem
+			var commissions shared.Money
+			for i, minor := range tt.items {
+				items = append(items, NewPayoutItem(uint(i+1), "ORD", myr(minor)))
+				commissions = commissions.Add(myr(minor))
+			}
+			p, err := NewPayout(PayoutParams{
+				AgentID:         1,
+				Period:          "2024-01",
+				Items:           items,
+				Deductions:      myr(tt.deductions),
+				Released:        myr(tt.released),
+				WithholdingRate: tt.withholding,
+				ReservePercent:  tt.reserve,
+			})
+			if err != nil {
+				t.Fatalf("NewPayout: %v", err)
+			}
+
+			// Every sen the payout takes in leaves it as paid, deducted,
+			// withheld or reserved
+			in := commissions.Add(myr(tt.released))
+			out := shared.SumMoney(p.Amount(), p.Deductions(), p.Withheld(), p.Reserved())
+			if out.Cmp(in) != 0 {
+				t.Errorf("payout totals %s, want %s", out, in)
+			}
+			if p.GrossAmount().Add(p.Released()).Cmp(in) != 0 {
+				t.Errorf("gross %s plus released %s, want %s", p.GrossAmount(), p.Released(), in)
+			}
+			if p.Amount().IsNegative() {
+				t.Errorf("amount %s is negative", p.Amount())
+			}
+
+			for _, entry := range p.LedgerEntries() {
+				if !entry.IsBalanced() {
+					t.Errorf("ledger entry %s is not balanced", entry.Kind())
+				}
+			}
+		})
+	}
+}
+
+// balancesOf sums ledger entries into account balances
+func balancesOf(entries []*ledger.Entry) ledger.Balances {
+	sums := make(map[shared.LedgerAccount]shared.Money)
+	var accounts []shared.LedgerAccount
+	for _, entry := range entries {
+		if !entry.IsBalanced() {
+			panic("unbalanced ledger entry " + entry.Kind())
+		}
+		for _, line := range entry.Lines() {
+			if _, ok := sums[line.Account()]; !ok {
+				accounts = append(accounts, line.Account())
+			}
+			sums[line.Account()] = sums[line.Account()].Add(line.Amount())
+		}
+	}
+	balances := make(ledger.Balances, 0, len(accounts))
+	for _, account := range accounts {
+		balances = append(balances, ledger.NewBalance(account, sums[account]))
+	}
+	return balances
+}
+
+func TestPayoutLedger(t *testing.T) {
+	myr := func(minor int64) shared.Money { return shared.NewMoney(minor, "MYR") }
+	tests := []struct {
+		name  string
+		steps func(p *Payout) error
+		// Balances after the steps, against an available balance of zero
+		// before the payout
+		available, inPayout, paidOut, receivable, withheld int64
+	}{
+		{"created", func(p *Payout) error { return nil }, -10000, 8550, 0, -500, 950},
+		{"processing", func(p *Payout) error { return p.Process() }, -10000, 8550, 0, -500, 950},
+		{"completed", func(p *Payout) error {
+			if err := p.Process(); err != nil {
+				return err
+			}
+			return p.Complete("TXN-1")
+		}, -10000, 0, 8550, -500, 950},
+		{"failed", func(p *Payout) error {
+			if err := p.Process(); err != nil {
+				return err
+			}
+			return p.Fail("account closed")
+		}, 0, 0, 0, 0, 0},
+		{"retried", func(p *Payout) error {
+			if err := p.Process(); err != nil {
+				return err
+			}
+			if err := p.Fail("account closed"); err != nil {
+				return err
+			}
+			return p.Retry()
+		}, -10000, 8550, 0, -500, 950},
+		{"cancelled", func(p *Payout) error { return p.Cancel() }, 0, 0, 0, 0, 0},
+		{"cancelled after failing", func(p *Payout) error {
+			if err := p.Process(); err != nil {
+				return err
+			}
+			if err := p.Fail("account closed"); err != nil {
+				return err
+			}
+			return p.Cancel()
+		}, 0, 0, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewPayout(PayoutParams{
+				AgentID:         1,
+				Period:          "2024-01",
+				Items:           []PayoutItem{NewPayoutItem(1, "ORD-1", myr(10000))},
+				Deductions:      myr(500),
+				WithholdingRate: 10,
+			})
+			if err != nil {
+				t.Fatalf("NewPayout: %v", err)
+			}
+			if err := tt.steps(p); err != nil {
+				t.Fatalf("steps: %v", err)
+			}
+
+			balances := balancesOf(p.LedgerEntries())
+			for account, want := range map[shared.LedgerAccount]int64{
+				shared.LedgerAvailable:          tt.available,
+				shared.LedgerInPayout:           tt.inPayout,
+				shared.LedgerPaidOut:            tt.paidOut,
+				shared.LedgerClawbackReceivable: tt.receivable,
+				shared.LedgerTaxWithheld:        tt.withheld,
+			} {
+				if got := balances.Of(account, "MYR"); got.Minor() != want {
+					t.Errorf("%s = %s, want %s", account, got, myr(want))
+				}
+			}
+		})
+	}
+}
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		reference string
+		id        uint
+		ok        bool
+	}{
+		{"PAYOUT-00000042", 42, true},
+		{"payout-00000042", 42, true},
+		{"PAYOUT-00000042-2", 42, true},
+		{"Commission PAYOUT-00000043 June", 43, true},
+		{"/ROC/PAYOUT-7", 7, true},
+		{"PAYOUT-00000000", 0, false},
+		{"PAYOUT-", 0, false},
+		{"PAYOUT-9999999999", 0, false},
+		{"FT24155XK2", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.reference, func(t *testing.T) {
+			id, ok := ParseReference(tt.reference)
+			if id != tt.id || ok != tt.ok {
+				t.Errorf("ParseReference(%q) = %d, %v, want %d, %v", tt.reference, id, ok, tt.id, tt.ok)
+			}
+		})
+	}
+}
+
+func TestCheckPaid(t *testing.T) {
+	p := Reconstitute(PayoutParams{ID: 42, AgentID: 1, Amount: shared.NewMoney(125000, "MYR"), Status: string(shared.PayoutProcessing)})
+	tests := []struct {
+		name  string
+		paid  shared.Money
+		match bool
+	}{
+		{"exact amount", shared.NewMoney(125000, "MYR"), true},
+		{"short paid", shared.NewMoney(124999, "MYR"), false},
+		{"overpaid", shared.NewMoney(125001, "MYR"), false},
+		{"other currency", shared.NewMoney(125000, "SGD"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := p.CheckPaid(tt.paid)
+			if tt.match && err != nil {
+				t.Errorf("CheckPaid(%s %s) error = %v, want nil", tt.paid, tt.paid.Currency(), err)
+			}
+			if !tt.match && !errors.Is(err, ErrStatementMismatch) {
+				t.Errorf("CheckPaid(%s %s) error = %v, want %v", tt.paid, tt.paid.Currency(), err, ErrStatementMismatch)
+			}
+		})
+	}
+}
+
+func TestTransferReference(t *testing.T) {
+	tests := []struct {
+		name    string
+		retries int
+		want    string
+	}{
+		{"first transfer", 0, "PAYOUT-00000042"},
+		{"first retry", 1, "PAYOUT-00000042-2"},
+		{"third retry", 3, "PAYOUT-00000042-4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewPayout(PayoutParams{
+				AgentID: 1,
+				Period:  "2024-01",
+				Items:   []PayoutItem{NewPayoutItem(1, "ORD-1", shared.NewMoney(10000, "MYR"))},
+			})
+			if err != nil {
+				t.Fatalf("NewPayout: %v", err)
+			}
+			p.SetID(42)
+			for i := 0; i < tt.retries; i++ {
+				if err := p.Process(); err != nil {
+					t.Fatalf("Process: %v", err)
+				}
+				if err := p.Fail("account closed"); err != nil {
+					t.Fatalf("Fail: %v", err)
+				}
+				if err := p.Retry(); err != nil {
+					t.Fatalf("Retry: %v", err)
+				}
+			}
+
+			// Each attempt is a new transfer, still traced to the payout
+			if got := p.TransferReference(); got != tt.want {
+				t.Errorf("TransferReference() = %s, want %s", got, tt.want)
+			}
+			if p.Reference() != "PAYOUT-00000042" {
+				t.Errorf("Reference() = %s, want PAYOUT-00000042", p.Reference())
+			}
+			if id, ok := ParseReference(p.TransferReference()); !ok || id != 42 {
+				t.Errorf("ParseReference(%s) = %d, %v, want 42, true", p.TransferReference(), id, ok)
+			}
+		})
+	}
+}
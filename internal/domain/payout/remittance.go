@@ -0,0 +1,117 @@
+package payout
+
+import (
+	"fmt"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/commission"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+)
+
+// Remittance line kinds
+const (
+	RemittanceCommission = "commission" // Earned on a sale
+	RemittanceAdjustment = "adjustment" // A bonus, penalty or correction
+	RemittanceClawback   = "clawback"   // A clawback netted against the payout
+)
+
+// Remittance is a payout's remittance advice, the agent's proof of what
+// they were paid: every commission the payout pays, the adjustments and
+// clawbacks netted against them, the tax withheld and the net amount.
+type Remittance struct {
+	Payout      *Payout
+	Commissions []RemittanceLine
+	Adjustments []RemittanceLine
+	Clawbacks   []RemittanceLine
+
+	CommissionTotal shared.Money
+	AdjustmentTotal shared.Money
+}
+
+// RemittanceLine is one commission or clawback on a remittance advice,
+// in the payout currency. Clawbacks are negative.
+type RemittanceLine struct {
+	Kind        string
+	ID          uint // The commission or clawback ID
+	OrderID     string
+	Description string
+	Amount      shared.Money
+}
+
+// NewRemittance builds a payout's remittance advice from the commissions
+// it pays and the clawbacks it recovered. Each item is described by its
+// commission, if given; the amounts are the payout's own.
+func NewRemittance(p *Payout, commissions []*commission.Commission, clawbacks []*commission.Clawback) *Remittance {
+	byID := make(map[uint]*commission.Commission, len(commissions))
+	for _, c := range commissions {
+		byID[c.ID()] = c
+	}
+
+	zero := shared.ZeroMoney(p.Currency())
+	r := &Remittance{Payout: p, CommissionTotal: zero, AdjustmentTotal: zero}
+	for _, item := range p.Items() {
+		line := RemittanceLine{
+			Kind:        RemittanceCommission,
+			ID:          item.CommissionID(),
+			OrderID:     item.OrderID(),
+			Description: "Commission",
+			Amount:      item.Amount(),
+		}
+		c, ok := byID[item.CommissionID()]
+		if ok {
+			line.Description = describeCommission(c)
+		}
+		if ok && c.IsAdjustment() {
+			line.Kind = RemittanceAdjustment
+			r.Adjustments = append(r.Adjustments, line)
+			r.AdjustmentTotal = r.AdjustmentTotal.Add(line.Amount)
+			continue
+		}
+		r.Commissions = append(r.Commissions, line)
+		r.CommissionTotal = r.CommissionTotal.Add(line.Amount)
+	}
+
+	for _, cb := range clawbacks {
+		// Clawbacks are netted in the payout currency, converted if the
+		// commission was
+		amount := cb.Amount()
+		if amount.Currency() != p.Currency() {
+			amount = cb.PayoutAmount()
+		}
+		description := "Clawback"
+		if cb.Reason() != "" {
+			description = fmt.Sprintf("Clawback: %s", cb.Reason())
+		}
+		r.Clawbacks = append(r.Clawbacks, RemittanceLine{
+			Kind:        RemittanceClawback,
+			ID:          cb.ID(),
+			OrderID:     cb.OrderID(),
+			Description: description,
+			Amount:      amount,
+		})
+	}
+	return r
+}
+
+// Lines returns every line on the advice: commissions, then adjustments,
+// then clawbacks.
+func (r *Remittance) Lines() []RemittanceLine {
+	lines := make([]RemittanceLine, 0, len(r.Commissions)+len(r.Adjustments)+len(r.Clawbacks))
+	lines = append(lines, r.Commissions...)
+	lines = append(lines, r.Adjustments...)
+	return append(lines, r.Clawbacks...)
+}
+
+// describeCommission says what a commission was paid for, e.g. "Sale at
+// 10.00%" or "Bonus: top seller in March".
+func describeCommission(c *commission.Commission) string {
+	switch {
+	case c.IsAdjustment():
+		return fmt.Sprintf("%s: %s", c.Category().Label(), c.Note())
+	case c.IsDerived() && c.SourceCommissionID() != nil:
+		return fmt.Sprintf("%s on commission #%d", c.Type().Label(), *c.SourceCommissionID())
+	case c.IsSplit():
+		return fmt.Sprintf("%s at %s, %.0f%% share", c.Type().Label(), c.Rate(), c.SplitShare())
+	default:
+		return fmt.Sprintf("%s at %s", c.Type().Label(), c.Rate())
+	}
+}
@@ -0,0 +1,253 @@
+package payout
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+)
+
+// Domain errors for reserve policies and reserves
+var (
+	ErrReservePolicyNotFound = errors.New("reserve policy not found")
+	ErrInvalidReservePolicy  = errors.New("invalid reserve policy")
+	ErrReserveNotFound       = errors.New("payout reserve not found")
+)
+
+// ReservePolicy keeps back a share of a risky agent's payouts, after tax,
+// for a number of days to cover clawbacks on the commissions they paid.
+// An agent has at most one policy; payouts already created keep the
+// reserve they were created with.
+type ReservePolicy struct {
+	agentID   uint
+	percent   float64
+	days      int
+	reason    string
+	updatedBy string
+	createdAt time.Time
+	updatedAt time.Time
+}
+
+// ReservePolicyParams contains parameters for creating a ReservePolicy.
+type ReservePolicyParams struct {
+	AgentID   uint
+	Percent   float64
+	Days      int
+	Reason    string
+	UpdatedBy string
+
+	// Stored state, only read by ReconstituteReservePolicy.
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewReservePolicy creates a reserve policy for an agent.
+func NewReservePolicy(params ReservePolicyParams) (*ReservePolicy, error) {
+	if params.AgentID == 0 {
+		return nil, fmt.Errorf("%w: agent ID is required", ErrInvalidReservePolicy)
+	}
+	now := time.Now()
+	p := &ReservePolicy{
+		agentID:   params.AgentID,
+		createdAt: now,
+		updatedAt: now,
+	}
+	if err := p.Update(params.Percent, params.Days, params.Reason, params.UpdatedBy); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ReconstituteReservePolicy rebuilds a ReservePolicy from stored state.
+func ReconstituteReservePolicy(params ReservePolicyParams) *ReservePolicy {
+	return &ReservePolicy{
+		agentID:   params.AgentID,
+		percent:   params.Percent,
+		days:      params.Days,
+		reason:    params.Reason,
+		updatedBy: params.UpdatedBy,
+		createdAt: params.CreatedAt,
+		updatedAt: params.UpdatedAt,
+	}
+}
+
+// Getters
+func (p *ReservePolicy) AgentID() uint        { return p.agentID }
+func (p *ReservePolicy) Percent() float64     { return p.percent }
+func (p *ReservePolicy) Days() int            { return p.days }
+func (p *ReservePolicy) Reason() string       { return p.reason }
+func (p *ReservePolicy) UpdatedBy() string    { return p.updatedBy }
+func (p *ReservePolicy) CreatedAt() time.Time { return p.createdAt }
+func (p *ReservePolicy) UpdatedAt() time.Time { return p.updatedAt }
+
+// Update changes the policy.
+func (p *ReservePolicy) Update(percent float64, days int, reason, actor string) error {
+	if percent <= 0 || percent > 100 {
+		return fmt.Errorf("%w: percent must be above 0 and at most 100", ErrInvalidReservePolicy)
+	}
+	if days < 1 {
+		return fmt.Errorf("%w: reserves must be held for at least a day", ErrInvalidReservePolicy)
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return fmt.Errorf("%w: %w", ErrInvalidReservePolicy, ErrReasonRequired)
+	}
+
+	p.percent = percent
+	p.days = days
+	p.reason = reason
+	p.updatedBy = actor
+	p.updatedAt = time.Now()
+	return nil
+}
+
+// Reserve is the share of one payout kept back under the agent's reserve
+// policy. Once it is due, and its payout has been paid, it is released to
+// the agent in a release payout that nets their outstanding clawbacks
+// against it.
+type Reserve struct {
+	id              uint
+	agentID         uint
+	payoutID        uint // The payout the reserve was kept back from
+	amount          shared.Money
+	percent         float64
+	releaseAt       time.Time
+	status          shared.ReserveStatus
+	releasePayoutID *uint // The payout that released it
+	releasedAt      *time.Time
+	createdAt       time.Time
+	updatedAt       time.Time
+
+	// version counts the reserve's saves, so two payouts cannot both
+	// release it
+	version int
+}
+
+// ReserveParams contains the stored state of a Reserve.
+type ReserveParams struct {
+	ID              uint
+	AgentID         uint
+	PayoutID        uint
+	Amount          shared.Money
+	Percent         float64
+	ReleaseAt       time.Time
+	Status          string
+	ReleasePayoutID *uint
+	ReleasedAt      *time.Time
+	Version         int
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// NewReserve records the reserve a saved payout kept back, held for the
+// given number of days from the payout's creation.
+func NewReserve(p *Payout, days int) (*Reserve, error) {
+	if p.ID() == 0 || !p.Reserved().IsPositive() || days < 1 {
+		return nil, fmt.Errorf("%w: payout %d keeps back no reserve", ErrInvalidPayout, p.ID())
+	}
+	now := time.Now()
+	return &Reserve{
+		agentID:   p.AgentID(),
+		payoutID:  p.ID(),
+		amount:    p.Reserved(),
+		percent:   p.ReservePercent(),
+		releaseAt: p.CreatedAt().AddDate(0, 0, days),
+		status:    shared.ReserveHeld,
+		createdAt: now,
+		updatedAt: now,
+	}, nil
+}
+
+// ReconstituteReserve rebuilds a Reserve from stored state.
+func ReconstituteReserve(params ReserveParams) *Reserve {
+	return &Reserve{
+		id:              params.ID,
+		agentID:         params.AgentID,
+		payoutID:        params.PayoutID,
+		amount:          params.Amount,
+		percent:         params.Percent,
+		releaseAt:       params.ReleaseAt,
+		status:          shared.ReserveStatus(params.Status),
+		releasePayoutID: params.ReleasePayoutID,
+		releasedAt:      params.ReleasedAt,
+		version:         params.Version,
+		createdAt:       params.CreatedAt,
+		updatedAt:       params.UpdatedAt,
+	}
+}
+
+// Getters
+func (r *Reserve) ID() uint                     { return r.id }
+func (r *Reserve) AgentID() uint                { return r.agentID }
+func (r *Reserve) PayoutID() uint               { return r.payoutID }
+func (r *Reserve) Amount() shared.Money         { return r.amount }
+func (r *Reserve) Percent() float64             { return r.percent }
+func (r *Reserve) ReleaseAt() time.Time         { return r.releaseAt }
+func (r *Reserve) Status() shared.ReserveStatus { return r.status }
+func (r *Reserve) ReleasePayoutID() *uint       { return r.releasePayoutID }
+func (r *Reserve) ReleasedAt() *time.Time       { return r.releasedAt }
+func (r *Reserve) CreatedAt() time.Time         { return r.createdAt }
+func (r *Reserve) UpdatedAt() time.Time         { return r.updatedAt }
+func (r *Reserve) Version() int                 { return r.version }
+
+// IsHeld returns true if the reserve is still kept back from the agent.
+func (r *Reserve) IsHeld() bool {
+	return r.status == shared.ReserveHeld
+}
+
+// IsDue returns true if the reserve is held and its term is over at the
+// given time.
+func (r *Reserve) IsDue(now time.Time) bool {
+	return r.IsHeld() && !now.Before(r.releaseAt)
+}
+
+// SetID sets the ID (used by repository after insert).
+func (r *Reserve) SetID(id uint) {
+	r.id = id
+}
+
+// SetVersion records the version the store saved the reserve at.
+func (r *Reserve) SetVersion(version int) {
+	r.version = version
+}
+
+// Release records the payout the reserve was released to the agent in.
+func (r *Reserve) Release(payoutID uint) error {
+	if err := r.transitionTo(shared.ReserveReleased); err != nil {
+		return err
+	}
+	r.releasePayoutID = &payoutID
+	r.releasedAt = &r.updatedAt
+	return nil
+}
+
+// Reinstate holds the reserve again after its release payout was
+// cancelled.
+func (r *Reserve) Reinstate() error {
+	if err := r.transitionTo(shared.ReserveHeld); err != nil {
+		return err
+	}
+	r.releasePayoutID = nil
+	r.releasedAt = nil
+	return nil
+}
+
+// Cancel records that the payout the reserve was kept back from was
+// cancelled, so nothing is held.
+func (r *Reserve) Cancel() error {
+	return r.transitionTo(shared.ReserveCancelled)
+}
+
+// transitionTo moves the reserve to the target status if the current
+// status allows it.
+func (r *Reserve) transitionTo(target shared.ReserveStatus) error {
+	status, err := r.status.TransitionTo(target)
+	if err != nil {
+		return err
+	}
+	r.status = status
+	r.updatedAt = time.Now()
+	return nil
+}
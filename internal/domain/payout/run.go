@@ -0,0 +1,284 @@
+package payout
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+)
+
+// Domain errors for the Run aggregate
+var (
+	ErrRunNotFound     = errors.New("payout run not found")
+	ErrEmptyRun        = errors.New("no agents are due a payout in this period")
+	ErrRunInProgress   = errors.New("payouts in the run are already being processed")
+	ErrFuturePeriod    = errors.New("period has not started yet")
+	ErrRunNotCommitted = errors.New("payout run is not committed")
+	ErrNothingToSend   = errors.New("no payouts in the run are waiting to be sent")
+)
+
+// Run outcomes for an agent
+const (
+	RunLinePaid           = "paid"
+	RunLineCarriedForward = "carried_forward"
+)
+
+// Run is the aggregate root for a payout run: one pass over every active
+// agent for a period, producing a pending payout for each agent whose
+// approved commissions reach the minimum payout. Smaller balances are
+// carried forward to the next run. A run is committed or cancelled as a
+// whole.
+type Run struct {
+	id          uint
+	period      string
+	status      shared.PayoutRunStatus
+	minimum     shared.Money // In the default currency
+	lines       []RunLine
+	createdBy   string
+	cancelledBy string
+	cancelledAt *time.Time
+	createdAt   time.Time
+	updatedAt   time.Time
+}
+
+// RunLine is an agent's outcome in a payout run: a payout, or a balance
+// carried forward with the reason why.
+type RunLine struct {
+	agentID     uint
+	outcome     string
+	amount      shared.Money // Net of deductions
+	deductions  shared.Money
+	commissions int
+	reason      string
+	payoutID    *uint
+
+	// payout is the payout to create when the run is first saved
+	payout *Payout
+}
+
+// RunParams contains parameters for creating a Run.
+type RunParams struct {
+	ID        uint
+	Period    string
+	Minimum   shared.Money
+	CreatedBy string
+
+	// Stored state, only read by Reconstitute.
+	Status      string
+	Lines       []RunLine
+	CancelledBy string
+	CancelledAt *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// NewRun creates a draft payout run for a period that has started.
+func NewRun(params RunParams) (*Run, error) {
+	start, err := ParsePeriod(params.Period)
+	if err != nil {
+		return nil, err
+	}
+	if start.After(time.Now()) {
+		return nil, ErrFuturePeriod
+	}
+	if params.Minimum.IsNegative() {
+		return nil, ErrInvalidPayout
+	}
+
+	now := time.Now()
+	return &Run{
+		id:        params.ID,
+		period:    params.Period,
+		status:    shared.PayoutRunDraft,
+		minimum:   params.Minimum,
+		createdBy: params.CreatedBy,
+		createdAt: now,
+		updatedAt: now,
+	}, nil
+}
+
+// ReconstituteRun rebuilds a Run from stored state.
+func ReconstituteRun(params RunParams) *Run {
+	return &Run{
+		id:          params.ID,
+		period:      params.Period,
+		status:      shared.PayoutRunStatus(params.Status),
+		minimum:     params.Minimum,
+		lines:       params.Lines,
+		createdBy:   params.CreatedBy,
+		cancelledBy: params.CancelledBy,
+		cancelledAt: params.CancelledAt,
+		createdAt:   params.CreatedAt,
+		updatedAt:   params.UpdatedAt,
+	}
+}
+
+// ReconstituteRunLine rebuilds a stored run line.
+func ReconstituteRunLine(agentID uint, outcome string, amount, deductions shared.Money, commissions int, reason string, payoutID *uint) RunLine {
+	return RunLine{
+		agentID:     agentID,
+		outcome:     outcome,
+		amount:      amount,
+		deductions:  deductions,
+		commissions: commissions,
+		reason:      reason,
+		payoutID:    payoutID,
+	}
+}
+
+// Getters
+func (r *Run) ID() uint                       { return r.id }
+func (r *Run) Period() string                 { return r.period }
+func (r *Run) Status() shared.PayoutRunStatus { return r.status }
+func (r *Run) Minimum() shared.Money          { return r.minimum }
+func (r *Run) Lines() []RunLine               { return r.lines }
+func (r *Run) CreatedBy() string              { return r.createdBy }
+func (r *Run) CancelledBy() string            { return r.cancelledBy }
+func (r *Run) CancelledAt() *time.Time        { return r.cancelledAt }
+func (r *Run) CreatedAt() time.Time           { return r.createdAt }
+func (r *Run) UpdatedAt() time.Time           { return r.updatedAt }
+
+func (l RunLine) AgentID() uint            { return l.agentID }
+func (l RunLine) Outcome() string          { return l.outcome }
+func (l RunLine) Amount() shared.Money     { return l.amount }
+func (l RunLine) Deductions() shared.Money { return l.deductions }
+func (l RunLine) Commissions() int         { return l.commissions }
+func (l RunLine) Reason() string           { return l.reason }
+
+// PayoutID returns the ID of the agent's payout, once it has been saved.
+func (l RunLine) PayoutID() *uint {
+	if l.payout != nil && l.payout.ID() != 0 {
+		id := l.payout.ID()
+		return &id
+	}
+	return l.payoutID
+}
+
+// IsPaid returns true if the agent is paid in the run.
+func (l RunLine) IsPaid() bool {
+	return l.outcome == RunLinePaid
+}
+
+// PeriodEnd returns the end of the run's period: commissions approved for
+// sales before it are paid.
+func (r *Run) PeriodEnd() time.Time {
+	start, _ := ParsePeriod(r.period)
+	return start.AddDate(0, 1, 0)
+}
+
+// Payouts returns the payouts a run creates when it is committed, saved
+// with the run.
+func (r *Run) Payouts() []*Payout {
+	var payouts []*Payout
+	for _, line := range r.lines {
+		if line.payout != nil {
+			payouts = append(payouts, line.payout)
+		}
+	}
+	return payouts
+}
+
+// PayoutIDs returns the IDs of the payouts the run created.
+func (r *Run) PayoutIDs() []uint {
+	var ids []uint
+	for _, line := range r.lines {
+		if id := line.PayoutID(); id != nil {
+			ids = append(ids, *id)
+		}
+	}
+	return ids
+}
+
+// PayoutCount returns the number of agents paid in the run.
+func (r *Run) PayoutCount() int {
+	count := 0
+	for _, line := range r.lines {
+		if line.IsPaid() {
+			count++
+		}
+	}
+	return count
+}
+
+// --- Behavior Methods ---
+
+// SetID records the ID assigned by the store on first save.
+func (r *Run) SetID(id uint) {
+	r.id = id
+}
+
+// AddPayout adds an agent's payout for the run's period to a draft run.
+func (r *Run) AddPayout(p *Payout) error {
+	if r.status != shared.PayoutRunDraft {
+		return shared.ErrInvalidPayoutRunTransition
+	}
+	if p.Period() != r.period || r.hasAgent(p.AgentID()) {
+		return ErrInvalidPayout
+	}
+	r.lines = append(r.lines, RunLine{
+		agentID:     p.AgentID(),
+		outcome:     RunLinePaid,
+		amount:      p.Amount(),
+		deductions:  p.Deductions(),
+		commissions: p.ItemCount(),
+		payout:      p,
+	})
+	return nil
+}
+
+// CarryForward records an agent's approved balance as carried forward to
+// the next run, with the reason it is not paid in this one.
+func (r *Run) CarryForward(agentID uint, balance shared.Money, commissions int, reason string) error {
+	if r.status != shared.PayoutRunDraft {
+		return shared.ErrInvalidPayoutRunTransition
+	}
+	if r.hasAgent(agentID) {
+		return ErrInvalidPayout
+	}
+	r.lines = append(r.lines, RunLine{
+		agentID:     agentID,
+		outcome:     RunLineCarriedForward,
+		amount:      balance,
+		commissions: commissions,
+		reason:      reason,
+	})
+	return nil
+}
+
+// Commit commits a draft run. Its payouts are created when the run is
+// saved. A run that pays nobody cannot be committed.
+func (r *Run) Commit() error {
+	status, err := r.status.TransitionTo(shared.PayoutRunCommitted)
+	if err != nil {
+		return err
+	}
+	if r.PayoutCount() == 0 {
+		return ErrEmptyRun
+	}
+	r.status = status
+	r.updatedAt = time.Now()
+	return nil
+}
+
+// Cancel cancels a committed run. Its payouts are cancelled with it.
+func (r *Run) Cancel(actor string) error {
+	status, err := r.status.TransitionTo(shared.PayoutRunCancelled)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	r.status = status
+	r.cancelledBy = actor
+	r.cancelledAt = &now
+	r.updatedAt = now
+	return nil
+}
+
+func (r *Run) hasAgent(agentID uint) bool {
+	for _, line := range r.lines {
+		if line.agentID == agentID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,263 @@
+package payout
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+)
+
+// Domain errors for the Statement aggregate
+var (
+	ErrStatementNotFound     = errors.New("bank statement not found")
+	ErrStatementLineNotFound = errors.New("bank statement line not found")
+	ErrEmptyStatement        = errors.New("bank statement has no lines")
+	ErrLineNotInReview       = errors.New("statement line is not waiting for review")
+	ErrStatementMismatch     = errors.New("statement amount does not match the payout")
+)
+
+// Statement line outcomes
+const (
+	StatementLineCompleted = "completed" // Payout completed with the bank's transaction reference
+	StatementLineFailed    = "failed"    // Payout failed: paid in the wrong amount or currency
+	StatementLineDuplicate = "duplicate" // Payout already completed by the same transaction
+	StatementLineUnmatched = "unmatched" // Waiting in the review queue
+	StatementLineResolved  = "resolved"  // Matched to a payout on review
+	StatementLineDismissed = "dismissed" // Dismissed on review
+)
+
+// Statement is the aggregate root for an imported bank statement: the
+// bank's record of the payments it made, matched line by line to the
+// payouts they paid.
+type Statement struct {
+	id         uint
+	format     string
+	fileName   string
+	checksum   string
+	lines      []*StatementLine
+	importedBy string
+	createdAt  time.Time
+}
+
+// StatementLine is one payment on a bank statement and what it was
+// matched to. Lines that match no payout wait in a review queue.
+type StatementLine struct {
+	id             uint
+	statementID    uint
+	reference      string
+	transactionRef string
+	amount         shared.Money
+	bookedAt       *time.Time
+	outcome        string
+	detail         string
+	payoutID       *uint
+	reviewedBy     string
+	reviewedAt     *time.Time
+}
+
+// StatementParams contains parameters for creating a Statement.
+type StatementParams struct {
+	ID         uint
+	Format     string
+	FileName   string
+	Checksum   string // Hex SHA-256 of the file, so a file is imported once
+	ImportedBy string
+
+	// Stored state, only read by Reconstitute.
+	Lines     []*StatementLine
+	CreatedAt time.Time
+}
+
+// StatementLineParams contains parameters for a statement line.
+type StatementLineParams struct {
+	ID          uint
+	StatementID uint
+	Reference   string
+	Amount      shared.Money
+	BookedAt    *time.Time
+
+	// TransactionRef is the bank's reference for the payment. The line's
+	// reference is used when the statement has none.
+	TransactionRef string
+
+	// Stored state, only read by ReconstituteStatementLine.
+	Outcome    string
+	Detail     string
+	PayoutID   *uint
+	ReviewedBy string
+	ReviewedAt *time.Time
+}
+
+// NewStatement creates a statement being imported.
+func NewStatement(params StatementParams) (*Statement, error) {
+	if params.Format == "" || params.Checksum == "" {
+		return nil, errors.New("statement format and checksum are required")
+	}
+	return &Statement{
+		id:         params.ID,
+		format:     params.Format,
+		fileName:   params.FileName,
+		checksum:   params.Checksum,
+		importedBy: params.ImportedBy,
+		createdAt:  time.Now(),
+	}, nil
+}
+
+// ReconstituteStatement rebuilds a Statement from stored state.
+func ReconstituteStatement(params StatementParams) *Statement {
+	return &Statement{
+		id:         params.ID,
+		format:     params.Format,
+		fileName:   params.FileName,
+		checksum:   params.Checksum,
+		lines:      params.Lines,
+		importedBy: params.ImportedBy,
+		createdAt:  params.CreatedAt,
+	}
+}
+
+// ReconstituteStatementLine rebuilds a stored statement line.
+func ReconstituteStatementLine(params StatementLineParams) *StatementLine {
+	return &StatementLine{
+		id:             params.ID,
+		statementID:    params.StatementID,
+		reference:      params.Reference,
+		transactionRef: params.TransactionRef,
+		amount:         params.Amount,
+		bookedAt:       params.BookedAt,
+		outcome:        params.Outcome,
+		detail:         params.Detail,
+		payoutID:       params.PayoutID,
+		reviewedBy:     params.ReviewedBy,
+		reviewedAt:     params.ReviewedAt,
+	}
+}
+
+// Getters
+func (s *Statement) ID() uint                { return s.id }
+func (s *Statement) Format() string          { return s.format }
+func (s *Statement) FileName() string        { return s.fileName }
+func (s *Statement) Checksum() string        { return s.checksum }
+func (s *Statement) Lines() []*StatementLine { return s.lines }
+func (s *Statement) ImportedBy() string      { return s.importedBy }
+func (s *Statement) CreatedAt() time.Time    { return s.createdAt }
+
+func (l *StatementLine) ID() uint               { return l.id }
+func (l *StatementLine) StatementID() uint      { return l.statementID }
+func (l *StatementLine) Reference() string      { return l.reference }
+func (l *StatementLine) TransactionRef() string { return l.transactionRef }
+func (l *StatementLine) Amount() shared.Money   { return l.amount }
+func (l *StatementLine) BookedAt() *time.Time   { return l.bookedAt }
+func (l *StatementLine) Outcome() string        { return l.outcome }
+func (l *StatementLine) Detail() string         { return l.detail }
+func (l *StatementLine) PayoutID() *uint        { return l.payoutID }
+func (l *StatementLine) ReviewedBy() string     { return l.reviewedBy }
+func (l *StatementLine) ReviewedAt() *time.Time { return l.reviewedAt }
+
+// Count returns the number of lines with an outcome.
+func (s *Statement) Count(outcome string) int {
+	count := 0
+	for _, line := range s.lines {
+		if line.outcome == outcome {
+			count++
+		}
+	}
+	return count
+}
+
+// --- Behavior Methods ---
+
+// SetID records the ID assigned by the store on first save, on the
+// statement and its lines.
+func (s *Statement) SetID(id uint) {
+	s.id = id
+	for _, line := range s.lines {
+		line.statementID = id
+	}
+}
+
+// SetID records the ID assigned by the store on first save.
+func (l *StatementLine) SetID(id uint) {
+	l.id = id
+}
+
+// AddLine adds a payment from the bank statement. It is matched to a
+// payout with Complete, Fail, Duplicate or Refer.
+func (s *Statement) AddLine(params StatementLineParams) *StatementLine {
+	transactionRef := params.TransactionRef
+	if transactionRef == "" {
+		transactionRef = params.Reference
+	}
+	line := &StatementLine{
+		statementID:    s.id,
+		reference:      params.Reference,
+		transactionRef: transactionRef,
+		amount:         params.Amount,
+		bookedAt:       params.BookedAt,
+	}
+	s.lines = append(s.lines, line)
+	return line
+}
+
+// Complete records that the line paid a payout in full.
+func (l *StatementLine) Complete(payoutID uint) {
+	l.match(StatementLineCompleted, &payoutID, "")
+}
+
+// Fail records that the line paid a payout the wrong amount, failing it.
+func (l *StatementLine) Fail(payoutID uint, reason string) {
+	l.match(StatementLineFailed, &payoutID, reason)
+}
+
+// Duplicate records that the payout was already completed by this
+// transaction, on an earlier statement or line.
+func (l *StatementLine) Duplicate(payoutID uint) {
+	l.match(StatementLineDuplicate, &payoutID, "payout already completed by this transaction")
+}
+
+// Refer sends the line to the review queue, with why it was not matched
+// and the payout its reference names, if any.
+func (l *StatementLine) Refer(detail string, payoutID *uint) {
+	l.match(StatementLineUnmatched, payoutID, detail)
+}
+
+// NeedsReview returns true if the line is waiting in the review queue.
+func (l *StatementLine) NeedsReview() bool {
+	return l.outcome == StatementLineUnmatched
+}
+
+// Resolve matches a line in the review queue to the payout it paid.
+func (l *StatementLine) Resolve(payoutID uint, actor string) error {
+	if !l.NeedsReview() {
+		return ErrLineNotInReview
+	}
+	l.match(StatementLineResolved, &payoutID, l.detail)
+	l.review(actor)
+	return nil
+}
+
+// Dismiss removes a line from the review queue without matching it, with
+// a note saying why.
+func (l *StatementLine) Dismiss(note, actor string) error {
+	if !l.NeedsReview() {
+		return ErrLineNotInReview
+	}
+	if note == "" {
+		return ErrReasonRequired
+	}
+	l.match(StatementLineDismissed, l.payoutID, note)
+	l.review(actor)
+	return nil
+}
+
+func (l *StatementLine) match(outcome string, payoutID *uint, detail string) {
+	l.outcome = outcome
+	l.payoutID = payoutID
+	l.detail = detail
+}
+
+func (l *StatementLine) review(actor string) {
+	now := time.Now()
+	l.reviewedBy = actor
+	l.reviewedAt = &now
+}
@@ -0,0 +1,76 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+)
+
+// AdjustmentCategory classifies a manual adjustment to an agent's
+// earnings.
+type AdjustmentCategory string
+
+// Adjustment category constants
+const (
+	// AdjustmentBonus is a one-off amount granted to the agent
+	AdjustmentBonus AdjustmentCategory = "bonus"
+	// AdjustmentPenalty is a one-off amount taken from the agent
+	AdjustmentPenalty AdjustmentCategory = "penalty"
+	// AdjustmentCorrection corrects a commission that was missing or
+	// wrong, either way
+	AdjustmentCorrection AdjustmentCategory = "correction"
+)
+
+// ErrInvalidAdjustmentCategory is returned for invalid category values.
+var ErrInvalidAdjustmentCategory = errors.New("invalid adjustment category")
+
+// IsValid returns true if the category is valid.
+func (c AdjustmentCategory) IsValid() bool {
+	switch c {
+	case AdjustmentBonus, AdjustmentPenalty, AdjustmentCorrection:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation.
+func (c AdjustmentCategory) String() string {
+	return string(c)
+}
+
+// Label returns a human-readable label.
+func (c AdjustmentCategory) Label() string {
+	switch c {
+	case AdjustmentBonus:
+		return "Bonus"
+	case AdjustmentPenalty:
+		return "Penalty"
+	case AdjustmentCorrection:
+		return "Correction"
+	default:
+		return "Unknown"
+	}
+}
+
+// AllowsAmount returns true if an adjustment in the category can be of
+// the given sign: bonuses are credits, penalties are debits, and
+// corrections can be either.
+func (c AdjustmentCategory) AllowsAmount(amount Money) bool {
+	switch c {
+	case AdjustmentBonus:
+		return amount.IsPositive()
+	case AdjustmentPenalty:
+		return amount.IsNegative()
+	default:
+		return !amount.IsZero()
+	}
+}
+
+// ParseAdjustmentCategory parses a string into an AdjustmentCategory.
+func ParseAdjustmentCategory(str string) (AdjustmentCategory, error) {
+	c := AdjustmentCategory(str)
+	if !c.IsValid() {
+		return "", fmt.Errorf("%w: %s", ErrInvalidAdjustmentCategory, str)
+	}
+	return c, nil
+}
@@ -114,6 +114,20 @@ func (t AgentTier) NextTier() AgentTier {
 	}
 }
 
+// PreviousTier returns the tier below (or same if already lowest).
+func (t AgentTier) PreviousTier() AgentTier {
+	switch t {
+	case TierPlatinum:
+		return TierGold
+	case TierGold:
+		return TierSilver
+	case TierSilver:
+		return TierBronze
+	default:
+		return t
+	}
+}
+
 // IsPremium returns true if tier is gold or higher.
 func (t AgentTier) IsPremium() bool {
 	return t == TierGold || t == TierPlatinum
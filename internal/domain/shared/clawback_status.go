@@ -0,0 +1,85 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ClawbackStatus represents the recovery status of a commission clawback.
+type ClawbackStatus string
+
+// Clawback status constants
+const (
+	// ClawbackApplied clawbacks were deducted from a commission before it
+	// was paid, so there is nothing to recover.
+	ClawbackApplied ClawbackStatus = "applied"
+	// ClawbackOutstanding clawbacks are owed by the agent and wait to be
+	// netted against the next payout.
+	ClawbackOutstanding ClawbackStatus = "outstanding"
+	// ClawbackRecovered clawbacks have been netted against a payout. They
+	// are outstanding again if the payout is cancelled.
+	ClawbackRecovered ClawbackStatus = "recovered"
+)
+
+// validClawbackTransitions defines allowed state transitions.
+var validClawbackTransitions = map[ClawbackStatus][]ClawbackStatus{
+	ClawbackApplied:     {}, // Terminal
+	ClawbackOutstanding: {ClawbackRecovered},
+	ClawbackRecovered:   {ClawbackOutstanding},
+}
+
+// ErrInvalidClawbackStatus is returned for invalid status values.
+var ErrInvalidClawbackStatus = errors.New("invalid clawback status")
+
+// IsValid returns true if the status is valid.
+func (s ClawbackStatus) IsValid() bool {
+	switch s {
+	case ClawbackApplied, ClawbackOutstanding, ClawbackRecovered:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation.
+func (s ClawbackStatus) String() string {
+	return string(s)
+}
+
+// Label returns a human-readable label.
+func (s ClawbackStatus) Label() string {
+	switch s {
+	case ClawbackApplied:
+		return "Applied"
+	case ClawbackOutstanding:
+		return "Outstanding"
+	case ClawbackRecovered:
+		return "Recovered"
+	default:
+		return "Unknown"
+	}
+}
+
+// CanTransitionTo returns true if the status can transition to target.
+func (s ClawbackStatus) CanTransitionTo(target ClawbackStatus) bool {
+	for _, status := range validClawbackTransitions[s] {
+		if status == target {
+			return true
+		}
+	}
+	return false
+}
+
+// IsOutstanding returns true if the clawback still has to be recovered.
+func (s ClawbackStatus) IsOutstanding() bool {
+	return s == ClawbackOutstanding
+}
+
+// ParseClawbackStatus parses a string into a ClawbackStatus.
+func ParseClawbackStatus(str string) (ClawbackStatus, error) {
+	s := ClawbackStatus(str)
+	if !s.IsValid() {
+		return "", fmt.Errorf("%w: %s", ErrInvalidClawbackStatus, str)
+	}
+	return s, nil
+}
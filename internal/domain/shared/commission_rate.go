@@ -56,9 +56,16 @@ func (r CommissionRate) IsZero() bool {
 	return r.value == 0
 }
 
-// CalculateCommission calculates commission from an amount.
-func (r CommissionRate) CalculateCommission(amount float64) float64 {
-	return amount * r.Percentage()
+// CalculateCommission calculates commission from an amount, rounded half
+// up to the nearest minor unit.
+func (r CommissionRate) CalculateCommission(amount Money) Money {
+	return r.CalculateCommissionRounded(amount, RoundHalfUp)
+}
+
+// CalculateCommissionRounded calculates commission from an amount with an
+// explicit rounding mode.
+func (r CommissionRate) CalculateCommissionRounded(amount Money, mode RoundingMode) Money {
+	return amount.MulPercent(r.value, mode)
 }
 
 // Add adds another rate to this rate (capped at 100).
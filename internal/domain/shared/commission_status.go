@@ -12,16 +12,26 @@ type CommissionStatus string
 const (
 	CommissionPending   CommissionStatus = "pending"
 	CommissionApproved  CommissionStatus = "approved"
+	CommissionInPayout  CommissionStatus = "in_payout" // In a payout that has not completed
 	CommissionPaid      CommissionStatus = "paid"
 	CommissionCancelled CommissionStatus = "cancelled"
+	CommissionRejected  CommissionStatus = "rejected"
+
+	// Clawback states for commissions that were already paid
+	CommissionPartiallyReversed CommissionStatus = "partially_reversed"
+	CommissionReversed          CommissionStatus = "reversed"
 )
 
 // validCommissionTransitions defines allowed state transitions.
 var validCommissionTransitions = map[CommissionStatus][]CommissionStatus{
-	CommissionPending:   {CommissionApproved, CommissionCancelled},
-	CommissionApproved:  {CommissionPaid, CommissionCancelled},
-	CommissionPaid:      {}, // Terminal
-	CommissionCancelled: {}, // Terminal
+	CommissionPending:           {CommissionApproved, CommissionRejected, CommissionCancelled},
+	CommissionApproved:          {CommissionInPayout, CommissionCancelled},
+	CommissionInPayout:          {CommissionPaid, CommissionApproved, CommissionPartiallyReversed, CommissionReversed},
+	CommissionPaid:              {CommissionPartiallyReversed, CommissionReversed},
+	CommissionPartiallyReversed: {CommissionPartiallyReversed, CommissionReversed},
+	CommissionCancelled:         {}, // Terminal
+	CommissionRejected:          {}, // Terminal
+	CommissionReversed:          {}, // Terminal
 }
 
 // ErrInvalidCommissionStatus is returned for invalid status values.
@@ -32,13 +42,17 @@ var ErrInvalidCommissionTransition = errors.New("invalid commission status trans
 
 // AllCommissionStatuses returns all valid statuses.
 func AllCommissionStatuses() []CommissionStatus {
-	return []CommissionStatus{CommissionPending, CommissionApproved, CommissionPaid, CommissionCancelled}
+	return []CommissionStatus{
+		CommissionPending, CommissionApproved, CommissionInPayout, CommissionPaid, CommissionCancelled,
+		CommissionRejected, CommissionPartiallyReversed, CommissionReversed,
+	}
 }
 
 // IsValid returns true if the status is valid.
 func (s CommissionStatus) IsValid() bool {
 	switch s {
-	case CommissionPending, CommissionApproved, CommissionPaid, CommissionCancelled:
+	case CommissionPending, CommissionApproved, CommissionInPayout, CommissionPaid, CommissionCancelled,
+		CommissionRejected, CommissionPartiallyReversed, CommissionReversed:
 		return true
 	default:
 		return false
@@ -57,10 +71,18 @@ func (s CommissionStatus) Label() string {
 		return "Pending"
 	case CommissionApproved:
 		return "Approved"
+	case CommissionInPayout:
+		return "In Payout"
 	case CommissionPaid:
 		return "Paid"
 	case CommissionCancelled:
 		return "Cancelled"
+	case CommissionRejected:
+		return "Rejected"
+	case CommissionPartiallyReversed:
+		return "Partially Reversed"
+	case CommissionReversed:
+		return "Reversed"
 	default:
 		return "Unknown"
 	}
@@ -103,6 +125,19 @@ func (s CommissionStatus) IsPaid() bool {
 	return s == CommissionPaid
 }
 
+// IsInPayout returns true if the commission is in a payout that has not
+// completed.
+func (s CommissionStatus) IsInPayout() bool {
+	return s == CommissionInPayout
+}
+
+// WasPaid returns true if the commission has left the agent's available
+// balance for a payout, including paid commissions that were later
+// partially clawed back. Anything clawed back from it is owed by the agent.
+func (s CommissionStatus) WasPaid() bool {
+	return s == CommissionInPayout || s == CommissionPaid || s == CommissionPartiallyReversed
+}
+
 // IsPayable returns true if commission can be included in a payout.
 func (s CommissionStatus) IsPayable() bool {
 	return s == CommissionApproved
@@ -110,7 +145,7 @@ func (s CommissionStatus) IsPayable() bool {
 
 // IsTerminal returns true if status is terminal.
 func (s CommissionStatus) IsTerminal() bool {
-	return s == CommissionPaid || s == CommissionCancelled
+	return s == CommissionCancelled || s == CommissionRejected || s == CommissionReversed
 }
 
 // ParseCommissionStatus parses a string into a CommissionStatus.
@@ -0,0 +1,74 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+)
+
+// CommissionType distinguishes commissions earned on an agent's own sales
+// from commissions derived from another agent's sale.
+type CommissionType string
+
+// Commission type constants
+const (
+	// CommissionTypeSale is earned by the agent who sold the order
+	CommissionTypeSale CommissionType = "sale"
+	// CommissionTypeOverride is earned by a team leader on a member's sale
+	CommissionTypeOverride CommissionType = "override"
+	// CommissionTypeDownline is earned by an upline sponsor on a sale made
+	// in their downline
+	CommissionTypeDownline CommissionType = "downline"
+	// CommissionTypeAdjustment is a signed amount an admin adds to or
+	// takes from an agent's earnings, such as a bonus, a penalty or a
+	// correction on resolving a dispute
+	CommissionTypeAdjustment CommissionType = "adjustment"
+)
+
+// ErrInvalidCommissionType is returned for invalid type values.
+var ErrInvalidCommissionType = errors.New("invalid commission type")
+
+// IsValid returns true if the type is valid.
+func (t CommissionType) IsValid() bool {
+	switch t {
+	case CommissionTypeSale, CommissionTypeOverride, CommissionTypeDownline, CommissionTypeAdjustment:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation.
+func (t CommissionType) String() string {
+	return string(t)
+}
+
+// Label returns a human-readable label.
+func (t CommissionType) Label() string {
+	switch t {
+	case CommissionTypeSale:
+		return "Sale"
+	case CommissionTypeOverride:
+		return "Team Override"
+	case CommissionTypeDownline:
+		return "Downline"
+	case CommissionTypeAdjustment:
+		return "Adjustment"
+	default:
+		return "Unknown"
+	}
+}
+
+// IsDerived returns true if the commission is derived from another
+// agent's sale commission.
+func (t CommissionType) IsDerived() bool {
+	return t == CommissionTypeOverride || t == CommissionTypeDownline
+}
+
+// ParseCommissionType parses a string into a CommissionType.
+func ParseCommissionType(str string) (CommissionType, error) {
+	t := CommissionType(str)
+	if !t.IsValid() {
+		return "", fmt.Errorf("%w: %s", ErrInvalidCommissionType, str)
+	}
+	return t, nil
+}
@@ -0,0 +1,94 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DisputeStatus represents the status of an agent's commission dispute.
+type DisputeStatus string
+
+// Dispute status constants
+const (
+	DisputeOpen        DisputeStatus = "open"
+	DisputeUnderReview DisputeStatus = "under_review"
+	DisputeResolved    DisputeStatus = "resolved"
+	DisputeRejected    DisputeStatus = "rejected"
+)
+
+// validDisputeTransitions defines allowed state transitions.
+var validDisputeTransitions = map[DisputeStatus][]DisputeStatus{
+	DisputeOpen:        {DisputeUnderReview, DisputeResolved, DisputeRejected},
+	DisputeUnderReview: {DisputeResolved, DisputeRejected},
+	DisputeResolved:    {}, // Terminal
+	DisputeRejected:    {}, // Terminal
+}
+
+// ErrInvalidDisputeStatus is returned for invalid status values.
+var ErrInvalidDisputeStatus = errors.New("invalid dispute status")
+
+// ErrInvalidDisputeTransition is returned for invalid transitions.
+var ErrInvalidDisputeTransition = errors.New("invalid dispute status transition")
+
+// IsValid returns true if the status is valid.
+func (s DisputeStatus) IsValid() bool {
+	switch s {
+	case DisputeOpen, DisputeUnderReview, DisputeResolved, DisputeRejected:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation.
+func (s DisputeStatus) String() string {
+	return string(s)
+}
+
+// Label returns a human-readable label.
+func (s DisputeStatus) Label() string {
+	switch s {
+	case DisputeOpen:
+		return "Open"
+	case DisputeUnderReview:
+		return "Under Review"
+	case DisputeResolved:
+		return "Resolved"
+	case DisputeRejected:
+		return "Rejected"
+	default:
+		return "Unknown"
+	}
+}
+
+// CanTransitionTo returns true if the status can transition to target.
+func (s DisputeStatus) CanTransitionTo(target DisputeStatus) bool {
+	for _, status := range validDisputeTransitions[s] {
+		if status == target {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionTo attempts to transition to the target status.
+func (s DisputeStatus) TransitionTo(target DisputeStatus) (DisputeStatus, error) {
+	if !s.CanTransitionTo(target) {
+		return s, fmt.Errorf("%w: cannot transition from %s to %s", ErrInvalidDisputeTransition, s, target)
+	}
+	return target, nil
+}
+
+// IsActive returns true if the dispute is still waiting for a decision.
+func (s DisputeStatus) IsActive() bool {
+	return s == DisputeOpen || s == DisputeUnderReview
+}
+
+// ParseDisputeStatus parses a string into a DisputeStatus.
+func ParseDisputeStatus(str string) (DisputeStatus, error) {
+	s := DisputeStatus(str)
+	if !s.IsValid() {
+		return "", fmt.Errorf("%w: %s", ErrInvalidDisputeStatus, str)
+	}
+	return s, nil
+}
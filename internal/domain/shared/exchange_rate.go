@@ -0,0 +1,115 @@
+package shared
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+)
+
+// ErrInvalidExchangeRate is returned for rates that are not positive
+// decimals between two currencies.
+var ErrInvalidExchangeRate = errors.New("exchange rate must be a positive decimal between two currencies")
+
+// exchangeRateDecimals is the number of decimal places a rate is kept to.
+const exchangeRateDecimals = 10
+
+// ExchangeRate is the price of one unit of a base currency in a quote
+// currency, e.g. 1 USD = 4.7125 MYR. The rate is held as an exact decimal
+// so conversions are reproducible.
+//
+// The zero value converts an amount to itself.
+type ExchangeRate struct {
+	base  string
+	quote string
+	rate  *big.Rat
+}
+
+// NewExchangeRate parses a rate such as "4.7125" from base to quote.
+func NewExchangeRate(base, quote, rate string) (ExchangeRate, error) {
+	base, err := ParseCurrency(base)
+	if err != nil {
+		return ExchangeRate{}, err
+	}
+	quote, err = ParseCurrency(quote)
+	if err != nil {
+		return ExchangeRate{}, err
+	}
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(rate))
+	if !ok || r.Sign() <= 0 {
+		return ExchangeRate{}, ErrInvalidExchangeRate
+	}
+	if base == quote && r.Cmp(big.NewRat(1, 1)) != 0 {
+		return ExchangeRate{}, ErrInvalidExchangeRate
+	}
+	return ExchangeRate{base: base, quote: quote, rate: r}, nil
+}
+
+// IdentityRate returns the rate of a currency to itself.
+func IdentityRate(currency string) ExchangeRate {
+	currency = normalizeCurrency(currency)
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	return ExchangeRate{base: currency, quote: currency, rate: big.NewRat(1, 1)}
+}
+
+// Base returns the currency converted from.
+func (r ExchangeRate) Base() string { return r.base }
+
+// Quote returns the currency converted to.
+func (r ExchangeRate) Quote() string { return r.quote }
+
+// IsZero returns true for the zero value.
+func (r ExchangeRate) IsZero() bool { return r.rate == nil }
+
+// IsIdentity returns true if the rate converts a currency to itself.
+func (r ExchangeRate) IsIdentity() bool {
+	return r.rate == nil || r.base == r.quote
+}
+
+// String returns the rate as a decimal, e.g. "4.7125".
+func (r ExchangeRate) String() string {
+	if r.rate == nil {
+		return "1"
+	}
+	s := r.rate.FloatString(exchangeRateDecimals)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// Float64 returns the rate as a float, for JSON responses.
+func (r ExchangeRate) Float64() float64 {
+	if r.rate == nil {
+		return 1
+	}
+	f, _ := r.rate.Float64()
+	return f
+}
+
+// Inverse returns the rate from quote back to base.
+func (r ExchangeRate) Inverse() ExchangeRate {
+	if r.rate == nil {
+		return r
+	}
+	return ExchangeRate{base: r.quote, quote: r.base, rate: new(big.Rat).Inv(r.rate)}
+}
+
+// Convert converts an amount in the base currency to the quote currency,
+// rounding half up to the quote currency's minor unit.
+func (r ExchangeRate) Convert(m Money) (Money, error) {
+	if r.IsIdentity() {
+		return m, nil
+	}
+	if m.Currency() != r.base {
+		return Money{}, ErrCurrencyMismatch
+	}
+	// Minor units scale by the difference in the currencies' exponents
+	v := new(big.Rat).Mul(new(big.Rat).SetInt64(m.minor), r.rate)
+	v.Mul(v, new(big.Rat).SetInt(pow10(CurrencyExponent(r.quote))))
+	v.Quo(v, new(big.Rat).SetInt(pow10(CurrencyExponent(r.base))))
+	minor, ok := roundRat(v, RoundHalfUp)
+	if !ok {
+		return Money{}, ErrInvalidMoney
+	}
+	return Money{minor: minor, currency: r.quote}, nil
+}
@@ -0,0 +1,94 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+)
+
+// LedgerAccount is one of the accounts each agent has in the earnings
+// ledger.
+type LedgerAccount string
+
+// Ledger account constants
+const (
+	// LedgerPending holds commissions waiting for approval
+	LedgerPending LedgerAccount = "pending"
+	// LedgerAvailable holds approved commissions waiting to be paid out
+	LedgerAvailable LedgerAccount = "available"
+	// LedgerInPayout holds the net amount of payouts created but not yet
+	// paid, until they complete, fail or are cancelled
+	LedgerInPayout LedgerAccount = "in_payout"
+	// LedgerPaidOut holds everything paid out to the agent
+	LedgerPaidOut LedgerAccount = "paid_out"
+	// LedgerClawbackReceivable holds clawbacks on paid commissions that
+	// the agent owes until they are netted against a payout
+	LedgerClawbackReceivable LedgerAccount = "clawback_receivable"
+	// LedgerTaxWithheld holds the tax withheld from the agent's payouts
+	// and paid to the tax authority on their behalf
+	LedgerTaxWithheld LedgerAccount = "tax_withheld"
+	// LedgerReserve holds the share of the agent's payouts kept back in
+	// reserve against future clawbacks, until it is released to them
+	LedgerReserve LedgerAccount = "reserve"
+	// LedgerCommissionExpense is the business's side of every entry: what
+	// the agent's commissions have cost, net of clawbacks
+	LedgerCommissionExpense LedgerAccount = "commission_expense"
+)
+
+// ErrInvalidLedgerAccount is returned for invalid account values.
+var ErrInvalidLedgerAccount = errors.New("invalid ledger account")
+
+// IsValid returns true if the account is valid.
+func (a LedgerAccount) IsValid() bool {
+	switch a {
+	case LedgerPending, LedgerAvailable, LedgerInPayout, LedgerPaidOut, LedgerClawbackReceivable,
+		LedgerTaxWithheld, LedgerReserve, LedgerCommissionExpense:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation.
+func (a LedgerAccount) String() string {
+	return string(a)
+}
+
+// Label returns a human-readable label.
+func (a LedgerAccount) Label() string {
+	switch a {
+	case LedgerPending:
+		return "Pending"
+	case LedgerAvailable:
+		return "Available"
+	case LedgerInPayout:
+		return "In Payout"
+	case LedgerPaidOut:
+		return "Paid Out"
+	case LedgerClawbackReceivable:
+		return "Clawback Receivable"
+	case LedgerTaxWithheld:
+		return "Tax Withheld"
+	case LedgerReserve:
+		return "Reserve"
+	case LedgerCommissionExpense:
+		return "Commission Expense"
+	default:
+		return "Unknown"
+	}
+}
+
+// IsDebitNormal returns true if debits increase the account's balance.
+// The agent's pending, available, in payout, paid out, tax withheld and
+// reserve accounts are owed to or paid for the agent, so credits increase them.
+func (a LedgerAccount) IsDebitNormal() bool {
+	return a == LedgerClawbackReceivable || a == LedgerCommissionExpense
+}
+
+// ParseLedgerAccount parses a string into a LedgerAccount.
+func ParseLedgerAccount(str string) (LedgerAccount, error) {
+	a := LedgerAccount(str)
+	if !a.IsValid() {
+		return "", fmt.Errorf("%w: %s", ErrInvalidLedgerAccount, str)
+	}
+	return a, nil
+}
@@ -0,0 +1,355 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// DefaultCurrency is the currency amounts are in unless stated otherwise.
+const DefaultCurrency = "MYR"
+
+// ErrCurrencyMismatch is returned when amounts in different currencies are combined.
+var ErrCurrencyMismatch = errors.New("money currencies do not match")
+
+// ErrInvalidMoney is returned for amounts that cannot be parsed.
+var ErrInvalidMoney = errors.New("invalid money amount")
+
+// ErrInvalidCurrency is returned for codes that are not ISO 4217 shaped.
+var ErrInvalidCurrency = errors.New("currency must be a three-letter ISO 4217 code")
+
+// ErrUnsupportedCurrency is returned for currencies whose amounts cannot
+// be stored exactly. It wraps ErrInvalidCurrency.
+var ErrUnsupportedCurrency = fmt.Errorf("%w with at most %d decimal places", ErrInvalidCurrency, MaxCurrencyExponent)
+
+// MaxCurrencyExponent is the most decimal places a supported currency may
+// have: amounts are stored in columns with two.
+const MaxCurrencyExponent = 2
+
+// RoundingMode decides how an amount that falls between two minor units
+// is rounded.
+type RoundingMode int
+
+// Rounding modes
+const (
+	RoundHalfUp   RoundingMode = iota // Halves away from zero (commercial rounding)
+	RoundHalfEven                     // Halves to the even unit (banker's rounding)
+	RoundDown                         // Towards zero (truncate)
+	RoundUp                           // Away from zero
+)
+
+// rateScale is the precision percentages are applied at: six decimal
+// places, enough for any stored rate or split share.
+const rateScale = 1_000_000
+
+// Money is an exact amount of a currency, held as a whole number of the
+// currency's minor units (sen for MYR) so that sums never drift.
+// Multiplications round explicitly.
+//
+// The zero value is zero in no particular currency, and takes the
+// currency of whatever it is combined with.
+type Money struct {
+	minor    int64
+	currency string
+}
+
+// NewMoney creates an amount from minor units.
+func NewMoney(minor int64, currency string) Money {
+	return Money{minor: minor, currency: normalizeCurrency(currency)}
+}
+
+// ZeroMoney returns zero in a currency.
+func ZeroMoney(currency string) Money {
+	return NewMoney(0, currency)
+}
+
+// MoneyFromFloat converts a decimal amount, such as a DECIMAL column or a
+// JSON number, rounding half up to the nearest minor unit.
+func MoneyFromFloat(amount float64, currency string) Money {
+	currency = normalizeCurrency(currency)
+	scaled := amount * math.Pow10(CurrencyExponent(currency))
+	return Money{minor: int64(math.Round(scaled)), currency: currency}
+}
+
+// ParseMoney parses a decimal string such as "1234.50" exactly. Digits
+// beyond the currency's minor unit are rounded half up.
+func ParseMoney(amount, currency string) (Money, error) {
+	currency = normalizeCurrency(currency)
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(amount))
+	if !ok {
+		return Money{}, ErrInvalidMoney
+	}
+	r.Mul(r, new(big.Rat).SetInt(pow10(CurrencyExponent(currency))))
+	minor, ok := roundRat(r, RoundHalfUp)
+	if !ok {
+		return Money{}, ErrInvalidMoney
+	}
+	return Money{minor: minor, currency: currency}, nil
+}
+
+// Minor returns the amount in minor units.
+func (m Money) Minor() int64 { return m.minor }
+
+// Currency returns the ISO 4217 currency code.
+func (m Money) Currency() string {
+	if m.currency == "" {
+		return DefaultCurrency
+	}
+	return m.currency
+}
+
+// Float64 returns the amount in major units, for JSON and DECIMAL columns.
+func (m Money) Float64() float64 {
+	return float64(m.minor) / math.Pow10(CurrencyExponent(m.Currency()))
+}
+
+// String returns the amount with its minor units, e.g. "1234.50".
+func (m Money) String() string {
+	exp := CurrencyExponent(m.Currency())
+	minor := m.minor
+	sign := ""
+	if minor < 0 {
+		sign, minor = "-", -minor
+	}
+	if exp == 0 {
+		return fmt.Sprintf("%s%d", sign, minor)
+	}
+	unit := int64(math.Pow10(exp))
+	return fmt.Sprintf("%s%d.%0*d", sign, minor/unit, exp, minor%unit)
+}
+
+// IsZero returns true if the amount is zero.
+func (m Money) IsZero() bool { return m.minor == 0 }
+
+// IsPositive returns true if the amount is above zero.
+func (m Money) IsPositive() bool { return m.minor > 0 }
+
+// IsNegative returns true if the amount is below zero.
+func (m Money) IsNegative() bool { return m.minor < 0 }
+
+// Add returns m + other. It panics if the currencies differ; use
+// SameCurrency to check amounts from outside the service.
+func (m Money) Add(other Money) Money {
+	currency := m.combine(other)
+	return Money{minor: m.minor + other.minor, currency: currency}
+}
+
+// Sub returns m - other. It panics if the currencies differ.
+func (m Money) Sub(other Money) Money {
+	currency := m.combine(other)
+	return Money{minor: m.minor - other.minor, currency: currency}
+}
+
+// Neg returns -m.
+func (m Money) Neg() Money {
+	return Money{minor: -m.minor, currency: m.currency}
+}
+
+// Abs returns the amount without its sign.
+func (m Money) Abs() Money {
+	if m.minor < 0 {
+		return m.Neg()
+	}
+	return m
+}
+
+// Cmp compares two amounts, returning -1, 0 or +1. It panics if the
+// currencies differ.
+func (m Money) Cmp(other Money) int {
+	m.combine(other)
+	switch {
+	case m.minor < other.minor:
+		return -1
+	case m.minor > other.minor:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Min returns the smaller of two amounts.
+func (m Money) Min(other Money) Money {
+	if m.Cmp(other) <= 0 {
+		return m
+	}
+	return other
+}
+
+// SameCurrency reports whether two amounts can be combined.
+func (m Money) SameCurrency(other Money) bool {
+	return m.currency == "" || other.currency == "" || m.currency == other.currency
+}
+
+// MulPercent returns the given percentage of the amount, such as a
+// commission rate or a split share, rounded with the given mode.
+func (m Money) MulPercent(percent float64, mode RoundingMode) Money {
+	scaled := int64(math.Round(percent * rateScale))
+	return m.MulRatio(scaled, 100*rateScale, mode)
+}
+
+// MulRatio returns the amount times num/den, rounded with the given mode.
+// It is exact for any amount and ratio.
+func (m Money) MulRatio(num, den int64, mode RoundingMode) Money {
+	if den == 0 {
+		return Money{currency: m.currency}
+	}
+	r := new(big.Rat).SetFrac(new(big.Int).Mul(big.NewInt(m.minor), big.NewInt(num)), big.NewInt(den))
+	minor, _ := roundRat(r, mode)
+	return Money{minor: minor, currency: m.currency}
+}
+
+// Allocate splits the amount in proportion to the weights without losing
+// or creating a minor unit: the parts always sum to the amount. Units left
+// over after rounding down go to the parts with the largest remainders.
+func (m Money) Allocate(weights []int64) []Money {
+	parts := make([]Money, len(weights))
+	var total int64
+	for _, w := range weights {
+		total += w
+	}
+	if total <= 0 {
+		for i := range parts {
+			parts[i] = Money{currency: m.currency}
+		}
+		return parts
+	}
+
+	type remainder struct {
+		index int
+		value *big.Int
+	}
+	remainders := make([]remainder, len(weights))
+	allocated := int64(0)
+	for i, w := range weights {
+		product := new(big.Int).Mul(big.NewInt(m.minor), big.NewInt(w))
+		quotient, rem := new(big.Int).QuoRem(product, big.NewInt(total), new(big.Int))
+		parts[i] = Money{minor: quotient.Int64(), currency: m.currency}
+		remainders[i] = remainder{index: i, value: rem.Abs(rem)}
+		allocated += quotient.Int64()
+	}
+
+	left := m.minor - allocated
+	step := int64(1)
+	if left < 0 {
+		step, left = -1, -left
+	}
+	for ; left > 0; left-- {
+		best := -1
+		for j, r := range remainders {
+			if r.value == nil {
+				continue
+			}
+			if best < 0 || r.value.Cmp(remainders[best].value) > 0 {
+				best = j
+			}
+		}
+		if best < 0 {
+			break
+		}
+		parts[remainders[best].index].minor += step
+		remainders[best].value = nil
+	}
+	return parts
+}
+
+// SumMoney adds up amounts in one currency.
+func SumMoney(amounts ...Money) Money {
+	var total Money
+	for _, a := range amounts {
+		total = total.Add(a)
+	}
+	return total
+}
+
+// combine returns the currency of m and other combined, panicking if
+// they differ.
+func (m Money) combine(other Money) string {
+	if !m.SameCurrency(other) {
+		panic(fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.currency, other.currency))
+	}
+	if m.currency == "" {
+		return other.currency
+	}
+	return m.currency
+}
+
+// currencyExponents lists currencies whose minor unit is not a hundredth.
+var currencyExponents = map[string]int{
+	"BHD": 3,
+	"IDR": 0,
+	"JPY": 0,
+	"KRW": 0,
+	"KWD": 3,
+	"OMR": 3,
+	"VND": 0,
+}
+
+// CurrencyExponent returns the number of decimal places of a currency's
+// minor unit.
+func CurrencyExponent(currency string) int {
+	if exp, ok := currencyExponents[normalizeCurrency(currency)]; ok {
+		return exp
+	}
+	return 2
+}
+
+// ParseCurrency validates and normalises a currency code. An empty code
+// is the default currency. Currencies with more than MaxCurrencyExponent
+// decimal places, such as BHD, are not supported.
+func ParseCurrency(currency string) (string, error) {
+	currency = normalizeCurrency(currency)
+	if currency == "" {
+		return DefaultCurrency, nil
+	}
+	if len(currency) != 3 {
+		return "", ErrInvalidCurrency
+	}
+	for _, r := range currency {
+		if r < 'A' || r > 'Z' {
+			return "", ErrInvalidCurrency
+		}
+	}
+	if CurrencyExponent(currency) > MaxCurrencyExponent {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedCurrency, currency)
+	}
+	return currency, nil
+}
+
+func normalizeCurrency(currency string) string {
+	return strings.ToUpper(strings.TrimSpace(currency))
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// roundRat rounds a rational to an integer with the given mode.
+func roundRat(r *big.Rat, mode RoundingMode) (int64, bool) {
+	num, den := r.Num(), r.Denom()
+	quotient, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Sign() != 0 {
+		// Compare twice the remainder with the denominator to find halves
+		twice := new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2))
+		half := twice.Cmp(den)
+		away := false
+		switch mode {
+		case RoundHalfUp:
+			away = half >= 0
+		case RoundHalfEven:
+			away = half > 0 || (half == 0 && quotient.Bit(0) == 1)
+		case RoundUp:
+			away = true
+		case RoundDown:
+			away = false
+		}
+		if away {
+			quotient.Add(quotient, big.NewInt(int64(num.Sign())))
+		}
+	}
+	if !quotient.IsInt64() {
+		return 0, false
+	}
+	return quotient.Int64(), true
+}
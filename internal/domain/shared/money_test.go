@@ -0,0 +1,139 @@
+package shared
+
+import "testing"
+
+func TestMulPercent(t *testing.T) {
+	tests := []struct {
+		name    string
+		minor   int64
+		percent float64
+		mode    RoundingMode
+		want    int64
+	}{
+		{"exact", 10000, 10, RoundHalfUp, 1000},
+		{"fractional rate", 12345, 7.5, RoundHalfUp, 926},
+		{"fractional rate down", 12345, 7.5, RoundDown, 925},
+		{"half up", 1005, 50, RoundHalfUp, 503},
+		{"half even to even", 1005, 50, RoundHalfEven, 502},
+		{"half even from odd", 1015, 50, RoundHalfEven, 508},
+		{"negative half up", -1005, 50, RoundHalfUp, -503},
+		{"negative half even", -1005, 50, RoundHalfEven, -502},
+		{"below half a unit", 1, 0.1, RoundHalfUp, 0},
+		{"below half a unit up", 1, 0.1, RoundUp, 1},
+		{"third", 10000, 33.333333, RoundHalfUp, 3333},
+		{"zero rate", 10000, 0, RoundHalfUp, 0},
+		{"full rate", 10000, 100, RoundHalfUp, 10000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewMoney(tt.minor, "MYR").MulPercent(tt.percent, tt.mode)
+			if got.Minor() != tt.want {
+				t.Errorf("MulPercent(%v) of %d = %d, want %d", tt.percent, tt.minor, got.Minor(), tt.want)
+			}
+			if got.Currency() != "MYR" {
+				t.Errorf("currency = %s, want MYR", got.Currency())
+			}
+		})
+	}
+}
+
+func TestRoundingModes(t *testing.T) {
+	// Each amount is minor/10 of a unit
+	tests := []struct {
+		minor int64
+		want  map[RoundingMode]int64
+	}{
+		{24, map[RoundingMode]int64{RoundHalfUp: 2, RoundHalfEven: 2, RoundDown: 2, RoundUp: 3}},
+		{25, map[RoundingMode]int64{RoundHalfUp: 3, RoundHalfEven: 2, RoundDown: 2, RoundUp: 3}},
+		{26, map[RoundingMode]int64{RoundHalfUp: 3, RoundHalfEven: 3, RoundDown: 2, RoundUp: 3}},
+		{35, map[RoundingMode]int64{RoundHalfUp: 4, RoundHalfEven: 4, RoundDown: 3, RoundUp: 4}},
+		{30, map[RoundingMode]int64{RoundHalfUp: 3, RoundHalfEven: 3, RoundDown: 3, RoundUp: 3}},
+		{-25, map[RoundingMode]int64{RoundHalfUp: -3, RoundHalfEven: -2, RoundDown: -2, RoundUp: -3}},
+		{-26, map[RoundingMode]int64{RoundHalfUp: -3, RoundHalfEven: -3, RoundDown: -2, RoundUp: -3}},
+		{-35, map[RoundingMode]int64{RoundHalfUp: -4, RoundHalfEven: -4, RoundDown: -3, RoundUp: -4}},
+	}
+	for _, tt := range tests {
+		for mode, want := range tt.want {
+			got := NewMoney(tt.minor, "MYR").MulRatio(1, 10, mode)
+			if got.Minor() != want {
+				t.Errorf("%d/10 with mode %d = %d, want %d", tt.minor, mode, got.Minor(), want)
+			}
+		}
+	}
+}
+
+func TestParseMoneyRoundsHalfUp(t *testing.T) {
+	tests := []struct {
+		amount   string
+		currency string
+		want     int64
+	}{
+		{"1234.50", "MYR", 123450},
+		{"0.005", "MYR", 1},
+		{"0.004", "MYR", 0},
+		{"-0.005", "MYR", -1},
+		{"1500.5", "JPY", 1501},
+	}
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.amount, tt.currency)
+		if err != nil {
+			t.Fatalf("ParseMoney(%q): %v", tt.amount, err)
+		}
+		if got.Minor() != tt.want {
+			t.Errorf("ParseMoney(%q, %s) = %d, want %d", tt.amount, tt.currency, got.Minor(), tt.want)
+		}
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		name    string
+		minor   int64
+		weights []int64
+		want    []int64
+	}{
+		{"exact", 1000, []int64{3, 7}, []int64{300, 700}},
+		{"thirds", 100, []int64{1, 1, 1}, []int64{34, 33, 33}},
+		{"negative thirds", -100, []int64{1, 1, 1}, []int64{-34, -33, -33}},
+		{"tie goes to first", 5, []int64{1, 1}, []int64{3, 2}},
+		{"largest remainders", 1001, []int64{1, 2, 3}, []int64{167, 334, 500}},
+		{"one sen", 1, []int64{50, 50}, []int64{1, 0}},
+		{"zero weight", 100, []int64{0, 1}, []int64{0, 100}},
+		{"single", 12345, []int64{7}, []int64{12345}},
+		{"no weight", 100, []int64{0, 0}, []int64{0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parts := NewMoney(tt.minor, "MYR").Allocate(tt.weights)
+			if len(parts) != len(tt.want) {
+				t.Fatalf("got %d parts, want %d", len(parts), len(tt.want))
+			}
+			for i, part := range parts {
+				if part.Minor() != tt.want[i] {
+					t.Errorf("part %d = %d, want %d", i, part.Minor(), tt.want[i])
+				}
+				if part.Currency() != "MYR" {
+					t.Errorf("part %d currency = %s, want MYR", i, part.Currency())
+				}
+			}
+		})
+	}
+}
+
+func TestAllocateSumsToAmount(t *testing.T) {
+	weights := [][]int64{
+		{1, 1, 1},
+		{1, 2, 3},
+		{33_330_000, 33_330_000, 33_340_000},
+		{1, 1, 1, 1, 1, 1, 1},
+		{999_999, 1},
+	}
+	for _, minor := range []int64{0, 1, 2, 99, 100, 101, 12345, 999_999_999, -1, -12345} {
+		for _, w := range weights {
+			parts := NewMoney(minor, "MYR").Allocate(w)
+			if sum := SumMoney(parts...); sum.Minor() != minor {
+				t.Errorf("Allocate(%d, %v) sums to %d", minor, w, sum.Minor())
+			}
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+)
+
+// PayoutMethodType is the kind of account an agent is paid into.
+type PayoutMethodType string
+
+// Payout method type constants
+const (
+	PayoutMethodBankAccount PayoutMethodType = "bank_account"
+	PayoutMethodEWallet     PayoutMethodType = "ewallet"
+)
+
+// ErrInvalidPayoutMethodType is returned for invalid type values.
+var ErrInvalidPayoutMethodType = errors.New("invalid payout method type")
+
+// IsValid returns true if the type is valid.
+func (t PayoutMethodType) IsValid() bool {
+	switch t {
+	case PayoutMethodBankAccount, PayoutMethodEWallet:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation.
+func (t PayoutMethodType) String() string {
+	return string(t)
+}
+
+// Label returns a human-readable label.
+func (t PayoutMethodType) Label() string {
+	switch t {
+	case PayoutMethodBankAccount:
+		return "Bank Account"
+	case PayoutMethodEWallet:
+		return "E-Wallet"
+	default:
+		return "Unknown"
+	}
+}
+
+// ParsePayoutMethodType parses a string into a PayoutMethodType.
+func ParsePayoutMethodType(str string) (PayoutMethodType, error) {
+	t := PayoutMethodType(str)
+	if !t.IsValid() {
+		return "", fmt.Errorf("%w: %s", ErrInvalidPayoutMethodType, str)
+	}
+	return t, nil
+}
+
+// PayoutMethodStatus is whether a payout method's details have been
+// verified by the agent.
+type PayoutMethodStatus string
+
+// Payout method status constants
+const (
+	PayoutMethodUnverified PayoutMethodStatus = "unverified"
+	PayoutMethodVerified   PayoutMethodStatus = "verified"
+)
+
+// IsValid returns true if the status is valid.
+func (s PayoutMethodStatus) IsValid() bool {
+	return s == PayoutMethodUnverified || s == PayoutMethodVerified
+}
+
+// String returns the string representation.
+func (s PayoutMethodStatus) String() string {
+	return string(s)
+}
+
+// Label returns a human-readable label.
+func (s PayoutMethodStatus) Label() string {
+	switch s {
+	case PayoutMethodUnverified:
+		return "Unverified"
+	case PayoutMethodVerified:
+		return "Verified"
+	default:
+		return "Unknown"
+	}
+}
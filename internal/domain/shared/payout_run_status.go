@@ -0,0 +1,87 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+)
+
+// PayoutRunStatus represents the status of a payout run.
+type PayoutRunStatus string
+
+// Payout run status constants
+const (
+	// PayoutRunDraft runs are previews: they show what committing would
+	// pay and are never stored.
+	PayoutRunDraft     PayoutRunStatus = "draft"
+	PayoutRunCommitted PayoutRunStatus = "committed"
+	PayoutRunCancelled PayoutRunStatus = "cancelled"
+)
+
+// validPayoutRunTransitions defines allowed state transitions.
+var validPayoutRunTransitions = map[PayoutRunStatus][]PayoutRunStatus{
+	PayoutRunDraft:     {PayoutRunCommitted},
+	PayoutRunCommitted: {PayoutRunCancelled},
+	PayoutRunCancelled: {}, // Terminal
+}
+
+// ErrInvalidPayoutRunStatus is returned for invalid status values.
+var ErrInvalidPayoutRunStatus = errors.New("invalid payout run status")
+
+// ErrInvalidPayoutRunTransition is returned for invalid transitions.
+var ErrInvalidPayoutRunTransition = errors.New("invalid payout run status transition")
+
+// IsValid returns true if the status is valid.
+func (s PayoutRunStatus) IsValid() bool {
+	switch s {
+	case PayoutRunDraft, PayoutRunCommitted, PayoutRunCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation.
+func (s PayoutRunStatus) String() string {
+	return string(s)
+}
+
+// Label returns a human-readable label.
+func (s PayoutRunStatus) Label() string {
+	switch s {
+	case PayoutRunDraft:
+		return "Draft"
+	case PayoutRunCommitted:
+		return "Committed"
+	case PayoutRunCancelled:
+		return "Cancelled"
+	default:
+		return "Unknown"
+	}
+}
+
+// CanTransitionTo returns true if the status can transition to target.
+func (s PayoutRunStatus) CanTransitionTo(target PayoutRunStatus) bool {
+	for _, status := range validPayoutRunTransitions[s] {
+		if status == target {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionTo attempts to transition to the target status.
+func (s PayoutRunStatus) TransitionTo(target PayoutRunStatus) (PayoutRunStatus, error) {
+	if !s.CanTransitionTo(target) {
+		return s, fmt.Errorf("%w: cannot transition from %s to %s", ErrInvalidPayoutRunTransition, s, target)
+	}
+	return target, nil
+}
+
+// ParsePayoutRunStatus parses a string into a PayoutRunStatus.
+func ParsePayoutRunStatus(str string) (PayoutRunStatus, error) {
+	s := PayoutRunStatus(str)
+	if !s.IsValid() {
+		return "", fmt.Errorf("%w: %s", ErrInvalidPayoutRunStatus, str)
+	}
+	return s, nil
+}
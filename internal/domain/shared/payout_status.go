@@ -21,9 +21,9 @@ const (
 var validPayoutTransitions = map[PayoutStatus][]PayoutStatus{
 	PayoutPending:    {PayoutProcessing, PayoutCancelled},
 	PayoutProcessing: {PayoutCompleted, PayoutFailed},
-	PayoutCompleted:  {},              // Terminal
-	PayoutFailed:     {PayoutPending}, // Can retry
-	PayoutCancelled:  {},              // Terminal
+	PayoutCompleted:  {},                               // Terminal
+	PayoutFailed:     {PayoutPending, PayoutCancelled}, // Can retry
+	PayoutCancelled:  {},                               // Terminal
 }
 
 // ErrInvalidPayoutStatus is returned for invalid status values.
@@ -0,0 +1,89 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ReserveStatus represents the status of a payout reserve.
+type ReserveStatus string
+
+// Reserve status constants
+const (
+	// ReserveHeld reserves are kept back from the agent until they are due
+	ReserveHeld ReserveStatus = "held"
+	// ReserveReleased reserves have been paid to the agent, less any
+	// clawbacks they covered, by a release payout
+	ReserveReleased ReserveStatus = "released"
+	// ReserveCancelled reserves were never held: their payout was cancelled
+	ReserveCancelled ReserveStatus = "cancelled"
+)
+
+// validReserveTransitions defines allowed state transitions.
+var validReserveTransitions = map[ReserveStatus][]ReserveStatus{
+	ReserveHeld:      {ReserveReleased, ReserveCancelled},
+	ReserveReleased:  {ReserveHeld}, // The release payout was cancelled
+	ReserveCancelled: {},            // Terminal
+}
+
+// ErrInvalidReserveStatus is returned for invalid status values.
+var ErrInvalidReserveStatus = errors.New("invalid reserve status")
+
+// ErrInvalidReserveTransition is returned for invalid transitions.
+var ErrInvalidReserveTransition = errors.New("invalid reserve status transition")
+
+// IsValid returns true if the status is valid.
+func (s ReserveStatus) IsValid() bool {
+	switch s {
+	case ReserveHeld, ReserveReleased, ReserveCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// String returns the string representation.
+func (s ReserveStatus) String() string {
+	return string(s)
+}
+
+// Label returns a human-readable label.
+func (s ReserveStatus) Label() string {
+	switch s {
+	case ReserveHeld:
+		return "Held"
+	case ReserveReleased:
+		return "Released"
+	case ReserveCancelled:
+		return "Cancelled"
+	default:
+		return "Unknown"
+	}
+}
+
+// CanTransitionTo returns true if the status can transition to target.
+func (s ReserveStatus) CanTransitionTo(target ReserveStatus) bool {
+	for _, status := range validReserveTransitions[s] {
+		if status == target {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionTo attempts to transition to the target status.
+func (s ReserveStatus) TransitionTo(target ReserveStatus) (ReserveStatus, error) {
+	if !s.CanTransitionTo(target) {
+		return s, fmt.Errorf("%w: cannot transition from %s to %s", ErrInvalidReserveTransition, s, target)
+	}
+	return target, nil
+}
+
+// ParseReserveStatus parses a string into a ReserveStatus.
+func ParseReserveStatus(str string) (ReserveStatus, error) {
+	s := ReserveStatus(str)
+	if !s.IsValid() {
+		return "", fmt.Errorf("%w: %s", ErrInvalidReserveStatus, str)
+	}
+	return s, nil
+}
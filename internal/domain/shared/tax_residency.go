@@ -0,0 +1,50 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+)
+
+// TaxResidency is whether an agent is tax resident in the country the
+// business withholds tax for.
+type TaxResidency string
+
+// Tax residency constants
+const (
+	TaxResident    TaxResidency = "resident"
+	TaxNonResident TaxResidency = "non_resident"
+)
+
+// ErrInvalidTaxResidency is returned for invalid residency values.
+var ErrInvalidTaxResidency = errors.New("invalid tax residency")
+
+// IsValid returns true if the residency is valid.
+func (r TaxResidency) IsValid() bool {
+	return r == TaxResident || r == TaxNonResident
+}
+
+// String returns the string representation.
+func (r TaxResidency) String() string {
+	return string(r)
+}
+
+// Label returns a human-readable label.
+func (r TaxResidency) Label() string {
+	switch r {
+	case TaxResident:
+		return "Resident"
+	case TaxNonResident:
+		return "Non-Resident"
+	default:
+		return "Unknown"
+	}
+}
+
+// ParseTaxResidency parses a string into a TaxResidency.
+func ParseTaxResidency(str string) (TaxResidency, error) {
+	r := TaxResidency(str)
+	if !r.IsValid() {
+		return "", fmt.Errorf("%w: %s", ErrInvalidTaxResidency, str)
+	}
+	return r, nil
+}
@@ -0,0 +1,156 @@
+package tax
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+)
+
+// Domain errors for tax profiles
+var (
+	ErrProfileNotFound = errors.New("tax profile not found")
+	ErrInvalidProfile  = errors.New("invalid tax profile")
+)
+
+// Profile is what decides the tax withheld from an agent's payouts: their
+// tax ID, if they have given one, and their residency. An admin can
+// override the rules with a rate for the agent, such as one agreed under
+// a tax treaty.
+type Profile struct {
+	agentID      uint
+	taxID        string
+	residency    shared.TaxResidency
+	rateOverride *float64
+	updatedBy    string
+	createdAt    time.Time
+	updatedAt    time.Time
+}
+
+// ProfileParams contains parameters for creating a Profile.
+type ProfileParams struct {
+	AgentID      uint
+	TaxID        string
+	Residency    string
+	RateOverride *float64
+	UpdatedBy    string
+
+	// Stored state, only read by ReconstituteProfile.
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewProfile creates a tax profile for an agent.
+func NewProfile(params ProfileParams) (*Profile, error) {
+	if params.AgentID == 0 {
+		return nil, fmt.Errorf("%w: agent ID is required", ErrInvalidProfile)
+	}
+	now := time.Now()
+	p := &Profile{
+		agentID:   params.AgentID,
+		createdAt: now,
+		updatedAt: now,
+	}
+	if err := p.Update(params.TaxID, params.Residency, params.RateOverride, params.UpdatedBy); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// DefaultProfile is the profile of an agent no profile has been recorded
+// for: a resident who has not given a tax ID.
+func DefaultProfile(agentID uint) *Profile {
+	now := time.Now()
+	return &Profile{
+		agentID:   agentID,
+		residency: shared.TaxResident,
+		createdAt: now,
+		updatedAt: now,
+	}
+}
+
+// ReconstituteProfile rebuilds a Profile from stored state.
+func ReconstituteProfile(params ProfileParams) *Profile {
+	return &Profile{
+		agentID:      params.AgentID,
+		taxID:        params.TaxID,
+		residency:    shared.TaxResidency(params.Residency),
+		rateOverride: params.RateOverride,
+		updatedBy:    params.UpdatedBy,
+		createdAt:    params.CreatedAt,
+		updatedAt:    params.UpdatedAt,
+	}
+}
+
+// Getters
+func (p *Profile) AgentID() uint                  { return p.agentID }
+func (p *Profile) TaxID() string                  { return p.taxID }
+func (p *Profile) Residency() shared.TaxResidency { return p.residency }
+func (p *Profile) RateOverride() *float64         { return p.rateOverride }
+func (p *Profile) UpdatedBy() string              { return p.updatedBy }
+func (p *Profile) CreatedAt() time.Time           { return p.createdAt }
+func (p *Profile) UpdatedAt() time.Time           { return p.updatedAt }
+
+// HasTaxID returns true if the agent has given a tax ID.
+func (p *Profile) HasTaxID() bool {
+	return p.taxID != ""
+}
+
+// Update changes the profile. Payouts already created keep the rate they
+// were created with.
+func (p *Profile) Update(taxID, residency string, rateOverride *float64, actor string) error {
+	res, err := shared.ParseTaxResidency(residency)
+	if err != nil {
+		return err
+	}
+	if rateOverride != nil && (*rateOverride < 0 || *rateOverride > 100) {
+		return fmt.Errorf("%w: rate override must be between 0 and 100", ErrInvalidProfile)
+	}
+
+	p.taxID = strings.TrimSpace(taxID)
+	p.residency = res
+	p.rateOverride = rateOverride
+	p.updatedBy = actor
+	p.updatedAt = time.Now()
+	return nil
+}
+
+// Withholding is the rate withheld from an agent's payouts, with where it
+// came from.
+type Withholding struct {
+	Rate   float64
+	RuleID *uint  // The rule that set the rate, if one did
+	Basis  string // Why this rate applies
+}
+
+// Withholding decides the rate withheld from the agent's payouts. An
+// override on the profile wins; otherwise the active rule that matches
+// the agent on the most conditions applies, the oldest on a tie. With no
+// matching rule nothing is withheld.
+func (p *Profile) Withholding(rules []*Rule) Withholding {
+	if p.rateOverride != nil {
+		return Withholding{Rate: *p.rateOverride, Basis: "agent rate override"}
+	}
+
+	var best *Rule
+	for _, r := range rules {
+		if !r.Matches(p) {
+			continue
+		}
+		if best == nil || r.specificity() > best.specificity() ||
+			(r.specificity() == best.specificity() && r.id < best.id) {
+			best = r
+		}
+	}
+	if best == nil {
+		return Withholding{Basis: "no withholding rule applies"}
+	}
+	id := best.id
+	return Withholding{
+		Rate:   best.rate,
+		RuleID: &id,
+		Basis:  fmt.Sprintf("%s (%s)", best.name, best.Describe()),
+	}
+}
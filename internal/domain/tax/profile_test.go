@@ -0,0 +1,71 @@
+package tax
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestProfileWithholding(t *testing.T) {
+	yes, no := true, false
+	rules := []*Rule{
+		ReconstituteRule(RuleParams{ID: 1, Name: "Standard", Rate: 2, Active: true}),
+		ReconstituteRule(RuleParams{ID: 2, Name: "Non-residents", Residency: "non_resident", Rate: 10, Active: true}),
+		ReconstituteRule(RuleParams{ID: 3, Name: "No tax ID", TaxIDPresent: &no, Rate: 20, Active: true}),
+		ReconstituteRule(RuleParams{ID: 4, Name: "Non-residents without tax ID", Residency: "non_resident", TaxIDPresent: &no, Rate: 25, Active: true}),
+		ReconstituteRule(RuleParams{ID: 5, Name: "Residents with tax ID", Residency: "resident", TaxIDPresent: &yes, Rate: 1, Active: false}),
+		ReconstituteRule(RuleParams{ID: 6, Name: "No tax ID, revised", TaxIDPresent: &no, Rate: 30, Active: true}),
+	}
+	ruleID := func(id uint) *uint { return &id }
+	rate := func(r float64) *float64 { return &r }
+
+	tests := []struct {
+		name     string
+		profile  ProfileParams
+		rules    []*Rule
+		wantRate float64
+		wantRule *uint
+	}{
+		{"resident with tax ID", ProfileParams{AgentID: 1, TaxID: "IG1234567890", Residency: "resident"}, rules, 2, ruleID(1)},
+		{"resident without tax ID, oldest rule on a tie", ProfileParams{AgentID: 1, Residency: "resident"}, rules, 20, ruleID(3)},
+		{"non-resident with tax ID", ProfileParams{AgentID: 1, TaxID: "IG1234567890", Residency: "non_resident"}, rules, 10, ruleID(2)},
+		{"non-resident without tax ID", ProfileParams{AgentID: 1, Residency: "non_resident"}, rules, 25, ruleID(4)},
+		{"override", ProfileParams{AgentID: 1, Residency: "non_resident", RateOverride: rate(15)}, rules, 15, nil},
+		{"zero override", ProfileParams{AgentID: 1, Residency: "non_resident", RateOverride: rate(0)}, rules, 0, nil},
+		{"no rules", ProfileParams{AgentID: 1, Residency: "resident"}, nil, 0, nil},
+		{"only an inactive rule matches", ProfileParams{AgentID: 1, TaxID: "IG1234567890", Residency: "resident"}, rules[4:5], 0, nil},
+		{"no rule matches", ProfileParams{AgentID: 1, TaxID: "IG1234567890", Residency: "resident"}, rules[1:4], 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The rules' order makes no difference
+			reversed := slices.Clone(tt.rules)
+			slices.Reverse(reversed)
+			for _, rules := range [][]*Rule{tt.rules, reversed} {
+				got := ReconstituteProfile(tt.profile).Withholding(rules)
+				if got.Rate != tt.wantRate {
+					t.Errorf("rate = %v, want %v", got.Rate, tt.wantRate)
+				}
+				if (got.RuleID == nil) != (tt.wantRule == nil) || (got.RuleID != nil && *got.RuleID != *tt.wantRule) {
+					t.Errorf("rule = %v, want %v (%s)", got.RuleID, tt.wantRule, got.Basis)
+				}
+				if got.Basis == "" {
+					t.Error("no basis given")
+				}
+			}
+		})
+	}
+}
+
+func TestDefaultProfileWithholding(t *testing.T) {
+	no := false
+	rules := []*Rule{
+		ReconstituteRule(RuleParams{ID: 1, Name: "Non-residents", Residency: "non_resident", Rate: 10, Active: true}),
+		ReconstituteRule(RuleParams{ID: 2, Name: "No tax ID", TaxIDPresent: &no, Rate: 20, Active: true}),
+	}
+
+	// An agent with no profile is treated as a resident without a tax ID
+	got := DefaultProfile(1).Withholding(rules)
+	if got.Rate != 20 || got.RuleID == nil || *got.RuleID != 2 {
+		t.Errorf("Withholding() = %+v, want rate 20 from rule 2", got)
+	}
+}
@@ -0,0 +1,163 @@
+package tax
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+)
+
+// Domain errors for withholding rules
+var (
+	ErrRuleNotFound = errors.New("withholding rule not found")
+	ErrInvalidRule  = errors.New("invalid withholding rule")
+)
+
+// Rule is a withholding rate for the agents it matches: by residency, by
+// whether they have given a tax ID, or both. A rule that leaves a
+// condition unset matches any agent on it.
+type Rule struct {
+	id           uint
+	name         string
+	residency    shared.TaxResidency // Empty matches any residency
+	taxIDPresent *bool               // Nil matches with or without a tax ID
+	rate         float64             // Percentage withheld
+	active       bool
+	createdAt    time.Time
+	updatedAt    time.Time
+}
+
+// RuleParams contains parameters for creating a Rule.
+type RuleParams struct {
+	ID           uint
+	Name         string
+	Residency    string
+	TaxIDPresent *bool
+	Rate         float64
+	Active       bool
+
+	// Stored state, only read by ReconstituteRule.
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewRule creates a new withholding rule.
+func NewRule(params RuleParams) (*Rule, error) {
+	now := time.Now()
+	r := &Rule{
+		id:        params.ID,
+		createdAt: now,
+		updatedAt: now,
+	}
+	if err := r.Update(params.Name, params.Residency, params.TaxIDPresent, params.Rate, params.Active); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// ReconstituteRule rebuilds a Rule from stored state.
+func ReconstituteRule(params RuleParams) *Rule {
+	return &Rule{
+		id:           params.ID,
+		name:         params.Name,
+		residency:    shared.TaxResidency(params.Residency),
+		taxIDPresent: params.TaxIDPresent,
+		rate:         params.Rate,
+		active:       params.Active,
+		createdAt:    params.CreatedAt,
+		updatedAt:    params.UpdatedAt,
+	}
+}
+
+// Getters
+func (r *Rule) ID() uint                       { return r.id }
+func (r *Rule) Name() string                   { return r.name }
+func (r *Rule) Residency() shared.TaxResidency { return r.residency }
+func (r *Rule) TaxIDPresent() *bool            { return r.taxIDPresent }
+func (r *Rule) Rate() float64                  { return r.rate }
+func (r *Rule) IsActive() bool                 { return r.active }
+func (r *Rule) CreatedAt() time.Time           { return r.createdAt }
+func (r *Rule) UpdatedAt() time.Time           { return r.updatedAt }
+
+// SetID records the ID assigned by the store on first save.
+func (r *Rule) SetID(id uint) {
+	r.id = id
+}
+
+// Update changes the rule. Payouts already created keep the rate they
+// were created with.
+func (r *Rule) Update(name, residency string, taxIDPresent *bool, rate float64, active bool) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidRule)
+	}
+	if rate < 0 || rate > 100 {
+		return fmt.Errorf("%w: rate must be between 0 and 100", ErrInvalidRule)
+	}
+	var res shared.TaxResidency
+	if residency != "" {
+		parsed, err := shared.ParseTaxResidency(residency)
+		if err != nil {
+			return err
+		}
+		res = parsed
+	}
+
+	r.name = name
+	r.residency = res
+	r.taxIDPresent = taxIDPresent
+	r.rate = rate
+	r.active = active
+	r.updatedAt = time.Now()
+	return nil
+}
+
+// Matches returns true if the rule is active and applies to an agent with
+// the given profile.
+func (r *Rule) Matches(p *Profile) bool {
+	if !r.active {
+		return false
+	}
+	if r.residency != "" && r.residency != p.Residency() {
+		return false
+	}
+	if r.taxIDPresent != nil && *r.taxIDPresent != p.HasTaxID() {
+		return false
+	}
+	return true
+}
+
+// specificity counts the conditions the rule sets: a rule that names more
+// of the agent's circumstances wins over a broader one.
+func (r *Rule) specificity() int {
+	n := 0
+	if r.residency != "" {
+		n++
+	}
+	if r.taxIDPresent != nil {
+		n++
+	}
+	return n
+}
+
+// Describe summarises who the rule applies to, e.g. "non-resident, no tax
+// ID".
+func (r *Rule) Describe() string {
+	var parts []string
+	if r.residency != "" {
+		parts = append(parts, strings.ToLower(r.residency.Label()))
+	}
+	if r.taxIDPresent != nil {
+		if *r.taxIDPresent {
+			parts = append(parts, "with tax ID")
+		} else {
+			parts = append(parts, "no tax ID")
+		}
+	}
+	if len(parts) == 0 {
+		return "all agents"
+	}
+	return strings.Join(parts, ", ")
+}
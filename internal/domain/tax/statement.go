@@ -0,0 +1,153 @@
+package tax
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/payout"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+)
+
+// ErrInvalidYear is returned for statement years that are not a four-digit
+// year.
+var ErrInvalidYear = errors.New("year must be a four-digit year")
+
+// ParseYear parses a statement year.
+func ParseYear(year string) (int, error) {
+	y, err := strconv.Atoi(year)
+	if err != nil || y < 2000 || y > 9999 {
+		return 0, ErrInvalidYear
+	}
+	return y, nil
+}
+
+// YearRange returns the start of a calendar year and of the next, in UTC.
+func YearRange(year int) (time.Time, time.Time) {
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(1, 0, 0)
+}
+
+// Amounts are the totals of a set of payouts in one currency: the
+// taxable commission income, the tax withheld from it and the net paid.
+type Amounts struct {
+	Currency string
+	Gross    shared.Money
+	Withheld shared.Money
+	Net      shared.Money
+	Payouts  int
+}
+
+// newAmounts returns zero amounts in a currency
+func newAmounts(currency string) Amounts {
+	zero := shared.ZeroMoney(currency)
+	return Amounts{Currency: currency, Gross: zero, Withheld: zero, Net: zero}
+}
+
+// add adds a payout to the totals
+func (a *Amounts) add(p *payout.Payout) {
+	a.Gross = a.Gross.Add(p.TaxableAmount())
+	a.Withheld = a.Withheld.Add(p.Withheld())
+	a.Net = a.Net.Add(p.Amount())
+	a.Payouts++
+}
+
+// StatementMonth is a month's payouts in one currency.
+type StatementMonth struct {
+	Month string // Format: YYYY-MM
+	Amounts
+}
+
+// StatementPayout is one payout on a statement.
+type StatementPayout struct {
+	PayoutID        uint
+	Reference       string
+	Period          string
+	PaidAt          time.Time
+	Gross           shared.Money
+	WithholdingRate float64
+	Withheld        shared.Money
+	Net             shared.Money
+}
+
+// Statement is an agent's annual statement of commission income: the
+// payouts paid to them in a calendar year, with the tax withheld from
+// each. Payouts count in the year they were paid, not the period they
+// were for. Income is shown after clawbacks netted against the payouts.
+type Statement struct {
+	AgentID   uint
+	Year      int
+	TaxID     string
+	Residency shared.TaxResidency
+	Totals    []Amounts // By currency
+	Months    []StatementMonth
+	Payouts   []StatementPayout
+}
+
+// NewStatement builds an agent's statement for a year from their payouts.
+// Only completed payouts paid in the year are included.
+func NewStatement(year int, profile *Profile, payouts []*payout.Payout) *Statement {
+	start, end := YearRange(year)
+	var paid []*payout.Payout
+	for _, p := range payouts {
+		if p.AgentID() != profile.AgentID() || !p.IsCompleted() || p.PaidAt() == nil {
+			continue
+		}
+		if paidAt := p.PaidAt().UTC(); !paidAt.Before(start) && paidAt.Before(end) {
+			paid = append(paid, p)
+		}
+	}
+	sort.SliceStable(paid, func(i, j int) bool {
+		if !paid[i].PaidAt().Equal(*paid[j].PaidAt()) {
+			return paid[i].PaidAt().Before(*paid[j].PaidAt())
+		}
+		return paid[i].ID() < paid[j].ID()
+	})
+
+	s := &Statement{
+		AgentID:   profile.AgentID(),
+		Year:      year,
+		TaxID:     profile.TaxID(),
+		Residency: profile.Residency(),
+	}
+	totals := make(map[string]int)
+	months := make(map[[2]string]int)
+	for _, p := range paid {
+		currency := p.Currency()
+		i, ok := totals[currency]
+		if !ok {
+			i = len(s.Totals)
+			totals[currency] = i
+			s.Totals = append(s.Totals, newAmounts(currency))
+		}
+		s.Totals[i].add(p)
+
+		key := [2]string{p.PaidAt().UTC().Format("2006-01"), currency}
+		j, ok := months[key]
+		if !ok {
+			j = len(s.Months)
+			months[key] = j
+			s.Months = append(s.Months, StatementMonth{Month: key[0], Amounts: newAmounts(currency)})
+		}
+		s.Months[j].add(p)
+
+		s.Payouts = append(s.Payouts, StatementPayout{
+			PayoutID:        p.ID(),
+			Reference:       p.Reference(),
+			Period:          p.Period(),
+			PaidAt:          *p.PaidAt(),
+			Gross:           p.TaxableAmount(),
+			WithholdingRate: p.WithholdingRate(),
+			Withheld:        p.Withheld(),
+			Net:             p.Amount(),
+		})
+	}
+	sort.Slice(s.Totals, func(i, j int) bool { return s.Totals[i].Currency < s.Totals[j].Currency })
+	return s
+}
+
+// IsEmpty returns true if the agent was paid nothing in the year.
+func (s *Statement) IsEmpty() bool {
+	return len(s.Payouts) == 0
+}
@@ -35,6 +35,10 @@ type TeamParams struct {
 	TargetMonthly  float64
 	CommissionRate float64
 	IsActive       bool
+
+	// Stored state, only read by Reconstitute.
+	CreatedAt time.Time
+	UpdatedAt time.Time
 }
 
 // NewTeam creates a new Team entity.
@@ -66,6 +70,22 @@ func NewTeam(params TeamParams) (*Team, error) {
 	}, nil
 }
 
+// Reconstitute rebuilds a Team from stored state without validation.
+func Reconstitute(params TeamParams) *Team {
+	return &Team{
+		id:             params.ID,
+		code:           params.Code,
+		name:           params.Name,
+		description:    params.Description,
+		leaderID:       params.LeaderID,
+		targetMonthly:  params.TargetMonthly,
+		commissionRate: params.CommissionRate,
+		isActive:       params.IsActive,
+		createdAt:      params.CreatedAt,
+		updatedAt:      params.UpdatedAt,
+	}
+}
+
 // Getters
 func (t *Team) ID() uint                { return t.id }
 func (t *Team) Code() string            { return t.code }
@@ -80,6 +100,11 @@ func (t *Team) UpdatedAt() time.Time    { return t.updatedAt }
 
 // --- Behavior Methods ---
 
+// SetID records the ID assigned by the store on first save.
+func (t *Team) SetID(id uint) {
+	t.id = id
+}
+
 // Update updates the team details.
 func (t *Team) Update(name, description string) {
 	if name != "" {
@@ -3,22 +3,67 @@ package handlers
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"os"
 	"strconv"
+	"time"
 
+	services "github.com/Ecom-micro-template/service-agent/internal/application"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/agent"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/commission"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
 	"github.com/gin-gonic/gin"
-	"github.com/Ecom-micro-template/service-agent/internal/database"
-	"github.com/Ecom-micro-template/service-agent/internal/domain"
 	"github.com/rs/zerolog/log"
 )
 
+// AgentHandler handles admin agent operations
+type AgentHandler struct {
+	agents      repository.AgentRepository
+	commissions repository.CommissionReader
+	payouts     repository.PayoutReader
+	users       repository.UserDirectory
+	hierarchy   *services.HierarchyService
+	rates       *services.RateService
+}
+
+// NewAgentHandler creates a new agent handler
+func NewAgentHandler(
+	agents repository.AgentRepository,
+	commissions repository.CommissionReader,
+	payouts repository.PayoutReader,
+	users repository.UserDirectory,
+	hierarchy *services.HierarchyService,
+	rates *services.RateService,
+) *AgentHandler {
+	return &AgentHandler{
+		agents:      agents,
+		commissions: commissions,
+		payouts:     payouts,
+		users:       users,
+		hierarchy:   hierarchy,
+		rates:       rates,
+	}
+}
+
+// parseIDParam parses a numeric route parameter
+func parseIDParam(c *gin.Context, name string) (uint, error) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	return uint(id), nil
+}
+
 type CreateAgentRequest struct {
 	Name           string  `json:"name" binding:"required"`
 	Email          string  `json:"email" binding:"required,email"`
 	Password       string  `json:"password" binding:"required,min=8"`
 	Phone          string  `json:"phone"`
 	CommissionRate float64 `json:"commission_rate"`
+	ParentID       *uint   `json:"parent_id"` // Upline sponsor
+	PayoutCurrency string  `json:"payout_currency"`
 }
 
 type UpdateAgentRequest struct {
@@ -27,6 +72,7 @@ type UpdateAgentRequest struct {
 	Phone          string  `json:"phone"`
 	CommissionRate float64 `json:"commission_rate"`
 	Status         string  `json:"status"`
+	PayoutCurrency string  `json:"payout_currency"`
 }
 
 // AuthRegisterRequest is the request to register user with auth service
@@ -39,13 +85,29 @@ type AuthRegisterRequest struct {
 }
 
 // CreateAgent creates a new agent and registers them with auth service
-func CreateAgent(c *gin.Context) {
+func (h *AgentHandler) CreateAgent(c *gin.Context) {
 	var req CreateAgentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	// Check the request before registering anything
+	if _, err := shared.ParseCurrency(req.PayoutCurrency); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.ParentID != nil {
+		if err := h.hierarchy.CheckSponsor(c.Request.Context(), *req.ParentID); err != nil {
+			if errors.Is(err, agent.ErrAgentNotFound) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Sponsor not found"})
+				return
+			}
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
 	// First, register the agent as a user in auth service
 	authURL := os.Getenv("AUTH_SERVICE_URL")
 	if authURL == "" {
@@ -90,64 +152,66 @@ func CreateAgent(c *gin.Context) {
 
 	// Update user role to "agent" in auth.users table
 	// This is needed because the public register endpoint sets role to "customer" for security
-	result := database.GetDB().Exec("UPDATE auth.users SET role = 'agent' WHERE email = ?", req.Email)
-	if result.Error != nil {
-		log.Error().Err(result.Error).Str("email", req.Email).Msg("Failed to update user role to agent")
+	if err := h.users.SetRole(c.Request.Context(), req.Email, "agent"); err != nil {
+		log.Error().Err(err).Str("email", req.Email).Msg("Failed to update user role to agent")
 		// Don't fail - the user was created, we can manually fix the role
-	} else if result.RowsAffected > 0 {
+	} else {
 		log.Info().Str("email", req.Email).Msg("User role updated to agent")
 	}
 
 	// Now create the agent record
-	agent := domain.Agent{
+	newAgent, err := agent.NewAgent(agent.AgentParams{
 		Name:           req.Name,
 		Email:          req.Email,
 		Phone:          req.Phone,
 		CommissionRate: req.CommissionRate,
+		ParentID:       req.ParentID,
+		PayoutCurrency: req.PayoutCurrency,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	if err := database.GetDB().Create(&agent).Error; err != nil {
+	if err := h.agents.Create(c.Request.Context(), newAgent); err != nil {
 		log.Error().Err(err).Msg("Failed to create agent")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create agent"})
 		return
 	}
 
-	log.Info().Uint("agent_id", agent.ID).Str("code", agent.Code).Msg("Agent created with auth credentials")
-	c.JSON(http.StatusCreated, agent)
+	log.Info().Uint("agent_id", newAgent.ID()).Str("code", newAgent.Code()).Msg("Agent created with auth credentials")
+	c.JSON(http.StatusCreated, NewAgentResponse(newAgent))
 }
 
 // GetAgents lists all agents with pagination
 // By default, excludes inactive/deleted agents unless ?include_inactive=true
-func GetAgents(c *gin.Context) {
+func (h *AgentHandler) GetAgents(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	status := c.Query("status")
-	includeInactive := c.Query("include_inactive") == "true"
 
-	offset := (page - 1) * limit
-
-	var agents []domain.Agent
-	query := database.GetDB().Model(&domain.Agent{})
-
-	if status != "" {
-		// If specific status is requested, use it
-		query = query.Where("status = ?", status)
-	} else if !includeInactive {
-		// By default, exclude inactive agents (deleted agents)
-		query = query.Where("status != ?", "inactive")
+	var parentID *uint
+	if p, err := strconv.ParseUint(c.Query("parent_id"), 10, 32); err == nil {
+		id := uint(p)
+		parentID = &id
 	}
 
-	var total int64
-	query.Count(&total)
-
-	if err := query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&agents).Error; err != nil {
+	agents, total, err := h.agents.List(c.Request.Context(), repository.AgentFilter{
+		Status:          c.Query("status"),
+		Tier:            c.Query("tier"),
+		ParentID:        parentID,
+		Search:          c.Query("search"),
+		Page:            page,
+		Limit:           limit,
+		IncludeInactive: c.Query("include_inactive") == "true",
+	})
+	if err != nil {
 		log.Error().Err(err).Msg("Failed to fetch agents")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch agents"})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"data":        agents,
+		"data":        NewAgentResponses(agents),
 		"total":       total,
 		"page":        page,
 		"limit":       limit,
@@ -156,24 +220,50 @@ func GetAgents(c *gin.Context) {
 }
 
 // GetAgent retrieves a single agent by ID
-func GetAgent(c *gin.Context) {
-	id := c.Param("id")
+func (h *AgentHandler) GetAgent(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid agent ID"})
+		return
+	}
 
-	var agent domain.Agent
-	if err := database.GetDB().Preload("Commissions").Preload("Payouts").First(&agent, id).Error; err != nil {
+	ctx := c.Request.Context()
+	a, err := h.agents.GetByID(ctx, id)
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Agent not found"})
 		return
 	}
 
-	c.JSON(http.StatusOK, agent)
+	commissions, _, err := h.commissions.GetByAgentID(ctx, id, repository.CommissionFilter{})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to fetch agent commissions")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch agent"})
+		return
+	}
+
+	payouts, _, err := h.payouts.GetByAgentID(ctx, id, 0, 0)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to fetch agent payouts")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch agent"})
+		return
+	}
+
+	response := NewAgentResponse(a)
+	response.Commissions = NewCommissionResponses(commissions)
+	response.Payouts = NewPayoutResponses(payouts)
+	c.JSON(http.StatusOK, response)
 }
 
 // UpdateAgent updates an existing agent
-func UpdateAgent(c *gin.Context) {
-	id := c.Param("id")
+func (h *AgentHandler) UpdateAgent(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid agent ID"})
+		return
+	}
 
-	var agent domain.Agent
-	if err := database.GetDB().First(&agent, id).Error; err != nil {
+	a, err := h.agents.GetByID(c.Request.Context(), id)
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Agent not found"})
 		return
 	}
@@ -184,31 +274,48 @@ func UpdateAgent(c *gin.Context) {
 		return
 	}
 
-	// Update fields if provided
-	if req.Name != "" {
-		agent.Name = req.Name
-	}
-	if req.Email != "" {
-		agent.Email = req.Email
+	// A new rate is recorded as a rate change effective now, so earlier
+	// orders keep the rate they were placed under
+	if req.CommissionRate > 0 && req.CommissionRate != a.CommissionRate().Value() {
+		if _, err := h.rates.Schedule(c.Request.Context(), commission.RateScopeAgent, a.ID(), req.CommissionRate, time.Time{}); err != nil {
+			respondRateError(c, err, "Failed to update agent")
+			return
+		}
+		if a, err = h.agents.GetByID(c.Request.Context(), id); err != nil {
+			log.Error().Err(err).Msg("Failed to reload agent")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update agent"})
+			return
+		}
 	}
+
+	// Update fields if provided
+	phone := a.Phone()
 	if req.Phone != "" {
-		agent.Phone = req.Phone
-	}
-	if req.CommissionRate > 0 {
-		agent.CommissionRate = req.CommissionRate
+		phone = req.Phone
 	}
+	_ = a.UpdateProfile(req.Name, req.Email, phone)
+
 	if req.Status != "" {
-		agent.Status = req.Status
+		if err := a.SetStatus(req.Status); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if req.PayoutCurrency != "" {
+		if err := a.SetPayoutCurrency(req.PayoutCurrency); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 	}
 
-	if err := database.GetDB().Save(&agent).Error; err != nil {
+	if err := h.agents.Update(c.Request.Context(), a); err != nil {
 		log.Error().Err(err).Msg("Failed to update agent")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update agent"})
 		return
 	}
 
-	log.Info().Uint("agent_id", agent.ID).Msg("Agent updated")
-	c.JSON(http.StatusOK, agent)
+	log.Info().Uint("agent_id", a.ID()).Msg("Agent updated")
+	c.JSON(http.StatusOK, NewAgentResponse(a))
 }
 
 // AuthUpdateStatusRequest is the request to update user status in auth service
@@ -218,18 +325,21 @@ type AuthUpdateStatusRequest struct {
 }
 
 // DeleteAgent soft deletes an agent and deactivates their auth account
-func DeleteAgent(c *gin.Context) {
-	id := c.Param("id")
+func (h *AgentHandler) DeleteAgent(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid agent ID"})
+		return
+	}
 
-	var agent domain.Agent
-	if err := database.GetDB().First(&agent, id).Error; err != nil {
+	a, err := h.agents.GetByID(c.Request.Context(), id)
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Agent not found"})
 		return
 	}
 
 	// Update agent status to inactive
-	agent.Status = "inactive"
-	if err := database.GetDB().Save(&agent).Error; err != nil {
+	if err := h.agents.Delete(c.Request.Context(), a.ID()); err != nil {
 		log.Error().Err(err).Msg("Failed to delete agent")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete agent"})
 		return
@@ -242,7 +352,7 @@ func DeleteAgent(c *gin.Context) {
 	}
 
 	authReq := AuthUpdateStatusRequest{
-		Email:  agent.Email,
+		Email:  a.Email(),
 		Status: "inactive",
 	}
 
@@ -260,11 +370,11 @@ func DeleteAgent(c *gin.Context) {
 		if authResp.StatusCode != http.StatusOK {
 			log.Warn().Int("status", authResp.StatusCode).Msg("Auth service returned non-OK status for deactivation")
 		} else {
-			log.Info().Str("email", agent.Email).Msg("Agent deactivated in auth service")
+			log.Info().Str("email", a.Email()).Msg("Agent deactivated in auth service")
 		}
 	}
 
-	log.Info().Uint("agent_id", agent.ID).Msg("Agent deleted")
+	log.Info().Uint("agent_id", a.ID()).Msg("Agent deleted")
 	c.JSON(http.StatusOK, gin.H{"message": "Agent deleted successfully"})
 }
 
@@ -280,12 +390,16 @@ type AuthResetPasswordRequest struct {
 }
 
 // ResetAgentPassword resets an agent's password
-func ResetAgentPassword(c *gin.Context) {
-	id := c.Param("id")
+func (h *AgentHandler) ResetAgentPassword(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid agent ID"})
+		return
+	}
 
 	// First get the agent to find their email
-	var agent domain.Agent
-	if err := database.GetDB().First(&agent, id).Error; err != nil {
+	a, err := h.agents.GetByID(c.Request.Context(), id)
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Agent not found"})
 		return
 	}
@@ -304,7 +418,7 @@ func ResetAgentPassword(c *gin.Context) {
 
 	// Use admin password reset endpoint
 	authReq := AuthResetPasswordRequest{
-		Email:    agent.Email,
+		Email:    a.Email(),
 		Password: req.Password,
 	}
 
@@ -334,6 +448,44 @@ func ResetAgentPassword(c *gin.Context) {
 		return
 	}
 
-	log.Info().Uint("agent_id", agent.ID).Str("email", agent.Email).Msg("Agent password reset successfully")
+	log.Info().Uint("agent_id", a.ID()).Str("email", a.Email()).Msg("Agent password reset successfully")
 	c.JSON(http.StatusOK, gin.H{"message": "Password reset successfully"})
 }
+
+// GetAgentStats retrieves statistics for an agent
+func (h *AgentHandler) GetAgentStats(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid agent ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	a, err := h.agents.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, agent.ErrAgentNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Agent not found"})
+			return
+		}
+		log.Error().Err(err).Msg("Failed to fetch agent")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch agent"})
+		return
+	}
+
+	stats, err := h.agents.GetStats(ctx, id)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to fetch agent stats")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch agent stats"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"agent":                   NewAgentResponse(a),
+		"total_commissions":       stats.TotalCommissions,
+		"total_commission_amount": stats.TotalCommission,
+		"pending_commissions":     stats.PendingCommissions,
+		"pending_amount":          stats.PendingCommission,
+		"this_month_amount":       stats.ThisMonthCommission,
+		"total_payouts":           stats.TotalPayouts,
+	})
+}
@@ -1,17 +1,53 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
 	"time"
 
-	"github.com/gin-gonic/gin"
-	"github.com/Ecom-micro-template/service-agent/internal/database"
+	services "github.com/Ecom-micro-template/service-agent/internal/application"
 	"github.com/Ecom-micro-template/service-agent/internal/domain"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/team"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
 )
 
+// AgentPortalHandler handles the authenticated agent's own portal endpoints
+type AgentPortalHandler struct {
+	agents      repository.AgentRepository
+	commissions repository.CommissionReader
+	teams       repository.TeamReader
+	customers   repository.CustomerRepository
+	orders      repository.OrderReader
+	users       repository.UserDirectory
+	holds       *services.PayoutHoldService
+}
+
+// NewAgentPortalHandler creates a new agent portal handler
+func NewAgentPortalHandler(
+	agents repository.AgentRepository,
+	commissions repository.CommissionReader,
+	teams repository.TeamReader,
+	customers repository.CustomerRepository,
+	orders repository.OrderReader,
+	users repository.UserDirectory,
+	holds *services.PayoutHoldService,
+) *AgentPortalHandler {
+	return &AgentPortalHandler{
+		agents:      agents,
+		commissions: commissions,
+		teams:       teams,
+		customers:   customers,
+		orders:      orders,
+		users:       users,
+		holds:       holds,
+	}
+}
+
 // GetAgentFromContext retrieves the agent ID from the JWT context
 func GetAgentFromContext(c *gin.Context) (uint, error) {
 	agentID, exists := c.Get("agent_id")
@@ -27,21 +63,56 @@ func GetAgentFromContext(c *gin.Context) (uint, error) {
 	return id, nil
 }
 
+// agentUserID looks up the auth user UUID of the authenticated agent.
+// Orders reference agents by auth user UUID, not the agent uint ID.
+func (h *AgentPortalHandler) agentUserID(c *gin.Context) (string, error) {
+	agentEmail, exists := c.Get("agent_email")
+	if !exists {
+		return "", fmt.Errorf("agent_email not found in context")
+	}
+	email, _ := agentEmail.(string)
+	return h.users.GetUserIDByEmail(c.Request.Context(), email)
+}
+
+// payoutCurrency returns the currency the agent is paid in, which their
+// commission totals are shown in
+func (h *AgentPortalHandler) payoutCurrency(c *gin.Context, agentID uint) string {
+	if a, err := h.agents.GetByID(c.Request.Context(), agentID); err == nil {
+		return a.PayoutCurrency()
+	}
+	return shared.DefaultCurrency
+}
+
 // GetAgentProfile retrieves the authenticated agent's profile
-func GetAgentProfile(c *gin.Context) {
+func (h *AgentPortalHandler) GetAgentProfile(c *gin.Context) {
 	agentID, err := GetAgentFromContext(c)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 		return
 	}
 
-	var agent domain.Agent
-	if err := database.GetDB().Preload("Team").Preload("Team.Leader").First(&agent, agentID).Error; err != nil {
+	ctx := c.Request.Context()
+	a, err := h.agents.GetByID(ctx, agentID)
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Agent not found"})
 		return
 	}
 
-	c.JSON(http.StatusOK, agent)
+	response := NewAgentResponse(a)
+	if a.TeamID() != nil {
+		if t, err := h.teams.GetByID(ctx, *a.TeamID()); err == nil {
+			teamResponse := NewTeamResponse(t)
+			if t.LeaderID() != nil {
+				if leader, err := h.agents.GetByID(ctx, *t.LeaderID()); err == nil {
+					leaderResponse := NewAgentResponse(leader)
+					teamResponse.Leader = &leaderResponse
+				}
+			}
+			response.Team = &teamResponse
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
 // UpdateAgentProfileRequest represents the request body for updating agent profile
@@ -51,7 +122,7 @@ type UpdateAgentProfileRequest struct {
 }
 
 // UpdateAgentProfile updates the authenticated agent's profile
-func UpdateAgentProfile(c *gin.Context) {
+func (h *AgentPortalHandler) UpdateAgentProfile(c *gin.Context) {
 	agentID, err := GetAgentFromContext(c)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
@@ -64,96 +135,75 @@ func UpdateAgentProfile(c *gin.Context) {
 		return
 	}
 
-	var agent domain.Agent
-	if err := database.GetDB().First(&agent, agentID).Error; err != nil {
+	a, err := h.agents.GetByID(c.Request.Context(), agentID)
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Agent not found"})
 		return
 	}
 
 	// Update fields if provided
-	if req.Name != "" {
-		agent.Name = req.Name
-	}
+	phone := a.Phone()
 	if req.Phone != "" {
-		agent.Phone = req.Phone
+		phone = req.Phone
 	}
+	_ = a.UpdateProfile(req.Name, "", phone)
 
-	if err := database.GetDB().Save(&agent).Error; err != nil {
+	if err := h.agents.Update(c.Request.Context(), a); err != nil {
 		log.Error().Err(err).Msg("Failed to update agent profile")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update profile"})
 		return
 	}
 
 	log.Info().Uint("agent_id", agentID).Msg("Agent profile updated")
-	c.JSON(http.StatusOK, agent)
+	c.JSON(http.StatusOK, NewAgentResponse(a))
 }
 
 // GetAgentDashboard retrieves dashboard statistics for the agent
-func GetAgentDashboard(c *gin.Context) {
+func (h *AgentPortalHandler) GetAgentDashboard(c *gin.Context) {
 	agentID, err := GetAgentFromContext(c)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 		return
 	}
 
-	// Get agent email from context to look up auth user ID for orders
-	agentEmail, _ := c.Get("agent_email")
-
-	db := database.GetDB()
-	dashboard := domain.Dashboard{}
-
-	// Get auth user ID for order queries (orders use auth UUID, not agent uint)
-	var authUserID string
-	if agentEmail != nil {
-		db.Table("auth.users").Where("email = ?", agentEmail).Select("id").Scan(&authUserID)
-	}
+	ctx := c.Request.Context()
+	dashboard := domain.Dashboard{Currency: h.payoutCurrency(c, agentID)}
 
 	// Get current month start
 	now := time.Now()
 	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	thisMonth := repository.Period{From: monthStart}
 
 	// Total orders and sales (use auth user UUID)
-	if authUserID != "" {
-		db.Model(&domain.Order{}).Where("agent_id = ?", authUserID).Count(&dashboard.TotalOrders)
-		db.Model(&domain.Order{}).Where("agent_id = ?", authUserID).Select("COALESCE(SUM(total), 0)").Scan(&dashboard.TotalSales)
+	if authUserID, err := h.agentUserID(c); err == nil {
+		if orders, err := h.orders.GetSummary(ctx, authUserID, repository.Period{}); err == nil {
+			dashboard.TotalOrders = orders.Count
+			dashboard.TotalSales = orders.Total
+		}
 
 		// Monthly stats
-		db.Model(&domain.Order{}).
-			Where("agent_id = ? AND created_at >= ?", authUserID, monthStart).
-			Count(&dashboard.MonthlyOrders)
-		db.Model(&domain.Order{}).
-			Where("agent_id = ? AND created_at >= ?", authUserID, monthStart).
-			Select("COALESCE(SUM(total), 0)").Scan(&dashboard.MonthlySales)
+		if orders, err := h.orders.GetSummary(ctx, authUserID, thisMonth); err == nil {
+			dashboard.MonthlyOrders = orders.Count
+			dashboard.MonthlySales = orders.Total
+		}
 	}
 
 	// Total customers (use agent uint ID)
-	db.Model(&domain.Customer{}).Where("agent_id = ?", agentID).Count(&dashboard.TotalCustomers)
+	dashboard.TotalCustomers, _ = h.customers.CountByAgentID(ctx, agentID)
 
 	// Commission stats (use agent uint ID)
-	db.Model(&domain.Commission{}).
-		Where("agent_id = ?", agentID).
-		Select("COALESCE(SUM(amount), 0)").
-		Scan(&dashboard.TotalCommission)
-
-	db.Model(&domain.Commission{}).
-		Where("agent_id = ? AND status = ?", agentID, "pending").
-		Select("COALESCE(SUM(amount), 0)").
-		Scan(&dashboard.PendingCommission)
-
-	db.Model(&domain.Commission{}).
-		Where("agent_id = ? AND status = ?", agentID, "approved").
-		Select("COALESCE(SUM(amount), 0)").
-		Scan(&dashboard.ApprovedCommission)
-
-	db.Model(&domain.Commission{}).
-		Where("agent_id = ? AND status = ?", agentID, "paid").
-		Select("COALESCE(SUM(amount), 0)").
-		Scan(&dashboard.PaidCommission)
-
-	db.Model(&domain.Commission{}).
-		Where("agent_id = ? AND created_at >= ?", agentID, monthStart).
-		Select("COALESCE(SUM(amount), 0)").
-		Scan(&dashboard.MonthlyCommission)
+	if summary, err := h.commissions.GetSummary(ctx, agentID, repository.Period{}); err == nil {
+		totals := summary.In(dashboard.Currency)
+		dashboard.TotalCommission = totals.Total
+		dashboard.PendingCommission = totals.Pending
+		dashboard.ApprovedCommission = totals.Approved
+		dashboard.PaidCommission = totals.Paid
+		dashboard.CommissionByCurrency = summary.Totals
+	}
+
+	if summary, err := h.commissions.GetSummary(ctx, agentID, thisMonth); err == nil {
+		dashboard.MonthlyCommission = summary.In(dashboard.Currency).Total
+	}
 
 	// Average order value
 	if dashboard.TotalOrders > 0 {
@@ -167,11 +217,21 @@ func GetAgentDashboard(c *gin.Context) {
 		Paid:     dashboard.PaidCommission,
 	}
 
+	// Held and reserved balances
+	if balances, err := h.holds.Balances(ctx, agentID); err == nil {
+		if hold := balances.Hold; hold != nil {
+			dashboard.HeldBalance = dashboard.ApprovedCommission
+			dashboard.PayoutHold = &domain.PayoutHold{Reason: hold.Reason(), ExpiresAt: hold.ExpiresAt()}
+		}
+		dashboard.ReservedBalance = balances.Reserved.Float64()
+		dashboard.NextReserveRelease = balances.NextRelease
+	}
+
 	c.JSON(http.StatusOK, dashboard)
 }
 
 // GetAgentOrders retrieves all orders for the agent
-func GetAgentOrders(c *gin.Context) {
+func (h *AgentPortalHandler) GetAgentOrders(c *gin.Context) {
 	// Get agent email from context (set by auth middleware)
 	agentEmail, exists := c.Get("agent_email")
 	if !exists {
@@ -180,12 +240,9 @@ func GetAgentOrders(c *gin.Context) {
 	}
 
 	// Get auth user ID for this agent (orders use auth user UUID, not agent integer ID)
-	var authUserID string
-	if err := database.GetDB().Table("auth.users").
-		Where("email = ?", agentEmail).
-		Select("id").
-		Scan(&authUserID).Error; err != nil || authUserID == "" {
-		log.Error().Str("email", agentEmail.(string)).Msg("Auth user not found for agent")
+	authUserID, err := h.agentUserID(c)
+	if err != nil {
+		log.Error().Interface("email", agentEmail).Msg("Auth user not found for agent")
 		// Return empty result instead of error (agent might not have any orders yet)
 		c.JSON(http.StatusOK, gin.H{
 			"data":        []domain.Order{},
@@ -199,21 +256,9 @@ func GetAgentOrders(c *gin.Context) {
 
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	status := c.Query("status")
-
-	offset := (page - 1) * limit
-
-	var orders []domain.Order
-	query := database.GetDB().Model(&domain.Order{}).Where("agent_id = ?", authUserID)
-
-	if status != "" {
-		query = query.Where("status = ?", status)
-	}
 
-	var total int64
-	query.Count(&total)
-
-	if err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&orders).Error; err != nil {
+	orders, total, err := h.orders.List(c.Request.Context(), authUserID, c.Query("status"), page, limit)
+	if err != nil {
 		log.Error().Err(err).Msg("Failed to fetch orders")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch orders"})
 		return
@@ -231,7 +276,7 @@ func GetAgentOrders(c *gin.Context) {
 // CreateAgentOrder creates a new order for the agent
 // NOTE: Orders should be created through the storefront (service-order) with agent referral code.
 // This endpoint is deprecated - agents cannot create orders directly.
-func CreateAgentOrder(c *gin.Context) {
+func (h *AgentPortalHandler) CreateAgentOrder(c *gin.Context) {
 	c.JSON(http.StatusNotImplemented, gin.H{
 		"error":   "Direct order creation is not supported",
 		"message": "Orders should be created through the storefront using your agent referral link",
@@ -239,28 +284,16 @@ func CreateAgentOrder(c *gin.Context) {
 }
 
 // GetAgentOrder retrieves a single order
-func GetAgentOrder(c *gin.Context) {
-	// Get agent email from context (set by auth middleware)
-	agentEmail, exists := c.Get("agent_email")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-		return
-	}
-
+func (h *AgentPortalHandler) GetAgentOrder(c *gin.Context) {
 	// Get auth user ID for this agent (orders use auth user UUID)
-	var authUserID string
-	if err := database.GetDB().Table("auth.users").
-		Where("email = ?", agentEmail).
-		Select("id").
-		Scan(&authUserID).Error; err != nil || authUserID == "" {
+	authUserID, err := h.agentUserID(c)
+	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Agent not found"})
 		return
 	}
 
-	orderID := c.Param("id")
-
-	var order domain.Order
-	if err := database.GetDB().Where("agent_id = ? AND id = ?", authUserID, orderID).First(&order).Error; err != nil {
+	order, err := h.orders.GetByID(c.Request.Context(), authUserID, c.Param("id"))
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
 		return
 	}
@@ -269,7 +302,7 @@ func GetAgentOrder(c *gin.Context) {
 }
 
 // GetAgentCustomers retrieves all customers for the agent
-func GetAgentCustomers(c *gin.Context) {
+func (h *AgentPortalHandler) GetAgentCustomers(c *gin.Context) {
 	agentID, err := GetAgentFromContext(c)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
@@ -278,21 +311,9 @@ func GetAgentCustomers(c *gin.Context) {
 
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	search := c.Query("search")
-
-	offset := (page - 1) * limit
-
-	var customers []domain.Customer
-	query := database.GetDB().Model(&domain.Customer{}).Where("agent_id = ?", agentID)
-
-	if search != "" {
-		query = query.Where("name ILIKE ? OR email ILIKE ?", "%"+search+"%", "%"+search+"%")
-	}
 
-	var total int64
-	query.Count(&total)
-
-	if err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&customers).Error; err != nil {
+	customers, total, err := h.customers.List(c.Request.Context(), agentID, c.Query("search"), page, limit)
+	if err != nil {
 		log.Error().Err(err).Msg("Failed to fetch customers")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch customers"})
 		return
@@ -308,7 +329,7 @@ func GetAgentCustomers(c *gin.Context) {
 }
 
 // CreateAgentCustomer creates a new customer for the agent
-func CreateAgentCustomer(c *gin.Context) {
+func (h *AgentPortalHandler) CreateAgentCustomer(c *gin.Context) {
 	agentID, err := GetAgentFromContext(c)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
@@ -332,7 +353,7 @@ func CreateAgentCustomer(c *gin.Context) {
 		Postcode: req.Postcode,
 	}
 
-	if err := database.GetDB().Create(&customer).Error; err != nil {
+	if err := h.customers.Create(c.Request.Context(), &customer); err != nil {
 		log.Error().Err(err).Msg("Failed to create customer")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create customer"})
 		return
@@ -343,17 +364,21 @@ func CreateAgentCustomer(c *gin.Context) {
 }
 
 // GetAgentCustomer retrieves a single customer
-func GetAgentCustomer(c *gin.Context) {
+func (h *AgentPortalHandler) GetAgentCustomer(c *gin.Context) {
 	agentID, err := GetAgentFromContext(c)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 		return
 	}
 
-	customerID := c.Param("id")
+	customerID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Customer not found"})
+		return
+	}
 
-	var customer domain.Customer
-	if err := database.GetDB().Where("agent_id = ?", agentID).First(&customer, customerID).Error; err != nil {
+	customer, err := h.customers.GetByID(c.Request.Context(), agentID, customerID)
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Customer not found"})
 		return
 	}
@@ -362,17 +387,21 @@ func GetAgentCustomer(c *gin.Context) {
 }
 
 // UpdateAgentCustomer updates a customer
-func UpdateAgentCustomer(c *gin.Context) {
+func (h *AgentPortalHandler) UpdateAgentCustomer(c *gin.Context) {
 	agentID, err := GetAgentFromContext(c)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 		return
 	}
 
-	customerID := c.Param("id")
+	customerID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Customer not found"})
+		return
+	}
 
-	var customer domain.Customer
-	if err := database.GetDB().Where("agent_id = ?", agentID).First(&customer, customerID).Error; err != nil {
+	customer, err := h.customers.GetByID(c.Request.Context(), agentID, customerID)
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Customer not found"})
 		return
 	}
@@ -406,7 +435,7 @@ func UpdateAgentCustomer(c *gin.Context) {
 		customer.Postcode = req.Postcode
 	}
 
-	if err := database.GetDB().Save(&customer).Error; err != nil {
+	if err := h.customers.Update(c.Request.Context(), customer); err != nil {
 		log.Error().Err(err).Msg("Failed to update customer")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update customer"})
 		return
@@ -417,7 +446,7 @@ func UpdateAgentCustomer(c *gin.Context) {
 }
 
 // GetAgentCommissions retrieves all commissions for the agent
-func GetAgentCommissions(c *gin.Context) {
+func (h *AgentPortalHandler) GetAgentCommissions(c *gin.Context) {
 	agentID, err := GetAgentFromContext(c)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
@@ -426,28 +455,21 @@ func GetAgentCommissions(c *gin.Context) {
 
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
-	status := c.Query("status")
-
-	offset := (page - 1) * limit
-
-	var commissions []domain.Commission
-	query := database.GetDB().Model(&domain.Commission{}).Where("agent_id = ?", agentID)
-
-	if status != "" {
-		query = query.Where("status = ?", status)
-	}
-
-	var total int64
-	query.Count(&total)
 
-	if err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&commissions).Error; err != nil {
+	commissions, total, err := h.commissions.GetByAgentID(c.Request.Context(), agentID, repository.CommissionFilter{
+		Status: c.Query("status"),
+		Type:   c.Query("type"),
+		Page:   page,
+		Limit:  limit,
+	})
+	if err != nil {
 		log.Error().Err(err).Msg("Failed to fetch commissions")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch commissions"})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"data":        commissions,
+		"data":        NewCommissionResponses(commissions),
 		"total":       total,
 		"page":        page,
 		"limit":       limit,
@@ -456,23 +478,18 @@ func GetAgentCommissions(c *gin.Context) {
 }
 
 // GetAgentPerformance retrieves monthly performance metrics
-func GetAgentPerformance(c *gin.Context) {
+func (h *AgentPortalHandler) GetAgentPerformance(c *gin.Context) {
 	agentID, err := GetAgentFromContext(c)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 		return
 	}
 
-	// Get agent email from context to look up auth user ID for orders
-	agentEmail, _ := c.Get("agent_email")
-
-	db := database.GetDB()
+	ctx := c.Request.Context()
 
 	// Get auth user ID for order queries (orders use auth UUID, not agent uint)
-	var authUserID string
-	if agentEmail != nil {
-		db.Table("auth.users").Where("email = ?", agentEmail).Select("id").Scan(&authUserID)
-	}
+	authUserID, _ := h.agentUserID(c)
+	currency := h.payoutCurrency(c, agentID)
 
 	// Get last 12 months
 	var performances []domain.Performance
@@ -480,43 +497,28 @@ func GetAgentPerformance(c *gin.Context) {
 	for i := 11; i >= 0; i-- {
 		monthStart := time.Now().AddDate(0, -i, 0)
 		monthStart = time.Date(monthStart.Year(), monthStart.Month(), 1, 0, 0, 0, 0, monthStart.Location())
-		monthEnd := monthStart.AddDate(0, 1, 0)
+		month := repository.Period{From: monthStart, To: monthStart.AddDate(0, 1, 0)}
 
 		var perf domain.Performance
 		perf.Month = monthStart
+		perf.Currency = currency
 
 		// Total sales and orders for this month (use auth user UUID)
 		if authUserID != "" {
-			db.Model(&domain.Order{}).
-				Where("agent_id = ? AND created_at >= ? AND created_at < ?", authUserID, monthStart, monthEnd).
-				Count(&perf.TotalOrders)
-
-			db.Model(&domain.Order{}).
-				Where("agent_id = ? AND created_at >= ? AND created_at < ?", authUserID, monthStart, monthEnd).
-				Select("COALESCE(SUM(total), 0)").
-				Scan(&perf.TotalSales)
+			if orders, err := h.orders.GetSummary(ctx, authUserID, month); err == nil {
+				perf.TotalOrders = orders.Count
+				perf.TotalSales = orders.Total
+			}
 		}
 
 		// Commission breakdown (use agent uint ID)
-		db.Model(&domain.Commission{}).
-			Where("agent_id = ? AND created_at >= ? AND created_at < ?", agentID, monthStart, monthEnd).
-			Select("COALESCE(SUM(amount), 0)").
-			Scan(&perf.TotalCommission)
-
-		db.Model(&domain.Commission{}).
-			Where("agent_id = ? AND status = ? AND created_at >= ? AND created_at < ?", agentID, "pending", monthStart, monthEnd).
-			Select("COALESCE(SUM(amount), 0)").
-			Scan(&perf.CommissionPending)
-
-		db.Model(&domain.Commission{}).
-			Where("agent_id = ? AND status = ? AND created_at >= ? AND created_at < ?", agentID, "approved", monthStart, monthEnd).
-			Select("COALESCE(SUM(amount), 0)").
-			Scan(&perf.CommissionApproved)
-
-		db.Model(&domain.Commission{}).
-			Where("agent_id = ? AND status = ? AND created_at >= ? AND created_at < ?", agentID, "paid", monthStart, monthEnd).
-			Select("COALESCE(SUM(amount), 0)").
-			Scan(&perf.CommissionPaid)
+		if summary, err := h.commissions.GetSummary(ctx, agentID, month); err == nil {
+			totals := summary.In(currency)
+			perf.TotalCommission = totals.Total
+			perf.CommissionPending = totals.Pending
+			perf.CommissionApproved = totals.Approved
+			perf.CommissionPaid = totals.Paid
+		}
 
 		performances = append(performances, perf)
 	}
@@ -525,31 +527,42 @@ func GetAgentPerformance(c *gin.Context) {
 }
 
 // GetAgentTeam retrieves team information
-func GetAgentTeam(c *gin.Context) {
+func (h *AgentPortalHandler) GetAgentTeam(c *gin.Context) {
 	agentID, err := GetAgentFromContext(c)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 		return
 	}
 
-	// Get agent with team
-	var agent domain.Agent
-	if err := database.GetDB().Preload("Team").First(&agent, agentID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Agent not found"})
-		return
-	}
+	ctx := c.Request.Context()
 
-	if agent.Team == nil {
-		c.JSON(http.StatusOK, gin.H{"message": "Agent is not part of any team"})
+	// Get agent's team
+	t, err := h.teams.GetByAgentID(ctx, agentID)
+	if err != nil {
+		if errors.Is(err, team.ErrTeamNotFound) {
+			c.JSON(http.StatusOK, gin.H{"message": "Agent is not part of any team"})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
 		return
 	}
 
 	// Get full team details with members
-	var team domain.Team
-	if err := database.GetDB().Preload("Leader").Preload("Members").First(&team, agent.TeamID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
+	response := NewTeamResponse(t)
+	if t.LeaderID() != nil {
+		if leader, err := h.agents.GetByID(ctx, *t.LeaderID()); err == nil {
+			leaderResponse := NewAgentResponse(leader)
+			response.Leader = &leaderResponse
+		}
+	}
+
+	members, err := h.teams.GetMembers(ctx, t.ID())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to fetch team members")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch team"})
 		return
 	}
+	response.Members = NewAgentResponses(members)
 
-	c.JSON(http.StatusOK, team)
+	c.JSON(http.StatusOK, response)
 }
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	services "github.com/Ecom-micro-template/service-agent/internal/application"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/commission"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// AutoApprovalHandler handles commissions approved without review
+type AutoApprovalHandler struct {
+	approvals repository.AutoApprovalReader
+	approver  *services.AutoApprover
+}
+
+// NewAutoApprovalHandler creates a new auto-approval handler
+func NewAutoApprovalHandler(approvals repository.AutoApprovalReader, approver *services.AutoApprover) *AutoApprovalHandler {
+	return &AutoApprovalHandler{
+		approvals: approvals,
+		approver:  approver,
+	}
+}
+
+// GetAutoApprovals lists auto-approvals, newest first
+func (h *AutoApprovalHandler) GetAutoApprovals(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	approvals, total, err := h.approvals.List(c.Request.Context(), page, limit)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to fetch auto-approvals")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch auto-approvals"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  NewAutoApprovalResponses(approvals),
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// GetCommissionAutoApproval shows the rule that approved a commission
+func (h *AutoApprovalHandler) GetCommissionAutoApproval(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid commission ID"})
+		return
+	}
+
+	approval, err := h.approvals.GetByCommissionID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, commission.ErrAutoApprovalNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Commission was not auto-approved"})
+			return
+		}
+		log.Error().Err(err).Uint("commission_id", id).Msg("Failed to fetch auto-approval")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch auto-approval"})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewAutoApprovalResponse(approval))
+}
+
+// RunAutoApproval approves every due pending commission now
+func (h *AutoApprovalHandler) RunAutoApproval(c *gin.Context) {
+	approvals, err := h.approver.ApproveDue(c.Request.Context(), time.Now())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to run auto-approval")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to run auto-approval"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  NewAutoApprovalResponses(approvals),
+		"total": len(approvals),
+	})
+}
@@ -3,27 +3,31 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+	"time"
 
-	"github.com/gin-gonic/gin"
+	services "github.com/Ecom-micro-template/service-agent/internal/application"
 	"github.com/Ecom-micro-template/service-agent/internal/domain"
-	"github.com/Ecom-micro-template/service-agent/internal/infrastructure/persistence"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog/log"
-	"gorm.io/gorm"
 )
 
 // CategoryCommissionHandler handles category commission operations
 type CategoryCommissionHandler struct {
-	repo persistence.CategoryCommissionRepository
+	repo  repository.CategoryCommissionReader
+	rates *services.RateService
 }
 
 // NewCategoryCommissionHandler creates a new category commission handler
-func NewCategoryCommissionHandler(db *gorm.DB) *CategoryCommissionHandler {
+func NewCategoryCommissionHandler(repo repository.CategoryCommissionReader, rates *services.RateService) *CategoryCommissionHandler {
 	return &CategoryCommissionHandler{
-		repo: persistence.NewCategoryCommissionRepository(db),
+		repo:  repo,
+		rates: rates,
 	}
 }
 
-// GetAgentCategoryCommissions gets category-specific commissions for an agent
+// GetAgentCategoryCommissions gets the category-specific commissions in
+// effect for an agent. ?at (RFC 3339) looks them up at another time.
 func (h *CategoryCommissionHandler) GetAgentCategoryCommissions(c *gin.Context) {
 	agentIDStr := c.Param("id")
 	agentID, err := strconv.ParseUint(agentIDStr, 10, 32)
@@ -32,7 +36,15 @@ func (h *CategoryCommissionHandler) GetAgentCategoryCommissions(c *gin.Context)
 		return
 	}
 
-	commissions, err := h.repo.GetByAgentID(c.Request.Context(), uint(agentID))
+	at := time.Now()
+	if value := c.Query("at"); value != "" {
+		if at, err = time.Parse(time.RFC3339, value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid at, expected RFC 3339"})
+			return
+		}
+	}
+
+	commissions, err := h.repo.GetByAgentID(c.Request.Context(), uint(agentID), at)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to fetch category commissions")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch category commissions"})
@@ -42,6 +54,25 @@ func (h *CategoryCommissionHandler) GetAgentCategoryCommissions(c *gin.Context)
 	c.JSON(http.StatusOK, gin.H{"data": commissions})
 }
 
+// GetAgentCategoryCommissionHistory gets every category-specific commission
+// an agent has had or is scheduled to have, oldest effective first
+func (h *CategoryCommissionHandler) GetAgentCategoryCommissionHistory(c *gin.Context) {
+	agentID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid agent ID"})
+		return
+	}
+
+	commissions, err := h.repo.GetHistory(c.Request.Context(), agentID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to fetch category commission history")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch category commission history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": commissions})
+}
+
 // UpdateAgentCategoryCommissionsRequest is the request for updating category commissions
 type UpdateAgentCategoryCommissionsRequest struct {
 	Commissions []struct {
@@ -50,9 +81,13 @@ type UpdateAgentCategoryCommissionsRequest struct {
 		CommissionRate float64 `json:"commission_rate"`
 		IsActive       bool    `json:"is_active"`
 	} `json:"commissions"`
+	// EffectiveFrom is when the rates take effect; omitted means now
+	EffectiveFrom *time.Time `json:"effective_from"`
 }
 
-// UpdateAgentCategoryCommissions updates category-specific commissions for an agent
+// UpdateAgentCategoryCommissions replaces category-specific commissions for
+// an agent, now or from a future date. Earlier rates are kept so past
+// orders are still calculated at the rates they were placed under.
 func (h *CategoryCommissionHandler) UpdateAgentCategoryCommissions(c *gin.Context) {
 	agentIDStr := c.Param("id")
 	agentID, err := strconv.ParseUint(agentIDStr, 10, 32)
@@ -81,9 +116,13 @@ func (h *CategoryCommissionHandler) UpdateAgentCategoryCommissions(c *gin.Contex
 		}
 	}
 
-	if err := h.repo.BulkReplace(c.Request.Context(), uint(agentID), commissions); err != nil {
-		log.Error().Err(err).Msg("Failed to update category commissions")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update category commissions"})
+	var effectiveFrom time.Time
+	if req.EffectiveFrom != nil {
+		effectiveFrom = *req.EffectiveFrom
+	}
+
+	if err := h.rates.ScheduleCategoryRates(c.Request.Context(), uint(agentID), commissions, effectiveFrom); err != nil {
+		respondRateError(c, err, "Failed to update category commissions")
 		return
 	}
 
@@ -91,18 +130,25 @@ func (h *CategoryCommissionHandler) UpdateAgentCategoryCommissions(c *gin.Contex
 	c.JSON(http.StatusOK, gin.H{"message": "Category commissions updated successfully"})
 }
 
-// Legacy function handlers for backward compatibility with existing routes
+// CancelAgentCategoryCommission cancels a category-specific commission that
+// has not taken effect yet
+func (h *CategoryCommissionHandler) CancelAgentCategoryCommission(c *gin.Context) {
+	agentID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid agent ID"})
+		return
+	}
+	id, err := parseIDParam(c, "commissionId")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category commission ID"})
+		return
+	}
 
-// GetAgentCategoryCommissionsLegacy is a legacy function-based handler
-// Deprecated: Use CategoryCommissionHandler.GetAgentCategoryCommissions instead
-func GetAgentCategoryCommissionsLegacy(db *gorm.DB) gin.HandlerFunc {
-	handler := NewCategoryCommissionHandler(db)
-	return handler.GetAgentCategoryCommissions
-}
+	if err := h.rates.CancelCategoryRate(c.Request.Context(), agentID, id); err != nil {
+		respondRateError(c, err, "Failed to cancel category commission")
+		return
+	}
 
-// UpdateAgentCategoryCommissionsLegacy is a legacy function-based handler
-// Deprecated: Use CategoryCommissionHandler.UpdateAgentCategoryCommissions instead
-func UpdateAgentCategoryCommissionsLegacy(db *gorm.DB) gin.HandlerFunc {
-	handler := NewCategoryCommissionHandler(db)
-	return handler.UpdateAgentCategoryCommissions
+	log.Info().Uint("agent_id", agentID).Uint("category_commission_id", id).Msg("Category commission cancelled")
+	c.JSON(http.StatusOK, gin.H{"message": "Category commission cancelled successfully"})
 }
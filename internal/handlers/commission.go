@@ -1,24 +1,48 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 
+	services "github.com/Ecom-micro-template/service-agent/internal/application"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/commission"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/fx"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
 	"github.com/gin-gonic/gin"
-	"github.com/Ecom-micro-template/service-agent/internal/database"
-	"github.com/Ecom-micro-template/service-agent/internal/domain"
 	"github.com/rs/zerolog/log"
 )
 
+// CommissionHandler handles commission operations
+type CommissionHandler struct {
+	commissions repository.CommissionRepository
+	agents      repository.AgentRepository
+	service     *services.CommissionService
+}
+
+// NewCommissionHandler creates a new commission handler
+func NewCommissionHandler(commissions repository.CommissionRepository, agents repository.AgentRepository, service *services.CommissionService) *CommissionHandler {
+	return &CommissionHandler{
+		commissions: commissions,
+		agents:      agents,
+		service:     service,
+	}
+}
+
 type CreateCommissionRequest struct {
 	AgentID    uint    `json:"agent_id" binding:"required"`
 	OrderID    string  `json:"order_id" binding:"required"`
 	OrderTotal float64 `json:"order_total" binding:"required,gt=0"`
 	Rate       float64 `json:"rate"`
+	// Currency is the order currency; omitted means the default currency
+	Currency string `json:"currency"`
 }
 
 // CreateCommission creates a new commission record
-func CreateCommission(c *gin.Context) {
+func (h *CommissionHandler) CreateCommission(c *gin.Context) {
 	var req CreateCommissionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -26,8 +50,8 @@ func CreateCommission(c *gin.Context) {
 	}
 
 	// Get agent to use their commission rate if not specified
-	var agent domain.Agent
-	if err := database.GetDB().First(&agent, req.AgentID).Error; err != nil {
+	a, err := h.agents.GetByID(c.Request.Context(), req.AgentID)
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Agent not found"})
 		return
 	}
@@ -35,136 +59,309 @@ func CreateCommission(c *gin.Context) {
 	// Use agent's commission rate if not provided
 	rate := req.Rate
 	if rate == 0 {
-		rate = agent.CommissionRate
+		rate = a.CommissionRate().Value()
 	}
 
-	// Calculate commission amount
-	amount := domain.CalculateCommission(req.OrderTotal, rate)
+	currency, err := shared.ParseCurrency(req.Currency)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	commission := domain.Commission{
+	newCommission, err := commission.NewCommission(commission.CommissionParams{
 		AgentID:    req.AgentID,
 		OrderID:    req.OrderID,
-		OrderTotal: req.OrderTotal,
+		OrderTotal: shared.MoneyFromFloat(req.OrderTotal, currency),
 		Rate:       rate,
-		Amount:     amount,
-		Status:     "pending",
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.service.ConvertForPayout(c.Request.Context(), newCommission, newCommission.CreatedAt()); err != nil {
+		if errors.Is(err, fx.ErrRateNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Error().Err(err).Msg("Failed to convert commission")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create commission"})
+		return
 	}
 
-	if err := database.GetDB().Create(&commission).Error; err != nil {
+	if err := h.commissions.Create(c.Request.Context(), newCommission); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Commission already exists for this order and agent"})
+			return
+		}
 		log.Error().Err(err).Msg("Failed to create commission")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create commission"})
 		return
 	}
 
-	log.Info().Uint("commission_id", commission.ID).Float64("amount", amount).Msg("Commission created")
-	c.JSON(http.StatusCreated, commission)
+	log.Info().Uint("commission_id", newCommission.ID()).Float64("amount", newCommission.Amount().Float64()).Msg("Commission created")
+	c.JSON(http.StatusCreated, NewCommissionResponse(newCommission))
 }
 
-// GetAgentCommissionsByID retrieves all commissions for an agent by ID (admin function)
-func GetAgentCommissionsByID(c *gin.Context) {
-	agentID := c.Param("id")
-	status := c.Query("status")
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+// CreateAdjustmentRequest is a manual adjustment to an agent's earnings.
+// The amount is signed: positive for a credit, negative for a debit.
+type CreateAdjustmentRequest struct {
+	Amount   float64 `json:"amount" binding:"required"`
+	Category string  `json:"category" binding:"required"`
+	Note     string  `json:"note" binding:"required"`
+	// Currency omitted means the agent's payout currency
+	Currency string `json:"currency"`
+}
 
-	offset := (page - 1) * limit
+// CreateAdjustment grants an agent a bonus, applies a penalty or corrects
+// their earnings. The adjustment is approved at once.
+func (h *CommissionHandler) CreateAdjustment(c *gin.Context) {
+	agentID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid agent ID"})
+		return
+	}
 
-	var commissions []domain.Commission
-	query := database.GetDB().Where("agent_id = ?", agentID)
+	var req CreateAdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
-	if status != "" {
-		query = query.Where("status = ?", status)
+	ctx := c.Request.Context()
+	a, err := h.agents.GetByID(ctx, agentID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Agent not found"})
+		return
 	}
 
-	var total int64
-	query.Model(&domain.Commission{}).Count(&total)
+	currency := a.PayoutCurrency()
+	if req.Currency != "" {
+		if currency, err = shared.ParseCurrency(req.Currency); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
 
-	if err := query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&commissions).Error; err != nil {
+	actor := actorFromContext(c)
+	adjustment, err := h.service.Adjust(ctx, commission.AdjustmentParams{
+		AgentID:   agentID,
+		Amount:    shared.MoneyFromFloat(req.Amount, currency),
+		Category:  shared.AdjustmentCategory(req.Category),
+		Note:      req.Note,
+		CreatedBy: actor,
+	})
+	if err != nil {
+		if errors.Is(err, shared.ErrInvalidAdjustmentCategory) || errors.Is(err, commission.ErrInvalidAdjustment) ||
+			errors.Is(err, commission.ErrNoteRequired) || errors.Is(err, fx.ErrRateNotFound) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Error().Err(err).Uint("agent_id", agentID).Msg("Failed to create adjustment")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create adjustment"})
+		return
+	}
+
+	log.Info().
+		Uint("commission_id", adjustment.ID()).
+		Uint("agent_id", agentID).
+		Str("category", adjustment.Category().String()).
+		Float64("amount", adjustment.Amount().Float64()).
+		Str("actor", actor).
+		Msg("Adjustment created")
+	c.JSON(http.StatusCreated, NewCommissionResponse(adjustment))
+}
+
+// GetAgentCommissionsByID retrieves all commissions for an agent by ID (admin function)
+func (h *CommissionHandler) GetAgentCommissionsByID(c *gin.Context) {
+	agentID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid agent ID"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	ctx := c.Request.Context()
+	commissions, total, err := h.commissions.GetByAgentID(ctx, agentID, repository.CommissionFilter{
+		Status: c.Query("status"),
+		Type:   c.Query("type"),
+		Page:   page,
+		Limit:  limit,
+	})
+	if err != nil {
 		log.Error().Err(err).Msg("Failed to fetch commissions")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch commissions"})
 		return
 	}
 
-	// Calculate totals
-	var totalAmount float64
-	database.GetDB().Model(&domain.Commission{}).
-		Where("agent_id = ?", agentID).
-		Select("COALESCE(SUM(amount), 0)").
-		Row().
-		Scan(&totalAmount)
-
-	var pendingAmount float64
-	database.GetDB().Model(&domain.Commission{}).
-		Where("agent_id = ? AND status = ?", agentID, "pending").
-		Select("COALESCE(SUM(amount), 0)").
-		Row().
-		Scan(&pendingAmount)
+	// Calculate totals, in the agent's payout currency and per currency
+	summary, err := h.commissions.GetSummary(ctx, agentID, repository.Period{})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to fetch commission summary")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch commissions"})
+		return
+	}
+	currency := shared.DefaultCurrency
+	if a, err := h.agents.GetByID(ctx, agentID); err == nil {
+		currency = a.PayoutCurrency()
+	}
+	totals := summary.In(currency)
 
 	c.JSON(http.StatusOK, gin.H{
-		"data":           commissions,
+		"data":           NewCommissionResponses(commissions),
 		"total":          total,
 		"page":           page,
 		"limit":          limit,
 		"total_pages":    (total + int64(limit) - 1) / int64(limit),
-		"total_amount":   totalAmount,
-		"pending_amount": pendingAmount,
+		"currency":       currency,
+		"total_amount":   totals.Total,
+		"pending_amount": totals.Pending,
+		"totals":         summary.Totals,
 	})
 }
 
 // ApproveCommission approves a pending commission
-func ApproveCommission(c *gin.Context) {
-	id := c.Param("id")
+func (h *CommissionHandler) ApproveCommission(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid commission ID"})
+		return
+	}
 
-	var commission domain.Commission
-	if err := database.GetDB().First(&commission, id).Error; err != nil {
+	ctx := c.Request.Context()
+	comm, err := h.commissions.GetByID(ctx, id)
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Commission not found"})
 		return
 	}
 
-	commission.Status = "approved"
-	if err := database.GetDB().Save(&commission).Error; err != nil {
+	// Approval records the agent's earnings and the team leader's override
+	if err := h.service.Approve(ctx, comm); err != nil {
+		if errors.Is(err, shared.ErrInvalidCommissionTransition) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Commission cannot be approved"})
+			return
+		}
+		if errors.Is(err, repository.ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		log.Error().Err(err).Msg("Failed to approve commission")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to approve commission"})
 		return
 	}
 
-	// Update agent's total earned
-	var agent domain.Agent
-	if err := database.GetDB().First(&agent, commission.AgentID).Error; err == nil {
-		agent.TotalEarned += commission.Amount
-		database.GetDB().Save(&agent)
+	log.Info().Uint("commission_id", comm.ID()).Msg("Commission approved")
+	c.JSON(http.StatusOK, NewCommissionResponse(comm))
+}
+
+// CommissionStatusRequest is the request to reject or cancel a commission
+type CommissionStatusRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// RejectCommission rejects a pending commission on review
+func (h *CommissionHandler) RejectCommission(c *gin.Context) {
+	h.closeCommission(c, "reject", h.service.Reject)
+}
+
+// CancelCommission cancels a pending or approved commission, reversing the
+// agent's earnings if it was approved
+func (h *CommissionHandler) CancelCommission(c *gin.Context) {
+	h.closeCommission(c, "cancel", h.service.Cancel)
+}
+
+// closeCommission rejects or cancels a commission with the reason given and
+// the admin making the request
+func (h *CommissionHandler) closeCommission(
+	c *gin.Context,
+	action string,
+	apply func(ctx context.Context, comm *commission.Commission, reason, actor string) error,
+) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid commission ID"})
+		return
+	}
+
+	var req CommissionStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	comm, err := h.commissions.GetByID(ctx, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Commission not found"})
+		return
 	}
 
-	log.Info().Uint("commission_id", commission.ID).Msg("Commission approved")
-	c.JSON(http.StatusOK, commission)
+	actor := actorFromContext(c)
+	if err := apply(ctx, comm, req.Reason, actor); err != nil {
+		if errors.Is(err, shared.ErrInvalidCommissionTransition) || errors.Is(err, commission.ErrReasonRequired) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, repository.ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		log.Error().Err(err).Uint("commission_id", id).Msgf("Failed to %s commission", action)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to %s commission", action)})
+		return
+	}
+
+	log.Info().
+		Uint("commission_id", comm.ID()).
+		Str("status", comm.Status().String()).
+		Str("reason", req.Reason).
+		Str("actor", actor).
+		Msg("Commission closed")
+	c.JSON(http.StatusOK, NewCommissionResponse(comm))
+}
+
+// actorFromContext identifies the admin making the request by the user ID
+// in their token, falling back to their email
+func actorFromContext(c *gin.Context) string {
+	for _, key := range []string{"user_id", "email"} {
+		if value, exists := c.Get(key); exists && value != nil {
+			return fmt.Sprint(value)
+		}
+	}
+	return ""
 }
 
 // GetPendingCommissions retrieves all pending commissions
-func GetPendingCommissions(c *gin.Context) {
+func (h *CommissionHandler) GetPendingCommissions(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
 
-	offset := (page - 1) * limit
-
-	var commissions []domain.Commission
-	var total int64
-
-	database.GetDB().Model(&domain.Commission{}).Where("status = ?", "pending").Count(&total)
-
-	if err := database.GetDB().
-		Where("status = ?", "pending").
-		Preload("Agent").
-		Offset(offset).
-		Limit(limit).
-		Order("created_at DESC").
-		Find(&commissions).Error; err != nil {
+	ctx := c.Request.Context()
+	commissions, total, err := h.commissions.GetPending(ctx, page, limit)
+	if err != nil {
 		log.Error().Err(err).Msg("Failed to fetch pending commissions")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch pending commissions"})
 		return
 	}
 
+	// Attach each commission's agent
+	data := NewCommissionResponses(commissions)
+	agents := make(map[uint]*AgentResponse)
+	for i := range data {
+		agentID := data[i].AgentID
+		if _, loaded := agents[agentID]; !loaded {
+			agents[agentID] = nil
+			if a, err := h.agents.GetByID(ctx, agentID); err == nil {
+				response := NewAgentResponse(a)
+				agents[agentID] = &response
+			}
+		}
+		data[i].Agent = agents[agentID]
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"data":        commissions,
+		"data":        data,
 		"total":       total,
 		"page":        page,
 		"limit":       limit,
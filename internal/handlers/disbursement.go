@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	services "github.com/Ecom-micro-template/service-agent/internal/application"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/payout"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/infrastructure/disbursement"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// DisbursementHandler pays payouts through the payout provider and
+// receives the provider's callbacks
+type DisbursementHandler struct {
+	payouts repository.PayoutReader
+	runs    repository.PayoutRunReader
+	service *services.DisbursementService
+	secret  string
+}
+
+// NewDisbursementHandler creates a new disbursement handler. secret signs
+// provider callbacks; without one, callbacks are refused.
+func NewDisbursementHandler(
+	payouts repository.PayoutReader,
+	runs repository.PayoutRunReader,
+	service *services.DisbursementService,
+	secret string,
+) *DisbursementHandler {
+	return &DisbursementHandler{
+		payouts: payouts,
+		runs:    runs,
+		service: service,
+		secret:  secret,
+	}
+}
+
+// SubmitPayout sends a pending payout to the payout provider
+func (h *DisbursementHandler) SubmitPayout(c *gin.Context) {
+	h.withPayout(c, h.service.Submit)
+}
+
+// SyncPayout queries a submitted payout's transfer at the provider and
+// applies its status
+func (h *DisbursementHandler) SyncPayout(c *gin.Context) {
+	h.withPayout(c, h.service.Sync)
+}
+
+// CancelTransfer cancels a processing payout's transfer at the provider.
+// The payout fails and can then be retried or cancelled.
+func (h *DisbursementHandler) CancelTransfer(c *gin.Context) {
+	h.withPayout(c, h.service.CancelTransfer)
+}
+
+// SubmitRun sends every pending payout in a payout run to the provider
+func (h *DisbursementHandler) SubmitRun(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payout run ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	run, err := h.runs.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, payout.ErrRunNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Payout run not found"})
+			return
+		}
+		log.Error().Err(err).Uint("run_id", id).Msg("Failed to fetch payout run")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch payout run"})
+		return
+	}
+
+	submitted, failed, err := h.service.SubmitRun(ctx, run)
+	if err != nil {
+		h.providerError(c, err, "run_id", id)
+		return
+	}
+
+	errs := make(map[uint]string, len(failed))
+	for payoutID, err := range failed {
+		errs[payoutID] = err.Error()
+		log.Warn().Err(err).Uint("run_id", id).Uint("payout_id", payoutID).Msg("Failed to submit payout")
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"submitted": submitted,
+		"failed":    errs,
+	})
+}
+
+// ProviderCallbackRequest is a transfer update pushed by the payout
+// provider
+type ProviderCallbackRequest struct {
+	Reference      string `json:"reference" binding:"required"`
+	Status         string `json:"status" binding:"required"`
+	TransactionRef string `json:"transaction_ref"`
+	Reason         string `json:"reason"`
+}
+
+// ProviderCallback applies a transfer update pushed by the payout
+// provider. The body must be signed with the shared secret, as the hex
+// HMAC-SHA256 in the X-Signature header. Any response other than 2xx asks
+// the provider to deliver the update again.
+func (h *DisbursementHandler) ProviderCallback(c *gin.Context) {
+	if h.secret == "" {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Payout provider callbacks are not configured"})
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, 1<<20))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !h.validSignature(body, c.GetHeader("X-Signature")) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid signature"})
+		return
+	}
+
+	var req ProviderCallbackRequest
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = h.service.HandleUpdate(c.Request.Context(), disbursement.Update{
+		Reference:      req.Reference,
+		Status:         req.Status,
+		TransactionRef: req.TransactionRef,
+		Reason:         req.Reason,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, payout.ErrPayoutNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Payout not found"})
+		case errors.Is(err, shared.ErrInvalidPayoutTransition), errors.Is(err, repository.ErrConflict):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			log.Error().Err(err).Str("reference", req.Reference).Msg("Failed to apply payout provider callback")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to apply payout provider callback"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// validSignature checks a callback body's HMAC-SHA256 signature
+func (h *DisbursementHandler) validSignature(body []byte, signature string) bool {
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(h.secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
+
+// withPayout loads the payout, applies a provider request to it and
+// responds with the payout as it is afterwards
+func (h *DisbursementHandler) withPayout(c *gin.Context, apply func(ctx context.Context, p *payout.Payout) error) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payout ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	p, err := h.payouts.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, payout.ErrPayoutNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Payout not found"})
+			return
+		}
+		log.Error().Err(err).Uint("payout_id", id).Msg("Failed to fetch payout")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch payout"})
+		return
+	}
+
+	if err := apply(ctx, p); err != nil {
+		h.providerError(c, err, "payout_id", id)
+		return
+	}
+
+	p, err = h.payouts.GetByID(ctx, id)
+	if err != nil {
+		log.Error().Err(err).Uint("payout_id", id).Msg("Failed to fetch payout")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch payout"})
+		return
+	}
+	c.JSON(http.StatusOK, NewPayoutResponse(p))
+}
+
+// providerError responds to an error paying through the provider
+func (h *DisbursementHandler) providerError(c *gin.Context, err error, key string, id uint) {
+	switch {
+	case errors.Is(err, services.ErrNoPayoutProvider):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	case errors.Is(err, shared.ErrInvalidPayoutTransition),
+		errors.Is(err, payout.ErrPayoutOnHold),
+		errors.Is(err, payout.ErrRunNotCommitted),
+		errors.Is(err, disbursement.ErrNotCancellable),
+		errors.Is(err, repository.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, payout.ErrNothingToSend),
+		errors.Is(err, disbursement.ErrTransferNotFound):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case disbursement.IsTransient(err):
+		log.Warn().Err(err).Uint(key, id).Msg("Payout provider unavailable")
+		c.JSON(http.StatusGatewayTimeout, gin.H{"error": "Payout provider did not respond; try again later"})
+	default:
+		log.Error().Err(err).Uint(key, id).Msg("Failed to pay through payout provider")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to pay through payout provider"})
+	}
+}
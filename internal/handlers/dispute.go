@@ -0,0 +1,304 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	services "github.com/Ecom-micro-template/service-agent/internal/application"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/commission"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/dispute"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/fx"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// DisputeHandler handles agents' commission disputes, from the portal and
+// from the admin side
+type DisputeHandler struct {
+	disputes repository.DisputeReader
+	service  *services.DisputeService
+}
+
+// NewDisputeHandler creates a new dispute handler
+func NewDisputeHandler(disputes repository.DisputeReader, service *services.DisputeService) *DisputeHandler {
+	return &DisputeHandler{
+		disputes: disputes,
+		service:  service,
+	}
+}
+
+// OpenDisputeRequest is an agent's request to dispute an order or a
+// commission. At least one of them must be given.
+type OpenDisputeRequest struct {
+	OrderID      string `json:"order_id"`
+	CommissionID *uint  `json:"commission_id"`
+	Note         string `json:"note" binding:"required"`
+}
+
+// DisputeMessageRequest adds a message to a dispute thread
+type DisputeMessageRequest struct {
+	Body string `json:"body" binding:"required"`
+}
+
+// ResolveDisputeRequest resolves a dispute, optionally granting the agent
+// an adjustment commission on the order
+type ResolveDisputeRequest struct {
+	Resolution       string  `json:"resolution" binding:"required"`
+	AdjustmentAmount float64 `json:"adjustment_amount" binding:"gte=0"`
+}
+
+// RejectDisputeRequest rejects a dispute
+type RejectDisputeRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// --- Agent portal ---
+
+// OpenDispute opens a dispute for the authenticated agent
+func (h *DisputeHandler) OpenDispute(c *gin.Context) {
+	agentID, err := GetAgentFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req OpenDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.OrderID == "" && req.CommissionID == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "order_id or commission_id is required"})
+		return
+	}
+
+	d, err := h.service.Open(c.Request.Context(), agentID, req.OrderID, req.CommissionID, req.Note)
+	if err != nil {
+		respondDisputeError(c, err, "Failed to open dispute")
+		return
+	}
+
+	log.Info().Uint("dispute_id", d.ID()).Uint("agent_id", agentID).Str("order_id", d.OrderID()).Msg("Dispute opened")
+	c.JSON(http.StatusCreated, NewDisputeResponse(d))
+}
+
+// GetMyDisputes lists the authenticated agent's disputes, newest first
+func (h *DisputeHandler) GetMyDisputes(c *gin.Context) {
+	agentID, err := GetAgentFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	h.listDisputes(c, agentID)
+}
+
+// GetMyDispute shows one of the authenticated agent's disputes with its
+// thread
+func (h *DisputeHandler) GetMyDispute(c *gin.Context) {
+	d, ok := h.loadMyDispute(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, NewDisputeResponse(d))
+}
+
+// AddMyDisputeMessage adds the authenticated agent's message to their
+// dispute
+func (h *DisputeHandler) AddMyDisputeMessage(c *gin.Context) {
+	d, ok := h.loadMyDispute(c)
+	if !ok {
+		return
+	}
+	h.addMessage(c, d, dispute.AuthorAgent, "")
+}
+
+// loadMyDispute loads the dispute in the path if it belongs to the
+// authenticated agent, responding with an error otherwise
+func (h *DisputeHandler) loadMyDispute(c *gin.Context) (*dispute.Dispute, bool) {
+	agentID, err := GetAgentFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return nil, false
+	}
+	d, ok := h.loadDispute(c)
+	if !ok {
+		return nil, false
+	}
+	if d.AgentID() != agentID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Dispute not found"})
+		return nil, false
+	}
+	return d, true
+}
+
+// --- Admin ---
+
+// GetDisputes lists disputes, newest first, optionally for one agent
+func (h *DisputeHandler) GetDisputes(c *gin.Context) {
+	agentID, _ := strconv.ParseUint(c.Query("agent_id"), 10, 32)
+	h.listDisputes(c, uint(agentID))
+}
+
+// GetDispute shows a dispute with its thread
+func (h *DisputeHandler) GetDispute(c *gin.Context) {
+	d, ok := h.loadDispute(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, NewDisputeResponse(d))
+}
+
+// AddDisputeMessage adds an admin's message to a dispute
+func (h *DisputeHandler) AddDisputeMessage(c *gin.Context) {
+	d, ok := h.loadDispute(c)
+	if !ok {
+		return
+	}
+	h.addMessage(c, d, dispute.AuthorAdmin, actorFromContext(c))
+}
+
+// ReviewDispute marks a dispute as under review
+func (h *DisputeHandler) ReviewDispute(c *gin.Context) {
+	d, ok := h.loadDispute(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.StartReview(c.Request.Context(), d, actorFromContext(c)); err != nil {
+		respondDisputeError(c, err, "Failed to update dispute")
+		return
+	}
+	c.JSON(http.StatusOK, NewDisputeResponse(d))
+}
+
+// ResolveDispute resolves a dispute, granting an adjustment commission when
+// an amount is given
+func (h *DisputeHandler) ResolveDispute(c *gin.Context) {
+	d, ok := h.loadDispute(c)
+	if !ok {
+		return
+	}
+
+	var req ResolveDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	actor := actorFromContext(c)
+	if err := h.service.Resolve(c.Request.Context(), d, req.Resolution, actor, req.AdjustmentAmount); err != nil {
+		respondDisputeError(c, err, "Failed to resolve dispute")
+		return
+	}
+
+	log.Info().Uint("dispute_id", d.ID()).Str("actor", actor).Float64("adjustment", req.AdjustmentAmount).Msg("Dispute resolved")
+	c.JSON(http.StatusOK, NewDisputeResponse(d))
+}
+
+// RejectDispute rejects a dispute with the reason given
+func (h *DisputeHandler) RejectDispute(c *gin.Context) {
+	d, ok := h.loadDispute(c)
+	if !ok {
+		return
+	}
+
+	var req RejectDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	actor := actorFromContext(c)
+	if err := h.service.Reject(c.Request.Context(), d, req.Reason, actor); err != nil {
+		respondDisputeError(c, err, "Failed to reject dispute")
+		return
+	}
+
+	log.Info().Uint("dispute_id", d.ID()).Str("actor", actor).Msg("Dispute rejected")
+	c.JSON(http.StatusOK, NewDisputeResponse(d))
+}
+
+// --- Shared ---
+
+func (h *DisputeHandler) listDisputes(c *gin.Context, agentID uint) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	status := c.Query("status")
+	if status != "" {
+		if _, err := shared.ParseDisputeStatus(status); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	disputes, total, err := h.disputes.List(c.Request.Context(), repository.DisputeFilter{
+		AgentID: agentID,
+		Status:  status,
+		Page:    page,
+		Limit:   limit,
+	})
+	if err != nil {
+		log.Error().Err(err).Uint("agent_id", agentID).Msg("Failed to fetch disputes")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch disputes"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  NewDisputeResponses(disputes),
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// loadDispute loads the dispute in the path, responding with an error if
+// there is none
+func (h *DisputeHandler) loadDispute(c *gin.Context) (*dispute.Dispute, bool) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dispute ID"})
+		return nil, false
+	}
+
+	d, err := h.disputes.GetByID(c.Request.Context(), id)
+	if err != nil {
+		respondDisputeError(c, err, "Failed to fetch dispute")
+		return nil, false
+	}
+	return d, true
+}
+
+func (h *DisputeHandler) addMessage(c *gin.Context, d *dispute.Dispute, author, authorID string) {
+	var req DisputeMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.AddMessage(c.Request.Context(), d, author, authorID, req.Body); err != nil {
+		respondDisputeError(c, err, "Failed to add message")
+		return
+	}
+	c.JSON(http.StatusCreated, NewDisputeResponse(d))
+}
+
+// respondDisputeError maps dispute errors to HTTP responses
+func respondDisputeError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, dispute.ErrDisputeNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Dispute not found"})
+	case errors.Is(err, commission.ErrCommissionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Commission not found"})
+	case errors.Is(err, dispute.ErrInvalidDispute), errors.Is(err, dispute.ErrEmptyMessage),
+		errors.Is(err, dispute.ErrDisputeClosed), errors.Is(err, shared.ErrInvalidDisputeTransition),
+		errors.Is(err, fx.ErrRateNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		log.Error().Err(err).Msg(message)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	services "github.com/Ecom-micro-template/service-agent/internal/application"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/fx"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// FXRateHandler handles the exchange rate table used to convert
+// commissions to agents' payout currencies
+type FXRateHandler struct {
+	rates repository.FXRateReader
+	fx    *services.FXService
+}
+
+// NewFXRateHandler creates a new FX rate handler
+func NewFXRateHandler(rates repository.FXRateReader, fx *services.FXService) *FXRateHandler {
+	return &FXRateHandler{
+		rates: rates,
+		fx:    fx,
+	}
+}
+
+// SetFXRateRequest is the request to record an exchange rate
+type SetFXRateRequest struct {
+	BaseCurrency  string `json:"base_currency" binding:"required"`
+	QuoteCurrency string `json:"quote_currency" binding:"required"`
+	// Rate is units of the quote currency per unit of the base currency,
+	// as a decimal string
+	Rate string `json:"rate" binding:"required"`
+	// EffectiveFrom is when the rate takes effect; omitted means now
+	EffectiveFrom *time.Time `json:"effective_from"`
+}
+
+// GetFXRates lists exchange rates, newest effective first. Both base and
+// quote filter to one currency pair.
+func (h *FXRateHandler) GetFXRates(c *gin.Context) {
+	base := strings.ToUpper(c.Query("base"))
+	quote := strings.ToUpper(c.Query("quote"))
+	if (base == "") != (quote == "") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "base and quote must be given together"})
+		return
+	}
+
+	rates, err := h.rates.List(c.Request.Context(), base, quote)
+	if err != nil {
+		log.Error().Err(err).Str("base", base).Str("quote", quote).Msg("Failed to fetch exchange rates")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch exchange rates"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  NewFXRateResponses(rates),
+		"total": len(rates),
+	})
+}
+
+// SetFXRate records the exchange rate for a currency pair from a point in
+// time on
+func (h *FXRateHandler) SetFXRate(c *gin.Context) {
+	var req SetFXRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var effectiveFrom time.Time
+	if req.EffectiveFrom != nil {
+		effectiveFrom = *req.EffectiveFrom
+	}
+
+	rate, err := h.fx.SetRate(c.Request.Context(), req.BaseCurrency, req.QuoteCurrency, req.Rate, effectiveFrom)
+	if err != nil {
+		respondFXRateError(c, err, "Failed to record exchange rate")
+		return
+	}
+
+	log.Info().
+		Str("base", rate.BaseCurrency()).
+		Str("quote", rate.QuoteCurrency()).
+		Str("rate", rate.ExchangeRate().String()).
+		Time("effective_from", rate.EffectiveFrom()).
+		Msg("Exchange rate recorded")
+	c.JSON(http.StatusCreated, NewFXRateResponse(rate))
+}
+
+// DeleteFXRate removes an exchange rate from the table
+func (h *FXRateHandler) DeleteFXRate(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid exchange rate ID"})
+		return
+	}
+
+	if err := h.fx.DeleteRate(c.Request.Context(), id); err != nil {
+		respondFXRateError(c, err, "Failed to delete exchange rate")
+		return
+	}
+
+	log.Info().Uint("fx_rate_id", id).Msg("Exchange rate deleted")
+	c.JSON(http.StatusOK, gin.H{"message": "Exchange rate deleted successfully"})
+}
+
+// respondFXRateError maps exchange rate errors to HTTP responses
+func respondFXRateError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, fx.ErrRateNotFound), errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Exchange rate not found"})
+	case errors.Is(err, fx.ErrInvalidRate), errors.Is(err, shared.ErrInvalidCurrency),
+		errors.Is(err, shared.ErrInvalidExchangeRate):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		log.Error().Err(err).Msg(message)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
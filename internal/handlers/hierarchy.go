@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	services "github.com/Ecom-micro-template/service-agent/internal/application"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/agent"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// HierarchyHandler handles admin operations on the agent upline/downline tree
+type HierarchyHandler struct {
+	hierarchy *services.HierarchyService
+}
+
+// NewHierarchyHandler creates a new hierarchy handler
+func NewHierarchyHandler(hierarchy *services.HierarchyService) *HierarchyHandler {
+	return &HierarchyHandler{hierarchy: hierarchy}
+}
+
+type MoveAgentRequest struct {
+	// ParentID is the new upline sponsor; null makes the agent top-level
+	ParentID *uint `json:"parent_id"`
+}
+
+// GetUpline returns an agent's upline sponsors, nearest first
+func (h *HierarchyHandler) GetUpline(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid agent ID"})
+		return
+	}
+
+	upline, err := h.hierarchy.Upline(c.Request.Context(), id, 0)
+	if err != nil {
+		h.respondError(c, err, "Failed to fetch upline")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  NewAgentResponses(upline),
+		"total": len(upline),
+	})
+}
+
+// GetDownline returns the tree of agents below an agent.
+// ?depth limits the number of levels returned.
+func (h *HierarchyHandler) GetDownline(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid agent ID"})
+		return
+	}
+	depth, _ := strconv.Atoi(c.DefaultQuery("depth", "0"))
+
+	root, err := h.hierarchy.Downline(c.Request.Context(), id, depth)
+	if err != nil {
+		h.respondError(c, err, "Failed to fetch downline")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":      NewHierarchyNodeResponse(root),
+		"depth":     root.Depth(),
+		"size":      root.Size(),
+		"max_depth": h.hierarchy.MaxDepth(),
+	})
+}
+
+// MoveAgent moves an agent, with its whole downline, under a new sponsor
+func (h *HierarchyHandler) MoveAgent(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid agent ID"})
+		return
+	}
+
+	var req MoveAgentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	a, err := h.hierarchy.Move(c.Request.Context(), id, req.ParentID)
+	if err != nil {
+		h.respondError(c, err, "Failed to move agent")
+		return
+	}
+
+	log.Info().Uint("agent_id", a.ID()).Interface("parent_id", a.ParentID()).Msg("Agent moved")
+	c.JSON(http.StatusOK, NewAgentResponse(a))
+}
+
+// respondError maps hierarchy errors to HTTP responses
+func (h *HierarchyHandler) respondError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, agent.ErrAgentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Agent not found"})
+	case errors.Is(err, agent.ErrHierarchyCycle), errors.Is(err, agent.ErrHierarchyTooDeep):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Error().Err(err).Msg(message)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	services "github.com/Ecom-micro-template/service-agent/internal/application"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/agent"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// LedgerHandler shows agents' earnings ledgers and reconciles them
+type LedgerHandler struct {
+	ledger  repository.LedgerReader
+	agents  repository.AgentReader
+	service *services.LedgerService
+}
+
+// NewLedgerHandler creates a new ledger handler
+func NewLedgerHandler(ledger repository.LedgerReader, agents repository.AgentReader, service *services.LedgerService) *LedgerHandler {
+	return &LedgerHandler{
+		ledger:  ledger,
+		agents:  agents,
+		service: service,
+	}
+}
+
+// GetMyLedger shows the authenticated agent's balances and ledger entries
+func (h *LedgerHandler) GetMyLedger(c *gin.Context) {
+	agentID, err := GetAgentFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	h.showLedger(c, agentID)
+}
+
+// GetAgentLedger shows an agent's balances and ledger entries
+func (h *LedgerHandler) GetAgentLedger(c *gin.Context) {
+	agentID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid agent ID"})
+		return
+	}
+	if _, err := h.agents.GetByID(c.Request.Context(), agentID); err != nil {
+		if errors.Is(err, agent.ErrAgentNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Agent not found"})
+			return
+		}
+		log.Error().Err(err).Uint("agent_id", agentID).Msg("Failed to fetch agent")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch agent"})
+		return
+	}
+	h.showLedger(c, agentID)
+}
+
+// Reconcile checks agents' ledger balances against their commissions,
+// clawbacks and payouts, for one agent when agent_id is given
+func (h *LedgerHandler) Reconcile(c *gin.Context) {
+	agentID, _ := strconv.ParseUint(c.Query("agent_id"), 10, 32)
+
+	result, err := h.service.Reconcile(c.Request.Context(), uint(agentID))
+	if err != nil {
+		if errors.Is(err, agent.ErrAgentNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Agent not found"})
+			return
+		}
+		log.Error().Err(err).Msg("Failed to reconcile ledger")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reconcile ledger"})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewReconciliationResponse(result))
+}
+
+func (h *LedgerHandler) showLedger(c *gin.Context, agentID uint) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	account := c.Query("account")
+	if account != "" {
+		if _, err := shared.ParseLedgerAccount(account); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	balances, err := h.ledger.GetBalances(ctx, agentID)
+	if err != nil {
+		log.Error().Err(err).Uint("agent_id", agentID).Msg("Failed to fetch ledger balances")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch ledger"})
+		return
+	}
+
+	entries, total, err := h.ledger.GetEntries(ctx, repository.LedgerFilter{
+		AgentID: agentID,
+		Account: account,
+		Page:    page,
+		Limit:   limit,
+	})
+	if err != nil {
+		log.Error().Err(err).Uint("agent_id", agentID).Msg("Failed to fetch ledger entries")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch ledger"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"balances": NewLedgerBalanceResponses(balances),
+		"data":     NewLedgerEntryResponses(entries),
+		"total":    total,
+		"page":     page,
+		"limit":    limit,
+	})
+}
@@ -1,192 +1,232 @@
 package handlers
 
 import (
-	"encoding/json"
+	"errors"
 	"net/http"
-	"time"
 
+	services "github.com/Ecom-micro-template/service-agent/internal/application"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/payout"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
 	"github.com/gin-gonic/gin"
-	"github.com/Ecom-micro-template/service-agent/internal/database"
-	"github.com/Ecom-micro-template/service-agent/internal/domain"
 	"github.com/rs/zerolog/log"
 )
 
+// PayoutHandler handles payout operations
+type PayoutHandler struct {
+	payouts repository.PayoutRepository
+	agents  repository.AgentReader
+	service *services.PayoutService
+}
+
+// NewPayoutHandler creates a new payout handler
+func NewPayoutHandler(
+	payouts repository.PayoutRepository,
+	agents repository.AgentReader,
+	service *services.PayoutService,
+) *PayoutHandler {
+	return &PayoutHandler{
+		payouts: payouts,
+		agents:  agents,
+		service: service,
+	}
+}
+
 type CreatePayoutRequest struct {
 	AgentID uint   `json:"agent_id" binding:"required"`
 	Period  string `json:"period" binding:"required"` // Format: YYYY-MM
+	// Convert pays commissions converted to the agent's payout currency.
+	// Without it, every commission must be in the currency it is paid in.
+	Convert bool `json:"convert"`
 }
 
 // CreatePayout creates a new payout for approved commissions
-func CreatePayout(c *gin.Context) {
+func (h *PayoutHandler) CreatePayout(c *gin.Context) {
 	var req CreatePayoutRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Get all approved commissions for the agent that haven't been paid
-	var commissions []domain.Commission
-	if err := database.GetDB().
-		Where("agent_id = ? AND status = ?", req.AgentID, "approved").
-		Find(&commissions).Error; err != nil {
-		log.Error().Err(err).Msg("Failed to fetch commissions")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch commissions"})
+	newPayout, err := h.service.Create(c.Request.Context(), req.AgentID, req.Period, req.Convert)
+	if err != nil {
+		switch {
+		case errors.Is(err, payout.ErrNoCommissions):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "No approved commissions found"})
+		case errors.Is(err, payout.ErrPayoutOnHold), errors.Is(err, repository.ErrConflict):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, payout.ErrConversionRequired):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Commissions were converted to the agent's payout currency; set convert to pay them in it"})
+		case errors.Is(err, shared.ErrCurrencyMismatch):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Commissions were converted to more than one payout currency"})
+		case errors.Is(err, payout.ErrInvalidPeriod),
+			errors.Is(err, payout.ErrInvalidPayout),
+			errors.Is(err, payout.ErrNegativePayout):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			log.Error().Err(err).Uint("agent_id", req.AgentID).Msg("Failed to create payout")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create payout"})
+		}
 		return
 	}
 
-	if len(commissions) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "No approved commissions found"})
+	c.JSON(http.StatusCreated, NewPayoutResponse(newPayout))
+}
+
+// GetAgentPayouts retrieves all payouts for an agent
+func (h *PayoutHandler) GetAgentPayouts(c *gin.Context) {
+	agentID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid agent ID"})
 		return
 	}
 
-	// Calculate total amount and collect commission IDs
-	var totalAmount float64
-	var commissionIDs []uint
-	for _, commission := range commissions {
-		totalAmount += commission.Amount
-		commissionIDs = append(commissionIDs, commission.ID)
+	payouts, _, err := h.payouts.GetByAgentID(c.Request.Context(), agentID, 0, 0)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to fetch payouts")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch payouts"})
+		return
 	}
 
-	// Convert commission IDs to JSON
-	commissionIDsJSON, _ := json.Marshal(commissionIDs)
-
-	payout := domain.Payout{
-		AgentID:       req.AgentID,
-		Amount:        totalAmount,
-		Period:        req.Period,
-		CommissionIDs: string(commissionIDsJSON),
-		Status:        "pending",
-	}
+	c.JSON(http.StatusOK, gin.H{
+		"data": NewPayoutResponses(payouts),
+	})
+}
 
-	if err := database.GetDB().Create(&payout).Error; err != nil {
-		log.Error().Err(err).Msg("Failed to create payout")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create payout"})
+// GetMyPayouts retrieves the authenticated agent's payouts
+func (h *PayoutHandler) GetMyPayouts(c *gin.Context) {
+	agentID, err := GetAgentFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 		return
 	}
 
-	// Update commissions status to 'paid'
-	database.GetDB().Model(&domain.Commission{}).
-		Where("id IN ?", commissionIDs).
-		Update("status", "paid")
-
-	log.Info().Uint("payout_id", payout.ID).Float64("amount", totalAmount).Msg("Payout created")
-	c.JSON(http.StatusCreated, payout)
-}
-
-// GetAgentPayouts retrieves all payouts for an agent
-func GetAgentPayouts(c *gin.Context) {
-	agentID := c.Param("id")
-
-	var payouts []domain.Payout
-	if err := database.GetDB().
-		Where("agent_id = ?", agentID).
-		Order("created_at DESC").
-		Find(&payouts).Error; err != nil {
+	payouts, _, err := h.payouts.GetByAgentID(c.Request.Context(), agentID, 0, 0)
+	if err != nil {
 		log.Error().Err(err).Msg("Failed to fetch payouts")
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch payouts"})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"data": payouts,
+		"data": NewPayoutResponses(payouts),
 	})
 }
 
 // GetPayout retrieves a single payout by ID
-func GetPayout(c *gin.Context) {
-	id := c.Param("id")
+func (h *PayoutHandler) GetPayout(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payout ID"})
+		return
+	}
 
-	var payout domain.Payout
-	if err := database.GetDB().Preload("Agent").First(&payout, id).Error; err != nil {
+	ctx := c.Request.Context()
+	p, err := h.payouts.GetByID(ctx, id)
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Payout not found"})
 		return
 	}
 
-	c.JSON(http.StatusOK, payout)
+	response := NewPayoutResponse(p)
+	if a, err := h.agents.GetByID(ctx, p.AgentID()); err == nil {
+		agentResponse := NewAgentResponse(a)
+		response.Agent = &agentResponse
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
-// MarkPayoutPaid marks a payout as paid
-func MarkPayoutPaid(c *gin.Context) {
-	id := c.Param("id")
+// CompletePayoutRequest is the request to mark a payout as paid
+type CompletePayoutRequest struct {
+	TransactionRef string `json:"transaction_ref" binding:"required"`
+}
 
-	var payout domain.Payout
-	if err := database.GetDB().First(&payout, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Payout not found"})
+// FailPayoutRequest is the request to mark a payout as failed
+type FailPayoutRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// ProcessPayout starts processing a pending payout
+func (h *PayoutHandler) ProcessPayout(c *gin.Context) {
+	h.transition(c, func(p *payout.Payout) error {
+		return h.service.Process(c.Request.Context(), p)
+	})
+}
+
+// CompletePayout marks a processing payout as paid, with the bank
+// transaction reference. Its commissions are paid.
+func (h *PayoutHandler) CompletePayout(c *gin.Context) {
+	var req CompletePayoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	h.transition(c, func(p *payout.Payout) error {
+		return h.service.Complete(c.Request.Context(), p, req.TransactionRef)
+	})
+}
 
-	now := time.Now()
-	payout.Status = "paid"
-	payout.PaidAt = &now
-
-	if err := database.GetDB().Save(&payout).Error; err != nil {
-		log.Error().Err(err).Msg("Failed to mark payout as paid")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark payout as paid"})
+// FailPayout marks a processing payout as failed, with the reason
+func (h *PayoutHandler) FailPayout(c *gin.Context) {
+	var req FailPayoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	h.transition(c, func(p *payout.Payout) error {
+		return h.service.Fail(c.Request.Context(), p, req.Reason)
+	})
+}
 
-	log.Info().Uint("payout_id", payout.ID).Msg("Payout marked as paid")
-	c.JSON(http.StatusOK, payout)
+// RetryPayout returns a failed payout to pending
+func (h *PayoutHandler) RetryPayout(c *gin.Context) {
+	h.transition(c, func(p *payout.Payout) error {
+		return h.service.Retry(c.Request.Context(), p)
+	})
 }
 
-// GetAgentStats retrieves statistics for an agent
-func GetAgentStats(c *gin.Context) {
-	agentID := c.Param("id")
+// CancelPayout cancels a pending or failed payout. Its commissions are
+// approved again and the clawbacks it netted are outstanding again.
+func (h *PayoutHandler) CancelPayout(c *gin.Context) {
+	h.transition(c, func(p *payout.Payout) error {
+		return h.service.Cancel(c.Request.Context(), p)
+	})
+}
 
-	var agent domain.Agent
-	if err := database.GetDB().First(&agent, agentID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Agent not found"})
+// transition loads the payout and applies a status change to it
+func (h *PayoutHandler) transition(c *gin.Context, apply func(*payout.Payout) error) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payout ID"})
 		return
 	}
 
-	// Get total commissions
-	var totalCommissions int64
-	var totalCommissionAmount float64
-	database.GetDB().Model(&domain.Commission{}).
-		Where("agent_id = ?", agentID).
-		Count(&totalCommissions)
-	database.GetDB().Model(&domain.Commission{}).
-		Where("agent_id = ?", agentID).
-		Select("COALESCE(SUM(amount), 0)").
-		Row().
-		Scan(&totalCommissionAmount)
-
-	// Get pending commissions
-	var pendingCommissions int64
-	var pendingAmount float64
-	database.GetDB().Model(&domain.Commission{}).
-		Where("agent_id = ? AND status = ?", agentID, "pending").
-		Count(&pendingCommissions)
-	database.GetDB().Model(&domain.Commission{}).
-		Where("agent_id = ? AND status = ?", agentID, "pending").
-		Select("COALESCE(SUM(amount), 0)").
-		Row().
-		Scan(&pendingAmount)
-
-	// Get this month's commissions
-	currentMonth := time.Now().Format("2006-01")
-	var thisMonthAmount float64
-	database.GetDB().Model(&domain.Commission{}).
-		Where("agent_id = ? AND TO_CHAR(created_at, 'YYYY-MM') = ?", agentID, currentMonth).
-		Select("COALESCE(SUM(amount), 0)").
-		Row().
-		Scan(&thisMonthAmount)
-
-	// Get total payouts
-	var totalPayouts int64
-	database.GetDB().Model(&domain.Payout{}).
-		Where("agent_id = ?", agentID).
-		Count(&totalPayouts)
-
-	stats := gin.H{
-		"agent":                   agent,
-		"total_commissions":       totalCommissions,
-		"total_commission_amount": totalCommissionAmount,
-		"pending_commissions":     pendingCommissions,
-		"pending_amount":          pendingAmount,
-		"this_month_amount":       thisMonthAmount,
-		"total_payouts":           totalPayouts,
+	p, err := h.payouts.GetByID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, payout.ErrPayoutNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Payout not found"})
+			return
+		}
+		log.Error().Err(err).Uint("payout_id", id).Msg("Failed to fetch payout")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch payout"})
+		return
+	}
+
+	if err := apply(p); err != nil {
+		switch {
+		case errors.Is(err, shared.ErrInvalidPayoutTransition),
+			errors.Is(err, payout.ErrPayoutOnHold),
+			errors.Is(err, repository.ErrConflict):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, payout.ErrReasonRequired):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			log.Error().Err(err).Uint("payout_id", id).Msg("Failed to update payout")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update payout"})
+		}
+		return
 	}
 
-	c.JSON(http.StatusOK, stats)
+	c.JSON(http.StatusOK, NewPayoutResponse(p))
 }
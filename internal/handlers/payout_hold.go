@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	services "github.com/Ecom-micro-template/service-agent/internal/application"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/agent"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/payout"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// PayoutHoldHandler handles the holds admins place on risky agents'
+// payouts, their reserve policies and the reserves kept back from their
+// payouts
+type PayoutHoldHandler struct {
+	holds    repository.PayoutHoldReader
+	reserves repository.PayoutReserveReader
+	service  *services.PayoutHoldService
+	payouts  *services.PayoutService
+}
+
+// NewPayoutHoldHandler creates a new payout hold handler
+func NewPayoutHoldHandler(
+	holds repository.PayoutHoldReader,
+	reserves repository.PayoutReserveReader,
+	service *services.PayoutHoldService,
+	payouts *services.PayoutService,
+) *PayoutHoldHandler {
+	return &PayoutHoldHandler{
+		holds:    holds,
+		reserves: reserves,
+		service:  service,
+		payouts:  payouts,
+	}
+}
+
+// PlacePayoutHoldRequest represents the request to hold an agent's payouts
+type PlacePayoutHoldRequest struct {
+	Reason    string    `json:"reason" binding:"required"`
+	ExpiresAt time.Time `json:"expires_at" binding:"required"`
+}
+
+// ReservePolicyRequest represents the request to set an agent's reserve
+// policy
+type ReservePolicyRequest struct {
+	Percent float64 `json:"percent" binding:"required,gt=0,max=100"`
+	Days    int     `json:"days" binding:"required,min=1"`
+	Reason  string  `json:"reason" binding:"required"`
+}
+
+// ListActiveHolds lists the payout holds active now, over every agent
+func (h *PayoutHoldHandler) ListActiveHolds(c *gin.Context) {
+	holds, err := h.holds.ListActive(c.Request.Context(), time.Now())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to fetch payout holds")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch payout holds"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": NewPayoutHoldResponses(holds)})
+}
+
+// GetAgentHolds lists an agent's payout holds, active or not
+func (h *PayoutHoldHandler) GetAgentHolds(c *gin.Context) {
+	agentID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid agent ID"})
+		return
+	}
+
+	holds, err := h.holds.ListByAgent(c.Request.Context(), agentID)
+	if err != nil {
+		log.Error().Err(err).Uint("agent_id", agentID).Msg("Failed to fetch payout holds")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch payout holds"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": NewPayoutHoldResponses(holds)})
+}
+
+// PlaceHold holds an agent's payouts until the expiry given
+func (h *PayoutHoldHandler) PlaceHold(c *gin.Context) {
+	agentID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid agent ID"})
+		return
+	}
+
+	var req PlacePayoutHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hold, err := h.service.Place(c.Request.Context(), agentID, req.Reason, req.ExpiresAt, actorFromContext(c))
+	if err != nil {
+		respondPayoutHoldError(c, err, "Failed to place payout hold")
+		return
+	}
+	c.JSON(http.StatusCreated, NewPayoutHoldResponse(hold))
+}
+
+// ReleaseHold ends a payout hold before it expires
+func (h *PayoutHoldHandler) ReleaseHold(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payout hold ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	hold, err := h.holds.GetByID(ctx, id)
+	if err != nil {
+		respondPayoutHoldError(c, err, "Failed to fetch payout hold")
+		return
+	}
+	if err := h.service.Release(ctx, hold, actorFromContext(c)); err != nil {
+		respondPayoutHoldError(c, err, "Failed to release payout hold")
+		return
+	}
+	c.JSON(http.StatusOK, NewPayoutHoldResponse(hold))
+}
+
+// GetReservePolicy shows an agent's reserve policy
+func (h *PayoutHoldHandler) GetReservePolicy(c *gin.Context) {
+	agentID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid agent ID"})
+		return
+	}
+
+	policy, err := h.service.ReservePolicy(c.Request.Context(), agentID)
+	if err != nil {
+		respondPayoutHoldError(c, err, "Failed to fetch reserve policy")
+		return
+	}
+	c.JSON(http.StatusOK, NewReservePolicyResponse(policy))
+}
+
+// UpdateReservePolicy sets an agent's reserve policy. Their payouts
+// created from now on keep back reserves by it.
+func (h *PayoutHoldHandler) UpdateReservePolicy(c *gin.Context) {
+	agentID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid agent ID"})
+		return
+	}
+
+	var req ReservePolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	policy, err := h.service.SetReservePolicy(c.Request.Context(), agentID, req.Percent, req.Days, req.Reason, actorFromContext(c))
+	if err != nil {
+		respondPayoutHoldError(c, err, "Failed to save reserve policy")
+		return
+	}
+	c.JSON(http.StatusOK, NewReservePolicyResponse(policy))
+}
+
+// DeleteReservePolicy stops keeping back reserves from an agent's payouts.
+// Reserves already held are still released when they are due.
+func (h *PayoutHoldHandler) DeleteReservePolicy(c *gin.Context) {
+	agentID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid agent ID"})
+		return
+	}
+
+	if err := h.service.RemoveReservePolicy(c.Request.Context(), agentID, actorFromContext(c)); err != nil {
+		respondPayoutHoldError(c, err, "Failed to delete reserve policy")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Reserve policy deleted"})
+}
+
+// GetAgentReserves lists the reserves kept back from an agent's payouts
+func (h *PayoutHoldHandler) GetAgentReserves(c *gin.Context) {
+	agentID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid agent ID"})
+		return
+	}
+
+	reserves, err := h.reserves.ListByAgent(c.Request.Context(), agentID)
+	if err != nil {
+		log.Error().Err(err).Uint("agent_id", agentID).Msg("Failed to fetch reserves")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reserves"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": NewPayoutReserveResponses(reserves)})
+}
+
+// RunReserveRelease releases every due reserve now, as the scheduler does
+func (h *PayoutHoldHandler) RunReserveRelease(c *gin.Context) {
+	payouts, err := h.payouts.ReleaseReserves(c.Request.Context(), time.Now())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to release reserves")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to release reserves"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  NewPayoutResponses(payouts),
+		"total": len(payouts),
+	})
+}
+
+func respondPayoutHoldError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, agent.ErrAgentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Agent not found"})
+	case errors.Is(err, payout.ErrHoldNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Payout hold not found"})
+	case errors.Is(err, payout.ErrReservePolicyNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reserve policy not found"})
+	case errors.Is(err, payout.ErrInvalidHold), errors.Is(err, payout.ErrInvalidReservePolicy):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, payout.ErrHoldInactive):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Error().Err(err).Msg(message)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
@@ -0,0 +1,266 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	services "github.com/Ecom-micro-template/service-agent/internal/application"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/payout"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// PayoutMethodHandler handles the accounts agents are paid into: agents
+// manage their own from the portal, and admins can see any agent's
+type PayoutMethodHandler struct {
+	methods  repository.PayoutMethodReader
+	service  *services.PayoutMethodService
+	cooldown time.Duration
+}
+
+// NewPayoutMethodHandler creates a new payout method handler. cooldown is
+// how long payouts are held after a change, shown on each method.
+func NewPayoutMethodHandler(
+	methods repository.PayoutMethodReader,
+	service *services.PayoutMethodService,
+	cooldown time.Duration,
+) *PayoutMethodHandler {
+	return &PayoutMethodHandler{
+		methods:  methods,
+		service:  service,
+		cooldown: cooldown,
+	}
+}
+
+// AddPayoutMethodRequest represents the request to add a payout method
+type AddPayoutMethodRequest struct {
+	Type string `json:"type" binding:"required,oneof=bank_account ewallet"`
+	UpdatePayoutMethodRequest
+}
+
+// UpdatePayoutMethodRequest represents the request to change where a
+// payout method pays
+type UpdatePayoutMethodRequest struct {
+	Provider      string `json:"provider" binding:"required"`
+	ProviderCode  string `json:"provider_code"`
+	AccountName   string `json:"account_name" binding:"required"`
+	AccountNumber string `json:"account_number" binding:"required"`
+}
+
+// VerifyPayoutMethodRequest represents the request to verify a payout
+// method with the code sent to the agent
+type VerifyPayoutMethodRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// GetAgentPayoutMethods lists an agent's payout methods
+func (h *PayoutMethodHandler) GetAgentPayoutMethods(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid agent ID"})
+		return
+	}
+	h.listMethods(c, id)
+}
+
+// GetMyPayoutMethods lists the authenticated agent's payout methods
+func (h *PayoutMethodHandler) GetMyPayoutMethods(c *gin.Context) {
+	agentID, err := GetAgentFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	h.listMethods(c, agentID)
+}
+
+func (h *PayoutMethodHandler) listMethods(c *gin.Context, agentID uint) {
+	methods, err := h.methods.ListByAgent(c.Request.Context(), agentID)
+	if err != nil {
+		log.Error().Err(err).Uint("agent_id", agentID).Msg("Failed to fetch payout methods")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch payout methods"})
+		return
+	}
+
+	// Payouts are held unless the default method can be paid now
+	hold := ""
+	if err := h.service.Check(c.Request.Context(), agentID); err != nil {
+		if !errors.Is(err, payout.ErrPayoutOnHold) {
+			log.Error().Err(err).Uint("agent_id", agentID).Msg("Failed to check payout hold")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch payout methods"})
+			return
+		}
+		hold = err.Error()
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":        NewPayoutMethodResponses(methods, h.cooldown),
+		"payout_hold": hold,
+	})
+}
+
+// AddMyPayoutMethod adds a payout method for the authenticated agent and
+// sends them a verification code
+func (h *PayoutMethodHandler) AddMyPayoutMethod(c *gin.Context) {
+	agentID, err := GetAgentFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req AddPayoutMethodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	m, err := h.service.Add(c.Request.Context(), payout.MethodParams{
+		AgentID:       agentID,
+		Type:          req.Type,
+		Provider:      req.Provider,
+		ProviderCode:  req.ProviderCode,
+		AccountName:   req.AccountName,
+		AccountNumber: req.AccountNumber,
+	})
+	if m == nil {
+		respondPayoutMethodError(c, err, "Failed to add payout method")
+		return
+	}
+	if err != nil {
+		// The method is saved; the agent can ask for the code again
+		log.Error().Err(err).Uint("method_id", m.ID()).Msg("Failed to send payout method verification code")
+	}
+	c.JSON(http.StatusCreated, NewPayoutMethodResponse(m, h.cooldown))
+}
+
+// UpdateMyPayoutMethod changes where one of the authenticated agent's
+// payout methods pays. It must be verified again.
+func (h *PayoutMethodHandler) UpdateMyPayoutMethod(c *gin.Context) {
+	m, ok := h.loadMyMethod(c)
+	if !ok {
+		return
+	}
+
+	var req UpdatePayoutMethodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.Update(c.Request.Context(), m, req.Provider, req.ProviderCode, req.AccountName, req.AccountNumber); err != nil {
+		respondPayoutMethodError(c, err, "Failed to update payout method")
+		return
+	}
+	c.JSON(http.StatusOK, NewPayoutMethodResponse(m, h.cooldown))
+}
+
+// VerifyMyPayoutMethod verifies one of the authenticated agent's payout
+// methods with the code sent to them
+func (h *PayoutMethodHandler) VerifyMyPayoutMethod(c *gin.Context) {
+	m, ok := h.loadMyMethod(c)
+	if !ok {
+		return
+	}
+
+	var req VerifyPayoutMethodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.Verify(c.Request.Context(), m, req.Code); err != nil {
+		respondPayoutMethodError(c, err, "Failed to verify payout method")
+		return
+	}
+	c.JSON(http.StatusOK, NewPayoutMethodResponse(m, h.cooldown))
+}
+
+// ResendMyPayoutMethodCode sends a new verification code for one of the
+// authenticated agent's unverified payout methods
+func (h *PayoutMethodHandler) ResendMyPayoutMethodCode(c *gin.Context) {
+	m, ok := h.loadMyMethod(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.SendCode(c.Request.Context(), m); err != nil {
+		respondPayoutMethodError(c, err, "Failed to send verification code")
+		return
+	}
+	c.JSON(http.StatusOK, NewPayoutMethodResponse(m, h.cooldown))
+}
+
+// SetMyDefaultPayoutMethod makes one of the authenticated agent's verified
+// payout methods the one their payouts go to
+func (h *PayoutMethodHandler) SetMyDefaultPayoutMethod(c *gin.Context) {
+	m, ok := h.loadMyMethod(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.SetDefault(c.Request.Context(), m); err != nil {
+		respondPayoutMethodError(c, err, "Failed to set default payout method")
+		return
+	}
+	c.JSON(http.StatusOK, NewPayoutMethodResponse(m, h.cooldown))
+}
+
+// DeleteMyPayoutMethod removes one of the authenticated agent's payout
+// methods
+func (h *PayoutMethodHandler) DeleteMyPayoutMethod(c *gin.Context) {
+	m, ok := h.loadMyMethod(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Remove(c.Request.Context(), m); err != nil {
+		respondPayoutMethodError(c, err, "Failed to remove payout method")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Payout method removed"})
+}
+
+// loadMyMethod loads the payout method in the path if it belongs to the
+// authenticated agent, responding with an error otherwise
+func (h *PayoutMethodHandler) loadMyMethod(c *gin.Context) (*payout.Method, bool) {
+	agentID, err := GetAgentFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return nil, false
+	}
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payout method ID"})
+		return nil, false
+	}
+
+	m, err := h.methods.GetByID(c.Request.Context(), id)
+	if err == nil && m.AgentID() != agentID {
+		err = payout.ErrMethodNotFound
+	}
+	if err != nil {
+		respondPayoutMethodError(c, err, "Failed to fetch payout method")
+		return nil, false
+	}
+	return m, true
+}
+
+func respondPayoutMethodError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, payout.ErrMethodNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Payout method not found"})
+	case errors.Is(err, payout.ErrInvalidMethod), errors.Is(err, shared.ErrInvalidPayoutMethodType),
+		errors.Is(err, payout.ErrVerificationCode):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, payout.ErrMethodVerified), errors.Is(err, payout.ErrMethodNotVerified),
+		errors.Is(err, payout.ErrDefaultMethod), errors.Is(err, repository.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, payout.ErrVerificationLocked):
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+	default:
+		log.Error().Err(err).Msg(message)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
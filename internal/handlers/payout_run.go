@@ -0,0 +1,328 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	services "github.com/Ecom-micro-template/service-agent/internal/application"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/fx"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/payout"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/infrastructure/bankfile"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// PayoutRunHandler handles payout runs, which pay every eligible agent for
+// a period in one batch
+type PayoutRunHandler struct {
+	runs    repository.PayoutRunReader
+	files   repository.PayoutExportReader
+	service *services.PayoutService
+	exports *services.BankExportService
+	minimum float64
+}
+
+// NewPayoutRunHandler creates a new payout run handler. minimum is the
+// default minimum payout, in the default currency.
+func NewPayoutRunHandler(
+	runs repository.PayoutRunReader,
+	files repository.PayoutExportReader,
+	service *services.PayoutService,
+	exports *services.BankExportService,
+	minimum float64,
+) *PayoutRunHandler {
+	return &PayoutRunHandler{
+		runs:    runs,
+		files:   files,
+		service: service,
+		exports: exports,
+		minimum: minimum,
+	}
+}
+
+// PayoutRunRequest is the request to preview or commit a payout run
+type PayoutRunRequest struct {
+	Period string `json:"period" binding:"required"` // Format: YYYY-MM
+	// Minimum overrides the configured minimum payout, in the default
+	// currency
+	Minimum *float64 `json:"minimum"`
+}
+
+// PreviewRun shows who a payout run for the period would pay, and whose
+// balances it would carry forward, without saving anything
+func (h *PayoutRunHandler) PreviewRun(c *gin.Context) {
+	req, ok := h.bindRun(c)
+	if !ok {
+		return
+	}
+
+	run, err := h.service.PreviewRun(c.Request.Context(), req.Period, h.minimumOf(req), actorFromContext(c))
+	if err != nil {
+		h.runError(c, err, "Failed to preview payout run")
+		return
+	}
+
+	c.JSON(http.StatusOK, NewPayoutRunResponse(run))
+}
+
+// CommitRun commits a payout run for the period, creating a pending payout
+// for every eligible agent
+func (h *PayoutRunHandler) CommitRun(c *gin.Context) {
+	req, ok := h.bindRun(c)
+	if !ok {
+		return
+	}
+
+	run, err := h.service.CommitRun(c.Request.Context(), req.Period, h.minimumOf(req), actorFromContext(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicate):
+			c.JSON(http.StatusConflict, gin.H{"error": "The period already has a committed payout run"})
+		case errors.Is(err, payout.ErrEmptyRun):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		default:
+			h.runError(c, err, "Failed to commit payout run")
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, NewPayoutRunResponse(run))
+}
+
+// ListRuns lists payout runs, newest first
+func (h *PayoutRunHandler) ListRuns(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	status := c.Query("status")
+	if status != "" {
+		if _, err := shared.ParsePayoutRunStatus(status); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	runs, total, err := h.runs.List(c.Request.Context(), repository.PayoutRunFilter{
+		Period: c.Query("period"),
+		Status: status,
+		Page:   page,
+		Limit:  limit,
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to fetch payout runs")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch payout runs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  NewPayoutRunResponses(runs),
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// GetRun retrieves a payout run and its lines by ID
+func (h *PayoutRunHandler) GetRun(c *gin.Context) {
+	run, ok := h.loadRun(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, NewPayoutRunResponse(run))
+}
+
+// CancelRun cancels a committed payout run and its payouts, before any of
+// them have started processing
+func (h *PayoutRunHandler) CancelRun(c *gin.Context) {
+	run, ok := h.loadRun(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.CancelRun(c.Request.Context(), run, actorFromContext(c)); err != nil {
+		switch {
+		case errors.Is(err, payout.ErrRunInProgress),
+			errors.Is(err, shared.ErrInvalidPayoutRunTransition):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			log.Error().Err(err).Uint("run_id", run.ID()).Msg("Failed to cancel payout run")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel payout run"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, NewPayoutRunResponse(run))
+}
+
+// BankExportRequest is the request to export a payout run's bank payment
+// file
+type BankExportRequest struct {
+	Format string `json:"format" binding:"required"` // csv or pain001
+}
+
+// ExportRun writes a bank payment file for the run's pending payouts and
+// starts processing them. The file's export ID, checksum, payment count
+// and totals are returned in headers.
+func (h *PayoutRunHandler) ExportRun(c *gin.Context) {
+	run, ok := h.loadRun(c)
+	if !ok {
+		return
+	}
+
+	var req BankExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	export, file, held, err := h.exports.ExportRun(c.Request.Context(), run, req.Format, actorFromContext(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, bankfile.ErrUnknownFormat):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, payout.ErrRunNotCommitted),
+			errors.Is(err, payout.ErrPayoutOnHold),
+			errors.Is(err, repository.ErrConflict):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, payout.ErrNothingToSend),
+			errors.Is(err, bankfile.ErrInvalidBatch):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		default:
+			log.Error().Err(err).Uint("run_id", run.ID()).Msg("Failed to export payment file")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export payment file"})
+		}
+		return
+	}
+
+	if len(held) > 0 {
+		ids := make([]string, len(held))
+		for i, id := range held {
+			ids[i] = strconv.FormatUint(uint64(id), 10)
+		}
+		c.Header("X-Held-Payouts", strings.Join(ids, ","))
+	}
+	writePaymentFile(c, export, file)
+}
+
+// ListExports lists the payment files exported for a run, newest first
+func (h *PayoutRunHandler) ListExports(c *gin.Context) {
+	run, ok := h.loadRun(c)
+	if !ok {
+		return
+	}
+
+	exports, err := h.files.ListByRun(c.Request.Context(), run.ID())
+	if err != nil {
+		log.Error().Err(err).Uint("run_id", run.ID()).Msg("Failed to fetch payment file exports")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch payment file exports"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": NewPayoutExportResponses(exports)})
+}
+
+// DownloadExport downloads a payment file exported earlier, exactly as it
+// was handed out, with the same headers as the export
+func (h *PayoutRunHandler) DownloadExport(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid export ID"})
+		return
+	}
+
+	export, err := h.files.GetByID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, payout.ErrExportNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Payment file export not found"})
+			return
+		}
+		log.Error().Err(err).Uint("export_id", id).Msg("Failed to fetch payment file export")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch payment file export"})
+		return
+	}
+
+	file, err := h.exports.File(export)
+	if err != nil {
+		log.Error().Err(err).Uint("export_id", id).Msg("Failed to read payment file export")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read payment file export"})
+		return
+	}
+	writePaymentFile(c, export, file)
+}
+
+// writePaymentFile responds with a payment file, its export ID, checksum,
+// payment count and totals in headers
+func writePaymentFile(c *gin.Context, export *payout.Export, file *bankfile.File) {
+	totals := make([]string, 0, len(file.Totals))
+	for _, currency := range file.Currencies() {
+		totals = append(totals, fmt.Sprintf("%s %s", currency, file.Totals[currency]))
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file.Name))
+	c.Header("X-Export-ID", strconv.FormatUint(uint64(export.ID()), 10))
+	c.Header("X-Checksum-SHA256", file.Checksum)
+	c.Header("X-Payment-Count", strconv.Itoa(file.Count))
+	c.Header("X-Payment-Totals", strings.Join(totals, ", "))
+	c.Data(http.StatusOK, file.ContentType, file.Content)
+}
+
+func (h *PayoutRunHandler) bindRun(c *gin.Context) (PayoutRunRequest, bool) {
+	var req PayoutRunRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return req, false
+	}
+	if req.Minimum != nil && *req.Minimum < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "minimum cannot be negative"})
+		return req, false
+	}
+	return req, true
+}
+
+// minimumOf returns the minimum payout for a run request
+func (h *PayoutRunHandler) minimumOf(req PayoutRunRequest) shared.Money {
+	minimum := h.minimum
+	if req.Minimum != nil {
+		minimum = *req.Minimum
+	}
+	return shared.MoneyFromFloat(minimum, shared.DefaultCurrency)
+}
+
+func (h *PayoutRunHandler) loadRun(c *gin.Context) (*payout.Run, bool) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payout run ID"})
+		return nil, false
+	}
+
+	run, err := h.runs.GetByID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, payout.ErrRunNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Payout run not found"})
+			return nil, false
+		}
+		log.Error().Err(err).Uint("run_id", id).Msg("Failed to fetch payout run")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch payout run"})
+		return nil, false
+	}
+	return run, true
+}
+
+// runError responds to an error building a payout run
+func (h *PayoutRunHandler) runError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, payout.ErrInvalidPeriod),
+		errors.Is(err, payout.ErrFuturePeriod),
+		errors.Is(err, payout.ErrInvalidPayout):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, fx.ErrRateNotFound):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		log.Error().Err(err).Msg(message)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	services "github.com/Ecom-micro-template/service-agent/internal/application"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/payout"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/infrastructure/bankfile"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// maxStatementSize is the largest bank statement file accepted
+const maxStatementSize = 10 << 20
+
+// PayoutStatementHandler imports bank statements to reconcile payouts and
+// works the review queue of statement lines that matched no payout
+type PayoutStatementHandler struct {
+	statements repository.PayoutStatementReader
+	service    *services.StatementService
+}
+
+// NewPayoutStatementHandler creates a new payout statement handler
+func NewPayoutStatementHandler(statements repository.PayoutStatementReader, service *services.StatementService) *PayoutStatementHandler {
+	return &PayoutStatementHandler{
+		statements: statements,
+		service:    service,
+	}
+}
+
+// ImportStatement imports a bank statement uploaded as the multipart
+// "file" field, in the "format" field's format: csv or camt053
+func (h *PayoutStatementHandler) ImportStatement(c *gin.Context) {
+	format := c.PostForm("format")
+	if format == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format is required"})
+		return
+	}
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	if header.Size > maxStatementSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Statement file is too large"})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+	content, err := io.ReadAll(io.LimitReader(file, maxStatementSize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	statement, err := h.service.Import(c.Request.Context(), format, header.Filename, content, actorFromContext(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, bankfile.ErrUnknownFormat):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, repository.ErrDuplicate):
+			c.JSON(http.StatusConflict, gin.H{"error": "This statement file has already been imported"})
+		case errors.Is(err, repository.ErrConflict):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, bankfile.ErrInvalidStatement),
+			errors.Is(err, payout.ErrEmptyStatement):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		default:
+			log.Error().Err(err).Msg("Failed to import bank statement")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import bank statement"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, NewPayoutStatementResponse(statement))
+}
+
+// ListStatements lists imported bank statements, newest first
+func (h *PayoutStatementHandler) ListStatements(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	statements, total, err := h.statements.List(c.Request.Context(), page, limit)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to fetch bank statements")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch bank statements"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  NewPayoutStatementResponses(statements),
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// GetStatement retrieves a bank statement and how each line was matched
+func (h *PayoutStatementHandler) GetStatement(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid statement ID"})
+		return
+	}
+
+	statement, err := h.statements.GetByID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, payout.ErrStatementNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Bank statement not found"})
+			return
+		}
+		log.Error().Err(err).Uint("statement_id", id).Msg("Failed to fetch bank statement")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch bank statement"})
+		return
+	}
+
+	c.JSON(http.StatusOK, NewPayoutStatementResponse(statement))
+}
+
+// ListReviewQueue lists statement lines that matched no payout, oldest
+// first
+func (h *PayoutStatementHandler) ListReviewQueue(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	lines, total, err := h.statements.ListForReview(c.Request.Context(), page, limit)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to fetch statement review queue")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch statement review queue"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  NewPayoutStatementLineResponses(lines),
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// ResolveLineRequest is the request to match a statement line to a payout
+type ResolveLineRequest struct {
+	PayoutID uint `json:"payout_id" binding:"required"`
+}
+
+// ResolveLine matches a statement line in the review queue to the
+// processing payout it paid, completing the payout
+func (h *PayoutStatementHandler) ResolveLine(c *gin.Context) {
+	line, ok := h.loadLine(c)
+	if !ok {
+		return
+	}
+
+	var req ResolveLineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.Resolve(c.Request.Context(), line, req.PayoutID, actorFromContext(c)); err != nil {
+		switch {
+		case errors.Is(err, payout.ErrPayoutNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Payout not found"})
+		case errors.Is(err, payout.ErrLineNotInReview),
+			errors.Is(err, shared.ErrInvalidPayoutTransition),
+			errors.Is(err, repository.ErrConflict):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, payout.ErrStatementMismatch):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		default:
+			log.Error().Err(err).Uint("line_id", line.ID()).Msg("Failed to resolve statement line")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve statement line"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, NewPayoutStatementLineResponse(line))
+}
+
+// DismissLineRequest is the request to dismiss a statement line
+type DismissLineRequest struct {
+	Note string `json:"note" binding:"required"`
+}
+
+// DismissLine removes a statement line from the review queue without
+// matching it
+func (h *PayoutStatementHandler) DismissLine(c *gin.Context) {
+	line, ok := h.loadLine(c)
+	if !ok {
+		return
+	}
+
+	var req DismissLineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.Dismiss(c.Request.Context(), line, req.Note, actorFromContext(c)); err != nil {
+		switch {
+		case errors.Is(err, payout.ErrLineNotInReview):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, payout.ErrReasonRequired):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			log.Error().Err(err).Uint("line_id", line.ID()).Msg("Failed to dismiss statement line")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to dismiss statement line"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, NewPayoutStatementLineResponse(line))
+}
+
+func (h *PayoutStatementHandler) loadLine(c *gin.Context) (*payout.StatementLine, bool) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid statement line ID"})
+		return nil, false
+	}
+
+	line, err := h.statements.GetLine(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, payout.ErrStatementLineNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Statement line not found"})
+			return nil, false
+		}
+		log.Error().Err(err).Uint("line_id", id).Msg("Failed to fetch statement line")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch statement line"})
+		return nil, false
+	}
+	return line, true
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	services "github.com/Ecom-micro-template/service-agent/internal/application"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/agent"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/commission"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/team"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// RateHandler handles the effective-dated base rates of agents and the
+// override rates of teams
+type RateHandler struct {
+	rateChanges repository.RateChangeReader
+	rates       *services.RateService
+}
+
+// NewRateHandler creates a new rate handler
+func NewRateHandler(rateChanges repository.RateChangeReader, rates *services.RateService) *RateHandler {
+	return &RateHandler{
+		rateChanges: rateChanges,
+		rates:       rates,
+	}
+}
+
+// ScheduleRateRequest is the request to change a rate
+type ScheduleRateRequest struct {
+	CommissionRate float64 `json:"commission_rate" binding:"min=0,max=100"`
+	// EffectiveFrom is when the rate takes effect; omitted means now
+	EffectiveFrom *time.Time `json:"effective_from"`
+}
+
+// GetAgentRates lists an agent's base rate changes, scheduled ones included
+func (h *RateHandler) GetAgentRates(c *gin.Context) {
+	h.listRates(c, commission.RateScopeAgent, "Invalid agent ID")
+}
+
+// ScheduleAgentRate changes an agent's base rate now or from a future date
+func (h *RateHandler) ScheduleAgentRate(c *gin.Context) {
+	h.scheduleRate(c, commission.RateScopeAgent, "Invalid agent ID")
+}
+
+// GetTeamRates lists a team's override rate changes, scheduled ones included
+func (h *RateHandler) GetTeamRates(c *gin.Context) {
+	h.listRates(c, commission.RateScopeTeam, "Invalid team ID")
+}
+
+// ScheduleTeamRate changes a team's override rate now or from a future date
+func (h *RateHandler) ScheduleTeamRate(c *gin.Context) {
+	h.scheduleRate(c, commission.RateScopeTeam, "Invalid team ID")
+}
+
+// CancelRateChange cancels a rate change that has not taken effect yet
+func (h *RateHandler) CancelRateChange(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rate change ID"})
+		return
+	}
+
+	if err := h.rates.Cancel(c.Request.Context(), id); err != nil {
+		respondRateError(c, err, "Failed to cancel rate change")
+		return
+	}
+
+	log.Info().Uint("rate_change_id", id).Msg("Rate change cancelled")
+	c.JSON(http.StatusOK, gin.H{"message": "Rate change cancelled successfully"})
+}
+
+func (h *RateHandler) listRates(c *gin.Context, scope commission.RateScope, invalidID string) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": invalidID})
+		return
+	}
+
+	changes, err := h.rateChanges.GetBySubject(c.Request.Context(), scope, id)
+	if err != nil {
+		log.Error().Err(err).Str("scope", string(scope)).Uint("subject_id", id).Msg("Failed to fetch rate changes")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch rate changes"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  NewRateChangeResponses(changes, time.Now()),
+		"total": len(changes),
+	})
+}
+
+func (h *RateHandler) scheduleRate(c *gin.Context, scope commission.RateScope, invalidID string) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": invalidID})
+		return
+	}
+
+	var req ScheduleRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var effectiveFrom time.Time
+	if req.EffectiveFrom != nil {
+		effectiveFrom = *req.EffectiveFrom
+	}
+
+	change, err := h.rates.Schedule(c.Request.Context(), scope, id, req.CommissionRate, effectiveFrom)
+	if err != nil {
+		respondRateError(c, err, "Failed to schedule rate change")
+		return
+	}
+
+	log.Info().
+		Str("scope", string(scope)).
+		Uint("subject_id", id).
+		Float64("rate", change.Rate()).
+		Time("effective_from", change.EffectiveFrom()).
+		Msg("Rate change scheduled")
+	c.JSON(http.StatusCreated, NewRateChangeResponse(change, time.Now()))
+}
+
+// respondRateError maps rate change errors to HTTP responses
+func respondRateError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, agent.ErrAgentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Agent not found"})
+	case errors.Is(err, team.ErrTeamNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
+	case errors.Is(err, commission.ErrRateChangeNotFound), errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Rate change not found"})
+	case errors.Is(err, commission.ErrRateChangeInPast), errors.Is(err, shared.ErrInvalidCommissionRate):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, commission.ErrRateChangeInEffect):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Error().Err(err).Msg(message)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
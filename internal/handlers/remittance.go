@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	services "github.com/Ecom-micro-template/service-agent/internal/application"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/payout"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// RemittanceHandler handles the download of payout remittance advices:
+// the statement of each payout agents are given as proof of payment
+type RemittanceHandler struct {
+	payouts repository.PayoutReader
+	runs    repository.PayoutRunReader
+	service *services.RemittanceService
+}
+
+// NewRemittanceHandler creates a new remittance handler
+func NewRemittanceHandler(payouts repository.PayoutReader, runs repository.PayoutRunReader, service *services.RemittanceService) *RemittanceHandler {
+	return &RemittanceHandler{
+		payouts: payouts,
+		runs:    runs,
+		service: service,
+	}
+}
+
+// GetPayoutStatement downloads a payout's remittance advice as a PDF, or
+// as CSV with ?format=csv
+func (h *RemittanceHandler) GetPayoutStatement(c *gin.Context) {
+	p, ok := h.loadPayout(c)
+	if !ok {
+		return
+	}
+	h.sendAdvice(c, p)
+}
+
+// GetMyPayoutStatement downloads the remittance advice of one of the
+// authenticated agent's payouts as a PDF, or as CSV with ?format=csv
+func (h *RemittanceHandler) GetMyPayoutStatement(c *gin.Context) {
+	agentID, err := GetAgentFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	p, ok := h.loadPayout(c)
+	if !ok {
+		return
+	}
+	if p.AgentID() != agentID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Payout not found"})
+		return
+	}
+	h.sendAdvice(c, p)
+}
+
+// GetRunStatements downloads the remittance advices of every payout in a
+// run as a zip archive of PDFs, or of CSVs with ?format=csv
+func (h *RemittanceHandler) GetRunStatements(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payout run ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	run, err := h.runs.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, payout.ErrRunNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Payout run not found"})
+			return
+		}
+		log.Error().Err(err).Uint("run_id", id).Msg("Failed to fetch payout run")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch payout run"})
+		return
+	}
+
+	content, err := h.service.RunArchive(ctx, run, c.DefaultQuery("format", services.RemittanceFormatPDF))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnknownRemittanceFormat):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, payout.ErrRunNotCommitted):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			log.Error().Err(err).Uint("run_id", id).Msg("Failed to build remittance advices")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build remittance advices"})
+		}
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("payout-run-%d-%s.zip", run.ID(), run.Period())))
+	c.Data(http.StatusOK, "application/zip", content)
+}
+
+// sendAdvice responds with a payout's remittance advice in the format
+// asked for
+func (h *RemittanceHandler) sendAdvice(c *gin.Context, p *payout.Payout) {
+	advice, err := h.service.Advice(c.Request.Context(), p)
+	if err != nil {
+		log.Error().Err(err).Uint("payout_id", p.ID()).Msg("Failed to build remittance advice")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build remittance advice"})
+		return
+	}
+
+	format := c.DefaultQuery("format", services.RemittanceFormatPDF)
+	name, content, err := h.service.Document(advice, format)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownRemittanceFormat) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		log.Error().Err(err).Uint("payout_id", p.ID()).Msg("Failed to write remittance advice")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to write remittance advice"})
+		return
+	}
+
+	if format == services.RemittanceFormatCSV {
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+		c.Data(http.StatusOK, "text/csv", content)
+		return
+	}
+	sendPDF(c, name, content)
+}
+
+func (h *RemittanceHandler) loadPayout(c *gin.Context) (*payout.Payout, bool) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payout ID"})
+		return nil, false
+	}
+
+	p, err := h.payouts.GetByID(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, payout.ErrPayoutNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Payout not found"})
+			return nil, false
+		}
+		log.Error().Err(err).Uint("payout_id", id).Msg("Failed to fetch payout")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch payout"})
+		return nil, false
+	}
+	return p, true
+}
@@ -0,0 +1,1134 @@
+package handlers
+
+import (
+	"encoding/json"
+	"time"
+
+	services "github.com/Ecom-micro-template/service-agent/internal/application"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/agent"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/commission"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/dispute"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/fx"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/ledger"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/payout"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/tax"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/team"
+)
+
+// AgentResponse is the JSON representation of an agent
+type AgentResponse struct {
+	ID             uint                 `json:"id"`
+	Code           string               `json:"code"`
+	Name           string               `json:"name"`
+	Email          string               `json:"email"`
+	Phone          string               `json:"phone"`
+	CommissionRate float64              `json:"commission_rate"`
+	Tier           string               `json:"tier"`
+	Status         string               `json:"status"`
+	TotalEarned    float64              `json:"total_earned"` // In the payout currency, from the ledger
+	PayoutCurrency string               `json:"payout_currency"`
+	TeamID         *uint                `json:"team_id,omitempty"`
+	ParentID       *uint                `json:"parent_id,omitempty"`
+	CreatedAt      time.Time            `json:"created_at"`
+	UpdatedAt      time.Time            `json:"updated_at"`
+	Commissions    []CommissionResponse `json:"commissions,omitempty"`
+	Payouts        []PayoutResponse     `json:"payouts,omitempty"`
+	Team           *TeamResponse        `json:"team,omitempty"`
+}
+
+// NewAgentResponse builds the response for an agent
+func NewAgentResponse(a *agent.Agent) AgentResponse {
+	return AgentResponse{
+		ID:             a.ID(),
+		Code:           a.Code(),
+		Name:           a.Name(),
+		Email:          a.Email(),
+		Phone:          a.Phone(),
+		CommissionRate: a.CommissionRate().Value(),
+		Tier:           a.Tier().String(),
+		Status:         a.Status().String(),
+		TotalEarned:    a.TotalEarned().Float64(),
+		PayoutCurrency: a.PayoutCurrency(),
+		TeamID:         a.TeamID(),
+		ParentID:       a.ParentID(),
+		CreatedAt:      a.CreatedAt(),
+		UpdatedAt:      a.UpdatedAt(),
+	}
+}
+
+// NewAgentResponses builds the responses for a list of agents
+func NewAgentResponses(agents []*agent.Agent) []AgentResponse {
+	responses := make([]AgentResponse, len(agents))
+	for i, a := range agents {
+		responses[i] = NewAgentResponse(a)
+	}
+	return responses
+}
+
+// TierEvaluationResponse is the JSON representation of a tier evaluation
+type TierEvaluationResponse struct {
+	ID              uint      `json:"id"`
+	AgentID         uint      `json:"agent_id"`
+	PreviousTier    string    `json:"previous_tier"`
+	NewTier         string    `json:"new_tier"`
+	Outcome         string    `json:"outcome"`
+	Sales           float64   `json:"sales"`
+	Orders          int64     `json:"orders"`
+	ActiveCustomers int64     `json:"active_customers"`
+	PeriodFrom      time.Time `json:"period_from"`
+	PeriodTo        time.Time `json:"period_to"`
+	Reasons         []string  `json:"reasons"`
+	EvaluatedAt     time.Time `json:"evaluated_at"`
+}
+
+// NewTierEvaluationResponse builds the response for a tier evaluation
+func NewTierEvaluationResponse(e *agent.TierEvaluation) TierEvaluationResponse {
+	metrics := e.Metrics()
+	return TierEvaluationResponse{
+		ID:              e.ID(),
+		AgentID:         e.AgentID(),
+		PreviousTier:    e.PreviousTier().String(),
+		NewTier:         e.NewTier().String(),
+		Outcome:         e.Outcome(),
+		Sales:           metrics.Sales,
+		Orders:          metrics.Orders,
+		ActiveCustomers: metrics.ActiveCustomers,
+		PeriodFrom:      e.PeriodFrom(),
+		PeriodTo:        e.PeriodTo(),
+		Reasons:         e.Reasons(),
+		EvaluatedAt:     e.EvaluatedAt(),
+	}
+}
+
+// NewTierEvaluationResponses builds the responses for a list of tier evaluations
+func NewTierEvaluationResponses(evaluations []*agent.TierEvaluation) []TierEvaluationResponse {
+	responses := make([]TierEvaluationResponse, len(evaluations))
+	for i, e := range evaluations {
+		responses[i] = NewTierEvaluationResponse(e)
+	}
+	return responses
+}
+
+// RateChangeResponse is the JSON representation of an agent or team rate change
+type RateChangeResponse struct {
+	ID            uint      `json:"id"`
+	Scope         string    `json:"scope"`
+	SubjectID     uint      `json:"subject_id"`
+	Rate          float64   `json:"commission_rate"`
+	EffectiveFrom time.Time `json:"effective_from"`
+	Scheduled     bool      `json:"scheduled"` // Not in effect yet
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// NewRateChangeResponse builds the response for a rate change
+func NewRateChangeResponse(c *commission.RateChange, now time.Time) RateChangeResponse {
+	return RateChangeResponse{
+		ID:            c.ID(),
+		Scope:         string(c.Scope()),
+		SubjectID:     c.SubjectID(),
+		Rate:          c.Rate(),
+		EffectiveFrom: c.EffectiveFrom(),
+		Scheduled:     c.IsScheduled(now),
+		CreatedAt:     c.CreatedAt(),
+	}
+}
+
+// NewRateChangeResponses builds the responses for a list of rate changes
+func NewRateChangeResponses(changes []*commission.RateChange, now time.Time) []RateChangeResponse {
+	responses := make([]RateChangeResponse, len(changes))
+	for i, c := range changes {
+		responses[i] = NewRateChangeResponse(c, now)
+	}
+	return responses
+}
+
+// FXRateResponse is the JSON representation of an exchange rate
+type FXRateResponse struct {
+	ID            uint      `json:"id"`
+	BaseCurrency  string    `json:"base_currency"`
+	QuoteCurrency string    `json:"quote_currency"`
+	Rate          string    `json:"rate"`
+	EffectiveFrom time.Time `json:"effective_from"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// NewFXRateResponse builds the response for an exchange rate
+func NewFXRateResponse(r *fx.Rate) FXRateResponse {
+	return FXRateResponse{
+		ID:            r.ID(),
+		BaseCurrency:  r.BaseCurrency(),
+		QuoteCurrency: r.QuoteCurrency(),
+		Rate:          r.ExchangeRate().String(),
+		EffectiveFrom: r.EffectiveFrom(),
+		CreatedAt:     r.CreatedAt(),
+	}
+}
+
+// NewFXRateResponses builds the responses for a list of exchange rates
+func NewFXRateResponses(rates []*fx.Rate) []FXRateResponse {
+	responses := make([]FXRateResponse, len(rates))
+	for i, r := range rates {
+		responses[i] = NewFXRateResponse(r)
+	}
+	return responses
+}
+
+// HierarchyNodeResponse is the JSON representation of an agent and its downline
+type HierarchyNodeResponse struct {
+	AgentResponse
+	Level    int                     `json:"level"`
+	Children []HierarchyNodeResponse `json:"children"`
+}
+
+// NewHierarchyNodeResponse builds the response for a downline tree
+func NewHierarchyNodeResponse(n *services.HierarchyNode) HierarchyNodeResponse {
+	children := make([]HierarchyNodeResponse, len(n.Children))
+	for i, child := range n.Children {
+		children[i] = NewHierarchyNodeResponse(child)
+	}
+	return HierarchyNodeResponse{
+		AgentResponse: NewAgentResponse(n.Agent),
+		Level:         n.Level,
+		Children:      children,
+	}
+}
+
+// CommissionResponse is the JSON representation of a commission
+type CommissionResponse struct {
+	ID         uint           `json:"id"`
+	AgentID    uint           `json:"agent_id"`
+	OrderID    string         `json:"order_id"`
+	OrderTotal float64        `json:"order_total"`
+	Rate       float64        `json:"rate"`
+	Amount     float64        `json:"amount"`
+	Reversed   float64        `json:"reversed_amount"`
+	NetAmount  float64        `json:"net_amount"`
+	Status     string         `json:"status"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	Agent      *AgentResponse `json:"agent,omitempty"`
+
+	// Why and by whom a rejected or cancelled commission was closed
+	StatusReason    string `json:"status_reason,omitempty"`
+	StatusChangedBy string `json:"status_changed_by,omitempty"`
+
+	// Amounts above are in the order currency. The payout amount is the
+	// amount converted to the agent's payout currency at the FX rate.
+	Currency        string  `json:"currency"`
+	PayoutAmount    float64 `json:"payout_amount"`
+	NetPayoutAmount float64 `json:"net_payout_amount"`
+	PayoutCurrency  string  `json:"payout_currency"`
+	FXRate          string  `json:"fx_rate"`
+
+	// Co-sold orders: the agent's share of the full order total
+	SplitGroupID string  `json:"split_group_id,omitempty"`
+	SplitShare   float64 `json:"split_share"`
+	ShareOfOrder float64 `json:"share_of_order"`
+
+	// Team overrides and downline commissions link the seller's sale
+	// commission; level is a downline commission's distance from the seller
+	Type               string `json:"type"`
+	SourceCommissionID *uint  `json:"source_commission_id,omitempty"`
+	Level              int    `json:"level,omitempty"`
+
+	// Manual adjustments: the amount is signed and the order is optional
+	Category  string `json:"category,omitempty"`
+	Note      string `json:"note,omitempty"`
+	CreatedBy string `json:"created_by,omitempty"`
+}
+
+// NewCommissionResponse builds the response for a commission
+func NewCommissionResponse(c *commission.Commission) CommissionResponse {
+	return CommissionResponse{
+		ID:         c.ID(),
+		AgentID:    c.AgentID(),
+		OrderID:    c.OrderID(),
+		OrderTotal: c.OrderTotal().Float64(),
+		Rate:       c.Rate().Value(),
+		Amount:     c.Amount().Float64(),
+		Reversed:   c.ReversedAmount().Float64(),
+		NetAmount:  c.NetAmount().Float64(),
+		Status:     c.Status().String(),
+		CreatedAt:  c.CreatedAt(),
+		UpdatedAt:  c.UpdatedAt(),
+
+		StatusReason:    c.StatusReason(),
+		StatusChangedBy: c.StatusChangedBy(),
+
+		Currency:        c.Currency(),
+		PayoutAmount:    c.PayoutAmount().Float64(),
+		NetPayoutAmount: c.NetPayoutAmount().Float64(),
+		PayoutCurrency:  c.PayoutCurrency(),
+		FXRate:          c.FXRate().String(),
+
+		SplitGroupID: c.SplitGroupID(),
+		SplitShare:   c.SplitShare(),
+		ShareOfOrder: c.ShareOfOrder().Float64(),
+
+		Type:               c.Type().String(),
+		SourceCommissionID: c.SourceCommissionID(),
+		Level:              c.Level(),
+
+		Category:  c.Category().String(),
+		Note:      c.Note(),
+		CreatedBy: c.CreatedBy(),
+	}
+}
+
+// NewCommissionResponses builds the responses for a list of commissions
+func NewCommissionResponses(commissions []*commission.Commission) []CommissionResponse {
+	responses := make([]CommissionResponse, len(commissions))
+	for i, c := range commissions {
+		responses[i] = NewCommissionResponse(c)
+	}
+	return responses
+}
+
+// AutoApprovalResponse is the JSON representation of an auto-approval
+type AutoApprovalResponse struct {
+	ID           uint      `json:"id"`
+	CommissionID uint      `json:"commission_id"`
+	AgentID      uint      `json:"agent_id"`
+	Rule         string    `json:"rule"`
+	Amount       float64   `json:"amount"`
+	Currency     string    `json:"currency"`
+	Reasons      []string  `json:"reasons"`
+	ApprovedAt   time.Time `json:"approved_at"`
+}
+
+// NewAutoApprovalResponse builds the response for an auto-approval
+func NewAutoApprovalResponse(a *commission.AutoApproval) AutoApprovalResponse {
+	return AutoApprovalResponse{
+		ID:           a.ID(),
+		CommissionID: a.CommissionID(),
+		AgentID:      a.AgentID(),
+		Rule:         a.Rule(),
+		Amount:       a.Amount().Float64(),
+		Currency:     a.Amount().Currency(),
+		Reasons:      a.Reasons(),
+		ApprovedAt:   a.ApprovedAt(),
+	}
+}
+
+// NewAutoApprovalResponses builds the responses for a list of auto-approvals
+func NewAutoApprovalResponses(approvals []*commission.AutoApproval) []AutoApprovalResponse {
+	responses := make([]AutoApprovalResponse, len(approvals))
+	for i, a := range approvals {
+		responses[i] = NewAutoApprovalResponse(a)
+	}
+	return responses
+}
+
+// DisputeResponse is the JSON representation of a dispute and its thread
+type DisputeResponse struct {
+	ID                     uint                     `json:"id"`
+	AgentID                uint                     `json:"agent_id"`
+	OrderID                string                   `json:"order_id"`
+	CommissionID           *uint                    `json:"commission_id,omitempty"`
+	Status                 string                   `json:"status"`
+	Resolution             string                   `json:"resolution,omitempty"`
+	ResolvedBy             string                   `json:"resolved_by,omitempty"`
+	ResolvedAt             *time.Time               `json:"resolved_at,omitempty"`
+	AdjustmentCommissionID *uint                    `json:"adjustment_commission_id,omitempty"`
+	CreatedAt              time.Time                `json:"created_at"`
+	UpdatedAt              time.Time                `json:"updated_at"`
+	Messages               []DisputeMessageResponse `json:"messages"`
+}
+
+// DisputeMessageResponse is the JSON representation of a message in a
+// dispute thread
+type DisputeMessageResponse struct {
+	ID        uint      `json:"id"`
+	Author    string    `json:"author"`
+	AuthorID  string    `json:"author_id,omitempty"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewDisputeResponse builds the response for a dispute
+func NewDisputeResponse(d *dispute.Dispute) DisputeResponse {
+	messages := make([]DisputeMessageResponse, len(d.Messages()))
+	for i, m := range d.Messages() {
+		messages[i] = DisputeMessageResponse{
+			ID:        m.ID(),
+			Author:    m.Author(),
+			AuthorID:  m.AuthorID(),
+			Body:      m.Body(),
+			CreatedAt: m.CreatedAt(),
+		}
+	}
+
+	return DisputeResponse{
+		ID:                     d.ID(),
+		AgentID:                d.AgentID(),
+		OrderID:                d.OrderID(),
+		CommissionID:           d.CommissionID(),
+		Status:                 d.Status().String(),
+		Resolution:             d.Resolution(),
+		ResolvedBy:             d.ResolvedBy(),
+		ResolvedAt:             d.ResolvedAt(),
+		AdjustmentCommissionID: d.AdjustmentCommissionID(),
+		CreatedAt:              d.CreatedAt(),
+		UpdatedAt:              d.UpdatedAt(),
+		Messages:               messages,
+	}
+}
+
+// NewDisputeResponses builds the responses for a list of disputes
+func NewDisputeResponses(disputes []*dispute.Dispute) []DisputeResponse {
+	responses := make([]DisputeResponse, len(disputes))
+	for i, d := range disputes {
+		responses[i] = NewDisputeResponse(d)
+	}
+	return responses
+}
+
+// PayoutResponse is the JSON representation of a payout
+type PayoutResponse struct {
+	ID              uint           `json:"id"`
+	AgentID         uint           `json:"agent_id"`
+	Amount          float64        `json:"amount"`
+	GrossAmount     float64        `json:"gross_amount"`
+	Deductions      float64        `json:"deductions"` // Clawbacks netted against the payout
+	Withheld        float64        `json:"withheld"`   // Tax withheld from the payout
+	WithholdingRate float64        `json:"withholding_rate"`
+	Reserved        float64        `json:"reserved"` // Kept back in reserve from the payout
+	ReservePercent  float64        `json:"reserve_percent"`
+	Released        float64        `json:"released"` // Earlier reserves the payout releases
+	Currency        string         `json:"currency"`
+	Period          string         `json:"period"`
+	CommissionIDs   string         `json:"commission_ids"` // JSON array of commission IDs
+	Status          string         `json:"status"`
+	TransactionRef  string         `json:"transaction_ref,omitempty"`
+	FailureReason   string         `json:"failure_reason,omitempty"`
+	Retries         int            `json:"retries"`
+	PaidAt          *time.Time     `json:"paid_at,omitempty"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	Agent           *AgentResponse `json:"agent,omitempty"`
+}
+
+// NewPayoutResponse builds the response for a payout
+func NewPayoutResponse(p *payout.Payout) PayoutResponse {
+	commissionIDs, _ := json.Marshal(p.CommissionIDs())
+	return PayoutResponse{
+		ID:              p.ID(),
+		AgentID:         p.AgentID(),
+		Amount:          p.Amount().Float64(),
+		GrossAmount:     p.GrossAmount().Float64(),
+		Deductions:      p.Deductions().Float64(),
+		Withheld:        p.Withheld().Float64(),
+		WithholdingRate: p.WithholdingRate(),
+		Reserved:        p.Reserved().Float64(),
+		ReservePercent:  p.ReservePercent(),
+		Released:        p.Released().Float64(),
+		Currency:        p.Currency(),
+		Period:          p.Period(),
+		CommissionIDs:   string(commissionIDs),
+		Status:          p.Status().String(),
+		TransactionRef:  p.TransactionRef(),
+		FailureReason:   p.FailureReason(),
+		Retries:         p.Retries(),
+		PaidAt:          p.PaidAt(),
+		CreatedAt:       p.CreatedAt(),
+		UpdatedAt:       p.UpdatedAt(),
+	}
+}
+
+// NewPayoutResponses builds the responses for a list of payouts
+func NewPayoutResponses(payouts []*payout.Payout) []PayoutResponse {
+	responses := make([]PayoutResponse, len(payouts))
+	for i, p := range payouts {
+		responses[i] = NewPayoutResponse(p)
+	}
+	return responses
+}
+
+// PayoutRunResponse is the JSON representation of a payout run
+type PayoutRunResponse struct {
+	ID             uint                    `json:"id,omitempty"` // Zero for a preview
+	Period         string                  `json:"period"`
+	Status         string                  `json:"status"`
+	Minimum        float64                 `json:"minimum"`
+	Currency       string                  `json:"currency"` // Currency of the minimum
+	Paid           int                     `json:"paid"`
+	CarriedForward int                     `json:"carried_forward"`
+	Totals         map[string]float64      `json:"totals"` // Amount paid by currency
+	Lines          []PayoutRunLineResponse `json:"lines"`
+	CreatedBy      string                  `json:"created_by,omitempty"`
+	CancelledBy    string                  `json:"cancelled_by,omitempty"`
+	CancelledAt    *time.Time              `json:"cancelled_at,omitempty"`
+	CreatedAt      time.Time               `json:"created_at"`
+	UpdatedAt      time.Time               `json:"updated_at"`
+}
+
+// PayoutRunLineResponse is the JSON representation of an agent's outcome
+// in a payout run
+type PayoutRunLineResponse struct {
+	AgentID     uint    `json:"agent_id"`
+	Outcome     string  `json:"outcome"`
+	Amount      float64 `json:"amount"`
+	Deductions  float64 `json:"deductions"`
+	Currency    string  `json:"currency"`
+	Commissions int     `json:"commissions"`
+	Reason      string  `json:"reason,omitempty"`
+	PayoutID    *uint   `json:"payout_id,omitempty"`
+}
+
+// NewPayoutRunResponse builds the response for a payout run
+func NewPayoutRunResponse(r *payout.Run) PayoutRunResponse {
+	lines := make([]PayoutRunLineResponse, len(r.Lines()))
+	paid := make(map[string]shared.Money)
+	for i, line := range r.Lines() {
+		lines[i] = PayoutRunLineResponse{
+			AgentID:     line.AgentID(),
+			Outcome:     line.Outcome(),
+			Amount:      line.Amount().Float64(),
+			Deductions:  line.Deductions().Float64(),
+			Currency:    line.Amount().Currency(),
+			Commissions: line.Commissions(),
+			Reason:      line.Reason(),
+			PayoutID:    line.PayoutID(),
+		}
+		if line.IsPaid() {
+			currency := line.Amount().Currency()
+			total, ok := paid[currency]
+			if !ok {
+				total = shared.ZeroMoney(currency)
+			}
+			paid[currency] = total.Add(line.Amount())
+		}
+	}
+	totals := make(map[string]float64, len(paid))
+	for currency, total := range paid {
+		totals[currency] = total.Float64()
+	}
+
+	return PayoutRunResponse{
+		ID:             r.ID(),
+		Period:         r.Period(),
+		Status:         r.Status().String(),
+		Minimum:        r.Minimum().Float64(),
+		Currency:       r.Minimum().Currency(),
+		Paid:           r.PayoutCount(),
+		CarriedForward: len(r.Lines()) - r.PayoutCount(),
+		Totals:         totals,
+		Lines:          lines,
+		CreatedBy:      r.CreatedBy(),
+		CancelledBy:    r.CancelledBy(),
+		CancelledAt:    r.CancelledAt(),
+		CreatedAt:      r.CreatedAt(),
+		UpdatedAt:      r.UpdatedAt(),
+	}
+}
+
+// NewPayoutRunResponses builds the responses for a list of payout runs
+func NewPayoutRunResponses(runs []*payout.Run) []PayoutRunResponse {
+	responses := make([]PayoutRunResponse, len(runs))
+	for i, r := range runs {
+		responses[i] = NewPayoutRunResponse(r)
+	}
+	return responses
+}
+
+// PayoutExportResponse is the JSON representation of a payment file
+// exported for a payout run, without the file itself
+type PayoutExportResponse struct {
+	ID         uint      `json:"id"`
+	RunID      uint      `json:"run_id"`
+	Format     string    `json:"format"`
+	FileName   string    `json:"file_name"`
+	Checksum   string    `json:"checksum"`
+	Count      int       `json:"count"`
+	ExportedBy string    `json:"exported_by,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// NewPayoutExportResponse builds the response for a payment file export
+func NewPayoutExportResponse(e *payout.Export) PayoutExportResponse {
+	return PayoutExportResponse{
+		ID:         e.ID(),
+		RunID:      e.RunID(),
+		Format:     e.Format(),
+		FileName:   e.FileName(),
+		Checksum:   e.Checksum(),
+		Count:      e.Count(),
+		ExportedBy: e.ExportedBy(),
+		CreatedAt:  e.CreatedAt(),
+	}
+}
+
+// NewPayoutExportResponses converts payment file exports to responses
+func NewPayoutExportResponses(exports []*payout.Export) []PayoutExportResponse {
+	responses := make([]PayoutExportResponse, len(exports))
+	for i, e := range exports {
+		responses[i] = NewPayoutExportResponse(e)
+	}
+	return responses
+}
+
+// PayoutStatementResponse is the JSON representation of an imported bank
+// statement
+type PayoutStatementResponse struct {
+	ID         uint                          `json:"id"`
+	Format     string                        `json:"format"`
+	FileName   string                        `json:"file_name,omitempty"`
+	Checksum   string                        `json:"checksum"`
+	Completed  int                           `json:"completed"`
+	Failed     int                           `json:"failed"`
+	Duplicate  int                           `json:"duplicate"`
+	Unmatched  int                           `json:"unmatched"`
+	Lines      []PayoutStatementLineResponse `json:"lines"`
+	ImportedBy string                        `json:"imported_by,omitempty"`
+	CreatedAt  time.Time                     `json:"created_at"`
+}
+
+// PayoutStatementLineResponse is the JSON representation of a bank
+// statement line and what it was matched to
+type PayoutStatementLineResponse struct {
+	ID             uint       `json:"id"`
+	StatementID    uint       `json:"statement_id"`
+	Reference      string     `json:"reference"`
+	TransactionRef string     `json:"transaction_ref"`
+	Amount         float64    `json:"amount"`
+	Currency       string     `json:"currency"`
+	BookedAt       *time.Time `json:"booked_at,omitempty"`
+	Outcome        string     `json:"outcome"`
+	Detail         string     `json:"detail,omitempty"`
+	PayoutID       *uint      `json:"payout_id,omitempty"`
+	ReviewedBy     string     `json:"reviewed_by,omitempty"`
+	ReviewedAt     *time.Time `json:"reviewed_at,omitempty"`
+}
+
+// NewPayoutStatementResponse builds the response for a bank statement
+func NewPayoutStatementResponse(s *payout.Statement) PayoutStatementResponse {
+	return PayoutStatementResponse{
+		ID:         s.ID(),
+		Format:     s.Format(),
+		FileName:   s.FileName(),
+		Checksum:   s.Checksum(),
+		Completed:  s.Count(payout.StatementLineCompleted),
+		Failed:     s.Count(payout.StatementLineFailed),
+		Duplicate:  s.Count(payout.StatementLineDuplicate),
+		Unmatched:  s.Count(payout.StatementLineUnmatched),
+		Lines:      NewPayoutStatementLineResponses(s.Lines()),
+		ImportedBy: s.ImportedBy(),
+		CreatedAt:  s.CreatedAt(),
+	}
+}
+
+// NewPayoutStatementResponses builds the responses for a list of bank
+// statements
+func NewPayoutStatementResponses(statements []*payout.Statement) []PayoutStatementResponse {
+	responses := make([]PayoutStatementResponse, len(statements))
+	for i, s := range statements {
+		responses[i] = NewPayoutStatementResponse(s)
+	}
+	return responses
+}
+
+// NewPayoutStatementLineResponse builds the response for a statement line
+func NewPayoutStatementLineResponse(l *payout.StatementLine) PayoutStatementLineResponse {
+	return PayoutStatementLineResponse{
+		ID:             l.ID(),
+		StatementID:    l.StatementID(),
+		Reference:      l.Reference(),
+		TransactionRef: l.TransactionRef(),
+		Amount:         l.Amount().Float64(),
+		Currency:       l.Amount().Currency(),
+		BookedAt:       l.BookedAt(),
+		Outcome:        l.Outcome(),
+		Detail:         l.Detail(),
+		PayoutID:       l.PayoutID(),
+		ReviewedBy:     l.ReviewedBy(),
+		ReviewedAt:     l.ReviewedAt(),
+	}
+}
+
+// NewPayoutStatementLineResponses builds the responses for a list of
+// statement lines
+func NewPayoutStatementLineResponses(lines []*payout.StatementLine) []PayoutStatementLineResponse {
+	responses := make([]PayoutStatementLineResponse, len(lines))
+	for i, l := range lines {
+		responses[i] = NewPayoutStatementLineResponse(l)
+	}
+	return responses
+}
+
+// PayoutMethodResponse is the JSON representation of a payout method.
+// The account number is always masked.
+type PayoutMethodResponse struct {
+	ID            uint       `json:"id"`
+	AgentID       uint       `json:"agent_id"`
+	Type          string     `json:"type"`
+	TypeLabel     string     `json:"type_label"`
+	Provider      string     `json:"provider"`
+	ProviderCode  string     `json:"provider_code,omitempty"`
+	AccountName   string     `json:"account_name"`
+	AccountNumber string     `json:"account_number"`
+	IsDefault     bool       `json:"is_default"`
+	Status        string     `json:"status"`
+	VerifiedAt    *time.Time `json:"verified_at,omitempty"`
+	HeldUntil     *time.Time `json:"held_until,omitempty"` // End of the cooldown after the last change
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// NewPayoutMethodResponse builds the response for a payout method.
+// cooldown is how long payouts are held after a change.
+func NewPayoutMethodResponse(m *payout.Method, cooldown time.Duration) PayoutMethodResponse {
+	resp := PayoutMethodResponse{
+		ID:            m.ID(),
+		AgentID:       m.AgentID(),
+		Type:          m.Type().String(),
+		TypeLabel:     m.Type().Label(),
+		Provider:      m.Provider(),
+		ProviderCode:  m.ProviderCode(),
+		AccountName:   m.AccountName(),
+		AccountNumber: m.MaskedAccountNumber(),
+		IsDefault:     m.IsDefault(),
+		Status:        m.Status().String(),
+		VerifiedAt:    m.VerifiedAt(),
+		CreatedAt:     m.CreatedAt(),
+		UpdatedAt:     m.UpdatedAt(),
+	}
+	if m.InCooldown(cooldown, time.Now()) {
+		heldUntil := m.CooldownUntil(cooldown)
+		resp.HeldUntil = &heldUntil
+	}
+	return resp
+}
+
+// NewPayoutMethodResponses builds the responses for a list of payout
+// methods
+func NewPayoutMethodResponses(methods []*payout.Method, cooldown time.Duration) []PayoutMethodResponse {
+	responses := make([]PayoutMethodResponse, len(methods))
+	for i, m := range methods {
+		responses[i] = NewPayoutMethodResponse(m, cooldown)
+	}
+	return responses
+}
+
+// LedgerEntryResponse is the JSON representation of a ledger entry
+type LedgerEntryResponse struct {
+	ID           uint                 `json:"id"`
+	Kind         string               `json:"kind"`
+	CommissionID *uint                `json:"commission_id,omitempty"`
+	PayoutID     *uint                `json:"payout_id,omitempty"`
+	Currency     string               `json:"currency"`
+	CreatedAt    time.Time            `json:"created_at"`
+	Lines        []LedgerLineResponse `json:"lines"`
+}
+
+// LedgerLineResponse is one side of a ledger entry. Exactly one of debit
+// and credit is set.
+type LedgerLineResponse struct {
+	Account string  `json:"account"`
+	Debit   float64 `json:"debit,omitempty"`
+	Credit  float64 `json:"credit,omitempty"`
+}
+
+// NewLedgerEntryResponse builds the response for a ledger entry
+func NewLedgerEntryResponse(e *ledger.Entry) LedgerEntryResponse {
+	lines := make([]LedgerLineResponse, len(e.Lines()))
+	for i, line := range e.Lines() {
+		lines[i] = LedgerLineResponse{Account: line.Account().String()}
+		if line.IsDebit() {
+			lines[i].Debit = line.Amount().Float64()
+		} else {
+			lines[i].Credit = line.Amount().Neg().Float64()
+		}
+	}
+
+	return LedgerEntryResponse{
+		ID:           e.ID(),
+		Kind:         e.Kind(),
+		CommissionID: e.CommissionID(),
+		PayoutID:     e.PayoutID(),
+		Currency:     e.Currency(),
+		CreatedAt:    e.CreatedAt(),
+		Lines:        lines,
+	}
+}
+
+// NewLedgerEntryResponses builds the responses for a list of ledger entries
+func NewLedgerEntryResponses(entries []*ledger.Entry) []LedgerEntryResponse {
+	responses := make([]LedgerEntryResponse, len(entries))
+	for i, e := range entries {
+		responses[i] = NewLedgerEntryResponse(e)
+	}
+	return responses
+}
+
+// LedgerBalanceResponse is an account balance in one currency, on the
+// account's normal side
+type LedgerBalanceResponse struct {
+	Account  string  `json:"account"`
+	Label    string  `json:"label"`
+	Amount   float64 `json:"amount"`
+	Currency string  `json:"currency"`
+}
+
+// NewLedgerBalanceResponses builds the responses for an agent's balances,
+// leaving out the commission expense the agent's accounts are offset
+// against
+func NewLedgerBalanceResponses(balances ledger.Balances) []LedgerBalanceResponse {
+	responses := make([]LedgerBalanceResponse, 0, len(balances))
+	for _, b := range balances {
+		if b.Account == shared.LedgerCommissionExpense {
+			continue
+		}
+		responses = append(responses, LedgerBalanceResponse{
+			Account:  b.Account.String(),
+			Label:    b.Account.Label(),
+			Amount:   b.Amount.Float64(),
+			Currency: b.Amount.Currency(),
+		})
+	}
+	return responses
+}
+
+// LedgerDiscrepancyResponse is an account whose ledger balance differs
+// from its source records
+type LedgerDiscrepancyResponse struct {
+	AgentID    uint    `json:"agent_id"`
+	Account    string  `json:"account"`
+	Currency   string  `json:"currency"`
+	Balance    float64 `json:"balance"`
+	Expected   float64 `json:"expected"`
+	Difference float64 `json:"difference"`
+}
+
+// ReconciliationResponse is the JSON representation of a ledger
+// reconciliation
+type ReconciliationResponse struct {
+	AgentsChecked     int                         `json:"agents_checked"`
+	Clean             bool                        `json:"clean"`
+	Discrepancies     []LedgerDiscrepancyResponse `json:"discrepancies"`
+	UnbalancedEntries map[uint][]uint             `json:"unbalanced_entries,omitempty"` // Entry IDs by agent
+}
+
+// NewReconciliationResponse builds the response for a ledger
+// reconciliation
+func NewReconciliationResponse(r *services.Reconciliation) ReconciliationResponse {
+	discrepancies := make([]LedgerDiscrepancyResponse, len(r.Discrepancies))
+	for i, d := range r.Discrepancies {
+		discrepancies[i] = LedgerDiscrepancyResponse{
+			AgentID:    d.AgentID,
+			Account:    d.Account.String(),
+			Currency:   d.Balance.Currency(),
+			Balance:    d.Balance.Float64(),
+			Expected:   d.Expected.Float64(),
+			Difference: d.Difference().Float64(),
+		}
+	}
+
+	return ReconciliationResponse{
+		AgentsChecked:     r.AgentsChecked,
+		Clean:             r.IsClean(),
+		Discrepancies:     discrepancies,
+		UnbalancedEntries: r.UnbalancedEntries,
+	}
+}
+
+// TeamResponse is the JSON representation of a team
+type TeamResponse struct {
+	ID             uint            `json:"id"`
+	Code           string          `json:"code"`
+	Name           string          `json:"name"`
+	Description    string          `json:"description"`
+	LeaderID       *uint           `json:"leader_id,omitempty"`
+	TargetMonthly  float64         `json:"target_monthly"`
+	CommissionRate float64         `json:"commission_rate"`
+	IsActive       bool            `json:"is_active"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+	Leader         *AgentResponse  `json:"leader,omitempty"`
+	Members        []AgentResponse `json:"members,omitempty"`
+}
+
+// NewTeamResponse builds the response for a team
+func NewTeamResponse(t *team.Team) TeamResponse {
+	return TeamResponse{
+		ID:             t.ID(),
+		Code:           t.Code(),
+		Name:           t.Name(),
+		Description:    t.Description(),
+		LeaderID:       t.LeaderID(),
+		TargetMonthly:  t.TargetMonthly(),
+		CommissionRate: t.CommissionRate(),
+		IsActive:       t.IsActive(),
+		CreatedAt:      t.CreatedAt(),
+		UpdatedAt:      t.UpdatedAt(),
+	}
+}
+
+// WithholdingRuleResponse is the JSON representation of a withholding rule
+type WithholdingRuleResponse struct {
+	ID           uint      `json:"id"`
+	Name         string    `json:"name"`
+	Residency    string    `json:"residency,omitempty"`      // Empty matches any residency
+	TaxIDPresent *bool     `json:"tax_id_present,omitempty"` // Absent matches with or without a tax ID
+	AppliesTo    string    `json:"applies_to"`
+	Rate         float64   `json:"rate"`
+	Active       bool      `json:"active"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// NewWithholdingRuleResponse builds the response for a withholding rule
+func NewWithholdingRuleResponse(r *tax.Rule) WithholdingRuleResponse {
+	return WithholdingRuleResponse{
+		ID:           r.ID(),
+		Name:         r.Name(),
+		Residency:    r.Residency().String(),
+		TaxIDPresent: r.TaxIDPresent(),
+		AppliesTo:    r.Describe(),
+		Rate:         r.Rate(),
+		Active:       r.IsActive(),
+		CreatedAt:    r.CreatedAt(),
+		UpdatedAt:    r.UpdatedAt(),
+	}
+}
+
+// NewWithholdingRuleResponses builds the responses for a list of
+// withholding rules
+func NewWithholdingRuleResponses(rules []*tax.Rule) []WithholdingRuleResponse {
+	responses := make([]WithholdingRuleResponse, len(rules))
+	for i, r := range rules {
+		responses[i] = NewWithholdingRuleResponse(r)
+	}
+	return responses
+}
+
+// TaxProfileResponse is the JSON representation of an agent's tax profile
+// and the rate withheld from their payouts
+type TaxProfileResponse struct {
+	AgentID          uint      `json:"agent_id"`
+	TaxID            string    `json:"tax_id,omitempty"`
+	Residency        string    `json:"residency"`
+	RateOverride     *float64  `json:"rate_override,omitempty"`
+	WithholdingRate  float64   `json:"withholding_rate"`
+	WithholdingRule  *uint     `json:"withholding_rule_id,omitempty"`
+	WithholdingBasis string    `json:"withholding_basis"`
+	UpdatedBy        string    `json:"updated_by,omitempty"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// NewTaxProfileResponse builds the response for a tax profile
+func NewTaxProfileResponse(p *tax.Profile, w tax.Withholding) TaxProfileResponse {
+	return TaxProfileResponse{
+		AgentID:          p.AgentID(),
+		TaxID:            p.TaxID(),
+		Residency:        p.Residency().String(),
+		RateOverride:     p.RateOverride(),
+		WithholdingRate:  w.Rate,
+		WithholdingRule:  w.RuleID,
+		WithholdingBasis: w.Basis,
+		UpdatedBy:        p.UpdatedBy(),
+		UpdatedAt:        p.UpdatedAt(),
+	}
+}
+
+// TaxAmountsResponse is the JSON representation of payout totals in one
+// currency on a tax statement
+type TaxAmountsResponse struct {
+	Currency string  `json:"currency"`
+	Gross    float64 `json:"gross"`
+	Withheld float64 `json:"withheld"`
+	Net      float64 `json:"net"`
+	Payouts  int     `json:"payouts"`
+}
+
+// TaxStatementMonthResponse is the JSON representation of a month on a tax
+// statement
+type TaxStatementMonthResponse struct {
+	Month string `json:"month"`
+	TaxAmountsResponse
+}
+
+// TaxStatementPayoutResponse is the JSON representation of a payout on a
+// tax statement
+type TaxStatementPayoutResponse struct {
+	PayoutID        uint      `json:"payout_id"`
+	Reference       string    `json:"reference"`
+	Period          string    `json:"period"`
+	PaidAt          time.Time `json:"paid_at"`
+	Currency        string    `json:"currency"`
+	Gross           float64   `json:"gross"`
+	WithholdingRate float64   `json:"withholding_rate"`
+	Withheld        float64   `json:"withheld"`
+	Net             float64   `json:"net"`
+}
+
+// TaxStatementResponse is the JSON representation of an agent's annual
+// statement of commission income
+type TaxStatementResponse struct {
+	AgentID   uint                         `json:"agent_id"`
+	AgentCode string                       `json:"agent_code"`
+	AgentName string                       `json:"agent_name"`
+	Year      int                          `json:"year"`
+	TaxID     string                       `json:"tax_id,omitempty"`
+	Residency string                       `json:"residency"`
+	Totals    []TaxAmountsResponse         `json:"totals"` // By currency
+	Months    []TaxStatementMonthResponse  `json:"months,omitempty"`
+	Payouts   []TaxStatementPayoutResponse `json:"payouts,omitempty"`
+}
+
+// newTaxAmountsResponse builds the response for payout totals
+func newTaxAmountsResponse(a tax.Amounts) TaxAmountsResponse {
+	return TaxAmountsResponse{
+		Currency: a.Currency,
+		Gross:    a.Gross.Float64(),
+		Withheld: a.Withheld.Float64(),
+		Net:      a.Net.Float64(),
+		Payouts:  a.Payouts,
+	}
+}
+
+// NewTaxStatementResponse builds the response for an annual statement.
+// Without detail only the totals are included.
+func NewTaxStatementResponse(st *services.AnnualTaxStatement, detail bool) TaxStatementResponse {
+	response := TaxStatementResponse{
+		AgentID:   st.AgentID,
+		AgentCode: st.Agent.Code(),
+		AgentName: st.Agent.Name(),
+		Year:      st.Year,
+		TaxID:     st.TaxID,
+		Residency: st.Residency.String(),
+		Totals:    make([]TaxAmountsResponse, len(st.Totals)),
+	}
+	for i, t := range st.Totals {
+		response.Totals[i] = newTaxAmountsResponse(t)
+	}
+	if !detail {
+		return response
+	}
+
+	response.Months = make([]TaxStatementMonthResponse, len(st.Months))
+	for i, m := range st.Months {
+		response.Months[i] = TaxStatementMonthResponse{Month: m.Month, TaxAmountsResponse: newTaxAmountsResponse(m.Amounts)}
+	}
+	response.Payouts = make([]TaxStatementPayoutResponse, len(st.Payouts))
+	for i, p := range st.Payouts {
+		response.Payouts[i] = TaxStatementPayoutResponse{
+			PayoutID:        p.PayoutID,
+			Reference:       p.Reference,
+			Period:          p.Period,
+			PaidAt:          p.PaidAt,
+			Currency:        p.Net.Currency(),
+			Gross:           p.Gross.Float64(),
+			WithholdingRate: p.WithholdingRate,
+			Withheld:        p.Withheld.Float64(),
+			Net:             p.Net.Float64(),
+		}
+	}
+	return response
+}
+
+// PayoutHoldResponse is the JSON representation of a payout hold
+type PayoutHoldResponse struct {
+	ID         uint       `json:"id"`
+	AgentID    uint       `json:"agent_id"`
+	Reason     string     `json:"reason"`
+	Status     string     `json:"status"` // active, released or expired
+	ExpiresAt  time.Time  `json:"expires_at"`
+	CreatedBy  string     `json:"created_by,omitempty"`
+	ReleasedBy string     `json:"released_by,omitempty"`
+	ReleasedAt *time.Time `json:"released_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// NewPayoutHoldResponse builds the response for a payout hold
+func NewPayoutHoldResponse(h *payout.Hold) PayoutHoldResponse {
+	return PayoutHoldResponse{
+		ID:         h.ID(),
+		AgentID:    h.AgentID(),
+		Reason:     h.Reason(),
+		Status:     h.Status(time.Now()),
+		ExpiresAt:  h.ExpiresAt(),
+		CreatedBy:  h.CreatedBy(),
+		ReleasedBy: h.ReleasedBy(),
+		ReleasedAt: h.ReleasedAt(),
+		CreatedAt:  h.CreatedAt(),
+		UpdatedAt:  h.UpdatedAt(),
+	}
+}
+
+// NewPayoutHoldResponses builds the responses for a list of payout holds
+func NewPayoutHoldResponses(holds []*payout.Hold) []PayoutHoldResponse {
+	responses := make([]PayoutHoldResponse, len(holds))
+	for i, h := range holds {
+		responses[i] = NewPayoutHoldResponse(h)
+	}
+	return responses
+}
+
+// ReservePolicyResponse is the JSON representation of an agent's reserve
+// policy
+type ReservePolicyResponse struct {
+	AgentID   uint      `json:"agent_id"`
+	Percent   float64   `json:"percent"`
+	Days      int       `json:"days"`
+	Reason    string    `json:"reason"`
+	UpdatedBy string    `json:"updated_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewReservePolicyResponse builds the response for a reserve policy
+func NewReservePolicyResponse(p *payout.ReservePolicy) ReservePolicyResponse {
+	return ReservePolicyResponse{
+		AgentID:   p.AgentID(),
+		Percent:   p.Percent(),
+		Days:      p.Days(),
+		Reason:    p.Reason(),
+		UpdatedBy: p.UpdatedBy(),
+		CreatedAt: p.CreatedAt(),
+		UpdatedAt: p.UpdatedAt(),
+	}
+}
+
+// PayoutReserveResponse is the JSON representation of a reserve kept back
+// from a payout
+type PayoutReserveResponse struct {
+	ID              uint       `json:"id"`
+	AgentID         uint       `json:"agent_id"`
+	PayoutID        uint       `json:"payout_id"`
+	Amount          float64    `json:"amount"`
+	Currency        string     `json:"currency"`
+	Percent         float64    `json:"percent"`
+	Status          string     `json:"status"`
+	ReleaseAt       time.Time  `json:"release_at"`
+	ReleasePayoutID *uint      `json:"release_payout_id,omitempty"`
+	ReleasedAt      *time.Time `json:"released_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// NewPayoutReserveResponse builds the response for a reserve
+func NewPayoutReserveResponse(r *payout.Reserve) PayoutReserveResponse {
+	return PayoutReserveResponse{
+		ID:              r.ID(),
+		AgentID:         r.AgentID(),
+		PayoutID:        r.PayoutID(),
+		Amount:          r.Amount().Float64(),
+		Currency:        r.Amount().Currency(),
+		Percent:         r.Percent(),
+		Status:          r.Status().String(),
+		ReleaseAt:       r.ReleaseAt(),
+		ReleasePayoutID: r.ReleasePayoutID(),
+		ReleasedAt:      r.ReleasedAt(),
+		CreatedAt:       r.CreatedAt(),
+	}
+}
+
+// NewPayoutReserveResponses builds the responses for a list of reserves
+func NewPayoutReserveResponses(reserves []*payout.Reserve) []PayoutReserveResponse {
+	responses := make([]PayoutReserveResponse, len(reserves))
+	for i, r := range reserves {
+		responses[i] = NewPayoutReserveResponse(r)
+	}
+	return responses
+}
@@ -0,0 +1,282 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	services "github.com/Ecom-micro-template/service-agent/internal/application"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/agent"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/tax"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// TaxHandler handles withholding tax: the rules admins configure, agents'
+// tax profiles and annual statements of commission income
+type TaxHandler struct {
+	rules   repository.WithholdingRuleReader
+	service *services.TaxService
+}
+
+// NewTaxHandler creates a new tax handler
+func NewTaxHandler(rules repository.WithholdingRuleReader, service *services.TaxService) *TaxHandler {
+	return &TaxHandler{
+		rules:   rules,
+		service: service,
+	}
+}
+
+// WithholdingRuleRequest represents the request to create or change a
+// withholding rule. Leaving residency or tax_id_present out matches any
+// agent on it.
+type WithholdingRuleRequest struct {
+	Name         string   `json:"name" binding:"required"`
+	Residency    string   `json:"residency" binding:"omitempty,oneof=resident non_resident"`
+	TaxIDPresent *bool    `json:"tax_id_present"`
+	Rate         *float64 `json:"rate" binding:"required,min=0,max=100"`
+	Active       *bool    `json:"active"` // Defaults to true
+}
+
+// params converts the request to rule parameters
+func (r WithholdingRuleRequest) params() tax.RuleParams {
+	active := true
+	if r.Active != nil {
+		active = *r.Active
+	}
+	return tax.RuleParams{
+		Name:         r.Name,
+		Residency:    r.Residency,
+		TaxIDPresent: r.TaxIDPresent,
+		Rate:         *r.Rate,
+		Active:       active,
+	}
+}
+
+// UpdateTaxProfileRequest represents the request to record an agent's tax
+// details
+type UpdateTaxProfileRequest struct {
+	TaxID        string   `json:"tax_id"`
+	Residency    string   `json:"residency" binding:"required,oneof=resident non_resident"`
+	RateOverride *float64 `json:"rate_override" binding:"omitempty,min=0,max=100"`
+}
+
+// ListRules lists the withholding rules
+func (h *TaxHandler) ListRules(c *gin.Context) {
+	rules, err := h.rules.List(c.Request.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to fetch withholding rules")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch withholding rules"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": NewWithholdingRuleResponses(rules)})
+}
+
+// CreateRule adds a withholding rule
+func (h *TaxHandler) CreateRule(c *gin.Context) {
+	var req WithholdingRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule, err := h.service.CreateRule(c.Request.Context(), req.params())
+	if err != nil {
+		respondTaxError(c, err, "Failed to create withholding rule")
+		return
+	}
+	c.JSON(http.StatusCreated, NewWithholdingRuleResponse(rule))
+}
+
+// UpdateRule changes a withholding rule
+func (h *TaxHandler) UpdateRule(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid withholding rule ID"})
+		return
+	}
+
+	var req WithholdingRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	rule, err := h.rules.GetByID(ctx, id)
+	if err != nil {
+		respondTaxError(c, err, "Failed to fetch withholding rule")
+		return
+	}
+	if err := h.service.UpdateRule(ctx, rule, req.params()); err != nil {
+		respondTaxError(c, err, "Failed to update withholding rule")
+		return
+	}
+	c.JSON(http.StatusOK, NewWithholdingRuleResponse(rule))
+}
+
+// DeleteRule removes a withholding rule
+func (h *TaxHandler) DeleteRule(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid withholding rule ID"})
+		return
+	}
+
+	if err := h.service.DeleteRule(c.Request.Context(), id); err != nil {
+		respondTaxError(c, err, "Failed to delete withholding rule")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Withholding rule deleted"})
+}
+
+// GetTaxProfile shows an agent's tax profile and the rate withheld from
+// their payouts
+func (h *TaxHandler) GetTaxProfile(c *gin.Context) {
+	agentID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid agent ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	profile, err := h.service.Profile(ctx, agentID)
+	if err != nil {
+		respondTaxError(c, err, "Failed to fetch tax profile")
+		return
+	}
+	h.respondProfile(c, profile)
+}
+
+// UpdateTaxProfile records an agent's tax details. Their payouts created
+// from now on are withheld by them.
+func (h *TaxHandler) UpdateTaxProfile(c *gin.Context) {
+	agentID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid agent ID"})
+		return
+	}
+
+	var req UpdateTaxProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	profile, err := h.service.SaveProfile(c.Request.Context(), agentID, req.TaxID, req.Residency, req.RateOverride, actorFromContext(c))
+	if err != nil {
+		respondTaxError(c, err, "Failed to save tax profile")
+		return
+	}
+	h.respondProfile(c, profile)
+}
+
+// respondProfile responds with a profile and the withholding it leads to
+func (h *TaxHandler) respondProfile(c *gin.Context, profile *tax.Profile) {
+	withholding, err := h.service.Withholding(c.Request.Context(), profile.AgentID())
+	if err != nil {
+		respondTaxError(c, err, "Failed to fetch tax profile")
+		return
+	}
+	c.JSON(http.StatusOK, NewTaxProfileResponse(profile, withholding))
+}
+
+// GetTaxStatements summarises the commission income of every agent paid in
+// a year: as JSON, or as a printable PDF with ?format=pdf
+func (h *TaxHandler) GetTaxStatements(c *gin.Context) {
+	year, err := tax.ParseYear(c.Param("year"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	statements, err := h.service.Statements(c.Request.Context(), year)
+	if err != nil {
+		respondTaxError(c, err, "Failed to build tax statements")
+		return
+	}
+
+	switch c.DefaultQuery("format", "json") {
+	case "pdf":
+		sendPDF(c, fmt.Sprintf("commission-income-%d.pdf", year), h.service.StatementsPDF(year, statements))
+	case "json":
+		responses := make([]TaxStatementResponse, len(statements))
+		for i, st := range statements {
+			responses[i] = NewTaxStatementResponse(st, false)
+		}
+		c.JSON(http.StatusOK, gin.H{"year": year, "data": responses})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json or pdf"})
+	}
+}
+
+// GetAgentTaxStatement shows an agent's statement of commission income for
+// a year
+func (h *TaxHandler) GetAgentTaxStatement(c *gin.Context) {
+	agentID, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid agent ID"})
+		return
+	}
+	h.showStatement(c, agentID)
+}
+
+// GetMyTaxStatement shows the authenticated agent's statement of
+// commission income for a year
+func (h *TaxHandler) GetMyTaxStatement(c *gin.Context) {
+	agentID, err := GetAgentFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	h.showStatement(c, agentID)
+}
+
+// showStatement responds with an agent's statement for the year in the
+// path: as JSON, or as a printable PDF with ?format=pdf
+func (h *TaxHandler) showStatement(c *gin.Context, agentID uint) {
+	year, err := tax.ParseYear(c.Param("year"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	statement, err := h.service.Statement(c.Request.Context(), agentID, year)
+	if err != nil {
+		respondTaxError(c, err, "Failed to build tax statement")
+		return
+	}
+
+	switch c.DefaultQuery("format", "json") {
+	case "pdf":
+		name := fmt.Sprintf("commission-income-%d-%s.pdf", year, statement.Agent.Code())
+		sendPDF(c, name, h.service.StatementPDF(statement))
+	case "json":
+		c.JSON(http.StatusOK, NewTaxStatementResponse(statement, true))
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json or pdf"})
+	}
+}
+
+// sendPDF responds with a PDF download
+func sendPDF(c *gin.Context, name string, content []byte) {
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	c.Data(http.StatusOK, "application/pdf", content)
+}
+
+func respondTaxError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, agent.ErrAgentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Agent not found"})
+	case errors.Is(err, tax.ErrRuleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Withholding rule not found"})
+	case errors.Is(err, tax.ErrInvalidRule), errors.Is(err, tax.ErrInvalidProfile),
+		errors.Is(err, shared.ErrInvalidTaxResidency):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		log.Error().Err(err).Msg(message)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	services "github.com/Ecom-micro-template/service-agent/internal/application"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+// TierHandler handles agent tier evaluations
+type TierHandler struct {
+	evaluations repository.TierEvaluationReader
+	evaluator   *services.TierEvaluator
+}
+
+// NewTierHandler creates a new tier handler
+func NewTierHandler(evaluations repository.TierEvaluationReader, evaluator *services.TierEvaluator) *TierHandler {
+	return &TierHandler{
+		evaluations: evaluations,
+		evaluator:   evaluator,
+	}
+}
+
+// GetAgentTierEvaluations lists an agent's tier evaluations, newest first
+func (h *TierHandler) GetAgentTierEvaluations(c *gin.Context) {
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid agent ID"})
+		return
+	}
+	h.listEvaluations(c, id)
+}
+
+// GetMyTierEvaluations lists the authenticated agent's tier evaluations,
+// so agents can see why their tier changed
+func (h *TierHandler) GetMyTierEvaluations(c *gin.Context) {
+	agentID, err := GetAgentFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	h.listEvaluations(c, agentID)
+}
+
+func (h *TierHandler) listEvaluations(c *gin.Context, agentID uint) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	evaluations, total, err := h.evaluations.GetByAgentID(c.Request.Context(), agentID, page, limit)
+	if err != nil {
+		log.Error().Err(err).Uint("agent_id", agentID).Msg("Failed to fetch tier evaluations")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tier evaluations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  NewTierEvaluationResponses(evaluations),
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// RunTierEvaluation evaluates every active agent's tier now
+func (h *TierHandler) RunTierEvaluation(c *gin.Context) {
+	evaluations, err := h.evaluator.EvaluateAll(c.Request.Context(), time.Now())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to run tier evaluation")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to run tier evaluation"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  NewTierEvaluationResponses(evaluations),
+		"total": len(evaluations),
+	})
+}
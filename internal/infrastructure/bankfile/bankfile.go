@@ -0,0 +1,204 @@
+// Package bankfile writes bank bulk-payment files for payout batches and
+// reads the bank statements that come back.
+package bankfile
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+)
+
+// Errors returned when exporting a batch
+var (
+	ErrUnknownFormat  = errors.New("unknown payment file format")
+	ErrInvalidBatch   = errors.New("invalid payment batch")
+	ErrTotalsMismatch = errors.New("payment file totals do not match the payouts")
+)
+
+// Debtor is the account payouts are paid from
+type Debtor struct {
+	Name    string
+	Account string // IBAN or local account number
+	BIC     string
+}
+
+// Payee is who a payment is made to. Account details may be empty when
+// they are not known; the bank asks for them before releasing the payment.
+type Payee struct {
+	AgentCode     string
+	Name          string
+	Email         string
+	AccountName   string
+	AccountNumber string
+	BankCode      string
+}
+
+// Payment is one credit transfer in a batch
+type Payment struct {
+	PayoutID   uint
+	Reference  string // Unique in the batch, quoted back on the bank statement
+	Amount     shared.Money
+	Payee      Payee
+	Remittance string
+}
+
+// Batch is a set of payments sent to the bank in one file
+type Batch struct {
+	ID        string // Message identification, at most 35 characters
+	CreatedAt time.Time
+	Debtor    Debtor
+	Payments  []Payment
+}
+
+// Summary is the payment count and totals by currency of a batch or file
+type Summary struct {
+	Count  int
+	Totals map[string]shared.Money
+}
+
+// File is an exported payment file
+type File struct {
+	Name        string
+	ContentType string
+	Content     []byte
+	Checksum    string // Hex SHA-256 of the content
+	Summary
+}
+
+// Exporter is a pluggable bank payment file format.
+//
+// Summarize reads the payment count and totals back from a file the
+// exporter wrote, so every file can be checked against the payouts it
+// pays before it is handed out.
+type Exporter interface {
+	Format() string
+	Export(batch Batch) (*File, error)
+	Summarize(content []byte) (Summary, error)
+}
+
+// Formats lists the supported payment file formats
+func Formats() []string {
+	return []string{FormatCSV, FormatPain001}
+}
+
+// NewExporter returns the exporter for a format
+func NewExporter(format string) (Exporter, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case FormatCSV:
+		return CSVExporter{}, nil
+	case FormatPain001:
+		return Pain001Exporter{}, nil
+	}
+	return nil, fmt.Errorf("%w %q, expected one of %s", ErrUnknownFormat, format, strings.Join(Formats(), ", "))
+}
+
+// Generate exports a batch, reads the file back and checks its payment
+// count and totals match the batch, then records the file's checksum.
+func Generate(exporter Exporter, batch Batch) (*File, error) {
+	expected, err := batch.Summary()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := exporter.Export(batch)
+	if err != nil {
+		return nil, err
+	}
+
+	actual, err := exporter.Summarize(file.Content)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTotalsMismatch, err)
+	}
+	if err := expected.Check(actual); err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(file.Content)
+	file.Checksum = hex.EncodeToString(sum[:])
+	file.Summary = expected
+	return file, nil
+}
+
+// Summary validates the batch and returns its payment count and totals
+func (b Batch) Summary() (Summary, error) {
+	if b.ID == "" || len(b.ID) > 35 {
+		return Summary{}, fmt.Errorf("%w: batch ID must be 1 to 35 characters", ErrInvalidBatch)
+	}
+	if len(b.Payments) == 0 {
+		return Summary{}, fmt.Errorf("%w: no payments", ErrInvalidBatch)
+	}
+
+	summary := Summary{Totals: make(map[string]shared.Money)}
+	references := make(map[string]bool, len(b.Payments))
+	for _, p := range b.Payments {
+		if p.Reference == "" || len(p.Reference) > 35 || references[p.Reference] {
+			return Summary{}, fmt.Errorf("%w: payment references must be unique and 1 to 35 characters", ErrInvalidBatch)
+		}
+		if !p.Amount.IsPositive() {
+			return Summary{}, fmt.Errorf("%w: payment %s is not positive", ErrInvalidBatch, p.Reference)
+		}
+		if p.Payee.Name == "" {
+			return Summary{}, fmt.Errorf("%w: payment %s has no payee name", ErrInvalidBatch, p.Reference)
+		}
+		references[p.Reference] = true
+		summary.add(p.Amount)
+	}
+	return summary, nil
+}
+
+// Currencies returns the currencies in the summary, sorted
+func (s Summary) Currencies() []string {
+	currencies := make([]string, 0, len(s.Totals))
+	for currency := range s.Totals {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	return currencies
+}
+
+// Check returns ErrTotalsMismatch if another summary differs from this one
+func (s Summary) Check(other Summary) error {
+	if s.Count != other.Count {
+		return fmt.Errorf("%w: %d payments in the file, expected %d", ErrTotalsMismatch, other.Count, s.Count)
+	}
+	if len(s.Totals) != len(other.Totals) {
+		return fmt.Errorf("%w: %d currencies in the file, expected %d", ErrTotalsMismatch, len(other.Totals), len(s.Totals))
+	}
+	for currency, total := range s.Totals {
+		got, ok := other.Totals[currency]
+		if !ok || got.Cmp(total) != 0 {
+			return fmt.Errorf("%w: %s total is %s in the file, expected %s", ErrTotalsMismatch, currency, got, total)
+		}
+	}
+	return nil
+}
+
+func (s *Summary) add(amount shared.Money) {
+	if s.Totals == nil {
+		s.Totals = make(map[string]shared.Money)
+	}
+	currency := amount.Currency()
+	s.Totals[currency] = s.Totals[currency].Add(amount)
+	s.Count++
+}
+
+// byCurrency groups a batch's payments by currency, in currency order
+func byCurrency(payments []Payment) ([]string, map[string][]Payment) {
+	groups := make(map[string][]Payment)
+	for _, p := range payments {
+		currency := p.Amount.Currency()
+		groups[currency] = append(groups[currency], p)
+	}
+	currencies := make([]string, 0, len(groups))
+	for currency := range groups {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	return currencies, groups
+}
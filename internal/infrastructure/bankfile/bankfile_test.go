@@ -0,0 +1,210 @@
+package bankfile
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+)
+
+func testBatch(payments ...Payment) Batch {
+	return Batch{
+		ID:        "RUN1-20240131120000",
+		CreatedAt: time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC),
+		Debtor:    Debtor{Name: "Agent Network Sdn Bhd", Account: "MY12MBBE0000001234567890", BIC: "MBBEMYKL"},
+		Payments:  payments,
+	}
+}
+
+func testPayment(reference string, minor int64, currency string) Payment {
+	return Payment{
+		PayoutID:  1,
+		Reference: reference,
+		Amount:    shared.NewMoney(minor, currency),
+		Payee: Payee{
+			AgentCode:     "AGT0001",
+			Name:          "Aisyah Rahman",
+			AccountName:   "AISYAH RAHMAN",
+			AccountNumber: "514012345678",
+			BankCode:      "MBBEMYKL",
+		},
+		Remittance: "Commission payout 2024-01",
+	}
+}
+
+func TestGenerateRoundTrip(t *testing.T) {
+	noAccount := testPayment("PAYOUT-00000003", 1, "MYR")
+	noAccount.Payee.AccountNumber = ""
+	noAccount.Payee.BankCode = ""
+
+	batches := []struct {
+		name  string
+		batch Batch
+	}{
+		{"one payment", testBatch(testPayment("PAYOUT-00000001", 12345, "MYR"))},
+		{"one currency", testBatch(
+			testPayment("PAYOUT-00000001", 12345, "MYR"),
+			testPayment("PAYOUT-00000002", 99, "MYR"),
+			noAccount,
+		)},
+		{"several currencies", testBatch(
+			testPayment("PAYOUT-00000001", 12345, "SGD"),
+			testPayment("PAYOUT-00000002", 5000, "MYR"),
+			testPayment("PAYOUT-00000003", 700, "JPY"),
+			testPayment("PAYOUT-00000004", 1, "SGD"),
+		)},
+		{"remittance needing quotes", testBatch(Payment{
+			Reference:  "PAYOUT-00000001",
+			Amount:     shared.NewMoney(100, "MYR"),
+			Payee:      Payee{Name: "Tan, Mei Ling"},
+			Remittance: `Commission "bonus", 2024-01`,
+		})},
+	}
+	for _, format := range Formats() {
+		for _, tt := range batches {
+			t.Run(format+"/"+tt.name, func(t *testing.T) {
+				exporter, err := NewExporter(format)
+				if err != nil {
+					t.Fatalf("NewExporter: %v", err)
+				}
+				expected, err := tt.batch.Summary()
+				if err != nil {
+					t.Fatalf("Summary: %v", err)
+				}
+
+				file, err := Generate(exporter, tt.batch)
+				if err != nil {
+					t.Fatalf("Generate: %v", err)
+				}
+				sum := sha256.Sum256(file.Content)
+				if file.Checksum != hex.EncodeToString(sum[:]) {
+					t.Errorf("checksum %s is not the SHA-256 of the content", file.Checksum)
+				}
+				if err := expected.Check(file.Summary); err != nil {
+					t.Errorf("file summary: %v", err)
+				}
+
+				// The file reads back to the batch's count and totals
+				actual, err := exporter.Summarize(file.Content)
+				if err != nil {
+					t.Fatalf("Summarize: %v", err)
+				}
+				if err := expected.Check(actual); err != nil {
+					t.Errorf("read back: %v", err)
+				}
+
+				// The same batch always gives the same file
+				again, err := Generate(exporter, tt.batch)
+				if err != nil {
+					t.Fatalf("Generate again: %v", err)
+				}
+				if again.Checksum != file.Checksum {
+					t.Errorf("checksum changed from %s to %s", file.Checksum, again.Checksum)
+				}
+			})
+		}
+	}
+}
+
+// tamperingExporter changes the files another exporter writes
+type tamperingExporter struct {
+	Exporter
+	old, new string
+}
+
+func (e tamperingExporter) Export(batch Batch) (*File, error) {
+	file, err := e.Exporter.Export(batch)
+	if err != nil {
+		return nil, err
+	}
+	file.Content = bytes.Replace(file.Content, []byte(e.old), []byte(e.new), 1)
+	return file, nil
+}
+
+func TestGenerateRefusesMismatchedFile(t *testing.T) {
+	batch := testBatch(testPayment("PAYOUT-00000001", 12345, "MYR"), testPayment("PAYOUT-00000002", 500, "MYR"))
+	tests := []struct {
+		name     string
+		exporter Exporter
+	}{
+		{"csv amount", tamperingExporter{CSVExporter{}, ",123.45,", ",123.46,"}},
+		{"csv trailer", tamperingExporter{CSVExporter{}, "T,MYR,2,", "T,MYR,3,"}},
+		{"csv payment dropped", tamperingExporter{CSVExporter{}, "D,PAYOUT-00000002", "X,PAYOUT-00000002"}},
+		{"pain001 amount", tamperingExporter{Pain001Exporter{}, ">123.45<", ">123.46<"}},
+		{"pain001 count", tamperingExporter{Pain001Exporter{}, "<NbOfTxs>2</NbOfTxs>", "<NbOfTxs>3</NbOfTxs>"}},
+		{"pain001 control sum", tamperingExporter{Pain001Exporter{}, "<CtrlSum>128.45</CtrlSum>", "<CtrlSum>128.00</CtrlSum>"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Generate(tt.exporter, batch); !errors.Is(err, ErrTotalsMismatch) {
+				t.Errorf("Generate() error = %v, want %v", err, ErrTotalsMismatch)
+			}
+		})
+	}
+}
+
+func TestBatchSummaryRejectsInvalidBatch(t *testing.T) {
+	noName := testPayment("PAYOUT-00000002", 100, "MYR")
+	noName.Payee.Name = ""
+	longID := testBatch(testPayment("PAYOUT-00000001", 100, "MYR"))
+	longID.ID = "RUN1-2024013112000000000000000000000"
+
+	tests := []struct {
+		name  string
+		batch Batch
+	}{
+		{"no payments", testBatch()},
+		{"long batch ID", longID},
+		{"repeated reference", testBatch(testPayment("PAYOUT-00000001", 100, "MYR"), testPayment("PAYOUT-00000001", 200, "MYR"))},
+		{"no reference", testBatch(testPayment("", 100, "MYR"))},
+		{"zero amount", testBatch(testPayment("PAYOUT-00000001", 0, "MYR"))},
+		{"negative amount", testBatch(testPayment("PAYOUT-00000001", -100, "MYR"))},
+		{"no payee name", testBatch(testPayment("PAYOUT-00000001", 100, "MYR"), noName)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.batch.Summary(); !errors.Is(err, ErrInvalidBatch) {
+				t.Errorf("Summary() error = %v, want %v", err, ErrInvalidBatch)
+			}
+		})
+	}
+}
+
+func TestPainAgentOf(t *testing.T) {
+	tests := []struct {
+		code     string
+		bic      string
+		clearing string
+		other    string
+	}{
+		{"MBBEMYKL", "MBBEMYKL", "", ""},
+		{"DEUTDEFF500", "DEUTDEFF500", "", ""},
+		{" mbbemykl ", "MBBEMYKL", "", ""},
+		{"", "", "", "NOTPROVIDED"},
+		{"27", "", "27", ""},
+		{"MBBEMY", "", "MBBEMY", ""},
+		{"MBB1MYKL", "", "MBB1MYKL", ""},
+		{"MBBEMYKLXX", "", "MBBEMYKLXX", ""},
+		{"TNG-EWALLET", "", "TNG-EWALLET", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			agent := painAgentOf(tt.code)
+			var clearing, other string
+			if agent.ClearingMember != nil {
+				clearing = agent.ClearingMember.MemberID
+			}
+			if agent.Other != nil {
+				other = agent.Other.ID
+			}
+			if agent.BIC != tt.bic || clearing != tt.clearing || other != tt.other {
+				t.Errorf("painAgentOf(%q) = BIC %q, clearing member %q, other %q; want %q, %q, %q",
+					tt.code, agent.BIC, clearing, other, tt.bic, tt.clearing, tt.other)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package bankfile
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+)
+
+// FormatCamt053 is the ISO 20022 bank to customer statement format,
+// camt.053
+const FormatCamt053 = "camt053"
+
+// Camt053Parser reads an ISO 20022 camt.053 bank statement, versions
+// 001.02 onwards. Each transaction in a booked entry is a statement entry;
+// pending and information-only entries are skipped. The end-to-end ID is
+// the payment reference, falling back to the unstructured remittance
+// information when the bank did not keep it.
+type Camt053Parser struct{}
+
+// Format returns the format name
+func (Camt053Parser) Format() string { return FormatCamt053 }
+
+// Parse reads the statement's entries
+func (Camt053Parser) Parse(content []byte) ([]StatementEntry, error) {
+	var doc camtDocument
+	if err := xml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidStatement, err)
+	}
+	if len(doc.Statements) == 0 {
+		return nil, fmt.Errorf("%w: no statements in the file", ErrInvalidStatement)
+	}
+
+	var entries []StatementEntry
+	for _, stmt := range doc.Statements {
+		for _, ntry := range stmt.Entries {
+			if status := ntry.Status.code(); status != "" && status != "BOOK" {
+				continue
+			}
+
+			var txs []camtTransaction
+			for _, details := range ntry.Details {
+				txs = append(txs, details.Transactions...)
+			}
+			if len(txs) == 0 {
+				txs = []camtTransaction{{}}
+			}
+
+			bookedAt := ntry.BookingDate.time()
+			for _, tx := range txs {
+				amount := tx.amount()
+				if amount == nil {
+					if len(txs) > 1 {
+						return nil, fmt.Errorf("%w: entry %s has several transactions without amounts", ErrInvalidStatement, ntry.Reference)
+					}
+					amount = &ntry.Amount
+				}
+				money, err := shared.ParseMoney(amount.Value, amount.Currency)
+				if err != nil {
+					return nil, fmt.Errorf("%w: entry %s: %v", ErrInvalidStatement, ntry.Reference, err)
+				}
+
+				indicator := ntry.Indicator
+				if tx.Indicator != "" {
+					indicator = tx.Indicator
+				}
+				transactionRef := tx.Refs.AccountServicerRef
+				if transactionRef == "" {
+					transactionRef = ntry.Reference
+				}
+
+				entries = append(entries, StatementEntry{
+					Reference:      tx.reference(),
+					TransactionRef: transactionRef,
+					Amount:         money,
+					BookedAt:       bookedAt,
+					Credit:         indicator == "CRDT",
+				})
+			}
+		}
+	}
+	return entries, nil
+}
+
+// camt.053 document, limited to the elements the parser reads
+
+type camtDocument struct {
+	XMLName    xml.Name        `xml:"Document"`
+	Statements []camtStatement `xml:"BkToCstmrStmt>Stmt"`
+}
+
+type camtStatement struct {
+	ID      string      `xml:"Id"`
+	Entries []camtEntry `xml:"Ntry"`
+}
+
+type camtEntry struct {
+	Reference   string        `xml:"AcctSvcrRef"`
+	Amount      camtAmount    `xml:"Amt"`
+	Indicator   string        `xml:"CdtDbtInd"`
+	Status      camtStatus    `xml:"Sts"`
+	BookingDate camtDate      `xml:"BookgDt"`
+	Details     []camtDetails `xml:"NtryDtls"`
+}
+
+type camtDetails struct {
+	Transactions []camtTransaction `xml:"TxDtls"`
+}
+
+type camtTransaction struct {
+	Refs struct {
+		EndToEndID         string `xml:"EndToEndId"`
+		AccountServicerRef string `xml:"AcctSvcrRef"`
+	} `xml:"Refs"`
+	Amount           *camtAmount `xml:"Amt"`
+	InstructedAmount *camtAmount `xml:"AmtDtls>InstdAmt>Amt"`
+	TxAmount         *camtAmount `xml:"AmtDtls>TxAmt>Amt"`
+	Indicator        string      `xml:"CdtDbtInd"`
+	Remittance       []string    `xml:"RmtInf>Ustrd"`
+}
+
+type camtAmount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+// camtStatus is a plain code before camt.053.001.08 and a Cd element from
+// it on
+type camtStatus struct {
+	Value string `xml:",chardata"`
+	Code  string `xml:"Cd"`
+}
+
+type camtDate struct {
+	Date     string `xml:"Dt"`
+	DateTime string `xml:"DtTm"`
+}
+
+func (s camtStatus) code() string {
+	if s.Code != "" {
+		return strings.TrimSpace(s.Code)
+	}
+	return strings.TrimSpace(s.Value)
+}
+
+func (d camtDate) time() *time.Time {
+	if t, err := time.Parse("2006-01-02", d.Date); err == nil {
+		return &t
+	}
+	if t, err := time.Parse(time.RFC3339, d.DateTime); err == nil {
+		return &t
+	}
+	return nil
+}
+
+// amount returns the transaction's own amount: the amount booked for it,
+// or the instructed amount, if the entry gives one
+func (tx camtTransaction) amount() *camtAmount {
+	for _, amount := range []*camtAmount{tx.Amount, tx.TxAmount, tx.InstructedAmount} {
+		if amount != nil && amount.Value != "" {
+			return amount
+		}
+	}
+	return nil
+}
+
+// reference returns the end-to-end ID, or the remittance information when
+// the end-to-end ID was not kept
+func (tx camtTransaction) reference() string {
+	if id := strings.TrimSpace(tx.Refs.EndToEndID); id != "" && id != "NOTPROVIDED" {
+		return id
+	}
+	return strings.TrimSpace(strings.Join(tx.Remittance, " "))
+}
@@ -0,0 +1,129 @@
+package bankfile
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+)
+
+// FormatCSV is the generic CSV payment file format
+const FormatCSV = "csv"
+
+// CSV record types
+const (
+	csvHeader  = "H"
+	csvPayment = "D"
+	csvTrailer = "T"
+)
+
+// CSVExporter writes a generic CSV payment file: a header record, a
+// payment record per payout and a trailer record per currency with the
+// payment count and total.
+//
+//	H,batch_id,created_at,debtor_name,debtor_account,debtor_bic
+//	D,reference,payee_name,agent_code,email,account_name,account_number,bank_code,amount,currency,remittance
+//	T,currency,count,total
+type CSVExporter struct{}
+
+// Format returns the format name
+func (CSVExporter) Format() string { return FormatCSV }
+
+// Export writes the batch as CSV
+func (CSVExporter) Export(batch Batch) (*File, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	records := [][]string{{
+		csvHeader, batch.ID, batch.CreatedAt.UTC().Format(time.RFC3339),
+		batch.Debtor.Name, batch.Debtor.Account, batch.Debtor.BIC,
+	}}
+
+	currencies, groups := byCurrency(batch.Payments)
+	for _, currency := range currencies {
+		for _, p := range groups[currency] {
+			records = append(records, []string{
+				csvPayment, p.Reference, p.Payee.Name, p.Payee.AgentCode, p.Payee.Email,
+				p.Payee.AccountName, p.Payee.AccountNumber, p.Payee.BankCode,
+				p.Amount.String(), currency, p.Remittance,
+			})
+		}
+	}
+	for _, currency := range currencies {
+		var total shared.Money
+		for _, p := range groups[currency] {
+			total = total.Add(p.Amount)
+		}
+		records = append(records, []string{
+			csvTrailer, currency, strconv.Itoa(len(groups[currency])), total.String(),
+		})
+	}
+
+	if err := w.WriteAll(records); err != nil {
+		return nil, fmt.Errorf("failed to write CSV payment file: %w", err)
+	}
+
+	return &File{
+		Name:        batch.ID + ".csv",
+		ContentType: "text/csv",
+		Content:     buf.Bytes(),
+	}, nil
+}
+
+// Summarize reads the payment records back from a CSV payment file and
+// checks them against its trailer records
+func (CSVExporter) Summarize(content []byte) (Summary, error) {
+	r := csv.NewReader(bytes.NewReader(content))
+	r.FieldsPerRecord = -1
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return Summary{}, err
+	}
+
+	var payments, trailers Summary
+	for i, record := range records {
+		switch record[0] {
+		case csvHeader:
+			if i != 0 {
+				return Summary{}, fmt.Errorf("line %d: header record must come first", i+1)
+			}
+		case csvPayment:
+			if len(record) != 11 {
+				return Summary{}, fmt.Errorf("line %d: payment record has %d fields", i+1, len(record))
+			}
+			amount, err := shared.ParseMoney(record[8], record[9])
+			if err != nil {
+				return Summary{}, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			payments.add(amount)
+		case csvTrailer:
+			if len(record) != 4 {
+				return Summary{}, fmt.Errorf("line %d: trailer record has %d fields", i+1, len(record))
+			}
+			count, err := strconv.Atoi(record[2])
+			if err != nil {
+				return Summary{}, fmt.Errorf("line %d: invalid count", i+1)
+			}
+			total, err := shared.ParseMoney(record[3], record[1])
+			if err != nil {
+				return Summary{}, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			if trailers.Totals == nil {
+				trailers.Totals = make(map[string]shared.Money)
+			}
+			trailers.Totals[total.Currency()] = total
+			trailers.Count += count
+		default:
+			return Summary{}, fmt.Errorf("line %d: unknown record type %q", i+1, record[0])
+		}
+	}
+
+	if err := trailers.Check(payments); err != nil {
+		return Summary{}, err
+	}
+	return payments, nil
+}
@@ -0,0 +1,281 @@
+package bankfile
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+)
+
+// FormatPain001 is the ISO 20022 customer credit transfer initiation
+// format, pain.001.001.03
+const FormatPain001 = "pain001"
+
+const pain001Namespace = "urn:iso:std:iso:20022:tech:xsd:pain.001.001.03"
+
+// Pain001Exporter writes an ISO 20022 pain.001.001.03 credit transfer
+// file with a payment information block per currency. Payment references
+// are sent as end-to-end IDs, which the bank quotes back on the statement.
+// Batch booking is off, so each payment is its own statement entry.
+type Pain001Exporter struct{}
+
+// Format returns the format name
+func (Pain001Exporter) Format() string { return FormatPain001 }
+
+// Export writes the batch as pain.001 XML
+func (Pain001Exporter) Export(batch Batch) (*File, error) {
+	if batch.Debtor.Name == "" || batch.Debtor.Account == "" {
+		return nil, fmt.Errorf("%w: the debtor name and account are required", ErrInvalidBatch)
+	}
+
+	doc := painDocument{
+		Xmlns: pain001Namespace,
+		Initiation: painInitiation{
+			GroupHeader: painGroupHeader{
+				MsgID:          batch.ID,
+				CreatedAt:      batch.CreatedAt.UTC().Format("2006-01-02T15:04:05"),
+				InitiatingName: batch.Debtor.Name,
+			},
+		},
+	}
+
+	var groupSum []shared.Money
+	currencies, groups := byCurrency(batch.Payments)
+	for i, currency := range currencies {
+		info := painPaymentInfo{
+			ID:            fmt.Sprintf("%s-%d", batch.ID, i+1),
+			Method:        "TRF",
+			BatchBooking:  false,
+			ExecutionDate: batch.CreatedAt.UTC().Format("2006-01-02"),
+			Debtor:        painParty{Name: batch.Debtor.Name},
+			DebtorAccount: painAccountOf(batch.Debtor.Account),
+			DebtorAgent:   painAgentOf(batch.Debtor.BIC),
+			ChargeBearer:  "SLEV",
+		}
+
+		var total shared.Money
+		for _, p := range groups[currency] {
+			tx := painTransaction{
+				EndToEndID: p.Reference,
+				Amount:     painAmount{Currency: currency, Value: p.Amount.String()},
+				Creditor:   painParty{Name: p.Payee.Name},
+				Remittance: p.Remittance,
+			}
+			if p.Payee.BankCode != "" {
+				agent := painAgentOf(p.Payee.BankCode)
+				tx.CreditorAgent = &agent
+			}
+			if p.Payee.AccountNumber != "" {
+				account := painAccountOf(p.Payee.AccountNumber)
+				tx.CreditorAccount = &account
+			}
+			info.Transactions = append(info.Transactions, tx)
+			total = total.Add(p.Amount)
+		}
+		info.Count = strconv.Itoa(len(info.Transactions))
+		info.ControlSum = total.String()
+
+		doc.Initiation.Payments = append(doc.Initiation.Payments, info)
+		groupSum = append(groupSum, total)
+	}
+	doc.Initiation.GroupHeader.Count = strconv.Itoa(len(batch.Payments))
+	doc.Initiation.GroupHeader.ControlSum = controlSum(groupSum)
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("failed to write pain.001 payment file: %w", err)
+	}
+	buf.WriteByte('\n')
+
+	return &File{
+		Name:        batch.ID + ".xml",
+		ContentType: "application/xml",
+		Content:     buf.Bytes(),
+	}, nil
+}
+
+// Summarize reads the transactions back from a pain.001 file and checks
+// them against the file's own counts and control sums
+func (Pain001Exporter) Summarize(content []byte) (Summary, error) {
+	var doc painDocument
+	if err := xml.Unmarshal(content, &doc); err != nil {
+		return Summary{}, err
+	}
+
+	var summary Summary
+	var groupSum []shared.Money
+	for _, info := range doc.Initiation.Payments {
+		var total shared.Money
+		for _, tx := range info.Transactions {
+			amount, err := shared.ParseMoney(tx.Amount.Value, tx.Amount.Currency)
+			if err != nil {
+				return Summary{}, fmt.Errorf("transaction %s: %w", tx.EndToEndID, err)
+			}
+			if !total.SameCurrency(amount) {
+				return Summary{}, fmt.Errorf("payment information %s mixes currencies", info.ID)
+			}
+			total = total.Add(amount)
+			summary.add(amount)
+		}
+		if info.Count != strconv.Itoa(len(info.Transactions)) || info.ControlSum != total.String() {
+			return Summary{}, fmt.Errorf("payment information %s count or control sum does not match its transactions", info.ID)
+		}
+		groupSum = append(groupSum, total)
+	}
+
+	header := doc.Initiation.GroupHeader
+	if header.Count != strconv.Itoa(summary.Count) || header.ControlSum != controlSum(groupSum) {
+		return Summary{}, fmt.Errorf("group header count or control sum does not match the transactions")
+	}
+	return summary, nil
+}
+
+// controlSum adds amounts in any currencies as plain decimals, as the
+// group header's control sum does
+func controlSum(amounts []shared.Money) string {
+	sum := new(big.Rat)
+	places := 0
+	for _, amount := range amounts {
+		r, _ := new(big.Rat).SetString(amount.String())
+		sum.Add(sum, r)
+		if exp := shared.CurrencyExponent(amount.Currency()); exp > places {
+			places = exp
+		}
+	}
+	return sum.FloatString(places)
+}
+
+// painAccountOf identifies an account by IBAN when it looks like one, and
+// by its local account number otherwise
+func painAccountOf(account string) painAccount {
+	if isIBAN(account) {
+		return painAccount{IBAN: account}
+	}
+	return painAccount{Other: &painOtherID{ID: account}}
+}
+
+// painAgentOf identifies a bank by BIC when the code is one, by the code
+// as its clearing system member ID otherwise, or as not provided
+func painAgentOf(code string) painAgent {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	switch {
+	case code == "":
+		return painAgent{Other: &painOtherID{ID: "NOTPROVIDED"}}
+	case isBIC(code):
+		return painAgent{BIC: code}
+	default:
+		return painAgent{ClearingMember: &painClearingMember{MemberID: code}}
+	}
+}
+
+// isBIC reports whether a code is an ISO 9362 BIC: a four letter bank
+// code, a two letter country code, a two character location code and an
+// optional three character branch code
+func isBIC(code string) bool {
+	if len(code) != 8 && len(code) != 11 {
+		return false
+	}
+	for i, r := range code {
+		switch {
+		case i < 6 && !(r >= 'A' && r <= 'Z'),
+			i >= 6 && !(r >= 'A' && r <= 'Z') && !(r >= '0' && r <= '9'):
+			return false
+		}
+	}
+	return true
+}
+
+func isIBAN(account string) bool {
+	if len(account) < 15 || len(account) > 34 {
+		return false
+	}
+	for i, r := range account {
+		switch {
+		case i < 2 && !unicode.IsUpper(r),
+			i >= 2 && i < 4 && !unicode.IsDigit(r),
+			!unicode.IsUpper(r) && !unicode.IsDigit(r):
+			return false
+		}
+	}
+	return true
+}
+
+// pain.001.001.03 document, limited to the elements the exporter writes
+
+type painDocument struct {
+	XMLName    xml.Name       `xml:"Document"`
+	Xmlns      string         `xml:"xmlns,attr"`
+	Initiation painInitiation `xml:"CstmrCdtTrfInitn"`
+}
+
+type painInitiation struct {
+	GroupHeader painGroupHeader   `xml:"GrpHdr"`
+	Payments    []painPaymentInfo `xml:"PmtInf"`
+}
+
+type painGroupHeader struct {
+	MsgID          string `xml:"MsgId"`
+	CreatedAt      string `xml:"CreDtTm"`
+	Count          string `xml:"NbOfTxs"`
+	ControlSum     string `xml:"CtrlSum"`
+	InitiatingName string `xml:"InitgPty>Nm"`
+}
+
+type painPaymentInfo struct {
+	ID            string            `xml:"PmtInfId"`
+	Method        string            `xml:"PmtMtd"`
+	BatchBooking  bool              `xml:"BtchBookg"`
+	Count         string            `xml:"NbOfTxs"`
+	ControlSum    string            `xml:"CtrlSum"`
+	ExecutionDate string            `xml:"ReqdExctnDt"`
+	Debtor        painParty         `xml:"Dbtr"`
+	DebtorAccount painAccount       `xml:"DbtrAcct>Id"`
+	DebtorAgent   painAgent         `xml:"DbtrAgt>FinInstnId"`
+	ChargeBearer  string            `xml:"ChrgBr"`
+	Transactions  []painTransaction `xml:"CdtTrfTxInf"`
+}
+
+type painTransaction struct {
+	EndToEndID      string       `xml:"PmtId>EndToEndId"`
+	Amount          painAmount   `xml:"Amt>InstdAmt"`
+	CreditorAgent   *painAgent   `xml:"CdtrAgt>FinInstnId,omitempty"`
+	Creditor        painParty    `xml:"Cdtr"`
+	CreditorAccount *painAccount `xml:"CdtrAcct>Id,omitempty"`
+	Remittance      string       `xml:"RmtInf>Ustrd,omitempty"`
+}
+
+type painAmount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type painParty struct {
+	Name string `xml:"Nm"`
+}
+
+type painAccount struct {
+	IBAN  string       `xml:"IBAN,omitempty"`
+	Other *painOtherID `xml:"Othr,omitempty"`
+}
+
+type painAgent struct {
+	BIC            string              `xml:"BIC,omitempty"`
+	ClearingMember *painClearingMember `xml:"ClrSysMmbId,omitempty"`
+	Other          *painOtherID        `xml:"Othr,omitempty"`
+}
+
+type painClearingMember struct {
+	MemberID string `xml:"MmbId"`
+}
+
+type painOtherID struct {
+	ID string `xml:"Id"`
+}
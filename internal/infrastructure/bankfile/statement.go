@@ -0,0 +1,113 @@
+package bankfile
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+)
+
+// ErrInvalidStatement is returned when a bank statement cannot be read
+var ErrInvalidStatement = errors.New("invalid bank statement")
+
+// StatementEntry is one payment on a bank statement
+type StatementEntry struct {
+	Reference      string // The payment reference, as sent in the payment file
+	TransactionRef string // The bank's reference for the transaction
+	Amount         shared.Money
+	BookedAt       *time.Time
+	Credit         bool // Money received rather than paid, such as a returned payment
+}
+
+// StatementParser is a pluggable bank statement format
+type StatementParser interface {
+	Format() string
+	Parse(content []byte) ([]StatementEntry, error)
+}
+
+// StatementFormats lists the supported bank statement formats
+func StatementFormats() []string {
+	return []string{FormatCSV, FormatCamt053}
+}
+
+// NewStatementParser returns the parser for a statement format
+func NewStatementParser(format string) (StatementParser, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case FormatCSV:
+		return CSVStatementParser{}, nil
+	case FormatCamt053:
+		return Camt053Parser{}, nil
+	}
+	return nil, fmt.Errorf("%w %q, expected one of %s", ErrUnknownFormat, format, strings.Join(StatementFormats(), ", "))
+}
+
+// CSVStatementParser reads a CSV bank statement with a header row naming
+// its columns. reference, amount and currency are required; date,
+// transaction_ref and type are optional. Amounts are read without their
+// sign, and a type of "credit" marks money received.
+//
+//	date,reference,amount,currency,transaction_ref,type
+//	2024-06-03,PAYOUT-00000042,1250.00,MYR,FT24155XK2,debit
+type CSVStatementParser struct{}
+
+// Format returns the format name
+func (CSVStatementParser) Format() string { return FormatCSV }
+
+// Parse reads the statement's entries
+func (CSVStatementParser) Parse(content []byte) ([]StatementEntry, error) {
+	r := csv.NewReader(bytes.NewReader(content))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidStatement, err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: no header row", ErrInvalidStatement)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"reference", "amount", "currency"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("%w: no %s column", ErrInvalidStatement, name)
+		}
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var entries []StatementEntry
+	for i, record := range records[1:] {
+		line := i + 2
+		amount, err := shared.ParseMoney(strings.TrimPrefix(field(record, "amount"), "-"), field(record, "currency"))
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidStatement, line, err)
+		}
+		entry := StatementEntry{
+			Reference:      field(record, "reference"),
+			TransactionRef: field(record, "transaction_ref"),
+			Amount:         amount,
+			Credit:         strings.EqualFold(field(record, "type"), "credit"),
+		}
+		if date := field(record, "date"); date != "" {
+			bookedAt, err := time.Parse("2006-01-02", date)
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: date must be YYYY-MM-DD", ErrInvalidStatement, line)
+			}
+			entry.BookedAt = &bookedAt
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
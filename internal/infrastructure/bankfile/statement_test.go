@@ -0,0 +1,199 @@
+package bankfile
+
+import (
+	"errors"
+	"testing"
+)
+
+// wantEntry is a statement entry as the tests expect it
+type wantEntry struct {
+	reference      string
+	transactionRef string
+	minor          int64
+	currency       string
+	bookedAt       string
+	credit         bool
+}
+
+func checkEntries(t *testing.T, entries []StatementEntry, want []wantEntry) {
+	t.Helper()
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries, want %d: %+v", len(entries), len(want), entries)
+	}
+	for i, entry := range entries {
+		var bookedAt string
+		if entry.BookedAt != nil {
+			bookedAt = entry.BookedAt.Format("2006-01-02")
+		}
+		got := wantEntry{entry.Reference, entry.TransactionRef, entry.Amount.Minor(), entry.Amount.Currency(), bookedAt, entry.Credit}
+		if got != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, got, want[i])
+		}
+	}
+}
+
+const camt053Statement = `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">
+  <BkToCstmrStmt>
+    <Stmt>
+      <Id>STMT-20240603</Id>
+      <Ntry>
+        <AcctSvcrRef>FT24155XK2</AcctSvcrRef>
+        <Amt Ccy="MYR">1250.00</Amt>
+        <CdtDbtInd>DBIT</CdtDbtInd>
+        <Sts>BOOK</Sts>
+        <BookgDt><Dt>2024-06-03</Dt></BookgDt>
+        <NtryDtls><TxDtls><Refs><EndToEndId>PAYOUT-00000042</EndToEndId></Refs></TxDtls></NtryDtls>
+      </Ntry>
+      <Ntry>
+        <AcctSvcrRef>FT24155XK3</AcctSvcrRef>
+        <Amt Ccy="MYR">300.50</Amt>
+        <CdtDbtInd>DBIT</CdtDbtInd>
+        <Sts>BOOK</Sts>
+        <BookgDt><DtTm>2024-06-03T09:30:00+08:00</DtTm></BookgDt>
+        <NtryDtls>
+          <TxDtls>
+            <Refs><AcctSvcrRef>FT24155XK3-1</AcctSvcrRef><EndToEndId>NOTPROVIDED</EndToEndId></Refs>
+            <AmtDtls><TxAmt><Amt Ccy="MYR">100.50</Amt></TxAmt></AmtDtls>
+            <RmtInf><Ustrd>Commission</Ustrd><Ustrd>PAYOUT-00000043</Ustrd></RmtInf>
+          </TxDtls>
+          <TxDtls>
+            <Refs><AcctSvcrRef>FT24155XK3-2</AcctSvcrRef><EndToEndId>PAYOUT-00000044-2</EndToEndId></Refs>
+            <Amt Ccy="MYR">200.00</Amt>
+          </TxDtls>
+        </NtryDtls>
+      </Ntry>
+      <Ntry>
+        <AcctSvcrRef>FT24156AA1</AcctSvcrRef>
+        <Amt Ccy="MYR">99.00</Amt>
+        <CdtDbtInd>DBIT</CdtDbtInd>
+        <Sts>PDNG</Sts>
+        <NtryDtls><TxDtls><Refs><EndToEndId>PAYOUT-00000045</EndToEndId></Refs></TxDtls></NtryDtls>
+      </Ntry>
+      <Ntry>
+        <AcctSvcrRef>FT24156RT7</AcctSvcrRef>
+        <Amt Ccy="MYR">1250.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <Sts>BOOK</Sts>
+        <BookgDt><Dt>2024-06-04</Dt></BookgDt>
+        <NtryDtls><TxDtls><Refs><EndToEndId>PAYOUT-00000042</EndToEndId></Refs></TxDtls></NtryDtls>
+      </Ntry>
+    </Stmt>
+  </BkToCstmrStmt>
+</Document>`
+
+// camt.053.001.08 and later give the entry status as a code element
+const camt053StatementV08 = `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.08">
+  <BkToCstmrStmt>
+    <Stmt>
+      <Id>STMT-20240605</Id>
+      <Ntry>
+        <AcctSvcrRef>FT24157ZZ9</AcctSvcrRef>
+        <Amt Ccy="SGD">75.25</Amt>
+        <CdtDbtInd>DBIT</CdtDbtInd>
+        <Sts><Cd>BOOK</Cd></Sts>
+        <BookgDt><Dt>2024-06-05</Dt></BookgDt>
+      </Ntry>
+      <Ntry>
+        <AcctSvcrRef>FT24157ZZ8</AcctSvcrRef>
+        <Amt Ccy="SGD">10.00</Amt>
+        <CdtDbtInd>DBIT</CdtDbtInd>
+        <Sts><Cd>INFO</Cd></Sts>
+      </Ntry>
+    </Stmt>
+  </BkToCstmrStmt>
+</Document>`
+
+func TestCamt053Parse(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []wantEntry
+	}{
+		{"booked entries", camt053Statement, []wantEntry{
+			{"PAYOUT-00000042", "FT24155XK2", 125000, "MYR", "2024-06-03", false},
+			{"Commission PAYOUT-00000043", "FT24155XK3-1", 10050, "MYR", "2024-06-03", false},
+			{"PAYOUT-00000044-2", "FT24155XK3-2", 20000, "MYR", "2024-06-03", false},
+			{"PAYOUT-00000042", "FT24156RT7", 125000, "MYR", "2024-06-04", true},
+		}},
+		{"status code element", camt053StatementV08, []wantEntry{
+			{"", "FT24157ZZ9", 7525, "SGD", "2024-06-05", false},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := Camt053Parser{}.Parse([]byte(tt.content))
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			checkEntries(t, entries, tt.want)
+		})
+	}
+}
+
+func TestCamt053ParseRejectsInvalidStatement(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"not XML", "date,reference,amount\n"},
+		{"no statements", `<Document><BkToCstmrStmt></BkToCstmrStmt></Document>`},
+		{"bad amount", `<Document><BkToCstmrStmt><Stmt><Ntry>
+			<Amt Ccy="MYR">12,50</Amt><CdtDbtInd>DBIT</CdtDbtInd><Sts>BOOK</Sts>
+		</Ntry></Stmt></BkToCstmrStmt></Document>`},
+		{"batch without transaction amounts", `<Document><BkToCstmrStmt><Stmt><Ntry>
+			<Amt Ccy="MYR">300.00</Amt><CdtDbtInd>DBIT</CdtDbtInd><Sts>BOOK</Sts>
+			<NtryDtls>
+				<TxDtls><Refs><EndToEndId>PAYOUT-00000001</EndToEndId></Refs></TxDtls>
+				<TxDtls><Refs><EndToEndId>PAYOUT-00000002</EndToEndId></Refs></TxDtls>
+			</NtryDtls>
+		</Ntry></Stmt></BkToCstmrStmt></Document>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := (Camt053Parser{}).Parse([]byte(tt.content)); !errors.Is(err, ErrInvalidStatement) {
+				t.Errorf("Parse() error = %v, want %v", err, ErrInvalidStatement)
+			}
+		})
+	}
+}
+
+func TestCSVStatementParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []wantEntry
+		valid   bool
+	}{
+		{"all columns", "date,reference,amount,currency,transaction_ref,type\n" +
+			"2024-06-03,PAYOUT-00000042,1250.00,MYR,FT24155XK2,debit\n" +
+			"2024-06-04,PAYOUT-00000042,-1250.00,MYR,FT24156RT7,CREDIT\n", []wantEntry{
+			{"PAYOUT-00000042", "FT24155XK2", 125000, "MYR", "2024-06-03", false},
+			{"PAYOUT-00000042", "FT24156RT7", 125000, "MYR", "2024-06-04", true},
+		}, true},
+		{"columns in any order", "Currency, Amount, Reference\nsgd, 75.25, PAYOUT-00000007\n", []wantEntry{
+			{"PAYOUT-00000007", "", 7525, "SGD", "", false},
+		}, true},
+		{"header only", "reference,amount,currency\n", nil, true},
+		{"empty", "", nil, false},
+		{"no amount column", "reference,currency\nPAYOUT-00000001,MYR\n", nil, false},
+		{"bad amount", "reference,amount,currency\nPAYOUT-00000001,RM10,MYR\n", nil, false},
+		{"bad date", "date,reference,amount,currency\n03/06/2024,PAYOUT-00000001,10.00,MYR\n", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := CSVStatementParser{}.Parse([]byte(tt.content))
+			if !tt.valid {
+				if !errors.Is(err, ErrInvalidStatement) {
+					t.Errorf("Parse() error = %v, want %v", err, ErrInvalidStatement)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			checkEntries(t, entries, tt.want)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// Package disbursement connects payouts to payout providers: disbursement
+// gateways that pay agents on request and report back how each transfer
+// went.
+package disbursement
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/infrastructure/bankfile"
+)
+
+// Errors returned by providers. Errors wrapping ErrTransient may succeed
+// if the request is made again.
+var (
+	ErrTransient        = errors.New("transient payout provider error")
+	ErrTimeout          = fmt.Errorf("payout provider timed out: %w", ErrTransient)
+	ErrUnavailable      = fmt.Errorf("payout provider unavailable: %w", ErrTransient)
+	ErrTransferNotFound = errors.New("transfer not found at payout provider")
+	ErrNotCancellable   = errors.New("transfer can no longer be cancelled")
+)
+
+// Transfer statuses reported by a provider
+const (
+	StatusProcessing = "processing"
+	StatusSucceeded  = "succeeded"
+	StatusFailed     = "failed"
+	StatusCancelled  = "cancelled"
+)
+
+// Transfer is a request to pay a payout. The reference identifies the
+// transfer at the provider, so submitting the same reference again does
+// not pay twice.
+type Transfer struct {
+	Reference   string
+	Amount      shared.Money
+	Payee       bankfile.Payee
+	Description string
+}
+
+// Update is a provider's report of a transfer's status, returned from a
+// request or delivered by a callback
+type Update struct {
+	Reference      string
+	Status         string
+	TransactionRef string // Set once the transfer has succeeded
+	Reason         string // Why the transfer failed or was cancelled
+}
+
+// IsFinal returns true if the transfer will not change status again
+func (u Update) IsFinal() bool {
+	return u.Status == StatusSucceeded || u.Status == StatusFailed || u.Status == StatusCancelled
+}
+
+// Callback receives transfer updates pushed by a provider
+type Callback func(ctx context.Context, update Update) error
+
+// Provider is a pluggable payout provider.
+//
+// Submit sends a transfer and returns its status on acceptance; the
+// outcome usually follows in a callback. Status queries a transfer and
+// Cancel stops one that has not completed yet.
+type Provider interface {
+	Name() string
+	Submit(ctx context.Context, transfer Transfer) (Update, error)
+	Status(ctx context.Context, reference string) (Update, error)
+	Cancel(ctx context.Context, reference string) (Update, error)
+}
+
+// IsTransient reports whether a provider request failed in a way that may
+// succeed on retry
+func IsTransient(err error) bool {
+	return errors.Is(err, ErrTransient) || errors.Is(err, context.DeadlineExceeded)
+}
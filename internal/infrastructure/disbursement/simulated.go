@@ -0,0 +1,156 @@
+package disbursement
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Simulated outcomes
+const (
+	OutcomeSucceed = "succeed"
+	OutcomeFail    = "fail"
+	OutcomeTimeout = "timeout"
+)
+
+// SimulatedConfig configures the simulated provider
+type SimulatedConfig struct {
+	// Outcome is what happens to every transfer: succeed, fail or
+	// timeout. Timed out submissions never reach the provider.
+	Outcome string
+
+	// Delay is how long after acceptance a transfer's outcome is
+	// reported by callback
+	Delay time.Duration
+
+	// TransientFailures is how many times a transfer's submission fails
+	// with ErrUnavailable before it is accepted
+	TransientFailures int
+
+	// FailureReason is the reason given for failed transfers
+	FailureReason string
+}
+
+// SimulatedProvider is a deterministic Provider for development and
+// testing: every transfer has the configured outcome, reported by callback
+// after the configured delay, and succeeded transfers get the transaction
+// reference "SIM-" followed by the transfer reference.
+type SimulatedProvider struct {
+	cfg SimulatedConfig
+
+	mu        sync.Mutex
+	transfers map[string]*Update
+	attempts  map[string]int
+	callback  Callback
+}
+
+// NewSimulatedProvider creates a simulated provider
+func NewSimulatedProvider(cfg SimulatedConfig) (*SimulatedProvider, error) {
+	switch cfg.Outcome {
+	case OutcomeSucceed, OutcomeFail, OutcomeTimeout:
+	default:
+		return nil, fmt.Errorf("unknown simulated payout outcome %q", cfg.Outcome)
+	}
+	if cfg.FailureReason == "" {
+		cfg.FailureReason = "simulated transfer failure"
+	}
+	return &SimulatedProvider{
+		cfg:       cfg,
+		transfers: make(map[string]*Update),
+		attempts:  make(map[string]int),
+	}, nil
+}
+
+// OnUpdate registers the callback that receives transfer outcomes
+func (p *SimulatedProvider) OnUpdate(callback Callback) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.callback = callback
+}
+
+// Name returns the provider name
+func (p *SimulatedProvider) Name() string { return "simulated" }
+
+// Submit accepts a transfer and reports its outcome after the delay. A
+// transfer submitted again returns its current status.
+func (p *SimulatedProvider) Submit(ctx context.Context, transfer Transfer) (Update, error) {
+	if p.cfg.Outcome == OutcomeTimeout {
+		<-ctx.Done()
+		return Update{}, fmt.Errorf("%w: %v", ErrTimeout, ctx.Err())
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if existing, ok := p.transfers[transfer.Reference]; ok {
+		return *existing, nil
+	}
+	p.attempts[transfer.Reference]++
+	if p.attempts[transfer.Reference] <= p.cfg.TransientFailures {
+		return Update{}, ErrUnavailable
+	}
+
+	update := &Update{Reference: transfer.Reference, Status: StatusProcessing}
+	p.transfers[transfer.Reference] = update
+	time.AfterFunc(p.cfg.Delay, func() { p.settle(transfer.Reference) })
+	return *update, nil
+}
+
+// Status returns a transfer's current status
+func (p *SimulatedProvider) Status(ctx context.Context, reference string) (Update, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	update, ok := p.transfers[reference]
+	if !ok {
+		return Update{}, ErrTransferNotFound
+	}
+	return *update, nil
+}
+
+// Cancel cancels a transfer whose outcome has not been reported yet
+func (p *SimulatedProvider) Cancel(ctx context.Context, reference string) (Update, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	update, ok := p.transfers[reference]
+	if !ok {
+		return Update{}, ErrTransferNotFound
+	}
+	if update.IsFinal() {
+		return Update{}, ErrNotCancellable
+	}
+	update.Status = StatusCancelled
+	update.Reason = "cancelled on request"
+	return *update, nil
+}
+
+// settle applies the configured outcome to a transfer still processing
+// and reports it to the callback
+func (p *SimulatedProvider) settle(reference string) {
+	p.mu.Lock()
+	update := p.transfers[reference]
+	if update.IsFinal() {
+		p.mu.Unlock()
+		return
+	}
+	if p.cfg.Outcome == OutcomeSucceed {
+		update.Status = StatusSucceeded
+		update.TransactionRef = "SIM-" + reference
+	} else {
+		update.Status = StatusFailed
+		update.Reason = p.cfg.FailureReason
+	}
+	callback, settled := p.callback, *update
+	p.mu.Unlock()
+
+	if callback == nil {
+		return
+	}
+	if err := callback(context.Background(), settled); err != nil {
+		log.Error().Err(err).Str("reference", reference).Msg("Simulated payout callback failed")
+	}
+}
@@ -0,0 +1,224 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/agent"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+)
+
+// agentRepository implements repository.AgentRepository
+type agentRepository struct {
+	store *Store
+}
+
+// NewAgentRepository creates a new in-memory agent repository
+func NewAgentRepository(store *Store) repository.AgentRepository {
+	return &agentRepository{store: store}
+}
+
+// agentParams captures the stored state of an agent
+func agentParams(a *agent.Agent) agent.AgentParams {
+	return agent.AgentParams{
+		ID:             a.ID(),
+		Code:           a.Code(),
+		Name:           a.Name(),
+		Email:          a.Email(),
+		Phone:          a.Phone(),
+		CommissionRate: a.CommissionRate().Value(),
+		Tier:           a.Tier().String(),
+		Status:         a.Status().String(),
+		TeamID:         copyUint(a.TeamID()),
+		ParentID:       copyUint(a.ParentID()),
+		PayoutCurrency: a.PayoutCurrency(),
+		CreatedAt:      a.CreatedAt(),
+		UpdatedAt:      a.UpdatedAt(),
+	}
+}
+
+// reconstituteAgent rebuilds an agent with their total earnings derived
+// from the ledger. Callers must hold the read lock.
+func (s *Store) reconstituteAgent(params agent.AgentParams) *agent.Agent {
+	params.TeamID = copyUint(params.TeamID)
+	params.ParentID = copyUint(params.ParentID)
+	params.TotalEarned = s.balances(params.ID).Earned(params.PayoutCurrency)
+	return agent.Reconstitute(params)
+}
+
+// GetByID retrieves an agent by ID
+func (r *agentRepository) GetByID(ctx context.Context, id uint) (*agent.Agent, error) {
+	return r.first(ctx, func(p agent.AgentParams) bool { return p.ID == id })
+}
+
+// GetByEmail retrieves an agent by email
+func (r *agentRepository) GetByEmail(ctx context.Context, email string) (*agent.Agent, error) {
+	return r.first(ctx, func(p agent.AgentParams) bool { return p.Email == email })
+}
+
+// GetByCode retrieves an agent by agent code
+func (r *agentRepository) GetByCode(ctx context.Context, code string) (*agent.Agent, error) {
+	return r.first(ctx, func(p agent.AgentParams) bool { return p.Code == code })
+}
+
+func (r *agentRepository) first(ctx context.Context, match func(agent.AgentParams) bool) (*agent.Agent, error) {
+	defer r.store.rlock(ctx)()
+
+	for _, params := range r.store.agents {
+		if match(params) {
+			return r.store.reconstituteAgent(params), nil
+		}
+	}
+	return nil, agent.ErrAgentNotFound
+}
+
+// List retrieves agents matching the filter, newest first
+func (r *agentRepository) List(ctx context.Context, filter repository.AgentFilter) ([]*agent.Agent, int64, error) {
+	defer r.store.rlock(ctx)()
+
+	var rows []agent.AgentParams
+	for _, params := range r.store.agents {
+		if filter.Status != "" {
+			if params.Status != filter.Status {
+				continue
+			}
+		} else if !filter.IncludeInactive && params.Status == shared.AgentStatusInactive.String() {
+			continue
+		}
+		if filter.Tier != "" && params.Tier != filter.Tier {
+			continue
+		}
+		if filter.ParentID != nil && (params.ParentID == nil || *params.ParentID != *filter.ParentID) {
+			continue
+		}
+		if filter.Search != "" &&
+			!containsFold(params.Name, filter.Search) &&
+			!containsFold(params.Email, filter.Search) &&
+			!containsFold(params.Code, filter.Search) {
+			continue
+		}
+		rows = append(rows, params)
+	}
+
+	newestFirst(rows,
+		func(p agent.AgentParams) time.Time { return p.CreatedAt },
+		func(p agent.AgentParams) uint { return p.ID })
+
+	page := paginate(rows, filter.Page, filter.Limit)
+	agents := make([]*agent.Agent, len(page))
+	for i, params := range page {
+		agents[i] = r.store.reconstituteAgent(params)
+	}
+	return agents, int64(len(rows)), nil
+}
+
+// GetStats retrieves commission, payout and customer statistics for an agent
+func (r *agentRepository) GetStats(ctx context.Context, agentID uint) (*repository.AgentStats, error) {
+	defer r.store.rlock(ctx)()
+
+	now := time.Now()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	stats := &repository.AgentStats{}
+	orders := make(map[string]struct{})
+	var total, pending, paid, thisMonth shared.Money
+	for _, c := range r.store.commissions {
+		if c.AgentID != agentID {
+			continue
+		}
+		net := netPayoutAmount(c)
+		stats.TotalCommissions++
+		total = addAmount(total, net)
+		switch shared.CommissionStatus(c.Status) {
+		case shared.CommissionPending:
+			stats.PendingCommissions++
+			pending = addAmount(pending, net)
+		case shared.CommissionPaid, shared.CommissionPartiallyReversed:
+			paid = addAmount(paid, net)
+		}
+		if !c.CreatedAt.Before(monthStart) {
+			thisMonth = addAmount(thisMonth, net)
+		}
+		orders[c.OrderID] = struct{}{}
+	}
+	stats.TotalOrders = int64(len(orders))
+	stats.TotalCommission = total.Float64()
+	stats.PendingCommission = pending.Float64()
+	stats.PaidCommission = paid.Float64()
+	stats.ThisMonthCommission = thisMonth.Float64()
+
+	for _, p := range r.store.payouts {
+		if p.AgentID == agentID {
+			stats.TotalPayouts++
+		}
+	}
+	for _, c := range r.store.customers {
+		if c.AgentID != nil && *c.AgentID == agentID {
+			stats.TotalCustomers++
+		}
+	}
+
+	return stats, nil
+}
+
+// Create creates a new agent and assigns its ID and code
+func (r *agentRepository) Create(ctx context.Context, a *agent.Agent) error {
+	defer r.store.lock(ctx)()
+
+	params := agentParams(a)
+	params.ID = r.store.nextID("agents")
+	if params.Code == "" {
+		params.Code = fmt.Sprintf("AGT%04d", len(r.store.agents)+1)
+	}
+	touch(&params.CreatedAt, &params.UpdatedAt)
+	r.store.agents[params.ID] = params
+
+	a.AssignIdentity(params.ID, params.Code)
+	return nil
+}
+
+// Update saves all agent fields
+func (r *agentRepository) Update(ctx context.Context, a *agent.Agent) error {
+	defer r.store.lock(ctx)()
+
+	existing, ok := r.store.agents[a.ID()]
+	if !ok {
+		return agent.ErrAgentNotFound
+	}
+
+	params := agentParams(a)
+	params.CreatedAt = existing.CreatedAt
+	params.UpdatedAt = time.Now()
+	r.store.agents[params.ID] = params
+	return nil
+}
+
+// Delete soft deletes an agent by marking it inactive
+func (r *agentRepository) Delete(ctx context.Context, id uint) error {
+	return r.UpdateStatus(ctx, id, shared.AgentStatusInactive)
+}
+
+// UpdateStatus updates the status of an agent
+func (r *agentRepository) UpdateStatus(ctx context.Context, id uint, status shared.AgentStatus) error {
+	return r.update(ctx, id, func(p *agent.AgentParams) { p.Status = status.String() })
+}
+
+// UpdateTier updates the tier of an agent
+func (r *agentRepository) UpdateTier(ctx context.Context, id uint, tier shared.AgentTier) error {
+	return r.update(ctx, id, func(p *agent.AgentParams) { p.Tier = tier.String() })
+}
+
+func (r *agentRepository) update(ctx context.Context, id uint, apply func(*agent.AgentParams)) error {
+	defer r.store.lock(ctx)()
+
+	params, ok := r.store.agents[id]
+	if !ok {
+		return agent.ErrAgentNotFound
+	}
+	apply(&params)
+	params.UpdatedAt = time.Now()
+	r.store.agents[id] = params
+	return nil
+}
@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/commission"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+)
+
+// autoApprovalRepository implements repository.AutoApprovalRepository
+type autoApprovalRepository struct {
+	store *Store
+}
+
+// NewAutoApprovalRepository creates a new in-memory auto-approval repository
+func NewAutoApprovalRepository(store *Store) repository.AutoApprovalRepository {
+	return &autoApprovalRepository{store: store}
+}
+
+func reconstituteAutoApproval(params commission.AutoApprovalParams) *commission.AutoApproval {
+	params.Reasons = append([]string(nil), params.Reasons...)
+	return commission.ReconstituteAutoApproval(params)
+}
+
+// GetByCommissionID retrieves a commission's auto-approval
+func (r *autoApprovalRepository) GetByCommissionID(ctx context.Context, commissionID uint) (*commission.AutoApproval, error) {
+	defer r.store.rlock(ctx)()
+
+	for _, params := range r.store.autoApprovals {
+		if params.CommissionID == commissionID {
+			return reconstituteAutoApproval(params), nil
+		}
+	}
+	return nil, commission.ErrAutoApprovalNotFound
+}
+
+// List retrieves auto-approvals, newest first
+func (r *autoApprovalRepository) List(ctx context.Context, page, limit int) ([]*commission.AutoApproval, int64, error) {
+	defer r.store.rlock(ctx)()
+
+	rows := make([]commission.AutoApprovalParams, 0, len(r.store.autoApprovals))
+	for _, params := range r.store.autoApprovals {
+		rows = append(rows, params)
+	}
+
+	newestFirst(rows,
+		func(p commission.AutoApprovalParams) time.Time { return p.ApprovedAt },
+		func(p commission.AutoApprovalParams) uint { return p.ID })
+
+	paged := paginate(rows, page, limit)
+	approvals := make([]*commission.AutoApproval, len(paged))
+	for i, params := range paged {
+		approvals[i] = reconstituteAutoApproval(params)
+	}
+	return approvals, int64(len(rows)), nil
+}
+
+// Create records an auto-approval and assigns its ID
+func (r *autoApprovalRepository) Create(ctx context.Context, a *commission.AutoApproval) error {
+	defer r.store.lock(ctx)()
+
+	for _, existing := range r.store.autoApprovals {
+		if existing.CommissionID == a.CommissionID() {
+			return repository.ErrDuplicate
+		}
+	}
+
+	params := commission.AutoApprovalParams{
+		ID:           r.store.nextID("commission_auto_approvals"),
+		CommissionID: a.CommissionID(),
+		AgentID:      a.AgentID(),
+		Rule:         a.Rule(),
+		Amount:       a.Amount(),
+		Reasons:      append([]string(nil), a.Reasons()...),
+		ApprovedAt:   a.ApprovedAt(),
+	}
+	r.store.autoApprovals[params.ID] = params
+
+	a.SetID(params.ID)
+	return nil
+}
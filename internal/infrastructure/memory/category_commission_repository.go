@@ -0,0 +1,164 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+)
+
+// categoryCommissionRepository implements repository.CategoryCommissionRepository
+type categoryCommissionRepository struct {
+	store *Store
+}
+
+// NewCategoryCommissionRepository creates a new in-memory category commission repository
+func NewCategoryCommissionRepository(store *Store) repository.CategoryCommissionRepository {
+	return &categoryCommissionRepository{store: store}
+}
+
+// GetByAgentID retrieves the category commissions in effect for an agent at a time
+func (r *categoryCommissionRepository) GetByAgentID(ctx context.Context, agentID uint, at time.Time) ([]domain.AgentCategoryCommission, error) {
+	defer r.store.rlock(ctx)()
+
+	return r.effectiveAt(agentID, at), nil
+}
+
+// GetHistory retrieves every category commission for an agent, oldest effective first
+func (r *categoryCommissionRepository) GetHistory(ctx context.Context, agentID uint) ([]domain.AgentCategoryCommission, error) {
+	defer r.store.rlock(ctx)()
+
+	var commissions []domain.AgentCategoryCommission
+	for _, cc := range r.store.categoryCommissions {
+		if cc.AgentID == agentID {
+			commissions = append(commissions, cc)
+		}
+	}
+	sort.Slice(commissions, func(i, j int) bool {
+		a, b := commissions[i], commissions[j]
+		if !a.EffectiveFrom.Equal(b.EffectiveFrom) {
+			return a.EffectiveFrom.Before(b.EffectiveFrom)
+		}
+		if a.CategoryID != b.CategoryID {
+			return a.CategoryID < b.CategoryID
+		}
+		return a.ID < b.ID
+	})
+	return commissions, nil
+}
+
+// GetByID retrieves a category commission by ID
+func (r *categoryCommissionRepository) GetByID(ctx context.Context, id uint) (*domain.AgentCategoryCommission, error) {
+	defer r.store.rlock(ctx)()
+
+	cc, ok := r.store.categoryCommissions[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &cc, nil
+}
+
+// Schedule replaces an agent's category commissions from effectiveFrom on
+func (r *categoryCommissionRepository) Schedule(ctx context.Context, agentID uint, commissions []domain.AgentCategoryCommission, effectiveFrom time.Time) error {
+	defer r.store.lock(ctx)()
+
+	// Replace anything already scheduled for the same time
+	for id, cc := range r.store.categoryCommissions {
+		if cc.AgentID == agentID && cc.EffectiveFrom.Equal(effectiveFrom) {
+			delete(r.store.categoryCommissions, id)
+		}
+	}
+
+	current := r.effectiveAt(agentID, effectiveFrom)
+
+	kept := make(map[string]bool, len(commissions))
+	for i := range commissions {
+		commissions[i].AgentID = agentID
+		commissions[i].ID = r.store.nextID("agent_category_commissions")
+		commissions[i].EffectiveFrom = effectiveFrom
+		commissions[i].CreatedAt = time.Time{}
+		r.save(&commissions[i])
+		kept[commissions[i].CategoryID] = true
+	}
+
+	// End the rates of categories left out
+	for _, cc := range current {
+		if !cc.IsActive || kept[cc.CategoryID] {
+			continue
+		}
+		r.save(&domain.AgentCategoryCommission{
+			ID:             r.store.nextID("agent_category_commissions"),
+			AgentID:        agentID,
+			CategoryID:     cc.CategoryID,
+			CategoryName:   cc.CategoryName,
+			CommissionRate: cc.CommissionRate,
+			EffectiveFrom:  effectiveFrom,
+		})
+	}
+	return nil
+}
+
+// Delete deletes a category commission
+func (r *categoryCommissionRepository) Delete(ctx context.Context, id uint) error {
+	defer r.store.lock(ctx)()
+
+	delete(r.store.categoryCommissions, id)
+	return nil
+}
+
+// effectiveAt returns the row in effect at a time for each of an agent's
+// categories. Callers must hold the lock.
+func (r *categoryCommissionRepository) effectiveAt(agentID uint, at time.Time) []domain.AgentCategoryCommission {
+	latest := make(map[string]domain.AgentCategoryCommission)
+	for _, cc := range r.store.categoryCommissions {
+		if cc.AgentID != agentID || cc.EffectiveFrom.After(at) {
+			continue
+		}
+		if current, ok := latest[cc.CategoryID]; !ok || categoryEffectiveBefore(current, cc) {
+			latest[cc.CategoryID] = cc
+		}
+	}
+
+	commissions := make([]domain.AgentCategoryCommission, 0, len(latest))
+	for _, cc := range latest {
+		commissions = append(commissions, cc)
+	}
+	sort.Slice(commissions, func(i, j int) bool { return commissions[i].CategoryID < commissions[j].CategoryID })
+	return commissions
+}
+
+// categoryRateAt finds the row in effect for an agent's category at a
+// time. Callers must hold the lock.
+func (s *Store) categoryRateAt(agentID uint, categoryID string, at time.Time) (domain.AgentCategoryCommission, bool) {
+	var found domain.AgentCategoryCommission
+	var ok bool
+	for _, cc := range s.categoryCommissions {
+		if cc.AgentID != agentID || cc.CategoryID != categoryID || cc.EffectiveFrom.After(at) {
+			continue
+		}
+		if !ok || categoryEffectiveBefore(found, cc) {
+			found, ok = cc, true
+		}
+	}
+	return found, ok
+}
+
+// categoryEffectiveBefore orders category rows by when they take effect,
+// breaking ties by ID so the later row wins
+func categoryEffectiveBefore(a, b domain.AgentCategoryCommission) bool {
+	if !a.EffectiveFrom.Equal(b.EffectiveFrom) {
+		return a.EffectiveFrom.Before(b.EffectiveFrom)
+	}
+	return a.ID < b.ID
+}
+
+// save stores a copy of the row without its relations. Callers must hold
+// the write lock.
+func (r *categoryCommissionRepository) save(commission *domain.AgentCategoryCommission) {
+	touch(&commission.CreatedAt, &commission.UpdatedAt)
+	row := *commission
+	row.Agent = domain.Agent{}
+	r.store.categoryCommissions[row.ID] = row
+}
@@ -0,0 +1,117 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/commission"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+)
+
+// clawbackRepository implements repository.ClawbackRepository
+type clawbackRepository struct {
+	store *Store
+}
+
+// NewClawbackRepository creates a new in-memory clawback repository
+func NewClawbackRepository(store *Store) repository.ClawbackRepository {
+	return &clawbackRepository{store: store}
+}
+
+// clawbackParams captures the stored state of a clawback
+func clawbackParams(c *commission.Clawback) commission.ClawbackParams {
+	return commission.ClawbackParams{
+		ID:           c.ID(),
+		CommissionID: c.CommissionID(),
+		AgentID:      c.AgentID(),
+		OrderID:      c.OrderID(),
+		RefundID:     c.RefundID(),
+		Amount:       c.Amount(),
+		RefundAmount: c.RefundAmount(),
+		PayoutAmount: c.PayoutAmount(),
+		Reason:       c.Reason(),
+		Status:       c.Status().String(),
+		PayoutID:     copyUint(c.PayoutID()),
+		Version:      c.Version(),
+		CreatedAt:    c.CreatedAt(),
+		UpdatedAt:    c.UpdatedAt(),
+	}
+}
+
+// GetByCommissionID retrieves a commission's clawbacks, oldest first
+func (r *clawbackRepository) GetByCommissionID(ctx context.Context, commissionID uint) ([]*commission.Clawback, error) {
+	return r.find(ctx, func(p commission.ClawbackParams) bool {
+		return p.CommissionID == commissionID
+	}), nil
+}
+
+// GetOutstanding retrieves an agent's unrecovered clawbacks, oldest first
+func (r *clawbackRepository) GetOutstanding(ctx context.Context, agentID uint) ([]*commission.Clawback, error) {
+	return r.find(ctx, func(p commission.ClawbackParams) bool {
+		return p.AgentID == agentID && p.Status == shared.ClawbackOutstanding.String()
+	}), nil
+}
+
+// GetByPayoutID retrieves the clawbacks recovered from a payout, oldest
+// first
+func (r *clawbackRepository) GetByPayoutID(ctx context.Context, payoutID uint) ([]*commission.Clawback, error) {
+	return r.find(ctx, func(p commission.ClawbackParams) bool {
+		return p.PayoutID != nil && *p.PayoutID == payoutID
+	}), nil
+}
+
+func (r *clawbackRepository) find(ctx context.Context, match func(commission.ClawbackParams) bool) []*commission.Clawback {
+	defer r.store.rlock(ctx)()
+
+	var rows []commission.ClawbackParams
+	for _, params := range r.store.clawbacks {
+		if match(params) {
+			rows = append(rows, params)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if !rows[i].CreatedAt.Equal(rows[j].CreatedAt) {
+			return rows[i].CreatedAt.Before(rows[j].CreatedAt)
+		}
+		return rows[i].ID < rows[j].ID
+	})
+
+	clawbacks := make([]*commission.Clawback, len(rows))
+	for i, params := range rows {
+		params.PayoutID = copyUint(params.PayoutID)
+		clawbacks[i] = commission.ReconstituteClawback(params)
+	}
+	return clawbacks
+}
+
+// Create creates a new clawback and assigns its ID
+func (r *clawbackRepository) Create(ctx context.Context, c *commission.Clawback) error {
+	defer r.store.lock(ctx)()
+
+	params := clawbackParams(c)
+	params.ID = r.store.nextID("commission_clawbacks")
+	touch(&params.CreatedAt, &params.UpdatedAt)
+	r.store.clawbacks[params.ID] = params
+
+	c.SetID(params.ID)
+	return nil
+}
+
+// Update saves all clawback fields
+func (r *clawbackRepository) Update(ctx context.Context, c *commission.Clawback) error {
+	defer r.store.lock(ctx)()
+
+	existing, ok := r.store.clawbacks[c.ID()]
+	if !ok {
+		return commission.ErrClawbackNotFound
+	}
+	if existing.Version != c.Version() {
+		return repository.ErrConflict
+	}
+	params := clawbackParams(c)
+	params.Version++
+	r.store.clawbacks[c.ID()] = params
+	c.SetVersion(params.Version)
+	return nil
+}
@@ -0,0 +1,328 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/agent"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/commission"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+)
+
+// commissionRepository implements repository.CommissionRepository
+type commissionRepository struct {
+	store *Store
+}
+
+// NewCommissionRepository creates a new in-memory commission repository
+func NewCommissionRepository(store *Store) repository.CommissionRepository {
+	return &commissionRepository{store: store}
+}
+
+// commissionParams captures the stored state of a commission
+func commissionParams(c *commission.Commission) commission.CommissionParams {
+	return commission.CommissionParams{
+		ID:         c.ID(),
+		AgentID:    c.AgentID(),
+		OrderID:    c.OrderID(),
+		OrderTotal: c.OrderTotal(),
+		Rate:       c.Rate().Value(),
+		Amount:     c.Amount(),
+		Status:     c.Status().String(),
+		CreatedAt:  c.CreatedAt(),
+		UpdatedAt:  c.UpdatedAt(),
+
+		StatusReason:    c.StatusReason(),
+		StatusChangedBy: c.StatusChangedBy(),
+
+		PayoutAmount: c.PayoutAmount(),
+		FXRate:       c.FXRate().String(),
+
+		ReversedAmount: c.ReversedAmount(),
+		SplitGroupID:   c.SplitGroupID(),
+		SplitShare:     c.SplitShare(),
+
+		Type:               c.Type().String(),
+		SourceCommissionID: copyUint(c.SourceCommissionID()),
+		Level:              c.Level(),
+
+		Category:  c.Category().String(),
+		Note:      c.Note(),
+		CreatedBy: c.CreatedBy(),
+
+		Version: c.Version(),
+	}
+}
+
+// commissionType returns the stored type, which defaults to a sale
+func commissionType(p commission.CommissionParams) string {
+	if p.Type == "" {
+		return shared.CommissionTypeSale.String()
+	}
+	return p.Type
+}
+
+// netPayoutAmount returns the stored commission's net amount in its payout
+// currency
+func netPayoutAmount(p commission.CommissionParams) shared.Money {
+	return reconstituteCommission(p).NetPayoutAmount()
+}
+
+// addAmount adds an amount to a total the way the SQL sums do: an amount in
+// another currency, such as a former payout currency, is added as it is.
+func addAmount(total, amount shared.Money) shared.Money {
+	if !total.SameCurrency(amount) {
+		amount = shared.MoneyFromFloat(amount.Float64(), total.Currency())
+	}
+	return total.Add(amount)
+}
+
+func reconstituteCommission(params commission.CommissionParams) *commission.Commission {
+	params.SourceCommissionID = copyUint(params.SourceCommissionID)
+	return commission.Reconstitute(params)
+}
+
+// GetByID retrieves a commission by ID
+func (r *commissionRepository) GetByID(ctx context.Context, id uint) (*commission.Commission, error) {
+	defer r.store.rlock(ctx)()
+
+	params, ok := r.store.commissions[id]
+	if !ok {
+		return nil, commission.ErrCommissionNotFound
+	}
+	return reconstituteCommission(params), nil
+}
+
+// GetByAgentID retrieves an agent's commissions matching the filter, newest first
+func (r *commissionRepository) GetByAgentID(ctx context.Context, agentID uint, filter repository.CommissionFilter) ([]*commission.Commission, int64, error) {
+	return r.list(ctx, func(p commission.CommissionParams) bool {
+		return p.AgentID == agentID &&
+			(filter.Status == "" || p.Status == filter.Status) &&
+			(filter.Type == "" || commissionType(p) == filter.Type) &&
+			inPeriod(p.CreatedAt, filter.Period)
+	}, filter.Page, filter.Limit)
+}
+
+// GetByOrderID retrieves all commissions for an order
+func (r *commissionRepository) GetByOrderID(ctx context.Context, orderID string) ([]*commission.Commission, error) {
+	commissions, _, err := r.list(ctx, func(p commission.CommissionParams) bool {
+		return p.OrderID == orderID
+	}, 0, 0)
+	return commissions, err
+}
+
+// GetBySourceID retrieves the commissions derived from a sale commission
+func (r *commissionRepository) GetBySourceID(ctx context.Context, sourceCommissionID uint) ([]*commission.Commission, error) {
+	commissions, _, err := r.list(ctx, func(p commission.CommissionParams) bool {
+		return p.SourceCommissionID != nil && *p.SourceCommissionID == sourceCommissionID
+	}, 0, 0)
+	return commissions, err
+}
+
+// GetPending retrieves all pending commissions, newest first
+func (r *commissionRepository) GetPending(ctx context.Context, page, limit int) ([]*commission.Commission, int64, error) {
+	return r.list(ctx, func(p commission.CommissionParams) bool {
+		return p.Status == shared.CommissionPending.String()
+	}, page, limit)
+}
+
+func (r *commissionRepository) list(ctx context.Context, match func(commission.CommissionParams) bool, page, limit int) ([]*commission.Commission, int64, error) {
+	defer r.store.rlock(ctx)()
+
+	var rows []commission.CommissionParams
+	for _, params := range r.store.commissions {
+		if match(params) {
+			rows = append(rows, params)
+		}
+	}
+
+	newestFirst(rows,
+		func(p commission.CommissionParams) time.Time { return p.CreatedAt },
+		func(p commission.CommissionParams) uint { return p.ID })
+
+	paged := paginate(rows, page, limit)
+	commissions := make([]*commission.Commission, len(paged))
+	for i, params := range paged {
+		commissions[i] = reconstituteCommission(params)
+	}
+	return commissions, int64(len(rows)), nil
+}
+
+// GetSummary aggregates an agent's commission amounts by status within a period
+func (r *commissionRepository) GetSummary(ctx context.Context, agentID uint, period repository.Period) (*repository.CommissionSummary, error) {
+	defer r.store.rlock(ctx)()
+
+	type totals struct{ total, pending, approved, paid shared.Money }
+	summary := &repository.CommissionSummary{}
+	byCurrency := make(map[string]*totals)
+	for _, c := range r.store.commissions {
+		if c.AgentID != agentID || !inPeriod(c.CreatedAt, period) {
+			continue
+		}
+		net := netPayoutAmount(c)
+		t, ok := byCurrency[net.Currency()]
+		if !ok {
+			t = &totals{}
+			byCurrency[net.Currency()] = t
+		}
+		summary.Count++
+		t.total = t.total.Add(net)
+		switch shared.CommissionStatus(c.Status) {
+		case shared.CommissionPending:
+			summary.PendingCount++
+			t.pending = t.pending.Add(net)
+		case shared.CommissionApproved, shared.CommissionInPayout:
+			t.approved = t.approved.Add(net)
+		case shared.CommissionPaid, shared.CommissionPartiallyReversed:
+			t.paid = t.paid.Add(net)
+		}
+	}
+
+	for currency, t := range byCurrency {
+		summary.Totals = append(summary.Totals, domain.CommissionTotals{
+			Currency: currency,
+			Total:    t.total.Float64(),
+			Pending:  t.pending.Float64(),
+			Approved: t.approved.Float64(),
+			Paid:     t.paid.Float64(),
+		})
+	}
+	sort.Slice(summary.Totals, func(i, j int) bool { return summary.Totals[i].Currency < summary.Totals[j].Currency })
+	return summary, nil
+}
+
+// GetSalesSummary aggregates the sales behind an agent's sale commissions
+// within a period, net of refunds
+func (r *commissionRepository) GetSalesSummary(ctx context.Context, agentID uint, period repository.Period) (*repository.SalesSummary, error) {
+	defer r.store.rlock(ctx)()
+
+	summary := &repository.SalesSummary{}
+	orders := make(map[string]struct{})
+	var sales shared.Money
+	for _, params := range r.store.commissions {
+		if params.AgentID != agentID || commissionType(params) != shared.CommissionTypeSale.String() ||
+			!inPeriod(params.CreatedAt, period) {
+			continue
+		}
+		c := reconstituteCommission(params)
+		if c.Status().IsTerminal() {
+			continue
+		}
+		if c.Amount().IsPositive() {
+			share := c.ShareOfOrder()
+			if converted, err := c.FXRate().Convert(share); err == nil {
+				share = converted
+			}
+			sales = addAmount(sales, share.MulRatio(c.NetAmount().Minor(), c.Amount().Minor(), shared.RoundHalfUp))
+		}
+		orders[c.OrderID()] = struct{}{}
+	}
+	summary.Orders = int64(len(orders))
+	summary.Sales = sales.Float64()
+	return summary, nil
+}
+
+// Create creates a new commission and assigns its ID
+func (r *commissionRepository) Create(ctx context.Context, c *commission.Commission) error {
+	defer r.store.lock(ctx)()
+
+	// One sale commission per agent per order, and one derived
+	// commission of each type per agent per source commission
+	source := c.SourceCommissionID()
+	sale := c.Type() == shared.CommissionTypeSale
+	for _, existing := range r.store.commissions {
+		if existing.AgentID != c.AgentID() {
+			continue
+		}
+		if sale && commissionType(existing) == shared.CommissionTypeSale.String() && existing.OrderID == c.OrderID() {
+			return repository.ErrDuplicate
+		}
+		if source != nil && existing.SourceCommissionID != nil && *existing.SourceCommissionID == *source &&
+			commissionType(existing) == c.Type().String() {
+			return repository.ErrDuplicate
+		}
+	}
+
+	params := commissionParams(c)
+	params.ID = r.store.nextID("commissions")
+	touch(&params.CreatedAt, &params.UpdatedAt)
+	r.store.commissions[params.ID] = params
+
+	c.SetID(params.ID)
+	r.store.appendLedger(c.LedgerEntries())
+	return nil
+}
+
+// Update saves all commission fields and the ledger entries for its
+// transitions. It refuses a commission saved by someone else since it was
+// read, so the ledger entries are never posted twice.
+func (r *commissionRepository) Update(ctx context.Context, c *commission.Commission) error {
+	defer r.store.lock(ctx)()
+
+	existing, ok := r.store.commissions[c.ID()]
+	if !ok {
+		return commission.ErrCommissionNotFound
+	}
+	if existing.Version != c.Version() {
+		return repository.ErrConflict
+	}
+
+	params := commissionParams(c)
+	params.Version++
+	params.CreatedAt = existing.CreatedAt
+	params.UpdatedAt = time.Now()
+	r.store.commissions[params.ID] = params
+	r.store.appendLedger(c.LedgerEntries())
+	c.SetVersion(params.Version)
+	return nil
+}
+
+// UpdateStatus moves the commissions that are still in status from to
+// status to, and returns how many moved
+func (r *commissionRepository) UpdateStatus(ctx context.Context, ids []uint, from, to shared.CommissionStatus) (int, error) {
+	defer r.store.lock(ctx)()
+
+	now := time.Now()
+	moved := 0
+	for _, id := range ids {
+		params, ok := r.store.commissions[id]
+		if !ok || params.Status != from.String() {
+			continue
+		}
+		params.Status = to.String()
+		params.Version++
+		params.UpdatedAt = now
+		r.store.commissions[id] = params
+		moved++
+	}
+	return moved, nil
+}
+
+// CalculateCommission calculates the commission an agent earns on an order
+// amount at the rates in effect at the given time, using the agent's
+// category rate when one was active.
+func (r *commissionRepository) CalculateCommission(ctx context.Context, agentID uint, orderAmount shared.Money, categoryID *string, at time.Time) (shared.Money, error) {
+	defer r.store.rlock(ctx)()
+
+	agentParams, ok := r.store.agents[agentID]
+	if !ok {
+		return shared.Money{}, agent.ErrAgentNotFound
+	}
+
+	if categoryID != nil {
+		if cc, ok := r.store.categoryRateAt(agentID, *categoryID, at); ok && cc.IsActive {
+			return orderAmount.MulPercent(cc.CommissionRate, shared.RoundHalfUp), nil
+		}
+	}
+
+	// Agents with no rate history earn at their current rate
+	rate := agentParams.CommissionRate
+	if change, ok := r.store.rateAt(commission.RateScopeAgent, agentID, at); ok {
+		rate = change.Rate
+	}
+
+	return orderAmount.MulPercent(rate, shared.RoundHalfUp), nil
+}
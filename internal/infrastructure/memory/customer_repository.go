@@ -0,0 +1,111 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+)
+
+// customerRepository implements repository.CustomerRepository
+type customerRepository struct {
+	store *Store
+}
+
+// NewCustomerRepository creates a new in-memory customer repository
+func NewCustomerRepository(store *Store) repository.CustomerRepository {
+	return &customerRepository{store: store}
+}
+
+func ownedBy(customer domain.Customer, agentID uint) bool {
+	return customer.AgentID != nil && *customer.AgentID == agentID
+}
+
+func copyCustomer(customer domain.Customer) domain.Customer {
+	customer.AgentID = copyUint(customer.AgentID)
+	customer.LastOrderAt = copyTime(customer.LastOrderAt)
+	return customer
+}
+
+// GetByID retrieves one of an agent's customers
+func (r *customerRepository) GetByID(ctx context.Context, agentID, id uint) (*domain.Customer, error) {
+	defer r.store.rlock(ctx)()
+
+	customer, ok := r.store.customers[id]
+	if !ok || !ownedBy(customer, agentID) {
+		return nil, repository.ErrNotFound
+	}
+	customer = copyCustomer(customer)
+	return &customer, nil
+}
+
+// List retrieves an agent's customers, optionally matching a name or email search
+func (r *customerRepository) List(ctx context.Context, agentID uint, search string, page, limit int) ([]domain.Customer, int64, error) {
+	defer r.store.rlock(ctx)()
+
+	var rows []domain.Customer
+	for _, customer := range r.store.customers {
+		if !ownedBy(customer, agentID) {
+			continue
+		}
+		if search != "" && !containsFold(customer.Name, search) && !containsFold(customer.Email, search) {
+			continue
+		}
+		rows = append(rows, copyCustomer(customer))
+	}
+
+	newestFirst(rows,
+		func(c domain.Customer) time.Time { return c.CreatedAt },
+		func(c domain.Customer) uint { return c.ID })
+
+	return paginate(rows, page, limit), int64(len(rows)), nil
+}
+
+// CountByAgentID counts an agent's customers
+func (r *customerRepository) CountByAgentID(ctx context.Context, agentID uint) (int64, error) {
+	defer r.store.rlock(ctx)()
+
+	var total int64
+	for _, customer := range r.store.customers {
+		if ownedBy(customer, agentID) {
+			total++
+		}
+	}
+	return total, nil
+}
+
+// CountActive counts an agent's customers who ordered since the given time
+func (r *customerRepository) CountActive(ctx context.Context, agentID uint, since time.Time) (int64, error) {
+	defer r.store.rlock(ctx)()
+
+	var total int64
+	for _, customer := range r.store.customers {
+		if ownedBy(customer, agentID) && customer.LastOrderAt != nil && !customer.LastOrderAt.Before(since) {
+			total++
+		}
+	}
+	return total, nil
+}
+
+// Create creates a new customer
+func (r *customerRepository) Create(ctx context.Context, customer *domain.Customer) error {
+	defer r.store.lock(ctx)()
+
+	customer.ID = r.store.nextID("customers")
+	touch(&customer.CreatedAt, &customer.UpdatedAt)
+	r.store.customers[customer.ID] = copyCustomer(*customer)
+	return nil
+}
+
+// Update saves all customer fields
+func (r *customerRepository) Update(ctx context.Context, customer *domain.Customer) error {
+	defer r.store.lock(ctx)()
+
+	if _, ok := r.store.customers[customer.ID]; !ok {
+		return repository.ErrNotFound
+	}
+	customer.UpdatedAt = time.Now()
+	r.store.customers[customer.ID] = copyCustomer(*customer)
+	return nil
+}
@@ -0,0 +1,133 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/dispute"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+)
+
+// disputeRepository implements repository.DisputeRepository
+type disputeRepository struct {
+	store *Store
+}
+
+// NewDisputeRepository creates a new in-memory dispute repository
+func NewDisputeRepository(store *Store) repository.DisputeRepository {
+	return &disputeRepository{store: store}
+}
+
+func disputeParams(d *dispute.Dispute) dispute.DisputeParams {
+	return dispute.DisputeParams{
+		ID:                     d.ID(),
+		AgentID:                d.AgentID(),
+		OrderID:                d.OrderID(),
+		CommissionID:           copyUint(d.CommissionID()),
+		Messages:               append([]dispute.Message(nil), d.Messages()...),
+		Status:                 d.Status().String(),
+		Resolution:             d.Resolution(),
+		ResolvedBy:             d.ResolvedBy(),
+		ResolvedAt:             copyTime(d.ResolvedAt()),
+		AdjustmentCommissionID: copyUint(d.AdjustmentCommissionID()),
+		CreatedAt:              d.CreatedAt(),
+		UpdatedAt:              d.UpdatedAt(),
+	}
+}
+
+func reconstituteDispute(params dispute.DisputeParams) *dispute.Dispute {
+	params.CommissionID = copyUint(params.CommissionID)
+	params.Messages = append([]dispute.Message(nil), params.Messages...)
+	params.ResolvedAt = copyTime(params.ResolvedAt)
+	params.AdjustmentCommissionID = copyUint(params.AdjustmentCommissionID)
+	return dispute.Reconstitute(params)
+}
+
+// GetByID retrieves a dispute and its thread by ID
+func (r *disputeRepository) GetByID(ctx context.Context, id uint) (*dispute.Dispute, error) {
+	defer r.store.rlock(ctx)()
+
+	params, ok := r.store.disputes[id]
+	if !ok {
+		return nil, dispute.ErrDisputeNotFound
+	}
+	return reconstituteDispute(params), nil
+}
+
+// List retrieves the disputes matching the filter, newest first
+func (r *disputeRepository) List(ctx context.Context, filter repository.DisputeFilter) ([]*dispute.Dispute, int64, error) {
+	defer r.store.rlock(ctx)()
+
+	rows := make([]dispute.DisputeParams, 0)
+	for _, params := range r.store.disputes {
+		if (filter.AgentID == 0 || params.AgentID == filter.AgentID) &&
+			(filter.Status == "" || params.Status == filter.Status) {
+			rows = append(rows, params)
+		}
+	}
+
+	newestFirst(rows,
+		func(p dispute.DisputeParams) time.Time { return p.CreatedAt },
+		func(p dispute.DisputeParams) uint { return p.ID })
+
+	paged := paginate(rows, filter.Page, filter.Limit)
+	disputes := make([]*dispute.Dispute, len(paged))
+	for i, params := range paged {
+		disputes[i] = reconstituteDispute(params)
+	}
+	return disputes, int64(len(rows)), nil
+}
+
+// HasActive reports whether the agent has an open or under review dispute
+// on the order
+func (r *disputeRepository) HasActive(ctx context.Context, agentID uint, orderID string) (bool, error) {
+	defer r.store.rlock(ctx)()
+
+	for _, params := range r.store.disputes {
+		if params.AgentID == agentID && params.OrderID == orderID && shared.DisputeStatus(params.Status).IsActive() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Create creates a new dispute with its thread and assigns their IDs
+func (r *disputeRepository) Create(ctx context.Context, d *dispute.Dispute) error {
+	defer r.store.lock(ctx)()
+
+	d.SetID(r.store.nextID("commission_disputes"))
+	r.saveMessages(d)
+
+	params := disputeParams(d)
+	touch(&params.CreatedAt, &params.UpdatedAt)
+	r.store.disputes[params.ID] = params
+	return nil
+}
+
+// Update saves all dispute fields and the messages added since it was loaded
+func (r *disputeRepository) Update(ctx context.Context, d *dispute.Dispute) error {
+	defer r.store.lock(ctx)()
+
+	existing, ok := r.store.disputes[d.ID()]
+	if !ok {
+		return dispute.ErrDisputeNotFound
+	}
+	r.saveMessages(d)
+
+	params := disputeParams(d)
+	params.CreatedAt = existing.CreatedAt
+	params.UpdatedAt = time.Now()
+	r.store.disputes[params.ID] = params
+	return nil
+}
+
+// saveMessages assigns IDs to the messages in the thread that are not
+// stored yet. Callers must hold the write lock.
+func (r *disputeRepository) saveMessages(d *dispute.Dispute) {
+	for i, m := range d.Messages() {
+		if m.IsNew() {
+			d.SetMessageID(i, r.store.nextID("commission_dispute_messages"))
+		}
+	}
+}
@@ -0,0 +1,306 @@
+package memory
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/agent"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/commission"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/fx"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/ledger"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/payout"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/tax"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/team"
+)
+
+// fixtureAgent describes a seeded agent and the auth user it logs in as
+type fixtureAgent struct {
+	params agent.AgentParams
+	userID string
+}
+
+// Seed fills the store with a small, consistent data set for running the
+// agent portal locally: one team, four agents (one inactive), customers,
+// four months of orders with their commissions, completed payouts, the
+// opening ledger balances they add up to, and a verified bank account for
+// each active agent.
+//
+// Seeded agents log in with a JWT carrying their email and role "agent".
+func Seed(s *Store) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	createdAt := monthStart.AddDate(0, -6, 0)
+
+	teamID := s.nextID("teams")
+
+	agents := []fixtureAgent{
+		{
+			params: agent.AgentParams{Name: "Aisyah Rahman", Email: "aisyah@example.com", Phone: "012-3456789",
+				CommissionRate: 12, Tier: shared.TierGold.String(), Status: shared.AgentStatusActive.String(), TeamID: &teamID},
+			userID: "8f14e45f-ceea-4e6a-9f3c-1a2b3c4d5e01",
+		},
+		{
+			params: agent.AgentParams{Name: "Hafiz Ismail", Email: "hafiz@example.com", Phone: "013-2345678",
+				CommissionRate: 10, Tier: shared.TierSilver.String(), Status: shared.AgentStatusActive.String(), TeamID: &teamID},
+			userID: "8f14e45f-ceea-4e6a-9f3c-1a2b3c4d5e02",
+		},
+		{
+			params: agent.AgentParams{Name: "Tan Mei Ling", Email: "meiling@example.com", Phone: "017-8765432",
+				CommissionRate: 8, Tier: shared.TierBronze.String(), Status: shared.AgentStatusActive.String()},
+			userID: "8f14e45f-ceea-4e6a-9f3c-1a2b3c4d5e03",
+		},
+		{
+			params: agent.AgentParams{Name: "Ravi Kumar", Email: "ravi@example.com", Phone: "019-1122334",
+				CommissionRate: 8, Tier: shared.TierBronze.String(), Status: shared.AgentStatusInactive.String()},
+			userID: "8f14e45f-ceea-4e6a-9f3c-1a2b3c4d5e04",
+		},
+	}
+
+	for i := range agents {
+		params := agents[i].params
+		params.ID = s.nextID("agents")
+		params.Code = fmt.Sprintf("AGT%04d", params.ID)
+		params.CreatedAt = createdAt.AddDate(0, 0, i)
+		params.UpdatedAt = params.CreatedAt
+		params.PayoutCurrency = shared.DefaultCurrency
+		s.agents[params.ID] = params
+		s.users[params.Email] = user{ID: agents[i].userID, Role: "agent"}
+		agents[i].params = params
+	}
+
+	// Aisyah sponsored Hafiz, who sponsored Mei Ling
+	for _, link := range [][2]int{{1, 0}, {2, 1}} {
+		child, parent := &agents[link[0]].params, agents[link[1]].params.ID
+		child.ParentID = &parent
+		s.agents[child.ID] = *child
+	}
+
+	leaderID := agents[0].params.ID
+	s.teams[teamID] = team.TeamParams{
+		ID:             teamID,
+		Code:           "TEAM-KL",
+		Name:           "Pasukan Kuala Lumpur",
+		Description:    "Klang Valley sales team",
+		LeaderID:       &leaderID,
+		TargetMonthly:  20000,
+		CommissionRate: 2,
+		IsActive:       true,
+		CreatedAt:      createdAt,
+		UpdatedAt:      createdAt,
+	}
+
+	// Rate history: every agent and the team have had their rate since they
+	// were created, and Mei Ling has a raise scheduled for next month
+	for _, fa := range agents {
+		s.seedRateChange(commission.RateScopeAgent, fa.params.ID, fa.params.CommissionRate, fa.params.CreatedAt)
+	}
+	s.seedRateChange(commission.RateScopeTeam, teamID, 2, createdAt)
+	s.seedRateChange(commission.RateScopeAgent, agents[2].params.ID, 9, monthStart.AddDate(0, 1, 0))
+
+	customers := []domain.Customer{
+		{Name: "Nurul Huda", Email: "nurul.huda@example.com", Phone: "011-2233445", City: "Shah Alam", State: "Selangor", Postcode: "40000"},
+		{Name: "Lim Wei Jie", Email: "weijie@example.com", Phone: "016-5566778", City: "Petaling Jaya", State: "Selangor", Postcode: "46000"},
+		{Name: "Siti Aminah", Email: "siti.aminah@example.com", Phone: "014-9988776", City: "Kuala Lumpur", State: "WP Kuala Lumpur", Postcode: "50450"},
+	}
+	customerAgents := []uint{agents[0].params.ID, agents[0].params.ID, agents[1].params.ID}
+	for i := range customers {
+		customer := customers[i]
+		agentID := customerAgents[i]
+		customer.ID = s.nextID("customers")
+		customer.AgentID = &agentID
+		customer.CreatedAt = createdAt.AddDate(0, 1, i)
+		customer.UpdatedAt = customer.CreatedAt
+		lastOrderAt := now.AddDate(0, 0, -7*(i+1))
+		customer.LastOrderAt = &lastOrderAt
+		s.customers[customer.ID] = customer
+	}
+
+	// Orders and commissions for the last four months. Older months are
+	// paid out, last month is approved and this month is still pending.
+	orderTotals := []float64{450, 1280, 320}
+	paidItems := make(map[uint][]payout.PayoutItem)
+	for i, fa := range agents[:3] {
+		for month := 3; month >= 0; month-- {
+			placedAt := monthStart.AddDate(0, -month, 3+i*5)
+			if placedAt.After(now) {
+				placedAt = now.Add(-time.Hour)
+			}
+			total := orderTotals[(i+month)%len(orderTotals)]
+
+			orderID := fmt.Sprintf("5b2d7c1e-0000-4000-8000-%012d", len(s.orders)+1)
+			s.orders[orderID] = domain.Order{
+				ID:              orderID,
+				OrderNumber:     fmt.Sprintf("ORD-%s-%04d", placedAt.Format("200601"), len(s.orders)+1),
+				AgentID:         fa.userID,
+				CustomerID:      fmt.Sprintf("5b2d7c1e-0000-4000-9000-%012d", i+1),
+				CustomerName:    customers[i].Name,
+				CustomerEmail:   customers[i].Email,
+				Subtotal:        total,
+				Total:           total,
+				Status:          "delivered",
+				PaymentStatus:   "paid",
+				AgentCommission: domain.CalculateCommission(total, fa.params.CommissionRate),
+				CreatedAt:       placedAt,
+				UpdatedAt:       placedAt,
+			}
+
+			status := shared.CommissionPaid
+			switch month {
+			case 1:
+				status = shared.CommissionApproved
+			case 0:
+				status = shared.CommissionPending
+			}
+
+			c := commission.CommissionParams{
+				ID:         s.nextID("commissions"),
+				AgentID:    fa.params.ID,
+				OrderID:    orderID,
+				OrderTotal: shared.MoneyFromFloat(total, shared.DefaultCurrency),
+				Rate:       fa.params.CommissionRate,
+				Amount:     shared.MoneyFromFloat(domain.CalculateCommission(total, fa.params.CommissionRate), shared.DefaultCurrency),
+				Status:     status.String(),
+				CreatedAt:  placedAt,
+				UpdatedAt:  placedAt,
+			}
+			s.commissions[c.ID] = c
+
+			switch status {
+			case shared.CommissionPending:
+				s.seedOpeningBalance(fa.params.ID, shared.LedgerPending, c.Amount, &c.ID, nil, placedAt)
+			case shared.CommissionApproved:
+				s.seedOpeningBalance(fa.params.ID, shared.LedgerAvailable, c.Amount, &c.ID, nil, placedAt)
+			case shared.CommissionPaid:
+				paidItems[fa.params.ID] = append(paidItems[fa.params.ID], payout.NewPayoutItem(c.ID, c.OrderID, c.Amount))
+			}
+		}
+	}
+
+	// Each agent's older months were paid out together last month
+	paidAt := monthStart.AddDate(0, -1, 5)
+	for _, fa := range agents[:3] {
+		items := paidItems[fa.params.ID]
+		paidTotal := shared.ZeroMoney(shared.DefaultCurrency)
+		for _, item := range items {
+			paidTotal = paidTotal.Add(item.Amount())
+		}
+
+		payoutID := s.nextID("payouts")
+		s.payouts[payoutID] = payout.PayoutParams{
+			ID:             payoutID,
+			AgentID:        fa.params.ID,
+			Period:         monthStart.AddDate(0, -2, 0).Format("2006-01"),
+			Items:          items,
+			Amount:         paidTotal,
+			Status:         shared.PayoutCompleted.String(),
+			TransactionRef: fmt.Sprintf("DUITNOW-%04d", payoutID),
+			PaidAt:         &paidAt,
+			CreatedAt:      paidAt,
+			UpdatedAt:      paidAt,
+		}
+		s.seedOpeningBalance(fa.params.ID, shared.LedgerPaidOut, paidTotal, nil, &payoutID, paidAt)
+	}
+
+	// Verified bank accounts the payouts above were paid into
+	banks := []struct{ name, bic, account string }{
+		{"Maybank", "MBBEMYKL", "514012345678"},
+		{"CIMB Bank", "CIBBMYKL", "8001234567"},
+		{"Public Bank", "PBBEMYKL", "3123456789"},
+	}
+	for i, fa := range agents[:3] {
+		verifiedAt := fa.params.CreatedAt
+		id := s.nextID("agent_payout_methods")
+		s.payoutMethods[id] = payout.MethodParams{
+			ID:            id,
+			AgentID:       fa.params.ID,
+			Type:          shared.PayoutMethodBankAccount.String(),
+			Provider:      banks[i].name,
+			ProviderCode:  banks[i].bic,
+			AccountName:   strings.ToUpper(fa.params.Name),
+			AccountNumber: banks[i].account,
+			IsDefault:     true,
+			Status:        shared.PayoutMethodVerified.String(),
+			VerifiedAt:    &verifiedAt,
+			ChangedAt:     verifiedAt,
+			CreatedAt:     verifiedAt,
+			UpdatedAt:     verifiedAt,
+		}
+	}
+
+	// Withholding rules, and tax profiles for the first and third agents;
+	// the second has given no profile and is withheld at the default
+	noTaxID := false
+	for _, rule := range []tax.RuleParams{
+		{Name: "Resident agents", Residency: shared.TaxResident.String(), Rate: 2},
+		{Name: "Non-resident agents", Residency: shared.TaxNonResident.String(), Rate: 10},
+		{Name: "Residents without a tax ID", Residency: shared.TaxResident.String(), TaxIDPresent: &noTaxID, Rate: 5},
+	} {
+		rule.ID = s.nextID("tax_withholding_rules")
+		rule.Active = true
+		rule.CreatedAt, rule.UpdatedAt = createdAt, createdAt
+		s.withholdingRules[rule.ID] = rule
+	}
+	for _, profile := range []tax.ProfileParams{
+		{AgentID: agents[0].params.ID, TaxID: "IG21543890070", Residency: shared.TaxResident.String()},
+		{AgentID: agents[2].params.ID, TaxID: "SG-T21LL0123A", Residency: shared.TaxNonResident.String()},
+	} {
+		profile.UpdatedBy = "seed"
+		profile.CreatedAt, profile.UpdatedAt = createdAt, createdAt
+		s.taxProfiles[profile.AgentID] = profile
+	}
+
+	// Exchange rates for orders in other currencies
+	s.seedFXRate("USD", "MYR", "4.7125", createdAt)
+	s.seedFXRate("SGD", "MYR", "3.4850", createdAt)
+}
+
+// seedOpeningBalance stores an opening ledger entry crediting an account
+// against commission expense. Callers must hold the write lock.
+func (s *Store) seedOpeningBalance(agentID uint, account shared.LedgerAccount, amount shared.Money, commissionID, payoutID *uint, at time.Time) {
+	entry := ledger.Transfer(agentID, ledger.KindOpeningBalance, shared.LedgerCommissionExpense, account, amount)
+	if entry == nil {
+		return
+	}
+	id := s.nextID("agent_ledger_entries")
+	s.ledger[id] = ledger.EntryParams{
+		ID:           id,
+		AgentID:      agentID,
+		Kind:         entry.Kind(),
+		CommissionID: copyUint(commissionID),
+		PayoutID:     copyUint(payoutID),
+		Lines:        entry.Lines(),
+		CreatedAt:    at,
+	}
+}
+
+// seedRateChange stores a rate change. Callers must hold the write lock.
+func (s *Store) seedRateChange(scope commission.RateScope, subjectID uint, rate float64, effectiveFrom time.Time) {
+	id := s.nextID("commission_rate_changes")
+	s.rateChanges[id] = commission.RateChangeParams{
+		ID:            id,
+		Scope:         string(scope),
+		SubjectID:     subjectID,
+		Rate:          rate,
+		EffectiveFrom: effectiveFrom,
+		CreatedAt:     effectiveFrom,
+	}
+}
+
+// seedFXRate stores an exchange rate. Callers must hold the write lock.
+func (s *Store) seedFXRate(base, quote, rate string, effectiveFrom time.Time) {
+	id := s.nextID("fx_rates")
+	s.fxRates[id] = fx.RateParams{
+		ID:            id,
+		BaseCurrency:  base,
+		QuoteCurrency: quote,
+		Rate:          rate,
+		EffectiveFrom: effectiveFrom,
+		CreatedAt:     effectiveFrom,
+	}
+}
@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/fx"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+)
+
+// fxRateRepository implements repository.FXRateRepository
+type fxRateRepository struct {
+	store *Store
+}
+
+// NewFXRateRepository creates a new in-memory exchange rate repository
+func NewFXRateRepository(store *Store) repository.FXRateRepository {
+	return &fxRateRepository{store: store}
+}
+
+// GetByID retrieves an exchange rate by ID
+func (r *fxRateRepository) GetByID(ctx context.Context, id uint) (*fx.Rate, error) {
+	defer r.store.rlock(ctx)()
+
+	params, ok := r.store.fxRates[id]
+	if !ok {
+		return nil, fx.ErrRateNotFound
+	}
+	return fx.ReconstituteRate(params), nil
+}
+
+// List retrieves the rates for a currency pair, or all pairs, newest effective first
+func (r *fxRateRepository) List(ctx context.Context, base, quote string) ([]*fx.Rate, error) {
+	defer r.store.rlock(ctx)()
+
+	var rows []fx.RateParams
+	for _, params := range r.store.fxRates {
+		if (base == "" || params.BaseCurrency == base) && (quote == "" || params.QuoteCurrency == quote) {
+			rows = append(rows, params)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].BaseCurrency != rows[j].BaseCurrency {
+			return rows[i].BaseCurrency < rows[j].BaseCurrency
+		}
+		if rows[i].QuoteCurrency != rows[j].QuoteCurrency {
+			return rows[i].QuoteCurrency < rows[j].QuoteCurrency
+		}
+		return fxEffectiveBefore(rows[j], rows[i])
+	})
+
+	rates := make([]*fx.Rate, len(rows))
+	for i, params := range rows {
+		rates[i] = fx.ReconstituteRate(params)
+	}
+	return rates, nil
+}
+
+// GetRateAt retrieves the rate from base to quote in effect at a time
+func (r *fxRateRepository) GetRateAt(ctx context.Context, base, quote string, at time.Time) (*fx.Rate, error) {
+	defer r.store.rlock(ctx)()
+
+	var found fx.RateParams
+	var ok bool
+	for _, params := range r.store.fxRates {
+		if params.BaseCurrency != base || params.QuoteCurrency != quote || params.EffectiveFrom.After(at) {
+			continue
+		}
+		if !ok || fxEffectiveBefore(found, params) {
+			found, ok = params, true
+		}
+	}
+	if !ok {
+		return nil, fx.ErrRateNotFound
+	}
+	return fx.ReconstituteRate(found), nil
+}
+
+// Create saves a new exchange rate and assigns its ID
+func (r *fxRateRepository) Create(ctx context.Context, rate *fx.Rate) error {
+	defer r.store.lock(ctx)()
+
+	params := fx.RateParams{
+		ID:            r.store.nextID("fx_rates"),
+		BaseCurrency:  rate.BaseCurrency(),
+		QuoteCurrency: rate.QuoteCurrency(),
+		Rate:          rate.ExchangeRate().String(),
+		EffectiveFrom: rate.EffectiveFrom(),
+		CreatedAt:     rate.CreatedAt(),
+	}
+	r.store.fxRates[params.ID] = params
+
+	rate.SetID(params.ID)
+	return nil
+}
+
+// Delete removes an exchange rate
+func (r *fxRateRepository) Delete(ctx context.Context, id uint) error {
+	defer r.store.lock(ctx)()
+
+	delete(r.store.fxRates, id)
+	return nil
+}
+
+// fxEffectiveBefore orders exchange rates by when they take effect,
+// breaking ties by ID so the later rate wins
+func fxEffectiveBefore(a, b fx.RateParams) bool {
+	if !a.EffectiveFrom.Equal(b.EffectiveFrom) {
+		return a.EffectiveFrom.Before(b.EffectiveFrom)
+	}
+	return a.ID < b.ID
+}
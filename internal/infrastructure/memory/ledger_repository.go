@@ -0,0 +1,136 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/ledger"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+)
+
+// ledgerRepository implements repository.LedgerReader
+type ledgerRepository struct {
+	store *Store
+}
+
+// NewLedgerRepository creates a new in-memory ledger repository
+func NewLedgerRepository(store *Store) repository.LedgerReader {
+	return &ledgerRepository{store: store}
+}
+
+func reconstituteEntry(params ledger.EntryParams) *ledger.Entry {
+	params.CommissionID = copyUint(params.CommissionID)
+	params.PayoutID = copyUint(params.PayoutID)
+	params.Lines = append([]ledger.Line(nil), params.Lines...)
+	return ledger.Reconstitute(params)
+}
+
+// GetEntries retrieves an agent's ledger entries and their lines, newest
+// first
+func (r *ledgerRepository) GetEntries(ctx context.Context, filter repository.LedgerFilter) ([]*ledger.Entry, int64, error) {
+	defer r.store.rlock(ctx)()
+
+	rows := make([]ledger.EntryParams, 0)
+	for _, params := range r.store.ledger {
+		if params.AgentID == filter.AgentID && (filter.Account == "" || postsTo(params, filter.Account)) {
+			rows = append(rows, params)
+		}
+	}
+
+	newestFirst(rows,
+		func(p ledger.EntryParams) time.Time { return p.CreatedAt },
+		func(p ledger.EntryParams) uint { return p.ID })
+
+	paged := paginate(rows, filter.Page, filter.Limit)
+	entries := make([]*ledger.Entry, len(paged))
+	for i, params := range paged {
+		entries[i] = reconstituteEntry(params)
+	}
+	return entries, int64(len(rows)), nil
+}
+
+// GetBalances sums an agent's ledger lines by account and currency
+func (r *ledgerRepository) GetBalances(ctx context.Context, agentID uint) (ledger.Balances, error) {
+	defer r.store.rlock(ctx)()
+
+	return r.store.balances(agentID), nil
+}
+
+// GetUnbalanced returns the IDs of an agent's entries whose lines do not
+// net to zero
+func (r *ledgerRepository) GetUnbalanced(ctx context.Context, agentID uint) ([]uint, error) {
+	defer r.store.rlock(ctx)()
+
+	ids := make([]uint, 0)
+	for _, params := range r.store.ledger {
+		if params.AgentID == agentID && !ledger.Reconstitute(params).IsBalanced() {
+			ids = append(ids, params.ID)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+// postsTo reports whether an entry has a line on the account
+func postsTo(params ledger.EntryParams, account string) bool {
+	for _, line := range params.Lines {
+		if line.Account().String() == account {
+			return true
+		}
+	}
+	return false
+}
+
+// appendLedger stores ledger entries written with the aggregate they
+// record. Callers must hold the write lock.
+func (s *Store) appendLedger(entries []*ledger.Entry) {
+	for _, entry := range entries {
+		entry.SetID(s.nextID("agent_ledger_entries"))
+		s.ledger[entry.ID()] = ledger.EntryParams{
+			ID:           entry.ID(),
+			AgentID:      entry.AgentID(),
+			Kind:         entry.Kind(),
+			CommissionID: copyUint(entry.CommissionID()),
+			PayoutID:     copyUint(entry.PayoutID()),
+			Lines:        append([]ledger.Line(nil), entry.Lines()...),
+			CreatedAt:    entry.CreatedAt(),
+		}
+	}
+}
+
+// balances sums an agent's ledger lines by account and currency.
+// Callers must hold the read lock.
+func (s *Store) balances(agentID uint) ledger.Balances {
+	type key struct {
+		account  shared.LedgerAccount
+		currency string
+	}
+	sums := make(map[key]shared.Money)
+	for _, params := range s.ledger {
+		if params.AgentID != agentID {
+			continue
+		}
+		for _, line := range params.Lines {
+			k := key{line.Account(), line.Amount().Currency()}
+			sum, ok := sums[k]
+			if !ok {
+				sum = shared.ZeroMoney(k.currency)
+			}
+			sums[k] = sum.Add(line.Amount())
+		}
+	}
+
+	balances := make(ledger.Balances, 0, len(sums))
+	for k, sum := range sums {
+		balances = append(balances, ledger.NewBalance(k.account, sum))
+	}
+	sort.Slice(balances, func(i, j int) bool {
+		if balances[i].Account != balances[j].Account {
+			return balances[i].Account < balances[j].Account
+		}
+		return balances[i].Amount.Currency() < balances[j].Amount.Currency()
+	})
+	return balances
+}
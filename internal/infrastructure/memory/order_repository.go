@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+)
+
+// orderRepository implements repository.OrderReader
+type orderRepository struct {
+	store *Store
+}
+
+// NewOrderRepository creates a new read-only in-memory order repository
+func NewOrderRepository(store *Store) repository.OrderReader {
+	return &orderRepository{store: store}
+}
+
+// GetByID retrieves one of an agent's orders
+func (r *orderRepository) GetByID(ctx context.Context, agentUserID, orderID string) (*domain.Order, error) {
+	defer r.store.rlock(ctx)()
+
+	order, ok := r.store.orders[orderID]
+	if !ok || order.AgentID != agentUserID {
+		return nil, repository.ErrNotFound
+	}
+	return &order, nil
+}
+
+// List retrieves an agent's orders, newest first
+func (r *orderRepository) List(ctx context.Context, agentUserID, status string, page, limit int) ([]domain.Order, int64, error) {
+	defer r.store.rlock(ctx)()
+
+	var rows []domain.Order
+	for _, order := range r.store.orders {
+		if order.AgentID == agentUserID && (status == "" || order.Status == status) {
+			rows = append(rows, order)
+		}
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		if !rows[i].CreatedAt.Equal(rows[j].CreatedAt) {
+			return rows[i].CreatedAt.After(rows[j].CreatedAt)
+		}
+		return rows[i].ID > rows[j].ID
+	})
+
+	return paginate(rows, page, limit), int64(len(rows)), nil
+}
+
+// GetSummary counts an agent's orders and sums their totals within a period
+func (r *orderRepository) GetSummary(ctx context.Context, agentUserID string, period repository.Period) (*repository.OrderSummary, error) {
+	defer r.store.rlock(ctx)()
+
+	summary := &repository.OrderSummary{}
+	for _, order := range r.store.orders {
+		if order.AgentID == agentUserID && inPeriod(order.CreatedAt, period) {
+			summary.Count++
+			summary.Total += order.Total
+		}
+	}
+	return summary, nil
+}
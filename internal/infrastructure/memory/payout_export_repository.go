@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/payout"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+)
+
+// payoutExportRepository implements repository.PayoutExportRepository
+type payoutExportRepository struct {
+	store *Store
+}
+
+// NewPayoutExportRepository creates a new in-memory payment file export
+// repository
+func NewPayoutExportRepository(store *Store) repository.PayoutExportRepository {
+	return &payoutExportRepository{store: store}
+}
+
+// payoutExportParams captures the stored state of an export
+func payoutExportParams(e *payout.Export) payout.ExportParams {
+	return payout.ExportParams{
+		ID:          e.ID(),
+		RunID:       e.RunID(),
+		Format:      e.Format(),
+		FileName:    e.FileName(),
+		ContentType: e.ContentType(),
+		Content:     bytes.Clone(e.Content()),
+		Checksum:    e.Checksum(),
+		Count:       e.Count(),
+		ExportedBy:  e.ExportedBy(),
+		CreatedAt:   e.CreatedAt(),
+	}
+}
+
+func reconstitutePayoutExport(params payout.ExportParams) *payout.Export {
+	params.Content = bytes.Clone(params.Content)
+	return payout.ReconstituteExport(params)
+}
+
+// GetByID retrieves an export and its file by ID
+func (r *payoutExportRepository) GetByID(ctx context.Context, id uint) (*payout.Export, error) {
+	defer r.store.rlock(ctx)()
+
+	params, ok := r.store.payoutExports[id]
+	if !ok {
+		return nil, payout.ErrExportNotFound
+	}
+	return reconstitutePayoutExport(params), nil
+}
+
+// ListByRun lists a run's exports, newest first
+func (r *payoutExportRepository) ListByRun(ctx context.Context, runID uint) ([]*payout.Export, error) {
+	defer r.store.rlock(ctx)()
+
+	var rows []payout.ExportParams
+	for _, params := range r.store.payoutExports {
+		if params.RunID == runID {
+			rows = append(rows, params)
+		}
+	}
+	newestFirst(rows,
+		func(p payout.ExportParams) time.Time { return p.CreatedAt },
+		func(p payout.ExportParams) uint { return p.ID })
+
+	exports := make([]*payout.Export, len(rows))
+	for i, params := range rows {
+		exports[i] = reconstitutePayoutExport(params)
+	}
+	return exports, nil
+}
+
+// Create saves a new export and assigns its ID
+func (r *payoutExportRepository) Create(ctx context.Context, export *payout.Export) error {
+	defer r.store.lock(ctx)()
+
+	export.SetID(r.store.nextID("payout_exports"))
+	r.store.payoutExports[export.ID()] = payoutExportParams(export)
+	return nil
+}
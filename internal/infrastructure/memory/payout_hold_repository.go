@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/payout"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+)
+
+// payoutHoldRepository implements repository.PayoutHoldRepository
+type payoutHoldRepository struct {
+	store *Store
+}
+
+// NewPayoutHoldRepository creates a new in-memory payout hold repository
+func NewPayoutHoldRepository(store *Store) repository.PayoutHoldRepository {
+	return &payoutHoldRepository{store: store}
+}
+
+// payoutHoldParams captures the stored state of a payout hold
+func payoutHoldParams(h *payout.Hold) payout.HoldParams {
+	return payout.HoldParams{
+		ID:         h.ID(),
+		AgentID:    h.AgentID(),
+		Reason:     h.Reason(),
+		ExpiresAt:  h.ExpiresAt(),
+		CreatedBy:  h.CreatedBy(),
+		ReleasedBy: h.ReleasedBy(),
+		ReleasedAt: copyTime(h.ReleasedAt()),
+		CreatedAt:  h.CreatedAt(),
+		UpdatedAt:  h.UpdatedAt(),
+	}
+}
+
+func reconstitutePayoutHold(params payout.HoldParams) *payout.Hold {
+	params.ReleasedAt = copyTime(params.ReleasedAt)
+	return payout.ReconstituteHold(params)
+}
+
+// GetByID retrieves a payout hold by ID
+func (r *payoutHoldRepository) GetByID(ctx context.Context, id uint) (*payout.Hold, error) {
+	defer r.store.rlock(ctx)()
+
+	params, ok := r.store.payoutHolds[id]
+	if !ok {
+		return nil, payout.ErrHoldNotFound
+	}
+	return reconstitutePayoutHold(params), nil
+}
+
+// ListByAgent lists an agent's payout holds, newest first
+func (r *payoutHoldRepository) ListByAgent(ctx context.Context, agentID uint) ([]*payout.Hold, error) {
+	return r.list(ctx, func(h payout.HoldParams) bool { return h.AgentID == agentID })
+}
+
+// ListActive lists the payout holds active at the given time, newest first
+func (r *payoutHoldRepository) ListActive(ctx context.Context, at time.Time) ([]*payout.Hold, error) {
+	return r.list(ctx, func(h payout.HoldParams) bool { return h.ReleasedAt == nil && at.Before(h.ExpiresAt) })
+}
+
+func (r *payoutHoldRepository) list(ctx context.Context, match func(payout.HoldParams) bool) ([]*payout.Hold, error) {
+	defer r.store.rlock(ctx)()
+
+	var rows []payout.HoldParams
+	for _, params := range r.store.payoutHolds {
+		if match(params) {
+			rows = append(rows, params)
+		}
+	}
+	newestFirst(rows,
+		func(h payout.HoldParams) time.Time { return h.CreatedAt },
+		func(h payout.HoldParams) uint { return h.ID })
+
+	holds := make([]*payout.Hold, len(rows))
+	for i, params := range rows {
+		holds[i] = reconstitutePayoutHold(params)
+	}
+	return holds, nil
+}
+
+// Create saves a new payout hold and assigns its ID
+func (r *payoutHoldRepository) Create(ctx context.Context, hold *payout.Hold) error {
+	defer r.store.lock(ctx)()
+
+	hold.SetID(r.store.nextID("payout_holds"))
+	r.store.payoutHolds[hold.ID()] = payoutHoldParams(hold)
+	return nil
+}
+
+// Update saves a payout hold
+func (r *payoutHoldRepository) Update(ctx context.Context, hold *payout.Hold) error {
+	defer r.store.lock(ctx)()
+
+	if _, ok := r.store.payoutHolds[hold.ID()]; !ok {
+		return payout.ErrHoldNotFound
+	}
+	r.store.payoutHolds[hold.ID()] = payoutHoldParams(hold)
+	return nil
+}
@@ -0,0 +1,149 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/payout"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+)
+
+// payoutMethodRepository implements repository.PayoutMethodRepository
+type payoutMethodRepository struct {
+	store *Store
+}
+
+// NewPayoutMethodRepository creates a new in-memory payout method
+// repository
+func NewPayoutMethodRepository(store *Store) repository.PayoutMethodRepository {
+	return &payoutMethodRepository{store: store}
+}
+
+// payoutMethodParams captures the stored state of a payout method
+func payoutMethodParams(m *payout.Method) payout.MethodParams {
+	return payout.MethodParams{
+		ID:                    m.ID(),
+		AgentID:               m.AgentID(),
+		Type:                  m.Type().String(),
+		Provider:              m.Provider(),
+		ProviderCode:          m.ProviderCode(),
+		AccountName:           m.AccountName(),
+		AccountNumber:         m.AccountNumber(),
+		IsDefault:             m.IsDefault(),
+		Status:                m.Status().String(),
+		VerificationHash:      m.VerificationHash(),
+		VerificationExpiresAt: copyTime(m.VerificationExpiresAt()),
+		VerificationAttempts:  m.VerificationAttempts(),
+		VerifiedAt:            copyTime(m.VerifiedAt()),
+		ChangedAt:             m.ChangedAt(),
+		Version:               m.Version(),
+		CreatedAt:             m.CreatedAt(),
+		UpdatedAt:             m.UpdatedAt(),
+	}
+}
+
+func reconstitutePayoutMethod(params payout.MethodParams) *payout.Method {
+	params.VerificationExpiresAt = copyTime(params.VerificationExpiresAt)
+	params.VerifiedAt = copyTime(params.VerifiedAt)
+	return payout.ReconstituteMethod(params)
+}
+
+// GetByID retrieves a payout method by ID
+func (r *payoutMethodRepository) GetByID(ctx context.Context, id uint) (*payout.Method, error) {
+	defer r.store.rlock(ctx)()
+
+	params, ok := r.store.payoutMethods[id]
+	if !ok {
+		return nil, payout.ErrMethodNotFound
+	}
+	return reconstitutePayoutMethod(params), nil
+}
+
+// ListByAgent lists an agent's payout methods, oldest first
+func (r *payoutMethodRepository) ListByAgent(ctx context.Context, agentID uint) ([]*payout.Method, error) {
+	defer r.store.rlock(ctx)()
+
+	var rows []payout.MethodParams
+	for _, params := range r.store.payoutMethods {
+		if params.AgentID == agentID {
+			rows = append(rows, params)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].ID < rows[j].ID })
+
+	methods := make([]*payout.Method, len(rows))
+	for i, params := range rows {
+		methods[i] = reconstitutePayoutMethod(params)
+	}
+	return methods, nil
+}
+
+// Create saves a new payout method and assigns its ID
+func (r *payoutMethodRepository) Create(ctx context.Context, method *payout.Method) error {
+	defer r.store.lock(ctx)()
+
+	method.SetID(r.store.nextID("agent_payout_methods"))
+	r.store.payoutMethods[method.ID()] = payoutMethodParams(method)
+	return nil
+}
+
+// Update saves a payout method. It returns repository.ErrConflict if the
+// method was saved since it was loaded.
+func (r *payoutMethodRepository) Update(ctx context.Context, method *payout.Method) error {
+	defer r.store.lock(ctx)()
+
+	return r.update(method)
+}
+
+// update saves method over the version it was loaded at; the store must
+// be locked
+func (r *payoutMethodRepository) update(method *payout.Method) error {
+	existing, ok := r.store.payoutMethods[method.ID()]
+	if !ok {
+		return payout.ErrMethodNotFound
+	}
+	if existing.Version != method.Version() {
+		return repository.ErrConflict
+	}
+	params := payoutMethodParams(method)
+	params.Version++
+	r.store.payoutMethods[method.ID()] = params
+	method.SetVersion(params.Version)
+	return nil
+}
+
+// SetDefault saves a method made the default and clears the default on
+// the agent's other methods. Like Update, it returns repository.ErrConflict
+// if the method was saved since it was loaded.
+func (r *payoutMethodRepository) SetDefault(ctx context.Context, method *payout.Method) error {
+	defer r.store.lock(ctx)()
+
+	existing, ok := r.store.payoutMethods[method.ID()]
+	if !ok {
+		return payout.ErrMethodNotFound
+	}
+	if existing.Version != method.Version() {
+		return repository.ErrConflict
+	}
+	for id, params := range r.store.payoutMethods {
+		if params.AgentID == method.AgentID() && id != method.ID() && params.IsDefault {
+			other := payout.ReconstituteMethod(params)
+			other.ClearDefault()
+			cleared := payoutMethodParams(other)
+			cleared.Version++
+			r.store.payoutMethods[id] = cleared
+		}
+	}
+	return r.update(method)
+}
+
+// Delete removes a payout method
+func (r *payoutMethodRepository) Delete(ctx context.Context, id uint) error {
+	defer r.store.lock(ctx)()
+
+	if _, ok := r.store.payoutMethods[id]; !ok {
+		return payout.ErrMethodNotFound
+	}
+	delete(r.store.payoutMethods, id)
+	return nil
+}
@@ -0,0 +1,164 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/payout"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+)
+
+// payoutRepository implements repository.PayoutRepository
+type payoutRepository struct {
+	store *Store
+}
+
+// NewPayoutRepository creates a new in-memory payout repository
+func NewPayoutRepository(store *Store) repository.PayoutRepository {
+	return &payoutRepository{store: store}
+}
+
+// payoutParams captures the stored state of a payout
+func payoutParams(p *payout.Payout) payout.PayoutParams {
+	return payout.PayoutParams{
+		ID:              p.ID(),
+		AgentID:         p.AgentID(),
+		Period:          p.Period(),
+		Items:           append([]payout.PayoutItem(nil), p.Items()...),
+		Deductions:      p.Deductions(),
+		WithholdingRate: p.WithholdingRate(),
+		Withheld:        p.Withheld(),
+		ReservePercent:  p.ReservePercent(),
+		Reserved:        p.Reserved(),
+		Released:        p.Released(),
+		Amount:          p.Amount(),
+		Status:          p.Status().String(),
+		TransactionRef:  p.TransactionRef(),
+		FailureReason:   p.FailureReason(),
+		Retries:         p.Retries(),
+		PaidAt:          copyTime(p.PaidAt()),
+		Version:         p.Version(),
+		CreatedAt:       p.CreatedAt(),
+		UpdatedAt:       p.UpdatedAt(),
+	}
+}
+
+func reconstitutePayout(params payout.PayoutParams) *payout.Payout {
+	params.Items = append([]payout.PayoutItem(nil), params.Items...)
+	params.PaidAt = copyTime(params.PaidAt)
+	return payout.Reconstitute(params)
+}
+
+// GetByID retrieves a payout and its items by ID
+func (r *payoutRepository) GetByID(ctx context.Context, id uint) (*payout.Payout, error) {
+	defer r.store.rlock(ctx)()
+
+	params, ok := r.store.payouts[id]
+	if !ok {
+		return nil, payout.ErrPayoutNotFound
+	}
+	return reconstitutePayout(params), nil
+}
+
+// GetByAgentID retrieves an agent's payouts, newest first
+func (r *payoutRepository) GetByAgentID(ctx context.Context, agentID uint, page, limit int) ([]*payout.Payout, int64, error) {
+	return r.list(ctx, func(p payout.PayoutParams) bool { return p.AgentID == agentID }, page, limit)
+}
+
+// GetPending retrieves all pending payouts, newest first
+func (r *payoutRepository) GetPending(ctx context.Context, page, limit int) ([]*payout.Payout, int64, error) {
+	return r.list(ctx, func(p payout.PayoutParams) bool { return p.Status == shared.PayoutPending.String() }, page, limit)
+}
+
+// GetPaid retrieves the completed payouts paid within the period, oldest
+// first
+func (r *payoutRepository) GetPaid(ctx context.Context, period repository.Period) ([]*payout.Payout, error) {
+	defer r.store.rlock(ctx)()
+
+	var rows []payout.PayoutParams
+	for _, params := range r.store.payouts {
+		if params.Status == shared.PayoutCompleted.String() && params.PaidAt != nil && inPeriod(*params.PaidAt, period) {
+			rows = append(rows, params)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if !rows[i].PaidAt.Equal(*rows[j].PaidAt) {
+			return rows[i].PaidAt.Before(*rows[j].PaidAt)
+		}
+		return rows[i].ID < rows[j].ID
+	})
+
+	payouts := make([]*payout.Payout, len(rows))
+	for i, params := range rows {
+		payouts[i] = reconstitutePayout(params)
+	}
+	return payouts, nil
+}
+
+func (r *payoutRepository) list(ctx context.Context, match func(payout.PayoutParams) bool, page, limit int) ([]*payout.Payout, int64, error) {
+	defer r.store.rlock(ctx)()
+
+	var rows []payout.PayoutParams
+	for _, params := range r.store.payouts {
+		if match(params) {
+			rows = append(rows, params)
+		}
+	}
+
+	newestFirst(rows,
+		func(p payout.PayoutParams) time.Time { return p.CreatedAt },
+		func(p payout.PayoutParams) uint { return p.ID })
+
+	paged := paginate(rows, page, limit)
+	payouts := make([]*payout.Payout, len(paged))
+	for i, params := range paged {
+		payouts[i] = reconstitutePayout(params)
+	}
+	return payouts, int64(len(rows)), nil
+}
+
+// Create creates a new payout and assigns its ID
+func (r *payoutRepository) Create(ctx context.Context, p *payout.Payout) error {
+	defer r.store.lock(ctx)()
+
+	r.store.createPayout(p)
+	return nil
+}
+
+// createPayout stores a payout and its ledger entries and assigns its ID.
+// The caller holds the write lock.
+func (s *Store) createPayout(p *payout.Payout) {
+	params := payoutParams(p)
+	params.ID = s.nextID("payouts")
+	touch(&params.CreatedAt, &params.UpdatedAt)
+	s.payouts[params.ID] = params
+
+	p.SetID(params.ID)
+	s.appendLedger(p.LedgerEntries())
+}
+
+// Update saves all payout fields and the ledger entries for its
+// transitions. It refuses a payout saved by someone else since it was
+// read, so the ledger entries are never posted twice.
+func (r *payoutRepository) Update(ctx context.Context, p *payout.Payout) error {
+	defer r.store.lock(ctx)()
+
+	existing, ok := r.store.payouts[p.ID()]
+	if !ok {
+		return payout.ErrPayoutNotFound
+	}
+	if existing.Version != p.Version() {
+		return repository.ErrConflict
+	}
+
+	params := payoutParams(p)
+	params.Version++
+	params.CreatedAt = existing.CreatedAt
+	params.UpdatedAt = time.Now()
+	r.store.payouts[params.ID] = params
+	r.store.appendLedger(p.LedgerEntries())
+	p.SetVersion(params.Version)
+	return nil
+}
@@ -0,0 +1,135 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/payout"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+)
+
+// payoutReserveRepository implements repository.PayoutReserveRepository
+type payoutReserveRepository struct {
+	store *Store
+}
+
+// NewPayoutReserveRepository creates a new in-memory payout reserve
+// repository
+func NewPayoutReserveRepository(store *Store) repository.PayoutReserveRepository {
+	return &payoutReserveRepository{store: store}
+}
+
+// payoutReserveParams captures the stored state of a reserve
+func payoutReserveParams(r *payout.Reserve) payout.ReserveParams {
+	return payout.ReserveParams{
+		ID:              r.ID(),
+		AgentID:         r.AgentID(),
+		PayoutID:        r.PayoutID(),
+		Amount:          r.Amount(),
+		Percent:         r.Percent(),
+		ReleaseAt:       r.ReleaseAt(),
+		Status:          r.Status().String(),
+		ReleasePayoutID: copyUint(r.ReleasePayoutID()),
+		ReleasedAt:      copyTime(r.ReleasedAt()),
+		Version:         r.Version(),
+		CreatedAt:       r.CreatedAt(),
+		UpdatedAt:       r.UpdatedAt(),
+	}
+}
+
+func reconstitutePayoutReserve(params payout.ReserveParams) *payout.Reserve {
+	params.ReleasePayoutID = copyUint(params.ReleasePayoutID)
+	params.ReleasedAt = copyTime(params.ReleasedAt)
+	return payout.ReconstituteReserve(params)
+}
+
+// ListByAgent lists an agent's reserves, newest first
+func (r *payoutReserveRepository) ListByAgent(ctx context.Context, agentID uint) ([]*payout.Reserve, error) {
+	rows := r.find(ctx, func(p payout.ReserveParams) bool { return p.AgentID == agentID })
+	newestFirst(rows,
+		func(p payout.ReserveParams) time.Time { return p.CreatedAt },
+		func(p payout.ReserveParams) uint { return p.ID })
+	return reconstitutePayoutReserves(rows), nil
+}
+
+// GetDue lists the held reserves due for release at the given time, oldest
+// first
+func (r *payoutReserveRepository) GetDue(ctx context.Context, at time.Time) ([]*payout.Reserve, error) {
+	rows := r.find(ctx, func(p payout.ReserveParams) bool {
+		return p.Status == shared.ReserveHeld.String() && !at.Before(p.ReleaseAt)
+	})
+	sort.Slice(rows, func(i, j int) bool { return rows[i].ID < rows[j].ID })
+	return reconstitutePayoutReserves(rows), nil
+}
+
+// GetByPayoutID retrieves the reserve kept back from a payout
+func (r *payoutReserveRepository) GetByPayoutID(ctx context.Context, payoutID uint) (*payout.Reserve, error) {
+	rows := r.find(ctx, func(p payout.ReserveParams) bool { return p.PayoutID == payoutID })
+	if len(rows) == 0 {
+		return nil, payout.ErrReserveNotFound
+	}
+	return reconstitutePayoutReserve(rows[0]), nil
+}
+
+// GetByReleasePayoutID lists the reserves a payout released
+func (r *payoutReserveRepository) GetByReleasePayoutID(ctx context.Context, payoutID uint) ([]*payout.Reserve, error) {
+	rows := r.find(ctx, func(p payout.ReserveParams) bool {
+		return p.ReleasePayoutID != nil && *p.ReleasePayoutID == payoutID
+	})
+	sort.Slice(rows, func(i, j int) bool { return rows[i].ID < rows[j].ID })
+	return reconstitutePayoutReserves(rows), nil
+}
+
+func (r *payoutReserveRepository) find(ctx context.Context, match func(payout.ReserveParams) bool) []payout.ReserveParams {
+	defer r.store.rlock(ctx)()
+
+	var rows []payout.ReserveParams
+	for _, params := range r.store.payoutReserves {
+		if match(params) {
+			rows = append(rows, params)
+		}
+	}
+	return rows
+}
+
+func reconstitutePayoutReserves(rows []payout.ReserveParams) []*payout.Reserve {
+	reserves := make([]*payout.Reserve, len(rows))
+	for i, params := range rows {
+		reserves[i] = reconstitutePayoutReserve(params)
+	}
+	return reserves
+}
+
+// Create saves a new reserve and assigns its ID
+func (r *payoutReserveRepository) Create(ctx context.Context, reserve *payout.Reserve) error {
+	defer r.store.lock(ctx)()
+
+	for _, params := range r.store.payoutReserves {
+		if params.PayoutID == reserve.PayoutID() {
+			return repository.ErrDuplicate
+		}
+	}
+	reserve.SetID(r.store.nextID("payout_reserves"))
+	r.store.payoutReserves[reserve.ID()] = payoutReserveParams(reserve)
+	return nil
+}
+
+// Update saves a reserve
+func (r *payoutReserveRepository) Update(ctx context.Context, reserve *payout.Reserve) error {
+	defer r.store.lock(ctx)()
+
+	existing, ok := r.store.payoutReserves[reserve.ID()]
+	if !ok {
+		return payout.ErrReserveNotFound
+	}
+	if existing.Version != reserve.Version() {
+		return repository.ErrConflict
+	}
+	params := payoutReserveParams(reserve)
+	params.Version++
+	r.store.payoutReserves[reserve.ID()] = params
+	reserve.SetVersion(params.Version)
+	return nil
+}
@@ -0,0 +1,126 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/payout"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+)
+
+// payoutRunRepository implements repository.PayoutRunRepository
+type payoutRunRepository struct {
+	store *Store
+}
+
+// NewPayoutRunRepository creates a new in-memory payout run repository
+func NewPayoutRunRepository(store *Store) repository.PayoutRunRepository {
+	return &payoutRunRepository{store: store}
+}
+
+// payoutRunParams captures the stored state of a payout run
+func payoutRunParams(r *payout.Run) payout.RunParams {
+	lines := make([]payout.RunLine, len(r.Lines()))
+	for i, line := range r.Lines() {
+		lines[i] = payout.ReconstituteRunLine(line.AgentID(), line.Outcome(), line.Amount(),
+			line.Deductions(), line.Commissions(), line.Reason(), line.PayoutID())
+	}
+	return payout.RunParams{
+		ID:          r.ID(),
+		Period:      r.Period(),
+		Minimum:     r.Minimum(),
+		CreatedBy:   r.CreatedBy(),
+		Status:      r.Status().String(),
+		Lines:       lines,
+		CancelledBy: r.CancelledBy(),
+		CancelledAt: copyTime(r.CancelledAt()),
+		CreatedAt:   r.CreatedAt(),
+		UpdatedAt:   r.UpdatedAt(),
+	}
+}
+
+func reconstitutePayoutRun(params payout.RunParams) *payout.Run {
+	params.Lines = append([]payout.RunLine(nil), params.Lines...)
+	params.CancelledAt = copyTime(params.CancelledAt)
+	return payout.ReconstituteRun(params)
+}
+
+// GetByID retrieves a payout run and its lines by ID
+func (r *payoutRunRepository) GetByID(ctx context.Context, id uint) (*payout.Run, error) {
+	defer r.store.rlock(ctx)()
+
+	params, ok := r.store.payoutRuns[id]
+	if !ok {
+		return nil, payout.ErrRunNotFound
+	}
+	return reconstitutePayoutRun(params), nil
+}
+
+// List retrieves the payout runs matching the filter, newest first
+func (r *payoutRunRepository) List(ctx context.Context, filter repository.PayoutRunFilter) ([]*payout.Run, int64, error) {
+	defer r.store.rlock(ctx)()
+
+	var rows []payout.RunParams
+	for _, params := range r.store.payoutRuns {
+		if filter.Period != "" && params.Period != filter.Period {
+			continue
+		}
+		if filter.Status != "" && params.Status != filter.Status {
+			continue
+		}
+		rows = append(rows, params)
+	}
+
+	newestFirst(rows,
+		func(p payout.RunParams) time.Time { return p.CreatedAt },
+		func(p payout.RunParams) uint { return p.ID })
+
+	paged := paginate(rows, filter.Page, filter.Limit)
+	runs := make([]*payout.Run, len(paged))
+	for i, params := range paged {
+		runs[i] = reconstitutePayoutRun(params)
+	}
+	return runs, int64(len(rows)), nil
+}
+
+// Create saves a committed run, creating its payouts and their ledger
+// entries, and assigns its ID
+func (r *payoutRunRepository) Create(ctx context.Context, run *payout.Run) error {
+	defer r.store.lock(ctx)()
+
+	for _, params := range r.store.payoutRuns {
+		if params.Period == run.Period() && params.Status == shared.PayoutRunCommitted.String() {
+			return repository.ErrDuplicate
+		}
+	}
+
+	for _, p := range run.Payouts() {
+		r.store.createPayout(p)
+	}
+
+	params := payoutRunParams(run)
+	params.ID = r.store.nextID("payout_runs")
+	touch(&params.CreatedAt, &params.UpdatedAt)
+	r.store.payoutRuns[params.ID] = params
+
+	run.SetID(params.ID)
+	return nil
+}
+
+// Update saves the run's status. Its lines do not change once saved.
+func (r *payoutRunRepository) Update(ctx context.Context, run *payout.Run) error {
+	defer r.store.lock(ctx)()
+
+	existing, ok := r.store.payoutRuns[run.ID()]
+	if !ok {
+		return payout.ErrRunNotFound
+	}
+
+	params := payoutRunParams(run)
+	params.Lines = existing.Lines
+	params.CreatedAt = existing.CreatedAt
+	params.UpdatedAt = time.Now()
+	r.store.payoutRuns[params.ID] = params
+	return nil
+}
@@ -0,0 +1,177 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/payout"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+)
+
+// payoutStatementRepository implements repository.PayoutStatementRepository
+type payoutStatementRepository struct {
+	store *Store
+}
+
+// NewPayoutStatementRepository creates a new in-memory bank statement
+// repository
+func NewPayoutStatementRepository(store *Store) repository.PayoutStatementRepository {
+	return &payoutStatementRepository{store: store}
+}
+
+// statementLineParams captures the stored state of a statement line
+func statementLineParams(l *payout.StatementLine) payout.StatementLineParams {
+	return payout.StatementLineParams{
+		ID:             l.ID(),
+		StatementID:    l.StatementID(),
+		Reference:      l.Reference(),
+		Amount:         l.Amount(),
+		BookedAt:       copyTime(l.BookedAt()),
+		TransactionRef: l.TransactionRef(),
+		Outcome:        l.Outcome(),
+		Detail:         l.Detail(),
+		PayoutID:       copyUint(l.PayoutID()),
+		ReviewedBy:     l.ReviewedBy(),
+		ReviewedAt:     copyTime(l.ReviewedAt()),
+	}
+}
+
+func reconstituteStatementLine(params payout.StatementLineParams) *payout.StatementLine {
+	params.BookedAt = copyTime(params.BookedAt)
+	params.PayoutID = copyUint(params.PayoutID)
+	params.ReviewedAt = copyTime(params.ReviewedAt)
+	return payout.ReconstituteStatementLine(params)
+}
+
+// reconstituteStatement rebuilds a statement with its lines in the order
+// they were added. Callers must hold the read lock.
+func (r *payoutStatementRepository) reconstituteStatement(params payout.StatementParams) *payout.Statement {
+	var lines []payout.StatementLineParams
+	for _, line := range r.store.statementLines {
+		if line.StatementID == params.ID {
+			lines = append(lines, line)
+		}
+	}
+	sort.Slice(lines, func(i, j int) bool { return lines[i].ID < lines[j].ID })
+
+	params.Lines = make([]*payout.StatementLine, len(lines))
+	for i, line := range lines {
+		params.Lines[i] = reconstituteStatementLine(line)
+	}
+	return payout.ReconstituteStatement(params)
+}
+
+// GetByID retrieves a statement and its lines by ID
+func (r *payoutStatementRepository) GetByID(ctx context.Context, id uint) (*payout.Statement, error) {
+	defer r.store.rlock(ctx)()
+
+	params, ok := r.store.statements[id]
+	if !ok {
+		return nil, payout.ErrStatementNotFound
+	}
+	return r.reconstituteStatement(params), nil
+}
+
+// GetByChecksum retrieves the statement imported from a file
+func (r *payoutStatementRepository) GetByChecksum(ctx context.Context, checksum string) (*payout.Statement, error) {
+	defer r.store.rlock(ctx)()
+
+	for _, params := range r.store.statements {
+		if params.Checksum == checksum {
+			return r.reconstituteStatement(params), nil
+		}
+	}
+	return nil, payout.ErrStatementNotFound
+}
+
+// List retrieves statements with their lines, newest first
+func (r *payoutStatementRepository) List(ctx context.Context, page, limit int) ([]*payout.Statement, int64, error) {
+	defer r.store.rlock(ctx)()
+
+	rows := make([]payout.StatementParams, 0, len(r.store.statements))
+	for _, params := range r.store.statements {
+		rows = append(rows, params)
+	}
+
+	newestFirst(rows,
+		func(p payout.StatementParams) time.Time { return p.CreatedAt },
+		func(p payout.StatementParams) uint { return p.ID })
+
+	paged := paginate(rows, page, limit)
+	statements := make([]*payout.Statement, len(paged))
+	for i, params := range paged {
+		statements[i] = r.reconstituteStatement(params)
+	}
+	return statements, int64(len(rows)), nil
+}
+
+// GetLine retrieves a statement line by ID
+func (r *payoutStatementRepository) GetLine(ctx context.Context, id uint) (*payout.StatementLine, error) {
+	defer r.store.rlock(ctx)()
+
+	params, ok := r.store.statementLines[id]
+	if !ok {
+		return nil, payout.ErrStatementLineNotFound
+	}
+	return reconstituteStatementLine(params), nil
+}
+
+// ListForReview lists unmatched lines, oldest first
+func (r *payoutStatementRepository) ListForReview(ctx context.Context, page, limit int) ([]*payout.StatementLine, int64, error) {
+	defer r.store.rlock(ctx)()
+
+	var rows []payout.StatementLineParams
+	for _, params := range r.store.statementLines {
+		if params.Outcome == payout.StatementLineUnmatched {
+			rows = append(rows, params)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].ID < rows[j].ID })
+
+	paged := paginate(rows, page, limit)
+	lines := make([]*payout.StatementLine, len(paged))
+	for i, params := range paged {
+		lines[i] = reconstituteStatementLine(params)
+	}
+	return lines, int64(len(rows)), nil
+}
+
+// Create saves a statement with its lines and assigns their IDs
+func (r *payoutStatementRepository) Create(ctx context.Context, statement *payout.Statement) error {
+	defer r.store.lock(ctx)()
+
+	for _, params := range r.store.statements {
+		if params.Checksum == statement.Checksum() {
+			return repository.ErrDuplicate
+		}
+	}
+
+	params := payout.StatementParams{
+		ID:         r.store.nextID("payout_statements"),
+		Format:     statement.Format(),
+		FileName:   statement.FileName(),
+		Checksum:   statement.Checksum(),
+		ImportedBy: statement.ImportedBy(),
+		CreatedAt:  statement.CreatedAt(),
+	}
+	r.store.statements[params.ID] = params
+	statement.SetID(params.ID)
+
+	for _, line := range statement.Lines() {
+		line.SetID(r.store.nextID("payout_statement_lines"))
+		r.store.statementLines[line.ID()] = statementLineParams(line)
+	}
+	return nil
+}
+
+// UpdateLine saves a statement line's outcome after review
+func (r *payoutStatementRepository) UpdateLine(ctx context.Context, line *payout.StatementLine) error {
+	defer r.store.lock(ctx)()
+
+	if _, ok := r.store.statementLines[line.ID()]; !ok {
+		return payout.ErrStatementLineNotFound
+	}
+	r.store.statementLines[line.ID()] = statementLineParams(line)
+	return nil
+}
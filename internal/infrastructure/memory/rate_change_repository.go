@@ -0,0 +1,135 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/commission"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+)
+
+// rateChangeRepository implements repository.RateChangeRepository
+type rateChangeRepository struct {
+	store *Store
+}
+
+// NewRateChangeRepository creates a new in-memory rate change repository
+func NewRateChangeRepository(store *Store) repository.RateChangeRepository {
+	return &rateChangeRepository{store: store}
+}
+
+// GetByID retrieves a rate change by ID
+func (r *rateChangeRepository) GetByID(ctx context.Context, id uint) (*commission.RateChange, error) {
+	defer r.store.rlock(ctx)()
+
+	params, ok := r.store.rateChanges[id]
+	if !ok {
+		return nil, commission.ErrRateChangeNotFound
+	}
+	return commission.ReconstituteRateChange(params), nil
+}
+
+// GetBySubject retrieves an agent's or team's rate changes, oldest effective first
+func (r *rateChangeRepository) GetBySubject(ctx context.Context, scope commission.RateScope, subjectID uint) ([]*commission.RateChange, error) {
+	defer r.store.rlock(ctx)()
+
+	var rows []commission.RateChangeParams
+	for _, params := range r.store.rateChanges {
+		if params.Scope == string(scope) && params.SubjectID == subjectID {
+			rows = append(rows, params)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool { return effectiveBefore(rows[i], rows[j]) })
+
+	changes := make([]*commission.RateChange, len(rows))
+	for i, params := range rows {
+		changes[i] = commission.ReconstituteRateChange(params)
+	}
+	return changes, nil
+}
+
+// GetRateAt retrieves the rate change in effect for an agent or team at a time
+func (r *rateChangeRepository) GetRateAt(ctx context.Context, scope commission.RateScope, subjectID uint, at time.Time) (*commission.RateChange, error) {
+	defer r.store.rlock(ctx)()
+
+	params, ok := r.store.rateAt(scope, subjectID, at)
+	if !ok {
+		return nil, commission.ErrRateChangeNotFound
+	}
+	return commission.ReconstituteRateChange(params), nil
+}
+
+// GetEffective retrieves the rate change in effect at a time for each agent
+// or team in the scope
+func (r *rateChangeRepository) GetEffective(ctx context.Context, scope commission.RateScope, at time.Time) ([]*commission.RateChange, error) {
+	defer r.store.rlock(ctx)()
+
+	latest := make(map[uint]commission.RateChangeParams)
+	for _, params := range r.store.rateChanges {
+		if params.Scope != string(scope) || params.EffectiveFrom.After(at) {
+			continue
+		}
+		if current, ok := latest[params.SubjectID]; !ok || effectiveBefore(current, params) {
+			latest[params.SubjectID] = params
+		}
+	}
+
+	changes := make([]*commission.RateChange, 0, len(latest))
+	for _, params := range latest {
+		changes = append(changes, commission.ReconstituteRateChange(params))
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].SubjectID() < changes[j].SubjectID() })
+	return changes, nil
+}
+
+// Create saves a new rate change and assigns its ID
+func (r *rateChangeRepository) Create(ctx context.Context, c *commission.RateChange) error {
+	defer r.store.lock(ctx)()
+
+	params := commission.RateChangeParams{
+		ID:            r.store.nextID("commission_rate_changes"),
+		Scope:         string(c.Scope()),
+		SubjectID:     c.SubjectID(),
+		Rate:          c.Rate(),
+		EffectiveFrom: c.EffectiveFrom(),
+		CreatedAt:     c.CreatedAt(),
+	}
+	r.store.rateChanges[params.ID] = params
+
+	c.SetID(params.ID)
+	return nil
+}
+
+// Delete removes a rate change
+func (r *rateChangeRepository) Delete(ctx context.Context, id uint) error {
+	defer r.store.lock(ctx)()
+
+	delete(r.store.rateChanges, id)
+	return nil
+}
+
+// rateAt finds the rate change in effect for an agent or team at a time.
+// Callers must hold the lock.
+func (s *Store) rateAt(scope commission.RateScope, subjectID uint, at time.Time) (commission.RateChangeParams, bool) {
+	var found commission.RateChangeParams
+	var ok bool
+	for _, params := range s.rateChanges {
+		if params.Scope != string(scope) || params.SubjectID != subjectID || params.EffectiveFrom.After(at) {
+			continue
+		}
+		if !ok || effectiveBefore(found, params) {
+			found, ok = params, true
+		}
+	}
+	return found, ok
+}
+
+// effectiveBefore orders rate changes by when they take effect, breaking
+// ties by ID so the later change wins
+func effectiveBefore(a, b commission.RateChangeParams) bool {
+	if !a.EffectiveFrom.Equal(b.EffectiveFrom) {
+		return a.EffectiveFrom.Before(b.EffectiveFrom)
+	}
+	return a.ID < b.ID
+}
@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/payout"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+)
+
+// reservePolicyRepository implements repository.ReservePolicyRepository
+type reservePolicyRepository struct {
+	store *Store
+}
+
+// NewReservePolicyRepository creates a new in-memory reserve policy
+// repository
+func NewReservePolicyRepository(store *Store) repository.ReservePolicyRepository {
+	return &reservePolicyRepository{store: store}
+}
+
+// reservePolicyParams captures the stored state of a reserve policy
+func reservePolicyParams(p *payout.ReservePolicy) payout.ReservePolicyParams {
+	return payout.ReservePolicyParams{
+		AgentID:   p.AgentID(),
+		Percent:   p.Percent(),
+		Days:      p.Days(),
+		Reason:    p.Reason(),
+		UpdatedBy: p.UpdatedBy(),
+		CreatedAt: p.CreatedAt(),
+		UpdatedAt: p.UpdatedAt(),
+	}
+}
+
+// GetByAgentID retrieves an agent's reserve policy
+func (r *reservePolicyRepository) GetByAgentID(ctx context.Context, agentID uint) (*payout.ReservePolicy, error) {
+	defer r.store.rlock(ctx)()
+
+	params, ok := r.store.reservePolicies[agentID]
+	if !ok {
+		return nil, payout.ErrReservePolicyNotFound
+	}
+	return payout.ReconstituteReservePolicy(params), nil
+}
+
+// Save creates or replaces an agent's reserve policy
+func (r *reservePolicyRepository) Save(ctx context.Context, policy *payout.ReservePolicy) error {
+	defer r.store.lock(ctx)()
+
+	params := reservePolicyParams(policy)
+	if existing, ok := r.store.reservePolicies[policy.AgentID()]; ok {
+		params.CreatedAt = existing.CreatedAt
+	}
+	r.store.reservePolicies[policy.AgentID()] = params
+	return nil
+}
+
+// Delete removes an agent's reserve policy
+func (r *reservePolicyRepository) Delete(ctx context.Context, agentID uint) error {
+	defer r.store.lock(ctx)()
+
+	if _, ok := r.store.reservePolicies[agentID]; !ok {
+		return payout.ErrReservePolicyNotFound
+	}
+	delete(r.store.reservePolicies, agentID)
+	return nil
+}
@@ -0,0 +1,283 @@
+// Package memory provides thread-safe in-memory implementations of the
+// repository interfaces. It is used to run the service without Postgres
+// in local development and as a fake in tests.
+package memory
+
+import (
+	"context"
+	"maps"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/agent"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/commission"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/dispute"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/fx"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/ledger"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/payout"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/tax"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/team"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+)
+
+// Store holds the rows shared by all in-memory repositories.
+// Repositories built on the same Store see each other's writes, the same
+// way the GORM repositories share one database.
+type Store struct {
+	mu sync.RWMutex
+
+	agents              map[uint]agent.AgentParams
+	tierEvaluations     map[uint]agent.TierEvaluationParams
+	commissions         map[uint]commission.CommissionParams
+	clawbacks           map[uint]commission.ClawbackParams
+	autoApprovals       map[uint]commission.AutoApprovalParams
+	disputes            map[uint]dispute.DisputeParams
+	categoryCommissions map[uint]domain.AgentCategoryCommission
+	rateChanges         map[uint]commission.RateChangeParams
+	fxRates             map[uint]fx.RateParams
+	payouts             map[uint]payout.PayoutParams
+	payoutRuns          map[uint]payout.RunParams
+	payoutExports       map[uint]payout.ExportParams
+	statements          map[uint]payout.StatementParams
+	statementLines      map[uint]payout.StatementLineParams
+	payoutMethods       map[uint]payout.MethodParams
+	payoutHolds         map[uint]payout.HoldParams
+	reservePolicies     map[uint]payout.ReservePolicyParams // Keyed by agent ID
+	payoutReserves      map[uint]payout.ReserveParams
+	withholdingRules    map[uint]tax.RuleParams
+	taxProfiles         map[uint]tax.ProfileParams // Keyed by agent ID
+	ledger              map[uint]ledger.EntryParams
+	teams               map[uint]team.TeamParams
+	customers           map[uint]domain.Customer
+	orders              map[string]domain.Order
+	users               map[string]user
+
+	sequences map[string]uint
+}
+
+// user is an auth service user, keyed by email
+type user struct {
+	ID   string
+	Role string
+}
+
+// NewStore creates an empty in-memory store
+func NewStore() *Store {
+	return &Store{
+		agents:              make(map[uint]agent.AgentParams),
+		tierEvaluations:     make(map[uint]agent.TierEvaluationParams),
+		commissions:         make(map[uint]commission.CommissionParams),
+		clawbacks:           make(map[uint]commission.ClawbackParams),
+		autoApprovals:       make(map[uint]commission.AutoApprovalParams),
+		disputes:            make(map[uint]dispute.DisputeParams),
+		categoryCommissions: make(map[uint]domain.AgentCategoryCommission),
+		rateChanges:         make(map[uint]commission.RateChangeParams),
+		fxRates:             make(map[uint]fx.RateParams),
+		payouts:             make(map[uint]payout.PayoutParams),
+		payoutRuns:          make(map[uint]payout.RunParams),
+		payoutExports:       make(map[uint]payout.ExportParams),
+		statements:          make(map[uint]payout.StatementParams),
+		statementLines:      make(map[uint]payout.StatementLineParams),
+		payoutMethods:       make(map[uint]payout.MethodParams),
+		payoutHolds:         make(map[uint]payout.HoldParams),
+		reservePolicies:     make(map[uint]payout.ReservePolicyParams),
+		payoutReserves:      make(map[uint]payout.ReserveParams),
+		withholdingRules:    make(map[uint]tax.RuleParams),
+		taxProfiles:         make(map[uint]tax.ProfileParams),
+		ledger:              make(map[uint]ledger.EntryParams),
+		teams:               make(map[uint]team.TeamParams),
+		customers:           make(map[uint]domain.Customer),
+		orders:              make(map[string]domain.Order),
+		users:               make(map[string]user),
+		sequences:           make(map[string]uint),
+	}
+}
+
+// txKey marks a context whose transaction holds a store's write lock
+type txKey struct{ store *Store }
+
+// lock write-locks the store for a repository call and returns the
+// function that unlocks it. Calls within a transaction already hold the
+// lock.
+func (s *Store) lock(ctx context.Context) func() {
+	if ctx.Value(txKey{s}) != nil {
+		return func() {}
+	}
+	s.mu.Lock()
+	return s.mu.Unlock
+}
+
+// rlock read-locks the store for a repository call and returns the
+// function that unlocks it. Calls within a transaction already hold the
+// lock.
+func (s *Store) rlock(ctx context.Context) func() {
+	if ctx.Value(txKey{s}) != nil {
+		return func() {}
+	}
+	s.mu.RLock()
+	return s.mu.RUnlock
+}
+
+// snapshot copies the store's tables, to restore if a transaction fails.
+// Rows are replaced on write, never changed in place, so copying the maps
+// is enough. Callers must hold the write lock.
+func (s *Store) snapshot() *Store {
+	return &Store{
+		agents:              maps.Clone(s.agents),
+		tierEvaluations:     maps.Clone(s.tierEvaluations),
+		commissions:         maps.Clone(s.commissions),
+		clawbacks:           maps.Clone(s.clawbacks),
+		autoApprovals:       maps.Clone(s.autoApprovals),
+		disputes:            maps.Clone(s.disputes),
+		categoryCommissions: maps.Clone(s.categoryCommissions),
+		rateChanges:         maps.Clone(s.rateChanges),
+		fxRates:             maps.Clone(s.fxRates),
+		payouts:             maps.Clone(s.payouts),
+		payoutRuns:          maps.Clone(s.payoutRuns),
+		payoutExports:       maps.Clone(s.payoutExports),
+		statements:          maps.Clone(s.statements),
+		statementLines:      maps.Clone(s.statementLines),
+		payoutMethods:       maps.Clone(s.payoutMethods),
+		payoutHolds:         maps.Clone(s.payoutHolds),
+		reservePolicies:     maps.Clone(s.reservePolicies),
+		payoutReserves:      maps.Clone(s.payoutReserves),
+		withholdingRules:    maps.Clone(s.withholdingRules),
+		taxProfiles:         maps.Clone(s.taxProfiles),
+		ledger:              maps.Clone(s.ledger),
+		teams:               maps.Clone(s.teams),
+		customers:           maps.Clone(s.customers),
+		orders:              maps.Clone(s.orders),
+		users:               maps.Clone(s.users),
+		sequences:           maps.Clone(s.sequences),
+	}
+}
+
+// restore puts back the tables of a snapshot. Callers must hold the write
+// lock.
+func (s *Store) restore(snapshot *Store) {
+	s.agents = snapshot.agents
+	s.tierEvaluations = snapshot.tierEvaluations
+	s.commissions = snapshot.commissions
+	s.clawbacks = snapshot.clawbacks
+	s.autoApprovals = snapshot.autoApprovals
+	s.disputes = snapshot.disputes
+	s.categoryCommissions = snapshot.categoryCommissions
+	s.rateChanges = snapshot.rateChanges
+	s.fxRates = snapshot.fxRates
+	s.payouts = snapshot.payouts
+	s.payoutRuns = snapshot.payoutRuns
+	s.payoutExports = snapshot.payoutExports
+	s.statements = snapshot.statements
+	s.statementLines = snapshot.statementLines
+	s.payoutMethods = snapshot.payoutMethods
+	s.payoutHolds = snapshot.payoutHolds
+	s.reservePolicies = snapshot.reservePolicies
+	s.payoutReserves = snapshot.payoutReserves
+	s.withholdingRules = snapshot.withholdingRules
+	s.taxProfiles = snapshot.taxProfiles
+	s.ledger = snapshot.ledger
+	s.teams = snapshot.teams
+	s.customers = snapshot.customers
+	s.orders = snapshot.orders
+	s.users = snapshot.users
+	s.sequences = snapshot.sequences
+}
+
+// nextID returns the next auto-increment ID for a table.
+// Callers must hold the write lock.
+func (s *Store) nextID(table string) uint {
+	s.sequences[table]++
+	return s.sequences[table]
+}
+
+// paginate applies page/limit to a sorted slice. A limit of zero or less
+// returns every row.
+func paginate[T any](rows []T, page, limit int) []T {
+	if limit <= 0 {
+		return rows
+	}
+	if page < 1 {
+		page = 1
+	}
+	start := (page - 1) * limit
+	if start >= len(rows) {
+		return []T{}
+	}
+	end := start + limit
+	if end > len(rows) {
+		end = len(rows)
+	}
+	return rows[start:end]
+}
+
+// newestFirst sorts rows by creation time descending, breaking ties by
+// ID so results are stable between calls.
+func newestFirst[T any](rows []T, createdAt func(T) time.Time, id func(T) uint) {
+	sort.Slice(rows, func(i, j int) bool {
+		ci, cj := createdAt(rows[i]), createdAt(rows[j])
+		if !ci.Equal(cj) {
+			return ci.After(cj)
+		}
+		return id(rows[i]) > id(rows[j])
+	})
+}
+
+// inPeriod reports whether t falls within the period
+func inPeriod(t time.Time, period repository.Period) bool {
+	if !period.From.IsZero() && t.Before(period.From) {
+		return false
+	}
+	if !period.To.IsZero() && !t.Before(period.To) {
+		return false
+	}
+	return true
+}
+
+// containsFold is a case-insensitive substring match, like ILIKE '%s%'
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// touch sets the creation and update timestamps of a new row
+func touch(createdAt, updatedAt *time.Time) {
+	now := time.Now()
+	if createdAt.IsZero() {
+		*createdAt = now
+	}
+	*updatedAt = now
+}
+
+func copyUint(v *uint) *uint {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	return &c
+}
+
+func copyTime(v *time.Time) *time.Time {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	return &c
+}
+
+func copyBool(v *bool) *bool {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	return &c
+}
+
+func copyFloat(v *float64) *float64 {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	return &c
+}
@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/tax"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+)
+
+// taxProfileRepository implements repository.TaxProfileRepository
+type taxProfileRepository struct {
+	store *Store
+}
+
+// NewTaxProfileRepository creates a new in-memory tax profile repository
+func NewTaxProfileRepository(store *Store) repository.TaxProfileRepository {
+	return &taxProfileRepository{store: store}
+}
+
+// taxProfileParams captures the stored state of a tax profile
+func taxProfileParams(p *tax.Profile) tax.ProfileParams {
+	return tax.ProfileParams{
+		AgentID:      p.AgentID(),
+		TaxID:        p.TaxID(),
+		Residency:    p.Residency().String(),
+		RateOverride: copyFloat(p.RateOverride()),
+		UpdatedBy:    p.UpdatedBy(),
+		CreatedAt:    p.CreatedAt(),
+		UpdatedAt:    p.UpdatedAt(),
+	}
+}
+
+// GetByAgentID retrieves an agent's tax profile
+func (r *taxProfileRepository) GetByAgentID(ctx context.Context, agentID uint) (*tax.Profile, error) {
+	defer r.store.rlock(ctx)()
+
+	params, ok := r.store.taxProfiles[agentID]
+	if !ok {
+		return nil, tax.ErrProfileNotFound
+	}
+	params.RateOverride = copyFloat(params.RateOverride)
+	return tax.ReconstituteProfile(params), nil
+}
+
+// Save creates or replaces an agent's tax profile
+func (r *taxProfileRepository) Save(ctx context.Context, profile *tax.Profile) error {
+	defer r.store.lock(ctx)()
+
+	params := taxProfileParams(profile)
+	if existing, ok := r.store.taxProfiles[profile.AgentID()]; ok {
+		params.CreatedAt = existing.CreatedAt
+	}
+	r.store.taxProfiles[profile.AgentID()] = params
+	return nil
+}
@@ -0,0 +1,148 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/agent"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/team"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+)
+
+// teamRepository implements repository.TeamRepository
+type teamRepository struct {
+	store *Store
+}
+
+// NewTeamRepository creates a new in-memory team repository
+func NewTeamRepository(store *Store) repository.TeamRepository {
+	return &teamRepository{store: store}
+}
+
+// teamParams captures the stored state of a team
+func teamParams(t *team.Team) team.TeamParams {
+	return team.TeamParams{
+		ID:             t.ID(),
+		Code:           t.Code(),
+		Name:           t.Name(),
+		Description:    t.Description(),
+		LeaderID:       copyUint(t.LeaderID()),
+		TargetMonthly:  t.TargetMonthly(),
+		CommissionRate: t.CommissionRate(),
+		IsActive:       t.IsActive(),
+		CreatedAt:      t.CreatedAt(),
+		UpdatedAt:      t.UpdatedAt(),
+	}
+}
+
+func reconstituteTeam(params team.TeamParams) *team.Team {
+	params.LeaderID = copyUint(params.LeaderID)
+	return team.Reconstitute(params)
+}
+
+// GetByID retrieves a team by ID
+func (r *teamRepository) GetByID(ctx context.Context, id uint) (*team.Team, error) {
+	defer r.store.rlock(ctx)()
+
+	params, ok := r.store.teams[id]
+	if !ok {
+		return nil, team.ErrTeamNotFound
+	}
+	return reconstituteTeam(params), nil
+}
+
+// GetByAgentID retrieves the team an agent belongs to
+func (r *teamRepository) GetByAgentID(ctx context.Context, agentID uint) (*team.Team, error) {
+	defer r.store.rlock(ctx)()
+
+	agentParams, ok := r.store.agents[agentID]
+	if !ok {
+		return nil, agent.ErrAgentNotFound
+	}
+	if agentParams.TeamID == nil {
+		return nil, team.ErrTeamNotFound
+	}
+
+	params, ok := r.store.teams[*agentParams.TeamID]
+	if !ok {
+		return nil, team.ErrTeamNotFound
+	}
+	return reconstituteTeam(params), nil
+}
+
+// GetMembers retrieves all agents in a team
+func (r *teamRepository) GetMembers(ctx context.Context, teamID uint) ([]*agent.Agent, error) {
+	defer r.store.rlock(ctx)()
+
+	var rows []agent.AgentParams
+	for _, params := range r.store.agents {
+		if params.TeamID != nil && *params.TeamID == teamID {
+			rows = append(rows, params)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Name < rows[j].Name })
+
+	members := make([]*agent.Agent, len(rows))
+	for i, params := range rows {
+		members[i] = r.store.reconstituteAgent(params)
+	}
+	return members, nil
+}
+
+// Create creates a new team and assigns its ID
+func (r *teamRepository) Create(ctx context.Context, t *team.Team) error {
+	defer r.store.lock(ctx)()
+
+	params := teamParams(t)
+	params.ID = r.store.nextID("teams")
+	touch(&params.CreatedAt, &params.UpdatedAt)
+	r.store.teams[params.ID] = params
+
+	t.SetID(params.ID)
+	return nil
+}
+
+// Update saves all team fields
+func (r *teamRepository) Update(ctx context.Context, t *team.Team) error {
+	defer r.store.lock(ctx)()
+
+	existing, ok := r.store.teams[t.ID()]
+	if !ok {
+		return team.ErrTeamNotFound
+	}
+
+	params := teamParams(t)
+	params.CreatedAt = existing.CreatedAt
+	params.UpdatedAt = time.Now()
+	r.store.teams[params.ID] = params
+	return nil
+}
+
+// AddMember assigns an agent to a team
+func (r *teamRepository) AddMember(ctx context.Context, teamID, agentID uint) error {
+	defer r.store.lock(ctx)()
+
+	params, ok := r.store.agents[agentID]
+	if !ok {
+		return agent.ErrAgentNotFound
+	}
+	params.TeamID = &teamID
+	params.UpdatedAt = time.Now()
+	r.store.agents[agentID] = params
+	return nil
+}
+
+// RemoveMember removes an agent from a team
+func (r *teamRepository) RemoveMember(ctx context.Context, teamID, agentID uint) error {
+	defer r.store.lock(ctx)()
+
+	params, ok := r.store.agents[agentID]
+	if !ok || params.TeamID == nil || *params.TeamID != teamID {
+		return nil
+	}
+	params.TeamID = nil
+	params.UpdatedAt = time.Now()
+	r.store.agents[agentID] = params
+	return nil
+}
@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/agent"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+)
+
+// tierEvaluationRepository implements repository.TierEvaluationRepository
+type tierEvaluationRepository struct {
+	store *Store
+}
+
+// NewTierEvaluationRepository creates a new in-memory tier evaluation repository
+func NewTierEvaluationRepository(store *Store) repository.TierEvaluationRepository {
+	return &tierEvaluationRepository{store: store}
+}
+
+// GetByAgentID retrieves an agent's tier evaluations, newest first
+func (r *tierEvaluationRepository) GetByAgentID(ctx context.Context, agentID uint, page, limit int) ([]*agent.TierEvaluation, int64, error) {
+	defer r.store.rlock(ctx)()
+
+	var rows []agent.TierEvaluationParams
+	for _, params := range r.store.tierEvaluations {
+		if params.AgentID == agentID {
+			rows = append(rows, params)
+		}
+	}
+
+	newestFirst(rows,
+		func(p agent.TierEvaluationParams) time.Time { return p.EvaluatedAt },
+		func(p agent.TierEvaluationParams) uint { return p.ID })
+
+	paged := paginate(rows, page, limit)
+	evaluations := make([]*agent.TierEvaluation, len(paged))
+	for i, params := range paged {
+		params.Reasons = append([]string(nil), params.Reasons...)
+		evaluations[i] = agent.ReconstituteTierEvaluation(params)
+	}
+	return evaluations, int64(len(rows)), nil
+}
+
+// Create records a tier evaluation and assigns its ID
+func (r *tierEvaluationRepository) Create(ctx context.Context, e *agent.TierEvaluation) error {
+	defer r.store.lock(ctx)()
+
+	params := agent.TierEvaluationParams{
+		ID:           r.store.nextID("agent_tier_evaluations"),
+		AgentID:      e.AgentID(),
+		PreviousTier: e.PreviousTier().String(),
+		NewTier:      e.NewTier().String(),
+		Metrics:      e.Metrics(),
+		PeriodFrom:   e.PeriodFrom(),
+		PeriodTo:     e.PeriodTo(),
+		Reasons:      append([]string(nil), e.Reasons()...),
+		EvaluatedAt:  e.EvaluatedAt(),
+	}
+	r.store.tierEvaluations[params.ID] = params
+
+	e.SetID(params.ID)
+	return nil
+}
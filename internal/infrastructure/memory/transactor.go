@@ -0,0 +1,38 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+)
+
+// transactor implements repository.Transactor
+type transactor struct {
+	store *Store
+}
+
+// NewTransactor creates a new in-memory transactor. A transaction holds
+// the store's write lock until it ends, so transactions and other
+// repository calls run one at a time, and restores the store as it was if
+// it fails.
+func NewTransactor(store *Store) repository.Transactor {
+	return &transactor{store: store}
+}
+
+// Transaction runs fn in a transaction, or within the one ctx is already in
+func (t *transactor) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	s := t.store
+	if ctx.Value(txKey{s}) != nil {
+		return fn(ctx)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot := s.snapshot()
+	if err := fn(context.WithValue(ctx, txKey{s}, true)); err != nil {
+		s.restore(snapshot)
+		return err
+	}
+	return nil
+}
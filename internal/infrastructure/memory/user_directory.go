@@ -0,0 +1,41 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+)
+
+// userDirectory implements repository.UserDirectory
+type userDirectory struct {
+	store *Store
+}
+
+// NewUserDirectory creates a new in-memory auth user directory
+func NewUserDirectory(store *Store) repository.UserDirectory {
+	return &userDirectory{store: store}
+}
+
+// GetUserIDByEmail looks up the auth user UUID for an email
+func (r *userDirectory) GetUserIDByEmail(ctx context.Context, email string) (string, error) {
+	defer r.store.rlock(ctx)()
+
+	u, ok := r.store.users[email]
+	if !ok {
+		return "", repository.ErrNotFound
+	}
+	return u.ID, nil
+}
+
+// SetRole updates the role of an auth user
+func (r *userDirectory) SetRole(ctx context.Context, email, role string) error {
+	defer r.store.lock(ctx)()
+
+	u, ok := r.store.users[email]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	u.Role = role
+	r.store.users[email] = u
+	return nil
+}
@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/tax"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+)
+
+// withholdingRuleRepository implements repository.WithholdingRuleRepository
+type withholdingRuleRepository struct {
+	store *Store
+}
+
+// NewWithholdingRuleRepository creates a new in-memory withholding rule
+// repository
+func NewWithholdingRuleRepository(store *Store) repository.WithholdingRuleRepository {
+	return &withholdingRuleRepository{store: store}
+}
+
+// withholdingRuleParams captures the stored state of a withholding rule
+func withholdingRuleParams(r *tax.Rule) tax.RuleParams {
+	return tax.RuleParams{
+		ID:           r.ID(),
+		Name:         r.Name(),
+		Residency:    r.Residency().String(),
+		TaxIDPresent: copyBool(r.TaxIDPresent()),
+		Rate:         r.Rate(),
+		Active:       r.IsActive(),
+		CreatedAt:    r.CreatedAt(),
+		UpdatedAt:    r.UpdatedAt(),
+	}
+}
+
+func reconstituteWithholdingRule(params tax.RuleParams) *tax.Rule {
+	params.TaxIDPresent = copyBool(params.TaxIDPresent)
+	return tax.ReconstituteRule(params)
+}
+
+// GetByID retrieves a withholding rule by ID
+func (r *withholdingRuleRepository) GetByID(ctx context.Context, id uint) (*tax.Rule, error) {
+	defer r.store.rlock(ctx)()
+
+	params, ok := r.store.withholdingRules[id]
+	if !ok {
+		return nil, tax.ErrRuleNotFound
+	}
+	return reconstituteWithholdingRule(params), nil
+}
+
+// List lists every withholding rule, oldest first
+func (r *withholdingRuleRepository) List(ctx context.Context) ([]*tax.Rule, error) {
+	defer r.store.rlock(ctx)()
+
+	rows := make([]tax.RuleParams, 0, len(r.store.withholdingRules))
+	for _, params := range r.store.withholdingRules {
+		rows = append(rows, params)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].ID < rows[j].ID })
+
+	rules := make([]*tax.Rule, len(rows))
+	for i, params := range rows {
+		rules[i] = reconstituteWithholdingRule(params)
+	}
+	return rules, nil
+}
+
+// Create saves a new withholding rule and assigns its ID
+func (r *withholdingRuleRepository) Create(ctx context.Context, rule *tax.Rule) error {
+	defer r.store.lock(ctx)()
+
+	rule.SetID(r.store.nextID("tax_withholding_rules"))
+	r.store.withholdingRules[rule.ID()] = withholdingRuleParams(rule)
+	return nil
+}
+
+// Update saves a withholding rule
+func (r *withholdingRuleRepository) Update(ctx context.Context, rule *tax.Rule) error {
+	defer r.store.lock(ctx)()
+
+	existing, ok := r.store.withholdingRules[rule.ID()]
+	if !ok {
+		return tax.ErrRuleNotFound
+	}
+	params := withholdingRuleParams(rule)
+	params.CreatedAt = existing.CreatedAt
+	params.UpdatedAt = time.Now()
+	r.store.withholdingRules[rule.ID()] = params
+	return nil
+}
+
+// Delete removes a withholding rule
+func (r *withholdingRuleRepository) Delete(ctx context.Context, id uint) error {
+	defer r.store.lock(ctx)()
+
+	if _, ok := r.store.withholdingRules[id]; !ok {
+		return tax.ErrRuleNotFound
+	}
+	delete(r.store.withholdingRules, id)
+	return nil
+}
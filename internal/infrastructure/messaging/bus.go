@@ -0,0 +1,47 @@
+// Package messaging provides the publish/subscribe message bus used to
+// exchange events with other services.
+package messaging
+
+import (
+	"context"
+	"strings"
+)
+
+// Message is a message delivered by the bus
+type Message struct {
+	Subject string
+	Data    []byte
+}
+
+// Handler processes a message delivered to a subscription
+type Handler func(ctx context.Context, msg Message) error
+
+// Bus is a pluggable publish/subscribe message bus.
+//
+// Subjects are dot-separated tokens. Subscriptions may use NATS-style
+// wildcards: "*" matches exactly one token and a trailing ">" matches one
+// or more tokens.
+type Bus interface {
+	Publish(ctx context.Context, subject string, data []byte) error
+	Subscribe(subject string, handler Handler) error
+	Close() error
+}
+
+// subjectMatches reports whether a subject matches a subscription pattern
+func subjectMatches(pattern, subject string) bool {
+	patternTokens := strings.Split(pattern, ".")
+	subjectTokens := strings.Split(subject, ".")
+
+	for i, token := range patternTokens {
+		if token == ">" {
+			return i == len(patternTokens)-1 && len(subjectTokens) > i
+		}
+		if i >= len(subjectTokens) {
+			return false
+		}
+		if token != "*" && token != subjectTokens[i] {
+			return false
+		}
+	}
+	return len(patternTokens) == len(subjectTokens)
+}
@@ -0,0 +1,78 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrBusClosed is returned when publishing to or subscribing on a closed bus
+var ErrBusClosed = errors.New("message bus is closed")
+
+// InProcessBus is a Bus that delivers messages synchronously to
+// subscribers in the same process. It is used for local development and
+// tests where no NATS server is available.
+type InProcessBus struct {
+	mu            sync.RWMutex
+	subscriptions []subscription
+	closed        bool
+}
+
+type subscription struct {
+	pattern string
+	handler Handler
+}
+
+// NewInProcessBus creates a new in-process message bus
+func NewInProcessBus() *InProcessBus {
+	return &InProcessBus{}
+}
+
+// Publish delivers a message to every matching subscriber before
+// returning. Handler errors are joined and returned to the publisher.
+func (b *InProcessBus) Publish(ctx context.Context, subject string, data []byte) error {
+	b.mu.RLock()
+	if b.closed {
+		b.mu.RUnlock()
+		return ErrBusClosed
+	}
+	var handlers []Handler
+	for _, sub := range b.subscriptions {
+		if subjectMatches(sub.pattern, subject) {
+			handlers = append(handlers, sub.handler)
+		}
+	}
+	b.mu.RUnlock()
+
+	msg := Message{Subject: subject, Data: append([]byte(nil), data...)}
+
+	var errs []error
+	for _, handler := range handlers {
+		if err := handler(ctx, msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Subscribe registers a handler for a subject pattern
+func (b *InProcessBus) Subscribe(subject string, handler Handler) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return ErrBusClosed
+	}
+	b.subscriptions = append(b.subscriptions, subscription{pattern: subject, handler: handler})
+	return nil
+}
+
+// Close removes all subscriptions and rejects further use of the bus
+func (b *InProcessBus) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	b.subscriptions = nil
+	return nil
+}
@@ -0,0 +1,65 @@
+package messaging
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+)
+
+// NATSBus is a Bus backed by a NATS server.
+//
+// Subscriptions join a queue group, so when several replicas of the
+// service are running each message is handled by only one of them.
+type NATSBus struct {
+	conn       *nats.Conn
+	queueGroup string
+}
+
+// NewNATSBus connects to the NATS server at url. Subscriptions are made
+// in the given queue group.
+func NewNATSBus(url, queueGroup string) (*NATSBus, error) {
+	conn, err := nats.Connect(url,
+		nats.Name(queueGroup),
+		nats.MaxReconnects(-1),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			if err != nil {
+				log.Warn().Err(err).Msg("Disconnected from NATS")
+			}
+		}),
+		nats.ReconnectHandler(func(conn *nats.Conn) {
+			log.Info().Str("url", conn.ConnectedUrl()).Msg("Reconnected to NATS")
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+
+	return &NATSBus{conn: conn, queueGroup: queueGroup}, nil
+}
+
+// Publish publishes a message to a subject
+func (b *NATSBus) Publish(ctx context.Context, subject string, data []byte) error {
+	return b.conn.Publish(subject, data)
+}
+
+// Subscribe registers a handler for a subject pattern. Handler errors are
+// logged; core NATS has no redelivery.
+func (b *NATSBus) Subscribe(subject string, handler Handler) error {
+	_, err := b.conn.QueueSubscribe(subject, b.queueGroup, func(m *nats.Msg) {
+		msg := Message{Subject: m.Subject, Data: m.Data}
+		if err := handler(context.Background(), msg); err != nil {
+			log.Error().Err(err).Str("subject", m.Subject).Msg("Failed to handle message")
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", subject, err)
+	}
+	return nil
+}
+
+// Close drains subscriptions and closes the connection
+func (b *NATSBus) Close() error {
+	return b.conn.Drain()
+}
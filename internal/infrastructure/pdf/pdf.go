@@ -0,0 +1,355 @@
+// Package pdf writes simple printable documents, such as statements, as
+// PDF without any external service or library. Documents flow top to
+// bottom over A4 pages in the standard Helvetica fonts: headings, text,
+// key-value blocks and tables, with page breaks and page numbers added as
+// needed.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// A4 page size and margins, in points
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+	Margin     = 50.0
+
+	// ContentWidth is the width between the margins
+	ContentWidth = PageWidth - 2*Margin
+
+	footerSize = 8.0
+)
+
+// Font is one of the standard fonts every PDF reader has
+type Font int
+
+// Standard fonts
+const (
+	Regular Font = iota
+	Bold
+)
+
+// resource returns the font's resource name in page content
+func (f Font) resource() string {
+	if f == Bold {
+		return "/F2"
+	}
+	return "/F1"
+}
+
+// Align is how text sits in a table column
+type Align int
+
+// Column alignments
+const (
+	Left Align = iota
+	Right
+)
+
+// Column is a table column, with its share of the content width
+type Column struct {
+	Header string
+	Width  float64 // Fraction of ContentWidth
+	Align  Align
+}
+
+// Document is a PDF being written. The zero value is not usable; create
+// documents with New.
+type Document struct {
+	title   string
+	created time.Time
+	footer  string
+	pages   []*bytes.Buffer
+	y       float64 // Distance of the cursor from the top of the page
+}
+
+// New creates a document with one empty page. footer is printed at the
+// bottom of every page, with the page number.
+func New(title, footer string) *Document {
+	d := &Document{title: title, footer: footer, created: time.Now()}
+	d.AddPage()
+	return d
+}
+
+// AddPage starts a new page and moves the cursor to its top
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.y = Margin
+}
+
+// Heading writes a bold heading
+func (d *Document) Heading(text string, size float64) {
+	d.ensure(size * 1.6)
+	d.y += size
+	d.text(Margin, d.y, Bold, size, text)
+	d.y += size * 0.6
+}
+
+// Text writes a paragraph, wrapped to the content width
+func (d *Document) Text(font Font, size float64, text string) {
+	for _, line := range wrap(font, size, text, ContentWidth) {
+		d.ensure(size * 1.4)
+		d.y += size
+		d.text(Margin, d.y, font, size, line)
+		d.y += size * 0.4
+	}
+}
+
+// Space moves the cursor down
+func (d *Document) Space(height float64) {
+	d.y += height
+}
+
+// Rule draws a horizontal line across the content width
+func (d *Document) Rule() {
+	d.ensure(6)
+	d.y += 3
+	d.line(Margin, d.y, PageWidth-Margin, d.y, 0.5)
+	d.y += 3
+}
+
+// KeyValues writes label and value pairs in two columns
+func (d *Document) KeyValues(size float64, pairs [][2]string) {
+	labelWidth := 0.0
+	for _, pair := range pairs {
+		if w := TextWidth(Bold, size, pair[0]); w > labelWidth {
+			labelWidth = w
+		}
+	}
+	for _, pair := range pairs {
+		d.ensure(size * 1.5)
+		d.y += size
+		d.text(Margin, d.y, Bold, size, pair[0])
+		d.text(Margin+labelWidth+12, d.y, Regular, size, pair[1])
+		d.y += size * 0.5
+	}
+}
+
+// Table writes a table with a header row, repeated at the top of every
+// page the table continues on. Rows whose first cell starts with "=" are
+// totals: bold, with a line above and the "=" removed.
+func (d *Document) Table(size float64, columns []Column, rows [][]string) {
+	rowHeight := size * 1.6
+	header := func() {
+		d.y += size
+		d.row(size, columns, Bold, func(i int) string { return columns[i].Header })
+		d.y += size * 0.3
+		d.line(Margin, d.y, PageWidth-Margin, d.y, 0.75)
+		d.y += size * 0.3
+	}
+
+	d.ensure(rowHeight * 2)
+	header()
+	for _, cells := range rows {
+		if d.y+rowHeight > PageHeight-Margin {
+			d.AddPage()
+			header()
+		}
+		font := Regular
+		if len(cells) > 0 && strings.HasPrefix(cells[0], "=") {
+			font = Bold
+			cells = append([]string{strings.TrimPrefix(cells[0], "=")}, cells[1:]...)
+			d.line(Margin, d.y, PageWidth-Margin, d.y, 0.5)
+		}
+		d.y += size
+		d.row(size, columns, font, func(i int) string {
+			if i < len(cells) {
+				return cells[i]
+			}
+			return ""
+		})
+		d.y += size * 0.6
+	}
+}
+
+// Bytes returns the finished PDF
+func (d *Document) Bytes() []byte {
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	// Objects 1-5 are fixed; each page then takes two: the page and its
+	// content stream
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 6+2*i)
+	}
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	object(fmt.Sprintf("<< /Title %s /Producer (service-agent) /CreationDate (D:%s) >>",
+		literal(d.title), d.created.UTC().Format("20060102150405Z")))
+
+	for i, page := range d.pages {
+		footer := fmt.Sprintf("Page %d of %d", i+1, len(d.pages))
+		if d.footer != "" {
+			footer = d.footer + "  |  " + footer
+		}
+		content := page.String() + textOp(Margin, Margin/2, Regular, footerSize, footer)
+
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			PageWidth, PageHeight, 7+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(offsets)+1, xref)
+	return out.Bytes()
+}
+
+// ensure starts a new page if the next height would run into the bottom
+// margin
+func (d *Document) ensure(height float64) {
+	if d.y+height > PageHeight-Margin {
+		d.AddPage()
+	}
+}
+
+// row writes one table row on the cursor's baseline
+func (d *Document) row(size float64, columns []Column, font Font, cell func(int) string) {
+	x := Margin
+	for i, column := range columns {
+		width := column.Width * ContentWidth
+		text := truncate(font, size, cell(i), width-6)
+		if column.Align == Right {
+			d.text(x+width-TextWidth(font, size, text), d.y, font, size, text)
+		} else {
+			d.text(x, d.y, font, size, text)
+		}
+		x += width
+	}
+}
+
+// text writes text with its baseline y points from the top of the page
+func (d *Document) text(x, y float64, font Font, size float64, text string) {
+	d.pages[len(d.pages)-1].WriteString(textOp(x, PageHeight-y, font, size, text))
+}
+
+// line draws a line between points measured from the top of the page
+func (d *Document) line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(d.pages[len(d.pages)-1], "%.2f w %.2f %.2f m %.2f %.2f l S\n",
+		width, x1, PageHeight-y1, x2, PageHeight-y2)
+}
+
+// textOp returns the content operators showing text at a point measured
+// from the bottom of the page
+func textOp(x, y float64, font Font, size float64, text string) string {
+	return fmt.Sprintf("BT %s %.1f Tf %.2f %.2f Td %s Tj ET\n", font.resource(), size, x, y, literal(text))
+}
+
+// literal encodes text as a PDF string literal in WinAnsiEncoding.
+// Characters the standard fonts cannot show are replaced with "?".
+func literal(text string) string {
+	var b strings.Builder
+	b.WriteByte('(')
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 32 && r < 127:
+			b.WriteRune(r)
+		case r >= 160 && r <= 255:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			if c, ok := winAnsi[r]; ok {
+				fmt.Fprintf(&b, "\\%03o", c)
+			} else {
+				b.WriteByte('?')
+			}
+		}
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+// winAnsi maps the common characters WinAnsiEncoding places in 128-159
+var winAnsi = map[rune]byte{
+	'€': 128, '‚': 130, '„': 132, '…': 133, '‘': 145, '’': 146, '“': 147, '”': 148,
+	'•': 149, '–': 150, '—': 151, '™': 153,
+}
+
+// wrap breaks text into lines no wider than width
+func wrap(font Font, size float64, text string, width float64) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if line != "" && TextWidth(font, size, candidate) > width {
+				lines = append(lines, line)
+				candidate = word
+			}
+			line = candidate
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// truncate shortens text with "..." to fit width
+func truncate(font Font, size float64, text string, width float64) string {
+	if TextWidth(font, size, text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && TextWidth(font, size, string(runes)+"...") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
+
+// TextWidth returns the width of text in points
+func TextWidth(font Font, size float64, text string) float64 {
+	widths := &helvetica
+	if font == Bold {
+		widths = &helveticaBold
+	}
+	total := 0
+	for _, r := range text {
+		if r >= 32 && r < 127 {
+			total += widths[r-32]
+		} else {
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// Glyph widths of the printable ASCII characters, from the fonts' metrics
+var (
+	helvetica = [95]int{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	}
+	helveticaBold = [95]int{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	}
+)
@@ -2,8 +2,11 @@
 package persistence
 
 import (
+	"fmt"
 	"time"
 
+	"github.com/Ecom-micro-template/service-agent/internal/domain/agent"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
 	"gorm.io/gorm"
 )
 
@@ -17,8 +20,10 @@ type AgentModel struct {
 	CommissionRate float64   `gorm:"type:decimal(5,2);default:10.0" json:"commission_rate"`
 	Tier           string    `gorm:"size:20;default:'bronze'" json:"tier"`
 	Status         string    `gorm:"size:20;default:'active'" json:"status"`
-	TotalEarned    float64   `gorm:"type:decimal(10,2);default:0" json:"total_earned"`
+	TotalEarned    float64   `gorm:"->;-:migration" json:"total_earned"` // Derived from the ledger, see withTotalEarned
+	PayoutCurrency string    `gorm:"size:3;not null;default:'MYR'" json:"payout_currency"`
 	TeamID         *uint     `gorm:"index" json:"team_id,omitempty"`
+	ParentID       *uint     `gorm:"index" json:"parent_id,omitempty"`
 	CreatedAt      time.Time `json:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at"`
 
@@ -35,6 +40,12 @@ func (AgentModel) TableName() string {
 
 // BeforeCreate hook to set defaults.
 func (m *AgentModel) BeforeCreate(tx *gorm.DB) error {
+	if m.Code == "" {
+		// Generate agent code: AGT + ID padded to 4 digits
+		var count int64
+		tx.Model(&AgentModel{}).Count(&count)
+		m.Code = fmt.Sprintf("AGT%04d", count+1)
+	}
 	if m.Status == "" {
 		m.Status = "active"
 	}
@@ -46,3 +57,56 @@ func (m *AgentModel) BeforeCreate(tx *gorm.DB) error {
 	}
 	return nil
 }
+
+// totalEarnedQuery derives an agent's total earnings from their ledger in
+// the payout currency: the available, in payout, paid out, tax withheld
+// and reserve balances less clawbacks still owed. Lines are signed debits,
+// so the credit-normal balances and the debit-normal receivable net out in
+// a single negated sum.
+const totalEarnedQuery = `SELECT COALESCE(-SUM(l.amount), 0) FROM agent_ledger_lines l
+	WHERE l.agent_id = agents.id AND l.currency = agents.payout_currency
+	AND l.account IN ('available', 'in_payout', 'paid_out', 'tax_withheld', 'reserve', 'clawback_receivable')`
+
+// withTotalEarned selects agents with their derived total earnings.
+func withTotalEarned(db *gorm.DB) *gorm.DB {
+	return db.Select("agents.*, (" + totalEarnedQuery + ") AS total_earned")
+}
+
+// toDomain converts the persistence model to the Agent aggregate.
+func (m *AgentModel) toDomain() *agent.Agent {
+	return agent.Reconstitute(agent.AgentParams{
+		ID:             m.ID,
+		Code:           m.Code,
+		Name:           m.Name,
+		Email:          m.Email,
+		Phone:          m.Phone,
+		CommissionRate: m.CommissionRate,
+		Tier:           m.Tier,
+		Status:         m.Status,
+		TeamID:         m.TeamID,
+		ParentID:       m.ParentID,
+		PayoutCurrency: m.PayoutCurrency,
+		TotalEarned:    shared.MoneyFromFloat(m.TotalEarned, m.PayoutCurrency),
+		CreatedAt:      m.CreatedAt,
+		UpdatedAt:      m.UpdatedAt,
+	})
+}
+
+// newAgentModel converts the Agent aggregate to its persistence model.
+func newAgentModel(a *agent.Agent) *AgentModel {
+	return &AgentModel{
+		ID:             a.ID(),
+		Code:           a.Code(),
+		Name:           a.Name(),
+		Email:          a.Email(),
+		Phone:          a.Phone(),
+		CommissionRate: a.CommissionRate().Value(),
+		Tier:           a.Tier().String(),
+		Status:         a.Status().String(),
+		PayoutCurrency: a.PayoutCurrency(),
+		TeamID:         a.TeamID(),
+		ParentID:       a.ParentID(),
+		CreatedAt:      a.CreatedAt(),
+		UpdatedAt:      a.UpdatedAt(),
+	}
+}
@@ -0,0 +1,187 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/agent"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"gorm.io/gorm"
+)
+
+// agentRepository implements repository.AgentRepository
+type agentRepository struct {
+	db *gorm.DB
+}
+
+// NewAgentRepository creates a new agent repository
+func NewAgentRepository(db *gorm.DB) repository.AgentRepository {
+	return &agentRepository{db: db}
+}
+
+// GetByID retrieves an agent by ID
+func (r *agentRepository) GetByID(ctx context.Context, id uint) (*agent.Agent, error) {
+	return r.first(ctx, "id = ?", id)
+}
+
+// GetByEmail retrieves an agent by email
+func (r *agentRepository) GetByEmail(ctx context.Context, email string) (*agent.Agent, error) {
+	return r.first(ctx, "email = ?", email)
+}
+
+// GetByCode retrieves an agent by agent code
+func (r *agentRepository) GetByCode(ctx context.Context, code string) (*agent.Agent, error) {
+	return r.first(ctx, "code = ?", code)
+}
+
+func (r *agentRepository) first(ctx context.Context, query string, args ...interface{}) (*agent.Agent, error) {
+	var model AgentModel
+	if err := withTotalEarned(conn(ctx, r.db)).Where(query, args...).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, agent.ErrAgentNotFound
+		}
+		return nil, err
+	}
+	return model.toDomain(), nil
+}
+
+// List retrieves agents matching the filter, newest first
+func (r *agentRepository) List(ctx context.Context, filter repository.AgentFilter) ([]*agent.Agent, int64, error) {
+	query := conn(ctx, r.db).Model(&AgentModel{})
+
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	} else if !filter.IncludeInactive {
+		query = query.Where("status != ?", shared.AgentStatusInactive)
+	}
+	if filter.Tier != "" {
+		query = query.Where("tier = ?", filter.Tier)
+	}
+	if filter.ParentID != nil {
+		query = query.Where("parent_id = ?", *filter.ParentID)
+	}
+	if filter.Search != "" {
+		like := "%" + filter.Search + "%"
+		query = query.Where("name ILIKE ? OR email ILIKE ? OR code ILIKE ?", like, like, like)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var models []AgentModel
+	if err := paginate(withTotalEarned(query), filter.Page, filter.Limit).Order("created_at DESC").Find(&models).Error; err != nil {
+		return nil, 0, err
+	}
+
+	agents := make([]*agent.Agent, len(models))
+	for i := range models {
+		agents[i] = models[i].toDomain()
+	}
+	return agents, total, nil
+}
+
+// GetStats retrieves commission, payout and customer statistics for an agent
+func (r *agentRepository) GetStats(ctx context.Context, agentID uint) (*repository.AgentStats, error) {
+	db := conn(ctx, r.db)
+	stats := &repository.AgentStats{}
+
+	db.Model(&CommissionModel{}).
+		Where("agent_id = ?", agentID).
+		Count(&stats.TotalCommissions)
+	db.Model(&CommissionModel{}).
+		Where("agent_id = ?", agentID).
+		Select("COALESCE(SUM(" + netPayoutAmountSQL + "), 0)").
+		Scan(&stats.TotalCommission)
+
+	db.Model(&CommissionModel{}).
+		Where("agent_id = ? AND status = ?", agentID, shared.CommissionPending).
+		Count(&stats.PendingCommissions)
+	db.Model(&CommissionModel{}).
+		Where("agent_id = ? AND status = ?", agentID, shared.CommissionPending).
+		Select("COALESCE(SUM(" + netPayoutAmountSQL + "), 0)").
+		Scan(&stats.PendingCommission)
+
+	db.Model(&CommissionModel{}).
+		Where("agent_id = ? AND status IN ?", agentID, []shared.CommissionStatus{shared.CommissionPaid, shared.CommissionPartiallyReversed}).
+		Select("COALESCE(SUM(" + netPayoutAmountSQL + "), 0)").
+		Scan(&stats.PaidCommission)
+
+	now := time.Now()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	db.Model(&CommissionModel{}).
+		Where("agent_id = ? AND created_at >= ?", agentID, monthStart).
+		Select("COALESCE(SUM(" + netPayoutAmountSQL + "), 0)").
+		Scan(&stats.ThisMonthCommission)
+
+	db.Model(&PayoutModel{}).
+		Where("agent_id = ?", agentID).
+		Count(&stats.TotalPayouts)
+
+	db.Table("customers").
+		Where("agent_id = ?", agentID).
+		Count(&stats.TotalCustomers)
+
+	db.Model(&CommissionModel{}).
+		Where("agent_id = ?", agentID).
+		Distinct("order_id").
+		Count(&stats.TotalOrders)
+
+	return stats, nil
+}
+
+// Create creates a new agent and assigns its ID and code
+func (r *agentRepository) Create(ctx context.Context, a *agent.Agent) error {
+	model := newAgentModel(a)
+	if err := conn(ctx, r.db).Create(model).Error; err != nil {
+		return err
+	}
+	a.AssignIdentity(model.ID, model.Code)
+	return nil
+}
+
+// Update saves all agent fields
+func (r *agentRepository) Update(ctx context.Context, a *agent.Agent) error {
+	return conn(ctx, r.db).Save(newAgentModel(a)).Error
+}
+
+// Delete soft deletes an agent by marking it inactive
+func (r *agentRepository) Delete(ctx context.Context, id uint) error {
+	return r.UpdateStatus(ctx, id, shared.AgentStatusInactive)
+}
+
+// UpdateStatus updates the status of an agent
+func (r *agentRepository) UpdateStatus(ctx context.Context, id uint, status shared.AgentStatus) error {
+	return r.updateColumn(ctx, id, "status", status.String())
+}
+
+// UpdateTier updates the tier of an agent
+func (r *agentRepository) UpdateTier(ctx context.Context, id uint, tier shared.AgentTier) error {
+	return r.updateColumn(ctx, id, "tier", tier.String())
+}
+
+func (r *agentRepository) updateColumn(ctx context.Context, id uint, column string, value interface{}) error {
+	result := conn(ctx, r.db).Model(&AgentModel{}).Where("id = ?", id).Update(column, value)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return agent.ErrAgentNotFound
+	}
+	return nil
+}
+
+// paginate applies page/limit to a query. A limit of zero or less
+// returns every row.
+func paginate(query *gorm.DB, page, limit int) *gorm.DB {
+	if limit <= 0 {
+		return query
+	}
+	if page < 1 {
+		page = 1
+	}
+	return query.Offset((page - 1) * limit).Limit(limit)
+}
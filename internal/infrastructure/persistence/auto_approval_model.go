@@ -0,0 +1,59 @@
+package persistence
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/commission"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+)
+
+// AutoApprovalModel is the GORM persistence model for AutoApproval.
+type AutoApprovalModel struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	CommissionID uint      `gorm:"not null;uniqueIndex" json:"commission_id"`
+	AgentID      uint      `gorm:"not null;index" json:"agent_id"`
+	Rule         string    `gorm:"size:50;not null" json:"rule"`
+	Amount       float64   `gorm:"type:decimal(10,2);not null" json:"amount"`
+	Currency     string    `gorm:"size:3;not null;default:'MYR'" json:"currency"`
+	Reasons      string    `gorm:"type:text" json:"reasons"` // JSON array of reasons
+	ApprovedAt   time.Time `gorm:"index" json:"approved_at"`
+}
+
+// TableName specifies the table name.
+func (AutoApprovalModel) TableName() string {
+	return "commission_auto_approvals"
+}
+
+// toDomain converts the persistence model to the AutoApproval entity.
+func (m *AutoApprovalModel) toDomain() *commission.AutoApproval {
+	var reasons []string
+	if m.Reasons != "" {
+		_ = json.Unmarshal([]byte(m.Reasons), &reasons)
+	}
+
+	return commission.ReconstituteAutoApproval(commission.AutoApprovalParams{
+		ID:           m.ID,
+		CommissionID: m.CommissionID,
+		AgentID:      m.AgentID,
+		Rule:         m.Rule,
+		Amount:       shared.MoneyFromFloat(m.Amount, m.Currency),
+		Reasons:      reasons,
+		ApprovedAt:   m.ApprovedAt,
+	})
+}
+
+// newAutoApprovalModel converts the AutoApproval entity to its persistence model.
+func newAutoApprovalModel(a *commission.AutoApproval) *AutoApprovalModel {
+	reasons, _ := json.Marshal(a.Reasons())
+	return &AutoApprovalModel{
+		ID:           a.ID(),
+		CommissionID: a.CommissionID(),
+		AgentID:      a.AgentID(),
+		Rule:         a.Rule(),
+		Amount:       a.Amount().Float64(),
+		Currency:     a.Amount().Currency(),
+		Reasons:      string(reasons),
+		ApprovedAt:   a.ApprovedAt(),
+	}
+}
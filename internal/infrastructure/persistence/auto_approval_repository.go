@@ -0,0 +1,66 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/commission"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"gorm.io/gorm"
+)
+
+// autoApprovalRepository implements repository.AutoApprovalRepository
+type autoApprovalRepository struct {
+	db *gorm.DB
+}
+
+// NewAutoApprovalRepository creates a new auto-approval repository
+func NewAutoApprovalRepository(db *gorm.DB) repository.AutoApprovalRepository {
+	return &autoApprovalRepository{db: db}
+}
+
+// GetByCommissionID retrieves a commission's auto-approval
+func (r *autoApprovalRepository) GetByCommissionID(ctx context.Context, commissionID uint) (*commission.AutoApproval, error) {
+	var model AutoApprovalModel
+	if err := conn(ctx, r.db).Where("commission_id = ?", commissionID).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, commission.ErrAutoApprovalNotFound
+		}
+		return nil, err
+	}
+	return model.toDomain(), nil
+}
+
+// List retrieves auto-approvals, newest first
+func (r *autoApprovalRepository) List(ctx context.Context, page, limit int) ([]*commission.AutoApproval, int64, error) {
+	query := conn(ctx, r.db).Model(&AutoApprovalModel{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var models []AutoApprovalModel
+	if err := paginate(query, page, limit).Order("approved_at DESC, id DESC").Find(&models).Error; err != nil {
+		return nil, 0, err
+	}
+
+	approvals := make([]*commission.AutoApproval, len(models))
+	for i := range models {
+		approvals[i] = models[i].toDomain()
+	}
+	return approvals, total, nil
+}
+
+// Create records an auto-approval and assigns its ID
+func (r *autoApprovalRepository) Create(ctx context.Context, a *commission.AutoApproval) error {
+	model := newAutoApprovalModel(a)
+	if err := conn(ctx, r.db).Create(model).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return repository.ErrDuplicate
+		}
+		return err
+	}
+	a.SetID(model.ID)
+	return nil
+}
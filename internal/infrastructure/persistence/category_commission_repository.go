@@ -2,61 +2,114 @@ package persistence
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/Ecom-micro-template/service-agent/internal/domain"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
 	"gorm.io/gorm"
 )
 
-// CategoryCommissionRepository defines the interface for agent category commission data operations
-type CategoryCommissionRepository interface {
-	GetByAgentID(ctx context.Context, agentID uint) ([]domain.AgentCategoryCommission, error)
-	DeleteByAgentID(ctx context.Context, agentID uint) error
-	Create(ctx context.Context, commission *domain.AgentCategoryCommission) error
-	BulkReplace(ctx context.Context, agentID uint, commissions []domain.AgentCategoryCommission) error
-}
-
-// categoryCommissionRepository implements CategoryCommissionRepository
+// categoryCommissionRepository implements repository.CategoryCommissionRepository
 type categoryCommissionRepository struct {
 	db *gorm.DB
 }
 
 // NewCategoryCommissionRepository creates a new category commission repository
-func NewCategoryCommissionRepository(db *gorm.DB) CategoryCommissionRepository {
+func NewCategoryCommissionRepository(db *gorm.DB) repository.CategoryCommissionRepository {
 	return &categoryCommissionRepository{db: db}
 }
 
-// GetByAgentID retrieves all category commissions for an agent
-func (r *categoryCommissionRepository) GetByAgentID(ctx context.Context, agentID uint) ([]domain.AgentCategoryCommission, error) {
+// GetByAgentID retrieves the category commissions in effect for an agent at a time
+func (r *categoryCommissionRepository) GetByAgentID(ctx context.Context, agentID uint, at time.Time) ([]domain.AgentCategoryCommission, error) {
+	return effectiveCategoryCommissions(conn(ctx, r.db), agentID, at)
+}
+
+// GetHistory retrieves every category commission for an agent, oldest effective first
+func (r *categoryCommissionRepository) GetHistory(ctx context.Context, agentID uint) ([]domain.AgentCategoryCommission, error) {
 	var commissions []domain.AgentCategoryCommission
-	err := r.db.WithContext(ctx).Where("agent_id = ?", agentID).Find(&commissions).Error
+	err := conn(ctx, r.db).
+		Where("agent_id = ?", agentID).
+		Order("effective_from ASC, category_id ASC, id ASC").
+		Find(&commissions).Error
 	return commissions, err
 }
 
-// DeleteByAgentID deletes all category commissions for an agent
-func (r *categoryCommissionRepository) DeleteByAgentID(ctx context.Context, agentID uint) error {
-	return r.db.WithContext(ctx).Where("agent_id = ?", agentID).Delete(&domain.AgentCategoryCommission{}).Error
+// GetByID retrieves a category commission by ID
+func (r *categoryCommissionRepository) GetByID(ctx context.Context, id uint) (*domain.AgentCategoryCommission, error) {
+	var commission domain.AgentCategoryCommission
+	if err := conn(ctx, r.db).First(&commission, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, err
+	}
+	return &commission, nil
 }
 
-// Create creates a new category commission
-func (r *categoryCommissionRepository) Create(ctx context.Context, commission *domain.AgentCategoryCommission) error {
-	return r.db.WithContext(ctx).Create(commission).Error
-}
+// Schedule replaces an agent's category commissions from effectiveFrom on
+func (r *categoryCommissionRepository) Schedule(ctx context.Context, agentID uint, commissions []domain.AgentCategoryCommission, effectiveFrom time.Time) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// Replace anything already scheduled for the same time
+		if err := tx.Where("agent_id = ? AND effective_from = ?", agentID, effectiveFrom).
+			Delete(&domain.AgentCategoryCommission{}).Error; err != nil {
+			return err
+		}
 
-// BulkReplace replaces all category commissions for an agent
-func (r *categoryCommissionRepository) BulkReplace(ctx context.Context, agentID uint, commissions []domain.AgentCategoryCommission) error {
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		// Delete existing
-		if err := tx.Where("agent_id = ?", agentID).Delete(&domain.AgentCategoryCommission{}).Error; err != nil {
+		current, err := effectiveCategoryCommissions(tx, agentID, effectiveFrom)
+		if err != nil {
 			return err
 		}
 
-		// Create new ones
+		kept := make(map[string]bool, len(commissions))
 		for i := range commissions {
+			commissions[i].ID = 0
 			commissions[i].AgentID = agentID
-			if err := tx.Create(&commissions[i]).Error; err != nil {
+			commissions[i].EffectiveFrom = effectiveFrom
+			if err := createCategoryCommission(tx, &commissions[i]); err != nil {
+				return err
+			}
+			kept[commissions[i].CategoryID] = true
+		}
+
+		// End the rates of categories left out
+		for _, cc := range current {
+			if !cc.IsActive || kept[cc.CategoryID] {
+				continue
+			}
+			ended := domain.AgentCategoryCommission{
+				AgentID:        agentID,
+				CategoryID:     cc.CategoryID,
+				CategoryName:   cc.CategoryName,
+				CommissionRate: cc.CommissionRate,
+				EffectiveFrom:  effectiveFrom,
+			}
+			if err := createCategoryCommission(tx, &ended); err != nil {
 				return err
 			}
 		}
 		return nil
 	})
 }
+
+// Delete deletes a category commission
+func (r *categoryCommissionRepository) Delete(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Delete(&domain.AgentCategoryCommission{}, id).Error
+}
+
+// createCategoryCommission inserts a row with every column selected, so an
+// inactive row is not given the is_active column default
+func createCategoryCommission(db *gorm.DB, commission *domain.AgentCategoryCommission) error {
+	return db.Select("*").Omit("id", "deleted_at", "Agent").Create(commission).Error
+}
+
+// effectiveCategoryCommissions returns the row in effect at a time for each
+// of an agent's categories
+func effectiveCategoryCommissions(db *gorm.DB, agentID uint, at time.Time) ([]domain.AgentCategoryCommission, error) {
+	var commissions []domain.AgentCategoryCommission
+	err := db.Select("DISTINCT ON (category_id) *").
+		Where("agent_id = ? AND effective_from <= ?", agentID, at).
+		Order("category_id, effective_from DESC, id DESC").
+		Find(&commissions).Error
+	return commissions, err
+}
@@ -0,0 +1,69 @@
+package persistence
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// cipherVersion prefixes every ciphertext, so the scheme or key can be
+// rotated later without guessing how a value was written
+const cipherVersion = "v1:"
+
+// ErrInvalidCiphertext is returned when a stored value cannot be decrypted
+var ErrInvalidCiphertext = errors.New("encrypted value cannot be decrypted")
+
+// FieldCipher encrypts sensitive columns, such as payout account numbers,
+// with AES-256-GCM before they are written.
+type FieldCipher struct {
+	aead cipher.AEAD
+}
+
+// NewFieldCipher creates a field cipher from a base64-encoded 32-byte key
+func NewFieldCipher(key string) (*FieldCipher, error) {
+	raw, err := base64.StdEncoding.DecodeString(key)
+	if err != nil || len(raw) != 32 {
+		return nil, errors.New("encryption key must be 32 bytes, base64 encoded")
+	}
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &FieldCipher{aead: aead}, nil
+}
+
+// Encrypt returns the versioned, base64-encoded ciphertext of a value
+func (c *FieldCipher) Encrypt(plaintext string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := c.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return cipherVersion + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt returns the value a ciphertext from Encrypt was made from
+func (c *FieldCipher) Decrypt(ciphertext string) (string, error) {
+	encoded, ok := strings.CutPrefix(ciphertext, cipherVersion)
+	if !ok {
+		return "", ErrInvalidCiphertext
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < c.aead.NonceSize() {
+		return "", ErrInvalidCiphertext
+	}
+	nonce, sealed := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return "", ErrInvalidCiphertext
+	}
+	return string(plaintext), nil
+}
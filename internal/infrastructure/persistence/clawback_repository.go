@@ -0,0 +1,76 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/commission"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"gorm.io/gorm"
+)
+
+// clawbackRepository implements repository.ClawbackRepository
+type clawbackRepository struct {
+	db *gorm.DB
+}
+
+// NewClawbackRepository creates a new clawback repository
+func NewClawbackRepository(db *gorm.DB) repository.ClawbackRepository {
+	return &clawbackRepository{db: db}
+}
+
+// GetByCommissionID retrieves a commission's clawbacks, oldest first
+func (r *clawbackRepository) GetByCommissionID(ctx context.Context, commissionID uint) ([]*commission.Clawback, error) {
+	return r.find(conn(ctx, r.db).Where("commission_id = ?", commissionID))
+}
+
+// GetOutstanding retrieves an agent's unrecovered clawbacks, oldest first
+func (r *clawbackRepository) GetOutstanding(ctx context.Context, agentID uint) ([]*commission.Clawback, error) {
+	return r.find(conn(ctx, r.db).Where("agent_id = ? AND status = ?", agentID, shared.ClawbackOutstanding))
+}
+
+// GetByPayoutID retrieves the clawbacks recovered from a payout, oldest
+// first
+func (r *clawbackRepository) GetByPayoutID(ctx context.Context, payoutID uint) ([]*commission.Clawback, error) {
+	return r.find(conn(ctx, r.db).Where("payout_id = ?", payoutID))
+}
+
+func (r *clawbackRepository) find(query *gorm.DB) ([]*commission.Clawback, error) {
+	var models []ClawbackModel
+	if err := query.Order("created_at ASC, id ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	clawbacks := make([]*commission.Clawback, len(models))
+	for i := range models {
+		clawbacks[i] = models[i].toDomain()
+	}
+	return clawbacks, nil
+}
+
+// Create creates a new clawback and assigns its ID
+func (r *clawbackRepository) Create(ctx context.Context, c *commission.Clawback) error {
+	model := newClawbackModel(c)
+	if err := conn(ctx, r.db).Create(model).Error; err != nil {
+		return err
+	}
+	c.SetID(model.ID)
+	return nil
+}
+
+// Update saves all clawback fields. It returns repository.ErrConflict if
+// the clawback was saved since it was loaded, such as by another payout
+// recovering it.
+func (r *clawbackRepository) Update(ctx context.Context, c *commission.Clawback) error {
+	model := newClawbackModel(c)
+	model.Version++
+	result := conn(ctx, r.db).Model(model).Where("version = ?", c.Version()).Select("*").Updates(model)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repository.ErrConflict
+	}
+	c.SetVersion(model.Version)
+	return nil
+}
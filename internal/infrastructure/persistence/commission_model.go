@@ -2,6 +2,9 @@ package persistence
 
 import (
 	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/commission"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
 )
 
 // CommissionModel is the GORM persistence model for Commission.
@@ -16,6 +19,39 @@ type CommissionModel struct {
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
 
+	// Amounts above are in the order currency. PayoutAmount is the amount
+	// converted to the agent's payout currency at FXRate.
+	Currency       string  `gorm:"size:3;not null;default:'MYR'" json:"currency"`
+	PayoutAmount   float64 `gorm:"type:decimal(10,2);not null" json:"payout_amount"`
+	PayoutCurrency string  `gorm:"size:3;not null;default:'MYR'" json:"payout_currency"`
+	FXRate         string  `gorm:"column:fx_rate;type:decimal(20,10);not null;default:1" json:"fx_rate"`
+
+	// ReversedAmount is the total clawed back from the commission
+	ReversedAmount float64 `gorm:"type:decimal(10,2);not null;default:0" json:"reversed_amount"`
+
+	// Why and by whom the commission was rejected or cancelled
+	StatusReason    string `gorm:"type:text" json:"status_reason,omitempty"`
+	StatusChangedBy string `gorm:"size:100" json:"status_changed_by,omitempty"`
+
+	// Split commissions of a co-sold order share a group ID
+	SplitGroupID string  `gorm:"size:36;index" json:"split_group_id,omitempty"`
+	SplitShare   float64 `gorm:"type:decimal(5,2);not null;default:100" json:"split_share"`
+
+	// Derived commissions (team overrides, downline) link their sale
+	// commission. Uniqueness is enforced by partial indexes, see migrations.
+	Type               string `gorm:"column:commission_type;size:20;not null;default:'sale'" json:"type"`
+	SourceCommissionID *uint  `gorm:"index" json:"source_commission_id,omitempty"`
+	Level              int    `gorm:"not null;default:0" json:"level,omitempty"`
+
+	// Manual adjustments have a signed amount, no order total and an
+	// optional order ID
+	AdjustmentCategory string `gorm:"size:20" json:"adjustment_category,omitempty"`
+	Note               string `gorm:"type:text" json:"note,omitempty"`
+	CreatedBy          string `gorm:"size:100" json:"created_by,omitempty"`
+
+	// Version is bumped on every update, for optimistic locking
+	Version int `gorm:"not null;default:0" json:"version"`
+
 	// Relations
 	Agent AgentModel `gorm:"foreignKey:AgentID" json:"agent,omitempty"`
 }
@@ -24,3 +60,145 @@ type CommissionModel struct {
 func (CommissionModel) TableName() string {
 	return "commissions"
 }
+
+// toDomain converts the persistence model to the Commission entity.
+func (m *CommissionModel) toDomain() *commission.Commission {
+	return commission.Reconstitute(commission.CommissionParams{
+		ID:         m.ID,
+		AgentID:    m.AgentID,
+		OrderID:    m.OrderID,
+		OrderTotal: shared.MoneyFromFloat(m.OrderTotal, m.Currency),
+		Rate:       m.Rate,
+		Amount:     shared.MoneyFromFloat(m.Amount, m.Currency),
+		Status:     m.Status,
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
+
+		StatusReason:    m.StatusReason,
+		StatusChangedBy: m.StatusChangedBy,
+
+		PayoutAmount: shared.MoneyFromFloat(m.PayoutAmount, m.PayoutCurrency),
+		FXRate:       m.FXRate,
+
+		ReversedAmount: shared.MoneyFromFloat(m.ReversedAmount, m.Currency),
+		SplitGroupID:   m.SplitGroupID,
+		SplitShare:     m.SplitShare,
+
+		Type:               m.Type,
+		SourceCommissionID: m.SourceCommissionID,
+		Level:              m.Level,
+
+		Category:  m.AdjustmentCategory,
+		Note:      m.Note,
+		CreatedBy: m.CreatedBy,
+
+		Version: m.Version,
+	})
+}
+
+// newCommissionModel converts the Commission entity to its persistence model.
+func newCommissionModel(c *commission.Commission) *CommissionModel {
+	return &CommissionModel{
+		ID:         c.ID(),
+		AgentID:    c.AgentID(),
+		OrderID:    c.OrderID(),
+		OrderTotal: c.OrderTotal().Float64(),
+		Rate:       c.Rate().Value(),
+		Amount:     c.Amount().Float64(),
+		Status:     c.Status().String(),
+		CreatedAt:  c.CreatedAt(),
+		UpdatedAt:  c.UpdatedAt(),
+
+		Currency:       c.Currency(),
+		PayoutAmount:   c.PayoutAmount().Float64(),
+		PayoutCurrency: c.PayoutCurrency(),
+		FXRate:         c.FXRate().String(),
+
+		ReversedAmount:  c.ReversedAmount().Float64(),
+		StatusReason:    c.StatusReason(),
+		StatusChangedBy: c.StatusChangedBy(),
+		SplitGroupID:    c.SplitGroupID(),
+		SplitShare:      c.SplitShare(),
+
+		Type:               c.Type().String(),
+		SourceCommissionID: c.SourceCommissionID(),
+		Level:              c.Level(),
+
+		AdjustmentCategory: c.Category().String(),
+		Note:               c.Note(),
+		CreatedBy:          c.CreatedBy(),
+
+		Version: c.Version(),
+	}
+}
+
+// ClawbackModel is the GORM persistence model for Clawback.
+type ClawbackModel struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	CommissionID uint      `gorm:"not null;index" json:"commission_id"`
+	AgentID      uint      `gorm:"not null;index" json:"agent_id"`
+	OrderID      string    `gorm:"size:100;not null" json:"order_id"`
+	RefundID     string    `gorm:"size:100" json:"refund_id,omitempty"`
+	Amount       float64   `gorm:"type:decimal(10,2);not null" json:"amount"` // Negative
+	RefundAmount float64   `gorm:"type:decimal(10,2);not null" json:"refund_amount"`
+	Reason       string    `gorm:"size:255" json:"reason"`
+	Status       string    `gorm:"size:20;not null" json:"status"`
+	PayoutID     *uint     `gorm:"index" json:"payout_id,omitempty"`
+	Version      int       `gorm:"not null;default:0" json:"version"` // Bumped on every update, for optimistic locking
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	// Amounts above are in the order currency. PayoutAmount is the amount
+	// in the commission's payout currency, also negative.
+	Currency       string  `gorm:"size:3;not null;default:'MYR'" json:"currency"`
+	PayoutAmount   float64 `gorm:"type:decimal(10,2);not null" json:"payout_amount"`
+	PayoutCurrency string  `gorm:"size:3;not null;default:'MYR'" json:"payout_currency"`
+}
+
+// TableName specifies the table name.
+func (ClawbackModel) TableName() string {
+	return "commission_clawbacks"
+}
+
+// toDomain converts the persistence model to the Clawback entity.
+func (m *ClawbackModel) toDomain() *commission.Clawback {
+	return commission.ReconstituteClawback(commission.ClawbackParams{
+		ID:           m.ID,
+		CommissionID: m.CommissionID,
+		AgentID:      m.AgentID,
+		OrderID:      m.OrderID,
+		RefundID:     m.RefundID,
+		Amount:       shared.MoneyFromFloat(m.Amount, m.Currency),
+		RefundAmount: shared.MoneyFromFloat(m.RefundAmount, m.Currency),
+		PayoutAmount: shared.MoneyFromFloat(m.PayoutAmount, m.PayoutCurrency),
+		Reason:       m.Reason,
+		Status:       m.Status,
+		PayoutID:     m.PayoutID,
+		Version:      m.Version,
+		CreatedAt:    m.CreatedAt,
+		UpdatedAt:    m.UpdatedAt,
+	})
+}
+
+// newClawbackModel converts the Clawback entity to its persistence model.
+func newClawbackModel(c *commission.Clawback) *ClawbackModel {
+	return &ClawbackModel{
+		ID:           c.ID(),
+		CommissionID: c.CommissionID(),
+		AgentID:      c.AgentID(),
+		OrderID:      c.OrderID(),
+		RefundID:     c.RefundID(),
+		Amount:       c.Amount().Float64(),
+		RefundAmount: c.RefundAmount().Float64(),
+		Reason:       c.Reason(),
+		Status:       c.Status().String(),
+		PayoutID:     c.PayoutID(),
+		Version:      c.Version(),
+		CreatedAt:    c.CreatedAt(),
+		UpdatedAt:    c.UpdatedAt(),
+
+		Currency:       c.Amount().Currency(),
+		PayoutAmount:   c.PayoutAmount().Float64(),
+		PayoutCurrency: c.PayoutAmount().Currency(),
+	}
+}
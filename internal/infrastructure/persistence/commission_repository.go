@@ -0,0 +1,254 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/agent"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/commission"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"gorm.io/gorm"
+)
+
+// commissionRepository implements repository.CommissionRepository
+type commissionRepository struct {
+	db *gorm.DB
+}
+
+// NewCommissionRepository creates a new commission repository
+func NewCommissionRepository(db *gorm.DB) repository.CommissionRepository {
+	return &commissionRepository{db: db}
+}
+
+// GetByID retrieves a commission by ID
+func (r *commissionRepository) GetByID(ctx context.Context, id uint) (*commission.Commission, error) {
+	var model CommissionModel
+	if err := conn(ctx, r.db).First(&model, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, commission.ErrCommissionNotFound
+		}
+		return nil, err
+	}
+	return model.toDomain(), nil
+}
+
+// GetByAgentID retrieves an agent's commissions matching the filter, newest first
+func (r *commissionRepository) GetByAgentID(ctx context.Context, agentID uint, filter repository.CommissionFilter) ([]*commission.Commission, int64, error) {
+	query := withPeriod(conn(ctx, r.db).Model(&CommissionModel{}).Where("agent_id = ?", agentID), filter.Period)
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Type != "" {
+		query = query.Where("commission_type = ?", filter.Type)
+	}
+	return r.list(query, filter.Page, filter.Limit)
+}
+
+// GetByOrderID retrieves all commissions for an order
+func (r *commissionRepository) GetByOrderID(ctx context.Context, orderID string) ([]*commission.Commission, error) {
+	commissions, _, err := r.list(conn(ctx, r.db).Model(&CommissionModel{}).Where("order_id = ?", orderID), 0, 0)
+	return commissions, err
+}
+
+// GetBySourceID retrieves the commissions derived from a sale commission
+func (r *commissionRepository) GetBySourceID(ctx context.Context, sourceCommissionID uint) ([]*commission.Commission, error) {
+	commissions, _, err := r.list(conn(ctx, r.db).Model(&CommissionModel{}).Where("source_commission_id = ?", sourceCommissionID), 0, 0)
+	return commissions, err
+}
+
+// GetPending retrieves all pending commissions, newest first
+func (r *commissionRepository) GetPending(ctx context.Context, page, limit int) ([]*commission.Commission, int64, error) {
+	return r.list(conn(ctx, r.db).Model(&CommissionModel{}).Where("status = ?", shared.CommissionPending), page, limit)
+}
+
+func (r *commissionRepository) list(query *gorm.DB, page, limit int) ([]*commission.Commission, int64, error) {
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var models []CommissionModel
+	if err := paginate(query, page, limit).Order("created_at DESC").Find(&models).Error; err != nil {
+		return nil, 0, err
+	}
+
+	commissions := make([]*commission.Commission, len(models))
+	for i := range models {
+		commissions[i] = models[i].toDomain()
+	}
+	return commissions, total, nil
+}
+
+// netPayoutAmountSQL is a commission's amount net of clawbacks, in the
+// agent's payout currency
+const netPayoutAmountSQL = "payout_amount * (amount - reversed_amount) / NULLIF(amount, 0)"
+
+// GetSummary aggregates an agent's commission amounts by status within a
+// period, per payout currency
+func (r *commissionRepository) GetSummary(ctx context.Context, agentID uint, period repository.Period) (*repository.CommissionSummary, error) {
+	var rows []struct {
+		Status         string
+		PayoutCurrency string
+		Count          int64
+		Total          float64
+	}
+	err := withPeriod(conn(ctx, r.db).Model(&CommissionModel{}).Where("agent_id = ?", agentID), period).
+		Select("status, payout_currency, COUNT(*) AS count, COALESCE(SUM(" + netPayoutAmountSQL + "), 0) AS total").
+		Group("status, payout_currency").
+		Order("payout_currency").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &repository.CommissionSummary{}
+	for _, row := range rows {
+		summary.Count += row.Count
+		if shared.CommissionStatus(row.Status) == shared.CommissionPending {
+			summary.PendingCount += row.Count
+		}
+		n := len(summary.Totals)
+		if n == 0 || summary.Totals[n-1].Currency != row.PayoutCurrency {
+			summary.Totals = append(summary.Totals, domain.CommissionTotals{Currency: row.PayoutCurrency})
+			n++
+		}
+		addCommissionTotal(&summary.Totals[n-1], shared.CommissionStatus(row.Status), row.Total)
+	}
+	return summary, nil
+}
+
+// addCommissionTotal adds an amount in a status to the totals
+func addCommissionTotal(totals *domain.CommissionTotals, status shared.CommissionStatus, amount float64) {
+	totals.Total += amount
+	switch status {
+	case shared.CommissionPending:
+		totals.Pending += amount
+	case shared.CommissionApproved, shared.CommissionInPayout:
+		totals.Approved += amount
+	case shared.CommissionPaid, shared.CommissionPartiallyReversed:
+		totals.Paid += amount
+	}
+}
+
+// GetSalesSummary aggregates the sales behind an agent's sale commissions
+// within a period, net of refunds and converted to the payout currency
+func (r *commissionRepository) GetSalesSummary(ctx context.Context, agentID uint, period repository.Period) (*repository.SalesSummary, error) {
+	summary := &repository.SalesSummary{}
+	err := withPeriod(conn(ctx, r.db).Model(&CommissionModel{}), period).
+		Where("agent_id = ? AND commission_type = ? AND status NOT IN ?", agentID, shared.CommissionTypeSale,
+			[]shared.CommissionStatus{shared.CommissionCancelled, shared.CommissionRejected, shared.CommissionReversed}).
+		Select("COUNT(DISTINCT order_id) AS orders, " +
+			"COALESCE(SUM(order_total * split_share / 100 * fx_rate * (amount - reversed_amount) / NULLIF(amount, 0)), 0) AS sales").
+		Scan(summary).Error
+	if err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// Create creates a new commission and assigns its ID
+func (r *commissionRepository) Create(ctx context.Context, c *commission.Commission) error {
+	model := newCommissionModel(c)
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(model).Error; err != nil {
+			return err
+		}
+		c.SetID(model.ID)
+		return writeLedgerEntries(tx, c.LedgerEntries())
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return repository.ErrDuplicate
+	}
+	return err
+}
+
+// Update saves all commission fields and the ledger entries for its
+// transitions. It refuses a commission saved by someone else since it was
+// read, so the ledger entries are never posted twice.
+func (r *commissionRepository) Update(ctx context.Context, c *commission.Commission) error {
+	model := newCommissionModel(c)
+	model.Version++
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(model).Where("version = ?", c.Version()).Select("*").Updates(model)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return repository.ErrConflict
+		}
+		return writeLedgerEntries(tx, c.LedgerEntries())
+	})
+	if err != nil {
+		return err
+	}
+	c.SetVersion(model.Version)
+	return nil
+}
+
+// UpdateStatus moves the commissions that are still in status from to
+// status to, and returns how many moved
+func (r *commissionRepository) UpdateStatus(ctx context.Context, ids []uint, from, to shared.CommissionStatus) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	result := conn(ctx, r.db).Model(&CommissionModel{}).
+		Where("id IN ? AND status = ?", ids, from.String()).
+		Updates(map[string]interface{}{"status": to.String(), "version": gorm.Expr("version + 1")})
+	return int(result.RowsAffected), result.Error
+}
+
+// CalculateCommission calculates the commission an agent earns on an order
+// amount at the rates in effect at the given time, using the agent's
+// category rate when one was active.
+func (r *commissionRepository) CalculateCommission(ctx context.Context, agentID uint, orderAmount shared.Money, categoryID *string, at time.Time) (shared.Money, error) {
+	var agentModel AgentModel
+	if err := conn(ctx, r.db).First(&agentModel, agentID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return shared.Money{}, agent.ErrAgentNotFound
+		}
+		return shared.Money{}, err
+	}
+
+	if categoryID != nil {
+		var categoryCommission domain.AgentCategoryCommission
+		err := conn(ctx, r.db).
+			Where("agent_id = ? AND category_id = ? AND effective_from <= ?", agentID, *categoryID, at).
+			Order("effective_from DESC, id DESC").
+			First(&categoryCommission).Error
+		if err == nil && categoryCommission.IsActive {
+			return orderAmount.MulPercent(categoryCommission.CommissionRate, shared.RoundHalfUp), nil
+		}
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return shared.Money{}, err
+		}
+	}
+
+	// Agents with no rate history earn at their current rate
+	rate := agentModel.CommissionRate
+	var change RateChangeModel
+	err := conn(ctx, r.db).
+		Where("scope = ? AND subject_id = ? AND effective_from <= ?", string(commission.RateScopeAgent), agentID, at).
+		Order("effective_from DESC, id DESC").
+		First(&change).Error
+	if err == nil {
+		rate = change.Rate
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return shared.Money{}, err
+	}
+
+	return orderAmount.MulPercent(rate, shared.RoundHalfUp), nil
+}
+
+// withPeriod restricts a query to rows created within the period.
+func withPeriod(query *gorm.DB, period repository.Period) *gorm.DB {
+	if !period.From.IsZero() {
+		query = query.Where("created_at >= ?", period.From)
+	}
+	if !period.To.IsZero() {
+		query = query.Where("created_at < ?", period.To)
+	}
+	return query
+}
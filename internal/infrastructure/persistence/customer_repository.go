@@ -0,0 +1,78 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"gorm.io/gorm"
+)
+
+// customerRepository implements repository.CustomerRepository
+type customerRepository struct {
+	db *gorm.DB
+}
+
+// NewCustomerRepository creates a new customer repository
+func NewCustomerRepository(db *gorm.DB) repository.CustomerRepository {
+	return &customerRepository{db: db}
+}
+
+// GetByID retrieves one of an agent's customers
+func (r *customerRepository) GetByID(ctx context.Context, agentID, id uint) (*domain.Customer, error) {
+	var customer domain.Customer
+	if err := conn(ctx, r.db).Where("agent_id = ?", agentID).First(&customer, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, err
+	}
+	return &customer, nil
+}
+
+// List retrieves an agent's customers, optionally matching a name or email search
+func (r *customerRepository) List(ctx context.Context, agentID uint, search string, page, limit int) ([]domain.Customer, int64, error) {
+	query := conn(ctx, r.db).Model(&domain.Customer{}).Where("agent_id = ?", agentID)
+	if search != "" {
+		query = query.Where("name ILIKE ? OR email ILIKE ?", "%"+search+"%", "%"+search+"%")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var customers []domain.Customer
+	if err := paginate(query, page, limit).Order("created_at DESC").Find(&customers).Error; err != nil {
+		return nil, 0, err
+	}
+	return customers, total, nil
+}
+
+// CountByAgentID counts an agent's customers
+func (r *customerRepository) CountByAgentID(ctx context.Context, agentID uint) (int64, error) {
+	var total int64
+	err := conn(ctx, r.db).Model(&domain.Customer{}).Where("agent_id = ?", agentID).Count(&total).Error
+	return total, err
+}
+
+// CountActive counts an agent's customers who ordered since the given time
+func (r *customerRepository) CountActive(ctx context.Context, agentID uint, since time.Time) (int64, error) {
+	var total int64
+	err := conn(ctx, r.db).Model(&domain.Customer{}).
+		Where("agent_id = ? AND last_order_at >= ?", agentID, since).
+		Count(&total).Error
+	return total, err
+}
+
+// Create creates a new customer
+func (r *customerRepository) Create(ctx context.Context, customer *domain.Customer) error {
+	return conn(ctx, r.db).Create(customer).Error
+}
+
+// Update saves all customer fields
+func (r *customerRepository) Update(ctx context.Context, customer *domain.Customer) error {
+	return conn(ctx, r.db).Save(customer).Error
+}
@@ -0,0 +1,100 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/dispute"
+)
+
+// DisputeModel is the GORM persistence model for Dispute.
+type DisputeModel struct {
+	ID                     uint       `gorm:"primaryKey" json:"id"`
+	AgentID                uint       `gorm:"not null;index" json:"agent_id"`
+	OrderID                string     `gorm:"size:100;not null;index" json:"order_id"`
+	CommissionID           *uint      `gorm:"index" json:"commission_id,omitempty"`
+	Status                 string     `gorm:"size:20;not null;default:'open';index" json:"status"`
+	Resolution             string     `gorm:"type:text" json:"resolution,omitempty"`
+	ResolvedBy             string     `gorm:"size:100" json:"resolved_by,omitempty"`
+	ResolvedAt             *time.Time `json:"resolved_at,omitempty"`
+	AdjustmentCommissionID *uint      `json:"adjustment_commission_id,omitempty"`
+	CreatedAt              time.Time  `json:"created_at"`
+	UpdatedAt              time.Time  `json:"updated_at"`
+
+	// Relations
+	Messages []DisputeMessageModel `gorm:"foreignKey:DisputeID" json:"messages,omitempty"`
+}
+
+// TableName specifies the table name.
+func (DisputeModel) TableName() string {
+	return "commission_disputes"
+}
+
+// DisputeMessageModel is the GORM persistence model for a message in a
+// dispute thread.
+type DisputeMessageModel struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	DisputeID uint      `gorm:"not null;index" json:"dispute_id"`
+	Author    string    `gorm:"size:20;not null" json:"author"`
+	AuthorID  string    `gorm:"size:100" json:"author_id,omitempty"`
+	Body      string    `gorm:"type:text;not null" json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name.
+func (DisputeMessageModel) TableName() string {
+	return "commission_dispute_messages"
+}
+
+// toDomain converts the persistence model to the Dispute aggregate.
+func (m *DisputeModel) toDomain() *dispute.Dispute {
+	messages := make([]dispute.Message, len(m.Messages))
+	for i, msg := range m.Messages {
+		messages[i] = dispute.ReconstituteMessage(msg.ID, msg.Author, msg.AuthorID, msg.Body, msg.CreatedAt)
+	}
+
+	return dispute.Reconstitute(dispute.DisputeParams{
+		ID:                     m.ID,
+		AgentID:                m.AgentID,
+		OrderID:                m.OrderID,
+		CommissionID:           m.CommissionID,
+		Messages:               messages,
+		Status:                 m.Status,
+		Resolution:             m.Resolution,
+		ResolvedBy:             m.ResolvedBy,
+		ResolvedAt:             m.ResolvedAt,
+		AdjustmentCommissionID: m.AdjustmentCommissionID,
+		CreatedAt:              m.CreatedAt,
+		UpdatedAt:              m.UpdatedAt,
+	})
+}
+
+// newDisputeModel converts the Dispute aggregate to its persistence model,
+// without its messages.
+func newDisputeModel(d *dispute.Dispute) *DisputeModel {
+	return &DisputeModel{
+		ID:                     d.ID(),
+		AgentID:                d.AgentID(),
+		OrderID:                d.OrderID(),
+		CommissionID:           d.CommissionID(),
+		Status:                 d.Status().String(),
+		Resolution:             d.Resolution(),
+		ResolvedBy:             d.ResolvedBy(),
+		ResolvedAt:             d.ResolvedAt(),
+		AdjustmentCommissionID: d.AdjustmentCommissionID(),
+		CreatedAt:              d.CreatedAt(),
+		UpdatedAt:              d.UpdatedAt(),
+	}
+}
+
+// newDisputeMessageModel converts a message in a dispute thread to its
+// persistence model.
+func newDisputeMessageModel(disputeID uint, m dispute.Message) *DisputeMessageModel {
+	return &DisputeMessageModel{
+		ID:        m.ID(),
+		DisputeID: disputeID,
+		Author:    m.Author(),
+		AuthorID:  m.AuthorID(),
+		Body:      m.Body(),
+		CreatedAt: m.CreatedAt(),
+	}
+}
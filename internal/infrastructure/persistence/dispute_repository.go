@@ -0,0 +1,118 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/dispute"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"gorm.io/gorm"
+)
+
+// disputeRepository implements repository.DisputeRepository
+type disputeRepository struct {
+	db *gorm.DB
+}
+
+// NewDisputeRepository creates a new dispute repository
+func NewDisputeRepository(db *gorm.DB) repository.DisputeRepository {
+	return &disputeRepository{db: db}
+}
+
+// withMessages preloads the thread, oldest message first
+func withMessages(query *gorm.DB) *gorm.DB {
+	return query.Preload("Messages", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at ASC, id ASC")
+	})
+}
+
+// GetByID retrieves a dispute and its thread by ID
+func (r *disputeRepository) GetByID(ctx context.Context, id uint) (*dispute.Dispute, error) {
+	var model DisputeModel
+	if err := withMessages(conn(ctx, r.db)).First(&model, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, dispute.ErrDisputeNotFound
+		}
+		return nil, err
+	}
+	return model.toDomain(), nil
+}
+
+// List retrieves the disputes matching the filter, newest first
+func (r *disputeRepository) List(ctx context.Context, filter repository.DisputeFilter) ([]*dispute.Dispute, int64, error) {
+	query := conn(ctx, r.db).Model(&DisputeModel{})
+	if filter.AgentID != 0 {
+		query = query.Where("agent_id = ?", filter.AgentID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var models []DisputeModel
+	if err := paginate(withMessages(query), filter.Page, filter.Limit).Order("created_at DESC, id DESC").Find(&models).Error; err != nil {
+		return nil, 0, err
+	}
+
+	disputes := make([]*dispute.Dispute, len(models))
+	for i := range models {
+		disputes[i] = models[i].toDomain()
+	}
+	return disputes, total, nil
+}
+
+// HasActive reports whether the agent has an open or under review dispute
+// on the order
+func (r *disputeRepository) HasActive(ctx context.Context, agentID uint, orderID string) (bool, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&DisputeModel{}).
+		Where("agent_id = ? AND order_id = ? AND status IN ?", agentID, orderID,
+			[]shared.DisputeStatus{shared.DisputeOpen, shared.DisputeUnderReview}).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Create creates a new dispute with its thread and assigns their IDs
+func (r *disputeRepository) Create(ctx context.Context, d *dispute.Dispute) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		model := newDisputeModel(d)
+		if err := tx.Create(model).Error; err != nil {
+			return err
+		}
+		d.SetID(model.ID)
+		return r.saveMessages(tx, d)
+	})
+}
+
+// Update saves all dispute fields and the messages added since it was loaded
+func (r *disputeRepository) Update(ctx context.Context, d *dispute.Dispute) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Messages").Save(newDisputeModel(d)).Error; err != nil {
+			return err
+		}
+		return r.saveMessages(tx, d)
+	})
+}
+
+// saveMessages inserts the messages in the thread that are not stored yet
+func (r *disputeRepository) saveMessages(tx *gorm.DB, d *dispute.Dispute) error {
+	for i, m := range d.Messages() {
+		if !m.IsNew() {
+			continue
+		}
+		model := newDisputeMessageModel(d.ID(), m)
+		if err := tx.Create(model).Error; err != nil {
+			return err
+		}
+		d.SetMessageID(i, model.ID)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/fx"
+)
+
+// FXRateModel is the GORM persistence model for an exchange Rate.
+type FXRateModel struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	BaseCurrency  string    `gorm:"size:3;not null;index:idx_fx_rates_pair" json:"base_currency"`
+	QuoteCurrency string    `gorm:"size:3;not null;index:idx_fx_rates_pair" json:"quote_currency"`
+	Rate          string    `gorm:"type:decimal(20,10);not null" json:"rate"`
+	EffectiveFrom time.Time `gorm:"not null;index:idx_fx_rates_pair" json:"effective_from"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name.
+func (FXRateModel) TableName() string {
+	return "fx_rates"
+}
+
+// toDomain converts the persistence model to the Rate entity.
+func (m *FXRateModel) toDomain() *fx.Rate {
+	return fx.ReconstituteRate(fx.RateParams{
+		ID:            m.ID,
+		BaseCurrency:  m.BaseCurrency,
+		QuoteCurrency: m.QuoteCurrency,
+		Rate:          m.Rate,
+		EffectiveFrom: m.EffectiveFrom,
+		CreatedAt:     m.CreatedAt,
+	})
+}
+
+// newFXRateModel converts the Rate entity to its persistence model.
+func newFXRateModel(r *fx.Rate) *FXRateModel {
+	return &FXRateModel{
+		ID:            r.ID(),
+		BaseCurrency:  r.BaseCurrency(),
+		QuoteCurrency: r.QuoteCurrency(),
+		Rate:          r.ExchangeRate().String(),
+		EffectiveFrom: r.EffectiveFrom(),
+		CreatedAt:     r.CreatedAt(),
+	}
+}
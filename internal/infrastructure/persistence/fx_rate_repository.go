@@ -0,0 +1,86 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/fx"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"gorm.io/gorm"
+)
+
+// fxRateRepository implements repository.FXRateRepository
+type fxRateRepository struct {
+	db *gorm.DB
+}
+
+// NewFXRateRepository creates a new exchange rate repository
+func NewFXRateRepository(db *gorm.DB) repository.FXRateRepository {
+	return &fxRateRepository{db: db}
+}
+
+// GetByID retrieves an exchange rate by ID
+func (r *fxRateRepository) GetByID(ctx context.Context, id uint) (*fx.Rate, error) {
+	var model FXRateModel
+	if err := conn(ctx, r.db).First(&model, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fx.ErrRateNotFound
+		}
+		return nil, err
+	}
+	return model.toDomain(), nil
+}
+
+// List retrieves the rates for a currency pair, or all pairs, newest effective first
+func (r *fxRateRepository) List(ctx context.Context, base, quote string) ([]*fx.Rate, error) {
+	query := conn(ctx, r.db)
+	if base != "" {
+		query = query.Where("base_currency = ?", base)
+	}
+	if quote != "" {
+		query = query.Where("quote_currency = ?", quote)
+	}
+
+	var models []FXRateModel
+	if err := query.Order("base_currency, quote_currency, effective_from DESC, id DESC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	rates := make([]*fx.Rate, len(models))
+	for i := range models {
+		rates[i] = models[i].toDomain()
+	}
+	return rates, nil
+}
+
+// GetRateAt retrieves the rate from base to quote in effect at a time
+func (r *fxRateRepository) GetRateAt(ctx context.Context, base, quote string, at time.Time) (*fx.Rate, error) {
+	var model FXRateModel
+	err := conn(ctx, r.db).
+		Where("base_currency = ? AND quote_currency = ? AND effective_from <= ?", base, quote, at).
+		Order("effective_from DESC, id DESC").
+		First(&model).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fx.ErrRateNotFound
+		}
+		return nil, err
+	}
+	return model.toDomain(), nil
+}
+
+// Create saves a new exchange rate and assigns its ID
+func (r *fxRateRepository) Create(ctx context.Context, rate *fx.Rate) error {
+	model := newFXRateModel(rate)
+	if err := conn(ctx, r.db).Create(model).Error; err != nil {
+		return err
+	}
+	rate.SetID(model.ID)
+	return nil
+}
+
+// Delete removes an exchange rate
+func (r *fxRateRepository) Delete(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Delete(&FXRateModel{}, id).Error
+}
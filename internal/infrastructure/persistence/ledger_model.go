@@ -0,0 +1,89 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/ledger"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+)
+
+// LedgerEntryModel is the GORM persistence model for a ledger Entry.
+// Entries are only ever inserted; the migration rejects updates and
+// deletes.
+type LedgerEntryModel struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	AgentID      uint      `gorm:"not null;index" json:"agent_id"`
+	Kind         string    `gorm:"size:40;not null" json:"kind"`
+	CommissionID *uint     `gorm:"index" json:"commission_id,omitempty"`
+	PayoutID     *uint     `gorm:"index" json:"payout_id,omitempty"`
+	Currency     string    `gorm:"size:3;not null" json:"currency"`
+	CreatedAt    time.Time `json:"created_at"`
+
+	// Relations
+	Lines []LedgerLineModel `gorm:"foreignKey:EntryID" json:"lines,omitempty"`
+}
+
+// TableName specifies the table name.
+func (LedgerEntryModel) TableName() string {
+	return "agent_ledger_entries"
+}
+
+// LedgerLineModel is the GORM persistence model for a line of a ledger
+// entry. Debits are positive and credits negative, so an account's
+// balance is the sum of its lines.
+type LedgerLineModel struct {
+	ID       uint    `gorm:"primaryKey" json:"id"`
+	EntryID  uint    `gorm:"not null;index" json:"entry_id"`
+	AgentID  uint    `gorm:"not null;index:idx_agent_ledger_lines_balance" json:"agent_id"`
+	Account  string  `gorm:"size:30;not null;index:idx_agent_ledger_lines_balance" json:"account"`
+	Amount   float64 `gorm:"type:decimal(12,2);not null" json:"amount"`
+	Currency string  `gorm:"size:3;not null" json:"currency"`
+}
+
+// TableName specifies the table name.
+func (LedgerLineModel) TableName() string {
+	return "agent_ledger_lines"
+}
+
+// toDomain converts the persistence model to the ledger Entry.
+func (m *LedgerEntryModel) toDomain() *ledger.Entry {
+	lines := make([]ledger.Line, len(m.Lines))
+	for i, line := range m.Lines {
+		lines[i] = ledger.ReconstituteLine(shared.LedgerAccount(line.Account), shared.MoneyFromFloat(line.Amount, line.Currency))
+	}
+
+	return ledger.Reconstitute(ledger.EntryParams{
+		ID:           m.ID,
+		AgentID:      m.AgentID,
+		Kind:         m.Kind,
+		CommissionID: m.CommissionID,
+		PayoutID:     m.PayoutID,
+		Lines:        lines,
+		CreatedAt:    m.CreatedAt,
+	})
+}
+
+// newLedgerEntryModel converts a ledger Entry to its persistence model,
+// with its lines.
+func newLedgerEntryModel(e *ledger.Entry) *LedgerEntryModel {
+	lines := make([]LedgerLineModel, len(e.Lines()))
+	for i, line := range e.Lines() {
+		lines[i] = LedgerLineModel{
+			AgentID:  e.AgentID(),
+			Account:  line.Account().String(),
+			Amount:   line.Amount().Float64(),
+			Currency: line.Amount().Currency(),
+		}
+	}
+
+	return &LedgerEntryModel{
+		ID:           e.ID(),
+		AgentID:      e.AgentID(),
+		Kind:         e.Kind(),
+		CommissionID: e.CommissionID(),
+		PayoutID:     e.PayoutID(),
+		Currency:     e.Currency(),
+		CreatedAt:    e.CreatedAt(),
+		Lines:        lines,
+	}
+}
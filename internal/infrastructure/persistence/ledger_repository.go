@@ -0,0 +1,100 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/ledger"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"gorm.io/gorm"
+)
+
+// ledgerRepository implements repository.LedgerReader
+type ledgerRepository struct {
+	db *gorm.DB
+}
+
+// NewLedgerRepository creates a new ledger repository
+func NewLedgerRepository(db *gorm.DB) repository.LedgerReader {
+	return &ledgerRepository{db: db}
+}
+
+// GetEntries retrieves an agent's ledger entries and their lines, newest
+// first
+func (r *ledgerRepository) GetEntries(ctx context.Context, filter repository.LedgerFilter) ([]*ledger.Entry, int64, error) {
+	query := conn(ctx, r.db).Model(&LedgerEntryModel{}).Where("agent_id = ?", filter.AgentID)
+	if filter.Account != "" {
+		query = query.Where("id IN (?)", r.db.Model(&LedgerLineModel{}).Select("entry_id").
+			Where("agent_id = ? AND account = ?", filter.AgentID, filter.Account))
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var models []LedgerEntryModel
+	err := paginate(query.Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }), filter.Page, filter.Limit).
+		Order("created_at DESC, id DESC").
+		Find(&models).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	entries := make([]*ledger.Entry, len(models))
+	for i := range models {
+		entries[i] = models[i].toDomain()
+	}
+	return entries, total, nil
+}
+
+// GetBalances sums an agent's ledger lines by account and currency
+func (r *ledgerRepository) GetBalances(ctx context.Context, agentID uint) (ledger.Balances, error) {
+	var rows []struct {
+		Account  string
+		Currency string
+		Total    float64
+	}
+	err := conn(ctx, r.db).Model(&LedgerLineModel{}).
+		Select("account, currency, COALESCE(SUM(amount), 0) AS total").
+		Where("agent_id = ?", agentID).
+		Group("account, currency").
+		Order("account, currency").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make(ledger.Balances, len(rows))
+	for i, row := range rows {
+		balances[i] = ledger.NewBalance(shared.LedgerAccount(row.Account), shared.MoneyFromFloat(row.Total, row.Currency))
+	}
+	return balances, nil
+}
+
+// GetUnbalanced returns the IDs of an agent's entries whose lines do not
+// net to zero
+func (r *ledgerRepository) GetUnbalanced(ctx context.Context, agentID uint) ([]uint, error) {
+	var ids []uint
+	err := conn(ctx, r.db).Model(&LedgerLineModel{}).
+		Select("entry_id").
+		Where("agent_id = ?", agentID).
+		Group("entry_id").
+		Having("SUM(amount) <> 0").
+		Order("entry_id").
+		Scan(&ids).Error
+	return ids, err
+}
+
+// writeLedgerEntries inserts ledger entries and their lines within the
+// transaction saving the aggregate they record
+func writeLedgerEntries(tx *gorm.DB, entries []*ledger.Entry) error {
+	for _, entry := range entries {
+		model := newLedgerEntryModel(entry)
+		if err := tx.Create(model).Error; err != nil {
+			return err
+		}
+		entry.SetID(model.ID)
+	}
+	return nil
+}
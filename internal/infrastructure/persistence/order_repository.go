@@ -0,0 +1,60 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"gorm.io/gorm"
+)
+
+// orderRepository implements repository.OrderReader
+type orderRepository struct {
+	db *gorm.DB
+}
+
+// NewOrderRepository creates a new read-only order repository
+func NewOrderRepository(db *gorm.DB) repository.OrderReader {
+	return &orderRepository{db: db}
+}
+
+// GetByID retrieves one of an agent's orders
+func (r *orderRepository) GetByID(ctx context.Context, agentUserID, orderID string) (*domain.Order, error) {
+	var order domain.Order
+	if err := conn(ctx, r.db).Where("agent_id = ? AND id = ?", agentUserID, orderID).First(&order).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, err
+	}
+	return &order, nil
+}
+
+// List retrieves an agent's orders, newest first
+func (r *orderRepository) List(ctx context.Context, agentUserID, status string, page, limit int) ([]domain.Order, int64, error) {
+	query := conn(ctx, r.db).Model(&domain.Order{}).Where("agent_id = ?", agentUserID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var orders []domain.Order
+	if err := paginate(query, page, limit).Order("created_at DESC").Find(&orders).Error; err != nil {
+		return nil, 0, err
+	}
+	return orders, total, nil
+}
+
+// GetSummary counts an agent's orders and sums their totals within a period
+func (r *orderRepository) GetSummary(ctx context.Context, agentUserID string, period repository.Period) (*repository.OrderSummary, error) {
+	summary := &repository.OrderSummary{}
+	err := withPeriod(conn(ctx, r.db).Model(&domain.Order{}).Where("agent_id = ?", agentUserID), period).
+		Select("COUNT(*) AS count, COALESCE(SUM(total), 0) AS total").
+		Scan(summary).Error
+	return summary, err
+}
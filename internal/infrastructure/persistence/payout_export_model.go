@@ -0,0 +1,60 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/payout"
+)
+
+// PayoutExportModel is the GORM persistence model for a payment file
+// Export.
+type PayoutExportModel struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	RunID       uint      `gorm:"not null;index" json:"run_id"`
+	Format      string    `gorm:"size:20;not null" json:"format"`
+	FileName    string    `gorm:"size:255;not null" json:"file_name"`
+	ContentType string    `gorm:"size:100;not null" json:"content_type"`
+	Content     []byte    `gorm:"type:bytea;not null" json:"-"`
+	Checksum    string    `gorm:"size:64;not null" json:"checksum"`
+	Count       int       `gorm:"not null" json:"count"`
+	ExportedBy  string    `gorm:"size:100" json:"exported_by,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name.
+func (PayoutExportModel) TableName() string {
+	return "payout_exports"
+}
+
+// toDomain converts the persistence model to the Export aggregate.
+func (m *PayoutExportModel) toDomain() *payout.Export {
+	return payout.ReconstituteExport(payout.ExportParams{
+		ID:          m.ID,
+		RunID:       m.RunID,
+		Format:      m.Format,
+		FileName:    m.FileName,
+		ContentType: m.ContentType,
+		Content:     m.Content,
+		Checksum:    m.Checksum,
+		Count:       m.Count,
+		ExportedBy:  m.ExportedBy,
+		CreatedAt:   m.CreatedAt,
+	})
+}
+
+// newPayoutExportModel converts the Export aggregate to its persistence
+// model.
+func newPayoutExportModel(e *payout.Export) *PayoutExportModel {
+	return &PayoutExportModel{
+		ID:          e.ID(),
+		RunID:       e.RunID(),
+		Format:      e.Format(),
+		FileName:    e.FileName(),
+		ContentType: e.ContentType(),
+		Content:     e.Content(),
+		Checksum:    e.Checksum(),
+		Count:       e.Count(),
+		ExportedBy:  e.ExportedBy(),
+		CreatedAt:   e.CreatedAt(),
+	}
+}
@@ -0,0 +1,58 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/payout"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"gorm.io/gorm"
+)
+
+// payoutExportRepository implements repository.PayoutExportRepository
+type payoutExportRepository struct {
+	db *gorm.DB
+}
+
+// NewPayoutExportRepository creates a new payment file export repository
+func NewPayoutExportRepository(db *gorm.DB) repository.PayoutExportRepository {
+	return &payoutExportRepository{db: db}
+}
+
+// GetByID retrieves an export and its file by ID
+func (r *payoutExportRepository) GetByID(ctx context.Context, id uint) (*payout.Export, error) {
+	var model PayoutExportModel
+	if err := conn(ctx, r.db).First(&model, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, payout.ErrExportNotFound
+		}
+		return nil, err
+	}
+	return model.toDomain(), nil
+}
+
+// ListByRun lists a run's exports, newest first
+func (r *payoutExportRepository) ListByRun(ctx context.Context, runID uint) ([]*payout.Export, error) {
+	var models []PayoutExportModel
+	err := conn(ctx, r.db).Where("run_id = ?", runID).
+		Order("created_at DESC, id DESC").Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	exports := make([]*payout.Export, len(models))
+	for i := range models {
+		exports[i] = models[i].toDomain()
+	}
+	return exports, nil
+}
+
+// Create saves a new export and assigns its ID
+func (r *payoutExportRepository) Create(ctx context.Context, export *payout.Export) error {
+	model := newPayoutExportModel(export)
+	if err := conn(ctx, r.db).Create(model).Error; err != nil {
+		return err
+	}
+	export.SetID(model.ID)
+	return nil
+}
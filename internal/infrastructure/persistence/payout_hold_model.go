@@ -0,0 +1,159 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/payout"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+)
+
+// PayoutHoldModel is the GORM persistence model for a payout Hold.
+type PayoutHoldModel struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	AgentID    uint       `gorm:"not null;index" json:"agent_id"`
+	Reason     string     `gorm:"type:text;not null" json:"reason"`
+	ExpiresAt  time.Time  `gorm:"not null" json:"expires_at"`
+	CreatedBy  string     `gorm:"size:255" json:"created_by,omitempty"`
+	ReleasedBy string     `gorm:"size:255" json:"released_by,omitempty"`
+	ReleasedAt *time.Time `json:"released_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name.
+func (PayoutHoldModel) TableName() string {
+	return "payout_holds"
+}
+
+// toDomain converts the persistence model to the Hold aggregate.
+func (m *PayoutHoldModel) toDomain() *payout.Hold {
+	return payout.ReconstituteHold(payout.HoldParams{
+		ID:         m.ID,
+		AgentID:    m.AgentID,
+		Reason:     m.Reason,
+		ExpiresAt:  m.ExpiresAt,
+		CreatedBy:  m.CreatedBy,
+		ReleasedBy: m.ReleasedBy,
+		ReleasedAt: m.ReleasedAt,
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
+	})
+}
+
+// newPayoutHoldModel converts the Hold aggregate to its persistence model.
+func newPayoutHoldModel(h *payout.Hold) *PayoutHoldModel {
+	return &PayoutHoldModel{
+		ID:         h.ID(),
+		AgentID:    h.AgentID(),
+		Reason:     h.Reason(),
+		ExpiresAt:  h.ExpiresAt(),
+		CreatedBy:  h.CreatedBy(),
+		ReleasedBy: h.ReleasedBy(),
+		ReleasedAt: h.ReleasedAt(),
+		CreatedAt:  h.CreatedAt(),
+		UpdatedAt:  h.UpdatedAt(),
+	}
+}
+
+// ReservePolicyModel is the GORM persistence model for a ReservePolicy.
+type ReservePolicyModel struct {
+	AgentID   uint      `gorm:"primaryKey;autoIncrement:false" json:"agent_id"`
+	Percent   float64   `gorm:"type:decimal(5,2);not null" json:"percent"`
+	Days      int       `gorm:"not null" json:"days"`
+	Reason    string    `gorm:"type:text;not null" json:"reason"`
+	UpdatedBy string    `gorm:"size:255" json:"updated_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name.
+func (ReservePolicyModel) TableName() string {
+	return "agent_reserve_policies"
+}
+
+// toDomain converts the persistence model to the ReservePolicy aggregate.
+func (m *ReservePolicyModel) toDomain() *payout.ReservePolicy {
+	return payout.ReconstituteReservePolicy(payout.ReservePolicyParams{
+		AgentID:   m.AgentID,
+		Percent:   m.Percent,
+		Days:      m.Days,
+		Reason:    m.Reason,
+		UpdatedBy: m.UpdatedBy,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	})
+}
+
+// newReservePolicyModel converts the ReservePolicy aggregate to its
+// persistence model.
+func newReservePolicyModel(p *payout.ReservePolicy) *ReservePolicyModel {
+	return &ReservePolicyModel{
+		AgentID:   p.AgentID(),
+		Percent:   p.Percent(),
+		Days:      p.Days(),
+		Reason:    p.Reason(),
+		UpdatedBy: p.UpdatedBy(),
+		CreatedAt: p.CreatedAt(),
+		UpdatedAt: p.UpdatedAt(),
+	}
+}
+
+// PayoutReserveModel is the GORM persistence model for a payout Reserve.
+type PayoutReserveModel struct {
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	AgentID         uint       `gorm:"not null;index" json:"agent_id"`
+	PayoutID        uint       `gorm:"not null;uniqueIndex" json:"payout_id"`
+	Amount          float64    `gorm:"type:decimal(10,2);not null" json:"amount"`
+	Currency        string     `gorm:"size:3;not null" json:"currency"`
+	Percent         float64    `gorm:"type:decimal(5,2);not null" json:"percent"`
+	ReleaseAt       time.Time  `gorm:"not null" json:"release_at"`
+	Status          string     `gorm:"size:20;not null;default:'held'" json:"status"`
+	ReleasePayoutID *uint      `gorm:"index" json:"release_payout_id,omitempty"`
+	ReleasedAt      *time.Time `json:"released_at,omitempty"`
+	Version         int        `gorm:"not null;default:0" json:"version"` // Bumped on every update, for optimistic locking
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name.
+func (PayoutReserveModel) TableName() string {
+	return "payout_reserves"
+}
+
+// toDomain converts the persistence model to the Reserve aggregate.
+func (m *PayoutReserveModel) toDomain() *payout.Reserve {
+	return payout.ReconstituteReserve(payout.ReserveParams{
+		ID:              m.ID,
+		AgentID:         m.AgentID,
+		PayoutID:        m.PayoutID,
+		Amount:          shared.MoneyFromFloat(m.Amount, m.Currency),
+		Percent:         m.Percent,
+		ReleaseAt:       m.ReleaseAt,
+		Status:          m.Status,
+		ReleasePayoutID: m.ReleasePayoutID,
+		ReleasedAt:      m.ReleasedAt,
+		Version:         m.Version,
+		CreatedAt:       m.CreatedAt,
+		UpdatedAt:       m.UpdatedAt,
+	})
+}
+
+// newPayoutReserveModel converts the Reserve aggregate to its persistence
+// model.
+func newPayoutReserveModel(r *payout.Reserve) *PayoutReserveModel {
+	return &PayoutReserveModel{
+		ID:              r.ID(),
+		AgentID:         r.AgentID(),
+		PayoutID:        r.PayoutID(),
+		Amount:          r.Amount().Float64(),
+		Currency:        r.Amount().Currency(),
+		Percent:         r.Percent(),
+		ReleaseAt:       r.ReleaseAt(),
+		Status:          r.Status().String(),
+		ReleasePayoutID: r.ReleasePayoutID(),
+		ReleasedAt:      r.ReleasedAt(),
+		Version:         r.Version(),
+		CreatedAt:       r.CreatedAt(),
+		UpdatedAt:       r.UpdatedAt(),
+	}
+}
@@ -0,0 +1,195 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/payout"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"gorm.io/gorm"
+)
+
+// payoutHoldRepository implements repository.PayoutHoldRepository
+type payoutHoldRepository struct {
+	db *gorm.DB
+}
+
+// NewPayoutHoldRepository creates a new payout hold repository
+func NewPayoutHoldRepository(db *gorm.DB) repository.PayoutHoldRepository {
+	return &payoutHoldRepository{db: db}
+}
+
+// GetByID retrieves a payout hold by ID
+func (r *payoutHoldRepository) GetByID(ctx context.Context, id uint) (*payout.Hold, error) {
+	var model PayoutHoldModel
+	if err := conn(ctx, r.db).First(&model, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, payout.ErrHoldNotFound
+		}
+		return nil, err
+	}
+	return model.toDomain(), nil
+}
+
+// ListByAgent lists an agent's payout holds, newest first
+func (r *payoutHoldRepository) ListByAgent(ctx context.Context, agentID uint) ([]*payout.Hold, error) {
+	return r.list(conn(ctx, r.db).Where("agent_id = ?", agentID))
+}
+
+// ListActive lists the payout holds active at the given time, newest first
+func (r *payoutHoldRepository) ListActive(ctx context.Context, at time.Time) ([]*payout.Hold, error) {
+	return r.list(conn(ctx, r.db).Where("released_at IS NULL AND expires_at > ?", at))
+}
+
+func (r *payoutHoldRepository) list(query *gorm.DB) ([]*payout.Hold, error) {
+	var models []PayoutHoldModel
+	if err := query.Order("created_at DESC, id DESC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	holds := make([]*payout.Hold, len(models))
+	for i := range models {
+		holds[i] = models[i].toDomain()
+	}
+	return holds, nil
+}
+
+// Create saves a new payout hold and assigns its ID
+func (r *payoutHoldRepository) Create(ctx context.Context, hold *payout.Hold) error {
+	model := newPayoutHoldModel(hold)
+	if err := conn(ctx, r.db).Create(model).Error; err != nil {
+		return err
+	}
+	hold.SetID(model.ID)
+	return nil
+}
+
+// Update saves all payout hold fields
+func (r *payoutHoldRepository) Update(ctx context.Context, hold *payout.Hold) error {
+	return conn(ctx, r.db).Save(newPayoutHoldModel(hold)).Error
+}
+
+// reservePolicyRepository implements repository.ReservePolicyRepository
+type reservePolicyRepository struct {
+	db *gorm.DB
+}
+
+// NewReservePolicyRepository creates a new reserve policy repository
+func NewReservePolicyRepository(db *gorm.DB) repository.ReservePolicyRepository {
+	return &reservePolicyRepository{db: db}
+}
+
+// GetByAgentID retrieves an agent's reserve policy
+func (r *reservePolicyRepository) GetByAgentID(ctx context.Context, agentID uint) (*payout.ReservePolicy, error) {
+	var model ReservePolicyModel
+	if err := conn(ctx, r.db).Where("agent_id = ?", agentID).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, payout.ErrReservePolicyNotFound
+		}
+		return nil, err
+	}
+	return model.toDomain(), nil
+}
+
+// Save creates or replaces an agent's reserve policy
+func (r *reservePolicyRepository) Save(ctx context.Context, policy *payout.ReservePolicy) error {
+	return conn(ctx, r.db).Save(newReservePolicyModel(policy)).Error
+}
+
+// Delete removes an agent's reserve policy
+func (r *reservePolicyRepository) Delete(ctx context.Context, agentID uint) error {
+	result := conn(ctx, r.db).Where("agent_id = ?", agentID).Delete(&ReservePolicyModel{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return payout.ErrReservePolicyNotFound
+	}
+	return nil
+}
+
+// payoutReserveRepository implements repository.PayoutReserveRepository
+type payoutReserveRepository struct {
+	db *gorm.DB
+}
+
+// NewPayoutReserveRepository creates a new payout reserve repository
+func NewPayoutReserveRepository(db *gorm.DB) repository.PayoutReserveRepository {
+	return &payoutReserveRepository{db: db}
+}
+
+// ListByAgent lists an agent's reserves, newest first
+func (r *payoutReserveRepository) ListByAgent(ctx context.Context, agentID uint) ([]*payout.Reserve, error) {
+	return r.list(conn(ctx, r.db).Where("agent_id = ?", agentID).Order("created_at DESC, id DESC"))
+}
+
+// GetDue lists the held reserves due for release at the given time, oldest
+// first
+func (r *payoutReserveRepository) GetDue(ctx context.Context, at time.Time) ([]*payout.Reserve, error) {
+	return r.list(conn(ctx, r.db).
+		Where("status = ? AND release_at <= ?", shared.ReserveHeld.String(), at).
+		Order("id"))
+}
+
+// GetByPayoutID retrieves the reserve kept back from a payout
+func (r *payoutReserveRepository) GetByPayoutID(ctx context.Context, payoutID uint) (*payout.Reserve, error) {
+	var model PayoutReserveModel
+	if err := conn(ctx, r.db).Where("payout_id = ?", payoutID).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, payout.ErrReserveNotFound
+		}
+		return nil, err
+	}
+	return model.toDomain(), nil
+}
+
+// GetByReleasePayoutID lists the reserves a payout released
+func (r *payoutReserveRepository) GetByReleasePayoutID(ctx context.Context, payoutID uint) ([]*payout.Reserve, error) {
+	return r.list(conn(ctx, r.db).Where("release_payout_id = ?", payoutID).Order("id"))
+}
+
+func (r *payoutReserveRepository) list(query *gorm.DB) ([]*payout.Reserve, error) {
+	var models []PayoutReserveModel
+	if err := query.Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	reserves := make([]*payout.Reserve, len(models))
+	for i := range models {
+		reserves[i] = models[i].toDomain()
+	}
+	return reserves, nil
+}
+
+// Create saves a new reserve and assigns its ID. It returns ErrDuplicate
+// if the payout already has a reserve.
+func (r *payoutReserveRepository) Create(ctx context.Context, reserve *payout.Reserve) error {
+	model := newPayoutReserveModel(reserve)
+	if err := conn(ctx, r.db).Create(model).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return repository.ErrDuplicate
+		}
+		return err
+	}
+	reserve.SetID(model.ID)
+	return nil
+}
+
+// Update saves all reserve fields. It returns repository.ErrConflict if
+// the reserve was saved since it was loaded, such as by another payout
+// releasing it.
+func (r *payoutReserveRepository) Update(ctx context.Context, reserve *payout.Reserve) error {
+	model := newPayoutReserveModel(reserve)
+	model.Version++
+	result := conn(ctx, r.db).Model(model).Where("version = ?", reserve.Version()).Select("*").Updates(model)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repository.ErrConflict
+	}
+	reserve.SetVersion(model.Version)
+	return nil
+}
@@ -0,0 +1,92 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/payout"
+)
+
+// PayoutMethodModel is the GORM persistence model for a payout Method.
+// The account number is only stored encrypted.
+type PayoutMethodModel struct {
+	ID                     uint       `gorm:"primaryKey" json:"id"`
+	AgentID                uint       `gorm:"not null;index" json:"agent_id"`
+	Type                   string     `gorm:"size:20;not null" json:"type"`
+	Provider               string     `gorm:"size:100;not null" json:"provider"`
+	ProviderCode           string     `gorm:"size:20" json:"provider_code,omitempty"`
+	AccountName            string     `gorm:"size:255;not null" json:"account_name"`
+	AccountNumberEncrypted string     `gorm:"type:text;not null" json:"-"`
+	IsDefault              bool       `gorm:"not null;default:false" json:"is_default"`
+	Status                 string     `gorm:"size:20;not null" json:"status"`
+	VerificationHash       string     `gorm:"size:64" json:"-"`
+	VerificationExpiresAt  *time.Time `json:"-"`
+	VerificationAttempts   int        `gorm:"not null;default:0" json:"-"`
+	VerifiedAt             *time.Time `json:"verified_at,omitempty"`
+	ChangedAt              time.Time  `gorm:"not null" json:"changed_at"`
+	Version                int        `gorm:"not null;default:0" json:"version"` // Bumped on every update, for optimistic locking
+	CreatedAt              time.Time  `json:"created_at"`
+	UpdatedAt              time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name.
+func (PayoutMethodModel) TableName() string {
+	return "agent_payout_methods"
+}
+
+// toDomain converts the persistence model to the Method aggregate,
+// decrypting the account number.
+func (m *PayoutMethodModel) toDomain(c *FieldCipher) (*payout.Method, error) {
+	accountNumber, err := c.Decrypt(m.AccountNumberEncrypted)
+	if err != nil {
+		return nil, err
+	}
+
+	return payout.ReconstituteMethod(payout.MethodParams{
+		ID:                    m.ID,
+		AgentID:               m.AgentID,
+		Type:                  m.Type,
+		Provider:              m.Provider,
+		ProviderCode:          m.ProviderCode,
+		AccountName:           m.AccountName,
+		AccountNumber:         accountNumber,
+		IsDefault:             m.IsDefault,
+		Status:                m.Status,
+		VerificationHash:      m.VerificationHash,
+		VerificationExpiresAt: m.VerificationExpiresAt,
+		VerificationAttempts:  m.VerificationAttempts,
+		VerifiedAt:            m.VerifiedAt,
+		ChangedAt:             m.ChangedAt,
+		Version:               m.Version,
+		CreatedAt:             m.CreatedAt,
+		UpdatedAt:             m.UpdatedAt,
+	}), nil
+}
+
+// newPayoutMethodModel converts the Method aggregate to its persistence
+// model, encrypting the account number.
+func newPayoutMethodModel(m *payout.Method, c *FieldCipher) (*PayoutMethodModel, error) {
+	encrypted, err := c.Encrypt(m.AccountNumber())
+	if err != nil {
+		return nil, err
+	}
+
+	return &PayoutMethodModel{
+		ID:                     m.ID(),
+		AgentID:                m.AgentID(),
+		Type:                   m.Type().String(),
+		Provider:               m.Provider(),
+		ProviderCode:           m.ProviderCode(),
+		AccountName:            m.AccountName(),
+		AccountNumberEncrypted: encrypted,
+		IsDefault:              m.IsDefault(),
+		Status:                 m.Status().String(),
+		VerificationHash:       m.VerificationHash(),
+		VerificationExpiresAt:  m.VerificationExpiresAt(),
+		VerificationAttempts:   m.VerificationAttempts(),
+		VerifiedAt:             m.VerifiedAt(),
+		ChangedAt:              m.ChangedAt(),
+		Version:                m.Version(),
+		CreatedAt:              m.CreatedAt(),
+		UpdatedAt:              m.UpdatedAt(),
+	}, nil
+}
@@ -0,0 +1,125 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/payout"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"gorm.io/gorm"
+)
+
+// payoutMethodRepository implements repository.PayoutMethodRepository
+type payoutMethodRepository struct {
+	db     *gorm.DB
+	cipher *FieldCipher
+}
+
+// NewPayoutMethodRepository creates a new payout method repository.
+// Account numbers are encrypted with cipher before they are written.
+func NewPayoutMethodRepository(db *gorm.DB, cipher *FieldCipher) repository.PayoutMethodRepository {
+	return &payoutMethodRepository{db: db, cipher: cipher}
+}
+
+// GetByID retrieves a payout method by ID
+func (r *payoutMethodRepository) GetByID(ctx context.Context, id uint) (*payout.Method, error) {
+	var model PayoutMethodModel
+	if err := conn(ctx, r.db).First(&model, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, payout.ErrMethodNotFound
+		}
+		return nil, err
+	}
+	return model.toDomain(r.cipher)
+}
+
+// ListByAgent lists an agent's payout methods, oldest first
+func (r *payoutMethodRepository) ListByAgent(ctx context.Context, agentID uint) ([]*payout.Method, error) {
+	var models []PayoutMethodModel
+	if err := conn(ctx, r.db).Where("agent_id = ?", agentID).Order("id").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	methods := make([]*payout.Method, len(models))
+	for i := range models {
+		m, err := models[i].toDomain(r.cipher)
+		if err != nil {
+			return nil, err
+		}
+		methods[i] = m
+	}
+	return methods, nil
+}
+
+// Create saves a new payout method and assigns its ID
+func (r *payoutMethodRepository) Create(ctx context.Context, method *payout.Method) error {
+	model, err := newPayoutMethodModel(method, r.cipher)
+	if err != nil {
+		return err
+	}
+	if err := conn(ctx, r.db).Create(model).Error; err != nil {
+		return err
+	}
+	method.SetID(model.ID)
+	return nil
+}
+
+// Update saves all payout method fields. It returns repository.ErrConflict
+// if the method was saved since it was loaded.
+func (r *payoutMethodRepository) Update(ctx context.Context, method *payout.Method) error {
+	model, err := newPayoutMethodModel(method, r.cipher)
+	if err != nil {
+		return err
+	}
+	return r.update(conn(ctx, r.db), method, model)
+}
+
+// update saves model over the version method was loaded at
+func (r *payoutMethodRepository) update(db *gorm.DB, method *payout.Method, model *PayoutMethodModel) error {
+	model.Version++
+	result := db.Model(model).Where("version = ?", method.Version()).Select("*").Updates(model)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repository.ErrConflict
+	}
+	method.SetVersion(model.Version)
+	return nil
+}
+
+// SetDefault saves a method made the default and clears the default on
+// the agent's other methods, in one transaction. Like Update, it returns
+// repository.ErrConflict if the method was saved since it was loaded.
+func (r *payoutMethodRepository) SetDefault(ctx context.Context, method *payout.Method) error {
+	model, err := newPayoutMethodModel(method, r.cipher)
+	if err != nil {
+		return err
+	}
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// Cleared first: at most one default per agent is enforced by index
+		err := tx.Model(&PayoutMethodModel{}).
+			Where("agent_id = ? AND id <> ? AND is_default", method.AgentID(), method.ID()).
+			Updates(map[string]interface{}{
+				"is_default": false,
+				"version":    gorm.Expr("version + 1"),
+				"updated_at": gorm.Expr("NOW()"),
+			}).Error
+		if err != nil {
+			return err
+		}
+		return r.update(tx, method, model)
+	})
+}
+
+// Delete removes a payout method
+func (r *payoutMethodRepository) Delete(ctx context.Context, id uint) error {
+	result := conn(ctx, r.db).Delete(&PayoutMethodModel{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return payout.ErrMethodNotFound
+	}
+	return nil
+}
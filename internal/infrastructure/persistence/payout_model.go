@@ -1,20 +1,35 @@
 package persistence
 
 import (
+	"encoding/json"
 	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/payout"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
 )
 
 // PayoutModel is the GORM persistence model for Payout.
 type PayoutModel struct {
-	ID            uint       `gorm:"primaryKey" json:"id"`
-	AgentID       uint       `gorm:"not null;index" json:"agent_id"`
-	Amount        float64    `gorm:"type:decimal(10,2);not null" json:"amount"`
-	Period        string     `gorm:"size:20;not null" json:"period"`  // Format: YYYY-MM
-	CommissionIDs string     `gorm:"type:text" json:"commission_ids"` // JSON array of commission IDs
-	Status        string     `gorm:"size:20;default:'pending'" json:"status"`
-	PaidAt        *time.Time `json:"paid_at,omitempty"`
-	CreatedAt     time.Time  `json:"created_at"`
-	UpdatedAt     time.Time  `json:"updated_at"`
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	AgentID         uint       `gorm:"not null;index" json:"agent_id"`
+	Amount          float64    `gorm:"type:decimal(10,2);not null" json:"amount"`
+	Deductions      float64    `gorm:"type:decimal(10,2);not null;default:0" json:"deductions"`
+	Withheld        float64    `gorm:"type:decimal(10,2);not null;default:0" json:"withheld"`
+	WithholdingRate float64    `gorm:"type:decimal(5,2);not null;default:0" json:"withholding_rate"`
+	Reserved        float64    `gorm:"type:decimal(10,2);not null;default:0" json:"reserved"`
+	ReservePercent  float64    `gorm:"type:decimal(5,2);not null;default:0" json:"reserve_percent"`
+	Released        float64    `gorm:"type:decimal(10,2);not null;default:0" json:"released"`
+	Currency        string     `gorm:"size:3;not null;default:'MYR'" json:"currency"`
+	Period          string     `gorm:"size:20;not null" json:"period"`  // Format: YYYY-MM
+	CommissionIDs   string     `gorm:"type:text" json:"commission_ids"` // JSON array of commission IDs
+	Status          string     `gorm:"size:20;default:'pending'" json:"status"`
+	TransactionRef  string     `gorm:"size:100" json:"transaction_ref,omitempty"`
+	FailureReason   string     `gorm:"type:text" json:"failure_reason,omitempty"`
+	Retries         int        `gorm:"not null;default:0" json:"retries"`
+	PaidAt          *time.Time `json:"paid_at,omitempty"`
+	Version         int        `gorm:"not null;default:0" json:"version"` // Bumped on every update, for optimistic locking
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
 
 	// Relations
 	Agent AgentModel `gorm:"foreignKey:AgentID" json:"agent,omitempty"`
@@ -24,3 +39,79 @@ type PayoutModel struct {
 func (PayoutModel) TableName() string {
 	return "payouts"
 }
+
+// commissionIDs decodes the stored JSON array of commission IDs.
+func (m *PayoutModel) commissionIDs() []uint {
+	var ids []uint
+	if m.CommissionIDs != "" {
+		_ = json.Unmarshal([]byte(m.CommissionIDs), &ids)
+	}
+	return ids
+}
+
+// toDomain converts the persistence model to the Payout aggregate.
+// Items are built from the given commissions, keyed by ID; IDs with no
+// matching commission are kept with an empty order ID and zero amount.
+// Commissions paid in another currency than they were earned in are
+// shown at their converted amount.
+func (m *PayoutModel) toDomain(commissions map[uint]CommissionModel) *payout.Payout {
+	ids := m.commissionIDs()
+	items := make([]payout.PayoutItem, 0, len(ids))
+	for _, id := range ids {
+		c := commissions[id]
+		amount := shared.MoneyFromFloat(c.Amount, c.Currency)
+		if c.Currency != m.Currency && c.PayoutCurrency == m.Currency {
+			amount = shared.MoneyFromFloat(c.PayoutAmount, c.PayoutCurrency)
+		}
+		items = append(items, payout.NewPayoutItem(id, c.OrderID, amount))
+	}
+
+	return payout.Reconstitute(payout.PayoutParams{
+		ID:              m.ID,
+		AgentID:         m.AgentID,
+		Period:          m.Period,
+		Items:           items,
+		Deductions:      shared.MoneyFromFloat(m.Deductions, m.Currency),
+		Withheld:        shared.MoneyFromFloat(m.Withheld, m.Currency),
+		WithholdingRate: m.WithholdingRate,
+		Reserved:        shared.MoneyFromFloat(m.Reserved, m.Currency),
+		ReservePercent:  m.ReservePercent,
+		Released:        shared.MoneyFromFloat(m.Released, m.Currency),
+		Amount:          shared.MoneyFromFloat(m.Amount, m.Currency),
+		Status:          m.Status,
+		TransactionRef:  m.TransactionRef,
+		FailureReason:   m.FailureReason,
+		Retries:         m.Retries,
+		PaidAt:          m.PaidAt,
+		CreatedAt:       m.CreatedAt,
+		Version:         m.Version,
+		UpdatedAt:       m.UpdatedAt,
+	})
+}
+
+// newPayoutModel converts the Payout aggregate to its persistence model.
+func newPayoutModel(p *payout.Payout) *PayoutModel {
+	ids, _ := json.Marshal(p.CommissionIDs())
+	return &PayoutModel{
+		ID:              p.ID(),
+		AgentID:         p.AgentID(),
+		Amount:          p.Amount().Float64(),
+		Deductions:      p.Deductions().Float64(),
+		Withheld:        p.Withheld().Float64(),
+		WithholdingRate: p.WithholdingRate(),
+		Reserved:        p.Reserved().Float64(),
+		ReservePercent:  p.ReservePercent(),
+		Released:        p.Released().Float64(),
+		Currency:        p.Currency(),
+		Period:          p.Period(),
+		CommissionIDs:   string(ids),
+		Status:          p.Status().String(),
+		TransactionRef:  p.TransactionRef(),
+		FailureReason:   p.FailureReason(),
+		Retries:         p.Retries(),
+		PaidAt:          p.PaidAt(),
+		Version:         p.Version(),
+		CreatedAt:       p.CreatedAt(),
+		UpdatedAt:       p.UpdatedAt(),
+	}
+}
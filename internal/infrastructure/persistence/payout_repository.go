@@ -0,0 +1,151 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/payout"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"gorm.io/gorm"
+)
+
+// payoutRepository implements repository.PayoutRepository
+type payoutRepository struct {
+	db *gorm.DB
+}
+
+// NewPayoutRepository creates a new payout repository
+func NewPayoutRepository(db *gorm.DB) repository.PayoutRepository {
+	return &payoutRepository{db: db}
+}
+
+// GetByID retrieves a payout and its items by ID
+func (r *payoutRepository) GetByID(ctx context.Context, id uint) (*payout.Payout, error) {
+	var model PayoutModel
+	if err := conn(ctx, r.db).First(&model, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, payout.ErrPayoutNotFound
+		}
+		return nil, err
+	}
+
+	payouts, err := r.toDomain(ctx, []PayoutModel{model})
+	if err != nil {
+		return nil, err
+	}
+	return payouts[0], nil
+}
+
+// GetByAgentID retrieves an agent's payouts, newest first
+func (r *payoutRepository) GetByAgentID(ctx context.Context, agentID uint, page, limit int) ([]*payout.Payout, int64, error) {
+	return r.list(ctx, conn(ctx, r.db).Model(&PayoutModel{}).Where("agent_id = ?", agentID), page, limit)
+}
+
+// GetPending retrieves all pending payouts, newest first
+func (r *payoutRepository) GetPending(ctx context.Context, page, limit int) ([]*payout.Payout, int64, error) {
+	return r.list(ctx, conn(ctx, r.db).Model(&PayoutModel{}).Where("status = ?", shared.PayoutPending), page, limit)
+}
+
+// GetPaid retrieves the completed payouts paid within the period, oldest
+// first
+func (r *payoutRepository) GetPaid(ctx context.Context, period repository.Period) ([]*payout.Payout, error) {
+	query := conn(ctx, r.db).Where("status = ? AND paid_at IS NOT NULL", shared.PayoutCompleted)
+	if !period.From.IsZero() {
+		query = query.Where("paid_at >= ?", period.From)
+	}
+	if !period.To.IsZero() {
+		query = query.Where("paid_at < ?", period.To)
+	}
+
+	var models []PayoutModel
+	if err := query.Order("paid_at, id").Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return r.toDomain(ctx, models)
+}
+
+func (r *payoutRepository) list(ctx context.Context, query *gorm.DB, page, limit int) ([]*payout.Payout, int64, error) {
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var models []PayoutModel
+	if err := paginate(query, page, limit).Order("created_at DESC").Find(&models).Error; err != nil {
+		return nil, 0, err
+	}
+
+	payouts, err := r.toDomain(ctx, models)
+	if err != nil {
+		return nil, 0, err
+	}
+	return payouts, total, nil
+}
+
+// toDomain loads the commissions referenced by the payouts in one query
+// and builds the aggregates.
+func (r *payoutRepository) toDomain(ctx context.Context, models []PayoutModel) ([]*payout.Payout, error) {
+	var ids []uint
+	for i := range models {
+		ids = append(ids, models[i].commissionIDs()...)
+	}
+
+	commissions := make(map[uint]CommissionModel, len(ids))
+	if len(ids) > 0 {
+		var rows []CommissionModel
+		if err := conn(ctx, r.db).Where("id IN ?", ids).Find(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			commissions[row.ID] = row
+		}
+	}
+
+	payouts := make([]*payout.Payout, len(models))
+	for i := range models {
+		payouts[i] = models[i].toDomain(commissions)
+	}
+	return payouts, nil
+}
+
+// Create creates a new payout and assigns its ID
+func (r *payoutRepository) Create(ctx context.Context, p *payout.Payout) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		return createPayout(tx, p)
+	})
+}
+
+// createPayout creates a payout and its ledger entries within a
+// transaction and assigns its ID
+func createPayout(tx *gorm.DB, p *payout.Payout) error {
+	model := newPayoutModel(p)
+	if err := tx.Create(model).Error; err != nil {
+		return err
+	}
+	p.SetID(model.ID)
+	return writeLedgerEntries(tx, p.LedgerEntries())
+}
+
+// Update saves all payout fields and the ledger entries for its
+// transitions. It refuses a payout saved by someone else since it was
+// read, so the ledger entries are never posted twice.
+func (r *payoutRepository) Update(ctx context.Context, p *payout.Payout) error {
+	model := newPayoutModel(p)
+	model.Version++
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(model).Where("version = ?", p.Version()).Select("*").Updates(model)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return repository.ErrConflict
+		}
+		return writeLedgerEntries(tx, p.LedgerEntries())
+	})
+	if err != nil {
+		return err
+	}
+	p.SetVersion(model.Version)
+	return nil
+}
@@ -0,0 +1,111 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/payout"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+)
+
+// PayoutRunModel is the GORM persistence model for a payout Run.
+type PayoutRunModel struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	Period      string     `gorm:"size:20;not null;index" json:"period"` // Format: YYYY-MM
+	Status      string     `gorm:"size:20;not null;default:'committed'" json:"status"`
+	Minimum     float64    `gorm:"type:decimal(10,2);not null;default:0" json:"minimum"`
+	Currency    string     `gorm:"size:3;not null;default:'MYR'" json:"currency"`
+	CreatedBy   string     `gorm:"size:100" json:"created_by,omitempty"`
+	CancelledBy string     `gorm:"size:100" json:"cancelled_by,omitempty"`
+	CancelledAt *time.Time `json:"cancelled_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+
+	// Relations
+	Lines []PayoutRunLineModel `gorm:"foreignKey:RunID" json:"lines,omitempty"`
+}
+
+// TableName specifies the table name.
+func (PayoutRunModel) TableName() string {
+	return "payout_runs"
+}
+
+// PayoutRunLineModel is the GORM persistence model for an agent's outcome
+// in a payout run.
+type PayoutRunLineModel struct {
+	ID          uint    `gorm:"primaryKey" json:"id"`
+	RunID       uint    `gorm:"not null;index" json:"run_id"`
+	AgentID     uint    `gorm:"not null;index" json:"agent_id"`
+	Outcome     string  `gorm:"size:20;not null" json:"outcome"`
+	Amount      float64 `gorm:"type:decimal(10,2);not null" json:"amount"`
+	Deductions  float64 `gorm:"type:decimal(10,2);not null;default:0" json:"deductions"`
+	Currency    string  `gorm:"size:3;not null;default:'MYR'" json:"currency"`
+	Commissions int     `gorm:"not null;default:0" json:"commissions"`
+	Reason      string  `gorm:"type:text" json:"reason,omitempty"`
+	PayoutID    *uint   `gorm:"index" json:"payout_id,omitempty"`
+}
+
+// TableName specifies the table name.
+func (PayoutRunLineModel) TableName() string {
+	return "payout_run_lines"
+}
+
+// toDomain converts the persistence model to the Run aggregate.
+func (m *PayoutRunModel) toDomain() *payout.Run {
+	lines := make([]payout.RunLine, len(m.Lines))
+	for i, line := range m.Lines {
+		lines[i] = payout.ReconstituteRunLine(
+			line.AgentID,
+			line.Outcome,
+			shared.MoneyFromFloat(line.Amount, line.Currency),
+			shared.MoneyFromFloat(line.Deductions, line.Currency),
+			line.Commissions,
+			line.Reason,
+			line.PayoutID,
+		)
+	}
+
+	return payout.ReconstituteRun(payout.RunParams{
+		ID:          m.ID,
+		Period:      m.Period,
+		Minimum:     shared.MoneyFromFloat(m.Minimum, m.Currency),
+		CreatedBy:   m.CreatedBy,
+		Status:      m.Status,
+		Lines:       lines,
+		CancelledBy: m.CancelledBy,
+		CancelledAt: m.CancelledAt,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	})
+}
+
+// newPayoutRunModel converts the Run aggregate to its persistence model,
+// without its lines.
+func newPayoutRunModel(r *payout.Run) *PayoutRunModel {
+	return &PayoutRunModel{
+		ID:          r.ID(),
+		Period:      r.Period(),
+		Status:      r.Status().String(),
+		Minimum:     r.Minimum().Float64(),
+		Currency:    r.Minimum().Currency(),
+		CreatedBy:   r.CreatedBy(),
+		CancelledBy: r.CancelledBy(),
+		CancelledAt: r.CancelledAt(),
+		CreatedAt:   r.CreatedAt(),
+		UpdatedAt:   r.UpdatedAt(),
+	}
+}
+
+// newPayoutRunLineModel converts a run line to its persistence model.
+func newPayoutRunLineModel(runID uint, line payout.RunLine) *PayoutRunLineModel {
+	return &PayoutRunLineModel{
+		RunID:       runID,
+		AgentID:     line.AgentID(),
+		Outcome:     line.Outcome(),
+		Amount:      line.Amount().Float64(),
+		Deductions:  line.Deductions().Float64(),
+		Currency:    line.Amount().Currency(),
+		Commissions: line.Commissions(),
+		Reason:      line.Reason(),
+		PayoutID:    line.PayoutID(),
+	}
+}
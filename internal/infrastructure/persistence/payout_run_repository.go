@@ -0,0 +1,100 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/payout"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"gorm.io/gorm"
+)
+
+// payoutRunRepository implements repository.PayoutRunRepository
+type payoutRunRepository struct {
+	db *gorm.DB
+}
+
+// NewPayoutRunRepository creates a new payout run repository
+func NewPayoutRunRepository(db *gorm.DB) repository.PayoutRunRepository {
+	return &payoutRunRepository{db: db}
+}
+
+// withLines preloads a run's lines in the order they were added
+func withLines(query *gorm.DB) *gorm.DB {
+	return query.Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("id ASC")
+	})
+}
+
+// GetByID retrieves a payout run and its lines by ID
+func (r *payoutRunRepository) GetByID(ctx context.Context, id uint) (*payout.Run, error) {
+	var model PayoutRunModel
+	if err := withLines(conn(ctx, r.db)).First(&model, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, payout.ErrRunNotFound
+		}
+		return nil, err
+	}
+	return model.toDomain(), nil
+}
+
+// List retrieves the payout runs matching the filter, newest first
+func (r *payoutRunRepository) List(ctx context.Context, filter repository.PayoutRunFilter) ([]*payout.Run, int64, error) {
+	query := conn(ctx, r.db).Model(&PayoutRunModel{})
+	if filter.Period != "" {
+		query = query.Where("period = ?", filter.Period)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var models []PayoutRunModel
+	if err := paginate(withLines(query), filter.Page, filter.Limit).Order("created_at DESC, id DESC").Find(&models).Error; err != nil {
+		return nil, 0, err
+	}
+
+	runs := make([]*payout.Run, len(models))
+	for i := range models {
+		runs[i] = models[i].toDomain()
+	}
+	return runs, total, nil
+}
+
+// Create saves a committed run, creating its payouts and their ledger
+// entries, and assigns its ID
+func (r *payoutRunRepository) Create(ctx context.Context, run *payout.Run) error {
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		for _, p := range run.Payouts() {
+			if err := createPayout(tx, p); err != nil {
+				return err
+			}
+		}
+
+		model := newPayoutRunModel(run)
+		if err := tx.Create(model).Error; err != nil {
+			return err
+		}
+		run.SetID(model.ID)
+
+		for _, line := range run.Lines() {
+			if err := tx.Create(newPayoutRunLineModel(model.ID, line)).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return repository.ErrDuplicate
+	}
+	return err
+}
+
+// Update saves the run's status. Its lines do not change once saved.
+func (r *payoutRunRepository) Update(ctx context.Context, run *payout.Run) error {
+	return conn(ctx, r.db).Omit("Lines").Save(newPayoutRunModel(run)).Error
+}
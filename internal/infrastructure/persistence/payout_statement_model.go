@@ -0,0 +1,116 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/payout"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+)
+
+// PayoutStatementModel is the GORM persistence model for an imported bank
+// Statement.
+type PayoutStatementModel struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	Format     string    `gorm:"size:20;not null" json:"format"`
+	FileName   string    `gorm:"size:255" json:"file_name,omitempty"`
+	Checksum   string    `gorm:"size:64;not null;uniqueIndex" json:"checksum"`
+	ImportedBy string    `gorm:"size:100" json:"imported_by,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+
+	// Relations
+	Lines []PayoutStatementLineModel `gorm:"foreignKey:StatementID" json:"lines,omitempty"`
+}
+
+// TableName specifies the table name.
+func (PayoutStatementModel) TableName() string {
+	return "payout_statements"
+}
+
+// PayoutStatementLineModel is the GORM persistence model for a bank
+// statement line.
+type PayoutStatementLineModel struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	StatementID    uint       `gorm:"not null;index" json:"statement_id"`
+	Reference      string     `gorm:"size:255" json:"reference,omitempty"`
+	TransactionRef string     `gorm:"size:100" json:"transaction_ref,omitempty"`
+	Amount         float64    `gorm:"type:decimal(10,2);not null" json:"amount"`
+	Currency       string     `gorm:"size:3;not null;default:'MYR'" json:"currency"`
+	BookedAt       *time.Time `json:"booked_at,omitempty"`
+	Outcome        string     `gorm:"size:20;not null;index" json:"outcome"`
+	Detail         string     `gorm:"type:text" json:"detail,omitempty"`
+	PayoutID       *uint      `gorm:"index" json:"payout_id,omitempty"`
+	ReviewedBy     string     `gorm:"size:100" json:"reviewed_by,omitempty"`
+	ReviewedAt     *time.Time `json:"reviewed_at,omitempty"`
+}
+
+// TableName specifies the table name.
+func (PayoutStatementLineModel) TableName() string {
+	return "payout_statement_lines"
+}
+
+// toDomain converts the persistence model to the Statement aggregate.
+func (m *PayoutStatementModel) toDomain() *payout.Statement {
+	lines := make([]*payout.StatementLine, len(m.Lines))
+	for i := range m.Lines {
+		lines[i] = m.Lines[i].toDomain()
+	}
+
+	return payout.ReconstituteStatement(payout.StatementParams{
+		ID:         m.ID,
+		Format:     m.Format,
+		FileName:   m.FileName,
+		Checksum:   m.Checksum,
+		ImportedBy: m.ImportedBy,
+		Lines:      lines,
+		CreatedAt:  m.CreatedAt,
+	})
+}
+
+// toDomain converts the persistence model to a statement line.
+func (m *PayoutStatementLineModel) toDomain() *payout.StatementLine {
+	return payout.ReconstituteStatementLine(payout.StatementLineParams{
+		ID:             m.ID,
+		StatementID:    m.StatementID,
+		Reference:      m.Reference,
+		Amount:         shared.MoneyFromFloat(m.Amount, m.Currency),
+		BookedAt:       m.BookedAt,
+		TransactionRef: m.TransactionRef,
+		Outcome:        m.Outcome,
+		Detail:         m.Detail,
+		PayoutID:       m.PayoutID,
+		ReviewedBy:     m.ReviewedBy,
+		ReviewedAt:     m.ReviewedAt,
+	})
+}
+
+// newPayoutStatementModel converts the Statement aggregate to its
+// persistence model, without its lines.
+func newPayoutStatementModel(s *payout.Statement) *PayoutStatementModel {
+	return &PayoutStatementModel{
+		ID:         s.ID(),
+		Format:     s.Format(),
+		FileName:   s.FileName(),
+		Checksum:   s.Checksum(),
+		ImportedBy: s.ImportedBy(),
+		CreatedAt:  s.CreatedAt(),
+	}
+}
+
+// newPayoutStatementLineModel converts a statement line to its persistence
+// model.
+func newPayoutStatementLineModel(l *payout.StatementLine) *PayoutStatementLineModel {
+	return &PayoutStatementLineModel{
+		ID:             l.ID(),
+		StatementID:    l.StatementID(),
+		Reference:      l.Reference(),
+		TransactionRef: l.TransactionRef(),
+		Amount:         l.Amount().Float64(),
+		Currency:       l.Amount().Currency(),
+		BookedAt:       l.BookedAt(),
+		Outcome:        l.Outcome(),
+		Detail:         l.Detail(),
+		PayoutID:       l.PayoutID(),
+		ReviewedBy:     l.ReviewedBy(),
+		ReviewedAt:     l.ReviewedAt(),
+	}
+}
@@ -0,0 +1,126 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/payout"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"gorm.io/gorm"
+)
+
+// payoutStatementRepository implements repository.PayoutStatementRepository
+type payoutStatementRepository struct {
+	db *gorm.DB
+}
+
+// NewPayoutStatementRepository creates a new bank statement repository
+func NewPayoutStatementRepository(db *gorm.DB) repository.PayoutStatementRepository {
+	return &payoutStatementRepository{db: db}
+}
+
+// GetByID retrieves a statement and its lines by ID
+func (r *payoutStatementRepository) GetByID(ctx context.Context, id uint) (*payout.Statement, error) {
+	return r.first(withLines(conn(ctx, r.db)).Where("id = ?", id))
+}
+
+// GetByChecksum retrieves the statement imported from a file
+func (r *payoutStatementRepository) GetByChecksum(ctx context.Context, checksum string) (*payout.Statement, error) {
+	return r.first(withLines(conn(ctx, r.db)).Where("checksum = ?", checksum))
+}
+
+func (r *payoutStatementRepository) first(query *gorm.DB) (*payout.Statement, error) {
+	var model PayoutStatementModel
+	if err := query.First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, payout.ErrStatementNotFound
+		}
+		return nil, err
+	}
+	return model.toDomain(), nil
+}
+
+// List retrieves statements with their lines, newest first
+func (r *payoutStatementRepository) List(ctx context.Context, page, limit int) ([]*payout.Statement, int64, error) {
+	query := conn(ctx, r.db).Model(&PayoutStatementModel{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var models []PayoutStatementModel
+	if err := paginate(withLines(query), page, limit).Order("created_at DESC, id DESC").Find(&models).Error; err != nil {
+		return nil, 0, err
+	}
+
+	statements := make([]*payout.Statement, len(models))
+	for i := range models {
+		statements[i] = models[i].toDomain()
+	}
+	return statements, total, nil
+}
+
+// GetLine retrieves a statement line by ID
+func (r *payoutStatementRepository) GetLine(ctx context.Context, id uint) (*payout.StatementLine, error) {
+	var model PayoutStatementLineModel
+	if err := conn(ctx, r.db).First(&model, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, payout.ErrStatementLineNotFound
+		}
+		return nil, err
+	}
+	return model.toDomain(), nil
+}
+
+// ListForReview lists unmatched lines, oldest first
+func (r *payoutStatementRepository) ListForReview(ctx context.Context, page, limit int) ([]*payout.StatementLine, int64, error) {
+	query := conn(ctx, r.db).Model(&PayoutStatementLineModel{}).
+		Where("outcome = ?", payout.StatementLineUnmatched)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var models []PayoutStatementLineModel
+	if err := paginate(query, page, limit).Order("id ASC").Find(&models).Error; err != nil {
+		return nil, 0, err
+	}
+
+	lines := make([]*payout.StatementLine, len(models))
+	for i := range models {
+		lines[i] = models[i].toDomain()
+	}
+	return lines, total, nil
+}
+
+// Create saves a statement with its lines in one transaction and assigns
+// their IDs
+func (r *payoutStatementRepository) Create(ctx context.Context, statement *payout.Statement) error {
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		model := newPayoutStatementModel(statement)
+		if err := tx.Create(model).Error; err != nil {
+			return err
+		}
+		statement.SetID(model.ID)
+
+		for _, line := range statement.Lines() {
+			lineModel := newPayoutStatementLineModel(line)
+			if err := tx.Create(lineModel).Error; err != nil {
+				return err
+			}
+			line.SetID(lineModel.ID)
+		}
+		return nil
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return repository.ErrDuplicate
+	}
+	return err
+}
+
+// UpdateLine saves a statement line's outcome after review
+func (r *payoutStatementRepository) UpdateLine(ctx context.Context, line *payout.StatementLine) error {
+	return conn(ctx, r.db).Save(newPayoutStatementLineModel(line)).Error
+}
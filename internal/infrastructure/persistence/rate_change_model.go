@@ -0,0 +1,46 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/commission"
+)
+
+// RateChangeModel is the GORM persistence model for RateChange.
+type RateChangeModel struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	Scope         string    `gorm:"size:20;not null;index:idx_rate_changes_subject" json:"scope"`
+	SubjectID     uint      `gorm:"not null;index:idx_rate_changes_subject" json:"subject_id"`
+	Rate          float64   `gorm:"type:decimal(5,2);not null" json:"rate"`
+	EffectiveFrom time.Time `gorm:"not null;index:idx_rate_changes_subject" json:"effective_from"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name.
+func (RateChangeModel) TableName() string {
+	return "commission_rate_changes"
+}
+
+// toDomain converts the persistence model to the RateChange entity.
+func (m *RateChangeModel) toDomain() *commission.RateChange {
+	return commission.ReconstituteRateChange(commission.RateChangeParams{
+		ID:            m.ID,
+		Scope:         m.Scope,
+		SubjectID:     m.SubjectID,
+		Rate:          m.Rate,
+		EffectiveFrom: m.EffectiveFrom,
+		CreatedAt:     m.CreatedAt,
+	})
+}
+
+// newRateChangeModel converts the RateChange entity to its persistence model.
+func newRateChangeModel(c *commission.RateChange) *RateChangeModel {
+	return &RateChangeModel{
+		ID:            c.ID(),
+		Scope:         string(c.Scope()),
+		SubjectID:     c.SubjectID(),
+		Rate:          c.Rate(),
+		EffectiveFrom: c.EffectiveFrom(),
+		CreatedAt:     c.CreatedAt(),
+	}
+}
@@ -0,0 +1,93 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/commission"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"gorm.io/gorm"
+)
+
+// rateChangeRepository implements repository.RateChangeRepository
+type rateChangeRepository struct {
+	db *gorm.DB
+}
+
+// NewRateChangeRepository creates a new rate change repository
+func NewRateChangeRepository(db *gorm.DB) repository.RateChangeRepository {
+	return &rateChangeRepository{db: db}
+}
+
+// GetByID retrieves a rate change by ID
+func (r *rateChangeRepository) GetByID(ctx context.Context, id uint) (*commission.RateChange, error) {
+	var model RateChangeModel
+	if err := conn(ctx, r.db).First(&model, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, commission.ErrRateChangeNotFound
+		}
+		return nil, err
+	}
+	return model.toDomain(), nil
+}
+
+// GetBySubject retrieves an agent's or team's rate changes, oldest effective first
+func (r *rateChangeRepository) GetBySubject(ctx context.Context, scope commission.RateScope, subjectID uint) ([]*commission.RateChange, error) {
+	return r.find(conn(ctx, r.db).
+		Where("scope = ? AND subject_id = ?", string(scope), subjectID).
+		Order("effective_from ASC, id ASC"))
+}
+
+// GetRateAt retrieves the rate change in effect for an agent or team at a time
+func (r *rateChangeRepository) GetRateAt(ctx context.Context, scope commission.RateScope, subjectID uint, at time.Time) (*commission.RateChange, error) {
+	var model RateChangeModel
+	err := conn(ctx, r.db).
+		Where("scope = ? AND subject_id = ? AND effective_from <= ?", string(scope), subjectID, at).
+		Order("effective_from DESC, id DESC").
+		First(&model).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, commission.ErrRateChangeNotFound
+		}
+		return nil, err
+	}
+	return model.toDomain(), nil
+}
+
+// GetEffective retrieves the rate change in effect at a time for each agent
+// or team in the scope
+func (r *rateChangeRepository) GetEffective(ctx context.Context, scope commission.RateScope, at time.Time) ([]*commission.RateChange, error) {
+	return r.find(conn(ctx, r.db).
+		Select("DISTINCT ON (subject_id) *").
+		Where("scope = ? AND effective_from <= ?", string(scope), at).
+		Order("subject_id, effective_from DESC, id DESC"))
+}
+
+func (r *rateChangeRepository) find(query *gorm.DB) ([]*commission.RateChange, error) {
+	var models []RateChangeModel
+	if err := query.Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	changes := make([]*commission.RateChange, len(models))
+	for i := range models {
+		changes[i] = models[i].toDomain()
+	}
+	return changes, nil
+}
+
+// Create saves a new rate change and assigns its ID
+func (r *rateChangeRepository) Create(ctx context.Context, c *commission.RateChange) error {
+	model := newRateChangeModel(c)
+	if err := conn(ctx, r.db).Create(model).Error; err != nil {
+		return err
+	}
+	c.SetID(model.ID)
+	return nil
+}
+
+// Delete removes a rate change
+func (r *rateChangeRepository) Delete(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Delete(&RateChangeModel{}, id).Error
+}
@@ -0,0 +1,124 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/tax"
+)
+
+// WithholdingRuleModel is the GORM persistence model for a tax Rule.
+type WithholdingRuleModel struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	Name         string    `gorm:"size:100;not null" json:"name"`
+	Residency    *string   `gorm:"size:20" json:"residency,omitempty"` // NULL matches any residency
+	TaxIDPresent *bool     `json:"tax_id_present,omitempty"`           // NULL matches with or without a tax ID
+	Rate         float64   `gorm:"type:decimal(5,2);not null" json:"rate"`
+	Active       bool      `gorm:"not null;default:true" json:"active"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name.
+func (WithholdingRuleModel) TableName() string {
+	return "tax_withholding_rules"
+}
+
+// toDomain converts the persistence model to the Rule aggregate.
+func (m *WithholdingRuleModel) toDomain() *tax.Rule {
+	residency := ""
+	if m.Residency != nil {
+		residency = *m.Residency
+	}
+	return tax.ReconstituteRule(tax.RuleParams{
+		ID:           m.ID,
+		Name:         m.Name,
+		Residency:    residency,
+		TaxIDPresent: m.TaxIDPresent,
+		Rate:         m.Rate,
+		Active:       m.Active,
+		CreatedAt:    m.CreatedAt,
+		UpdatedAt:    m.UpdatedAt,
+	})
+}
+
+// newWithholdingRuleModel converts the Rule aggregate to its persistence
+// model.
+func newWithholdingRuleModel(r *tax.Rule) *WithholdingRuleModel {
+	var residency *string
+	if r.Residency() != "" {
+		s := r.Residency().String()
+		residency = &s
+	}
+	return &WithholdingRuleModel{
+		ID:           r.ID(),
+		Name:         r.Name(),
+		Residency:    residency,
+		TaxIDPresent: r.TaxIDPresent(),
+		Rate:         r.Rate(),
+		Active:       r.IsActive(),
+		CreatedAt:    r.CreatedAt(),
+		UpdatedAt:    r.UpdatedAt(),
+	}
+}
+
+// TaxProfileModel is the GORM persistence model for a tax Profile. The tax
+// ID is only stored encrypted.
+type TaxProfileModel struct {
+	AgentID        uint      `gorm:"primaryKey;autoIncrement:false" json:"agent_id"`
+	TaxIDEncrypted string    `gorm:"type:text" json:"-"`
+	Residency      string    `gorm:"size:20;not null" json:"residency"`
+	RateOverride   *float64  `gorm:"type:decimal(5,2)" json:"rate_override,omitempty"`
+	UpdatedBy      string    `gorm:"size:255" json:"updated_by,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name.
+func (TaxProfileModel) TableName() string {
+	return "agent_tax_profiles"
+}
+
+// toDomain converts the persistence model to the Profile aggregate,
+// decrypting the tax ID.
+func (m *TaxProfileModel) toDomain(c *FieldCipher) (*tax.Profile, error) {
+	taxID := ""
+	if m.TaxIDEncrypted != "" {
+		decrypted, err := c.Decrypt(m.TaxIDEncrypted)
+		if err != nil {
+			return nil, err
+		}
+		taxID = decrypted
+	}
+
+	return tax.ReconstituteProfile(tax.ProfileParams{
+		AgentID:      m.AgentID,
+		TaxID:        taxID,
+		Residency:    m.Residency,
+		RateOverride: m.RateOverride,
+		UpdatedBy:    m.UpdatedBy,
+		CreatedAt:    m.CreatedAt,
+		UpdatedAt:    m.UpdatedAt,
+	}), nil
+}
+
+// newTaxProfileModel converts the Profile aggregate to its persistence
+// model, encrypting the tax ID.
+func newTaxProfileModel(p *tax.Profile, c *FieldCipher) (*TaxProfileModel, error) {
+	encrypted := ""
+	if p.HasTaxID() {
+		var err error
+		if encrypted, err = c.Encrypt(p.TaxID()); err != nil {
+			return nil, err
+		}
+	}
+
+	return &TaxProfileModel{
+		AgentID:        p.AgentID(),
+		TaxIDEncrypted: encrypted,
+		Residency:      p.Residency().String(),
+		RateOverride:   p.RateOverride(),
+		UpdatedBy:      p.UpdatedBy(),
+		CreatedAt:      p.CreatedAt(),
+		UpdatedAt:      p.UpdatedAt(),
+	}, nil
+}
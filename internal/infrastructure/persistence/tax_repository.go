@@ -0,0 +1,106 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/tax"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"gorm.io/gorm"
+)
+
+// withholdingRuleRepository implements repository.WithholdingRuleRepository
+type withholdingRuleRepository struct {
+	db *gorm.DB
+}
+
+// NewWithholdingRuleRepository creates a new withholding rule repository
+func NewWithholdingRuleRepository(db *gorm.DB) repository.WithholdingRuleRepository {
+	return &withholdingRuleRepository{db: db}
+}
+
+// GetByID retrieves a withholding rule by ID
+func (r *withholdingRuleRepository) GetByID(ctx context.Context, id uint) (*tax.Rule, error) {
+	var model WithholdingRuleModel
+	if err := conn(ctx, r.db).First(&model, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, tax.ErrRuleNotFound
+		}
+		return nil, err
+	}
+	return model.toDomain(), nil
+}
+
+// List lists every withholding rule, oldest first
+func (r *withholdingRuleRepository) List(ctx context.Context) ([]*tax.Rule, error) {
+	var models []WithholdingRuleModel
+	if err := conn(ctx, r.db).Order("id").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	rules := make([]*tax.Rule, len(models))
+	for i := range models {
+		rules[i] = models[i].toDomain()
+	}
+	return rules, nil
+}
+
+// Create saves a new withholding rule and assigns its ID
+func (r *withholdingRuleRepository) Create(ctx context.Context, rule *tax.Rule) error {
+	model := newWithholdingRuleModel(rule)
+	if err := conn(ctx, r.db).Create(model).Error; err != nil {
+		return err
+	}
+	rule.SetID(model.ID)
+	return nil
+}
+
+// Update saves all withholding rule fields
+func (r *withholdingRuleRepository) Update(ctx context.Context, rule *tax.Rule) error {
+	return conn(ctx, r.db).Save(newWithholdingRuleModel(rule)).Error
+}
+
+// Delete removes a withholding rule
+func (r *withholdingRuleRepository) Delete(ctx context.Context, id uint) error {
+	result := conn(ctx, r.db).Delete(&WithholdingRuleModel{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return tax.ErrRuleNotFound
+	}
+	return nil
+}
+
+// taxProfileRepository implements repository.TaxProfileRepository
+type taxProfileRepository struct {
+	db     *gorm.DB
+	cipher *FieldCipher
+}
+
+// NewTaxProfileRepository creates a new tax profile repository. Tax IDs are
+// encrypted with cipher before they are written.
+func NewTaxProfileRepository(db *gorm.DB, cipher *FieldCipher) repository.TaxProfileRepository {
+	return &taxProfileRepository{db: db, cipher: cipher}
+}
+
+// GetByAgentID retrieves an agent's tax profile
+func (r *taxProfileRepository) GetByAgentID(ctx context.Context, agentID uint) (*tax.Profile, error) {
+	var model TaxProfileModel
+	if err := conn(ctx, r.db).Where("agent_id = ?", agentID).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, tax.ErrProfileNotFound
+		}
+		return nil, err
+	}
+	return model.toDomain(r.cipher)
+}
+
+// Save creates or replaces an agent's tax profile
+func (r *taxProfileRepository) Save(ctx context.Context, profile *tax.Profile) error {
+	model, err := newTaxProfileModel(profile, r.cipher)
+	if err != nil {
+		return err
+	}
+	return conn(ctx, r.db).Save(model).Error
+}
@@ -2,6 +2,8 @@ package persistence
 
 import (
 	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/team"
 )
 
 // TeamModel is the GORM persistence model for Team.
@@ -26,3 +28,35 @@ type TeamModel struct {
 func (TeamModel) TableName() string {
 	return "teams"
 }
+
+// toDomain converts the persistence model to the Team entity.
+func (m *TeamModel) toDomain() *team.Team {
+	return team.Reconstitute(team.TeamParams{
+		ID:             m.ID,
+		Code:           m.Code,
+		Name:           m.Name,
+		Description:    m.Description,
+		LeaderID:       m.LeaderID,
+		TargetMonthly:  m.TargetMonthly,
+		CommissionRate: m.CommissionRate,
+		IsActive:       m.IsActive,
+		CreatedAt:      m.CreatedAt,
+		UpdatedAt:      m.UpdatedAt,
+	})
+}
+
+// newTeamModel converts the Team entity to its persistence model.
+func newTeamModel(t *team.Team) *TeamModel {
+	return &TeamModel{
+		ID:             t.ID(),
+		Code:           t.Code(),
+		Name:           t.Name(),
+		Description:    t.Description(),
+		LeaderID:       t.LeaderID(),
+		TargetMonthly:  t.TargetMonthly(),
+		CommissionRate: t.CommissionRate(),
+		IsActive:       t.IsActive(),
+		CreatedAt:      t.CreatedAt(),
+		UpdatedAt:      t.UpdatedAt(),
+	}
+}
@@ -0,0 +1,96 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/agent"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/team"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"gorm.io/gorm"
+)
+
+// teamRepository implements repository.TeamRepository
+type teamRepository struct {
+	db *gorm.DB
+}
+
+// NewTeamRepository creates a new team repository
+func NewTeamRepository(db *gorm.DB) repository.TeamRepository {
+	return &teamRepository{db: db}
+}
+
+// GetByID retrieves a team by ID
+func (r *teamRepository) GetByID(ctx context.Context, id uint) (*team.Team, error) {
+	var model TeamModel
+	if err := conn(ctx, r.db).First(&model, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, team.ErrTeamNotFound
+		}
+		return nil, err
+	}
+	return model.toDomain(), nil
+}
+
+// GetByAgentID retrieves the team an agent belongs to
+func (r *teamRepository) GetByAgentID(ctx context.Context, agentID uint) (*team.Team, error) {
+	var agentModel AgentModel
+	if err := conn(ctx, r.db).Select("team_id").First(&agentModel, agentID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, agent.ErrAgentNotFound
+		}
+		return nil, err
+	}
+	if agentModel.TeamID == nil {
+		return nil, team.ErrTeamNotFound
+	}
+	return r.GetByID(ctx, *agentModel.TeamID)
+}
+
+// GetMembers retrieves all agents in a team
+func (r *teamRepository) GetMembers(ctx context.Context, teamID uint) ([]*agent.Agent, error) {
+	var models []AgentModel
+	if err := withTotalEarned(conn(ctx, r.db)).Where("team_id = ?", teamID).Order("name ASC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	members := make([]*agent.Agent, len(models))
+	for i := range models {
+		members[i] = models[i].toDomain()
+	}
+	return members, nil
+}
+
+// Create creates a new team and assigns its ID
+func (r *teamRepository) Create(ctx context.Context, t *team.Team) error {
+	model := newTeamModel(t)
+	if err := conn(ctx, r.db).Create(model).Error; err != nil {
+		return err
+	}
+	t.SetID(model.ID)
+	return nil
+}
+
+// Update saves all team fields
+func (r *teamRepository) Update(ctx context.Context, t *team.Team) error {
+	return conn(ctx, r.db).Save(newTeamModel(t)).Error
+}
+
+// AddMember assigns an agent to a team
+func (r *teamRepository) AddMember(ctx context.Context, teamID, agentID uint) error {
+	result := conn(ctx, r.db).Model(&AgentModel{}).Where("id = ?", agentID).Update("team_id", teamID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return agent.ErrAgentNotFound
+	}
+	return nil
+}
+
+// RemoveMember removes an agent from a team
+func (r *teamRepository) RemoveMember(ctx context.Context, teamID, agentID uint) error {
+	return conn(ctx, r.db).Model(&AgentModel{}).
+		Where("id = ? AND team_id = ?", agentID, teamID).
+		Update("team_id", nil).Error
+}
@@ -0,0 +1,73 @@
+package persistence
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/agent"
+)
+
+// TierEvaluationModel is the GORM persistence model for TierEvaluation.
+type TierEvaluationModel struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	AgentID         uint      `gorm:"not null;index" json:"agent_id"`
+	PreviousTier    string    `gorm:"size:20;not null" json:"previous_tier"`
+	NewTier         string    `gorm:"size:20;not null" json:"new_tier"`
+	Outcome         string    `gorm:"size:20;not null" json:"outcome"`
+	Sales           float64   `gorm:"type:decimal(12,2);not null;default:0" json:"sales"`
+	Orders          int64     `gorm:"not null;default:0" json:"orders"`
+	ActiveCustomers int64     `gorm:"not null;default:0" json:"active_customers"`
+	PeriodFrom      time.Time `json:"period_from"`
+	PeriodTo        time.Time `json:"period_to"`
+	Reasons         string    `gorm:"type:text" json:"reasons"` // JSON array of reasons
+	EvaluatedAt     time.Time `gorm:"index" json:"evaluated_at"`
+}
+
+// TableName specifies the table name.
+func (TierEvaluationModel) TableName() string {
+	return "agent_tier_evaluations"
+}
+
+// toDomain converts the persistence model to the TierEvaluation entity.
+func (m *TierEvaluationModel) toDomain() *agent.TierEvaluation {
+	var reasons []string
+	if m.Reasons != "" {
+		_ = json.Unmarshal([]byte(m.Reasons), &reasons)
+	}
+
+	return agent.ReconstituteTierEvaluation(agent.TierEvaluationParams{
+		ID:           m.ID,
+		AgentID:      m.AgentID,
+		PreviousTier: m.PreviousTier,
+		NewTier:      m.NewTier,
+		Metrics: agent.TierMetrics{
+			Sales:           m.Sales,
+			Orders:          m.Orders,
+			ActiveCustomers: m.ActiveCustomers,
+		},
+		PeriodFrom:  m.PeriodFrom,
+		PeriodTo:    m.PeriodTo,
+		Reasons:     reasons,
+		EvaluatedAt: m.EvaluatedAt,
+	})
+}
+
+// newTierEvaluationModel converts the TierEvaluation entity to its persistence model.
+func newTierEvaluationModel(e *agent.TierEvaluation) *TierEvaluationModel {
+	reasons, _ := json.Marshal(e.Reasons())
+	metrics := e.Metrics()
+	return &TierEvaluationModel{
+		ID:              e.ID(),
+		AgentID:         e.AgentID(),
+		PreviousTier:    e.PreviousTier().String(),
+		NewTier:         e.NewTier().String(),
+		Outcome:         e.Outcome(),
+		Sales:           metrics.Sales,
+		Orders:          metrics.Orders,
+		ActiveCustomers: metrics.ActiveCustomers,
+		PeriodFrom:      e.PeriodFrom(),
+		PeriodTo:        e.PeriodTo(),
+		Reasons:         string(reasons),
+		EvaluatedAt:     e.EvaluatedAt(),
+	}
+}
@@ -0,0 +1,50 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/Ecom-micro-template/service-agent/internal/domain/agent"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"gorm.io/gorm"
+)
+
+// tierEvaluationRepository implements repository.TierEvaluationRepository
+type tierEvaluationRepository struct {
+	db *gorm.DB
+}
+
+// NewTierEvaluationRepository creates a new tier evaluation repository
+func NewTierEvaluationRepository(db *gorm.DB) repository.TierEvaluationRepository {
+	return &tierEvaluationRepository{db: db}
+}
+
+// GetByAgentID retrieves an agent's tier evaluations, newest first
+func (r *tierEvaluationRepository) GetByAgentID(ctx context.Context, agentID uint, page, limit int) ([]*agent.TierEvaluation, int64, error) {
+	query := conn(ctx, r.db).Model(&TierEvaluationModel{}).Where("agent_id = ?", agentID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var models []TierEvaluationModel
+	if err := paginate(query, page, limit).Order("evaluated_at DESC, id DESC").Find(&models).Error; err != nil {
+		return nil, 0, err
+	}
+
+	evaluations := make([]*agent.TierEvaluation, len(models))
+	for i := range models {
+		evaluations[i] = models[i].toDomain()
+	}
+	return evaluations, total, nil
+}
+
+// Create records a tier evaluation and assigns its ID
+func (r *tierEvaluationRepository) Create(ctx context.Context, e *agent.TierEvaluation) error {
+	model := newTierEvaluationModel(e)
+	if err := conn(ctx, r.db).Create(model).Error; err != nil {
+		return err
+	}
+	e.SetID(model.ID)
+	return nil
+}
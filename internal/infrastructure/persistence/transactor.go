@@ -0,0 +1,40 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"gorm.io/gorm"
+)
+
+// txKey is the context key of the transaction a context is in
+type txKey struct{}
+
+// transactor implements repository.Transactor
+type transactor struct {
+	db *gorm.DB
+}
+
+// NewTransactor creates a new transactor. The repositories built on the
+// same database run their queries in the transaction a context carries.
+func NewTransactor(db *gorm.DB) repository.Transactor {
+	return &transactor{db: db}
+}
+
+// Transaction runs fn in a transaction, or within the one ctx is already in
+func (t *transactor) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+	return t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// conn returns the transaction ctx is in, or db when it is in none
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
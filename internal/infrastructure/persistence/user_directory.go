@@ -0,0 +1,42 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"gorm.io/gorm"
+)
+
+// userDirectory implements repository.UserDirectory on the auth schema
+type userDirectory struct {
+	db *gorm.DB
+}
+
+// NewUserDirectory creates a new auth user directory
+func NewUserDirectory(db *gorm.DB) repository.UserDirectory {
+	return &userDirectory{db: db}
+}
+
+// GetUserIDByEmail looks up the auth user UUID for an email
+func (r *userDirectory) GetUserIDByEmail(ctx context.Context, email string) (string, error) {
+	var userID string
+	if err := conn(ctx, r.db).Table("auth.users").Where("email = ?", email).Select("id").Scan(&userID).Error; err != nil {
+		return "", err
+	}
+	if userID == "" {
+		return "", repository.ErrNotFound
+	}
+	return userID, nil
+}
+
+// SetRole updates the role of an auth user
+func (r *userDirectory) SetRole(ctx context.Context, email, role string) error {
+	result := conn(ctx, r.db).Exec("UPDATE auth.users SET role = ? WHERE email = ?", role, email)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
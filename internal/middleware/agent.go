@@ -3,14 +3,13 @@ package middleware
 import (
 	"net/http"
 
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
 	"github.com/gin-gonic/gin"
-	"github.com/Ecom-micro-template/service-agent/internal/database"
-	"github.com/Ecom-micro-template/service-agent/internal/domain"
 )
 
 // RequireAgent middleware checks if the authenticated user is an agent
 // It extracts the user_id from JWT and verifies agent status
-func RequireAgent() gin.HandlerFunc {
+func RequireAgent(agents repository.AgentReader) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get user_id from JWT (set by auth middleware)
 		userID, exists := c.Get("user_id")
@@ -29,26 +28,27 @@ func RequireAgent() gin.HandlerFunc {
 		}
 
 		// Get agent record using user_id
-		var agent domain.Agent
 		// Assuming there's a user_id field in agents table or email matching
 		// For now, we'll use the ID directly since the existing model uses auto-increment ID
 		agentID := userID.(uint)
 
-		if err := database.GetDB().First(&agent, agentID).Error; err != nil {
+		agent, err := agents.GetByID(c.Request.Context(), agentID)
+		if err != nil {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Agent profile not found"})
 			c.Abort()
 			return
 		}
 
 		// Check if agent is active
-		if agent.Status != "active" {
+		if !agent.IsActive() {
 			c.JSON(http.StatusForbidden, gin.H{"error": "Agent account is not active"})
 			c.Abort()
 			return
 		}
 
 		// Set agent_id and agent details in context for handlers
-		c.Set("agent_id", agent.ID)
+		c.Set("agent_id", agent.ID())
+		c.Set("agent_email", agent.Email())
 		c.Set("agent", agent)
 
 		c.Next()
@@ -57,7 +57,7 @@ func RequireAgent() gin.HandlerFunc {
 
 // OptionalAgent is similar to RequireAgent but doesn't abort if agent not found
 // Useful for endpoints that can work with or without agent context
-func OptionalAgent() gin.HandlerFunc {
+func OptionalAgent(agents repository.AgentReader) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userID, exists := c.Get("user_id")
 		if !exists {
@@ -71,12 +71,12 @@ func OptionalAgent() gin.HandlerFunc {
 			return
 		}
 
-		var agent domain.Agent
 		agentID := userID.(uint)
 
-		if err := database.GetDB().First(&agent, agentID).Error; err == nil {
-			if agent.Status == "active" {
-				c.Set("agent_id", agent.ID)
+		if agent, err := agents.GetByID(c.Request.Context(), agentID); err == nil {
+			if agent.IsActive() {
+				c.Set("agent_id", agent.ID())
+				c.Set("agent_email", agent.Email())
 				c.Set("agent", agent)
 			}
 		}
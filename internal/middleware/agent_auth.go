@@ -5,15 +5,14 @@ import (
 	"os"
 	"strings"
 
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
-	"github.com/Ecom-micro-template/service-agent/internal/database"
-	"github.com/Ecom-micro-template/service-agent/internal/domain"
 	"github.com/rs/zerolog/log"
 )
 
 // AgentAuthMiddleware verifies JWT and sets agent_id in context
-func AgentAuthMiddleware() gin.HandlerFunc {
+func AgentAuthMiddleware(agents repository.AgentReader) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get Authorization header
 		authHeader := c.GetHeader("Authorization")
@@ -85,8 +84,8 @@ func AgentAuthMiddleware() gin.HandlerFunc {
 		}
 
 		// Find agent by email
-		var agent domain.Agent
-		if err := database.GetDB().Where("email = ?", email).First(&agent).Error; err != nil {
+		agent, err := agents.GetByEmail(c.Request.Context(), email)
+		if err != nil {
 			log.Error().Str("email", email).Msg("Agent not found for email")
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Agent not found"})
 			c.Abort()
@@ -94,9 +93,9 @@ func AgentAuthMiddleware() gin.HandlerFunc {
 		}
 
 		// Set agent_id in context
-		c.Set("agent_id", agent.ID)
+		c.Set("agent_id", agent.ID())
 		c.Set("agent_email", email)
-		c.Set("agent_name", agent.Name)
+		c.Set("agent_name", agent.Name())
 
 		c.Next()
 	}
@@ -2,9 +2,19 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"time"
 
-	"github.com/google/uuid"
 	"github.com/Ecom-micro-template/service-agent/internal/domain"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/agent"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/commission"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/dispute"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/fx"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/ledger"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/payout"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/shared"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/tax"
+	"github.com/Ecom-micro-template/service-agent/internal/domain/team"
 )
 
 // =============================================================================
@@ -21,8 +31,41 @@ import (
 // - Clear separation of read/write operations
 // - Supports CQRS patterns
 //
+// Conventions:
+// - Aggregates are returned as domain types, never persistence models
+// - Missing aggregates are reported with the aggregate's ErrXxxNotFound
+// - A Limit of zero or less returns every matching row
+//
 // =============================================================================
 
+// ErrNotFound is returned for missing records that have no aggregate of
+// their own (customers, orders, users).
+var ErrNotFound = errors.New("record not found")
+
+// ErrDuplicate is returned when a write would break a uniqueness rule,
+// such as a second commission for the same agent and order.
+var ErrDuplicate = errors.New("record already exists")
+
+// ErrConflict is returned when an update was made from a stale copy of an
+// aggregate: someone else changed it since it was loaded. Reload it and
+// try again.
+var ErrConflict = errors.New("record was changed by someone else")
+
+// Transactor runs work in a single database transaction. Repository calls
+// made with the context passed to fn take part in it, and are all rolled
+// back if fn returns an error. Transactions started within fn join the
+// outer one.
+type Transactor interface {
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Period bounds a query by creation time. A zero From or To leaves that
+// side of the range open.
+type Period struct {
+	From time.Time
+	To   time.Time
+}
+
 // =============================================================================
 // AGENT REPOSITORY INTERFACES
 // =============================================================================
@@ -30,21 +73,21 @@ import (
 // AgentReader provides read-only access to agents
 // Use this for handlers that only need to query agents
 type AgentReader interface {
-	GetByID(ctx context.Context, id uuid.UUID) (*domain.Agent, error)
-	GetByUserID(ctx context.Context, userID uuid.UUID) (*domain.Agent, error)
-	GetByCode(ctx context.Context, code string) (*domain.Agent, error)
-	List(ctx context.Context, filter AgentFilter) ([]domain.Agent, int64, error)
-	GetStats(ctx context.Context, agentID uuid.UUID) (*AgentStats, error)
+	GetByID(ctx context.Context, id uint) (*agent.Agent, error)
+	GetByEmail(ctx context.Context, email string) (*agent.Agent, error)
+	GetByCode(ctx context.Context, code string) (*agent.Agent, error)
+	List(ctx context.Context, filter AgentFilter) ([]*agent.Agent, int64, error)
+	GetStats(ctx context.Context, agentID uint) (*AgentStats, error)
 }
 
 // AgentWriter provides write access to agents
 // Use this for handlers that create or modify agents
 type AgentWriter interface {
-	Create(ctx context.Context, agent *domain.Agent) error
-	Update(ctx context.Context, agent *domain.Agent) error
-	Delete(ctx context.Context, id uuid.UUID) error
-	UpdateStatus(ctx context.Context, id uuid.UUID, status string) error
-	UpdateTier(ctx context.Context, id uuid.UUID, tier string) error
+	Create(ctx context.Context, agent *agent.Agent) error
+	Update(ctx context.Context, agent *agent.Agent) error
+	Delete(ctx context.Context, id uint) error
+	UpdateStatus(ctx context.Context, id uint, status shared.AgentStatus) error
+	UpdateTier(ctx context.Context, id uint, tier shared.AgentTier) error
 }
 
 // AgentRepository is the composed interface
@@ -57,19 +100,28 @@ type AgentRepository interface {
 type AgentFilter struct {
 	Status   string
 	Tier     string
-	ParentID *uuid.UUID
+	ParentID *uint
 	Search   string
 	Page     int
 	Limit    int
+
+	// IncludeInactive keeps inactive (deleted) agents in the result
+	// when no Status is given.
+	IncludeInactive bool
 }
 
-// AgentStats represents agent statistics
+// AgentStats represents agent statistics. Commission amounts are in the
+// agent's payout currency.
 type AgentStats struct {
-	TotalOrders       int64   `json:"total_orders"`
-	TotalCommission   float64 `json:"total_commission"`
-	PendingCommission float64 `json:"pending_commission"`
-	PaidCommission    float64 `json:"paid_commission"`
-	TotalCustomers    int64   `json:"total_customers"`
+	TotalOrders         int64   `json:"total_orders"`
+	TotalCommissions    int64   `json:"total_commissions"`
+	TotalCommission     float64 `json:"total_commission"`
+	PendingCommissions  int64   `json:"pending_commissions"`
+	PendingCommission   float64 `json:"pending_commission"`
+	PaidCommission      float64 `json:"paid_commission"`
+	ThisMonthCommission float64 `json:"this_month_commission"`
+	TotalPayouts        int64   `json:"total_payouts"`
+	TotalCustomers      int64   `json:"total_customers"`
 }
 
 // =============================================================================
@@ -78,23 +130,31 @@ type AgentStats struct {
 
 // CommissionReader provides read-only access to commissions
 type CommissionReader interface {
-	GetByID(ctx context.Context, id uuid.UUID) (*domain.Commission, error)
-	GetByAgentID(ctx context.Context, agentID uuid.UUID, page, limit int) ([]domain.Commission, int64, error)
-	GetByOrderID(ctx context.Context, orderID uuid.UUID) ([]domain.Commission, error)
-	GetPending(ctx context.Context, page, limit int) ([]domain.Commission, int64, error)
+	GetByID(ctx context.Context, id uint) (*commission.Commission, error)
+	GetByAgentID(ctx context.Context, agentID uint, filter CommissionFilter) ([]*commission.Commission, int64, error)
+	GetByOrderID(ctx context.Context, orderID string) ([]*commission.Commission, error)
+	GetBySourceID(ctx context.Context, sourceCommissionID uint) ([]*commission.Commission, error)
+	GetPending(ctx context.Context, page, limit int) ([]*commission.Commission, int64, error)
+	GetSummary(ctx context.Context, agentID uint, period Period) (*CommissionSummary, error)
+	GetSalesSummary(ctx context.Context, agentID uint, period Period) (*SalesSummary, error)
 }
 
-// CommissionWriter provides write access to commissions
+// CommissionWriter provides write access to commissions. Create and Update
+// write the commission's ledger entries in the same transaction. Update
+// returns ErrConflict if the commission changed since it was loaded.
 type CommissionWriter interface {
-	Create(ctx context.Context, commission *domain.Commission) error
-	Update(ctx context.Context, commission *domain.Commission) error
-	UpdateStatus(ctx context.Context, id uuid.UUID, status string) error
-	Approve(ctx context.Context, id uuid.UUID, approvedBy uuid.UUID) error
+	Create(ctx context.Context, commission *commission.Commission) error
+	Update(ctx context.Context, commission *commission.Commission) error
+	// UpdateStatus moves the commissions that are still in status from to
+	// status to, such as a payout's commissions once it is paid, and
+	// returns how many it moved
+	UpdateStatus(ctx context.Context, ids []uint, from, to shared.CommissionStatus) (int, error)
 }
 
-// CommissionCalculator calculates commission rates
+// CommissionCalculator calculates commission at the rates that were in
+// effect at the given time
 type CommissionCalculator interface {
-	CalculateCommission(ctx context.Context, agentID uuid.UUID, orderAmount float64, categoryID *uuid.UUID) (float64, error)
+	CalculateCommission(ctx context.Context, agentID uint, orderAmount shared.Money, categoryID *string, at time.Time) (shared.Money, error)
 }
 
 // CommissionRepository is the composed interface
@@ -104,21 +164,150 @@ type CommissionRepository interface {
 	CommissionCalculator
 }
 
+// CommissionFilter represents filters for listing an agent's commissions
+type CommissionFilter struct {
+	Status string
+	Type   string
+	Period Period
+	Page   int
+	Limit  int
+}
+
+// CommissionSummary aggregates an agent's commissions by status. Amounts
+// are totalled per payout currency, so an agent whose payout currency
+// changed has totals in each currency they were paid in.
+type CommissionSummary struct {
+	Count        int64                     `json:"count"`
+	PendingCount int64                     `json:"pending_count"`
+	Totals       []domain.CommissionTotals `json:"totals"` // Sorted by currency
+}
+
+// In returns the totals in a currency, which are zero if the agent has no
+// commissions in it
+func (s *CommissionSummary) In(currency string) domain.CommissionTotals {
+	for _, totals := range s.Totals {
+		if totals.Currency == currency {
+			return totals
+		}
+	}
+	return domain.CommissionTotals{Currency: currency}
+}
+
+// SalesSummary aggregates the sales behind an agent's own sale
+// commissions, net of refunds. Cancelled and reversed sales are excluded.
+// Sales are converted to the agent's payout currency.
+type SalesSummary struct {
+	Orders int64   `json:"orders"`
+	Sales  float64 `json:"sales"`
+}
+
+// =============================================================================
+// AUTO-APPROVAL REPOSITORY INTERFACES
+// =============================================================================
+
+// AutoApprovalReader provides read-only access to auto-approval records
+type AutoApprovalReader interface {
+	// GetByCommissionID returns the commission's auto-approval, or
+	// ErrAutoApprovalNotFound if it was not auto-approved
+	GetByCommissionID(ctx context.Context, commissionID uint) (*commission.AutoApproval, error)
+	// List returns auto-approvals, newest first
+	List(ctx context.Context, page, limit int) ([]*commission.AutoApproval, int64, error)
+}
+
+// AutoApprovalWriter provides write access to auto-approval records
+type AutoApprovalWriter interface {
+	Create(ctx context.Context, approval *commission.AutoApproval) error
+}
+
+// AutoApprovalRepository is the composed interface
+type AutoApprovalRepository interface {
+	AutoApprovalReader
+	AutoApprovalWriter
+}
+
+// =============================================================================
+// DISPUTE REPOSITORY INTERFACES
+// =============================================================================
+
+// DisputeReader provides read-only access to commission disputes. Disputes
+// are loaded with their message threads.
+type DisputeReader interface {
+	// GetByID returns dispute.ErrDisputeNotFound when there is none
+	GetByID(ctx context.Context, id uint) (*dispute.Dispute, error)
+	// List returns the disputes matching the filter, newest first
+	List(ctx context.Context, filter DisputeFilter) ([]*dispute.Dispute, int64, error)
+	// HasActive reports whether the agent has an open or under review
+	// dispute on the order
+	HasActive(ctx context.Context, agentID uint, orderID string) (bool, error)
+}
+
+// DisputeFilter narrows a dispute listing. Zero values match everything.
+type DisputeFilter struct {
+	AgentID uint
+	Status  string
+	Page    int
+	Limit   int
+}
+
+// DisputeWriter provides write access to commission disputes. Messages
+// added to the thread since it was loaded are stored with the dispute.
+type DisputeWriter interface {
+	Create(ctx context.Context, dispute *dispute.Dispute) error
+	Update(ctx context.Context, dispute *dispute.Dispute) error
+}
+
+// DisputeRepository is the composed interface
+type DisputeRepository interface {
+	DisputeReader
+	DisputeWriter
+}
+
+// =============================================================================
+// CLAWBACK REPOSITORY INTERFACES
+// =============================================================================
+
+// ClawbackReader provides read-only access to commission clawbacks
+type ClawbackReader interface {
+	GetByCommissionID(ctx context.Context, commissionID uint) ([]*commission.Clawback, error)
+	GetOutstanding(ctx context.Context, agentID uint) ([]*commission.Clawback, error)
+	GetByPayoutID(ctx context.Context, payoutID uint) ([]*commission.Clawback, error)
+}
+
+// ClawbackWriter provides write access to commission clawbacks
+type ClawbackWriter interface {
+	Create(ctx context.Context, clawback *commission.Clawback) error
+	// Update returns ErrConflict if the clawback changed since it was loaded
+	Update(ctx context.Context, clawback *commission.Clawback) error
+}
+
+// ClawbackRepository is the composed interface
+type ClawbackRepository interface {
+	ClawbackReader
+	ClawbackWriter
+}
+
 // =============================================================================
 // CATEGORY COMMISSION REPOSITORY INTERFACES
 // =============================================================================
 
 // CategoryCommissionReader provides read-only access to category commissions
 type CategoryCommissionReader interface {
-	GetByAgentID(ctx context.Context, agentID uuid.UUID) ([]domain.AgentCategoryCommission, error)
-	GetByAgentAndCategory(ctx context.Context, agentID, categoryID uuid.UUID) (*domain.AgentCategoryCommission, error)
+	// GetByAgentID returns the row in effect at the given time for each of
+	// the agent's categories, inactive rows included
+	GetByAgentID(ctx context.Context, agentID uint, at time.Time) ([]domain.AgentCategoryCommission, error)
+	// GetHistory returns every row for the agent, scheduled ones included,
+	// oldest effective first
+	GetHistory(ctx context.Context, agentID uint) ([]domain.AgentCategoryCommission, error)
+	GetByID(ctx context.Context, id uint) (*domain.AgentCategoryCommission, error)
 }
 
 // CategoryCommissionWriter provides write access to category commissions
 type CategoryCommissionWriter interface {
-	Upsert(ctx context.Context, commission *domain.AgentCategoryCommission) error
-	Delete(ctx context.Context, agentID, categoryID uuid.UUID) error
-	BulkUpdate(ctx context.Context, agentID uuid.UUID, commissions []domain.AgentCategoryCommission) error
+	// Schedule replaces the agent's category rates from effectiveFrom on.
+	// Categories left out are deactivated from then, and rows already
+	// scheduled for the same time are replaced.
+	Schedule(ctx context.Context, agentID uint, commissions []domain.AgentCategoryCommission, effectiveFrom time.Time) error
+	Delete(ctx context.Context, id uint) error
 }
 
 // CategoryCommissionRepository is the composed interface
@@ -127,23 +316,84 @@ type CategoryCommissionRepository interface {
 	CategoryCommissionWriter
 }
 
+// =============================================================================
+// RATE CHANGE REPOSITORY INTERFACES
+// =============================================================================
+
+// RateChangeReader provides read-only access to agent and team rate changes
+type RateChangeReader interface {
+	GetByID(ctx context.Context, id uint) (*commission.RateChange, error)
+	// GetBySubject returns an agent's or team's changes, scheduled ones
+	// included, oldest effective first
+	GetBySubject(ctx context.Context, scope commission.RateScope, subjectID uint) ([]*commission.RateChange, error)
+	// GetRateAt returns the change in effect for an agent or team at the
+	// given time, or ErrRateChangeNotFound if none had taken effect
+	GetRateAt(ctx context.Context, scope commission.RateScope, subjectID uint, at time.Time) (*commission.RateChange, error)
+	// GetEffective returns the change in effect at the given time for each
+	// agent or team in the scope that has one
+	GetEffective(ctx context.Context, scope commission.RateScope, at time.Time) ([]*commission.RateChange, error)
+}
+
+// RateChangeWriter provides write access to rate changes
+type RateChangeWriter interface {
+	Create(ctx context.Context, change *commission.RateChange) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// RateChangeRepository is the composed interface
+type RateChangeRepository interface {
+	RateChangeReader
+	RateChangeWriter
+}
+
+// =============================================================================
+// FX RATE REPOSITORY INTERFACES
+// =============================================================================
+
+// FXRateReader provides read-only access to the exchange rate table
+type FXRateReader interface {
+	GetByID(ctx context.Context, id uint) (*fx.Rate, error)
+	// List returns the rates for a currency pair, or every pair when both
+	// currencies are empty, newest effective first
+	List(ctx context.Context, base, quote string) ([]*fx.Rate, error)
+	// GetRateAt returns the rate from base to quote in effect at the given
+	// time, or ErrRateNotFound if none had taken effect
+	GetRateAt(ctx context.Context, base, quote string, at time.Time) (*fx.Rate, error)
+}
+
+// FXRateWriter provides write access to the exchange rate table
+type FXRateWriter interface {
+	Create(ctx context.Context, rate *fx.Rate) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// FXRateRepository is the composed interface
+type FXRateRepository interface {
+	FXRateReader
+	FXRateWriter
+}
+
 // =============================================================================
 // PAYOUT REPOSITORY INTERFACES
 // =============================================================================
 
 // PayoutReader provides read-only access to payouts
 type PayoutReader interface {
-	GetByID(ctx context.Context, id uuid.UUID) (*domain.Payout, error)
-	GetByAgentID(ctx context.Context, agentID uuid.UUID, page, limit int) ([]domain.Payout, int64, error)
-	GetPending(ctx context.Context, page, limit int) ([]domain.Payout, int64, error)
+	GetByID(ctx context.Context, id uint) (*payout.Payout, error)
+	GetByAgentID(ctx context.Context, agentID uint, page, limit int) ([]*payout.Payout, int64, error)
+	GetPending(ctx context.Context, page, limit int) ([]*payout.Payout, int64, error)
+	// GetPaid lists the completed payouts paid within the period, by paid
+	// time rather than creation time, oldest first
+	GetPaid(ctx context.Context, period Period) ([]*payout.Payout, error)
 }
 
-// PayoutWriter provides write access to payouts
+// PayoutWriter provides write access to payouts. Create and Update write
+// the payout's ledger entries in the same transaction. Status changes go
+// through the payout's behavior methods and are saved with Update.
 type PayoutWriter interface {
-	Create(ctx context.Context, payout *domain.Payout) error
-	Update(ctx context.Context, payout *domain.Payout) error
-	UpdateStatus(ctx context.Context, id uuid.UUID, status string) error
-	MarkAsPaid(ctx context.Context, id uuid.UUID, paidBy uuid.UUID, transactionRef string) error
+	Create(ctx context.Context, payout *payout.Payout) error
+	// Update returns ErrConflict if the payout changed since it was loaded
+	Update(ctx context.Context, payout *payout.Payout) error
 }
 
 // PayoutRepository is the composed interface
@@ -152,23 +402,252 @@ type PayoutRepository interface {
 	PayoutWriter
 }
 
+// PayoutRunReader provides read-only access to payout runs
+type PayoutRunReader interface {
+	GetByID(ctx context.Context, id uint) (*payout.Run, error)
+	List(ctx context.Context, filter PayoutRunFilter) ([]*payout.Run, int64, error)
+}
+
+// PayoutRunWriter provides write access to payout runs. Create saves a
+// committed run together with the payouts it creates, and their ledger
+// entries, in one transaction; it returns ErrDuplicate if the period
+// already has a committed run.
+type PayoutRunWriter interface {
+	Create(ctx context.Context, run *payout.Run) error
+	Update(ctx context.Context, run *payout.Run) error
+}
+
+// PayoutRunRepository is the composed interface
+type PayoutRunRepository interface {
+	PayoutRunReader
+	PayoutRunWriter
+}
+
+// PayoutRunFilter represents filters for listing payout runs, newest first
+type PayoutRunFilter struct {
+	Period string
+	Status string
+	Page   int
+	Limit  int
+}
+
+// PayoutExportReader provides read-only access to the payment files
+// exported for payout runs
+type PayoutExportReader interface {
+	GetByID(ctx context.Context, id uint) (*payout.Export, error)
+	// ListByRun lists a run's exports, newest first
+	ListByRun(ctx context.Context, runID uint) ([]*payout.Export, error)
+}
+
+// PayoutExportWriter provides write access to payment file exports
+type PayoutExportWriter interface {
+	Create(ctx context.Context, export *payout.Export) error
+}
+
+// PayoutExportRepository is the composed interface
+type PayoutExportRepository interface {
+	PayoutExportReader
+	PayoutExportWriter
+}
+
+// PayoutStatementReader provides read-only access to imported bank
+// statements and the review queue of lines that matched no payout
+type PayoutStatementReader interface {
+	GetByID(ctx context.Context, id uint) (*payout.Statement, error)
+	GetByChecksum(ctx context.Context, checksum string) (*payout.Statement, error)
+	List(ctx context.Context, page, limit int) ([]*payout.Statement, int64, error)
+	GetLine(ctx context.Context, id uint) (*payout.StatementLine, error)
+	// ListForReview lists unmatched lines, oldest first
+	ListForReview(ctx context.Context, page, limit int) ([]*payout.StatementLine, int64, error)
+}
+
+// PayoutStatementWriter provides write access to bank statements. Create
+// saves a statement with its lines and returns ErrDuplicate if the same
+// file was imported before.
+type PayoutStatementWriter interface {
+	Create(ctx context.Context, statement *payout.Statement) error
+	UpdateLine(ctx context.Context, line *payout.StatementLine) error
+}
+
+// PayoutStatementRepository is the composed interface
+type PayoutStatementRepository interface {
+	PayoutStatementReader
+	PayoutStatementWriter
+}
+
+// PayoutMethodReader provides read-only access to agents' payout methods
+type PayoutMethodReader interface {
+	GetByID(ctx context.Context, id uint) (*payout.Method, error)
+	// ListByAgent lists an agent's methods, oldest first
+	ListByAgent(ctx context.Context, agentID uint) ([]*payout.Method, error)
+}
+
+// PayoutMethodWriter provides write access to payout methods. SetDefault
+// saves a method that was made the default and clears the default flag on
+// the agent's other methods, together.
+type PayoutMethodWriter interface {
+	Create(ctx context.Context, method *payout.Method) error
+	Update(ctx context.Context, method *payout.Method) error
+	SetDefault(ctx context.Context, method *payout.Method) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// PayoutMethodRepository is the composed interface
+type PayoutMethodRepository interface {
+	PayoutMethodReader
+	PayoutMethodWriter
+}
+
+// PayoutHoldReader provides read-only access to the payout holds placed
+// on agents
+type PayoutHoldReader interface {
+	GetByID(ctx context.Context, id uint) (*payout.Hold, error)
+	// ListByAgent lists an agent's holds, newest first
+	ListByAgent(ctx context.Context, agentID uint) ([]*payout.Hold, error)
+	// ListActive lists the holds active at the given time, newest first
+	ListActive(ctx context.Context, at time.Time) ([]*payout.Hold, error)
+}
+
+// PayoutHoldWriter provides write access to payout holds
+type PayoutHoldWriter interface {
+	Create(ctx context.Context, hold *payout.Hold) error
+	Update(ctx context.Context, hold *payout.Hold) error
+}
+
+// PayoutHoldRepository is the composed interface
+type PayoutHoldRepository interface {
+	PayoutHoldReader
+	PayoutHoldWriter
+}
+
+// ReservePolicyReader provides read-only access to agents' reserve
+// policies
+type ReservePolicyReader interface {
+	GetByAgentID(ctx context.Context, agentID uint) (*payout.ReservePolicy, error)
+}
+
+// ReservePolicyWriter provides write access to reserve policies. Save
+// creates the agent's policy or replaces it.
+type ReservePolicyWriter interface {
+	Save(ctx context.Context, policy *payout.ReservePolicy) error
+	Delete(ctx context.Context, agentID uint) error
+}
+
+// ReservePolicyRepository is the composed interface
+type ReservePolicyRepository interface {
+	ReservePolicyReader
+	ReservePolicyWriter
+}
+
+// PayoutReserveReader provides read-only access to the reserves kept back
+// from payouts
+type PayoutReserveReader interface {
+	// ListByAgent lists an agent's reserves, newest first
+	ListByAgent(ctx context.Context, agentID uint) ([]*payout.Reserve, error)
+	// GetDue lists the held reserves due for release at the given time,
+	// oldest first
+	GetDue(ctx context.Context, at time.Time) ([]*payout.Reserve, error)
+	// GetByPayoutID retrieves the reserve kept back from a payout
+	GetByPayoutID(ctx context.Context, payoutID uint) (*payout.Reserve, error)
+	// GetByReleasePayoutID lists the reserves a payout released
+	GetByReleasePayoutID(ctx context.Context, payoutID uint) ([]*payout.Reserve, error)
+}
+
+// PayoutReserveWriter provides write access to reserves
+type PayoutReserveWriter interface {
+	Create(ctx context.Context, reserve *payout.Reserve) error
+	// Update returns ErrConflict if the reserve changed since it was loaded
+	Update(ctx context.Context, reserve *payout.Reserve) error
+}
+
+// PayoutReserveRepository is the composed interface
+type PayoutReserveRepository interface {
+	PayoutReserveReader
+	PayoutReserveWriter
+}
+
+// =============================================================================
+// TAX REPOSITORY INTERFACES
+// =============================================================================
+
+// WithholdingRuleReader provides read-only access to withholding rules
+type WithholdingRuleReader interface {
+	GetByID(ctx context.Context, id uint) (*tax.Rule, error)
+	// List lists every rule, oldest first
+	List(ctx context.Context) ([]*tax.Rule, error)
+}
+
+// WithholdingRuleWriter provides write access to withholding rules
+type WithholdingRuleWriter interface {
+	Create(ctx context.Context, rule *tax.Rule) error
+	Update(ctx context.Context, rule *tax.Rule) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// WithholdingRuleRepository is the composed interface
+type WithholdingRuleRepository interface {
+	WithholdingRuleReader
+	WithholdingRuleWriter
+}
+
+// TaxProfileReader provides read-only access to agents' tax profiles
+type TaxProfileReader interface {
+	GetByAgentID(ctx context.Context, agentID uint) (*tax.Profile, error)
+}
+
+// TaxProfileWriter provides write access to tax profiles. Save creates the
+// agent's profile or replaces it.
+type TaxProfileWriter interface {
+	Save(ctx context.Context, profile *tax.Profile) error
+}
+
+// TaxProfileRepository is the composed interface
+type TaxProfileRepository interface {
+	TaxProfileReader
+	TaxProfileWriter
+}
+
+// =============================================================================
+// LEDGER REPOSITORY INTERFACES
+// =============================================================================
+
+// LedgerReader provides read-only access to agents' earnings ledgers. The
+// ledger is append-only; entries are written by the commission and payout
+// writers along with the transitions they record.
+type LedgerReader interface {
+	GetEntries(ctx context.Context, filter LedgerFilter) ([]*ledger.Entry, int64, error)
+	GetBalances(ctx context.Context, agentID uint) (ledger.Balances, error)
+	// GetUnbalanced returns the IDs of an agent's entries whose lines do
+	// not net to zero, which should never happen
+	GetUnbalanced(ctx context.Context, agentID uint) ([]uint, error)
+}
+
+// LedgerFilter represents filters for listing an agent's ledger entries,
+// newest first
+type LedgerFilter struct {
+	AgentID uint
+	Account string // Only entries with a line on this account
+	Page    int
+	Limit   int
+}
+
 // =============================================================================
 // TEAM REPOSITORY INTERFACES
 // =============================================================================
 
 // TeamReader provides read-only access to teams
 type TeamReader interface {
-	GetByID(ctx context.Context, id uuid.UUID) (*domain.Team, error)
-	GetByAgentID(ctx context.Context, agentID uuid.UUID) (*domain.Team, error)
-	GetMembers(ctx context.Context, teamID uuid.UUID) ([]domain.Agent, error)
+	GetByID(ctx context.Context, id uint) (*team.Team, error)
+	GetByAgentID(ctx context.Context, agentID uint) (*team.Team, error)
+	GetMembers(ctx context.Context, teamID uint) ([]*agent.Agent, error)
 }
 
 // TeamWriter provides write access to teams
 type TeamWriter interface {
-	Create(ctx context.Context, team *domain.Team) error
-	Update(ctx context.Context, team *domain.Team) error
-	AddMember(ctx context.Context, teamID, agentID uuid.UUID) error
-	RemoveMember(ctx context.Context, teamID, agentID uuid.UUID) error
+	Create(ctx context.Context, team *team.Team) error
+	Update(ctx context.Context, team *team.Team) error
+	AddMember(ctx context.Context, teamID, agentID uint) error
+	RemoveMember(ctx context.Context, teamID, agentID uint) error
 }
 
 // TeamRepository is the composed interface
@@ -176,3 +655,71 @@ type TeamRepository interface {
 	TeamReader
 	TeamWriter
 }
+
+// =============================================================================
+// CUSTOMER REPOSITORY INTERFACES
+// =============================================================================
+
+// CustomerReader provides read-only access to an agent's customers
+type CustomerReader interface {
+	GetByID(ctx context.Context, agentID, id uint) (*domain.Customer, error)
+	List(ctx context.Context, agentID uint, search string, page, limit int) ([]domain.Customer, int64, error)
+	CountByAgentID(ctx context.Context, agentID uint) (int64, error)
+	CountActive(ctx context.Context, agentID uint, since time.Time) (int64, error)
+}
+
+// CustomerWriter provides write access to an agent's customers
+type CustomerWriter interface {
+	Create(ctx context.Context, customer *domain.Customer) error
+	Update(ctx context.Context, customer *domain.Customer) error
+}
+
+// CustomerRepository is the composed interface
+type CustomerRepository interface {
+	CustomerReader
+	CustomerWriter
+}
+
+// =============================================================================
+// TIER EVALUATION REPOSITORY INTERFACES
+// =============================================================================
+
+// TierEvaluationReader provides read-only access to tier evaluations
+type TierEvaluationReader interface {
+	GetByAgentID(ctx context.Context, agentID uint, page, limit int) ([]*agent.TierEvaluation, int64, error)
+}
+
+// TierEvaluationWriter provides write access to tier evaluations
+type TierEvaluationWriter interface {
+	Create(ctx context.Context, evaluation *agent.TierEvaluation) error
+}
+
+// TierEvaluationRepository is the composed interface
+type TierEvaluationRepository interface {
+	TierEvaluationReader
+	TierEvaluationWriter
+}
+
+// =============================================================================
+// ORDER AND USER INTERFACES
+// =============================================================================
+
+// OrderReader provides read-only access to orders owned by service-order.
+// Orders reference agents by their auth user UUID, not the agent ID.
+type OrderReader interface {
+	GetByID(ctx context.Context, agentUserID, orderID string) (*domain.Order, error)
+	List(ctx context.Context, agentUserID, status string, page, limit int) ([]domain.Order, int64, error)
+	GetSummary(ctx context.Context, agentUserID string, period Period) (*OrderSummary, error)
+}
+
+// OrderSummary aggregates an agent's order count and sales
+type OrderSummary struct {
+	Count int64   `json:"count"`
+	Total float64 `json:"total"`
+}
+
+// UserDirectory provides access to auth service users
+type UserDirectory interface {
+	GetUserIDByEmail(ctx context.Context, email string) (string, error)
+	SetRole(ctx context.Context, email, role string) error
+}
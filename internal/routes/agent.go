@@ -1,74 +1,85 @@
 package routes
 
 import (
-	"github.com/gin-gonic/gin"
 	"github.com/Ecom-micro-template/service-agent/internal/handlers"
 	"github.com/Ecom-micro-template/service-agent/internal/middleware"
+	"github.com/Ecom-micro-template/service-agent/internal/repository"
+	"github.com/gin-gonic/gin"
 )
 
 // RegisterAgentRoutes registers all agent portal routes
-func RegisterAgentRoutes(r *gin.Engine) {
+func RegisterAgentRoutes(r *gin.Engine, agents repository.AgentReader, portal *handlers.AgentPortalHandler) {
 	// Agent Portal API - requires authentication and agent role
 	agentAPI := r.Group("/api/v1/agent")
-	agentAPI.Use(middleware.RequireAgent()) // Assumes auth middleware is already applied
+	agentAPI.Use(middleware.RequireAgent(agents)) // Assumes auth middleware is already applied
 	{
 		// Profile
-		agentAPI.GET("/profile", handlers.GetAgentProfile)
-		agentAPI.PUT("/profile", handlers.UpdateAgentProfile)
+		agentAPI.GET("/profile", portal.GetAgentProfile)
+		agentAPI.PUT("/profile", portal.UpdateAgentProfile)
 
 		// Dashboard
-		agentAPI.GET("/dashboard", handlers.GetAgentDashboard)
+		agentAPI.GET("/dashboard", portal.GetAgentDashboard)
 
 		// Orders
-		agentAPI.GET("/orders", handlers.GetAgentOrders)
-		agentAPI.POST("/orders", handlers.CreateAgentOrder)
-		agentAPI.GET("/orders/:id", handlers.GetAgentOrder)
+		agentAPI.GET("/orders", portal.GetAgentOrders)
+		agentAPI.POST("/orders", portal.CreateAgentOrder)
+		agentAPI.GET("/orders/:id", portal.GetAgentOrder)
 
 		// Customers
-		agentAPI.GET("/customers", handlers.GetAgentCustomers)
-		agentAPI.POST("/customers", handlers.CreateAgentCustomer)
-		agentAPI.GET("/customers/:id", handlers.GetAgentCustomer)
-		agentAPI.PUT("/customers/:id", handlers.UpdateAgentCustomer)
+		agentAPI.GET("/customers", portal.GetAgentCustomers)
+		agentAPI.POST("/customers", portal.CreateAgentCustomer)
+		agentAPI.GET("/customers/:id", portal.GetAgentCustomer)
+		agentAPI.PUT("/customers/:id", portal.UpdateAgentCustomer)
 
 		// Commissions
-		agentAPI.GET("/commissions", handlers.GetAgentCommissions)
+		agentAPI.GET("/commissions", portal.GetAgentCommissions)
 
 		// Performance
-		agentAPI.GET("/performance", handlers.GetAgentPerformance)
+		agentAPI.GET("/performance", portal.GetAgentPerformance)
 
 		// Team
-		agentAPI.GET("/team", handlers.GetAgentTeam)
+		agentAPI.GET("/team", portal.GetAgentTeam)
 	}
 }
 
 // RegisterAdminAgentRoutes registers admin routes for managing agents
-func RegisterAdminAgentRoutes(r *gin.Engine) {
+func RegisterAdminAgentRoutes(
+	r *gin.Engine,
+	agentHandler *handlers.AgentHandler,
+	commissionHandler *handlers.CommissionHandler,
+	payoutHandler *handlers.PayoutHandler,
+) {
 	// Admin API for managing agents - requires admin role
 	adminAPI := r.Group("/api/v1/admin/agents")
 	// adminAPI.Use(middleware.RequireAdmin()) // Add admin middleware
 	{
-		adminAPI.POST("", handlers.CreateAgent)
-		adminAPI.GET("", handlers.GetAgents)
-		adminAPI.GET("/:id", handlers.GetAgent)
-		adminAPI.PUT("/:id", handlers.UpdateAgent)
-		adminAPI.DELETE("/:id", handlers.DeleteAgent)
+		adminAPI.POST("", agentHandler.CreateAgent)
+		adminAPI.GET("", agentHandler.GetAgents)
+		adminAPI.GET("/:id", agentHandler.GetAgent)
+		adminAPI.PUT("/:id", agentHandler.UpdateAgent)
+		adminAPI.DELETE("/:id", agentHandler.DeleteAgent)
 	}
 
 	// Commission management
 	commissionAPI := r.Group("/api/v1/admin/commissions")
 	// commissionAPI.Use(middleware.RequireAdmin())
 	{
-		commissionAPI.GET("", handlers.GetPendingCommissions)
-		commissionAPI.GET("/:id/agent/:agent_id", handlers.GetAgentCommissionsByID)
-		commissionAPI.PUT("/:id/approve", handlers.ApproveCommission)
+		commissionAPI.GET("", commissionHandler.GetPendingCommissions)
+		commissionAPI.GET("/agent/:id", commissionHandler.GetAgentCommissionsByID)
+		commissionAPI.PUT("/:id/approve", commissionHandler.ApproveCommission)
 	}
 
 	// Payout management
 	payoutAPI := r.Group("/api/v1/admin/payouts")
 	// payoutAPI.Use(middleware.RequireAdmin())
 	{
-		payoutAPI.POST("", handlers.CreatePayout)
-		payoutAPI.GET("/:id", handlers.GetPayout)
-		payoutAPI.PUT("/:id/mark-paid", handlers.MarkPayoutPaid)
+		payoutAPI.POST("", payoutHandler.CreatePayout)
+		payoutAPI.GET("/:id", payoutHandler.GetPayout)
+		payoutAPI.PUT("/:id/process", payoutHandler.ProcessPayout)
+		payoutAPI.PUT("/:id/complete", payoutHandler.CompletePayout)
+		payoutAPI.PUT("/:id/fail", payoutHandler.FailPayout)
+		payoutAPI.PUT("/:id/retry", payoutHandler.RetryPayout)
+		payoutAPI.PUT("/:id/cancel", payoutHandler.CancelPayout)
+		payoutAPI.PUT("/:id/mark-paid", payoutHandler.CompletePayout)
 	}
 }
@@ -0,0 +1,2 @@
+-- Bank/transfer reference recorded when a payout is marked as paid
+ALTER TABLE payouts ADD COLUMN IF NOT EXISTS transaction_ref VARCHAR(100);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_commissions_order_agent;
@@ -0,0 +1,3 @@
+-- One commission per agent per order, so replayed order events cannot
+-- create duplicates.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_commissions_order_agent ON commissions (order_id, agent_id);
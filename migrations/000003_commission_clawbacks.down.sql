@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS commission_clawbacks;
+ALTER TABLE payouts DROP COLUMN IF EXISTS deductions;
+ALTER TABLE commissions DROP COLUMN IF EXISTS reversed_amount;
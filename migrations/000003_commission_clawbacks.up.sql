@@ -0,0 +1,23 @@
+-- Clawbacks: negative adjustments against a commission when its order is
+-- refunded. Outstanding clawbacks are netted against the agent's next payout.
+ALTER TABLE commissions ADD COLUMN IF NOT EXISTS reversed_amount DECIMAL(10,2) NOT NULL DEFAULT 0;
+ALTER TABLE payouts ADD COLUMN IF NOT EXISTS deductions DECIMAL(10,2) NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS commission_clawbacks (
+    id            BIGSERIAL PRIMARY KEY,
+    commission_id BIGINT NOT NULL REFERENCES commissions (id),
+    agent_id      BIGINT NOT NULL,
+    order_id      VARCHAR(100) NOT NULL,
+    refund_id     VARCHAR(100),
+    amount        DECIMAL(10,2) NOT NULL,
+    refund_amount DECIMAL(10,2) NOT NULL,
+    reason        VARCHAR(255),
+    status        VARCHAR(20) NOT NULL,
+    payout_id     BIGINT REFERENCES payouts (id),
+    created_at    TIMESTAMPTZ,
+    updated_at    TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_commission_clawbacks_commission_id ON commission_clawbacks (commission_id);
+CREATE INDEX IF NOT EXISTS idx_commission_clawbacks_agent_status ON commission_clawbacks (agent_id, status);
+CREATE INDEX IF NOT EXISTS idx_commission_clawbacks_payout_id ON commission_clawbacks (payout_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_commissions_split_group_id;
+ALTER TABLE commissions DROP COLUMN IF EXISTS split_share;
+ALTER TABLE commissions DROP COLUMN IF EXISTS split_group_id;
@@ -0,0 +1,6 @@
+-- Co-sold orders: one commission per agent, linked by a split group ID,
+-- each holding the agent's percentage share of the order.
+ALTER TABLE commissions ADD COLUMN IF NOT EXISTS split_group_id VARCHAR(36);
+ALTER TABLE commissions ADD COLUMN IF NOT EXISTS split_share DECIMAL(5,2) NOT NULL DEFAULT 100;
+
+CREATE INDEX IF NOT EXISTS idx_commissions_split_group_id ON commissions (split_group_id);
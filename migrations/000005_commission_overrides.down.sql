@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_commissions_source_agent;
+DROP INDEX IF EXISTS idx_commissions_order_agent;
+DELETE FROM commission_clawbacks WHERE commission_id IN (SELECT id FROM commissions WHERE source_commission_id IS NOT NULL);
+DELETE FROM commissions WHERE source_commission_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_commissions_order_agent ON commissions (order_id, agent_id);
+
+DROP INDEX IF EXISTS idx_commissions_source_commission_id;
+ALTER TABLE commissions DROP COLUMN IF EXISTS source_commission_id;
+ALTER TABLE commissions DROP COLUMN IF EXISTS commission_type;
@@ -0,0 +1,15 @@
+-- Team leader overrides: derived commissions linked to the sale commission
+-- they were generated from.
+ALTER TABLE commissions ADD COLUMN IF NOT EXISTS commission_type VARCHAR(20) NOT NULL DEFAULT 'sale';
+ALTER TABLE commissions ADD COLUMN IF NOT EXISTS source_commission_id BIGINT REFERENCES commissions (id);
+
+CREATE INDEX IF NOT EXISTS idx_commissions_source_commission_id ON commissions (source_commission_id);
+
+-- A leader can sell on an order and also earn an override on it, so the
+-- one-per-agent-per-order rule only applies to sale commissions. Derived
+-- commissions are unique per agent per source commission.
+DROP INDEX IF EXISTS idx_commissions_order_agent;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_commissions_order_agent ON commissions (order_id, agent_id)
+    WHERE source_commission_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_commissions_source_agent ON commissions (source_commission_id, agent_id)
+    WHERE source_commission_id IS NOT NULL;
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_commissions_source_agent;
+DELETE FROM commission_clawbacks WHERE commission_id IN (SELECT id FROM commissions WHERE commission_type = 'downline');
+DELETE FROM commissions WHERE commission_type = 'downline';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_commissions_source_agent ON commissions (source_commission_id, agent_id)
+    WHERE source_commission_id IS NOT NULL;
+
+ALTER TABLE commissions DROP COLUMN IF EXISTS level;
+
+DROP INDEX IF EXISTS idx_agents_parent_id;
+ALTER TABLE agents DROP COLUMN IF EXISTS parent_id;
@@ -0,0 +1,12 @@
+-- Agent hierarchy: each agent may have an upline sponsor, and sponsors
+-- earn downline commissions on sales made below them.
+ALTER TABLE agents ADD COLUMN IF NOT EXISTS parent_id BIGINT REFERENCES agents (id);
+CREATE INDEX IF NOT EXISTS idx_agents_parent_id ON agents (parent_id);
+
+ALTER TABLE commissions ADD COLUMN IF NOT EXISTS level INTEGER NOT NULL DEFAULT 0;
+
+-- A sponsor can also be the seller's team leader, so derived commissions
+-- are unique per agent per source commission per type.
+DROP INDEX IF EXISTS idx_commissions_source_agent;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_commissions_source_agent ON commissions (source_commission_id, agent_id, commission_type)
+    WHERE source_commission_id IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_customers_agent_last_order;
+DROP TABLE IF EXISTS agent_tier_evaluations;
@@ -0,0 +1,21 @@
+-- Tier engine: agents are promoted and demoted from their rolling
+-- results, and every evaluation is kept with its reasons.
+ALTER TABLE agents ADD COLUMN IF NOT EXISTS tier VARCHAR(20) NOT NULL DEFAULT 'bronze';
+
+CREATE TABLE IF NOT EXISTS agent_tier_evaluations (
+    id               BIGSERIAL PRIMARY KEY,
+    agent_id         BIGINT NOT NULL REFERENCES agents (id),
+    previous_tier    VARCHAR(20) NOT NULL,
+    new_tier         VARCHAR(20) NOT NULL,
+    outcome          VARCHAR(20) NOT NULL,
+    sales            DECIMAL(12,2) NOT NULL DEFAULT 0,
+    orders           BIGINT NOT NULL DEFAULT 0,
+    active_customers BIGINT NOT NULL DEFAULT 0,
+    period_from      TIMESTAMPTZ NOT NULL,
+    period_to        TIMESTAMPTZ NOT NULL,
+    reasons          TEXT,
+    evaluated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_agent_tier_evaluations_agent_id ON agent_tier_evaluations (agent_id, evaluated_at DESC);
+CREATE INDEX IF NOT EXISTS idx_customers_agent_last_order ON customers (agent_id, last_order_at);
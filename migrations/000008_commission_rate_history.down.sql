@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_agent_category_commissions_effective;
+ALTER TABLE agent_category_commissions DROP COLUMN IF EXISTS effective_from;
+DROP TABLE IF EXISTS commission_rate_changes;
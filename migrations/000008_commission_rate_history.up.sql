@@ -0,0 +1,28 @@
+-- Effective-dated commission rates: rate changes are recorded instead of
+-- overwriting the current rate, so old orders are calculated at the rate
+-- that was valid when they were placed.
+CREATE TABLE IF NOT EXISTS commission_rate_changes (
+    id             BIGSERIAL PRIMARY KEY,
+    scope          VARCHAR(20) NOT NULL,
+    subject_id     BIGINT NOT NULL,
+    rate           DECIMAL(5,2) NOT NULL,
+    effective_from TIMESTAMPTZ NOT NULL,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_rate_changes_subject ON commission_rate_changes (scope, subject_id, effective_from);
+
+-- Current rates have been in effect since the agent or team was created
+INSERT INTO commission_rate_changes (scope, subject_id, rate, effective_from)
+SELECT 'agent', id, commission_rate, created_at FROM agents;
+
+INSERT INTO commission_rate_changes (scope, subject_id, rate, effective_from)
+SELECT 'team', id, commission_rate, created_at FROM teams;
+
+-- Category rates become versions; existing ones apply from when they were set
+ALTER TABLE agent_category_commissions ADD COLUMN IF NOT EXISTS effective_from TIMESTAMPTZ;
+UPDATE agent_category_commissions SET effective_from = created_at WHERE effective_from IS NULL;
+ALTER TABLE agent_category_commissions ALTER COLUMN effective_from SET NOT NULL;
+ALTER TABLE agent_category_commissions ALTER COLUMN effective_from SET DEFAULT NOW();
+
+CREATE INDEX IF NOT EXISTS idx_agent_category_commissions_effective ON agent_category_commissions (agent_id, category_id, effective_from);
@@ -0,0 +1,11 @@
+ALTER TABLE payouts DROP COLUMN IF EXISTS currency;
+ALTER TABLE commission_clawbacks DROP COLUMN IF EXISTS payout_currency;
+ALTER TABLE commission_clawbacks DROP COLUMN IF EXISTS payout_amount;
+ALTER TABLE commission_clawbacks DROP COLUMN IF EXISTS currency;
+ALTER TABLE commissions DROP COLUMN IF EXISTS fx_rate;
+ALTER TABLE commissions DROP COLUMN IF EXISTS payout_currency;
+ALTER TABLE commissions DROP COLUMN IF EXISTS payout_amount;
+ALTER TABLE commissions DROP COLUMN IF EXISTS currency;
+ALTER TABLE agents DROP COLUMN IF EXISTS payout_currency;
+DROP INDEX IF EXISTS idx_fx_rates_pair;
+DROP TABLE IF EXISTS fx_rates;
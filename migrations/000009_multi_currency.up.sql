@@ -0,0 +1,32 @@
+-- Multi-currency commissions: orders are sold in several currencies and
+-- agents are paid in their own payout currency. Commissions keep the
+-- amount in the order currency and the amount converted at the rate from
+-- the local FX rate table that was in effect when the order was placed.
+CREATE TABLE IF NOT EXISTS fx_rates (
+    id             BIGSERIAL PRIMARY KEY,
+    base_currency  VARCHAR(3) NOT NULL,
+    quote_currency VARCHAR(3) NOT NULL,
+    rate           DECIMAL(20,10) NOT NULL,
+    effective_from TIMESTAMPTZ NOT NULL,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_fx_rates_pair ON fx_rates (base_currency, quote_currency, effective_from);
+
+ALTER TABLE agents ADD COLUMN IF NOT EXISTS payout_currency VARCHAR(3) NOT NULL DEFAULT 'MYR';
+
+-- Existing commissions were earned and paid in the default currency
+ALTER TABLE commissions ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'MYR';
+ALTER TABLE commissions ADD COLUMN IF NOT EXISTS payout_amount DECIMAL(10,2);
+ALTER TABLE commissions ADD COLUMN IF NOT EXISTS payout_currency VARCHAR(3) NOT NULL DEFAULT 'MYR';
+ALTER TABLE commissions ADD COLUMN IF NOT EXISTS fx_rate DECIMAL(20,10) NOT NULL DEFAULT 1;
+UPDATE commissions SET payout_amount = amount WHERE payout_amount IS NULL;
+ALTER TABLE commissions ALTER COLUMN payout_amount SET NOT NULL;
+
+ALTER TABLE commission_clawbacks ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'MYR';
+ALTER TABLE commission_clawbacks ADD COLUMN IF NOT EXISTS payout_amount DECIMAL(10,2);
+ALTER TABLE commission_clawbacks ADD COLUMN IF NOT EXISTS payout_currency VARCHAR(3) NOT NULL DEFAULT 'MYR';
+UPDATE commission_clawbacks SET payout_amount = amount WHERE payout_amount IS NULL;
+ALTER TABLE commission_clawbacks ALTER COLUMN payout_amount SET NOT NULL;
+
+ALTER TABLE payouts ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'MYR';
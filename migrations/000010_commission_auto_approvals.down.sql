@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_commission_auto_approvals_approved_at;
+DROP INDEX IF EXISTS idx_commission_auto_approvals_agent;
+DROP INDEX IF EXISTS idx_commission_auto_approvals_commission;
+DROP TABLE IF EXISTS commission_auto_approvals;
@@ -0,0 +1,16 @@
+-- Auto-approvals: pending commissions approved without review once the
+-- holding period has passed, with the rule that approved them and why.
+CREATE TABLE IF NOT EXISTS commission_auto_approvals (
+    id            BIGSERIAL PRIMARY KEY,
+    commission_id BIGINT NOT NULL REFERENCES commissions (id),
+    agent_id      BIGINT NOT NULL,
+    rule          VARCHAR(50) NOT NULL,
+    amount        DECIMAL(10,2) NOT NULL,
+    currency      VARCHAR(3) NOT NULL DEFAULT 'MYR',
+    reasons       TEXT,
+    approved_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_commission_auto_approvals_commission ON commission_auto_approvals (commission_id);
+CREATE INDEX IF NOT EXISTS idx_commission_auto_approvals_agent ON commission_auto_approvals (agent_id);
+CREATE INDEX IF NOT EXISTS idx_commission_auto_approvals_approved_at ON commission_auto_approvals (approved_at);
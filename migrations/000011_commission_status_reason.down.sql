@@ -0,0 +1,3 @@
+UPDATE commissions SET status = 'cancelled' WHERE status = 'rejected';
+ALTER TABLE commissions DROP COLUMN IF EXISTS status_changed_by;
+ALTER TABLE commissions DROP COLUMN IF EXISTS status_reason;
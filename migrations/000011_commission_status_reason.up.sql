@@ -0,0 +1,4 @@
+-- Rejected and cancelled commissions keep why and by whom they were closed.
+-- An empty actor means the system, such as on a full refund.
+ALTER TABLE commissions ADD COLUMN IF NOT EXISTS status_reason TEXT;
+ALTER TABLE commissions ADD COLUMN IF NOT EXISTS status_changed_by VARCHAR(100);
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_commissions_order_agent;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_commissions_order_agent ON commissions (order_id, agent_id)
+    WHERE source_commission_id IS NULL;
+
+DROP INDEX IF EXISTS idx_commission_dispute_messages_dispute_id;
+DROP TABLE IF EXISTS commission_dispute_messages;
+DROP INDEX IF EXISTS idx_commission_disputes_status;
+DROP INDEX IF EXISTS idx_commission_disputes_commission_id;
+DROP INDEX IF EXISTS idx_commission_disputes_agent_order;
+DROP TABLE IF EXISTS commission_disputes;
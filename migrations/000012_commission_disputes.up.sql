@@ -0,0 +1,36 @@
+-- Commission disputes: agents contest a missing or wrong commission on an
+-- order, and admins triage them in a thread of messages.
+CREATE TABLE IF NOT EXISTS commission_disputes (
+    id                       BIGSERIAL PRIMARY KEY,
+    agent_id                 BIGINT NOT NULL REFERENCES agents (id),
+    order_id                 VARCHAR(100) NOT NULL,
+    commission_id            BIGINT REFERENCES commissions (id),
+    status                   VARCHAR(20) NOT NULL DEFAULT 'open',
+    resolution               TEXT,
+    resolved_by              VARCHAR(100),
+    resolved_at              TIMESTAMPTZ,
+    adjustment_commission_id BIGINT REFERENCES commissions (id),
+    created_at               TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at               TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_commission_disputes_agent_order ON commission_disputes (agent_id, order_id);
+CREATE INDEX IF NOT EXISTS idx_commission_disputes_commission_id ON commission_disputes (commission_id);
+CREATE INDEX IF NOT EXISTS idx_commission_disputes_status ON commission_disputes (status);
+
+CREATE TABLE IF NOT EXISTS commission_dispute_messages (
+    id         BIGSERIAL PRIMARY KEY,
+    dispute_id BIGINT NOT NULL REFERENCES commission_disputes (id) ON DELETE CASCADE,
+    author     VARCHAR(20) NOT NULL,
+    author_id  VARCHAR(100),
+    body       TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_commission_dispute_messages_dispute_id ON commission_dispute_messages (dispute_id);
+
+-- Adjustments granted on a dispute sit on the order next to the agent's
+-- sale commission, so one-per-agent-per-order only applies to sales.
+DROP INDEX IF EXISTS idx_commissions_order_agent;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_commissions_order_agent ON commissions (order_id, agent_id)
+    WHERE source_commission_id IS NULL AND commission_type = 'sale';
@@ -0,0 +1,3 @@
+ALTER TABLE commissions DROP COLUMN IF EXISTS created_by;
+ALTER TABLE commissions DROP COLUMN IF EXISTS note;
+ALTER TABLE commissions DROP COLUMN IF EXISTS adjustment_category;
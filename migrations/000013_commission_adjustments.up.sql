@@ -0,0 +1,9 @@
+-- Manual adjustments are commissions of type 'adjustment' with a signed
+-- amount, no order total or rate, and an order ID only when they correct an
+-- order ('' otherwise). The category is bonus, penalty or correction.
+ALTER TABLE commissions ADD COLUMN IF NOT EXISTS adjustment_category VARCHAR(20);
+ALTER TABLE commissions ADD COLUMN IF NOT EXISTS note TEXT;
+ALTER TABLE commissions ADD COLUMN IF NOT EXISTS created_by VARCHAR(100);
+
+UPDATE commissions SET adjustment_category = 'correction', note = 'Granted on dispute'
+    WHERE commission_type = 'adjustment' AND adjustment_category IS NULL;
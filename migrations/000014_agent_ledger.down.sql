@@ -0,0 +1,17 @@
+ALTER TABLE agents ADD COLUMN IF NOT EXISTS total_earned DECIMAL(10,2) DEFAULT 0;
+UPDATE agents SET total_earned = COALESCE((
+    SELECT -SUM(l.amount) FROM agent_ledger_lines l
+    WHERE l.agent_id = agents.id AND l.currency = agents.payout_currency
+      AND l.account IN ('available', 'paid_out', 'clawback_receivable')
+), 0);
+
+DROP TRIGGER IF EXISTS agent_ledger_lines_append_only ON agent_ledger_lines;
+DROP TRIGGER IF EXISTS agent_ledger_entries_append_only ON agent_ledger_entries;
+DROP FUNCTION IF EXISTS agent_ledger_append_only();
+DROP INDEX IF EXISTS idx_agent_ledger_lines_balance;
+DROP INDEX IF EXISTS idx_agent_ledger_lines_entry_id;
+DROP TABLE IF EXISTS agent_ledger_lines;
+DROP INDEX IF EXISTS idx_agent_ledger_entries_payout_id;
+DROP INDEX IF EXISTS idx_agent_ledger_entries_commission_id;
+DROP INDEX IF EXISTS idx_agent_ledger_entries_agent_id;
+DROP TABLE IF EXISTS agent_ledger_entries;
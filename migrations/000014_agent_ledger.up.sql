@@ -0,0 +1,86 @@
+-- Agent earnings ledger: every commission and payout transition is posted
+-- as a balanced entry moving money between an agent's accounts (pending,
+-- available, paid_out, clawback_receivable, against commission_expense).
+-- Lines hold debits as positive amounts and credits as negative, so an
+-- entry's lines always sum to zero. Entries are append-only.
+CREATE TABLE IF NOT EXISTS agent_ledger_entries (
+    id            BIGSERIAL PRIMARY KEY,
+    agent_id      BIGINT NOT NULL REFERENCES agents (id),
+    kind          VARCHAR(40) NOT NULL,
+    commission_id BIGINT REFERENCES commissions (id),
+    payout_id     BIGINT REFERENCES payouts (id),
+    currency      VARCHAR(3) NOT NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_agent_ledger_entries_agent_id ON agent_ledger_entries (agent_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_agent_ledger_entries_commission_id ON agent_ledger_entries (commission_id);
+CREATE INDEX IF NOT EXISTS idx_agent_ledger_entries_payout_id ON agent_ledger_entries (payout_id);
+
+CREATE TABLE IF NOT EXISTS agent_ledger_lines (
+    id       BIGSERIAL PRIMARY KEY,
+    entry_id BIGINT NOT NULL REFERENCES agent_ledger_entries (id),
+    agent_id BIGINT NOT NULL,
+    account  VARCHAR(30) NOT NULL,
+    amount   DECIMAL(12,2) NOT NULL,
+    currency VARCHAR(3) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_agent_ledger_lines_entry_id ON agent_ledger_lines (entry_id);
+CREATE INDEX IF NOT EXISTS idx_agent_ledger_lines_balance ON agent_ledger_lines (agent_id, account, currency);
+
+CREATE OR REPLACE FUNCTION agent_ledger_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'agent ledger is append-only: % on % is not allowed', TG_OP, TG_TABLE_NAME;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS agent_ledger_entries_append_only ON agent_ledger_entries;
+CREATE TRIGGER agent_ledger_entries_append_only BEFORE UPDATE OR DELETE ON agent_ledger_entries
+    FOR EACH ROW EXECUTE FUNCTION agent_ledger_append_only();
+DROP TRIGGER IF EXISTS agent_ledger_lines_append_only ON agent_ledger_lines;
+CREATE TRIGGER agent_ledger_lines_append_only BEFORE UPDATE OR DELETE ON agent_ledger_lines
+    FOR EACH ROW EXECUTE FUNCTION agent_ledger_append_only();
+
+-- Opening balances from existing records, each against commission expense:
+-- pending and approved commissions at their net payout amount, payouts that
+-- were not cancelled, and clawbacks still owed on paid commissions.
+CREATE TEMP TABLE ledger_opening_balances AS
+SELECT nextval('agent_ledger_entries_id_seq') AS entry_id, opening.*
+FROM (
+    SELECT agent_id, id AS commission_id, NULL::BIGINT AS payout_id,
+           CASE status WHEN 'pending' THEN 'pending' ELSE 'available' END AS account,
+           CASE WHEN reversed_amount = 0 OR amount = 0 THEN payout_amount
+                ELSE ROUND(payout_amount * (amount - reversed_amount) / amount, 2) END AS amount,
+           payout_currency AS currency, created_at
+    FROM commissions
+    WHERE status IN ('pending', 'approved')
+    UNION ALL
+    SELECT agent_id, NULL, id, 'paid_out', amount, currency, created_at
+    FROM payouts
+    WHERE status <> 'cancelled'
+    UNION ALL
+    SELECT agent_id, commission_id, NULL, 'clawback_receivable', -payout_amount, payout_currency, created_at
+    FROM commission_clawbacks
+    WHERE status = 'outstanding'
+) AS opening
+WHERE opening.amount <> 0;
+
+INSERT INTO agent_ledger_entries (id, agent_id, kind, commission_id, payout_id, currency, created_at)
+SELECT entry_id, agent_id, 'opening_balance', commission_id, payout_id, currency, COALESCE(created_at, NOW())
+FROM ledger_opening_balances;
+
+-- The receivable is debited; the agent's other accounts are credited
+INSERT INTO agent_ledger_lines (entry_id, agent_id, account, amount, currency)
+SELECT entry_id, agent_id, account,
+       CASE account WHEN 'clawback_receivable' THEN amount ELSE -amount END, currency
+FROM ledger_opening_balances
+UNION ALL
+SELECT entry_id, agent_id, 'commission_expense',
+       CASE account WHEN 'clawback_receivable' THEN -amount ELSE amount END, currency
+FROM ledger_opening_balances;
+
+DROP TABLE ledger_opening_balances;
+
+-- Total earnings are derived from the ledger from now on
+ALTER TABLE agents DROP COLUMN IF EXISTS total_earned;
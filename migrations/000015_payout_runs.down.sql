@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_payout_run_lines_payout_id;
+DROP INDEX IF EXISTS idx_payout_run_lines_agent_id;
+DROP INDEX IF EXISTS idx_payout_run_lines_run_id;
+DROP TABLE IF EXISTS payout_run_lines;
+DROP INDEX IF EXISTS idx_payout_runs_committed_period;
+DROP INDEX IF EXISTS idx_payout_runs_period;
+DROP TABLE IF EXISTS payout_runs;
@@ -0,0 +1,37 @@
+-- Payout runs: one batch over every active agent for a period, recording
+-- who was paid and whose balance was carried forward to the next run.
+CREATE TABLE IF NOT EXISTS payout_runs (
+    id           BIGSERIAL PRIMARY KEY,
+    period       VARCHAR(20) NOT NULL,
+    status       VARCHAR(20) NOT NULL DEFAULT 'committed',
+    minimum      DECIMAL(10,2) NOT NULL DEFAULT 0,
+    currency     VARCHAR(3) NOT NULL DEFAULT 'MYR',
+    created_by   VARCHAR(100),
+    cancelled_by VARCHAR(100),
+    cancelled_at TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_payout_runs_period ON payout_runs (period);
+
+-- A period is paid by at most one run; a cancelled run can be run again.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payout_runs_committed_period ON payout_runs (period)
+    WHERE status = 'committed';
+
+CREATE TABLE IF NOT EXISTS payout_run_lines (
+    id          BIGSERIAL PRIMARY KEY,
+    run_id      BIGINT NOT NULL REFERENCES payout_runs (id) ON DELETE CASCADE,
+    agent_id    BIGINT NOT NULL REFERENCES agents (id),
+    outcome     VARCHAR(20) NOT NULL,
+    amount      DECIMAL(10,2) NOT NULL,
+    deductions  DECIMAL(10,2) NOT NULL DEFAULT 0,
+    currency    VARCHAR(3) NOT NULL DEFAULT 'MYR',
+    commissions INT NOT NULL DEFAULT 0,
+    reason      TEXT,
+    payout_id   BIGINT REFERENCES payouts (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_payout_run_lines_run_id ON payout_run_lines (run_id);
+CREATE INDEX IF NOT EXISTS idx_payout_run_lines_agent_id ON payout_run_lines (agent_id);
+CREATE INDEX IF NOT EXISTS idx_payout_run_lines_payout_id ON payout_run_lines (payout_id);
@@ -0,0 +1,2 @@
+UPDATE commissions SET status = 'paid' WHERE status = 'in_payout';
+ALTER TABLE payouts DROP COLUMN IF EXISTS failure_reason;
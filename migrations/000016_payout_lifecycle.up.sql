@@ -0,0 +1,16 @@
+-- Payouts record why the bank rejected them.
+ALTER TABLE payouts ADD COLUMN IF NOT EXISTS failure_reason TEXT;
+
+-- Payouts marked paid before the lifecycle was enforced were completed.
+UPDATE payouts SET status = 'completed' WHERE status = 'paid';
+
+-- Commissions are only paid once their payout completes; until then they
+-- are in the payout.
+UPDATE commissions SET status = 'in_payout'
+WHERE status = 'paid'
+  AND id IN (
+    SELECT (jsonb_array_elements_text(commission_ids::jsonb))::BIGINT
+    FROM payouts
+    WHERE status IN ('pending', 'processing', 'failed')
+      AND commission_ids IS NOT NULL AND commission_ids <> ''
+  );
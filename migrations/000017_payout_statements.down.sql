@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_payout_statement_lines_payout_id;
+DROP INDEX IF EXISTS idx_payout_statement_lines_outcome;
+DROP INDEX IF EXISTS idx_payout_statement_lines_statement_id;
+DROP TABLE IF EXISTS payout_statement_lines;
+DROP INDEX IF EXISTS idx_payout_statements_checksum;
+DROP TABLE IF EXISTS payout_statements;
@@ -0,0 +1,33 @@
+-- Bank statements imported to reconcile payouts, one row per file, with
+-- each statement line and the payout it was matched to. Unmatched lines
+-- form the review queue.
+CREATE TABLE IF NOT EXISTS payout_statements (
+    id          BIGSERIAL PRIMARY KEY,
+    format      VARCHAR(20) NOT NULL,
+    file_name   VARCHAR(255),
+    checksum    VARCHAR(64) NOT NULL,
+    imported_by VARCHAR(100),
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- A statement file is imported once.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payout_statements_checksum ON payout_statements (checksum);
+
+CREATE TABLE IF NOT EXISTS payout_statement_lines (
+    id              BIGSERIAL PRIMARY KEY,
+    statement_id    BIGINT NOT NULL REFERENCES payout_statements (id) ON DELETE CASCADE,
+    reference       VARCHAR(255),
+    transaction_ref VARCHAR(100),
+    amount          DECIMAL(10,2) NOT NULL,
+    currency        VARCHAR(3) NOT NULL DEFAULT 'MYR',
+    booked_at       TIMESTAMPTZ,
+    outcome         VARCHAR(20) NOT NULL,
+    detail          TEXT,
+    payout_id       BIGINT REFERENCES payouts (id),
+    reviewed_by     VARCHAR(100),
+    reviewed_at     TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_payout_statement_lines_statement_id ON payout_statement_lines (statement_id);
+CREATE INDEX IF NOT EXISTS idx_payout_statement_lines_outcome ON payout_statement_lines (outcome);
+CREATE INDEX IF NOT EXISTS idx_payout_statement_lines_payout_id ON payout_statement_lines (payout_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_agent_payout_methods_default;
+DROP INDEX IF EXISTS idx_agent_payout_methods_agent_id;
+DROP TABLE IF EXISTS agent_payout_methods;
@@ -0,0 +1,27 @@
+-- Accounts agents are paid into: bank accounts and e-wallets. Account
+-- numbers are encrypted by the service (AES-256-GCM) before they are
+-- written; only the outstanding verification code's hash is stored.
+CREATE TABLE IF NOT EXISTS agent_payout_methods (
+    id                       BIGSERIAL PRIMARY KEY,
+    agent_id                 BIGINT NOT NULL REFERENCES agents (id) ON DELETE CASCADE,
+    type                     VARCHAR(20) NOT NULL,
+    provider                 VARCHAR(100) NOT NULL,
+    provider_code            VARCHAR(20),
+    account_name             VARCHAR(255) NOT NULL,
+    account_number_encrypted TEXT NOT NULL,
+    is_default               BOOLEAN NOT NULL DEFAULT FALSE,
+    status                   VARCHAR(20) NOT NULL DEFAULT 'unverified',
+    verification_hash        VARCHAR(64),
+    verification_expires_at  TIMESTAMPTZ,
+    verification_attempts    INTEGER NOT NULL DEFAULT 0,
+    verified_at              TIMESTAMPTZ,
+    changed_at               TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_at               TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at               TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_agent_payout_methods_agent_id ON agent_payout_methods (agent_id);
+
+-- An agent has at most one default payout method.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_agent_payout_methods_default
+    ON agent_payout_methods (agent_id) WHERE is_default;
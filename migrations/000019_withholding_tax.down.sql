@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS agent_tax_profiles;
+DROP TABLE IF EXISTS tax_withholding_rules;
+DROP INDEX IF EXISTS idx_payouts_paid_at;
+ALTER TABLE payouts DROP COLUMN IF EXISTS withholding_rate;
+ALTER TABLE payouts DROP COLUMN IF EXISTS withheld;
@@ -0,0 +1,36 @@
+-- Tax withheld from agents' commission payouts. Each payout stores the
+-- rate it was created with and the amount withheld, so later rule changes
+-- never alter a payout already made. Withholding is posted to the agent's
+-- tax_withheld ledger account.
+ALTER TABLE payouts ADD COLUMN IF NOT EXISTS withheld DECIMAL(10,2) NOT NULL DEFAULT 0;
+ALTER TABLE payouts ADD COLUMN IF NOT EXISTS withholding_rate DECIMAL(5,2) NOT NULL DEFAULT 0;
+
+-- Annual statements select completed payouts by the time they were paid.
+CREATE INDEX IF NOT EXISTS idx_payouts_paid_at ON payouts (paid_at) WHERE status = 'completed';
+
+-- Withholding rules match agents by residency and whether they have given
+-- a tax ID; a NULL condition matches any agent.
+CREATE TABLE IF NOT EXISTS tax_withholding_rules (
+    id             BIGSERIAL PRIMARY KEY,
+    name           VARCHAR(100) NOT NULL,
+    residency      VARCHAR(20),
+    tax_id_present BOOLEAN,
+    rate           DECIMAL(5,2) NOT NULL,
+    active         BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_tax_withholding_rules_rate CHECK (rate >= 0 AND rate <= 100)
+);
+
+-- Agents' tax details. Tax IDs are encrypted by the service (AES-256-GCM)
+-- before they are written.
+CREATE TABLE IF NOT EXISTS agent_tax_profiles (
+    agent_id         BIGINT PRIMARY KEY REFERENCES agents (id) ON DELETE CASCADE,
+    tax_id_encrypted TEXT,
+    residency        VARCHAR(20) NOT NULL,
+    rate_override    DECIMAL(5,2),
+    updated_by       VARCHAR(255),
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_agent_tax_profiles_rate_override CHECK (rate_override >= 0 AND rate_override <= 100)
+);
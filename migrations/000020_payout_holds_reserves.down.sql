@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS payout_reserves;
+DROP TABLE IF EXISTS agent_reserve_policies;
+DROP TABLE IF EXISTS payout_holds;
+ALTER TABLE payouts DROP COLUMN IF EXISTS released;
+ALTER TABLE payouts DROP COLUMN IF EXISTS reserve_percent;
+ALTER TABLE payouts DROP COLUMN IF EXISTS reserved;
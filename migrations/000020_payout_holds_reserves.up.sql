@@ -0,0 +1,58 @@
+-- Reserves kept back from payouts under an agent's reserve policy, and
+-- earlier reserves a release payout pays. Both are posted to the agent's
+-- reserve ledger account.
+ALTER TABLE payouts ADD COLUMN IF NOT EXISTS reserved DECIMAL(10,2) NOT NULL DEFAULT 0;
+ALTER TABLE payouts ADD COLUMN IF NOT EXISTS reserve_percent DECIMAL(5,2) NOT NULL DEFAULT 0;
+ALTER TABLE payouts ADD COLUMN IF NOT EXISTS released DECIMAL(10,2) NOT NULL DEFAULT 0;
+
+-- Holds admins place on risky agents' payouts. A hold is active until it
+-- expires or is released.
+CREATE TABLE IF NOT EXISTS payout_holds (
+    id          BIGSERIAL PRIMARY KEY,
+    agent_id    BIGINT NOT NULL REFERENCES agents (id) ON DELETE CASCADE,
+    reason      TEXT NOT NULL,
+    expires_at  TIMESTAMPTZ NOT NULL,
+    created_by  VARCHAR(255),
+    released_by VARCHAR(255),
+    released_at TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_payout_holds_agent_id ON payout_holds (agent_id);
+CREATE INDEX IF NOT EXISTS idx_payout_holds_active ON payout_holds (expires_at) WHERE released_at IS NULL;
+
+-- The share of an agent's payouts kept back in reserve, and for how long.
+CREATE TABLE IF NOT EXISTS agent_reserve_policies (
+    agent_id   BIGINT PRIMARY KEY REFERENCES agents (id) ON DELETE CASCADE,
+    percent    DECIMAL(5,2) NOT NULL,
+    days       INTEGER NOT NULL,
+    reason     TEXT NOT NULL,
+    updated_by VARCHAR(255),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT chk_agent_reserve_policies_percent CHECK (percent > 0 AND percent <= 100),
+    CONSTRAINT chk_agent_reserve_policies_days CHECK (days >= 1)
+);
+
+-- One reserve per payout that kept one back, until a release payout pays
+-- it to the agent.
+CREATE TABLE IF NOT EXISTS payout_reserves (
+    id                BIGSERIAL PRIMARY KEY,
+    agent_id          BIGINT NOT NULL REFERENCES agents (id),
+    payout_id         BIGINT NOT NULL REFERENCES payouts (id),
+    amount            DECIMAL(10,2) NOT NULL,
+    currency          VARCHAR(3) NOT NULL,
+    percent           DECIMAL(5,2) NOT NULL,
+    release_at        TIMESTAMPTZ NOT NULL,
+    status            VARCHAR(20) NOT NULL DEFAULT 'held',
+    release_payout_id BIGINT REFERENCES payouts (id),
+    released_at       TIMESTAMPTZ,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payout_reserves_payout_id ON payout_reserves (payout_id);
+CREATE INDEX IF NOT EXISTS idx_payout_reserves_agent_id ON payout_reserves (agent_id);
+CREATE INDEX IF NOT EXISTS idx_payout_reserves_release_payout_id ON payout_reserves (release_payout_id);
+CREATE INDEX IF NOT EXISTS idx_payout_reserves_due ON payout_reserves (release_at) WHERE status = 'held';
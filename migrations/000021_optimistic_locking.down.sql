@@ -0,0 +1,2 @@
+ALTER TABLE payouts DROP COLUMN IF EXISTS version;
+ALTER TABLE commissions DROP COLUMN IF EXISTS version;
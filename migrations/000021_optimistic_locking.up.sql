@@ -0,0 +1,5 @@
+-- Version counters for optimistic locking. Every update bumps the version,
+-- and an update made from a stale copy of the row matches nothing and is
+-- refused, so concurrent approvals and payouts never post the ledger twice.
+ALTER TABLE commissions ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE payouts ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 0;
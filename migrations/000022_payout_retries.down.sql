@@ -0,0 +1 @@
+ALTER TABLE payouts DROP COLUMN IF EXISTS retries;
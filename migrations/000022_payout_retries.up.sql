@@ -0,0 +1,3 @@
+-- How many times each payout was retried after failing. Every retry is
+-- sent to the payout provider as a new transfer under its own reference.
+ALTER TABLE payouts ADD COLUMN IF NOT EXISTS retries INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,34 @@
+-- The ledger is append-only: post the opposite entries, returning the net
+-- amount of payouts that have not completed to paid_out and posting the
+-- entries of failed payouts again.
+CREATE TEMP TABLE ledger_payout_moves AS
+SELECT nextval('agent_ledger_entries_id_seq') AS entry_id, id AS payout_id, agent_id, status, currency,
+       amount AS net, deductions, withheld, reserved, released,
+       amount + withheld + reserved - released + deductions AS gross
+FROM payouts
+WHERE status IN ('pending', 'processing', 'failed');
+
+DELETE FROM ledger_payout_moves
+WHERE (status = 'failed' AND gross = 0 AND released = 0)
+   OR (status <> 'failed' AND net = 0);
+
+INSERT INTO agent_ledger_entries (id, agent_id, kind, payout_id, currency, created_at)
+SELECT entry_id, agent_id,
+       CASE status WHEN 'failed' THEN 'payout_retried' ELSE 'opening_balance' END,
+       payout_id, currency, NOW()
+FROM ledger_payout_moves;
+
+INSERT INTO agent_ledger_lines (entry_id, agent_id, account, amount, currency)
+SELECT m.entry_id, m.agent_id, line.account, line.amount, m.currency
+FROM ledger_payout_moves m,
+LATERAL (VALUES
+    ('paid_out', -m.net),
+    ('in_payout', CASE m.status WHEN 'failed' THEN 0 ELSE m.net END),
+    ('available', CASE m.status WHEN 'failed' THEN m.gross ELSE 0 END),
+    ('clawback_receivable', CASE m.status WHEN 'failed' THEN -m.deductions ELSE 0 END),
+    ('tax_withheld', CASE m.status WHEN 'failed' THEN -m.withheld ELSE 0 END),
+    ('reserve', CASE m.status WHEN 'failed' THEN m.released - m.reserved ELSE 0 END)
+) AS line (account, amount)
+WHERE line.amount <> 0;
+
+DROP TABLE ledger_payout_moves;
@@ -0,0 +1,37 @@
+-- Payouts hold their net amount in the in_payout ledger account until they
+-- complete, and a failed payout's entry is reversed until it is retried.
+-- Payouts created before this posted their net amount straight to
+-- paid_out: move those that have not completed.
+CREATE TEMP TABLE ledger_payout_moves AS
+SELECT nextval('agent_ledger_entries_id_seq') AS entry_id, id AS payout_id, agent_id, status, currency,
+       amount AS net, deductions, withheld, reserved, released,
+       amount + withheld + reserved - released + deductions AS gross
+FROM payouts
+WHERE status IN ('pending', 'processing', 'failed');
+
+DELETE FROM ledger_payout_moves
+WHERE (status = 'failed' AND gross = 0 AND released = 0)
+   OR (status <> 'failed' AND net = 0);
+
+INSERT INTO agent_ledger_entries (id, agent_id, kind, payout_id, currency, created_at)
+SELECT entry_id, agent_id,
+       CASE status WHEN 'failed' THEN 'payout_failed' ELSE 'opening_balance' END,
+       payout_id, currency, NOW()
+FROM ledger_payout_moves;
+
+-- Pending and processing payouts move from paid_out to in_payout; failed
+-- ones reverse the whole payout_created entry
+INSERT INTO agent_ledger_lines (entry_id, agent_id, account, amount, currency)
+SELECT m.entry_id, m.agent_id, line.account, line.amount, m.currency
+FROM ledger_payout_moves m,
+LATERAL (VALUES
+    ('paid_out', m.net),
+    ('in_payout', CASE m.status WHEN 'failed' THEN 0 ELSE -m.net END),
+    ('available', CASE m.status WHEN 'failed' THEN -m.gross ELSE 0 END),
+    ('clawback_receivable', CASE m.status WHEN 'failed' THEN m.deductions ELSE 0 END),
+    ('tax_withheld', CASE m.status WHEN 'failed' THEN m.withheld ELSE 0 END),
+    ('reserve', CASE m.status WHEN 'failed' THEN m.reserved - m.released ELSE 0 END)
+) AS line (account, amount)
+WHERE line.amount <> 0;
+
+DROP TABLE ledger_payout_moves;
@@ -0,0 +1,2 @@
+ALTER TABLE payout_reserves DROP COLUMN IF EXISTS version;
+ALTER TABLE commission_clawbacks DROP COLUMN IF EXISTS version;
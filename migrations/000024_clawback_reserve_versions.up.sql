@@ -0,0 +1,6 @@
+-- Version counters for clawbacks and reserves. A payout recovers
+-- outstanding clawbacks and releases due reserves; with a version check, a
+-- second payout working from the same rows is refused instead of netting
+-- the same clawback or releasing the same reserve twice.
+ALTER TABLE commission_clawbacks ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE payout_reserves ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 0;
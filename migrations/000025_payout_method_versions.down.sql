@@ -0,0 +1 @@
+ALTER TABLE agent_payout_methods DROP COLUMN IF EXISTS version;
@@ -0,0 +1,5 @@
+-- Version counter for payout methods. Verifying, sending a code and
+-- changing details all save the whole method; with a version check, a save
+-- from a stale copy is refused, so wrong codes entered in parallel are all
+-- counted and a verification cannot be saved over newer details.
+ALTER TABLE agent_payout_methods ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,16 @@
+-- Payment files exported for payout runs, kept as they were handed out so
+-- they can be downloaded again and checked against their checksum.
+CREATE TABLE IF NOT EXISTS payout_exports (
+    id           BIGSERIAL PRIMARY KEY,
+    run_id       BIGINT NOT NULL REFERENCES payout_runs (id) ON DELETE CASCADE,
+    format       VARCHAR(20) NOT NULL,
+    file_name    VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    content      BYTEA NOT NULL,
+    checksum     VARCHAR(64) NOT NULL,
+    count        INTEGER NOT NULL,
+    exported_by  VARCHAR(100),
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_payout_exports_run_id ON payout_exports (run_id);